@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+)
+
+// 匿名化後の全ユーザーのパスワード（--password で変更できる）
+const defaultAnonymizedPassword = "password"
+
+// 匿名化処理を実行
+// ステージング環境にコピーした本番環境のデータベースで使用する
+func handleAnonymize(cfg *config.Config, args []string) {
+	usage := "使用方法: app anonymize --yes [--password <パスワード>]"
+
+	confirmed := false
+	password := defaultAnonymizedPassword
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--yes":
+			confirmed = true
+		case "--password":
+			if i+1 >= len(args) {
+				log.Fatal(usage)
+			}
+			i++
+			password = args[i]
+		default:
+			log.Fatalf("不明な引数です: %s（%s）", args[i], usage)
+		}
+	}
+
+	// 本番環境のデータを誤って書き換えないようにする
+	if cfg.IsProduction() {
+		log.Fatal("APP_ENV=production では匿名化できません")
+	}
+	if cfg.UsesMemoryDB() {
+		log.Println("DB_DRIVER=memory またはデモモードでは匿名化は不要です")
+		return
+	}
+
+	// データベース接続
+	db, err := config.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("データベース接続に失敗しました: %v", err)
+	}
+
+	// 元に戻せないため、接続先を確認してから --yes を付けて実行してもらう
+	if !confirmed {
+		log.Fatalf("%s:%s/%s の個人情報を置き換えます（元に戻せません）。実行する場合は --yes を付けてください",
+			cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName)
+	}
+
+	log.Println("個人情報を匿名化中...")
+	result, err := services.NewAnonymizeService(db).Run(context.Background(), password)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	log.Printf("ユーザー %d 件、メッセージ %d 件、通報 %d 件を匿名化しました", result.Users, result.Messages, result.Reports)
+	log.Printf("通知 %d 件、変更前のハンドル %d 件、IPアドレスの制限 %d 件、順番待ち %d 件、外部サービスの連携 %d 件を削除しました",
+		result.Notifications, result.HandleHistories, result.IPBlocks, result.WaitlistEntries, result.OAuthProviders)
+	log.Printf("全てのユーザーのパスワードを %q に設定しました（メールアドレスは user<ID>@example.com）", password)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+)
+
+// バックアップ処理を実行
+// 引数なしの場合はバックアップを作成する
+func handleBackup(cfg *config.Config, args []string) {
+	command := "run"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	// メモリ上のリポジトリにはバックアップするテーブルがない
+	if cfg.UsesMemoryDB() {
+		log.Println("DB_DRIVER=memory またはデモモードではバックアップできません")
+		return
+	}
+
+	// データベース接続
+	db, err := config.InitDB(cfg)
+	if err != nil {
+		log.Fatalf("データベース接続に失敗しました: %v", err)
+	}
+	storage, err := services.NewStorageService(cfg)
+	if err != nil {
+		log.Fatalf("ストレージの初期化に失敗しました: %v", err)
+	}
+	backupService := services.NewBackupService(db, storage, cfg)
+	ctx := context.Background()
+
+	switch command {
+	case "run":
+		log.Println("バックアップを作成中...")
+		backup, err := backupService.Run(ctx)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		log.Printf("バックアップ %s を作成しました（%d バイト）", backup.Name, backup.Size)
+
+	case "list":
+		backups, err := backupService.List(ctx)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if len(backups) == 0 {
+			fmt.Println("バックアップはありません")
+		}
+		for _, backup := range backups {
+			fmt.Printf("%s\t%d\t%s\n", backup.Name, backup.Size, backup.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+		}
+
+	case "restore":
+		// 現在のデータを置き換えるため、--yes を付けた場合のみ実行する
+		if len(args) < 2 {
+			log.Fatal("使用方法: app backup restore <バックアップ名> --yes")
+		}
+		if len(args) < 3 || args[2] != "--yes" {
+			log.Fatalf("現在のデータは %s の内容で置き換えられます。実行する場合は --yes を付けてください", args[1])
+		}
+		log.Printf("バックアップ %s を復元中...", args[1])
+		restored, err := backupService.Restore(ctx, args[1])
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		log.Printf("%d 件のレコードを復元しました", restored)
+
+	default:
+		log.Fatalf("不明なコマンドです: %s（使用方法: app backup [run|list|restore <バックアップ名> --yes]）", command)
+	}
+}
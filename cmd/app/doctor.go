@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"gorm.io/gorm"
+)
+
+// 外部サービスへの接続確認のタイムアウト
+const doctorTimeout = 10 * time.Second
+
+// doctorReport 診断結果
+type doctorReport struct {
+	failed int
+}
+
+// ok 問題のない項目を表示
+func (r *doctorReport) ok(name, detail string) {
+	fmt.Printf("[OK]   %s: %s\n", name, detail)
+}
+
+// warn 動作はするが確認が必要な項目を表示
+func (r *doctorReport) warn(name, detail, hint string) {
+	fmt.Printf("[WARN] %s: %s\n       → %s\n", name, detail, hint)
+}
+
+// fail 問題のある項目と対処方法を表示
+func (r *doctorReport) fail(name string, err error, hint string) {
+	r.failed++
+	fmt.Printf("[NG]   %s: %v\n       → %s\n", name, err, hint)
+}
+
+// skip 確認しなかった項目を表示
+func (r *doctorReport) skip(name, reason string) {
+	fmt.Printf("[SKIP] %s: %s\n", name, reason)
+}
+
+// runDoctor 設定・データベース・ストレージ・外部サービスを確認して結果を表示
+// 問題がある場合は終了コード1を返す
+func runDoctor() int {
+	report := &doctorReport{}
+	fmt.Println("環境を診断しています...")
+
+	// 設定
+	cfg, err := config.Load()
+	if err != nil {
+		report.fail("設定", err, ".env または環境変数を確認してください（.env.example を参照）")
+		return report.summary()
+	}
+	report.ok("設定", fmt.Sprintf("APP_ENV=%s, SECRETS_SOURCE=%s", cfg.Env, cfg.Secrets.Source))
+	if cfg.Auth.JWTSecret == "your-secret-key" {
+		report.warn("JWT", "デフォルトのJWT_SECRETを使用しています", "本番環境では推測できない値をJWT_SECRETに設定してください")
+	}
+
+	// データベース
+	if cfg.UsesMemoryDB() {
+		report.warn("データベース", "メモリ上のリポジトリを使用しています（DB_DRIVER=memory またはDEMO_MODE=true）", "データは再起動すると消えます。MySQLを使用する場合はDB_DRIVER=mysqlを設定してください")
+		report.skip("マイグレーション", "メモリ上のリポジトリを使用しています")
+	} else if db, err := config.InitDB(cfg); err != nil {
+		report.fail("データベース", err, "DB_HOST・DB_PORT・DB_USER・DB_PASSWORD・DB_NAMEと、データベースが起動しているか確認してください")
+		report.skip("マイグレーション", "データベースに接続できません")
+	} else {
+		report.ok("データベース", fmt.Sprintf("%s:%s/%s", cfg.Database.Host, cfg.Database.Port, cfg.Database.DBName))
+		if missing, err := pendingMigrations(db); err != nil {
+			report.fail("マイグレーション", err, "データベースの権限を確認してください")
+		} else if len(missing) > 0 {
+			report.fail("マイグレーション", fmt.Errorf("未適用の変更があります: %s", strings.Join(missing, ", ")), "app migrate up を実行してください")
+		} else {
+			report.ok("マイグレーション", "最新です")
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+	httpClients := services.NewHTTPClientFactory(cfg)
+
+	// ストレージ
+	if storage, err := services.NewStorageService(cfg); err != nil {
+		report.fail("ストレージ", err, "STORAGE_PROVIDERを確認してください")
+	} else if err := storage.Ping(ctx); err != nil {
+		report.fail("ストレージ", err, "STORAGE_LOCAL_DIRが存在し、書き込み権限があるか確認してください")
+	} else {
+		report.ok("ストレージ", fmt.Sprintf("%s (%s)", cfg.Storage.Provider, cfg.Storage.LocalDir))
+	}
+
+	// Cloudinary（サムネイル画像のアップロードに使用、任意）
+	if cfg.Cloudinary.CloudName == "" {
+		report.skip("Cloudinary", "CLOUDINARY_CLOUD_NAMEが未設定のため、サムネイル画像のアップロードは利用できません")
+	} else if cld, err := services.NewCloudinaryService(httpClients, cfg); err != nil {
+		report.fail("Cloudinary", err, "CLOUDINARY_CLOUD_NAME・CLOUDINARY_API_KEY・CLOUDINARY_API_SECRETを確認してください")
+	} else if err := cld.Ping(ctx); err != nil {
+		report.fail("Cloudinary", err, "CLOUDINARY_API_KEY・CLOUDINARY_API_SECRETを確認してください")
+	} else {
+		report.ok("Cloudinary", cfg.Cloudinary.CloudName)
+	}
+
+	// Lambda（DryRunで呼び出し権限を確認、呼び出し履歴は記録しない）
+	lambdaService := services.NewLambdaService(nil, httpClients, cfg)
+	if err := lambdaService.Ping(ctx); err != nil {
+		report.fail("Lambda", err, "AWSの認証情報、AWS_REGION・AWS_LAMBDA_FUNCTION・AWS_LAMBDA_VERSIONと、lambda:InvokeFunctionの権限を確認してください")
+	} else {
+		report.ok("Lambda", fmt.Sprintf("%s:%s", cfg.Lambda.FunctionName, lambdaService.ConverterVersion()))
+	}
+
+	return report.summary()
+}
+
+// summary 診断結果のまとめを表示し、終了コードを返す
+func (r *doctorReport) summary() int {
+	if r.failed > 0 {
+		fmt.Printf("\n%d 件の問題が見つかりました\n", r.failed)
+		return 1
+	}
+	fmt.Println("\n問題は見つかりませんでした")
+	return 0
+}
+
+// pendingMigrations マイグレーションで作成されるはずのテーブル・カラムのうち存在しないものを取得
+func pendingMigrations(db *gorm.DB) ([]string, error) {
+	var missing []string
+	migrator := db.Migrator()
+	for _, model := range models.AllModels() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+		table := stmt.Schema.Table
+		if !migrator.HasTable(model) {
+			missing = append(missing, table)
+			continue
+		}
+		for _, column := range stmt.Schema.DBNames {
+			if !migrator.HasColumn(model, column) {
+				missing = append(missing, table+"."+column)
+			}
+		}
+	}
+	return missing, nil
+}
@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/config"
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository/memory"
 	"github.com/SketchShifter/sketchshifter_backend/internal/routes"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -16,6 +21,11 @@ func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	log.Println("サーバーを起動しています...")
 
+	// 環境の診断（設定の読み込みに失敗した場合も結果を表示するため、先に処理する）
+	if len(os.Args) > 1 && os.Args[1] == "doctor" {
+		os.Exit(runDoctor())
+	}
+
 	// 設定をロード
 	cfg, err := config.Load()
 	if err != nil {
@@ -28,6 +38,16 @@ func main() {
 		handleMigration(cfg, os.Args[2:])
 		return
 	}
+	if len(os.Args) > 1 && os.Args[1] == "backup" {
+		// バックアップモードで実行
+		handleBackup(cfg, os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "anonymize" {
+		// 匿名化モードで実行
+		handleAnonymize(cfg, os.Args[2:])
+		return
+	}
 
 	// Gin モードの設定（環境変数が設定されていない場合はデバッグモード）
 	ginMode := os.Getenv("GIN_MODE")
@@ -42,10 +62,17 @@ func main() {
 		log.Printf("エンドポイント登録: %s %s -> %s (%d handlers)\n", httpMethod, absolutePath, handlerName, nuHandlers)
 	}
 
-	// データベース接続
-	db, err := config.InitDB(cfg)
-	if err != nil {
-		log.Fatalf("データベース接続に失敗しました: %v", err)
+	// データベース接続（メモリ上のリポジトリを使用する場合は接続しない）
+	var db *gorm.DB
+	if cfg.Demo.Enabled {
+		log.Printf("デモモードで起動します（ログイン: %s / %s）", memory.DemoUserEmail, memory.DemoPassword)
+	} else if cfg.UsesMemoryDB() {
+		log.Println("DB_DRIVER=memory のため、データはメモリ上に保存されます（再起動すると消えます）")
+	} else {
+		db, err = config.InitDB(cfg)
+		if err != nil {
+			log.Fatalf("データベース接続に失敗しました: %v", err)
+		}
 	}
 
 	// ルーターをセットアップ
@@ -66,6 +93,12 @@ func handleMigration(cfg *config.Config, args []string) {
 
 	command := args[0]
 
+	// メモリ上のリポジトリにはマイグレーションするテーブルがない
+	if cfg.UsesMemoryDB() {
+		log.Println("DB_DRIVER=memory またはデモモードではマイグレーションは不要です")
+		return
+	}
+
 	// データベース接続
 	db, err := config.InitDB(cfg)
 	if err != nil {
@@ -76,29 +109,74 @@ func handleMigration(cfg *config.Config, args []string) {
 	case "up":
 		// マイグレーションを実行
 		log.Println("マイグレーションを実行中...")
-		err = db.AutoMigrate(
-			&models.User{},
-			&models.Tag{},
-			&models.Work{},
-			&models.Like{},
-			&models.Comment{},
-			&models.Project{},
-			&models.ProjectMember{},
-			&models.Task{},
-			&models.TaskWork{},
-			&models.Vote{},
-			&models.VoteOption{},
-			&models.VoteResponse{},
-		)
+		err = db.AutoMigrate(models.AllModels()...)
 		if err != nil {
 			log.Fatalf("マイグレーションに失敗しました: %v", err)
 		}
+
+		// 既存データのいいね数・コメント数を集計
+		fixed, err := repository.NewWorkRepository(db).ReconcileCounters(context.Background())
+		if err != nil {
+			log.Fatalf("いいね数・コメント数の集計に失敗しました: %v", err)
+		}
+		log.Printf("いいね数・コメント数を %d 件集計しました", fixed)
+
+		// 既存のユーザーにハンドルを割り当て（画像は扱わない）
+		assigned, err := services.NewUserService(
+			repository.NewUserRepository(db), repository.NewWorkRepository(db), repository.NewProjectRepository(db), nil,
+		).AssignMissingHandles(context.Background())
+		if err != nil {
+			log.Fatalf("ハンドルの割り当てに失敗しました: %v", err)
+		}
+		log.Printf("%d 人のユーザーにハンドルを割り当てました", assigned)
+
 		log.Println("マイグレーションが成功しました")
 
 	case "down":
 		// テーブルを削除（逆順）
 		log.Println("マイグレーションをロールバック中...")
 		err = db.Migrator().DropTable(
+			&models.PersonalToken{},
+			&models.RevokedToken{},
+			&models.RefreshToken{},
+			&models.LinkPreview{},
+			&models.OAuthProvider{},
+			&models.Subscription{},
+			&models.OrganizationMember{},
+			&models.Organization{},
+			&models.WorkRanking{},
+			&models.CritiqueScore{},
+			&models.Critique{},
+			&models.CritiqueCriterion{},
+			&models.CommentSubscription{},
+			&models.AuditLog{},
+			&models.RetagJobWork{},
+			&models.RetagJob{},
+			&models.WaitlistEntry{},
+			&models.InviteCode{},
+			&models.WorkSnapshot{},
+			&models.WorkCollaborator{},
+			&models.EventAttendee{},
+			&models.Event{},
+			&models.HandleHistory{},
+			&models.IPBlock{},
+			&models.Report{},
+			&models.Series{},
+			&models.WorkAnnotation{},
+			&models.AssetUpload{},
+			&models.WorkAsset{},
+			&models.ImageVariant{},
+			&models.Image{},
+			&models.ReconversionCampaign{},
+			&models.InvocationLog{},
+			&models.ConversionJob{},
+			&models.ConversionLog{},
+			&models.Message{},
+			&models.ConversationParticipant{},
+			&models.Conversation{},
+			&models.WorkBadge{},
+			&models.Notification{},
+			&models.Activity{},
 			&models.VoteResponse{},
 			&models.VoteOption{},
 			&models.Vote{},
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -11,11 +14,304 @@ import (
 
 // Config アプリケーション設定に追加
 type Config struct {
-	Server     ServerConfig
-	Database   DatabaseConfig
-	Auth       AuthConfig
-	Lambda     LambdaConfig
-	Cloudinary CloudinaryConfig // 追加
+	Env          string // development, production
+	Secrets      SecretsConfig
+	Server       ServerConfig
+	Database     DatabaseConfig
+	Auth         AuthConfig
+	Lambda       LambdaConfig
+	Cloudinary   CloudinaryConfig // 追加
+	Vote         VoteConfig
+	Scheduler    SchedulerConfig
+	Reputation   ReputationConfig
+	Conversion   ConversionConfig
+	Validation   ValidationConfig
+	Limits       LimitsConfig
+	Maintenance  MaintenanceConfig
+	Trash        TrashConfig
+	Storage      StorageConfig
+	Video        VideoConfig
+	Demo         DemoConfig
+	Featured     FeaturedConfig
+	Mail         MailConfig
+	Report       ReportConfig
+	Access       AccessConfig
+	Sitemap      SitemapConfig
+	PublicAPI    PublicAPIConfig
+	Circuit      CircuitBreakerConfig
+	HTTPClient   HTTPClientConfig
+	Fetch        FetchConfig
+	Image        ImageConfig
+	Player       PlayerConfig
+	Backup       BackupConfig
+	Archive      ArchiveConfig
+	Ranking      RankingConfig
+	Home         HomeConfig
+	LinkPreview  LinkPreviewConfig
+	TagCleanup   TagCleanupConfig
+	Organization OrganizationConfig
+	Billing      BillingConfig
+	Registration RegistrationConfig
+	AgeGate      AgeGateConfig
+}
+
+// AgeGateConfig 未成年のユーザーの利用制限の設定
+type AgeGateConfig struct {
+	MinorAge int // この年齢未満のユーザーを未成年として扱う（0以下で制限しない）
+}
+
+// 新規登録の受け付け方
+const (
+	RegistrationModeOpen   = "open"   // 誰でも登録できる
+	RegistrationModeInvite = "invite" // 招待コードがある場合のみ登録できる
+	RegistrationModeClosed = "closed" // 登録を受け付けない（順番待ちのみ）
+)
+
+// RegistrationConfig 新規登録の設定
+type RegistrationConfig struct {
+	Mode                 string // open / invite / closed
+	InviteCodeExpiryDays int    // 順番待ちの承認で発行する招待コードの有効期限（日、0以下で無期限）
+}
+
+// BackupConfig データベースのバックアップ設定
+type BackupConfig struct {
+	Prefix        string        // バックアップを保存するストレージのキーの接頭辞（.で始まるキーは /uploads から配信されないため、.で始まる必要がある）
+	Retention     int           // 残すバックアップの数（古いものから削除、0以下で削除しない）
+	Interval      time.Duration // 定期バックアップの間隔（0で定期実行しない）
+	ExcludeTables []string      // バックアップしないテーブル（ログなど失っても困らないもの）
+}
+
+// ArchiveConfig 長期間更新されていない作品のコードをストレージに移す設定
+type ArchiveConfig struct {
+	AfterYears int           // この年数だけ更新されていない作品を移す（0以下で移さない）
+	MinSizeKB  int           // PDEとJSの合計がこのサイズ以上の作品のみ移す
+	Prefix     string        // 移したコードを保存するストレージのキーの接頭辞（.で始まる必要がある）
+	Interval   time.Duration // 移す作品を確認する間隔
+	BatchSize  int           // 1回に移す作品数の上限
+}
+
+// RankingConfig 人気順（sort=popular）の人気スコアの設定
+// スコアは (閲覧数×ViewWeight + いいね数×LikeWeight + コメント数×CommentWeight) を公開からの経過日数で減衰させたもの
+type RankingConfig struct {
+	ViewWeight    float64       // 閲覧1回あたりのポイント
+	LikeWeight    float64       // いいね1件あたりのポイント
+	CommentWeight float64       // コメント1件あたりのポイント
+	HalfLifeDays  float64       // スコアが半分になるまでの日数（0以下で減衰させない）
+	Interval      time.Duration // スコアを計算し直す間隔
+}
+
+// HomeConfig トップページのセクション（GET /home）の設定
+type HomeConfig struct {
+	WorksLimit    int           // 作品のセクションごとの件数
+	ContestsLimit int           // 応募を受け付けているコンテストの件数
+	TagsLimit     int           // タグの件数
+	CacheTTL      time.Duration // セクションをキャッシュする時間（サーバー内のキャッシュとCache-Control）
+}
+
+// LinkPreviewConfig コメントに含まれるURLのリンクプレビューの設定
+type LinkPreviewConfig struct {
+	AllowedHosts  []string      // プレビューを取得するホスト（サブドメインを含む、空の場合は取得しない）
+	CacheTTL      time.Duration // 取得したプレビューを再取得するまでの時間
+	Timeout       time.Duration // 1件の取得のタイムアウト
+	MaxBytes      int64         // 読み込むHTMLの最大サイズ
+	MaxPerComment int           // 1件のコメントでプレビューを取得するURLの数
+}
+
+// TagCleanupConfig どの作品にも付いていないタグを削除する設定
+type TagCleanupConfig struct {
+	MinAgeDays int           // 作成からこの日数以上経ったタグのみ削除する
+	Protected  []string      // 作品がなくても削除しない公式のタグ
+	Interval   time.Duration // 削除するタグを確認する間隔
+}
+
+// OrganizationConfig 学校・クラブなどの組織の設定
+type OrganizationConfig struct {
+	DefaultMaxProjects int // 新しい組織のプロジェクト数の上限（0の場合は無制限）
+	DefaultMaxMembers  int // 新しい組織のメンバー数の上限（0の場合は無制限）
+}
+
+// PlanConfig 料金プランごとの上限（0以下で無制限）
+type PlanConfig struct {
+	MaxProjects         int    // オーナーとして作成できる個人のプロジェクト数（組織のプロジェクトは含めない）
+	StorageQuotaMB      int    // 合計ストレージ容量（MB）
+	ConversionsPerMonth int    // 1か月あたりのPDE変換回数
+	StripePriceID       string // Stripeの価格ID（サブスクリプションの料金プランの判定に使用、無料プランは不要）
+}
+
+// BillingConfig 料金プランとStripeの設定
+type BillingConfig struct {
+	Free                PlanConfig
+	Pro                 PlanConfig
+	Org                 PlanConfig
+	StripeWebhookSecret string        // Webhookの署名シークレット（空の場合はWebhookを受け付けない）
+	WebhookTolerance    time.Duration // Webhookの署名のタイムスタンプの許容範囲
+}
+
+// PlayerConfig サーバーで描画する作品のプレイヤーページの設定
+type PlayerConfig struct {
+	P5URL string // 読み込むp5.jsのURL
+}
+
+// ImageConfig アップロードされた画像（サムネイル・アバター・アセット・スナップショット）の変換設定
+type ImageConfig struct {
+	MaxSizeMB int // 変換する画像1枚あたりの最大サイズ
+	Thumbnail ImagePipelineConfig
+	Avatar    ImagePipelineConfig
+	Asset     ImagePipelineConfig
+	Snapshot  ImagePipelineConfig
+}
+
+// ImagePipelineConfig アップロード種別ごとの画像変換の手順
+type ImagePipelineConfig struct {
+	Steps   []string // 順に適用する手順（resize, strip_exif, webp, blurhash）
+	Sizes   []string // resizeで作成する派生画像（名前:幅x高さ）
+	Crop    string   // resizeの方法（limit: 縦横比を保って縮小, fill: 指定サイズに切り抜き）
+	Quality int      // 派生画像の品質（1〜100）
+}
+
+// HTTPClientConfig 外部サービス（Lambda・Cloudinary）との通信に使うHTTPクライアントの設定
+type HTTPClientConfig struct {
+	Timeout        time.Duration // 1回のリクエスト全体のタイムアウト
+	ConnectTimeout time.Duration // 接続とTLSハンドシェイクのタイムアウト
+	MaxRetries     int           // 通信エラー・429・5xxの場合の再試行回数（冪等なリクエストのみ）
+	RetryBackoff   time.Duration // 再試行までの待ち時間（再試行ごとに倍にし、ランダムにずらす）
+	ProxyURL       string        // 経由するプロキシ（空の場合は環境変数 HTTPS_PROXY などに従う）
+}
+
+// FetchConfig 利用者が指定したURL（リンクプレビューなど）を取得する際の上限
+// 用途ごとにこれより厳しい上限を指定できる
+type FetchConfig struct {
+	Timeout      time.Duration // 1回の取得全体のタイムアウト（リダイレクトを含む）
+	MaxBytes     int64         // レスポンスの最大サイズ
+	MaxRedirects int           // 辿るリダイレクトの最大回数
+}
+
+// CircuitBreakerConfig 外部サービス（Lambda・Cloudinary）の呼び出しを遮断するサーキットブレーカーの設定
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // 連続して失敗すると遮断する回数（0以下で遮断しない）
+	OpenTimeout      time.Duration // 遮断してから試しに1件だけ呼び出すまでの時間
+}
+
+// PublicAPIConfig 匿名の利用者（外部のギャラリーなど）向けの公開API（/public/v1）の設定
+type PublicAPIConfig struct {
+	RateLimit int           // IPアドレスごとの1分あたりのリクエスト数（0以下で無制限）
+	CacheTTL  time.Duration // レスポンスをキャッシュする時間（サーバー内のキャッシュとCache-Control）
+}
+
+// SitemapConfig サイトマップの設定
+type SitemapConfig struct {
+	SiteURL string // 作品ページのURLの起点（フロントエンドのURL）
+	BaseURL string // サイトマップを配信するこのサーバーの公開URL（サイトマップインデックスに記載する）
+}
+
+// AccessConfig IPアドレス・国によるアクセス制限の設定
+type AccessConfig struct {
+	TrustedProxies   []string // X-Forwarded-Forを信頼するプロキシ（空の場合はGinのデフォルト）
+	CountryHeader    string   // 国コードを含むヘッダー（CDNが付与する CF-IPCountry や CloudFront-Viewer-Country）
+	BlockedCountries []string // 書き込み系のリクエストを拒否する国コード
+	AllowedCountries []string // 指定した場合は、この国以外からの書き込み系のリクエストを拒否する
+}
+
+// ReportConfig 通報による自動非表示の設定
+type ReportConfig struct {
+	HideThreshold   int // この人数を超えるユーザーから通報されたら非表示にする（0以下の場合は自動で非表示にしない）
+	RehideThreshold int // 管理者が確認して再表示した後に、再び非表示にする人数（確認後の通報のみ数える）
+}
+
+// MailConfig メール送信設定
+type MailConfig struct {
+	Provider         string // log: 送信せずにログに出力（開発用） / smtp: SMTPサーバーから送信
+	SMTPHost         string
+	SMTPPort         int
+	SMTPUsername     string
+	SMTPPassword     string
+	From             string
+	FrontendURL      string // メール本文のリンク先（フロントエンドのURL）
+	InviteExpiryDays int    // 招待メールのリンクの有効期限（日）
+	Language         string // メールのテンプレートの言語（ja, en）
+}
+
+// FeaturedConfig ピックアップ作品の設定
+type FeaturedConfig struct {
+	DefaultDays   int  // 終了日時を指定しなかった場合のピックアップ期間（日）
+	BoostHomepage bool // 作品一覧の新着順でピックアップ中の作品を先頭に表示するか
+}
+
+// DemoConfig デモモード設定
+type DemoConfig struct {
+	Enabled bool // MySQLの代わりにメモリ上のデモデータで動かす（再起動すると初期状態に戻る）
+}
+
+// VideoConfig 作品のデモ動画設定
+type VideoConfig struct {
+	MaxSizeMB  int    // デモ動画の最大サイズ（MB）
+	Transcode  bool   // 添付時にffmpegでブラウザ互換のmp4に変換するか
+	FFmpegPath string // ffmpegの実行ファイル
+}
+
+// StorageConfig アセット（動画やデータファイルなど）の保存先設定
+type StorageConfig struct {
+	Provider       string // local: ローカルディスクに保存
+	LocalDir       string // localの場合の保存先ディレクトリ
+	AssetMaxSizeMB int    // アセット1件あたりの最大サイズ（MB）
+}
+
+// TrashConfig 削除済み作品の保持設定
+type TrashConfig struct {
+	RetentionDays int // 削除後に復元できる日数（経過後に完全に削除する）
+}
+
+// MaintenanceConfig メンテナンスモード設定（起動時の初期状態）
+type MaintenanceConfig struct {
+	Enabled bool
+	Message string
+}
+
+// LimitsConfig 作品のサイズ制限設定
+type LimitsConfig struct {
+	PDEMaxSizeKB int // PDEコードの最大サイズ（KB）
+}
+
+// ValidationConfig 変換後のJS検証設定
+type ValidationConfig struct {
+	JSMaxSizeKB int    // 変換後のJSの最大サイズ（KB）
+	JSMode      string // reject: 問題のあるJSを保存しない / flag: 保存してフラグを立てる
+}
+
+// ConversionConfig PDE変換設定
+type ConversionConfig struct {
+	QuotaPerHour     int           // ユーザーごとの1時間あたりの変換回数上限（0以下で無制限）
+	RetryMaxAttempts int           // 作成・更新時に失敗した変換をバックグラウンドで再試行する回数
+	RetryBaseDelay   time.Duration // 1回目の再試行までの待ち時間（再試行ごとに倍にする）
+	RetryMaxDelay    time.Duration // 再試行までの待ち時間の上限
+	Workers          int           // 同時に実行する変換の数（2以上の場合は1つをプレビューと作品の保存用に空けておく）
+	CacheSize        int           // 同じPDEの変換結果を再利用するためにキャッシュする件数（0以下でキャッシュしない）
+}
+
+// ReputationConfig レピュテーション設定
+type ReputationConfig struct {
+	LikePoints         int // いいね1件あたりのポイント
+	SubmissionPoints   int // タスクへの作品提出1件あたりのポイント
+	ContestWinPoints   int // 投票で優勝した際のポイント
+	MinToCreateProject int // プロジェクト作成に必要なレピュテーション
+}
+
+// VoteConfig 投票設定
+type VoteConfig struct {
+	AwardWinnerBadge bool // 投票終了時に勝者の作品へバッジを付与するか
+}
+
+// SchedulerConfig スケジューラ設定
+type SchedulerConfig struct {
+	Enabled                 bool
+	VoteCloseInterval       time.Duration
+	ReconversionInterval    time.Duration
+	CounterInterval         time.Duration // いいね数・コメント数の再集計間隔
+	TrashPurgeInterval      time.Duration // 保持期間を過ぎた削除済み作品の完全削除間隔
+	ConversionRetryInterval time.Duration // 失敗した変換の再試行ジョブを確認する間隔
+	InvocationPurgeInterval time.Duration // 保持期間を過ぎたLambdaの呼び出し履歴の削除間隔
+	RetagInterval           time.Duration // 一括タグ付けジョブを進める間隔
+	TokenPurgeInterval      time.Duration // 有効期限を過ぎたリフレッシュトークン・無効にしたアクセストークンの記録の削除間隔
 }
 
 // CloudinaryConfig Cloudinary設定
@@ -24,18 +320,31 @@ type CloudinaryConfig struct {
 	APIKey    string
 	APISecret string
 	Folder    string
+	Timeout   time.Duration // アップロード・削除のタイムアウト
 }
 
 // ServerConfig サーバー設定
 type ServerConfig struct {
-	Port         string
-	ReadTimeout  time.Duration
-	WriteTimeout time.Duration
-	APIBaseURL   string
+	Port           string
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	RequestTimeout time.Duration // リクエスト処理（DB呼び出しを含む）のタイムアウト
+	ExportTimeout  time.Duration // エクスポート（少しずつ書き出すCSVなど）のタイムアウト
+	UploadTimeout  time.Duration // ファイルのアップロード（動画・アセットなど）のタイムアウト
+	ResponseFormat string        // v1のレスポンス形式（envelope または legacy、v2は常にenvelope）
+	APIV1Sunset    string        // v1の廃止予定日（Sunsetヘッダーの値、空の場合は送らない）
+	APIBaseURL     string
 }
 
+// データベースの種類
+const (
+	DBDriverMySQL  = "mysql"
+	DBDriverMemory = "memory" // メモリ上に保存（MySQLなしでの開発・デモ用、再起動すると消える）
+)
+
 // DatabaseConfig データベース設定
 type DatabaseConfig struct {
+	Driver   string // mysql または memory
 	Host     string
 	Port     string
 	Username string
@@ -47,10 +356,14 @@ type DatabaseConfig struct {
 type AuthConfig struct {
 	JWTSecret          string
 	TokenExpiry        time.Duration
+	RefreshTokenExpiry time.Duration // リフレッシュトークンの有効期限（使うたびに新しいトークンに置き換わる）
 	GoogleClientID     string
 	GoogleClientSecret string
 	GithubClientID     string
 	GithubClientSecret string
+	GithubRedirectURL  string // GitHubの認可後に戻るフロントエンドのURL（OAuth Appに複数登録している場合に指定）
+	GithubOAuthURL     string // GitHub Enterpriseの場合に変更する
+	GithubAPIURL       string
 }
 
 // LambdaConfig Lambda設定
@@ -61,6 +374,16 @@ type LambdaConfig struct {
 	VpcID         string
 	SubnetIDs     []string
 	SecurityGroup string
+	Version       string        // 変換に使用するLambdaのバージョンまたはエイリアス
+	Timeout       time.Duration // 1回の呼び出しのタイムアウト
+	MaxRetries    int           // 一時的なエラー（スロットリングなど）の場合の再試行回数
+	RetryBackoff  time.Duration // 再試行までの待ち時間（再試行ごとに倍にする）
+
+	// 呼び出し履歴からの費用の見積もりに使用
+	MemoryMB                int     // 関数に割り当てたメモリ
+	PricePerGBSecond        float64 // 実行時間1GB秒あたりの料金（USD）
+	PricePerMillionRequests float64 // 100万リクエストあたりの料金（USD）
+	LogRetentionDays        int     // 呼び出し履歴を保持する日数（0以下で無期限）
 }
 
 // Load 環境変数から設定をロード
@@ -68,15 +391,36 @@ func Load() (*Config, error) {
 	// .env ファイルをロード (存在すれば)
 	_ = godotenv.Load()
 
+	env := getEnv("APP_ENV", "development")
+
+	// 本番環境ではデフォルトでSSMからシークレットを取得する
+	secretsSource := SecretsSourceEnv
+	if env == "production" {
+		secretsSource = SecretsSourceSSM
+	}
+
 	// デフォルト値を設定
 	config := &Config{
+		Env: env,
+		Secrets: SecretsConfig{
+			Source:    getEnv("SECRETS_SOURCE", secretsSource),
+			Region:    getEnv("SECRETS_REGION", getEnv("AWS_REGION", "ap-northeast-1")),
+			SSMPrefix: getEnv("SECRETS_SSM_PREFIX", "/sketchshifter/"+env+"/"),
+			SecretID:  getEnv("SECRETS_MANAGER_SECRET_ID", ""),
+		},
 		Server: ServerConfig{
-			Port:         getEnv("SERVER_PORT", "8080"),
-			ReadTimeout:  time.Duration(getEnvAsInt("SERVER_READ_TIMEOUT", 10)) * time.Second,
-			WriteTimeout: time.Duration(getEnvAsInt("SERVER_WRITE_TIMEOUT", 10)) * time.Second,
-			APIBaseURL:   getEnv("API_BASE_URL", "http://localhost:8080"),
+			Port:           getEnv("SERVER_PORT", "8080"),
+			ReadTimeout:    time.Duration(getEnvAsInt("SERVER_READ_TIMEOUT", 10)) * time.Second,
+			WriteTimeout:   time.Duration(getEnvAsInt("SERVER_WRITE_TIMEOUT", 10)) * time.Second,
+			RequestTimeout: time.Duration(getEnvAsInt("SERVER_REQUEST_TIMEOUT", 30)) * time.Second,
+			ExportTimeout:  time.Duration(getEnvAsInt("SERVER_EXPORT_TIMEOUT", 600)) * time.Second,
+			UploadTimeout:  time.Duration(getEnvAsInt("SERVER_UPLOAD_TIMEOUT", 300)) * time.Second,
+			ResponseFormat: getEnv("API_RESPONSE_FORMAT", "legacy"),
+			APIV1Sunset:    getEnv("API_V1_SUNSET", ""),
+			APIBaseURL:     getEnv("API_BASE_URL", "http://localhost:8080"),
 		},
 		Database: DatabaseConfig{
+			Driver:   getEnv("DB_DRIVER", DBDriverMySQL),
 			Host:     getEnv("DB_HOST", "localhost"),
 			Port:     getEnv("DB_PORT", "3306"),
 			Username: getEnv("DB_USER", "root"),
@@ -86,10 +430,14 @@ func Load() (*Config, error) {
 		Auth: AuthConfig{
 			JWTSecret:          getEnv("JWT_SECRET", "your-secret-key"),
 			TokenExpiry:        time.Duration(getEnvAsInt("TOKEN_EXPIRY", 24)) * time.Hour,
+			RefreshTokenExpiry: time.Duration(getEnvAsInt("REFRESH_TOKEN_EXPIRY_DAYS", 30)) * 24 * time.Hour,
 			GoogleClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
 			GoogleClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
 			GithubClientID:     getEnv("GITHUB_CLIENT_ID", ""),
 			GithubClientSecret: getEnv("GITHUB_CLIENT_SECRET", ""),
+			GithubRedirectURL:  getEnv("GITHUB_REDIRECT_URL", ""),
+			GithubOAuthURL:     getEnv("GITHUB_OAUTH_URL", "https://github.com"),
+			GithubAPIURL:       getEnv("GITHUB_API_URL", "https://api.github.com"),
 		},
 		Lambda: LambdaConfig{
 			Region:        getEnv("AWS_REGION", "ap-northeast-1"),
@@ -98,18 +446,291 @@ func Load() (*Config, error) {
 			VpcID:         getEnv("AWS_VPC_ID", ""),
 			SubnetIDs:     getEnvAsStringSlice("AWS_SUBNET_IDS", ",", []string{}),
 			SecurityGroup: getEnv("AWS_SECURITY_GROUP", ""),
+			Version:       getEnv("AWS_LAMBDA_VERSION", "$LATEST"),
+			Timeout:       time.Duration(getEnvAsInt("AWS_LAMBDA_TIMEOUT", 30)) * time.Second,
+			MaxRetries:    getEnvAsInt("AWS_LAMBDA_MAX_RETRIES", 2),
+			RetryBackoff:  time.Duration(getEnvAsInt("AWS_LAMBDA_RETRY_BACKOFF_MS", 500)) * time.Millisecond,
+
+			MemoryMB:                getEnvAsInt("AWS_LAMBDA_MEMORY_MB", 1024),
+			PricePerGBSecond:        getEnvAsFloat("AWS_LAMBDA_PRICE_PER_GB_SECOND", 0.0000166667),
+			PricePerMillionRequests: getEnvAsFloat("AWS_LAMBDA_PRICE_PER_MILLION_REQUESTS", 0.20),
+			LogRetentionDays:        getEnvAsInt("AWS_LAMBDA_LOG_RETENTION_DAYS", 90),
 		},
 		Cloudinary: CloudinaryConfig{
 			CloudName: getEnv("CLOUDINARY_CLOUD_NAME", ""),
 			APIKey:    getEnv("CLOUDINARY_API_KEY", ""),
 			APISecret: getEnv("CLOUDINARY_API_SECRET", ""),
 			Folder:    getEnv("CLOUDINARY_FOLDER", "sketchshifter"),
+			Timeout:   time.Duration(getEnvAsInt("CLOUDINARY_TIMEOUT", 30)) * time.Second,
+		},
+		Vote: VoteConfig{
+			AwardWinnerBadge: getEnvAsBool("VOTE_AWARD_WINNER_BADGE", true),
+		},
+		Reputation: ReputationConfig{
+			LikePoints:         getEnvAsInt("REPUTATION_LIKE_POINTS", 1),
+			SubmissionPoints:   getEnvAsInt("REPUTATION_SUBMISSION_POINTS", 5),
+			ContestWinPoints:   getEnvAsInt("REPUTATION_CONTEST_WIN_POINTS", 20),
+			MinToCreateProject: getEnvAsInt("REPUTATION_MIN_TO_CREATE_PROJECT", 0),
+		},
+		Conversion: ConversionConfig{
+			QuotaPerHour:     getEnvAsInt("CONVERSION_QUOTA_PER_HOUR", 30),
+			RetryMaxAttempts: getEnvAsInt("CONVERSION_RETRY_MAX_ATTEMPTS", 5),
+			RetryBaseDelay:   time.Duration(getEnvAsInt("CONVERSION_RETRY_BASE_DELAY", 30)) * time.Second,
+			RetryMaxDelay:    time.Duration(getEnvAsInt("CONVERSION_RETRY_MAX_DELAY", 3600)) * time.Second,
+			Workers:          getEnvAsInt("CONVERSION_WORKERS", 4),
+			CacheSize:        getEnvAsInt("CONVERSION_CACHE_SIZE", 100),
+		},
+		Validation: ValidationConfig{
+			JSMaxSizeKB: getEnvAsInt("JS_VALIDATION_MAX_SIZE_KB", 512),
+			JSMode:      getEnv("JS_VALIDATION_MODE", "reject"),
+		},
+		Limits: LimitsConfig{
+			PDEMaxSizeKB: getEnvAsInt("LIMIT_PDE_MAX_SIZE_KB", 256),
+		},
+		Maintenance: MaintenanceConfig{
+			Enabled: getEnvAsBool("MAINTENANCE_MODE", false),
+			Message: getEnv("MAINTENANCE_MESSAGE", ""),
+		},
+		Trash: TrashConfig{
+			RetentionDays: getEnvAsInt("WORK_TRASH_RETENTION_DAYS", 30),
+		},
+		Storage: StorageConfig{
+			Provider:       getEnv("STORAGE_PROVIDER", "local"),
+			LocalDir:       getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+			AssetMaxSizeMB: getEnvAsInt("ASSET_MAX_SIZE_MB", 200),
+		},
+		AgeGate: AgeGateConfig{
+			MinorAge: getEnvAsInt("AGE_GATE_MINOR_AGE", 18),
+		},
+		Registration: RegistrationConfig{
+			Mode:                 getEnv("REGISTRATION_MODE", RegistrationModeOpen),
+			InviteCodeExpiryDays: getEnvAsInt("REGISTRATION_INVITE_CODE_EXPIRY_DAYS", 14),
+		},
+		Backup: BackupConfig{
+			Prefix:        getEnv("BACKUP_PREFIX", ".backups"),
+			Retention:     getEnvAsInt("BACKUP_RETENTION", 7),
+			Interval:      time.Duration(getEnvAsInt("BACKUP_INTERVAL_HOURS", 0)) * time.Hour,
+			ExcludeTables: getEnvAsStringSlice("BACKUP_EXCLUDE_TABLES", ",", []string{"invocation_logs", "conversion_logs"}),
+		},
+		Archive: ArchiveConfig{
+			AfterYears: getEnvAsInt("ARCHIVE_AFTER_YEARS", 0),
+			MinSizeKB:  getEnvAsInt("ARCHIVE_MIN_SIZE_KB", 64),
+			Prefix:     getEnv("ARCHIVE_PREFIX", ".archive"),
+			Interval:   time.Duration(getEnvAsInt("ARCHIVE_INTERVAL_HOURS", 24)) * time.Hour,
+			BatchSize:  getEnvAsInt("ARCHIVE_BATCH_SIZE", 500),
+		},
+		Ranking: RankingConfig{
+			ViewWeight:    getEnvAsFloat("RANKING_VIEW_WEIGHT", 1),
+			LikeWeight:    getEnvAsFloat("RANKING_LIKE_WEIGHT", 10),
+			CommentWeight: getEnvAsFloat("RANKING_COMMENT_WEIGHT", 20),
+			HalfLifeDays:  getEnvAsFloat("RANKING_HALF_LIFE_DAYS", 14),
+			Interval:      time.Duration(getEnvAsInt("RANKING_INTERVAL_HOURS", 24)) * time.Hour,
+		},
+		Home: HomeConfig{
+			WorksLimit:    getEnvAsInt("HOME_WORKS_LIMIT", 12),
+			ContestsLimit: getEnvAsInt("HOME_CONTESTS_LIMIT", 5),
+			TagsLimit:     getEnvAsInt("HOME_TAGS_LIMIT", 20),
+			CacheTTL:      time.Duration(getEnvAsInt("HOME_CACHE_TTL", 60)) * time.Second,
+		},
+		LinkPreview: LinkPreviewConfig{
+			AllowedHosts: getEnvAsStringSlice("LINK_PREVIEW_ALLOWED_HOSTS", ",", []string{
+				"github.com", "youtube.com", "youtu.be", "openprocessing.org", "p5js.org", "processing.org", "wikipedia.org",
+			}),
+			CacheTTL:      time.Duration(getEnvAsInt("LINK_PREVIEW_CACHE_TTL_HOURS", 24)) * time.Hour,
+			Timeout:       time.Duration(getEnvAsInt("LINK_PREVIEW_TIMEOUT", 5)) * time.Second,
+			MaxBytes:      int64(getEnvAsInt("LINK_PREVIEW_MAX_KB", 512)) * 1024,
+			MaxPerComment: getEnvAsInt("LINK_PREVIEW_MAX_PER_COMMENT", 3),
+		},
+		TagCleanup: TagCleanupConfig{
+			MinAgeDays: getEnvAsInt("TAG_CLEANUP_MIN_AGE_DAYS", 30),
+			Protected:  getEnvAsStringSlice("TAG_CLEANUP_PROTECTED", ",", []string{}),
+			Interval:   time.Duration(getEnvAsInt("TAG_CLEANUP_INTERVAL_HOURS", 24)) * time.Hour,
+		},
+		Organization: OrganizationConfig{
+			DefaultMaxProjects: getEnvAsInt("ORGANIZATION_DEFAULT_MAX_PROJECTS", 0),
+			DefaultMaxMembers:  getEnvAsInt("ORGANIZATION_DEFAULT_MAX_MEMBERS", 0),
+		},
+		Billing: BillingConfig{
+			Free: PlanConfig{
+				MaxProjects:         getEnvAsInt("PLAN_FREE_MAX_PROJECTS", 5),
+				StorageQuotaMB:      getEnvAsInt("LIMIT_STORAGE_QUOTA_MB", 50),
+				ConversionsPerMonth: getEnvAsInt("PLAN_FREE_CONVERSIONS_PER_MONTH", 1000),
+			},
+			Pro: PlanConfig{
+				MaxProjects:         getEnvAsInt("PLAN_PRO_MAX_PROJECTS", 50),
+				StorageQuotaMB:      getEnvAsInt("PLAN_PRO_STORAGE_QUOTA_MB", 1024),
+				ConversionsPerMonth: getEnvAsInt("PLAN_PRO_CONVERSIONS_PER_MONTH", 10000),
+				StripePriceID:       getEnv("STRIPE_PRICE_PRO", ""),
+			},
+			Org: PlanConfig{
+				MaxProjects:         getEnvAsInt("PLAN_ORG_MAX_PROJECTS", 0),
+				StorageQuotaMB:      getEnvAsInt("PLAN_ORG_STORAGE_QUOTA_MB", 10240),
+				ConversionsPerMonth: getEnvAsInt("PLAN_ORG_CONVERSIONS_PER_MONTH", 0),
+				StripePriceID:       getEnv("STRIPE_PRICE_ORG", ""),
+			},
+			StripeWebhookSecret: getEnv("STRIPE_WEBHOOK_SECRET", ""),
+			WebhookTolerance:    time.Duration(getEnvAsInt("STRIPE_WEBHOOK_TOLERANCE", 300)) * time.Second,
+		},
+		Video: VideoConfig{
+			MaxSizeMB:  getEnvAsInt("VIDEO_MAX_SIZE_MB", 50),
+			Transcode:  getEnvAsBool("VIDEO_TRANSCODE", false),
+			FFmpegPath: getEnv("VIDEO_FFMPEG_PATH", "ffmpeg"),
+		},
+		Demo: DemoConfig{
+			Enabled: getEnvAsBool("DEMO_MODE", false),
+		},
+		Mail: MailConfig{
+			Provider:         getEnv("MAIL_PROVIDER", "log"),
+			SMTPHost:         getEnv("SMTP_HOST", ""),
+			SMTPPort:         getEnvAsInt("SMTP_PORT", 587),
+			SMTPUsername:     getEnv("SMTP_USERNAME", ""),
+			SMTPPassword:     getEnv("SMTP_PASSWORD", ""),
+			From:             getEnv("MAIL_FROM", "no-reply@sketchshifter.local"),
+			FrontendURL:      getEnv("FRONTEND_URL", "http://localhost:3000"),
+			InviteExpiryDays: getEnvAsInt("INVITE_EXPIRY_DAYS", 14),
+			Language:         getEnv("MAIL_LANGUAGE", "ja"),
+		},
+		Sitemap: SitemapConfig{
+			SiteURL: getEnv("SITEMAP_SITE_URL", getEnv("FRONTEND_URL", "http://localhost:3000")),
+			BaseURL: getEnv("SITEMAP_BASE_URL", "http://localhost:8080"),
+		},
+		PublicAPI: PublicAPIConfig{
+			RateLimit: getEnvAsInt("PUBLIC_API_RATE_LIMIT", 60),
+			CacheTTL:  time.Duration(getEnvAsInt("PUBLIC_API_CACHE_TTL", 300)) * time.Second,
+		},
+		Circuit: CircuitBreakerConfig{
+			FailureThreshold: getEnvAsInt("CIRCUIT_BREAKER_FAILURE_THRESHOLD", 5),
+			OpenTimeout:      time.Duration(getEnvAsInt("CIRCUIT_BREAKER_OPEN_TIMEOUT", 30)) * time.Second,
+		},
+		HTTPClient: HTTPClientConfig{
+			Timeout:        time.Duration(getEnvAsInt("HTTP_CLIENT_TIMEOUT", 60)) * time.Second,
+			ConnectTimeout: time.Duration(getEnvAsInt("HTTP_CLIENT_CONNECT_TIMEOUT", 10)) * time.Second,
+			MaxRetries:     getEnvAsInt("HTTP_CLIENT_MAX_RETRIES", 2),
+			RetryBackoff:   time.Duration(getEnvAsInt("HTTP_CLIENT_RETRY_BACKOFF_MS", 200)) * time.Millisecond,
+			ProxyURL:       getEnv("HTTP_CLIENT_PROXY_URL", ""),
+		},
+		Fetch: FetchConfig{
+			Timeout:      time.Duration(getEnvAsInt("FETCH_TIMEOUT", 10)) * time.Second,
+			MaxBytes:     int64(getEnvAsInt("FETCH_MAX_KB", 2048)) * 1024,
+			MaxRedirects: getEnvAsInt("FETCH_MAX_REDIRECTS", 3),
+		},
+		Image: ImageConfig{
+			MaxSizeMB: getEnvAsInt("IMAGE_MAX_SIZE_MB", 10),
+			Thumbnail: ImagePipelineConfig{
+				Steps:   getEnvAsStringSlice("IMAGE_THUMBNAIL_STEPS", ",", []string{"resize", "strip_exif", "webp", "blurhash"}),
+				Sizes:   getEnvAsStringSlice("IMAGE_THUMBNAIL_SIZES", ",", []string{"large:1280x720", "small:400x225"}),
+				Crop:    getEnv("IMAGE_THUMBNAIL_CROP", "limit"),
+				Quality: getEnvAsInt("IMAGE_THUMBNAIL_QUALITY", 80),
+			},
+			Avatar: ImagePipelineConfig{
+				Steps:   getEnvAsStringSlice("IMAGE_AVATAR_STEPS", ",", []string{"resize", "strip_exif", "webp", "blurhash"}),
+				Sizes:   getEnvAsStringSlice("IMAGE_AVATAR_SIZES", ",", []string{"large:256x256", "small:64x64"}),
+				Crop:    getEnv("IMAGE_AVATAR_CROP", "fill"),
+				Quality: getEnvAsInt("IMAGE_AVATAR_QUALITY", 80),
+			},
+			Asset: ImagePipelineConfig{
+				Steps:   getEnvAsStringSlice("IMAGE_ASSET_STEPS", ",", []string{"resize", "strip_exif", "webp", "blurhash"}),
+				Sizes:   getEnvAsStringSlice("IMAGE_ASSET_SIZES", ",", []string{"preview:640x640"}),
+				Crop:    getEnv("IMAGE_ASSET_CROP", "limit"),
+				Quality: getEnvAsInt("IMAGE_ASSET_QUALITY", 80),
+			},
+			Snapshot: ImagePipelineConfig{
+				Steps:   getEnvAsStringSlice("IMAGE_SNAPSHOT_STEPS", ",", []string{"resize", "strip_exif", "webp", "blurhash"}),
+				Sizes:   getEnvAsStringSlice("IMAGE_SNAPSHOT_SIZES", ",", []string{"large:1280x720", "small:400x225"}),
+				Crop:    getEnv("IMAGE_SNAPSHOT_CROP", "limit"),
+				Quality: getEnvAsInt("IMAGE_SNAPSHOT_QUALITY", 80),
+			},
+		},
+		Player: PlayerConfig{
+			P5URL: getEnv("PLAYER_P5_URL", "https://cdn.jsdelivr.net/npm/p5@1.9.4/lib/p5.min.js"),
+		},
+		Access: AccessConfig{
+			TrustedProxies:   getEnvAsStringSlice("TRUSTED_PROXIES", ",", []string{}),
+			CountryHeader:    getEnv("GEO_COUNTRY_HEADER", "CF-IPCountry"),
+			BlockedCountries: getEnvAsStringSlice("GEO_BLOCKED_COUNTRIES", ",", []string{}),
+			AllowedCountries: getEnvAsStringSlice("GEO_ALLOWED_COUNTRIES", ",", []string{}),
+		},
+		Report: ReportConfig{
+			HideThreshold:   getEnvAsInt("REPORT_HIDE_THRESHOLD", 5),
+			RehideThreshold: getEnvAsInt("REPORT_REHIDE_THRESHOLD", 15),
+		},
+		Featured: FeaturedConfig{
+			DefaultDays:   getEnvAsInt("FEATURED_DEFAULT_DAYS", 7),
+			BoostHomepage: getEnvAsBool("FEATURED_BOOST_HOMEPAGE", false),
 		},
+		Scheduler: SchedulerConfig{
+			Enabled:                 getEnvAsBool("SCHEDULER_ENABLED", true),
+			VoteCloseInterval:       time.Duration(getEnvAsInt("SCHEDULER_VOTE_CLOSE_INTERVAL", 60)) * time.Second,
+			ReconversionInterval:    time.Duration(getEnvAsInt("SCHEDULER_RECONVERSION_INTERVAL", 30)) * time.Second,
+			CounterInterval:         time.Duration(getEnvAsInt("SCHEDULER_COUNTER_INTERVAL", 3600)) * time.Second,
+			TrashPurgeInterval:      time.Duration(getEnvAsInt("SCHEDULER_TRASH_PURGE_INTERVAL", 3600)) * time.Second,
+			ConversionRetryInterval: time.Duration(getEnvAsInt("SCHEDULER_CONVERSION_RETRY_INTERVAL", 15)) * time.Second,
+			InvocationPurgeInterval: time.Duration(getEnvAsInt("SCHEDULER_INVOCATION_PURGE_INTERVAL", 86400)) * time.Second,
+			RetagInterval:           time.Duration(getEnvAsInt("SCHEDULER_RETAG_INTERVAL", 10)) * time.Second,
+			TokenPurgeInterval:      time.Duration(getEnvAsInt("SCHEDULER_TOKEN_PURGE_INTERVAL", 86400)) * time.Second,
+		},
+	}
+
+	// シークレットを読み込み
+	if err := loadSecrets(config); err != nil {
+		return nil, fmt.Errorf("シークレットの読み込みに失敗しました: %v", err)
+	}
+
+	// 本番環境でデフォルトのJWTシークレットのまま起動しない
+	if config.IsProduction() && config.Auth.JWTSecret == "your-secret-key" {
+		return nil, errors.New("本番環境ではJWT_SECRETを設定してください")
+	}
+
+	if config.HTTPClient.ProxyURL != "" {
+		if proxy, err := url.Parse(config.HTTPClient.ProxyURL); err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("HTTP_CLIENT_PROXY_URLが正しくありません: %s", config.HTTPClient.ProxyURL)
+		}
+	}
+
+	// バックアップ（パスワードのハッシュやトークンを含む）と移したコードが /uploads から配信されないようにする
+	if !isHiddenStoragePrefix(config.Backup.Prefix) {
+		return nil, fmt.Errorf("BACKUP_PREFIXは.で始まる必要があります（/uploadsから配信されないように）: %s", config.Backup.Prefix)
+	}
+	if !isHiddenStoragePrefix(config.Archive.Prefix) {
+		return nil, fmt.Errorf("ARCHIVE_PREFIXは.で始まる必要があります（/uploadsから配信されないように）: %s", config.Archive.Prefix)
+	}
+
+	if config.Database.Driver != DBDriverMySQL && config.Database.Driver != DBDriverMemory {
+		return nil, fmt.Errorf("DB_DRIVERにはmysqlまたはmemoryを指定してください: %s", config.Database.Driver)
+	}
+
+	switch config.Registration.Mode {
+	case RegistrationModeOpen, RegistrationModeInvite, RegistrationModeClosed:
+	default:
+		return nil, fmt.Errorf("REGISTRATION_MODEにはopen、invite、closedのいずれかを指定してください: %s", config.Registration.Mode)
+	}
+
+	// デモデータやメモリ上のデータのまま本番環境で起動しない
+	if config.IsProduction() && config.Demo.Enabled {
+		return nil, errors.New("本番環境ではデモモードを使用できません")
+	}
+	if config.IsProduction() && config.Database.Driver == DBDriverMemory {
+		return nil, errors.New("本番環境ではDB_DRIVER=memoryを使用できません")
 	}
 
 	return config, nil
 }
 
+// IsProduction 本番環境かどうか
+func (c *Config) IsProduction() bool {
+	return c.Env == "production"
+}
+
+// UsesMemoryDB MySQLの代わりにメモリ上のリポジトリを使用するかどうか（デモモードを含む）
+func (c *Config) UsesMemoryDB() bool {
+	return c.Database.Driver == DBDriverMemory || c.Demo.Enabled
+}
+
+// isHiddenStoragePrefix ストレージのキーの接頭辞が.で始まるか（.で始まるキーはローカルストレージの配信の対象外）
+func isHiddenStoragePrefix(prefix string) bool {
+	return strings.HasPrefix(strings.TrimLeft(prefix, "/"), ".")
+}
+
 // getEnv 環境変数を取得、存在しない場合はデフォルト値を返す
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -128,6 +749,24 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsFloat 環境変数を小数として取得
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsBool 環境変数を真偽値として取得
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 // getEnvAsStringSlice 環境変数を文字列スライスとして取得
 func getEnvAsStringSlice(key string, sep string, defaultValue []string) []string {
 	valueStr := getEnv(key, "")
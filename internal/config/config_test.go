@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+// TestLoadDefaultsToLegacyResponseFormat 指定がない場合、v1は従来の形式で返すことを確認
+func TestLoadDefaultsToLegacyResponseFormat(t *testing.T) {
+	t.Setenv("API_RESPONSE_FORMAT", "")
+	os.Unsetenv("API_RESPONSE_FORMAT")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	if cfg.Server.ResponseFormat != "legacy" {
+		t.Errorf("ResponseFormat = %q, want %q", cfg.Server.ResponseFormat, "legacy")
+	}
+}
+
+// TestLoadRejectsServedBackupPrefix /uploadsから配信される接頭辞にバックアップ・移したコードを保存しないことを確認
+func TestLoadRejectsServedBackupPrefix(t *testing.T) {
+	tests := []struct {
+		key, value string
+		wantErr    bool
+	}{
+		{"BACKUP_PREFIX", "backups", true},
+		{"BACKUP_PREFIX", ".private-backups", false},
+		{"ARCHIVE_PREFIX", "archive", true},
+		{"ARCHIVE_PREFIX", "/.archive", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			_, err := Load()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
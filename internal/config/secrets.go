@@ -0,0 +1,141 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// シークレットの取得元
+const (
+	SecretsSourceEnv            = "env"            // 環境変数（ローカル開発用）
+	SecretsSourceSSM            = "ssm"            // SSMパラメータストア
+	SecretsSourceSecretsManager = "secretsmanager" // Secrets Manager
+)
+
+// シークレット取得のタイムアウト
+const secretsFetchTimeout = 10 * time.Second
+
+// SecretsConfig シークレットの取得設定
+type SecretsConfig struct {
+	Source    string // env, ssm, secretsmanager
+	Region    string
+	SSMPrefix string // SSMパラメータ名のプレフィックス（例: /sketchshifter/production/）
+	SecretID  string // Secrets ManagerのシークレットID
+}
+
+// secretTargets シークレットとして外部から取得する設定値（キーは環境変数名と同じ）
+func secretTargets(cfg *Config) map[string]*string {
+	return map[string]*string{
+		"DB_PASSWORD":           &cfg.Database.Password,
+		"JWT_SECRET":            &cfg.Auth.JWTSecret,
+		"GOOGLE_CLIENT_SECRET":  &cfg.Auth.GoogleClientSecret,
+		"GITHUB_CLIENT_SECRET":  &cfg.Auth.GithubClientSecret,
+		"CLOUDINARY_API_KEY":    &cfg.Cloudinary.APIKey,
+		"CLOUDINARY_API_SECRET": &cfg.Cloudinary.APISecret,
+		"SMTP_PASSWORD":         &cfg.Mail.SMTPPassword,
+	}
+}
+
+// loadSecrets 設定された取得元からシークレットを読み込み、環境変数の値を上書きする
+func loadSecrets(cfg *Config) error {
+	var (
+		values map[string]string
+		err    error
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretsFetchTimeout)
+	defer cancel()
+
+	switch cfg.Secrets.Source {
+	case SecretsSourceEnv, "":
+		return nil
+	case SecretsSourceSSM:
+		values, err = fetchSSMSecrets(ctx, cfg.Secrets)
+	case SecretsSourceSecretsManager:
+		values, err = fetchSecretsManagerSecrets(ctx, cfg.Secrets)
+	default:
+		return fmt.Errorf("不明なシークレットの取得元です: %s", cfg.Secrets.Source)
+	}
+	if err != nil {
+		return err
+	}
+
+	loaded := []string{}
+	for key, target := range secretTargets(cfg) {
+		if value, ok := values[key]; ok && value != "" {
+			*target = value
+			loaded = append(loaded, key)
+		}
+	}
+	log.Printf("シークレットを %s から %d 件読み込みました: %s", cfg.Secrets.Source, len(loaded), strings.Join(loaded, ", "))
+
+	return nil
+}
+
+// fetchSSMSecrets SSMパラメータストアのプレフィックス配下のパラメータを取得
+func fetchSSMSecrets(ctx context.Context, secrets SecretsConfig) (map[string]string, error) {
+	if secrets.SSMPrefix == "" {
+		return nil, fmt.Errorf("SSMパラメータのプレフィックスが設定されていません")
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(secrets.Region)})
+	if err != nil {
+		return nil, fmt.Errorf("AWSセッションの作成に失敗しました: %v", err)
+	}
+	client := ssm.New(sess)
+
+	prefix := strings.TrimSuffix(secrets.SSMPrefix, "/") + "/"
+	values := map[string]string{}
+	input := &ssm.GetParametersByPathInput{
+		Path:           aws.String(prefix),
+		WithDecryption: aws.Bool(true),
+	}
+	err = client.GetParametersByPathPagesWithContext(ctx, input, func(page *ssm.GetParametersByPathOutput, lastPage bool) bool {
+		for _, param := range page.Parameters {
+			key := strings.TrimPrefix(aws.StringValue(param.Name), prefix)
+			values[key] = aws.StringValue(param.Value)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SSMパラメータの取得に失敗しました: %v", err)
+	}
+
+	return values, nil
+}
+
+// fetchSecretsManagerSecrets Secrets ManagerからJSON形式のシークレットを取得
+func fetchSecretsManagerSecrets(ctx context.Context, secrets SecretsConfig) (map[string]string, error) {
+	if secrets.SecretID == "" {
+		return nil, fmt.Errorf("Secrets ManagerのシークレットIDが設定されていません")
+	}
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String(secrets.Region)})
+	if err != nil {
+		return nil, fmt.Errorf("AWSセッションの作成に失敗しました: %v", err)
+	}
+	client := secretsmanager.New(sess)
+
+	output, err := client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secrets.SecretID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("シークレットの取得に失敗しました: %v", err)
+	}
+
+	values := map[string]string{}
+	if err := json.Unmarshal([]byte(aws.StringValue(output.SecretString)), &values); err != nil {
+		return nil, fmt.Errorf("シークレットをパースできませんでした（キーが環境変数名のJSONである必要があります）: %v", err)
+	}
+
+	return values, nil
+}
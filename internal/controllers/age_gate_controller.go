@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AgeGateController 生まれた年と未成年のメンバーの見守りに関するコントローラー
+type AgeGateController struct {
+	ageGateService services.AgeGateService
+}
+
+// NewAgeGateController AgeGateControllerを作成
+func NewAgeGateController(ageGateService services.AgeGateService) *AgeGateController {
+	return &AgeGateController{
+		ageGateService: ageGateService,
+	}
+}
+
+// GetAge 自分の生まれた年と利用制限の状態を取得
+func (c *AgeGateController) GetAge(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	status, err := c.ageGateService.GetStatus(ctx.Request.Context(), u.ID)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "age", status)
+}
+
+// SetBirthYear 自分の生まれた年を登録（一度登録すると変更できない）
+func (c *AgeGateController) SetBirthYear(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req struct {
+		BirthYear int `json:"birth_year" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	status, err := c.ageGateService.SetBirthYear(ctx.Request.Context(), u.ID, req.BirthYear)
+	if err != nil {
+		if strings.Contains(err.Error(), "変更できません") {
+			utils.RespondError(ctx, http.StatusConflict, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "age", status)
+}
+
+// SetSupervision プロジェクトのメンバーの見守りを設定（プロジェクトのオーナーのみ）
+func (c *AgeGateController) SetSupervision(ctx *gin.Context) {
+	// プロジェクトIDを解析
+	projectID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なプロジェクトIDです")
+		return
+	}
+
+	// メンバーIDを解析
+	memberID, err := strconv.ParseUint(ctx.Param("memberID"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なメンバーIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req struct {
+		Supervised *bool `json:"supervised" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := c.ageGateService.SetSupervised(ctx.Request.Context(), uint(projectID), u.ID, uint(memberID), *req.Supervised); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "権限がありません"):
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+		case strings.Contains(err.Error(), "見つかりません"):
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		default:
+			utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,176 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnnotationController 作品のコード注釈に関するコントローラー
+type AnnotationController struct {
+	annotationService services.AnnotationService
+}
+
+// NewAnnotationController AnnotationControllerを作成
+func NewAnnotationController(annotationService services.AnnotationService) *AnnotationController {
+	return &AnnotationController{
+		annotationService: annotationService,
+	}
+}
+
+// AnnotationRequest 注釈リクエスト
+type AnnotationRequest struct {
+	Line    int    `json:"line" binding:"required"`
+	Content string `json:"content" binding:"required"`
+}
+
+// List 作品の注釈一覧を取得
+func (c *AnnotationController) List(ctx *gin.Context) {
+	// 作品IDを解析
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// コードが非公開の作品は作者のみ閲覧できるため、ログインしている場合はユーザーIDを渡す
+	var viewerID *uint
+	if user, exists := ctx.Get("user"); exists {
+		viewerID = &user.(*models.User).ID
+	}
+
+	annotations, err := c.annotationService.ListByWork(ctx.Request.Context(), uint(workID), viewerID)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "annotations", annotations)
+}
+
+// Create 作品のコードに注釈を追加
+func (c *AnnotationController) Create(ctx *gin.Context) {
+	// 作品IDを解析
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	var req AnnotationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	annotation, err := c.annotationService.Create(ctx.Request.Context(), uint(workID), u.ID, req.Line, req.Content)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "annotation", annotation)
+}
+
+// Update 注釈を更新
+func (c *AnnotationController) Update(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	var req AnnotationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	annotation, err := c.annotationService.Update(ctx.Request.Context(), uint(id), u.ID, req.Line, req.Content)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "annotation", annotation)
+}
+
+// Delete 注釈を削除
+func (c *AnnotationController) Delete(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.annotationService.Delete(ctx.Request.Context(), uint(id), u.ID); err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
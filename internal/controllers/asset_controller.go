@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// アセットのキャッシュ期間（秒）
+const assetCacheMaxAge = 86400
+
+// ユーザーがアップロードしたファイルに付けるCSP
+// 直接開かれてもAPIと同じオリジンでスクリプトを実行させない（画像・音声としての読み込みには影響しない）
+const userContentSecurityPolicy = "sandbox; default-src 'none'"
+
+// AssetController 作品のアセットの配信に関するコントローラー
+type AssetController struct {
+	assetService services.AssetService
+}
+
+// NewAssetController AssetControllerを作成
+func NewAssetController(assetService services.AssetService) *AssetController {
+	return &AssetController{
+		assetService: assetService,
+	}
+}
+
+// Get アセットをIDで配信
+func (c *AssetController) Get(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	asset, file, err := c.assetService.Open(ctx.Request.Context(), uint(id))
+	if err != nil {
+		respondUploadError(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	serveAsset(ctx, asset, file)
+}
+
+// Proxy 作品のアセットをファイル名で配信
+// 変換後のスケッチが別オリジンから画像や音声を読み込めるようにする
+func (c *AssetController) Proxy(ctx *gin.Context) {
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な作品IDです")
+		return
+	}
+	filename := strings.TrimPrefix(ctx.Param("filename"), "/")
+
+	asset, file, err := c.assetService.OpenByFilename(ctx.Request.Context(), uint(workID), filename)
+	if err != nil {
+		respondUploadError(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	serveAsset(ctx, asset, file)
+}
+
+// serveAsset CORSとRangeリクエストに対応してアセットを返す
+// 認証情報は不要なため、どのオリジンからも読み込めるようにする
+// （canvasを汚染せずにloadImageやWeb Audioで扱うには、CORSが許可されている必要がある）
+func serveAsset(ctx *gin.Context, asset *models.WorkAsset, file io.ReadSeeker) {
+	header := ctx.Writer.Header()
+	header.Set("Access-Control-Allow-Origin", "*")
+	header.Del("Access-Control-Allow-Credentials")
+	header.Set("Access-Control-Expose-Headers", "Accept-Ranges, Content-Range, Content-Length, Content-Type, ETag")
+	header.Set("Cross-Origin-Resource-Policy", "cross-origin")
+	header.Set("Timing-Allow-Origin", "*")
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", assetCacheMaxAge))
+	// アセットの内容は変更されないため、IDをETagとして使用する（If-Rangeにも対応）
+	header.Set("ETag", fmt.Sprintf(`"asset-%d"`, asset.ID))
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Content-Security-Policy", userContentSecurityPolicy)
+	// 保存済みの形式ではなく拡張子から決め直す（以前にクライアントの指定のまま保存したアセットも含む）
+	contentType := services.AssetContentType(asset.Filename)
+	header.Set("Content-Type", contentType)
+	if contentType == services.AssetDownloadContentType {
+		header.Set("Content-Disposition", "attachment")
+	}
+
+	http.ServeContent(ctx.Writer, ctx.Request, asset.Filename, asset.CreatedAt, file)
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestServeAssetSandboxesUserContent HTMLのアセットがダウンロードとして、サンドボックスのCSP付きで返されることを確認
+func TestServeAssetSandboxesUserContent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/assets/1", nil)
+
+	asset := &models.WorkAsset{ID: 1, Filename: "x.html", ContentType: "text/html", CreatedAt: time.Now()}
+	serveAsset(ctx, asset, strings.NewReader("<script>alert(1)</script>"))
+
+	if got := w.Header().Get("Content-Security-Policy"); got != userContentSecurityPolicy {
+		t.Errorf("Content-Security-Policy = %q, want %q", got, userContentSecurityPolicy)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Content-Type = %q, want application/octet-stream", got)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != "attachment" {
+		t.Errorf("Content-Disposition = %q, want attachment", got)
+	}
+}
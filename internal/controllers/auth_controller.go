@@ -1,11 +1,14 @@
 package controllers
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strings"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -28,6 +31,8 @@ type RegisterRequest struct {
 	Password string `json:"password" binding:"required,min=6"`
 	Name     string `json:"name" binding:"required"`
 	Nickname string `json:"nickname" binding:"required"`
+	// 招待制（REGISTRATION_MODE=invite）の場合のみ必要
+	InviteCode string `json:"invite_code"`
 }
 
 // LoginRequest ログインリクエスト
@@ -38,58 +43,125 @@ type LoginRequest struct {
 
 // PasswordChangeRequest パスワード変更リクエスト
 type PasswordChangeRequest struct {
-	CurrentPassword string `json:"current_password" binding:"required"`
+	CurrentPassword string `json:"current_password"` // パスワードを設定していない（GitHubで登録した）場合は不要
 	NewPassword     string `json:"new_password" binding:"required,min=6"`
 }
 
+// AcceptInvitationRequest 招待の承認リクエスト
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+	Nickname string `json:"nickname"`
+}
+
+// EmailChangeRequest メールアドレス変更リクエスト
+type EmailChangeRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// ConfirmEmailChangeRequest メールアドレス変更の確認リクエスト
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// RefreshTokenRequest リフレッシュトークンを使うリクエスト
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest ログアウトリクエスト
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"` // 省略した場合はAuthorizationヘッダーのアクセストークンのみ無効にする
+}
+
 // AuthResponse 認証レスポンス
 type AuthResponse struct {
-	User  interface{} `json:"user"`
-	Token string      `json:"token"`
+	User         interface{} `json:"user"`
+	Token        string      `json:"token"`
+	RefreshToken string      `json:"refresh_token"` // tokenの期限が切れたら POST /auth/refresh で再発行する
+}
+
+// respondAuth ログインしたユーザーにアクセストークンと新しいログインのリフレッシュトークンを返す
+func respondAuth(ctx *gin.Context, authService services.AuthService, status int, user *models.User, token string) {
+	refreshToken, err := authService.IssueRefreshToken(ctx.Request.Context(), user.ID)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, status, "", AuthResponse{
+		User:         user,
+		Token:        token,
+		RefreshToken: refreshToken,
+	})
 }
 
 // Register ユーザー登録
 func (c *AuthController) Register(ctx *gin.Context) {
 	var req RegisterRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	user, token, err := c.authService.Register(req.Email, req.Password, req.Name, req.Nickname)
+	user, token, err := c.authService.Register(ctx.Request.Context(), req.Email, req.Password, req.Name, req.Nickname, req.InviteCode)
 	if err != nil {
 		if strings.Contains(err.Error(), "既に使用されています") {
-			ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusConflict, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "受け付けていません") || strings.Contains(err.Error(), "招待コード") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, AuthResponse{
-		User:  user,
-		Token: token,
-	})
+	respondAuth(ctx, c.authService, http.StatusCreated, user, token)
+}
+
+// AcceptInvitation 招待を承認してパスワードを設定
+func (c *AuthController) AcceptInvitation(ctx *gin.Context) {
+	var req AcceptInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, token, err := c.authService.AcceptInvitation(ctx.Request.Context(), req.Token, req.Password, req.Nickname)
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "有効期限") {
+			utils.RespondError(ctx, http.StatusGone, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondAuth(ctx, c.authService, http.StatusOK, user, token)
 }
 
 // Login ログイン
 func (c *AuthController) Login(ctx *gin.Context) {
 	var req LoginRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	user, token, err := c.authService.Login(req.Email, req.Password)
+	user, token, err := c.authService.Login(ctx.Request.Context(), req.Email, req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, AuthResponse{
-		User:  user,
-		Token: token,
-	})
+	respondAuth(ctx, c.authService, http.StatusOK, user, token)
 }
 
 // GetMe 現在のユーザー情報を取得
@@ -97,11 +169,11 @@ func (c *AuthController) GetMe(ctx *gin.Context) {
 	// コンテキストからユーザーを取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	utils.Respond(ctx, http.StatusOK, "", user)
 }
 
 // ChangePassword パスワードを変更
@@ -109,7 +181,7 @@ func (c *AuthController) ChangePassword(ctx *gin.Context) {
 	// ユーザーを取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
@@ -117,15 +189,172 @@ func (c *AuthController) ChangePassword(ctx *gin.Context) {
 	// リクエストをバインド
 	var req PasswordChangeRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// パスワードを変更
-	if err := c.authService.ChangePassword(u.ID, req.CurrentPassword, req.NewPassword); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := c.authService.ChangePassword(ctx.Request.Context(), u.ID, req.CurrentPassword, req.NewPassword); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "message", "パスワードが正常に変更されました")
+}
+
+// RequestEmailChange 新しいメールアドレスに確認メールを送信
+func (c *AuthController) RequestEmailChange(ctx *gin.Context) {
+	// ユーザーを取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// リクエストをバインド
+	var req EmailChangeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "パスワードが正常に変更されました"})
+	if err := c.authService.RequestEmailChange(ctx.Request.Context(), u.ID, req.Email, req.Password); err != nil {
+		if strings.Contains(err.Error(), "既に使用されています") {
+			utils.RespondError(ctx, http.StatusConflict, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusAccepted, "message", "確認メールを送信しました。メール内のリンクを開くと変更が完了します")
+}
+
+// ConfirmEmailChange 確認リンクのトークンでメールアドレスを変更
+func (c *AuthController) ConfirmEmailChange(ctx *gin.Context) {
+	var req ConfirmEmailChangeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, token, err := c.authService.ConfirmEmailChange(ctx.Request.Context(), req.Token)
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "有効期限") {
+			utils.RespondError(ctx, http.StatusGone, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "既に使用されています") {
+			utils.RespondError(ctx, http.StatusConflict, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondAuth(ctx, c.authService, http.StatusOK, user, token)
+}
+
+// Refresh リフレッシュトークンでアクセストークンを再発行（リフレッシュトークンも新しいものに置き換わる）
+func (c *AuthController) Refresh(ctx *gin.Context) {
+	var req RefreshTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, token, refreshToken, err := c.authService.Refresh(ctx.Request.Context(), req.RefreshToken)
+	if err != nil {
+		if strings.Contains(err.Error(), "無効です") {
+			utils.RespondError(ctx, http.StatusUnauthorized, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "", AuthResponse{
+		User:         user,
+		Token:        token,
+		RefreshToken: refreshToken,
+	})
+}
+
+// Logout Authorizationヘッダーのアクセストークンと、リフレッシュトークン（同じログインで発行したものを含む）を無効にする
+func (c *AuthController) Logout(ctx *gin.Context) {
+	// 本文は省略できる
+	var req LogoutRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	tokenString := bearerToken(ctx)
+	if req.RefreshToken == "" && tokenString == "" {
+		utils.RespondError(ctx, http.StatusBadRequest, "アクセストークンかリフレッシュトークンを指定してください")
+		return
+	}
+
+	if req.RefreshToken != "" {
+		if err := c.authService.RevokeRefreshToken(ctx.Request.Context(), req.RefreshToken); err != nil {
+			if strings.Contains(err.Error(), "無効です") {
+				utils.RespondError(ctx, http.StatusUnauthorized, err.Error())
+				return
+			}
+			utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	// 期限切れのアクセストークンは無効にする必要がないため、リフレッシュトークンを指定した場合はエラーにしない
+	if tokenString != "" {
+		if err := c.authService.RevokeToken(ctx.Request.Context(), tokenString); err != nil {
+			if strings.Contains(err.Error(), "失敗しました") {
+				utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if req.RefreshToken == "" {
+				utils.RespondError(ctx, http.StatusUnauthorized, err.Error())
+				return
+			}
+		}
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// LogoutAll 全ての端末からログアウト（発行済みのアクセストークンとリフレッシュトークンを全て無効にする）
+func (c *AuthController) LogoutAll(ctx *gin.Context) {
+	// ユーザーを取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.authService.RevokeAllTokens(ctx.Request.Context(), u.ID); err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// 同じ秒に発行されたトークンは一括の無効化の対象外のため、使用中のトークンは個別に無効にする
+	if err := c.authService.RevokeToken(ctx.Request.Context(), bearerToken(ctx)); err != nil && strings.Contains(err.Error(), "失敗しました") {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// bearerToken AuthorizationヘッダーのBearerトークンを取得（ない場合は空文字）
+func bearerToken(ctx *gin.Context) string {
+	authHeader := ctx.GetHeader("Authorization")
+	if !strings.HasPrefix(authHeader, "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
 }
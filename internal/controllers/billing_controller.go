@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 受け付けるWebhookの最大サイズ
+const maxWebhookSize = 1 << 16
+
+// BillingController 料金プランに関するコントローラー
+type BillingController struct {
+	billingService services.BillingService
+	limitService   services.LimitService
+}
+
+// NewBillingController BillingControllerを作成
+func NewBillingController(billingService services.BillingService, limitService services.LimitService) *BillingController {
+	return &BillingController{
+		billingService: billingService,
+		limitService:   limitService,
+	}
+}
+
+// GetPlan 自分の料金プランと上限・使用状況を取得
+func (c *BillingController) GetPlan(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	status, err := c.limitService.Status(ctx.Request.Context(), u.ID)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "plan", status)
+}
+
+// StripeWebhook StripeのWebhookを受け取り、契約の状態を更新
+func (c *BillingController) StripeWebhook(ctx *gin.Context) {
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxWebhookSize)
+	payload, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "Webhookの読み込みに失敗しました")
+		return
+	}
+
+	err = c.billingService.HandleStripeWebhook(ctx.Request.Context(), payload, ctx.GetHeader("Stripe-Signature"))
+	if errors.Is(err, services.ErrInvalidWebhookSignature) {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		// 保存に失敗した場合はStripeに再送させる
+		if strings.Contains(err.Error(), "失敗しました") {
+			utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BlocklistController IPアドレスのブロックリストに関するコントローラー（管理者用）
+type BlocklistController struct {
+	blocklistService services.BlocklistService
+}
+
+// NewBlocklistController BlocklistControllerを作成
+func NewBlocklistController(blocklistService services.BlocklistService) *BlocklistController {
+	return &BlocklistController{
+		blocklistService: blocklistService,
+	}
+}
+
+// List ブロックリストを取得
+func (c *BlocklistController) List(ctx *gin.Context) {
+	blocks, err := c.blocklistService.List(ctx.Request.Context())
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "ip_blocks", blocks)
+}
+
+// Create IPアドレスまたはCIDRをブロックリストに追加
+func (c *BlocklistController) Create(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// リクエストをバインド
+	var req struct {
+		CIDR      string     `json:"cidr" binding:"required"`
+		Reason    string     `json:"reason"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	block, err := c.blocklistService.Add(ctx.Request.Context(), req.CIDR, req.Reason, req.ExpiresAt, u.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "既に") {
+			utils.RespondError(ctx, http.StatusConflict, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "ip_block", block)
+}
+
+// Delete ブロックリストから削除
+func (c *BlocklistController) Delete(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	if err := c.blocklistService.Remove(ctx.Request.Context(), uint(id)); err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "message", "ブロックを削除しました")
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarController プロジェクトのカレンダー（iCal）に関するコントローラー
+type CalendarController struct {
+	calendarService services.CalendarService
+}
+
+// NewCalendarController CalendarControllerを作成
+func NewCalendarController(calendarService services.CalendarService) *CalendarController {
+	return &CalendarController{
+		calendarService: calendarService,
+	}
+}
+
+// GetURL カレンダーを購読するためのURLを取得
+func (c *CalendarController) GetURL(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	url, err := c.calendarService.FeedURL(ctx.Request.Context(), uint(id), u.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "calendar", gin.H{"url": url})
+}
+
+// Feed カレンダーをiCal形式で取得（URLのトークンで認証）
+func (c *CalendarController) Feed(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	body, err := c.calendarService.Feed(ctx.Request.Context(), uint(id), ctx.Query("token"))
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
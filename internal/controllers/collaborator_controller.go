@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CollaboratorController 作品の共同編集者に関するコントローラー
+type CollaboratorController struct {
+	collaboratorService services.CollaboratorService
+}
+
+// NewCollaboratorController CollaboratorControllerを作成
+func NewCollaboratorController(collaboratorService services.CollaboratorService) *CollaboratorController {
+	return &CollaboratorController{
+		collaboratorService: collaboratorService,
+	}
+}
+
+// List 作品の共同編集者一覧を取得（作成者には招待中のユーザーも含める）
+func (c *CollaboratorController) List(ctx *gin.Context) {
+	// 作品IDを解析
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	var viewerID *uint
+	if user, exists := ctx.Get("user"); exists {
+		viewerID = &user.(*models.User).ID
+	}
+
+	collaborators, err := c.collaboratorService.List(ctx.Request.Context(), uint(workID), viewerID)
+	if err != nil {
+		respondCollaboratorError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "collaborators", collaborators)
+}
+
+// Invite ユーザーを共同編集者に招待（作成者のみ）
+func (c *CollaboratorController) Invite(ctx *gin.Context) {
+	// 作品IDを解析
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req struct {
+		Nickname   string `json:"nickname" binding:"required"`
+		Permission string `json:"permission"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Permission == "" {
+		req.Permission = models.CollaboratorPermissionEdit
+	}
+
+	collaborator, err := c.collaboratorService.Invite(ctx.Request.Context(), uint(workID), u.ID, req.Nickname, req.Permission)
+	if err != nil {
+		respondCollaboratorError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "collaborator", collaborator)
+}
+
+// Accept 共同編集の招待を承認
+func (c *CollaboratorController) Accept(ctx *gin.Context) {
+	// 作品IDを解析
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	collaborator, err := c.collaboratorService.Accept(ctx.Request.Context(), uint(workID), u.ID)
+	if err != nil {
+		respondCollaboratorError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "collaborator", collaborator)
+}
+
+// UpdatePermission 共同編集者の権限を変更（作成者のみ）
+func (c *CollaboratorController) UpdatePermission(ctx *gin.Context) {
+	workID, userID, ok := parseCollaboratorParams(ctx)
+	if !ok {
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req struct {
+		Permission string `json:"permission" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collaborator, err := c.collaboratorService.UpdatePermission(ctx.Request.Context(), workID, u.ID, userID, req.Permission)
+	if err != nil {
+		respondCollaboratorError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "collaborator", collaborator)
+}
+
+// Remove 共同編集者を外す（作成者、または共同編集者自身）
+func (c *CollaboratorController) Remove(ctx *gin.Context) {
+	workID, userID, ok := parseCollaboratorParams(ctx)
+	if !ok {
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.collaboratorService.Remove(ctx.Request.Context(), workID, u.ID, userID); err != nil {
+		respondCollaboratorError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListInvitations 自分宛ての承認していない共同編集の招待一覧を取得
+func (c *CollaboratorController) ListInvitations(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	invitations, err := c.collaboratorService.ListInvitations(ctx.Request.Context(), u.ID)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "invitations", invitations)
+}
+
+// parseCollaboratorParams パスの作品IDとユーザーIDを解析（無効な場合はエラーレスポンスを返してfalse）
+func parseCollaboratorParams(ctx *gin.Context) (uint, uint, bool) {
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return 0, 0, false
+	}
+	userID, err := strconv.ParseUint(ctx.Param("userID"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なユーザーIDです")
+		return 0, 0, false
+	}
+	return uint(workID), uint(userID), true
+}
+
+// respondCollaboratorError 共同編集者のエラーを対応するステータスで返す
+func respondCollaboratorError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrAlreadyCollaborator):
+		utils.RespondError(ctx, http.StatusConflict, err.Error())
+	case strings.Contains(err.Error(), "権限がありません"):
+		utils.RespondError(ctx, http.StatusForbidden, err.Error())
+	case strings.Contains(err.Error(), "見つかりません"):
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+	case strings.Contains(err.Error(), "失敗しました"):
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+	default:
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+	}
+}
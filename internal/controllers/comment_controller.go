@@ -1,12 +1,17 @@
 package controllers
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,7 +30,8 @@ func NewCommentController(commentService services.CommentService) *CommentContro
 
 // CommentRequest コメントリクエスト
 type CommentRequest struct {
-	Content string `json:"content" binding:"required"`
+	Content  string `json:"content" binding:"required"`
+	ParentID *uint  `json:"parent_id"` // 返信先のコメントID（作成時のみ）
 }
 
 // Create 新しいコメントを作成
@@ -33,40 +39,42 @@ func (c *CommentController) Create(ctx *gin.Context) {
 	// IDを解析
 	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	var req CommentRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// コメントを作成
 	comment, err := c.commentService.Create(
+		ctx.Request.Context(),
 		req.Content,
 		uint(workID),
 		u.ID,
+		req.ParentID,
 	)
 	if err != nil {
 		if strings.Contains(err.Error(), "見つかりません") {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{"comment": comment})
+	utils.Respond(ctx, http.StatusCreated, "comment", comment)
 }
 
 // Update コメントを更新
@@ -74,40 +82,40 @@ func (c *CommentController) Update(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	var req CommentRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// コメントを更新
-	comment, err := c.commentService.Update(uint(id), u.ID, req.Content)
+	comment, err := c.commentService.Update(ctx.Request.Context(), uint(id), u.ID, req.Content)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
 		if strings.Contains(err.Error(), "見つかりません") {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"comment": comment})
+	utils.Respond(ctx, http.StatusOK, "comment", comment)
 }
 
 // Delete コメントを削除
@@ -115,29 +123,29 @@ func (c *CommentController) Delete(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// コメントを削除
-	if err := c.commentService.Delete(uint(id), u.ID); err != nil {
+	if err := c.commentService.Delete(ctx.Request.Context(), uint(id), u.ID); err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
 		if strings.Contains(err.Error(), "見つかりません") {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -149,13 +157,15 @@ func (c *CommentController) List(ctx *gin.Context) {
 	// 作品IDを解析
 	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// クエリパラメータを取得
 	pageStr := ctx.DefaultQuery("page", "1")
 	limitStr := ctx.DefaultQuery("limit", "20")
+	sort := ctx.DefaultQuery("sort", "newest")
+	afterIDStr := ctx.Query("after_id")
 
 	// 数値パラメータを解析
 	page, err := strconv.Atoi(pageStr)
@@ -168,21 +178,356 @@ func (c *CommentController) List(ctx *gin.Context) {
 		limit = 20
 	}
 
+	switch sort {
+	case "newest", "oldest", "top":
+	default:
+		utils.RespondError(ctx, http.StatusBadRequest, "sortにはnewest, oldest, topのいずれかを指定してください")
+		return
+	}
+
+	var afterID uint64
+	if afterIDStr != "" {
+		afterID, err = strconv.ParseUint(afterIDStr, 10, 32)
+		if err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, "無効なafter_idです")
+			return
+		}
+	}
+
 	// コメント一覧を取得
-	comments, total, pages, err := c.commentService.ListByWork(uint(workID), page, limit)
+	comments, total, pages, err := c.commentService.ListByWork(ctx.Request.Context(), uint(workID), page, limit, sort, uint(afterID))
 	if err != nil {
 		if strings.Contains(err.Error(), "見つかりません") {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"comments": comments,
-		"total":    total,
-		"pages":    pages,
-		"page":     page,
+	// 続きを読み込むためのカーソル（先頭のピン留めコメントは数えない）
+	var nextAfterID *uint
+	regular := comments
+	if len(regular) > 0 && regular[0].Pinned {
+		regular = regular[1:]
+	}
+	if len(regular) == limit {
+		nextAfterID = &regular[len(regular)-1].ID
+	}
+
+	respondPaginated(ctx, "comments", comments, total, page, limit, pages, gin.H{
+		"sort":          sort,
+		"next_after_id": nextAfterID,
 	})
 }
+
+// Pin コメントをピン留め（作品の作者のみ）
+func (c *CommentController) Pin(ctx *gin.Context) {
+	c.setPinned(ctx, true)
+}
+
+// Unpin コメントのピン留めを解除（作品の作者のみ）
+func (c *CommentController) Unpin(ctx *gin.Context) {
+	c.setPinned(ctx, false)
+}
+
+// setPinned コメントのピン留めを設定
+func (c *CommentController) setPinned(ctx *gin.Context, pinned bool) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	comment, err := c.commentService.SetPinned(ctx.Request.Context(), uint(id), u.ID, pinned)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "comment", comment)
+}
+
+// Resolve コメントを対応済みにする（作品の作者のみ）
+func (c *CommentController) Resolve(ctx *gin.Context) {
+	c.setResolved(ctx, true)
+}
+
+// Unresolve コメントを未対応に戻す（作品の作者のみ）
+func (c *CommentController) Unresolve(ctx *gin.Context) {
+	c.setResolved(ctx, false)
+}
+
+// setResolved コメントの対応状況を設定
+func (c *CommentController) setResolved(ctx *gin.Context, resolved bool) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	comment, err := c.commentService.SetResolved(ctx.Request.Context(), uint(id), u.ID, resolved)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "comment", comment)
+}
+
+// commentExportCSVHeader エクスポートするCSVのヘッダー
+var commentExportCSVHeader = []string{"id", "parent_id", "user_id", "nickname", "content", "pinned", "resolved", "resolved_at", "created_at", "updated_at"}
+
+// commentExportRecord エクスポートするコメントの1行
+type commentExportRecord struct {
+	ID         uint       `json:"id"`
+	ParentID   *uint      `json:"parent_id,omitempty"`
+	UserID     uint       `json:"user_id"`
+	Nickname   string     `json:"nickname"`
+	Content    string     `json:"content"`
+	Pinned     bool       `json:"pinned"`
+	Resolved   bool       `json:"resolved"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// newCommentExportRecord コメントからエクスポート用の行を作成
+func newCommentExportRecord(comment models.Comment) commentExportRecord {
+	return commentExportRecord{
+		ID:         comment.ID,
+		ParentID:   comment.ParentID,
+		UserID:     comment.UserID,
+		Nickname:   comment.User.Nickname,
+		Content:    comment.Content,
+		Pinned:     comment.Pinned,
+		Resolved:   comment.ResolvedAt != nil,
+		ResolvedAt: comment.ResolvedAt,
+		CreatedAt:  comment.CreatedAt,
+		UpdatedAt:  comment.UpdatedAt,
+	}
+}
+
+// csvRow CSVの1行に変換（数式として解釈されるセルはエスケープする）
+func (r commentExportRecord) csvRow() []string {
+	parentID, resolvedAt := "", ""
+	if r.ParentID != nil {
+		parentID = strconv.FormatUint(uint64(*r.ParentID), 10)
+	}
+	if r.ResolvedAt != nil {
+		resolvedAt = r.ResolvedAt.Format(time.RFC3339)
+	}
+	return utils.CSVSafeRecord([]string{
+		strconv.FormatUint(uint64(r.ID), 10),
+		parentID,
+		strconv.FormatUint(uint64(r.UserID), 10),
+		r.Nickname,
+		r.Content,
+		strconv.FormatBool(r.Pinned),
+		strconv.FormatBool(r.Resolved),
+		resolvedAt,
+		r.CreatedAt.Format(time.RFC3339),
+		r.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+// GetSubscription 作品のコメントの通知を受け取るかどうかを取得
+func (c *CommentController) GetSubscription(ctx *gin.Context) {
+	// 作品IDを解析
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	subscription, err := c.commentService.GetSubscription(ctx.Request.Context(), uint(workID), u.ID)
+	if err != nil {
+		respondSubscriptionError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "subscription", subscription)
+}
+
+// Subscribe 作品のコメントの通知を受け取る
+func (c *CommentController) Subscribe(ctx *gin.Context) {
+	c.setSubscription(ctx, true)
+}
+
+// Unsubscribe 作品のコメントの通知を受け取らない
+func (c *CommentController) Unsubscribe(ctx *gin.Context) {
+	c.setSubscription(ctx, false)
+}
+
+// setSubscription 作品のコメントの通知を受け取るかどうかを設定
+func (c *CommentController) setSubscription(ctx *gin.Context, subscribed bool) {
+	// 作品IDを解析
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	subscription, err := c.commentService.SetSubscription(ctx.Request.Context(), uint(workID), u.ID, subscribed)
+	if err != nil {
+		respondSubscriptionError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "subscription", subscription)
+}
+
+// respondSubscriptionError コメントの通知の設定のエラーをステータスコードに変換して返す
+func respondSubscriptionError(ctx *gin.Context, err error) {
+	if strings.Contains(err.Error(), "見つかりません") {
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+}
+
+// Export 作品のコメントをCSVまたはJSONでエクスポート（作品の作者のみ）
+// コメント数が多い場合に備え、少しずつ読み込みながらレスポンスに書き出す
+func (c *CommentController) Export(ctx *gin.Context) {
+	// 作品IDを解析
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	format := ctx.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		utils.RespondError(ctx, http.StatusBadRequest, "formatにはcsvまたはjsonを指定してください")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// 最初のバッチを書き出す前にヘッダーとCSVの見出し行（JSONの場合は配列の開始）を書き出す
+	csvWriter := csv.NewWriter(ctx.Writer)
+	started := false
+	start := func() {
+		if started {
+			return
+		}
+		started = true
+		if format == "csv" {
+			ctx.Header("Content-Type", "text/csv; charset=utf-8")
+		} else {
+			ctx.Header("Content-Type", "application/json; charset=utf-8")
+		}
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=work_%d_comments.%s", workID, format))
+		ctx.Status(http.StatusOK)
+		if format == "csv" {
+			csvWriter.Write(commentExportCSVHeader)
+		} else {
+			ctx.Writer.WriteString("[")
+		}
+	}
+
+	count := 0
+	err = c.commentService.Export(ctx.Request.Context(), uint(workID), u.ID, func(comments []models.Comment) error {
+		start()
+		for _, comment := range comments {
+			record := newCommentExportRecord(comment)
+			if format == "csv" {
+				csvWriter.Write(record.csvRow())
+			} else {
+				data, err := json.Marshal(record)
+				if err != nil {
+					return err
+				}
+				if count > 0 {
+					ctx.Writer.WriteString(",")
+				}
+				ctx.Writer.Write(data)
+			}
+			count++
+		}
+		csvWriter.Flush()
+		ctx.Writer.Flush()
+		return csvWriter.Error()
+	})
+	if err != nil && !started {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err != nil {
+		// 書き出し開始後のエラーはステータスを変更できないため中断のみ
+		ctx.Error(err)
+		return
+	}
+
+	// コメントが1件もない場合もヘッダーを書き出し、JSON配列を閉じる
+	start()
+	if format == "csv" {
+		csvWriter.Flush()
+		return
+	}
+	ctx.Writer.WriteString("]")
+}
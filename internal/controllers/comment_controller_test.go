@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+)
+
+// TestCommentExportEscapesFormulaCells 数式として解釈されるコメントがエスケープされてCSVに書き出されることを確認
+func TestCommentExportEscapesFormulaCells(t *testing.T) {
+	now := time.Now()
+	comment := models.Comment{
+		ID:        1,
+		UserID:    2,
+		Content:   `=HYPERLINK("http://evil.example.com","click")`,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	comment.User.Nickname = "@attacker"
+
+	var buf bytes.Buffer
+	csvWriter := csv.NewWriter(&buf)
+	csvWriter.Write(newCommentExportRecord(comment).csvRow())
+	csvWriter.Flush()
+
+	record, err := csv.NewReader(&buf).Read()
+	if err != nil {
+		t.Fatalf("CSVの読み込みに失敗しました: %v", err)
+	}
+	if got, want := record[4], `'=HYPERLINK("http://evil.example.com","click")`; got != want {
+		t.Errorf("content = %q, want %q", got, want)
+	}
+	if got, want := record[3], "'@attacker"; got != want {
+		t.Errorf("nickname = %q, want %q", got, want)
+	}
+	if got, want := record[0], "1"; got != want {
+		t.Errorf("id = %q, want %q", got, want)
+	}
+}
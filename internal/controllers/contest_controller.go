@@ -0,0 +1,255 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContestController コンテスト（公開プロジェクト）に関するコントローラー
+type ContestController struct {
+	projectService services.ProjectService
+	taskService    services.TaskService
+}
+
+// NewContestController ContestControllerを作成
+func NewContestController(projectService services.ProjectService, taskService services.TaskService) *ContestController {
+	return &ContestController{
+		projectService: projectService,
+		taskService:    taskService,
+	}
+}
+
+// Create 新しいコンテストを作成
+func (c *ContestController) Create(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// リクエストをバインド
+	var req ProjectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	project, err := c.projectService.CreateContest(ctx.Request.Context(), req.Title, req.Description, u.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "project", project)
+}
+
+// List コンテスト一覧を取得
+func (c *ContestController) List(ctx *gin.Context) {
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// 進行状況でフィルタリング（オプション）
+	phase := ctx.Query("phase")
+	switch phase {
+	case "", models.ContestPhaseOpen, models.ContestPhaseJudging, models.ContestPhaseFinished:
+	default:
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な進行状況です: "+phase)
+		return
+	}
+
+	projects, total, pages, err := c.projectService.ListContests(ctx.Request.Context(), page, limit, phase)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, projects)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "projects", items, total, page, limit, pages, nil)
+}
+
+// GetByID IDでコンテストを取得
+func (c *ContestController) GetByID(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	project, err := c.projectService.GetContest(ctx.Request.Context(), uint(id))
+	if err != nil {
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "project", project)
+}
+
+// Register コンテストに参加登録
+func (c *ContestController) Register(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	project, err := c.projectService.RegisterForContest(ctx.Request.Context(), uint(id), u.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "project", project)
+}
+
+// Gallery コンテストの応募作品一覧を取得
+func (c *ContestController) Gallery(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	works, total, pages, err := c.taskService.GetContestGallery(ctx.Request.Context(), uint(id), page, limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, works)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "works", items, total, page, limit, pages, nil)
+}
+
+// StartJudging 応募を締め切って審査の投票を開始
+func (c *ContestController) StartJudging(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// リクエストをバインド（締め切りは省略可能）
+	var req struct {
+		ClosesAt *time.Time `json:"closes_at"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	vote, err := c.projectService.StartJudging(ctx.Request.Context(), uint(id), u.ID, req.ClosesAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "vote", vote)
+}
+
+// Results コンテストの結果を取得
+func (c *ContestController) Results(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	results, err := c.projectService.GetContestResults(ctx.Request.Context(), uint(id))
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "まだ発表されていません") {
+			utils.RespondError(ctx, http.StatusConflict, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "results", results)
+}
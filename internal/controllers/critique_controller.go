@@ -0,0 +1,334 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CritiqueController タスクの講評に関するコントローラー
+type CritiqueController struct {
+	critiqueService services.CritiqueService
+}
+
+// NewCritiqueController CritiqueControllerを作成
+func NewCritiqueController(critiqueService services.CritiqueService) *CritiqueController {
+	return &CritiqueController{
+		critiqueService: critiqueService,
+	}
+}
+
+// RubricRequest 評価基準の設定リクエスト
+type RubricRequest struct {
+	Criteria []models.CritiqueCriterion `json:"criteria"` // name と description のみ使用（空の場合は評価基準を削除）
+}
+
+// CritiqueRequest 講評リクエスト
+type CritiqueRequest struct {
+	Scores  []models.CritiqueScore `json:"scores" binding:"required"` // 評価基準ごとの点数（1〜5）
+	Comment string                 `json:"comment"`
+}
+
+// GetRubric タスクの評価基準を取得
+func (c *CritiqueController) GetRubric(ctx *gin.Context) {
+	// タスクIDを解析
+	taskID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	criteria, err := c.critiqueService.GetRubric(ctx.Request.Context(), uint(taskID), u.ID)
+	if err != nil {
+		respondCritiqueError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "criteria", criteria)
+}
+
+// SetRubric タスクの評価基準を設定（プロジェクトのオーナーのみ）
+func (c *CritiqueController) SetRubric(ctx *gin.Context) {
+	// タスクIDを解析
+	taskID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req RubricRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	criteria, err := c.critiqueService.SetRubric(ctx.Request.Context(), uint(taskID), u.ID, req.Criteria)
+	if err != nil {
+		respondCritiqueError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "criteria", criteria)
+}
+
+// Submit タスクに提出された作品を講評（既に講評している場合は置き換える）
+func (c *CritiqueController) Submit(ctx *gin.Context) {
+	taskID, workID, ok := parseCritiqueParams(ctx)
+	if !ok {
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req CritiqueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	critique, err := c.critiqueService.Submit(ctx.Request.Context(), taskID, workID, u.ID, req.Scores, req.Comment)
+	if err != nil {
+		respondCritiqueError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "critique", critique)
+}
+
+// Delete 自分の講評を削除
+func (c *CritiqueController) Delete(ctx *gin.Context) {
+	taskID, workID, ok := parseCritiqueParams(ctx)
+	if !ok {
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.critiqueService.Delete(ctx.Request.Context(), taskID, workID, u.ID); err != nil {
+		respondCritiqueError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "message", "講評を削除しました")
+}
+
+// ListByWork タスクに提出された作品の講評一覧を取得
+func (c *CritiqueController) ListByWork(ctx *gin.Context) {
+	taskID, workID, ok := parseCritiqueParams(ctx)
+	if !ok {
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	critiques, err := c.critiqueService.ListByWork(ctx.Request.Context(), taskID, workID, u.ID)
+	if err != nil {
+		respondCritiqueError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "critiques", critiques)
+}
+
+// Summary タスクに提出された作品ごとの講評の平均点を取得
+func (c *CritiqueController) Summary(ctx *gin.Context) {
+	// タスクIDを解析
+	taskID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	summaries, err := c.critiqueService.Summary(ctx.Request.Context(), uint(taskID), u.ID)
+	if err != nil {
+		respondCritiqueError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "summaries", summaries)
+}
+
+// Export タスクに提出された作品ごとの講評と投票結果をCSVでエクスポート（プロジェクトのオーナーのみ）
+// 列は work_id, title, user_id, nickname, critiques_count の後に評価基準ごとの平均点・全体の平均点・投票ごとの得票数・講評のコメントが続く
+// 提出作品が多い場合に備え、少しずつ読み込みながらレスポンスに書き出す
+func (c *CritiqueController) Export(ctx *gin.Context) {
+	// タスクIDを解析
+	taskID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// 権限を確認してからヘッダーを書き出し、提出作品は少しずつ書き出す
+	csvWriter := csv.NewWriter(ctx.Writer)
+	started := false
+	var export *services.CritiqueExport
+	err = c.critiqueService.Export(ctx.Request.Context(), uint(taskID), u.ID, func(e *services.CritiqueExport) error {
+		started = true
+		export = e
+		ctx.Header("Content-Type", "text/csv; charset=utf-8")
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=task_%d_critiques.csv", e.Task.ID))
+		ctx.Status(http.StatusOK)
+
+		header := []string{"work_id", "title", "user_id", "nickname", "critiques_count"}
+		for _, criterion := range e.Criteria {
+			header = append(header, "criterion:"+criterion.Name)
+		}
+		header = append(header, "average")
+		for _, vote := range e.Votes {
+			header = append(header, "vote:"+vote.Title)
+		}
+		header = append(header, "comments")
+		csvWriter.Write(header)
+		csvWriter.Flush()
+		ctx.Writer.Flush()
+		return csvWriter.Error()
+	}, func(rows []services.CritiqueExportRow) error {
+		for _, row := range rows {
+			csvWriter.Write(critiqueExportCSVRow(export, row))
+		}
+		csvWriter.Flush()
+		ctx.Writer.Flush()
+		return csvWriter.Error()
+	})
+	if err != nil && !started {
+		respondCritiqueError(ctx, err)
+		return
+	}
+	if err != nil {
+		// 書き出し開始後のエラーはステータスを変更できないため中断のみ
+		ctx.Error(err)
+	}
+}
+
+// critiqueExportCSVRow 提出作品の講評と投票結果をCSVの行に変換（講評がない場合は平均点を空にする）
+func critiqueExportCSVRow(export *services.CritiqueExport, row services.CritiqueExportRow) []string {
+	record := []string{
+		strconv.FormatUint(uint64(row.Work.ID), 10),
+		row.Work.Title,
+		strconv.FormatUint(uint64(row.Work.UserID), 10),
+		row.Work.User.Nickname,
+		strconv.FormatInt(row.Summary.CritiquesCount, 10),
+	}
+
+	averages := make(map[uint]float64, len(row.Summary.Criteria))
+	for _, criterion := range row.Summary.Criteria {
+		averages[criterion.CriterionID] = criterion.Average
+	}
+	for _, criterion := range export.Criteria {
+		average, ok := averages[criterion.ID]
+		if !ok {
+			record = append(record, "")
+			continue
+		}
+		record = append(record, strconv.FormatFloat(average, 'f', 2, 64))
+	}
+	if row.Summary.CritiquesCount > 0 {
+		record = append(record, strconv.FormatFloat(row.Summary.Average, 'f', 2, 64))
+	} else {
+		record = append(record, "")
+	}
+
+	for _, vote := range export.Votes {
+		record = append(record, strconv.FormatInt(row.Votes[vote.ID], 10))
+	}
+
+	comments := make([]string, 0, len(row.Critiques))
+	for _, critique := range row.Critiques {
+		if critique.Comment != "" {
+			comments = append(comments, critique.User.Nickname+": "+critique.Comment)
+		}
+	}
+	return utils.CSVSafeRecord(append(record, strings.Join(comments, "\n")))
+}
+
+// parseCritiqueParams パスのタスクIDと作品IDを解析（無効な場合はエラーレスポンスを返してfalse）
+func parseCritiqueParams(ctx *gin.Context) (uint, uint, bool) {
+	taskID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return 0, 0, false
+	}
+	workID, err := strconv.ParseUint(ctx.Param("workID"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な作品IDです")
+		return 0, 0, false
+	}
+	return uint(taskID), uint(workID), true
+}
+
+// respondCritiqueError 講評のエラーを対応するステータスで返す
+func respondCritiqueError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrCritiquesSubmitted):
+		utils.RespondError(ctx, http.StatusConflict, err.Error())
+	case strings.Contains(err.Error(), "権限がありません"):
+		utils.RespondError(ctx, http.StatusForbidden, err.Error())
+	case strings.Contains(err.Error(), "見つかりません"):
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+	case strings.Contains(err.Error(), "失敗しました"):
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+	default:
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+	}
+}
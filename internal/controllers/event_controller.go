@@ -0,0 +1,291 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventController イベントに関するコントローラー
+type EventController struct {
+	eventService services.EventService
+}
+
+// NewEventController EventControllerを作成
+func NewEventController(eventService services.EventService) *EventController {
+	return &EventController{
+		eventService: eventService,
+	}
+}
+
+// List イベント一覧を取得
+func (c *EventController) List(ctx *gin.Context) {
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	events, total, pages, err := c.eventService.List(ctx.Request.Context(), ctx.Query("period"), page, limit)
+	if err != nil {
+		respondEventError(ctx, err)
+		return
+	}
+
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, events)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "events", items, total, page, limit, pages, nil)
+}
+
+// GetByID IDまたは識別子でイベントを取得（/events/1 または /events/hackathon-2026）
+func (c *EventController) GetByID(ctx *gin.Context) {
+	event, ok := c.findEvent(ctx)
+	if !ok {
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "event", event)
+}
+
+// Create 新しいイベントを作成
+func (c *EventController) Create(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req services.EventInput
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	event, err := c.eventService.Create(ctx.Request.Context(), u.ID, req)
+	if err != nil {
+		respondEventError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "event", event)
+}
+
+// Update イベントを更新（主催者のみ）
+func (c *EventController) Update(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req services.EventInput
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	event, err := c.eventService.Update(ctx.Request.Context(), uint(id), u.ID, req)
+	if err != nil {
+		respondEventError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "event", event)
+}
+
+// Delete イベントを削除（主催者のみ）
+func (c *EventController) Delete(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.eventService.Delete(ctx.Request.Context(), uint(id), u.ID); err != nil {
+		respondEventError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListWorks イベントで制作された作品一覧を取得（IDまたは識別子で指定）
+func (c *EventController) ListWorks(ctx *gin.Context) {
+	event, ok := c.findEvent(ctx)
+	if !ok {
+		return
+	}
+
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	works, total, pages, err := c.eventService.ListWorks(ctx.Request.Context(), event.ID, page, limit)
+	if err != nil {
+		respondEventError(ctx, err)
+		return
+	}
+
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, works)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "works", items, total, page, limit, pages, nil)
+}
+
+// Register イベントに参加登録
+func (c *EventController) Register(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	event, err := c.eventService.Register(ctx.Request.Context(), uint(id), u.ID)
+	if err != nil {
+		respondEventError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "event", event)
+}
+
+// Unregister イベントの参加登録を取り消す
+func (c *EventController) Unregister(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.eventService.Unregister(ctx.Request.Context(), uint(id), u.ID); err != nil {
+		respondEventError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListAttendees イベントの参加者一覧を取得（主催者のみ）
+func (c *EventController) ListAttendees(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	attendees, err := c.eventService.ListAttendees(ctx.Request.Context(), uint(id), u.ID)
+	if err != nil {
+		respondEventError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "attendees", attendees)
+}
+
+// findEvent パスのIDまたは識別子でイベントを取得（見つからない場合はエラーレスポンスを返してfalse）
+func (c *EventController) findEvent(ctx *gin.Context) (*models.Event, bool) {
+	var event *models.Event
+	var err error
+	if id, parseErr := strconv.ParseUint(ctx.Param("id"), 10, 32); parseErr == nil {
+		event, err = c.eventService.GetByID(ctx.Request.Context(), uint(id))
+	} else {
+		event, err = c.eventService.GetBySlug(ctx.Request.Context(), ctx.Param("id"))
+	}
+	if err != nil {
+		respondEventError(ctx, err)
+		return nil, false
+	}
+	return event, true
+}
+
+// respondEventError イベントのエラーを対応するステータスで返す
+func respondEventError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrAlreadyRegistered), errors.Is(err, repository.ErrEventFull), errors.Is(err, repository.ErrEventSlugTaken):
+		utils.RespondError(ctx, http.StatusConflict, err.Error())
+	case strings.Contains(err.Error(), "権限がありません"):
+		utils.RespondError(ctx, http.StatusForbidden, err.Error())
+	case strings.Contains(err.Error(), "イベントが見つかりません"):
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+	case strings.Contains(err.Error(), "失敗しました"):
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+	default:
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// applyFields fieldsクエリパラメータ（カンマ区切り）で指定された項目のみを一覧の各要素に残す
+// 例: ?fields=id,title,thumbnail_url
+// 指定がない場合はitemsをそのまま返す。idは常に含める。
+func applyFields(ctx *gin.Context, items interface{}) (interface{}, error) {
+	param := strings.TrimSpace(ctx.Query("fields"))
+	if param == "" {
+		return items, nil
+	}
+
+	// 要素の型のJSONの項目名を指定できる項目とする
+	allowed := jsonFieldNames(reflect.TypeOf(items).Elem())
+	fields := map[string]bool{"id": true}
+	for _, field := range strings.Split(param, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !allowed[field] {
+			return nil, fmt.Errorf("無効なフィールドです: %s", field)
+		}
+		fields[field] = true
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var rows []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, err
+	}
+
+	selected := make([]map[string]json.RawMessage, 0, len(rows))
+	for _, row := range rows {
+		item := make(map[string]json.RawMessage, len(fields))
+		for name, value := range row {
+			if fields[name] {
+				item[name] = value
+			}
+		}
+		selected = append(selected, item)
+	}
+	return selected, nil
+}
+
+// jsonFieldNames 構造体のJSONの項目名の一覧
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	names := map[string]bool{}
+	if t.Kind() != reflect.Struct {
+		return names
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[name] = true
+	}
+	return names
+}
@@ -2,33 +2,43 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
 	"github.com/gin-gonic/gin"
 )
 
 // HealthController ヘルスチェックに関するコントローラー
 type HealthController struct {
-	startTime time.Time
+	healthService services.HealthService
+	httpClients   services.HTTPClientFactory
+	startTime     time.Time
 }
 
 // NewHealthController HealthControllerを作成
-func NewHealthController() *HealthController {
+func NewHealthController(healthService services.HealthService, httpClients services.HTTPClientFactory) *HealthController {
 	return &HealthController{
-		startTime: time.Now(),
+		healthService: healthService,
+		httpClients:   httpClients,
+		startTime:     time.Now(),
 	}
 }
 
 // HealthStatus ヘルスステータスレスポンス
 type HealthStatus struct {
-	Status      string `json:"status"`
-	Uptime      string `json:"uptime"`
-	Timestamp   string `json:"timestamp"`
-	Version     string `json:"version"`
-	Description string `json:"description"`
+	Status       string                      `json:"status"`
+	Uptime       string                      `json:"uptime"`
+	Timestamp    string                      `json:"timestamp"`
+	Version      string                      `json:"version"`
+	Description  string                      `json:"description"`
+	Dependencies []services.DependencyStatus `json:"dependencies,omitempty"` // deep=trueの場合のみ
 }
 
 // Check ヘルスチェック
+// deep=trueの場合は依存サービス（DB・ストレージ・Lambdaなど）への疎通も確認し、異常があれば503を返す
 func (c *HealthController) Check(ctx *gin.Context) {
 	status := "ok"
 	uptime := time.Since(c.startTime).String()
@@ -42,5 +52,20 @@ func (c *HealthController) Check(ctx *gin.Context) {
 		Description: "Serendicode Sub",
 	}
 
-	ctx.JSON(http.StatusOK, healthStatus)
+	httpStatus := http.StatusOK
+	if deep, _ := strconv.ParseBool(ctx.Query("deep")); deep {
+		dependencies, healthy := c.healthService.CheckDependencies(ctx.Request.Context())
+		healthStatus.Dependencies = dependencies
+		if !healthy {
+			healthStatus.Status = "degraded"
+			httpStatus = http.StatusServiceUnavailable
+		}
+	}
+
+	utils.Respond(ctx, httpStatus, "", healthStatus)
+}
+
+// HTTPClients 外部サービスとの通信の統計を連携先ごとに取得（管理者用）
+func (c *HealthController) HTTPClients(ctx *gin.Context) {
+	utils.Respond(ctx, http.StatusOK, "http_clients", c.httpClients.Stats())
 }
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HomeController トップページに関するコントローラー
+type HomeController struct {
+	homeService services.HomeService
+	cacheTTL    time.Duration
+}
+
+// NewHomeController HomeControllerを作成
+func NewHomeController(homeService services.HomeService, cacheTTL time.Duration) *HomeController {
+	return &HomeController{
+		homeService: homeService,
+		cacheTTL:    cacheTTL,
+	}
+}
+
+// Get トップページのセクション（ピックアップ・今週の人気・新着・コンテスト・タグ）をまとめて取得
+func (c *HomeController) Get(ctx *gin.Context) {
+	sections, err := c.homeService.Get(ctx.Request.Context())
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 誰が取得しても同じ内容のため、ブラウザやCDNでもキャッシュできるようにする
+	if seconds := int(c.cacheTTL.Seconds()); seconds > 0 {
+		ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", seconds))
+	}
+	utils.Respond(ctx, http.StatusOK, "sections", sections)
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImageController アップロードされた画像に関するコントローラー
+type ImageController struct {
+	imageService services.ImageService
+}
+
+// NewImageController ImageControllerを作成
+func NewImageController(imageService services.ImageService) *ImageController {
+	return &ImageController{
+		imageService: imageService,
+	}
+}
+
+// GetByID 画像と派生画像を取得
+func (c *ImageController) GetByID(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	image, err := c.imageService.GetByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		respondImageError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "image", image)
+}
+
+// respondImageError 画像のアップロードのエラーを対応するステータスで返す
+func respondImageError(ctx *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case errors.Is(err, services.ErrCircuitOpen):
+		utils.RespondError(ctx, http.StatusServiceUnavailable, message)
+	case strings.Contains(message, "見つかりません"):
+		utils.RespondError(ctx, http.StatusNotFound, message)
+	case strings.Contains(message, "上限"):
+		utils.RespondError(ctx, http.StatusRequestEntityTooLarge, message)
+	case strings.Contains(message, "対応していない"):
+		utils.RespondError(ctx, http.StatusUnsupportedMediaType, message)
+	case strings.Contains(message, "失敗しました"):
+		utils.RespondError(ctx, http.StatusInternalServerError, message)
+	default:
+		utils.RespondError(ctx, http.StatusBadRequest, message)
+	}
+}
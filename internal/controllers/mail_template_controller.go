@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MailTemplateController メールのテンプレートに関するコントローラー（管理者用）
+type MailTemplateController struct {
+	mailTemplateService services.MailTemplateService
+}
+
+// NewMailTemplateController MailTemplateControllerを作成
+func NewMailTemplateController(mailTemplateService services.MailTemplateService) *MailTemplateController {
+	return &MailTemplateController{
+		mailTemplateService: mailTemplateService,
+	}
+}
+
+// List メールのテンプレートの一覧を取得
+func (c *MailTemplateController) List(ctx *gin.Context) {
+	utils.Respond(ctx, http.StatusOK, "templates", c.mailTemplateService.List())
+}
+
+// Preview サンプルの値で作成したメールを取得（format=htmlの場合はHTMLをそのまま返す）
+func (c *MailTemplateController) Preview(ctx *gin.Context) {
+	message, err := c.mailTemplateService.Preview(ctx.Param("name"), ctx.Query("lang"))
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	switch ctx.Query("format") {
+	case "html":
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(message.HTML))
+	case "text":
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(message.Text))
+	default:
+		utils.Respond(ctx, http.StatusOK, "message", message)
+	}
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceController メンテナンスモードに関するコントローラー（管理者用）
+type MaintenanceController struct {
+	maintenanceService services.MaintenanceService
+}
+
+// NewMaintenanceController MaintenanceControllerを作成
+func NewMaintenanceController(maintenanceService services.MaintenanceService) *MaintenanceController {
+	return &MaintenanceController{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// Get メンテナンスモードの状態を取得
+func (c *MaintenanceController) Get(ctx *gin.Context) {
+	utils.Respond(ctx, http.StatusOK, "maintenance", c.maintenanceService.Status())
+}
+
+// Update メンテナンスモードを切り替え
+func (c *MaintenanceController) Update(ctx *gin.Context) {
+	// リクエストをバインド
+	var req struct {
+		Enabled *bool      `json:"enabled" binding:"required"`
+		Message string     `json:"message"`
+		EndsAt  *time.Time `json:"ends_at"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var status services.MaintenanceStatus
+	if *req.Enabled {
+		status = c.maintenanceService.Enable(req.Message, req.EndsAt)
+	} else {
+		status = c.maintenanceService.Disable()
+	}
+
+	utils.Respond(ctx, http.StatusOK, "maintenance", status)
+}
@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MessageController ダイレクトメッセージに関するコントローラー
+type MessageController struct {
+	messageService services.MessageService
+}
+
+// NewMessageController MessageControllerを作成
+func NewMessageController(messageService services.MessageService) *MessageController {
+	return &MessageController{
+		messageService: messageService,
+	}
+}
+
+// MessageRequest メッセージ送信リクエスト
+type MessageRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// ListConversations 会話一覧を取得
+func (c *MessageController) ListConversations(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// クエリパラメータを取得
+	pageStr := ctx.DefaultQuery("page", "1")
+	limitStr := ctx.DefaultQuery("limit", "20")
+
+	// 数値パラメータを解析
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// 会話一覧を取得
+	conversations, total, pages, err := c.messageService.ListConversations(ctx.Request.Context(), u.ID, page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "conversations", conversations, total, page, limit, pages, nil)
+}
+
+// StartConversation 会話を開始して最初のメッセージを送信
+func (c *MessageController) StartConversation(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// リクエストをバインド
+	var req struct {
+		RecipientID uint   `json:"recipient_id" binding:"required"`
+		Content     string `json:"content" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 会話を開始
+	conversation, message, err := c.messageService.StartConversation(ctx.Request.Context(), u.ID, req.RecipientID, req.Content)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "", gin.H{
+		"conversation": conversation,
+		"message":      message,
+	})
+}
+
+// ListMessages 会話のメッセージ一覧を取得
+func (c *MessageController) ListMessages(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// クエリパラメータを取得
+	pageStr := ctx.DefaultQuery("page", "1")
+	limitStr := ctx.DefaultQuery("limit", "50")
+
+	// 数値パラメータを解析
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	// メッセージ一覧を取得
+	messages, total, pages, err := c.messageService.ListMessages(ctx.Request.Context(), uint(id), u.ID, page, limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "messages", messages, total, page, limit, pages, nil)
+}
+
+// SendMessage 会話にメッセージを送信
+func (c *MessageController) SendMessage(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// リクエストをバインド
+	var req MessageRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// メッセージを送信
+	message, err := c.messageService.SendMessage(ctx.Request.Context(), uint(id), u.ID, req.Content)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "message", message)
+}
+
+// UnreadCount 未読メッセージ数を取得
+func (c *MessageController) UnreadCount(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	count, err := c.messageService.UnreadCount(ctx.Request.Context(), u.ID)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "unread_count", count)
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationController 通知に関するコントローラー
+type NotificationController struct {
+	notificationService services.NotificationService
+}
+
+// NewNotificationController NotificationControllerを作成
+func NewNotificationController(notificationService services.NotificationService) *NotificationController {
+	return &NotificationController{
+		notificationService: notificationService,
+	}
+}
+
+// List 自分の通知一覧を取得
+func (c *NotificationController) List(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// クエリパラメータを取得
+	pageStr := ctx.DefaultQuery("page", "1")
+	limitStr := ctx.DefaultQuery("limit", "20")
+	unreadOnly := ctx.Query("unread") == "true"
+
+	// 数値パラメータを解析
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// 通知一覧を取得
+	notifications, total, pages, err := c.notificationService.List(ctx.Request.Context(), u.ID, page, limit, unreadOnly)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "notifications", notifications, total, page, limit, pages, nil)
+}
+
+// UnreadCount 未読の通知数を取得
+func (c *NotificationController) UnreadCount(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	count, err := c.notificationService.CountUnread(ctx.Request.Context(), u.ID)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "unread_count", count)
+}
+
+// MarkAsRead 通知を既読にする
+func (c *NotificationController) MarkAsRead(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.notificationService.MarkAsRead(ctx.Request.Context(), uint(id), u.ID); err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// MarkAllAsRead すべての通知を既読にする
+func (c *NotificationController) MarkAllAsRead(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.notificationService.MarkAllAsRead(ctx.Request.Context(), u.ID); err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GitHubの認可のstateを保存するCookie
+const githubStateCookie = "github_oauth_state"
+
+// OAuthController 外部サービス（GitHub）のアカウントでのログインと連携に関するコントローラー
+type OAuthController struct {
+	oauthService services.OAuthService
+	authService  services.AuthService
+	secureCookie bool // CookieをHTTPSでのみ送る（本番環境）
+}
+
+// NewOAuthController OAuthControllerを作成
+func NewOAuthController(oauthService services.OAuthService, authService services.AuthService, secureCookie bool) *OAuthController {
+	return &OAuthController{
+		oauthService: oauthService,
+		authService:  authService,
+		secureCookie: secureCookie,
+	}
+}
+
+// GithubLoginRequest GitHubでのログインリクエスト
+type GithubLoginRequest struct {
+	Code       string `json:"code" binding:"required"`  // GitHubの認可後にリダイレクト先に渡されるcode
+	State      string `json:"state" binding:"required"` // GitHubの認可後にリダイレクト先に渡されるstate
+	InviteCode string `json:"invite_code"`              // 招待制で新しく登録する場合のみ必要
+}
+
+// GithubAuthorize GitHubの認可画面のURLを取得
+// stateは署名してCookieに保存し、認可後に戻ってきたstateと一致する場合のみログイン・連携する（ログインCSRF対策）
+func (c *OAuthController) GithubAuthorize(ctx *gin.Context) {
+	authorizeURL, state, err := c.oauthService.GithubAuthorizeURL()
+	if err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(githubStateCookie, c.oauthService.SignGithubState(state),
+		int(services.GithubStateExpiry.Seconds()), "/api", "", c.secureCookie, true)
+	utils.Respond(ctx, http.StatusOK, "", gin.H{
+		"url":   authorizeURL,
+		"state": state,
+	})
+}
+
+// GithubLogin GitHubの認可コードでログイン（アカウントがなければ作成）
+func (c *OAuthController) GithubLogin(ctx *gin.Context) {
+	var req GithubLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !c.verifyState(ctx, req.State) {
+		return
+	}
+
+	user, token, err := c.oauthService.LoginWithGithub(ctx.Request.Context(), req.Code, req.InviteCode)
+	if err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+
+	respondAuth(ctx, c.authService, http.StatusOK, user, token)
+}
+
+// LinkGithub ログイン中のユーザーにGitHubのアカウントを連携
+func (c *OAuthController) LinkGithub(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req struct {
+		Code  string `json:"code" binding:"required"`
+		State string `json:"state" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !c.verifyState(ctx, req.State) {
+		return
+	}
+
+	identity, err := c.oauthService.LinkGithub(ctx.Request.Context(), u.ID, req.Code)
+	if err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "provider", identity)
+}
+
+// ListProviders 連携しているアカウントの一覧を取得
+func (c *OAuthController) ListProviders(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	identities, err := c.oauthService.ListProviders(ctx.Request.Context(), u.ID)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "providers", identities)
+}
+
+// Unlink アカウントの連携を解除
+func (c *OAuthController) Unlink(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.oauthService.Unlink(ctx.Request.Context(), u.ID, ctx.Param("provider")); err != nil {
+		respondOAuthError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// verifyState Cookieに保存したstateと一致するか確認し、Cookieを削除（一致しない場合はエラーレスポンスを返してfalse）
+func (c *OAuthController) verifyState(ctx *gin.Context, state string) bool {
+	signed, _ := ctx.Cookie(githubStateCookie)
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(githubStateCookie, "", -1, "/api", "", c.secureCookie, true)
+
+	if err := c.oauthService.VerifyGithubState(signed, state); err != nil {
+		utils.RespondError(ctx, http.StatusForbidden, err.Error())
+		return false
+	}
+	return true
+}
+
+// respondOAuthError ログイン・連携のエラーをステータスコードに対応付けて返す
+func respondOAuthError(ctx *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case errors.Is(err, services.ErrGithubLoginDisabled):
+		utils.RespondError(ctx, http.StatusNotFound, message)
+	case strings.Contains(message, "認証に失敗しました"):
+		utils.RespondError(ctx, http.StatusUnauthorized, message)
+	case strings.Contains(message, "通信に失敗しました"):
+		utils.RespondError(ctx, http.StatusBadGateway, message)
+	case strings.Contains(message, "受け付けていません"), strings.Contains(message, "招待コード"):
+		utils.RespondError(ctx, http.StatusForbidden, message)
+	case strings.Contains(message, "既に"), strings.Contains(message, "解除できません"):
+		utils.RespondError(ctx, http.StatusConflict, message)
+	case strings.Contains(message, "見つかりません"):
+		utils.RespondError(ctx, http.StatusNotFound, message)
+	case strings.Contains(message, "失敗しました"):
+		utils.RespondError(ctx, http.StatusInternalServerError, message)
+	default:
+		utils.RespondError(ctx, http.StatusBadRequest, message)
+	}
+}
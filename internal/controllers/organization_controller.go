@@ -0,0 +1,421 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHeader プロジェクトの作成・一覧で組織を指定するヘッダー
+const OrganizationHeader = "X-Org-ID"
+
+// OrganizationController 学校・クラブなどの組織に関するコントローラー
+type OrganizationController struct {
+	organizationService services.OrganizationService
+	projectService      services.ProjectService
+}
+
+// NewOrganizationController OrganizationControllerを作成
+func NewOrganizationController(organizationService services.OrganizationService, projectService services.ProjectService) *OrganizationController {
+	return &OrganizationController{
+		organizationService: organizationService,
+		projectService:      projectService,
+	}
+}
+
+// OrganizationMemberRequest 組織のメンバーの追加・役割の変更リクエスト
+type OrganizationMemberRequest struct {
+	UserID uint   `json:"user_id"` // 追加時のみ
+	Role   string `json:"role"`    // owner, admin, member（追加時に省略した場合は member）
+}
+
+// OrganizationLimitsRequest 組織の上限の変更リクエスト（管理者用）
+type OrganizationLimitsRequest struct {
+	MaxProjects int `json:"max_projects"` // 0の場合は無制限
+	MaxMembers  int `json:"max_members"`  // 0の場合は無制限
+}
+
+// List 自分が所属する組織一覧を取得
+func (c *OrganizationController) List(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	organizations, err := c.organizationService.ListMine(ctx.Request.Context(), u.ID)
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "organizations", organizations)
+}
+
+// Create 新しい組織を作成（作成者がオーナーになる）
+func (c *OrganizationController) Create(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req services.OrganizationInput
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	organization, err := c.organizationService.Create(ctx.Request.Context(), u.ID, req)
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "organization", organization)
+}
+
+// GetByID IDで組織を取得（メンバーのみ）
+func (c *OrganizationController) GetByID(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	organization, err := c.organizationService.GetByID(ctx.Request.Context(), uint(id), u.ID)
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "organization", organization)
+}
+
+// Update 組織を更新（オーナー・管理者のみ）
+func (c *OrganizationController) Update(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req services.OrganizationInput
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	organization, err := c.organizationService.Update(ctx.Request.Context(), uint(id), u.ID, req)
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "organization", organization)
+}
+
+// Delete 組織を削除（オーナーのみ）
+func (c *OrganizationController) Delete(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.organizationService.Delete(ctx.Request.Context(), uint(id), u.ID); err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListMembers 組織のメンバー一覧を取得（メンバーのみ）
+func (c *OrganizationController) ListMembers(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	members, err := c.organizationService.ListMembers(ctx.Request.Context(), uint(id), u.ID)
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "members", members)
+}
+
+// AddMember 組織にメンバーを追加（オーナー・管理者のみ）
+func (c *OrganizationController) AddMember(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req OrganizationMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.UserID == 0 {
+		utils.RespondError(ctx, http.StatusBadRequest, "user_idは必須です")
+		return
+	}
+
+	members, err := c.organizationService.AddMember(ctx.Request.Context(), uint(id), u.ID, req.UserID, req.Role)
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "members", members)
+}
+
+// UpdateMemberRole 組織のメンバーの役割を変更（オーナーのみ）
+func (c *OrganizationController) UpdateMemberRole(ctx *gin.Context) {
+	id, memberID, ok := parseOrganizationMemberParams(ctx)
+	if !ok {
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req OrganizationMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	members, err := c.organizationService.UpdateRole(ctx.Request.Context(), id, u.ID, memberID, req.Role)
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "members", members)
+}
+
+// RemoveMember 組織からメンバーを削除（オーナー・管理者のみ、自分自身の場合は組織から抜ける）
+func (c *OrganizationController) RemoveMember(ctx *gin.Context) {
+	id, memberID, ok := parseOrganizationMemberParams(ctx)
+	if !ok {
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.organizationService.RemoveMember(ctx.Request.Context(), id, u.ID, memberID); err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListProjects 組織のプロジェクト一覧を取得（メンバーのみ、GET /projects に X-Org-ID を指定した場合と同じ）
+func (c *OrganizationController) ListProjects(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	listOrganizationProjects(ctx, c.projectService, uint(id))
+}
+
+// CreateProject 組織のプロジェクトを作成（メンバーのみ、POST /projects に X-Org-ID を指定した場合と同じ）
+func (c *OrganizationController) CreateProject(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+	organizationID := uint(id)
+
+	createProject(ctx, c.projectService, &organizationID)
+}
+
+// SetLimits 組織のプロジェクト数・メンバー数の上限を変更（管理者用）
+func (c *OrganizationController) SetLimits(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	var req OrganizationLimitsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	organization, err := c.organizationService.SetLimits(ctx.Request.Context(), uint(id), req.MaxProjects, req.MaxMembers)
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "organization", organization)
+}
+
+// listOrganizationProjects 組織のプロジェクト一覧をページ単位で返す
+func listOrganizationProjects(ctx *gin.Context, projectService services.ProjectService, organizationID uint) {
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	projects, total, pages, err := projectService.ListByOrganization(ctx.Request.Context(), organizationID, u.ID, page, limit, ctx.Query("search"))
+	if err != nil {
+		respondOrganizationError(ctx, err)
+		return
+	}
+
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, projects)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "projects", items, total, page, limit, pages, nil)
+}
+
+// organizationIDFromHeader X-Org-ID ヘッダーで指定された組織IDを取得
+// 指定がない場合はnil、無効な場合はエラーレスポンスを返してfalse
+func organizationIDFromHeader(ctx *gin.Context) (*uint, bool) {
+	value := strings.TrimSpace(ctx.GetHeader(OrganizationHeader))
+	if value == "" {
+		return nil, true
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な組織IDです")
+		return nil, false
+	}
+	organizationID := uint(id)
+	return &organizationID, true
+}
+
+// parseOrganizationMemberParams パスの組織IDとメンバーのユーザーIDを解析（無効な場合はエラーレスポンスを返してfalse）
+func parseOrganizationMemberParams(ctx *gin.Context) (uint, uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return 0, 0, false
+	}
+	memberID, err := strconv.ParseUint(ctx.Param("memberID"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なメンバーIDです")
+		return 0, 0, false
+	}
+	return uint(id), uint(memberID), true
+}
+
+// respondOrganizationError 組織のエラーを対応するステータスで返す
+func respondOrganizationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrAlreadyOrganizationMember), errors.Is(err, repository.ErrOrganizationFull),
+		errors.Is(err, repository.ErrLastOrganizationOwner), errors.Is(err, repository.ErrOrganizationHasProjects),
+		strings.Contains(err.Error(), "上限"):
+		utils.RespondError(ctx, http.StatusConflict, err.Error())
+	case strings.Contains(err.Error(), "権限がありません"):
+		utils.RespondError(ctx, http.StatusForbidden, err.Error())
+	case strings.Contains(err.Error(), "見つかりません"):
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+	case strings.Contains(err.Error(), "失敗しました"):
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+	default:
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaginationLinks 前後のページのURL
+type PaginationLinks struct {
+	First string `json:"first"`
+	Last  string `json:"last"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+}
+
+// respondPaginated ページネーション付きのレスポンスを返し、Linkヘッダー（RFC 5988）を設定
+// extraはページネーション情報と一緒にmetaに含める
+// 以前のレスポンスとの互換のため pages も残している（total_pages と同じ値）
+func respondPaginated(ctx *gin.Context, key string, items interface{}, total int64, page, perPage, totalPages int, extra gin.H) {
+	lastPage := totalPages
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	links := PaginationLinks{
+		First: pageURL(ctx, 1, perPage),
+		Last:  pageURL(ctx, lastPage, perPage),
+	}
+	if page < lastPage {
+		links.Next = pageURL(ctx, page+1, perPage)
+	}
+	if page > 1 {
+		// 範囲外のページを指定された場合は最後のページを前のページとする
+		prevPage := page - 1
+		if prevPage > lastPage {
+			prevPage = lastPage
+		}
+		links.Prev = pageURL(ctx, prevPage, perPage)
+	}
+
+	header := []string{}
+	for _, link := range []struct{ rel, url string }{
+		{"next", links.Next}, {"prev", links.Prev}, {"first", links.First}, {"last", links.Last},
+	} {
+		if link.url != "" {
+			header = append(header, fmt.Sprintf(`<%s>; rel="%s"`, link.url, link.rel))
+		}
+	}
+	// v1の非推奨通知のLinkヘッダーを上書きしないように追加する
+	ctx.Writer.Header().Add("Link", strings.Join(header, ", "))
+	ctx.Header("X-Total-Count", strconv.FormatInt(total, 10))
+
+	meta := gin.H{
+		"total":       total,
+		"page":        page,
+		"per_page":    perPage,
+		"total_pages": totalPages,
+		"pages":       totalPages,
+		"links":       links,
+	}
+	// v2はカーソルで前後のページを指定する
+	if utils.IsAPIv2(ctx) {
+		if page < lastPage {
+			meta["next_cursor"] = utils.EncodeCursor(page+1, perPage)
+		}
+		if page > 1 {
+			meta["prev_cursor"] = utils.EncodeCursor(page-1, perPage)
+		}
+	}
+	for k, v := range extra {
+		meta[k] = v
+	}
+	utils.RespondWithMeta(ctx, http.StatusOK, key, items, meta)
+}
+
+// pageURL 現在のリクエストのクエリを維持したまま、ページ番号と件数を差し替えたURLを作成
+func pageURL(ctx *gin.Context, page, perPage int) string {
+	query := ctx.Request.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("limit", strconv.Itoa(perPage))
+	return ctx.Request.URL.Path + "?" + query.Encode()
+}
@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PersonalTokenController 個人用アクセストークンに関するコントローラー
+type PersonalTokenController struct {
+	personalTokenService services.PersonalTokenService
+}
+
+// NewPersonalTokenController PersonalTokenControllerを作成
+func NewPersonalTokenController(personalTokenService services.PersonalTokenService) *PersonalTokenController {
+	return &PersonalTokenController{
+		personalTokenService: personalTokenService,
+	}
+}
+
+// PersonalTokenRequest 個人用アクセストークンの発行・更新リクエスト
+type PersonalTokenRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	Scopes        []string `json:"scopes" binding:"required"` // read, works:write
+	ExpiresInDays int      `json:"expires_in_days"`           // 発行時のみ、省略した場合は無期限
+}
+
+// CreatedPersonalTokenResponse 個人用アクセストークンの発行レスポンス
+type CreatedPersonalTokenResponse struct {
+	PersonalToken *models.PersonalToken `json:"personal_token"`
+	Token         string                `json:"token"` // 発行時にのみ返す
+}
+
+// List 自分のトークン一覧を取得
+func (c *PersonalTokenController) List(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	tokens, err := c.personalTokenService.List(ctx.Request.Context(), u.ID)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "personal_tokens", tokens)
+}
+
+// Create トークンを発行
+func (c *PersonalTokenController) Create(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// リクエストをバインド
+	var req PersonalTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, tokenString, err := c.personalTokenService.Create(ctx.Request.Context(), u.ID, req.Name, req.Scopes, req.ExpiresInDays)
+	if err != nil {
+		if strings.Contains(err.Error(), "失敗しました") {
+			utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "", CreatedPersonalTokenResponse{
+		PersonalToken: token,
+		Token:         tokenString,
+	})
+}
+
+// Update トークンの名前・スコープを変更
+func (c *PersonalTokenController) Update(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// リクエストをバインド
+	var req PersonalTokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, err := c.personalTokenService.Update(ctx.Request.Context(), uint(id), u.ID, req.Name, req.Scopes)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "見つかりません"):
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		case strings.Contains(err.Error(), "失敗しました"):
+			utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		default:
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "personal_token", token)
+}
+
+// Delete トークンを削除
+func (c *PersonalTokenController) Delete(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.personalTokenService.Delete(ctx.Request.Context(), uint(id), u.ID); err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// playerContentSecurityPolicy プレイヤーページのCSP
+// 作品のJSはこのサーバーのオリジンで実行されるため、サンドボックス化して別オリジン扱いにする
+// （別タブで作品ページを開けるよう、ポップアップはサンドボックスの外で開く）
+const playerContentSecurityPolicy = "sandbox allow-scripts allow-pointer-lock allow-popups allow-popups-to-escape-sandbox"
+
+// PlayerController 作品のプレイヤーページに関するコントローラー
+type PlayerController struct {
+	playerService services.PlayerService
+}
+
+// NewPlayerController PlayerControllerを作成
+func NewPlayerController(playerService services.PlayerService) *PlayerController {
+	return &PlayerController{
+		playerService: playerService,
+	}
+}
+
+// Show 作品のプレイヤーページを取得（再生・一時停止・最初から再生の操作ができる単体のHTML）
+func (c *PlayerController) Show(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	body, err := c.playerService.Render(ctx.Request.Context(), uint(id))
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Header("Content-Security-Policy", playerContentSecurityPolicy)
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", body)
+}
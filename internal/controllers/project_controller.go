@@ -1,39 +1,60 @@
 package controllers
 
 import (
+	"encoding/csv"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
 // ProjectController プロジェクトに関するコントローラー
 type ProjectController struct {
 	projectService services.ProjectService
+	rosterService  services.RosterService
 }
 
 // NewProjectController ProjectControllerを作成
-func NewProjectController(projectService services.ProjectService) *ProjectController {
+func NewProjectController(projectService services.ProjectService, rosterService services.RosterService) *ProjectController {
 	return &ProjectController{
 		projectService: projectService,
+		rosterService:  rosterService,
 	}
 }
 
+// 取り込める名簿ファイルの最大サイズ
+const maxRosterSize = 1 << 20
+
 // ProjectRequest プロジェクト作成・更新リクエスト
 type ProjectRequest struct {
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description"`
+	Version     *uint  `json:"version"` // 更新時のみ（編集を始めた時点のバージョン）
 }
 
-// Create 新しいプロジェクトを作成
+// Create 新しいプロジェクトを作成（X-Org-ID を指定した場合は組織のプロジェクトとして作成）
 func (c *ProjectController) Create(ctx *gin.Context) {
+	organizationID, ok := organizationIDFromHeader(ctx)
+	if !ok {
+		return
+	}
+
+	createProject(ctx, c.projectService, organizationID)
+}
+
+// createProject リクエストの内容でプロジェクトを作成して返す
+func createProject(ctx *gin.Context, projectService services.ProjectService, organizationID *uint) {
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
@@ -41,18 +62,75 @@ func (c *ProjectController) Create(ctx *gin.Context) {
 	// リクエストをバインド
 	var req ProjectRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// プロジェクトを作成
-	project, err := c.projectService.Create(req.Title, req.Description, u.ID)
+	project, err := projectService.Create(ctx.Request.Context(), req.Title, req.Description, u.ID, organizationID)
+	if err != nil {
+		if organizationID != nil {
+			respondOrganizationError(ctx, err)
+			return
+		}
+		if strings.Contains(err.Error(), "権限がありません") || strings.Contains(err.Error(), "料金プラン") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "project", project)
+}
+
+// CloneProjectRequest プロジェクトの複製リクエスト
+type CloneProjectRequest struct {
+	Title string `json:"title"` // 省略した場合は元のタイトルに「（コピー）」を付ける
+}
+
+// Clone プロジェクトのタスク・評価基準・投票をコピーした新しいプロジェクトを作成（オーナーのみ）
+func (c *ProjectController) Clone(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{"project": project})
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// リクエストをバインド（本文は省略可能）
+	var req CloneProjectRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	project, err := c.projectService.Clone(ctx.Request.Context(), uint(id), u.ID, req.Title)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "権限がありません"), strings.Contains(err.Error(), "料金プラン"):
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+		case strings.Contains(err.Error(), "見つかりません"):
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		case strings.Contains(err.Error(), "失敗しました"):
+			utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		default:
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "project", project)
 }
 
 // GetByID IDでプロジェクトを取得
@@ -60,33 +138,33 @@ func (c *ProjectController) GetByID(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// プロジェクトを取得
-	project, err := c.projectService.GetByID(uint(id))
+	project, err := c.projectService.GetByID(ctx.Request.Context(), uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
 		return
 	}
 
 	// アクセス権限をチェック
-	allowed, err := c.projectService.IsUserAllowed(uint(id), u.ID)
+	allowed, err := c.projectService.IsUserAllowed(ctx.Request.Context(), uint(id), u.ID)
 	if err != nil || !allowed {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "このプロジェクトにアクセスする権限がありません"})
+		utils.RespondError(ctx, http.StatusForbidden, "このプロジェクトにアクセスする権限がありません")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"project": project})
+	utils.Respond(ctx, http.StatusOK, "project", project)
 }
 
 // Update プロジェクトを更新
@@ -94,14 +172,14 @@ func (c *ProjectController) Update(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
@@ -109,22 +187,25 @@ func (c *ProjectController) Update(ctx *gin.Context) {
 	// リクエストをバインド
 	var req ProjectRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// プロジェクトを更新
-	project, err := c.projectService.Update(uint(id), u.ID, req.Title, req.Description)
+	project, err := c.projectService.Update(ctx.Request.Context(), uint(id), u.ID, req.Title, req.Description, req.Version)
 	if err != nil {
+		if respondVersionConflict(ctx, err) {
+			return
+		}
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"project": project})
+	utils.Respond(ctx, http.StatusOK, "project", project)
 }
 
 // Delete プロジェクトを削除
@@ -132,37 +213,37 @@ func (c *ProjectController) Delete(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// プロジェクトを削除
-	err = c.projectService.Delete(uint(id), u.ID)
+	err = c.projectService.Delete(ctx.Request.Context(), uint(id), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
 		if strings.Contains(err.Error(), "見つかりません") {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
 	ctx.Status(http.StatusNoContent)
 }
 
-// List プロジェクト一覧を取得
+// List 参加しているプロジェクト一覧を取得（X-Org-ID を指定した場合は組織のプロジェクト一覧）
 func (c *ProjectController) List(ctx *gin.Context) {
 	// クエリパラメータを取得
 	pageStr := ctx.DefaultQuery("page", "1")
@@ -180,10 +261,20 @@ func (c *ProjectController) List(ctx *gin.Context) {
 		limit = 20
 	}
 
+	// X-Org-ID を指定した場合は組織のプロジェクト一覧
+	organizationID, ok := organizationIDFromHeader(ctx)
+	if !ok {
+		return
+	}
+	if organizationID != nil {
+		listOrganizationProjects(ctx, c.projectService, *organizationID)
+		return
+	}
+
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
@@ -192,18 +283,20 @@ func (c *ProjectController) List(ctx *gin.Context) {
 	userID := u.ID
 
 	// プロジェクト一覧を取得
-	projects, total, pages, err := c.projectService.List(page, limit, search, &userID)
+	projects, total, pages, err := c.projectService.List(ctx.Request.Context(), page, limit, search, &userID)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, projects)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"projects": projects,
-		"total":    total,
-		"pages":    pages,
-		"page":     page,
-	})
+	respondPaginated(ctx, "projects", items, total, page, limit, pages, nil)
 }
 
 // GetMembers プロジェクトのメンバー一覧を取得
@@ -211,33 +304,143 @@ func (c *ProjectController) GetMembers(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// アクセス権限をチェック
-	allowed, err := c.projectService.IsUserAllowed(uint(id), u.ID)
+	allowed, err := c.projectService.IsUserAllowed(ctx.Request.Context(), uint(id), u.ID)
 	if err != nil || !allowed {
-		ctx.JSON(http.StatusForbidden, gin.H{"error": "このプロジェクトにアクセスする権限がありません"})
+		utils.RespondError(ctx, http.StatusForbidden, "このプロジェクトにアクセスする権限がありません")
 		return
 	}
 
 	// メンバー一覧を取得
-	members, err := c.projectService.GetMembers(uint(id))
+	members, err := c.projectService.GetMembers(ctx.Request.Context(), uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"members": members})
+	utils.Respond(ctx, http.StatusOK, "members", members)
+}
+
+// projectMemberExportCSVHeader エクスポートするメンバーのCSVのヘッダー（last_activity_atは活動がない場合は空）
+var projectMemberExportCSVHeader = []string{"user_id", "name", "nickname", "handle", "role", "supervised", "joined_at", "submissions", "last_activity_at"}
+
+// ExportMembers メンバーを参加日時・役割・提出作品数・最後の活動日時とともにCSVでエクスポート（オーナーのみ）
+func (c *ProjectController) ExportMembers(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	rows, err := c.projectService.ExportMembers(ctx.Request.Context(), uint(id), u.ID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "見つかりません"):
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		case strings.Contains(err.Error(), "権限がありません"):
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+		default:
+			utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=project_%d_members.csv", id))
+	ctx.Status(http.StatusOK)
+
+	csvWriter := csv.NewWriter(ctx.Writer)
+	csvWriter.Write(projectMemberExportCSVHeader)
+	for _, row := range rows {
+		handle := ""
+		if row.Member.User.Handle != nil {
+			handle = *row.Member.User.Handle
+		}
+		lastActivityAt := ""
+		if row.LastActivityAt != nil {
+			lastActivityAt = row.LastActivityAt.Format(time.RFC3339)
+		}
+		csvWriter.Write(utils.CSVSafeRecord([]string{
+			strconv.FormatUint(uint64(row.Member.UserID), 10),
+			row.Member.User.Name,
+			row.Member.User.Nickname,
+			handle,
+			row.Role,
+			strconv.FormatBool(row.Member.Supervised),
+			row.Member.JoinedAt.Format(time.RFC3339),
+			strconv.FormatInt(row.Submissions, 10),
+			lastActivityAt,
+		}))
+	}
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		ctx.Error(err)
+	}
+}
+
+// ListInactiveMembers 整理の候補として、一定期間（days、既定は30日）活動のないメンバーを取得（オーナーのみ）
+func (c *ProjectController) ListInactiveMembers(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	days := 0
+	if daysStr := ctx.Query("days"); daysStr != "" {
+		days, err = strconv.Atoi(daysStr)
+		if err != nil || days < 1 {
+			utils.RespondError(ctx, http.StatusBadRequest, "無効な日数です")
+			return
+		}
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	members, err := c.projectService.ListInactiveMembers(ctx.Request.Context(), uint(id), u.ID, days)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "見つかりません"):
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		case strings.Contains(err.Error(), "権限がありません"):
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+		case strings.Contains(err.Error(), "指定してください"):
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		default:
+			utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "members", members)
 }
 
 // RemoveMember メンバーをプロジェクトから削除
@@ -245,33 +448,33 @@ func (c *ProjectController) RemoveMember(ctx *gin.Context) {
 	// プロジェクトIDを解析
 	projectID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なプロジェクトIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なプロジェクトIDです")
 		return
 	}
 
 	// メンバーIDを解析
 	memberID, err := strconv.ParseUint(ctx.Param("memberID"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なメンバーIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なメンバーIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// メンバーを削除
-	err = c.projectService.RemoveMember(uint(projectID), u.ID, uint(memberID))
+	err = c.projectService.RemoveMember(ctx.Request.Context(), uint(projectID), u.ID, uint(memberID))
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -283,30 +486,30 @@ func (c *ProjectController) GenerateInvitationCode(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// 招待コードを生成
-	code, err := c.projectService.GenerateInvitationCode(uint(id), u.ID)
+	code, err := c.projectService.GenerateInvitationCode(ctx.Request.Context(), uint(id), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"invitation_code": code})
+	utils.Respond(ctx, http.StatusOK, "invitation_code", code)
 }
 
 // JoinProject 招待コードを使用してプロジェクトに参加
@@ -316,26 +519,26 @@ func (c *ProjectController) JoinProject(ctx *gin.Context) {
 		InvitationCode string `json:"invitation_code" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// プロジェクトに参加
-	project, err := c.projectService.JoinByInvitationCode(req.InvitationCode, u.ID)
+	project, err := c.projectService.JoinByInvitationCode(ctx.Request.Context(), req.InvitationCode, u.ID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"project": project})
+	utils.Respond(ctx, http.StatusOK, "project", project)
 }
 
 // GetUserProjects ユーザーが参加しているプロジェクト一覧を取得
@@ -358,22 +561,111 @@ func (c *ProjectController) GetUserProjects(ctx *gin.Context) {
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// プロジェクト一覧を取得
-	projects, total, pages, err := c.projectService.GetUserProjects(u.ID, page, limit)
+	projects, total, pages, err := c.projectService.GetUserProjects(ctx.Request.Context(), u.ID, page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, projects)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "projects", items, total, page, limit, pages, nil)
+}
+
+// GetDashboard プロジェクトのダッシュボードを取得
+func (c *ProjectController) GetDashboard(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	dashboard, err := c.projectService.GetDashboard(ctx.Request.Context(), uint(id), u.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "dashboard", dashboard)
+}
+
+// ImportRoster CSV（name,email）の名簿を取り込んでメンバーに追加
+// multipart/form-dataの file、またはCSVをそのままリクエストボディで受け付ける
+func (c *ProjectController) ImportRoster(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxRosterSize)
+
+	var roster io.Reader = ctx.Request.Body
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		fileHeader, err := ctx.FormFile("file")
+		if err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, "名簿ファイル（file）は必須です")
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, "名簿ファイルを開けませんでした")
+			return
+		}
+		defer file.Close()
+		roster = file
+	}
+
+	result, err := c.rosterService.Import(ctx.Request.Context(), uint(id), u.ID, roster)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"projects": projects,
-		"total":    total,
-		"pages":    pages,
-		"page":     page,
-	})
+	utils.Respond(ctx, http.StatusOK, "roster", result)
 }
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PublicController 匿名の利用者向けの公開API（/public/v1）に関するコントローラー
+type PublicController struct {
+	publicService services.PublicService
+	cacheTTL      time.Duration
+}
+
+// NewPublicController PublicControllerを作成
+func NewPublicController(publicService services.PublicService, cacheTTL time.Duration) *PublicController {
+	return &PublicController{
+		publicService: publicService,
+		cacheTTL:      cacheTTL,
+	}
+}
+
+// ListWorks 作品一覧を取得
+func (c *PublicController) ListWorks(ctx *gin.Context) {
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	works, total, pages, err := c.publicService.ListWorks(ctx.Request.Context(), page, limit, ctx.Query("tag"), ctx.DefaultQuery("sort", "newest"))
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.setCacheHeaders(ctx)
+	respondPaginated(ctx, "works", works, total, page, limit, pages, nil)
+}
+
+// GetWork 作品の詳細を取得
+func (c *PublicController) GetWork(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	work, err := c.publicService.GetWork(ctx.Request.Context(), uint(id))
+	if err != nil {
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	c.setCacheHeaders(ctx)
+	utils.Respond(ctx, http.StatusOK, "work", work)
+}
+
+// Embed 作品を埋め込むためのメタデータ（oEmbed）を取得
+func (c *PublicController) Embed(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// 埋め込む大きさの上限（オプション）
+	maxWidth, _ := strconv.Atoi(ctx.Query("maxwidth"))
+	maxHeight, _ := strconv.Atoi(ctx.Query("maxheight"))
+
+	embed, err := c.publicService.Embed(ctx.Request.Context(), uint(id), maxWidth, maxHeight)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	c.setCacheHeaders(ctx)
+	utils.Respond(ctx, http.StatusOK, "embed", embed)
+}
+
+// ListTags タグ一覧を取得
+func (c *PublicController) ListTags(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	tags, err := c.publicService.ListTags(ctx.Request.Context(), limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	c.setCacheHeaders(ctx)
+	utils.Respond(ctx, http.StatusOK, "tags", tags)
+}
+
+// setCacheHeaders CDNやブラウザでキャッシュできるようにする（期限切れ後もしばらくは古い内容を返してよい）
+func (c *PublicController) setCacheHeaders(ctx *gin.Context) {
+	seconds := int(c.cacheTTL.Seconds())
+	if seconds <= 0 {
+		return
+	}
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d, s-maxage=%d, stale-while-revalidate=%d", seconds, seconds, seconds))
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PurgeController 削除依頼に応じた完全な削除に関するコントローラー（管理者用）
+type PurgeController struct {
+	purgeService services.PurgeService
+}
+
+// NewPurgeController PurgeControllerを作成
+func NewPurgeController(purgeService services.PurgeService) *PurgeController {
+	return &PurgeController{
+		purgeService: purgeService,
+	}
+}
+
+// purgeRequest 完全な削除のリクエスト
+type purgeRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// PurgeUser ユーザーを完全に削除
+func (c *PurgeController) PurgeUser(ctx *gin.Context) {
+	c.purge(ctx, c.purgeService.PurgeUser)
+}
+
+// PurgeWork 作品を完全に削除
+func (c *PurgeController) PurgeWork(ctx *gin.Context) {
+	c.purge(ctx, c.purgeService.PurgeWork)
+}
+
+// purge 対象のIDと理由を解析して完全に削除
+func (c *PurgeController) purge(ctx *gin.Context, purge func(ctx context.Context, adminID, id uint, reason string) (*services.PurgeSummary, error)) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req purgeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "削除の理由（削除依頼の受付番号など）を指定してください")
+		return
+	}
+
+	summary, err := purge(ctx.Request.Context(), u.ID, uint(id), req.Reason)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "主催するイベントがあります"):
+			utils.RespondError(ctx, http.StatusConflict, err.Error())
+		case strings.Contains(err.Error(), "見つかりません"):
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		case strings.Contains(err.Error(), "失敗しました"):
+			utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		default:
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "purge", summary)
+}
+
+// ListAuditLogs 監査ログを取得
+func (c *PurgeController) ListAuditLogs(ctx *gin.Context) {
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	logs, total, pages, err := c.purgeService.ListAuditLogs(ctx.Request.Context(), ctx.Query("action"), page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "audit_logs", logs, total, page, limit, pages, nil)
+}
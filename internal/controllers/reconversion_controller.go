@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReconversionController 再変換キャンペーンと変換キューに関するコントローラー（管理者用）
+type ReconversionController struct {
+	reconversionService services.ReconversionService
+	conversionQueue     services.ConversionQueue
+	invocationService   services.InvocationAnalyticsService
+}
+
+// NewReconversionController ReconversionControllerを作成
+func NewReconversionController(reconversionService services.ReconversionService, conversionQueue services.ConversionQueue, invocationService services.InvocationAnalyticsService) *ReconversionController {
+	return &ReconversionController{
+		reconversionService: reconversionService,
+		conversionQueue:     conversionQueue,
+		invocationService:   invocationService,
+	}
+}
+
+// Start 再変換キャンペーンを開始
+func (c *ReconversionController) Start(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// リクエストをバインド（ボディは省略可）
+	var req struct {
+		BatchSize int `json:"batch_size"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// キャンペーンを開始
+	campaign, err := c.reconversionService.Start(ctx.Request.Context(), u.ID, req.BatchSize)
+	if err != nil {
+		if strings.Contains(err.Error(), "実行中の再変換キャンペーンがあります") {
+			utils.RespondError(ctx, http.StatusConflict, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusAccepted, "campaign", campaign)
+}
+
+// GetByID キャンペーンの進捗を取得
+func (c *ReconversionController) GetByID(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	campaign, err := c.reconversionService.GetByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "campaign", campaign)
+}
+
+// List キャンペーン一覧を取得
+func (c *ReconversionController) List(ctx *gin.Context) {
+	// クエリパラメータを取得
+	pageStr := ctx.DefaultQuery("page", "1")
+	limitStr := ctx.DefaultQuery("limit", "20")
+
+	// 数値パラメータを解析
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	campaigns, total, pages, err := c.reconversionService.List(ctx.Request.Context(), page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "campaigns", campaigns, total, page, limit, pages, nil)
+}
+
+// Cancel 実行中のキャンペーンを中止
+func (c *ReconversionController) Cancel(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	campaign, err := c.reconversionService.Cancel(ctx.Request.Context(), uint(id))
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "campaign", campaign)
+}
+
+// QueueStats 変換キューの優先度ごとのメトリクスを取得
+func (c *ReconversionController) QueueStats(ctx *gin.Context) {
+	utils.Respond(ctx, http.StatusOK, "lanes", c.conversionQueue.Stats())
+}
+
+// Invocations Lambda呼び出しの回数・所要時間・費用の見積もりを日ごと・変換バージョンごとに取得
+func (c *ReconversionController) Invocations(ctx *gin.Context) {
+	days, err := strconv.Atoi(ctx.DefaultQuery("days", "30"))
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な日数です")
+		return
+	}
+
+	analytics, err := c.invocationService.Analyze(ctx.Request.Context(), days)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "invocations", analytics)
+}
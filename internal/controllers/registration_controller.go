@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegistrationController 新規登録の受け付け方（招待コード・順番待ち）に関するコントローラー
+type RegistrationController struct {
+	registrationService services.RegistrationService
+}
+
+// NewRegistrationController RegistrationControllerを作成
+func NewRegistrationController(registrationService services.RegistrationService) *RegistrationController {
+	return &RegistrationController{
+		registrationService: registrationService,
+	}
+}
+
+// Status 新規登録の受け付け状況を取得
+func (c *RegistrationController) Status(ctx *gin.Context) {
+	utils.Respond(ctx, http.StatusOK, "registration", c.registrationService.Status())
+}
+
+// JoinWaitlist 新規登録の順番待ちに登録
+func (c *RegistrationController) JoinWaitlist(ctx *gin.Context) {
+	var req struct {
+		Email   string `json:"email" binding:"required,email"`
+		Name    string `json:"name"`
+		Message string `json:"message" binding:"max=1000"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	entry, err := c.registrationService.JoinWaitlist(ctx.Request.Context(), req.Email, req.Name, req.Message)
+	if err != nil {
+		respondRegistrationError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "waitlist_entry", entry)
+}
+
+// ListWaitlist 順番待ち一覧を取得（管理者用、statusで絞り込み）
+func (c *RegistrationController) ListWaitlist(ctx *gin.Context) {
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	entries, total, pages, err := c.registrationService.ListWaitlist(ctx.Request.Context(), ctx.Query("status"), page, limit)
+	if err != nil {
+		respondRegistrationError(ctx, err)
+		return
+	}
+
+	respondPaginated(ctx, "waitlist", entries, total, page, limit, pages, nil)
+}
+
+// ApproveWaitlist 順番待ちを承認して招待コードをメールで送る（管理者用）
+func (c *RegistrationController) ApproveWaitlist(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	entry, err := c.registrationService.ApproveWaitlist(ctx.Request.Context(), uint(id), u.ID)
+	if err != nil {
+		respondRegistrationError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "waitlist_entry", entry)
+}
+
+// DeleteWaitlist 順番待ちを削除（管理者用）
+func (c *RegistrationController) DeleteWaitlist(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	if err := c.registrationService.DeleteWaitlist(ctx.Request.Context(), uint(id)); err != nil {
+		respondRegistrationError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListInviteCodes 招待コード一覧を取得（管理者用）
+func (c *RegistrationController) ListInviteCodes(ctx *gin.Context) {
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	codes, total, pages, err := c.registrationService.ListInviteCodes(ctx.Request.Context(), page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "invite_codes", codes, total, page, limit, pages, nil)
+}
+
+// CreateInviteCode 招待コードを発行（管理者用）
+func (c *RegistrationController) CreateInviteCode(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req struct {
+		MaxUses   int        `json:"max_uses"`
+		ExpiresAt *time.Time `json:"expires_at"`
+		Note      string     `json:"note"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.MaxUses == 0 {
+		req.MaxUses = 1
+	}
+
+	code, err := c.registrationService.CreateInviteCode(ctx.Request.Context(), u.ID, req.MaxUses, req.ExpiresAt, req.Note)
+	if err != nil {
+		respondRegistrationError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "invite_code", code)
+}
+
+// DeleteInviteCode 招待コードを削除（管理者用）
+func (c *RegistrationController) DeleteInviteCode(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	if err := c.registrationService.DeleteInviteCode(ctx.Request.Context(), uint(id)); err != nil {
+		respondRegistrationError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// respondRegistrationError 招待コード・順番待ちのエラーを対応するステータスで返す
+func respondRegistrationError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrAlreadyOnWaitlist), strings.Contains(err.Error(), "既に"):
+		utils.RespondError(ctx, http.StatusConflict, err.Error())
+	case strings.Contains(err.Error(), "見つかりません"):
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+	case strings.Contains(err.Error(), "失敗しました"):
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+	default:
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportController 通報と通報された投稿の確認に関するコントローラー
+type ReportController struct {
+	reportService services.ReportService
+}
+
+// NewReportController ReportControllerを作成
+func NewReportController(reportService services.ReportService) *ReportController {
+	return &ReportController{
+		reportService: reportService,
+	}
+}
+
+// ReportWork 作品を通報
+func (c *ReportController) ReportWork(ctx *gin.Context) {
+	c.report(ctx, models.ReportTargetWork)
+}
+
+// ReportComment コメントを通報
+func (c *ReportController) ReportComment(ctx *gin.Context) {
+	c.report(ctx, models.ReportTargetComment)
+}
+
+// report 作品・コメントを通報（理由は省略可能）
+func (c *ReportController) report(ctx *gin.Context, targetType string) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	report, err := c.reportService.Report(ctx.Request.Context(), targetType, uint(id), u.ID, req.Reason)
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "既に通報済みです") {
+			utils.RespondError(ctx, http.StatusConflict, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "report", report)
+}
+
+// ListHiddenWorks 通報により非表示になっている作品一覧を取得（管理者用）
+func (c *ReportController) ListHiddenWorks(ctx *gin.Context) {
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	works, total, pages, err := c.reportService.ListHiddenWorks(ctx.Request.Context(), page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "works", works, total, page, limit, pages, nil)
+}
+
+// ListHiddenComments 通報により非表示になっているコメント一覧を取得（管理者用）
+func (c *ReportController) ListHiddenComments(ctx *gin.Context) {
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	comments, total, pages, err := c.reportService.ListHiddenComments(ctx.Request.Context(), page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "comments", comments, total, page, limit, pages, nil)
+}
+
+// ListReports 作品・コメントへの通報一覧を取得（管理者用）
+func (c *ReportController) ListReports(ctx *gin.Context) {
+	targetType, id, ok := parseReportTarget(ctx)
+	if !ok {
+		return
+	}
+
+	reports, err := c.reportService.ListReports(ctx.Request.Context(), targetType, id)
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "reports", reports)
+}
+
+// Restore 問題がないと確認した投稿を再表示（管理者用）
+func (c *ReportController) Restore(ctx *gin.Context) {
+	targetType, id, ok := parseReportTarget(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.reportService.Restore(ctx.Request.Context(), targetType, id); err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Remove 問題があると確認した投稿を削除（管理者用）
+func (c *ReportController) Remove(ctx *gin.Context) {
+	targetType, id, ok := parseReportTarget(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.reportService.Remove(ctx.Request.Context(), targetType, id); err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// parseReportTarget パスから通報の対象（work / comment）とIDを解析（失敗した場合はエラーレスポンスを返す）
+func parseReportTarget(ctx *gin.Context) (string, uint, bool) {
+	targetType := ctx.Param("type")
+	if targetType != models.ReportTargetWork && targetType != models.ReportTargetComment {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な通報の対象です: "+targetType)
+		return "", 0, false
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return "", 0, false
+	}
+
+	return targetType, uint(id), true
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RetagController 作品のタグの一括追加・削除に関するコントローラー（管理者用）
+type RetagController struct {
+	retagService services.RetagService
+}
+
+// NewRetagController RetagControllerを作成
+func NewRetagController(retagService services.RetagService) *RetagController {
+	return &RetagController{
+		retagService: retagService,
+	}
+}
+
+// Start 一括タグ付けジョブを開始
+func (c *RetagController) Start(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req services.RetagInput
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job, err := c.retagService.Start(ctx.Request.Context(), u.ID, req)
+	if err != nil {
+		respondRetagError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusAccepted, "job", job)
+}
+
+// GetByID ジョブの進捗を取得
+func (c *RetagController) GetByID(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	job, err := c.retagService.GetByID(ctx.Request.Context(), uint(id))
+	if err != nil {
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "job", job)
+}
+
+// List ジョブ一覧を取得
+func (c *RetagController) List(ctx *gin.Context) {
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	jobs, total, pages, err := c.retagService.List(ctx.Request.Context(), page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "jobs", jobs, total, page, limit, pages, nil)
+}
+
+// Cancel 実行中のジョブを中止
+func (c *RetagController) Cancel(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	job, err := c.retagService.Cancel(ctx.Request.Context(), uint(id))
+	if err != nil {
+		respondRetagError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "job", job)
+}
+
+// Undo ジョブで変更したタグを元に戻すジョブを開始
+func (c *RetagController) Undo(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	job, err := c.retagService.Undo(ctx.Request.Context(), uint(id), u.ID)
+	if err != nil {
+		respondRetagError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusAccepted, "job", job)
+}
+
+// respondRetagError 一括タグ付けのエラーを対応するステータスで返す
+func respondRetagError(ctx *gin.Context, err error) {
+	switch {
+	case strings.Contains(err.Error(), "実行中の一括タグ付けジョブがあります"), strings.Contains(err.Error(), "既に取り消されています"):
+		utils.RespondError(ctx, http.StatusConflict, err.Error())
+	case strings.Contains(err.Error(), "見つかりません"):
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+	case strings.Contains(err.Error(), "失敗しました"):
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+	default:
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+	}
+}
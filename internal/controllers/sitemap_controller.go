@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SitemapController サイトマップに関するコントローラー
+type SitemapController struct {
+	sitemapService services.SitemapService
+}
+
+// NewSitemapController SitemapControllerを作成
+func NewSitemapController(sitemapService services.SitemapService) *SitemapController {
+	return &SitemapController{
+		sitemapService: sitemapService,
+	}
+}
+
+// Index サイトマップインデックスを取得
+func (c *SitemapController) Index(ctx *gin.Context) {
+	body, err := c.sitemapService.Index(ctx.Request.Context())
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
+
+// Works 作品のサイトマップを取得（/sitemaps/works/1.xml）
+func (c *SitemapController) Works(ctx *gin.Context) {
+	// ページ番号を解析
+	page, err := strconv.Atoi(strings.TrimSuffix(ctx.Param("page"), ".xml"))
+	if err != nil || page < 1 {
+		utils.RespondError(ctx, http.StatusNotFound, "サイトマップが見つかりません")
+		return
+	}
+
+	body, err := c.sitemapService.Works(ctx.Request.Context(), page)
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
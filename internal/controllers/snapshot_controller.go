@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SnapshotController 作品のスナップショット（再生中にキャプチャしたフレーム）に関するコントローラー
+type SnapshotController struct {
+	snapshotService services.SnapshotService
+}
+
+// NewSnapshotController SnapshotControllerを作成
+func NewSnapshotController(snapshotService services.SnapshotService) *SnapshotController {
+	return &SnapshotController{
+		snapshotService: snapshotService,
+	}
+}
+
+// List 作品のスナップショット一覧を取得
+func (c *SnapshotController) List(ctx *gin.Context) {
+	// 作品IDを解析
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	snapshots, err := c.snapshotService.List(ctx.Request.Context(), uint(workID))
+	if err != nil {
+		respondSnapshotError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "snapshots", snapshots)
+}
+
+// Create キャプチャしたフレームを保存
+// multipart/form-dataの image（canvas.toBlobの結果）か、JSONの image（Base64またはdata URL）で受け取る
+func (c *SnapshotController) Create(ctx *gin.Context) {
+	// 作品IDを解析
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var image io.Reader
+	var frameCount int
+	var setThumbnail bool
+	if ctx.ContentType() == "multipart/form-data" {
+		imageHeader, err := ctx.FormFile("image")
+		if err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, "画像（image）は必須です")
+			return
+		}
+		imageFile, err := imageHeader.Open()
+		if err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, "画像を開けませんでした")
+			return
+		}
+		defer imageFile.Close()
+
+		image = imageFile
+		if value := ctx.PostForm("frame_count"); value != "" {
+			if frameCount, err = strconv.Atoi(value); err != nil {
+				utils.RespondError(ctx, http.StatusBadRequest, "無効なフレーム数です")
+				return
+			}
+		}
+		setThumbnail, _ = strconv.ParseBool(ctx.PostForm("set_thumbnail"))
+	} else {
+		var req struct {
+			Image        string `json:"image" binding:"required"`
+			FrameCount   int    `json:"frame_count"`
+			SetThumbnail bool   `json:"set_thumbnail"`
+		}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		data, err := decodeBase64Image(req.Image)
+		if err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, "画像はBase64またはdata URLで指定してください")
+			return
+		}
+
+		image = bytes.NewReader(data)
+		frameCount = req.FrameCount
+		setThumbnail = req.SetThumbnail
+	}
+
+	snapshot, err := c.snapshotService.Create(ctx.Request.Context(), uint(workID), u.ID, image, frameCount, setThumbnail)
+	if err != nil {
+		respondSnapshotError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusCreated, "snapshot", snapshot)
+}
+
+// SetThumbnail スナップショットを作品のサムネイルに設定
+func (c *SnapshotController) SetThumbnail(ctx *gin.Context) {
+	workID, snapshotID, ok := parseSnapshotParams(ctx)
+	if !ok {
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	snapshot, err := c.snapshotService.SetThumbnail(ctx.Request.Context(), workID, snapshotID, u.ID)
+	if err != nil {
+		respondSnapshotError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "snapshot", snapshot)
+}
+
+// Delete スナップショットを削除
+func (c *SnapshotController) Delete(ctx *gin.Context) {
+	workID, snapshotID, ok := parseSnapshotParams(ctx)
+	if !ok {
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.snapshotService.Delete(ctx.Request.Context(), workID, snapshotID, u.ID); err != nil {
+		respondSnapshotError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// parseSnapshotParams パスの作品IDとスナップショットIDを解析（無効な場合はエラーレスポンスを返してfalse）
+func parseSnapshotParams(ctx *gin.Context) (uint, uint, bool) {
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return 0, 0, false
+	}
+	snapshotID, err := strconv.ParseUint(ctx.Param("snapshotID"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なスナップショットIDです")
+		return 0, 0, false
+	}
+	return uint(workID), uint(snapshotID), true
+}
+
+// decodeBase64Image Base64の画像をデコード（data:image/png;base64, で始まるdata URLも受け付ける）
+func decodeBase64Image(value string) ([]byte, error) {
+	if strings.HasPrefix(value, "data:") {
+		if i := strings.Index(value, ";base64,"); i >= 0 {
+			value = value[i+len(";base64,"):]
+		}
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+}
+
+// respondSnapshotError スナップショットのエラーを対応するステータスで返す
+func respondSnapshotError(ctx *gin.Context, err error) {
+	if strings.Contains(err.Error(), "権限がありません") {
+		utils.RespondError(ctx, http.StatusForbidden, err.Error())
+		return
+	}
+	respondImageError(ctx, err)
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StaticController ローカルストレージに保存したファイルを配信するコントローラー
+type StaticController struct {
+	dir string
+}
+
+// NewStaticController StaticControllerを作成
+func NewStaticController(dir string) *StaticController {
+	return &StaticController{
+		dir: dir,
+	}
+}
+
+// Serve ファイルを配信（ETagとIf-None-Match、Rangeリクエストに対応）
+func (c *StaticController) Serve(ctx *gin.Context) {
+	// 保存先ディレクトリの外や隠しファイルは配信しない
+	name := ctx.Param("filepath")
+	if strings.Contains(name, "..") || strings.Contains(name, "\x00") || strings.Contains(name, "/.") {
+		utils.RespondError(ctx, http.StatusNotFound, "ファイルが見つかりません")
+		return
+	}
+	path := filepath.Join(c.dir, filepath.FromSlash(filepath.Clean("/"+name)))
+
+	file, err := os.Open(path)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusNotFound, "ファイルが見つかりません")
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		utils.RespondError(ctx, http.StatusNotFound, "ファイルが見つかりません")
+		return
+	}
+
+	// 保存したファイルはランダムなキーで上書きされないため、長めにキャッシュさせる
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", assetCacheMaxAge))
+	ctx.Header("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	// HTML・SVGなどはAPIと同じオリジンで開かれないよう、アセットと同じく拡張子から形式を決めてダウンロードさせる
+	ctx.Header("Content-Security-Policy", userContentSecurityPolicy)
+	contentType := services.AssetContentType(info.Name())
+	ctx.Header("Content-Type", contentType)
+	if contentType == services.AssetDownloadContentType {
+		ctx.Header("Content-Disposition", "attachment")
+	}
+	http.ServeContent(ctx.Writer, ctx.Request, info.Name(), info.ModTime(), file)
+}
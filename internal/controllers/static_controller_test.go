@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestStaticServeSandboxesUserContent 保存したHTML・SVGがダウンロードとして、画像はそのまま、どちらもサンドボックスのCSP付きで返されることを確認
+func TestStaticServeSandboxesUserContent(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"x.html": "<script>alert(1)</script>",
+		"x.svg":  `<svg xmlns="http://www.w3.org/2000/svg" onload="alert(1)"/>`,
+		"x.png":  "\x89PNG\r\n\x1a\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/uploads/*filepath", NewStaticController(dir).Serve)
+
+	tests := []struct {
+		path            string
+		wantType        string
+		wantDisposition string
+	}{
+		{"/uploads/x.html", "application/octet-stream", "attachment"},
+		{"/uploads/x.svg", "application/octet-stream", "attachment"},
+		{"/uploads/x.png", "image/png", ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", tt.path, w.Code)
+		}
+		if got := w.Header().Get("Content-Security-Policy"); got != userContentSecurityPolicy {
+			t.Errorf("%s: Content-Security-Policy = %q", tt.path, got)
+		}
+		if got := w.Header().Get("Content-Type"); got != tt.wantType {
+			t.Errorf("%s: Content-Type = %q, want %q", tt.path, got, tt.wantType)
+		}
+		if got := w.Header().Get("Content-Disposition"); got != tt.wantDisposition {
+			t.Errorf("%s: Content-Disposition = %q, want %q", tt.path, got, tt.wantDisposition)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -33,11 +34,22 @@ func (c *TagController) List(ctx *gin.Context) {
 	}
 
 	// タグ一覧を取得
-	tags, err := c.tagService.List(search, limit)
+	tags, err := c.tagService.List(ctx.Request.Context(), search, limit)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, tags)
+	utils.Respond(ctx, http.StatusOK, "", tags)
+}
+
+// CleanupUnused どの作品にも付いていないタグを即座に削除（管理者用）
+func (c *TagController) CleanupUnused(ctx *gin.Context) {
+	tags, err := c.tagService.CleanupUnused(ctx.Request.Context())
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "tags", tags)
 }
@@ -4,9 +4,11 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,9 +27,10 @@ func NewTaskController(taskService services.TaskService) *TaskController {
 
 // TaskRequest タスク作成・更新リクエスト
 type TaskRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
-	ProjectID   uint   `json:"project_id" binding:"required"`
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	DueAt       *time.Time `json:"due_at"`
+	ProjectID   uint       `json:"project_id" binding:"required"`
 }
 
 // Create 新しいタスクを作成
@@ -35,7 +38,7 @@ func (c *TaskController) Create(ctx *gin.Context) {
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
@@ -43,22 +46,22 @@ func (c *TaskController) Create(ctx *gin.Context) {
 	// リクエストをバインド
 	var req TaskRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// タスクを作成
-	task, err := c.taskService.Create(req.Title, req.Description, req.ProjectID, u.ID)
+	task, err := c.taskService.Create(ctx.Request.Context(), req.Title, req.Description, req.DueAt, req.ProjectID, u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{"task": task})
+	utils.Respond(ctx, http.StatusCreated, "task", task)
 }
 
 // GetByID IDでタスクを取得
@@ -66,30 +69,30 @@ func (c *TaskController) GetByID(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// タスクを取得
-	task, err := c.taskService.GetByID(uint(id), u.ID)
+	task, err := c.taskService.GetByID(ctx.Request.Context(), uint(id), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"task": task})
+	utils.Respond(ctx, http.StatusOK, "task", task)
 }
 
 // Update タスクを更新
@@ -97,40 +100,45 @@ func (c *TaskController) Update(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// リクエストをバインド
 	var req struct {
-		Title       string `json:"title" binding:"required"`
-		Description string `json:"description"`
+		Title       string     `json:"title" binding:"required"`
+		Description string     `json:"description"`
+		DueAt       *time.Time `json:"due_at"`
+		Version     *uint      `json:"version"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// タスクを更新
-	task, err := c.taskService.Update(uint(id), u.ID, req.Title, req.Description)
+	task, err := c.taskService.Update(ctx.Request.Context(), uint(id), u.ID, req.Title, req.Description, req.DueAt, req.Version)
 	if err != nil {
+		if respondVersionConflict(ctx, err) {
+			return
+		}
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"task": task})
+	utils.Respond(ctx, http.StatusOK, "task", task)
 }
 
 // Delete タスクを削除
@@ -138,26 +146,26 @@ func (c *TaskController) Delete(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// タスクを削除
-	err = c.taskService.Delete(uint(id), u.ID)
+	err = c.taskService.Delete(ctx.Request.Context(), uint(id), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -169,30 +177,30 @@ func (c *TaskController) ListByProject(ctx *gin.Context) {
 	// プロジェクトIDを解析
 	projectID, err := strconv.ParseUint(ctx.Param("projectID"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なプロジェクトIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なプロジェクトIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// タスク一覧を取得
-	tasks, err := c.taskService.ListByProject(uint(projectID), u.ID)
+	tasks, err := c.taskService.ListByProject(ctx.Request.Context(), uint(projectID), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"tasks": tasks})
+	utils.Respond(ctx, http.StatusOK, "tasks", tasks)
 }
 
 // AddWork 作品をタスクに追加
@@ -200,7 +208,7 @@ func (c *TaskController) AddWork(ctx *gin.Context) {
 	// タスクIDを解析
 	taskID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なタスクIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なタスクIDです")
 		return
 	}
 
@@ -209,26 +217,26 @@ func (c *TaskController) AddWork(ctx *gin.Context) {
 		WorkID uint `json:"work_id" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// 作品をタスクに追加
-	err = c.taskService.AddWork(uint(taskID), req.WorkID, u.ID)
+	err = c.taskService.AddWork(ctx.Request.Context(), uint(taskID), req.WorkID, u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -240,33 +248,33 @@ func (c *TaskController) RemoveWork(ctx *gin.Context) {
 	// タスクIDを解析
 	taskID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なタスクIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なタスクIDです")
 		return
 	}
 
 	// 作品IDを解析
 	workID, err := strconv.ParseUint(ctx.Param("workID"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効な作品IDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な作品IDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// 作品をタスクから削除
-	err = c.taskService.RemoveWork(uint(taskID), uint(workID), u.ID)
+	err = c.taskService.RemoveWork(ctx.Request.Context(), uint(taskID), uint(workID), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -278,7 +286,7 @@ func (c *TaskController) GetWorks(ctx *gin.Context) {
 	// タスクIDを解析
 	taskID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なタスクIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なタスクIDです")
 		return
 	}
 
@@ -300,28 +308,23 @@ func (c *TaskController) GetWorks(ctx *gin.Context) {
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// 作品一覧を取得
-	works, total, pages, err := c.taskService.GetWorks(uint(taskID), u.ID, page, limit)
+	works, total, pages, err := c.taskService.GetWorks(ctx.Request.Context(), uint(taskID), u.ID, page, limit)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"works": works,
-		"total": total,
-		"pages": pages,
-		"page":  page,
-	})
+	respondPaginated(ctx, "works", works, total, page, limit, pages, nil)
 }
 
 // UpdateOrders タスクの表示順序を更新
@@ -332,26 +335,26 @@ func (c *TaskController) UpdateOrders(ctx *gin.Context) {
 		OrderIndices []int  `json:"order_indices" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// タスクの順序を更新
-	err := c.taskService.UpdateOrders(req.TaskIDs, req.OrderIndices, u.ID)
+	err := c.taskService.UpdateOrders(ctx.Request.Context(), req.TaskIDs, req.OrderIndices, u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 対応するtusプロトコルのバージョンと拡張
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,termination"
+)
+
+// UploadController tusプロトコルによる再開可能なアップロードのコントローラー
+type UploadController struct {
+	assetService services.AssetService
+}
+
+// NewUploadController UploadControllerを作成
+func NewUploadController(assetService services.AssetService) *UploadController {
+	return &UploadController{
+		assetService: assetService,
+	}
+}
+
+// TusMiddleware tusの共通ヘッダーを設定し、クライアントのプロトコルバージョンを確認
+func (c *UploadController) TusMiddleware(ctx *gin.Context) {
+	ctx.Header("Tus-Resumable", tusVersion)
+	ctx.Header("Tus-Version", tusVersion)
+	ctx.Header("Tus-Extension", tusExtensions)
+	ctx.Header("Tus-Max-Size", strconv.FormatInt(c.assetService.MaxSize(), 10))
+
+	if ctx.GetHeader("Tus-Resumable") != tusVersion {
+		utils.AbortWithError(ctx, http.StatusPreconditionFailed, "対応していないtusのバージョンです")
+		return
+	}
+	ctx.Next()
+}
+
+// Create アップロードを作成（creation拡張）
+// Upload-Metadataにはfilename、work_idを指定する（filetypeは無視し、形式はファイル名の拡張子から決める）
+func (c *UploadController) Create(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	length, err := strconv.ParseInt(ctx.GetHeader("Upload-Length"), 10, 64)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "Upload-Lengthを指定してください")
+		return
+	}
+
+	metadata := parseUploadMetadata(ctx.GetHeader("Upload-Metadata"))
+	workID, err := strconv.ParseUint(metadata["work_id"], 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "Upload-Metadataにwork_idを指定してください")
+		return
+	}
+
+	upload, err := c.assetService.CreateUpload(ctx.Request.Context(), u.ID, uint(workID), metadata["filename"], length)
+	if err != nil {
+		respondUploadError(ctx, err)
+		return
+	}
+
+	ctx.Header("Location", strings.TrimSuffix(ctx.Request.URL.Path, "/")+"/"+upload.ID)
+	ctx.Header("Upload-Offset", "0")
+	ctx.Status(http.StatusCreated)
+}
+
+// Head アップロードの受け取り済みバイト数を取得
+func (c *UploadController) Head(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	upload, err := c.assetService.GetUpload(ctx.Request.Context(), ctx.Param("id"), u.ID)
+	if err != nil {
+		respondUploadError(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	ctx.Header("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	ctx.Status(http.StatusOK)
+}
+
+// Patch アップロードのデータを受け取る
+func (c *UploadController) Patch(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if ctx.ContentType() != "application/offset+octet-stream" {
+		utils.RespondError(ctx, http.StatusUnsupportedMediaType, "Content-Typeはapplication/offset+octet-streamである必要があります")
+		return
+	}
+	offset, err := strconv.ParseInt(ctx.GetHeader("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		utils.RespondError(ctx, http.StatusBadRequest, "Upload-Offsetを指定してください")
+		return
+	}
+
+	upload, err := c.assetService.WriteChunk(ctx.Request.Context(), ctx.Param("id"), u.ID, offset, ctx.Request.Body)
+	if err != nil {
+		respondUploadError(ctx, err)
+		return
+	}
+
+	ctx.Header("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	ctx.Status(http.StatusNoContent)
+}
+
+// Delete アップロードを中止（termination拡張）
+func (c *UploadController) Delete(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	if err := c.assetService.DeleteUpload(ctx.Request.Context(), ctx.Param("id"), u.ID); err != nil {
+		respondUploadError(ctx, err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListByWork 作品のアセット一覧を取得
+func (c *UploadController) ListByWork(ctx *gin.Context) {
+	workID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な作品IDです")
+		return
+	}
+
+	assets, err := c.assetService.ListByWork(ctx.Request.Context(), uint(workID))
+	if err != nil {
+		respondUploadError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "assets", assets)
+}
+
+// respondUploadError アップロードのエラーを対応するステータスで返す
+func respondUploadError(ctx *gin.Context, err error) {
+	message := err.Error()
+	switch {
+	case strings.Contains(message, "見つかりません"):
+		utils.RespondError(ctx, http.StatusNotFound, message)
+	case strings.Contains(message, "権限がありません"):
+		utils.RespondError(ctx, http.StatusForbidden, message)
+	case strings.Contains(message, "オフセットが一致しません"), strings.Contains(message, "中止できません"):
+		utils.RespondError(ctx, http.StatusConflict, message)
+	case strings.Contains(message, "上限"):
+		utils.RespondError(ctx, http.StatusRequestEntityTooLarge, message)
+	case strings.Contains(message, "失敗しました"):
+		utils.RespondError(ctx, http.StatusInternalServerError, message)
+	default:
+		utils.RespondError(ctx, http.StatusBadRequest, message)
+	}
+}
+
+// parseUploadMetadata Upload-Metadataヘッダー（"key base64値"のカンマ区切り）を解析
+func parseUploadMetadata(header string) map[string]string {
+	metadata := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		fields := strings.Fields(pair)
+		if len(fields) == 0 {
+			continue
+		}
+		value := ""
+		if len(fields) > 1 {
+			decoded, err := base64.StdEncoding.DecodeString(fields[1])
+			if err != nil {
+				continue
+			}
+			value = string(decoded)
+		}
+		metadata[fields[0]] = value
+	}
+	return metadata
+}
@@ -3,42 +3,66 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 // UserController ユーザーに関するコントローラー
 type UserController struct {
-	userService services.UserService
+	userService            services.UserService
+	reputationService      services.ReputationService
+	storageQuotaService    services.StorageQuotaService
+	conversionQuotaService services.ConversionQuotaService
 }
 
 // NewUserController UserControllerを作成
-func NewUserController(userService services.UserService) *UserController {
+func NewUserController(
+	userService services.UserService,
+	reputationService services.ReputationService,
+	storageQuotaService services.StorageQuotaService,
+	conversionQuotaService services.ConversionQuotaService,
+) *UserController {
 	return &UserController{
-		userService: userService,
+		userService:            userService,
+		reputationService:      reputationService,
+		storageQuotaService:    storageQuotaService,
+		conversionQuotaService: conversionQuotaService,
 	}
 }
 
 // GetByID IDでユーザーを取得
 func (c *UserController) GetByID(ctx *gin.Context) {
+	// @から始まる場合はハンドルで取得（/users/@handle）
+	if handle := ctx.Param("id"); strings.HasPrefix(handle, "@") {
+		user, err := c.userService.GetByHandle(ctx.Request.Context(), handle)
+		if err != nil {
+			utils.RespondError(ctx, http.StatusNotFound, "ユーザーが見つかりません")
+			return
+		}
+		utils.Respond(ctx, http.StatusOK, "", user)
+		return
+	}
+
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザーを取得
-	user, err := c.userService.GetByID(uint(id))
+	user, err := c.userService.GetByID(ctx.Request.Context(), uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "ユーザーが見つかりません"})
+		utils.RespondError(ctx, http.StatusNotFound, "ユーザーが見つかりません")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	utils.Respond(ctx, http.StatusOK, "", user)
 }
 
 // GetMe 自分のユーザー情報を取得
@@ -46,11 +70,11 @@ func (c *UserController) GetMe(ctx *gin.Context) {
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	utils.Respond(ctx, http.StatusOK, "", user)
 }
 
 // UpdateProfile 自分のプロフィールを更新
@@ -58,7 +82,7 @@ func (c *UserController) UpdateProfile(ctx *gin.Context) {
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
@@ -70,16 +94,342 @@ func (c *UserController) UpdateProfile(ctx *gin.Context) {
 		Bio      string `json:"bio"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// プロフィールを更新
-	updatedUser, err := c.userService.UpdateProfile(u.ID, req.Name, req.Nickname, req.Bio)
+	updatedUser, err := c.userService.UpdateProfile(ctx.Request.Context(), u.ID, req.Name, req.Nickname, req.Bio)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "", updatedUser)
+}
+
+// UploadAvatar 自分のアバター画像をアップロード（multipart/form-dataのavatarファイル）
+// レスポンスには変換で作成した派生画像を含める
+func (c *UserController) UploadAvatar(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	avatarHeader, err := ctx.FormFile("avatar")
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "アバター画像（avatar）は必須です")
+		return
+	}
+	avatarFile, err := avatarHeader.Open()
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "アバター画像を開けませんでした")
+		return
+	}
+	defer avatarFile.Close()
+
+	image, err := c.userService.UpdateAvatar(ctx.Request.Context(), u.ID, avatarFile)
+	if err != nil {
+		respondImageError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "image", image)
+}
+
+// ChangeHandle 自分のハンドルを変更
+func (c *UserController) ChangeHandle(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// リクエストをバインド
+	var req struct {
+		Handle string `json:"handle" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updatedUser, err := c.userService.ChangeHandle(ctx.Request.Context(), u.ID, req.Handle)
+	if err != nil {
+		if strings.Contains(err.Error(), "既に使用されています") {
+			utils.RespondError(ctx, http.StatusConflict, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "", updatedUser)
+}
+
+// Ranking レピュテーション順のユーザー一覧を取得
+func (c *UserController) Ranking(ctx *gin.Context) {
+	// クエリパラメータを取得
+	pageStr := ctx.DefaultQuery("page", "1")
+	limitStr := ctx.DefaultQuery("limit", "20")
+
+	// 数値パラメータを解析
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// ランキングを取得
+	users, total, pages, err := c.reputationService.Ranking(ctx.Request.Context(), page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "users", users, total, page, limit, pages, nil)
+}
+
+// GetReputation ユーザーのレピュテーションと内訳を取得（再計算はしない）
+func (c *UserController) GetReputation(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	reputation, sources, err := c.reputationService.Breakdown(ctx.Request.Context(), uint(id))
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "", gin.H{
+		"reputation": reputation,
+		"breakdown":  sources,
+	})
+}
+
+// RecalculateReputation 集計値からユーザーのレピュテーションを再計算して保存（管理者用）
+func (c *UserController) RecalculateReputation(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	reputation, sources, err := c.reputationService.Recalculate(ctx.Request.Context(), uint(id))
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "", gin.H{
+		"reputation": reputation,
+		"breakdown":  sources,
+	})
+}
+
+// GetActivityCalendar ユーザーの過去1年間の日ごとの活動量を取得（ヒートマップ表示用）
+func (c *UserController) GetActivityCalendar(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	calendar, err := c.userService.GetActivityCalendar(ctx.Request.Context(), uint(id))
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "calendar", calendar)
+}
+
+// GetQuota 現在のユーザーのストレージ使用量と上限を取得
+func (c *UserController) GetQuota(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	storage, err := c.storageQuotaService.GetQuota(ctx.Request.Context(), u.ID)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	conversions, err := c.conversionQuotaService.Status(ctx.Request.Context(), u.ID)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "", gin.H{
+		"storage":     storage,
+		"conversions": conversions,
+	})
+}
+
+// GetPrivacy 自分のプライバシー設定を取得
+func (c *UserController) GetPrivacy(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	privacy, err := c.userService.GetPrivacy(ctx.Request.Context(), u.ID)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "privacy", privacy)
+}
+
+// UpdatePrivacy 自分のプライバシー設定を変更（指定した項目のみ）
+func (c *UserController) UpdatePrivacy(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// リクエストをバインド
+	var req services.PrivacyUpdate
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	privacy, err := c.userService.UpdatePrivacy(ctx.Request.Context(), u.ID, req)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, updatedUser)
+	utils.Respond(ctx, http.StatusOK, "privacy", privacy)
+}
+
+// GetLikedWorks ユーザーがいいねした作品一覧を取得
+func (c *UserController) GetLikedWorks(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// 非公開の設定でも本人は閲覧できるため、ログインしている場合はユーザーIDを渡す
+	var viewerID *uint
+	if user, exists := ctx.Get("user"); exists {
+		viewerID = &user.(*models.User).ID
+	}
+
+	works, total, pages, err := c.userService.GetLikedWorks(ctx.Request.Context(), uint(id), viewerID, page, limit)
+	if err != nil {
+		respondPrivacyError(ctx, err)
+		return
+	}
+
+	respondPaginated(ctx, "works", works, total, page, limit, pages, nil)
+}
+
+// GetContests ユーザーが参加しているコンテスト一覧を取得
+func (c *UserController) GetContests(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// 非公開の設定でも本人は閲覧できるため、ログインしている場合はユーザーIDを渡す
+	var viewerID *uint
+	if user, exists := ctx.Get("user"); exists {
+		viewerID = &user.(*models.User).ID
+	}
+
+	projects, total, pages, err := c.userService.GetContests(ctx.Request.Context(), uint(id), viewerID, page, limit)
+	if err != nil {
+		respondPrivacyError(ctx, err)
+		return
+	}
+
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, projects)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "projects", items, total, page, limit, pages, nil)
+}
+
+// respondPrivacyError プライバシー設定で制限された一覧のエラーを返す
+func respondPrivacyError(ctx *gin.Context, err error) {
+	if strings.Contains(err.Error(), "権限がありません") {
+		utils.RespondError(ctx, http.StatusForbidden, err.Error())
+		return
+	}
+	if strings.Contains(err.Error(), "見つかりません") {
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+	utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 }
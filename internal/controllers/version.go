@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// respondVersionConflict 楽観的ロックの競合の場合は最新のバージョンを含めて409を返す
+func respondVersionConflict(ctx *gin.Context, err error) bool {
+	var conflict *services.VersionConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	utils.RespondErrorWithMeta(ctx, http.StatusConflict, conflict.Error(), gin.H{"current_version": conflict.CurrentVersion})
+	return true
+}
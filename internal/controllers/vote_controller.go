@@ -1,12 +1,16 @@
 package controllers
 
 import (
+	"encoding/csv"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -25,10 +29,12 @@ func NewVoteController(voteService services.VoteService) *VoteController {
 
 // VoteRequest 投票作成・更新リクエスト
 type VoteRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
-	TaskID      uint   `json:"task_id" binding:"required"`
-	MultiSelect bool   `json:"multi_select"`
+	Title       string     `json:"title" binding:"required"`
+	Description string     `json:"description"`
+	TaskID      uint       `json:"task_id" binding:"required"`
+	MultiSelect bool       `json:"multi_select"`
+	Anonymous   bool       `json:"anonymous"` // 作成時のみ指定できる
+	ClosesAt    *time.Time `json:"closes_at"`
 }
 
 // Create 新しい投票を作成
@@ -36,7 +42,7 @@ func (c *VoteController) Create(ctx *gin.Context) {
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
@@ -44,22 +50,22 @@ func (c *VoteController) Create(ctx *gin.Context) {
 	// リクエストをバインド
 	var req VoteRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// 投票を作成
-	vote, err := c.voteService.Create(req.Title, req.Description, req.TaskID, req.MultiSelect, u.ID)
+	vote, err := c.voteService.Create(ctx.Request.Context(), req.Title, req.Description, req.TaskID, req.MultiSelect, req.Anonymous, req.ClosesAt, u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{"vote": vote})
+	utils.Respond(ctx, http.StatusCreated, "vote", vote)
 }
 
 // GetByID IDで投票を取得
@@ -67,30 +73,30 @@ func (c *VoteController) GetByID(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// 投票を取得
-	vote, err := c.voteService.GetByID(uint(id), u.ID)
+	vote, err := c.voteService.GetByID(ctx.Request.Context(), uint(id), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"vote": vote})
+	utils.Respond(ctx, http.StatusOK, "vote", vote)
 }
 
 // Update 投票を更新
@@ -98,41 +104,42 @@ func (c *VoteController) Update(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// リクエストをバインド
 	var req struct {
-		Title       string `json:"title" binding:"required"`
-		Description string `json:"description"`
-		MultiSelect bool   `json:"multi_select"`
+		Title       string     `json:"title" binding:"required"`
+		Description string     `json:"description"`
+		MultiSelect bool       `json:"multi_select"`
+		ClosesAt    *time.Time `json:"closes_at"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// 投票を更新
-	vote, err := c.voteService.Update(uint(id), u.ID, req.Title, req.Description, req.MultiSelect)
+	vote, err := c.voteService.Update(ctx.Request.Context(), uint(id), u.ID, req.Title, req.Description, req.MultiSelect, req.ClosesAt)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"vote": vote})
+	utils.Respond(ctx, http.StatusOK, "vote", vote)
 }
 
 // Delete 投票を削除
@@ -140,26 +147,26 @@ func (c *VoteController) Delete(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// 投票を削除
-	err = c.voteService.Delete(uint(id), u.ID)
+	err = c.voteService.Delete(ctx.Request.Context(), uint(id), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
@@ -171,30 +178,30 @@ func (c *VoteController) ListByTask(ctx *gin.Context) {
 	// タスクIDを解析
 	taskID, err := strconv.ParseUint(ctx.Param("taskID"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なタスクIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なタスクIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// 投票一覧を取得
-	votes, err := c.voteService.ListByTask(uint(taskID), u.ID)
+	votes, err := c.voteService.ListByTask(ctx.Request.Context(), uint(taskID), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"votes": votes})
+	utils.Respond(ctx, http.StatusOK, "votes", votes)
 }
 
 // AddOption 投票オプションを追加
@@ -202,7 +209,7 @@ func (c *VoteController) AddOption(ctx *gin.Context) {
 	// 投票IDを解析
 	voteID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効な投票IDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な投票IDです")
 		return
 	}
 
@@ -212,30 +219,30 @@ func (c *VoteController) AddOption(ctx *gin.Context) {
 		WorkID     *uint  `json:"work_id"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// オプションを追加
-	option, err := c.voteService.AddOption(uint(voteID), u.ID, req.OptionText, req.WorkID)
+	option, err := c.voteService.AddOption(ctx.Request.Context(), uint(voteID), u.ID, req.OptionText, req.WorkID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{"option": option})
+	utils.Respond(ctx, http.StatusCreated, "option", option)
 }
 
 // DeleteOption 投票オプションを削除
@@ -243,33 +250,33 @@ func (c *VoteController) DeleteOption(ctx *gin.Context) {
 	// 投票IDを解析
 	// voteID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	// if err != nil {
-	// 	ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効な投票IDです"})
+	// 	utils.RespondError(ctx, http.StatusBadRequest, "無効な投票IDです")
 	// 	return
 	// }
 
 	// オプションIDを解析
 	optionID, err := strconv.ParseUint(ctx.Param("optionID"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なオプションIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なオプションIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// オプションを削除
-	err = c.voteService.DeleteOption(uint(optionID), u.ID)
+	err = c.voteService.DeleteOption(ctx.Request.Context(), uint(optionID), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -281,7 +288,7 @@ func (c *VoteController) Vote(ctx *gin.Context) {
 	// 投票IDを解析
 	voteID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効な投票IDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な投票IDです")
 		return
 	}
 
@@ -290,26 +297,30 @@ func (c *VoteController) Vote(ctx *gin.Context) {
 		OptionID uint `json:"option_id" binding:"required"`
 	}
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// 投票
-	err = c.voteService.Vote(uint(voteID), req.OptionID, u.ID)
+	err = c.voteService.Vote(ctx.Request.Context(), uint(voteID), req.OptionID, u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if strings.Contains(err.Error(), "競合しました") {
+			utils.RespondError(ctx, http.StatusConflict, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -321,33 +332,33 @@ func (c *VoteController) RemoveVote(ctx *gin.Context) {
 	// 投票IDを解析
 	voteID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効な投票IDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な投票IDです")
 		return
 	}
 
 	// オプションIDを解析
 	optionID, err := strconv.ParseUint(ctx.Param("optionID"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なオプションIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なオプションIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// 投票を削除
-	err = c.voteService.RemoveVote(uint(voteID), uint(optionID), u.ID)
+	err = c.voteService.RemoveVote(ctx.Request.Context(), uint(voteID), uint(optionID), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -359,30 +370,30 @@ func (c *VoteController) GetUserVotes(ctx *gin.Context) {
 	// 投票IDを解析
 	voteID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効な投票IDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な投票IDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// ユーザーの投票を取得
-	responses, err := c.voteService.GetUserVotes(uint(voteID), u.ID)
+	responses, err := c.voteService.GetUserVotes(ctx.Request.Context(), uint(voteID), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"votes": responses})
+	utils.Respond(ctx, http.StatusOK, "votes", responses)
 }
 
 // CloseVote 投票を終了
@@ -390,28 +401,111 @@ func (c *VoteController) CloseVote(ctx *gin.Context) {
 	// 投票IDを解析
 	voteID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効な投票IDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効な投票IDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// 投票を終了
-	err = c.voteService.CloseVote(uint(voteID), u.ID)
+	err = c.voteService.CloseVote(ctx.Request.Context(), uint(voteID), u.ID)
 	if err != nil {
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// voteExportCSVHeader エクスポートする投票結果のCSVのヘッダー
+// オプションごとの集計行（user_id以降は空）の後に、匿名でない場合は回答ごとの行（vote_countは空）が続く
+var voteExportCSVHeader = []string{"option_id", "option_text", "work_id", "vote_count", "user_id", "nickname", "voted_at"}
+
+// ExportResults 投票の結果をCSVでエクスポート
+func (c *VoteController) ExportResults(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// 権限を確認してからヘッダーと集計行を書き出し、回答は少しずつ書き出す
+	csvWriter := csv.NewWriter(ctx.Writer)
+	started := false
+	options := map[uint]models.VoteOption{}
+	err = c.voteService.ExportResults(ctx.Request.Context(), uint(id), u.ID, func(vote *models.Vote) error {
+		started = true
+		ctx.Header("Content-Type", "text/csv; charset=utf-8")
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=vote_%d_results.csv", vote.ID))
+		ctx.Status(http.StatusOK)
+
+		csvWriter.Write(voteExportCSVHeader)
+		for _, option := range vote.Options {
+			options[option.ID] = option
+			workID := ""
+			if option.WorkID != nil {
+				workID = strconv.FormatUint(uint64(*option.WorkID), 10)
+			}
+			csvWriter.Write(utils.CSVSafeRecord([]string{
+				strconv.FormatUint(uint64(option.ID), 10),
+				option.OptionText,
+				workID,
+				strconv.FormatInt(option.VoteCount, 10),
+				"", "", "",
+			}))
+		}
+		csvWriter.Flush()
+		ctx.Writer.Flush()
+		return csvWriter.Error()
+	}, func(responses []models.VoteResponse) error {
+		for _, response := range responses {
+			csvWriter.Write(utils.CSVSafeRecord([]string{
+				strconv.FormatUint(uint64(response.OptionID), 10),
+				options[response.OptionID].OptionText,
+				"",
+				"",
+				strconv.FormatUint(uint64(response.UserID), 10),
+				response.User.Nickname,
+				response.CreatedAt.Format(time.RFC3339),
+			}))
+		}
+		csvWriter.Flush()
+		ctx.Writer.Flush()
+		return csvWriter.Error()
+	})
+	if err != nil && !started {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err != nil {
+		// 書き出し開始後のエラーはステータスを変更できないため中断のみ
+		ctx.Error(err)
+	}
+}
@@ -1,28 +1,86 @@
 package controllers
 
 import (
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 // WorkController 作品に関するコントローラー
 type WorkController struct {
-	workService services.WorkService
+	workService            services.WorkService
+	workImportService      services.WorkImportService
+	conversionQuotaService services.ConversionQuotaService
+	conversionJobService   services.ConversionJobService
+	imageService           services.ImageService
+	videoService           services.VideoService
 }
 
 // NewWorkController WorkControllerを作成
-func NewWorkController(workService services.WorkService) *WorkController {
+func NewWorkController(workService services.WorkService, workImportService services.WorkImportService, conversionQuotaService services.ConversionQuotaService, conversionJobService services.ConversionJobService, imageService services.ImageService, videoService services.VideoService) *WorkController {
 	return &WorkController{
-		workService: workService,
+		workService:            workService,
+		workImportService:      workImportService,
+		conversionQuotaService: conversionQuotaService,
+		conversionJobService:   conversionJobService,
+		imageService:           imageService,
+		videoService:           videoService,
 	}
 }
 
+// setConversionQuotaHeaders PDE変換回数の状況をレスポンスヘッダーに設定
+// 上限に達している場合はtrueを返す
+func (c *WorkController) setConversionQuotaHeaders(ctx *gin.Context, userID uint) bool {
+	quota, err := c.conversionQuotaService.Status(ctx.Request.Context(), userID)
+	if err != nil {
+		return false
+	}
+
+	if !quota.Unlimited() {
+		ctx.Header("X-RateLimit-Limit", strconv.Itoa(quota.Limit))
+		ctx.Header("X-RateLimit-Remaining", strconv.Itoa(quota.Remaining))
+		ctx.Header("X-RateLimit-Reset", strconv.FormatInt(quota.ResetAt.Unix(), 10))
+	}
+
+	if !quota.Exhausted() {
+		return false
+	}
+
+	retryAfter := int(time.Until(quota.RetryAt()).Seconds()) + 1
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+	return true
+}
+
+// respondLimitExceeded 変換回数・サイズ・ストレージ容量の上限超過エラーを返す
+func (c *WorkController) respondLimitExceeded(ctx *gin.Context, userID uint, err error) bool {
+	switch {
+	case strings.Contains(err.Error(), "変換回数の上限"):
+		c.setConversionQuotaHeaders(ctx, userID)
+		utils.RespondError(ctx, http.StatusTooManyRequests, err.Error())
+	case strings.Contains(err.Error(), "サイズが上限"):
+		utils.RespondError(ctx, http.StatusRequestEntityTooLarge, err.Error())
+	case strings.Contains(err.Error(), "ストレージ容量の上限"):
+		utils.RespondError(ctx, http.StatusForbidden, err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
 // Create 新しい作品を作成
 func (c *WorkController) Create(ctx *gin.Context) {
 	// JSONリクエストをバインド
@@ -32,40 +90,274 @@ func (c *WorkController) Create(ctx *gin.Context) {
 		PDEContent   string   `json:"pde_content" binding:"required"`
 		ThumbnailURL string   `json:"thumbnail_url"`
 		CodeShared   bool     `json:"code_shared"`
+		License      string   `json:"license"`
+		Language     string   `json:"language"`
 		Tags         []string `json:"tags"`
 		TaskID       *uint    `json:"task_id"`
 	}
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
 	// 作品を作成
 	work, err := c.workService.Create(
+		ctx.Request.Context(),
 		req.Title,
 		req.Description,
 		req.PDEContent,
 		req.ThumbnailURL,
+		"",
 		req.CodeShared,
+		req.License,
+		req.Language,
 		req.Tags,
 		req.TaskID,
 		u.ID,
 	)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		if c.respondLimitExceeded(ctx, u.ID, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.setConversionQuotaHeaders(ctx, u.ID)
+	utils.Respond(ctx, http.StatusCreated, "work", work)
+}
+
+// ImportURL GitHub Gist・GitHubのファイル・.pdeファイルのURLからコードを取り込んで作品を作成
+func (c *WorkController) ImportURL(ctx *gin.Context) {
+	// JSONリクエストをバインド
+	var req struct {
+		URL         string   `json:"url" binding:"required"`
+		Title       string   `json:"title"` // 省略した場合はGistの説明文かファイル名
+		Description string   `json:"description"`
+		CodeShared  bool     `json:"code_shared"`
+		License     string   `json:"license"`
+		Language    string   `json:"language"`
+		Tags        []string `json:"tags"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	work, err := c.workImportService.ImportURL(
+		ctx.Request.Context(),
+		req.URL,
+		req.Title,
+		req.Description,
+		req.CodeShared,
+		req.License,
+		req.Language,
+		req.Tags,
+		u.ID,
+	)
+	if err != nil {
+		if c.respondLimitExceeded(ctx, u.ID, err) {
+			return
+		}
+		switch {
+		case strings.Contains(err.Error(), "権限がありません"):
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+		case strings.Contains(err.Error(), "見つかりません"):
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		case strings.Contains(err.Error(), "取得に失敗しました"):
+			utils.RespondError(ctx, http.StatusBadGateway, err.Error())
+		case strings.Contains(err.Error(), "保存に失敗しました"):
+			utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		default:
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	c.setConversionQuotaHeaders(ctx, u.ID)
+	utils.Respond(ctx, http.StatusCreated, "work", work)
+}
+
+// Preview 編集中のPDEコードを保存せずにJavaScriptへ変換
+func (c *WorkController) Preview(ctx *gin.Context) {
+	// JSONリクエストをバインド
+	var req struct {
+		PDEContent string `json:"pde_content" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	preview, err := c.workService.Preview(ctx.Request.Context(), u.ID, req.PDEContent)
+	if err != nil {
+		if c.respondLimitExceeded(ctx, u.ID, err) {
+			return
+		}
+		if errors.Is(err, services.ErrCircuitOpen) {
+			utils.RespondError(ctx, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "PDE変換に失敗しました") {
+			utils.RespondError(ctx, http.StatusBadGateway, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.setConversionQuotaHeaders(ctx, u.ID)
+	utils.Respond(ctx, http.StatusOK, "preview", preview)
+}
+
+// Upload PDEファイル（とサムネイル画像）をmultipart/form-dataで受け取って作品を作成
+func (c *WorkController) Upload(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// PDEファイルを読み込み
+	pdeHeader, err := ctx.FormFile("pde_file")
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "PDEファイル（pde_file）は必須です")
+		return
+	}
+	if !strings.EqualFold(filepath.Ext(pdeHeader.Filename), ".pde") {
+		utils.RespondError(ctx, http.StatusBadRequest, "PDEファイルの拡張子は.pdeである必要があります")
+		return
+	}
+	pdeFile, err := pdeHeader.Open()
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "PDEファイルを開けませんでした")
+		return
+	}
+	defer pdeFile.Close()
+	pdeContent, err := io.ReadAll(pdeFile)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "PDEファイルの読み込みに失敗しました")
+		return
+	}
+
+	// タイトルが指定されていない場合はファイル名を使用
+	title := ctx.PostForm("title")
+	if strings.TrimSpace(title) == "" {
+		title = strings.TrimSuffix(filepath.Base(pdeHeader.Filename), filepath.Ext(pdeHeader.Filename))
+	}
+
+	codeShared, _ := strconv.ParseBool(ctx.PostForm("code_shared"))
+
+	// タグはカンマ区切り、または複数指定
+	var tags []string
+	for _, value := range ctx.PostFormArray("tags") {
+		tags = append(tags, strings.Split(value, ",")...)
+	}
+
+	var taskID *uint
+	if taskIDStr := ctx.PostForm("task_id"); taskIDStr != "" {
+		id, err := strconv.ParseUint(taskIDStr, 10, 32)
+		if err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, "無効なタスクIDです")
+			return
+		}
+		tid := uint(id)
+		taskID = &tid
+	}
+
+	// サムネイル画像をアップロード（オプション）
+	// 設定された手順で縮小・WebPへの変換などを行い、最初の派生画像をサムネイルにする
+	thumbnailURL := ctx.PostForm("thumbnail_url")
+	thumbnailBlurhash := ""
+	var thumbnail *models.Image
+	if thumbnailHeader, err := ctx.FormFile("thumbnail"); err == nil {
+		thumbnailFile, err := thumbnailHeader.Open()
+		if err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, "サムネイル画像を開けませんでした")
+			return
+		}
+		defer thumbnailFile.Close()
+
+		thumbnail, err = c.imageService.Upload(ctx.Request.Context(), u.ID, models.ImageKindThumbnail, thumbnailFile)
+		if err != nil {
+			respondImageError(ctx, err)
+			return
+		}
+		thumbnailURL = thumbnail.URL
+		if len(thumbnail.Variants) > 0 {
+			thumbnailURL = thumbnail.Variants[0].URL
+		}
+		thumbnailBlurhash = thumbnail.Blurhash
+	}
+
+	// 作品を作成
+	work, err := c.workService.Create(
+		ctx.Request.Context(),
+		title,
+		ctx.PostForm("description"),
+		string(pdeContent),
+		thumbnailURL,
+		thumbnailBlurhash,
+		codeShared,
+		ctx.PostForm("license"),
+		ctx.PostForm("language"),
+		tags,
+		taskID,
+		u.ID,
+	)
+	if err != nil {
+		// アップロード済みのサムネイル画像を削除
+		if thumbnail != nil {
+			c.imageService.Delete(ctx.Request.Context(), thumbnail.ID)
+		}
+		if c.respondLimitExceeded(ctx, u.ID, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, gin.H{"work": work})
+	work.ThumbnailImage = thumbnail
+
+	c.setConversionQuotaHeaders(ctx, u.ID)
+	utils.Respond(ctx, http.StatusCreated, "work", work)
 }
 
 // GetByID IDで作品を取得
@@ -73,18 +365,24 @@ func (c *WorkController) GetByID(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// 作品を取得
-	work, err := c.workService.GetByID(uint(id))
+	work, err := c.workService.GetByID(ctx.Request.Context(), uint(id))
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "作品が見つかりません"})
+		utils.RespondError(ctx, http.StatusNotFound, "作品が見つかりません")
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"work": work})
+	// 通報により非表示の作品は、管理者が確認するまで表示しない
+	if work.HiddenAt != nil {
+		utils.RespondError(ctx, http.StatusNotFound, "作品が見つかりません")
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "work", work)
 }
 
 // Update 作品を更新
@@ -92,14 +390,14 @@ func (c *WorkController) Update(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
@@ -111,17 +409,21 @@ func (c *WorkController) Update(ctx *gin.Context) {
 		PDEContent   string   `json:"pde_content"`
 		ThumbnailURL string   `json:"thumbnail_url"`
 		CodeShared   bool     `json:"code_shared"`
+		License      string   `json:"license"`
+		Language     string   `json:"language"`
 		Tags         []string `json:"tags"`
 		TaskID       *uint    `json:"task_id"`
+		Version      *uint    `json:"version"`
 	}
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	// 作品を更新
 	work, err := c.workService.Update(
+		ctx.Request.Context(),
 		uint(id),
 		u.ID,
 		req.Title,
@@ -129,212 +431,774 @@ func (c *WorkController) Update(ctx *gin.Context) {
 		req.PDEContent,
 		req.ThumbnailURL,
 		req.CodeShared,
+		req.License,
+		req.Language,
 		req.Tags,
 		req.TaskID,
+		req.Version,
 	)
 	if err != nil {
+		if respondVersionConflict(ctx, err) {
+			return
+		}
+		if c.respondLimitExceeded(ctx, u.ID, err) {
+			return
+		}
 		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
 			return
 		}
 		if strings.Contains(err.Error(), "見つかりません") {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"work": work})
+	c.setConversionQuotaHeaders(ctx, u.ID)
+	utils.Respond(ctx, http.StatusOK, "work", work)
 }
 
-// Delete 作品を削除
-func (c *WorkController) Delete(ctx *gin.Context) {
-	// IDを解析
-	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+// Compare 複数の作品を並べて再生するための情報を取得
+func (c *WorkController) Compare(ctx *gin.Context) {
+	// 作品IDを解析（カンマ区切り）
+	var ids []uint
+	for _, idStr := range strings.Split(ctx.Query("ids"), ",") {
+		if idStr = strings.TrimSpace(idStr); idStr == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです: "+idStr)
+			return
+		}
+		ids = append(ids, uint(id))
+	}
+
+	// ログインしている場合は自分の作品のコードも含める
+	var viewerID *uint
+	if user, exists := ctx.Get("user"); exists {
+		viewerID = &user.(*models.User).ID
+	}
+
+	works, err := c.workService.Compare(ctx.Request.Context(), ids, viewerID)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	utils.Respond(ctx, http.StatusOK, "works", works)
+}
+
+// Trash 削除済みの自分の作品一覧を取得
+func (c *WorkController) Trash(ctx *gin.Context) {
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
-	// 作品を削除
-	if err := c.workService.Delete(uint(id), u.ID); err != nil {
-		if strings.Contains(err.Error(), "権限がありません") {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
-			return
-		}
-		if strings.Contains(err.Error(), "見つかりません") {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	works, total, pages, err := c.workService.ListTrash(ctx.Request.Context(), u.ID, page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.Status(http.StatusNoContent)
+	respondPaginated(ctx, "works", works, total, page, limit, pages, nil)
 }
 
-// List 作品一覧を取得
-func (c *WorkController) List(ctx *gin.Context) {
-	// クエリパラメータを取得
-	pageStr := ctx.DefaultQuery("page", "1")
-	limitStr := ctx.DefaultQuery("limit", "20")
-	search := ctx.Query("search")
-	tag := ctx.Query("tag")
-	userIDStr := ctx.Query("user_id")
-	sort := ctx.DefaultQuery("sort", "newest")
+// Liked 自分がいいねした作品一覧を取得
+func (c *WorkController) Liked(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
 
-	// 数値パラメータを解析
-	page, err := strconv.Atoi(pageStr)
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	if err != nil || page < 1 {
 		page = 1
 	}
 
-	limit, err := strconv.Atoi(limitStr)
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
 	if err != nil || limit < 1 || limit > 100 {
 		limit = 20
 	}
 
-	// ユーザーIDを解析（オプション）
-	var userID *uint
-	if userIDStr != "" {
-		id, err := strconv.ParseUint(userIDStr, 10, 32)
-		if err == nil {
-			uid := uint(id)
-			userID = &uid
-		}
-	}
-
-	// 作品一覧を取得
-	works, total, pages, err := c.workService.List(page, limit, search, tag, userID, sort)
+	works, total, pages, err := c.workService.ListLiked(ctx.Request.Context(), u.ID, page, limit)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"works": works,
-		"total": total,
-		"pages": pages,
-		"page":  page,
-	})
+	respondPaginated(ctx, "works", works, total, page, limit, pages, nil)
 }
 
-// HasLiked ユーザーがいいねしているか確認
-func (c *WorkController) HasLiked(ctx *gin.Context) {
+// Likers 作品にいいねしたユーザー一覧を取得
+func (c *WorkController) Likers(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
-	// ユーザー情報を取得
-	user, exists := ctx.Get("user")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
-		return
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
 	}
-	u := user.(*models.User)
 
-	// いいね状態を確認
-	liked, err := c.workService.HasLiked(u.ID, uint(id))
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	users, total, pages, err := c.workService.ListLikers(ctx.Request.Context(), uint(id), page, limit)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"liked": liked})
+	respondPaginated(ctx, "users", users, total, page, limit, pages, nil)
 }
 
-// AddLike いいねを追加
-func (c *WorkController) AddLike(ctx *gin.Context) {
+// Restore 削除済みの作品を復元
+func (c *WorkController) Restore(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
-	// いいねを追加
-	likesCount, err := c.workService.AddLike(u.ID, uint(id))
+	work, err := c.workService.Restore(ctx.Request.Context(), uint(id), u.ID)
 	if err != nil {
+		if c.respondLimitExceeded(ctx, u.ID, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
 		if strings.Contains(err.Error(), "見つかりません") {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "保持期間") {
+			utils.RespondError(ctx, http.StatusGone, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"likes_count": likesCount,
-	})
+	utils.Respond(ctx, http.StatusOK, "work", work)
 }
 
-// RemoveLike いいねを削除
-func (c *WorkController) RemoveLike(ctx *gin.Context) {
+// Fork 作品をフォーク
+func (c *WorkController) Fork(ctx *gin.Context) {
 	// IDを解析
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
 	// ユーザー情報を取得
 	user, exists := ctx.Get("user")
 	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
 		return
 	}
 	u := user.(*models.User)
 
-	// いいねを削除
-	likesCount, err := c.workService.RemoveLike(u.ID, uint(id))
+	// 作品をフォーク
+	work, err := c.workService.Fork(ctx.Request.Context(), uint(id), u.ID)
 	if err != nil {
+		if c.respondLimitExceeded(ctx, u.ID, err) {
+			return
+		}
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
 		if strings.Contains(err.Error(), "見つかりません") {
-			ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
 			return
 		}
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"likes_count": likesCount,
-	})
+	c.setConversionQuotaHeaders(ctx, u.ID)
+	utils.Respond(ctx, http.StatusCreated, "work", work)
 }
 
-// GetUserWorks ユーザーの作品一覧を取得
-func (c *WorkController) GetUserWorks(ctx *gin.Context) {
-	// ユーザーIDを解析
-	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+// AttachVideo 作品にデモ動画を添付
+// multipart/form-dataの場合はvideoファイルを、JSONの場合はアップロード済みのasset_idを受け取る
+func (c *WorkController) AttachVideo(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "無効なユーザーIDです"})
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
 		return
 	}
 
-	// クエリパラメータを取得
-	pageStr := ctx.DefaultQuery("page", "1")
-	limitStr := ctx.DefaultQuery("limit", "20")
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
 
-	// 数値パラメータを解析
+	var work *models.Work
+	if strings.HasPrefix(ctx.ContentType(), "multipart/") {
+		videoHeader, err := ctx.FormFile("video")
+		if err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, "動画ファイル（video）は必須です")
+			return
+		}
+		videoFile, err := videoHeader.Open()
+		if err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, "動画ファイルを開けませんでした")
+			return
+		}
+		defer videoFile.Close()
+
+		work, err = c.videoService.Upload(ctx.Request.Context(), uint(id), u.ID, videoHeader.Filename, videoHeader.Size, videoFile)
+	} else {
+		var req struct {
+			AssetID uint `json:"asset_id" binding:"required"`
+		}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		work, err = c.videoService.Attach(ctx.Request.Context(), uint(id), u.ID, req.AssetID)
+	}
+	if err != nil {
+		respondVideoError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "work", work)
+}
+
+// DetachVideo 作品のデモ動画を外す
+func (c *WorkController) DetachVideo(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	work, err := c.videoService.Detach(ctx.Request.Context(), uint(id), u.ID)
+	if err != nil {
+		respondVideoError(ctx, err)
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "work", work)
+}
+
+// respondVideoError デモ動画のエラーを対応するステータスで返す
+func respondVideoError(ctx *gin.Context, err error) {
+	switch {
+	case strings.Contains(err.Error(), "権限がありません"):
+		utils.RespondError(ctx, http.StatusForbidden, err.Error())
+	case strings.Contains(err.Error(), "見つかりません"):
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+	case strings.Contains(err.Error(), "上限"):
+		utils.RespondError(ctx, http.StatusRequestEntityTooLarge, err.Error())
+	case strings.Contains(err.Error(), "失敗しました"):
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+	default:
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+	}
+}
+
+// Delete 作品を削除
+func (c *WorkController) Delete(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// 作品を削除
+	if err := c.workService.Delete(ctx.Request.Context(), uint(id), u.ID); err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// List 作品一覧を取得
+func (c *WorkController) List(ctx *gin.Context) {
+	// クエリパラメータを取得
+	pageStr := ctx.DefaultQuery("page", "1")
+	limitStr := ctx.DefaultQuery("limit", "20")
+	search := ctx.Query("search")
+	tag := ctx.Query("tag")
+	userIDStr := ctx.Query("user_id")
+	sort := ctx.DefaultQuery("sort", "newest")
+	license := ctx.Query("license")
+	language := ctx.Query("language")
+
+	// 数値パラメータを解析
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// ユーザーIDを解析（オプション）
+	var userID *uint
+	if userIDStr != "" {
+		id, err := strconv.ParseUint(userIDStr, 10, 32)
+		if err == nil {
+			uid := uint(id)
+			userID = &uid
+		}
+	}
+
+	// ライセンスを確認（オプション）
+	if license != "" && !models.IsValidWorkLicense(license) {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なライセンスです: "+license)
+		return
+	}
+
+	// 言語を確認（オプション、ja-JPなどは地域を除いて絞り込む）
+	if language != "" {
+		code, ok := models.NormalizeWorkLanguage(language)
+		if !ok {
+			utils.RespondError(ctx, http.StatusBadRequest, "無効な言語です: "+language)
+			return
+		}
+		language = code
+	}
+
+	// 作品一覧を取得
+	works, total, pages, err := c.workService.List(ctx.Request.Context(), page, limit, search, tag, license, language, userID, sort)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, works)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "works", items, total, page, limit, pages, nil)
+}
+
+// ListFeatured ピックアップ中の作品一覧を取得
+func (c *WorkController) ListFeatured(ctx *gin.Context) {
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	works, total, pages, err := c.workService.ListFeatured(ctx.Request.Context(), page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, works)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "works", items, total, page, limit, pages, nil)
+}
+
+// SetFeatured 作品をピックアップに設定（管理者用）
+func (c *WorkController) SetFeatured(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// リクエストをバインド（省略した場合は現在から既定の期間）
+	var req struct {
+		FeaturedFrom  *time.Time `json:"featured_from"`
+		FeaturedUntil *time.Time `json:"featured_until"`
+	}
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	work, err := c.workService.SetFeatured(ctx.Request.Context(), uint(id), req.FeaturedFrom, req.FeaturedUntil)
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "work", work)
+}
+
+// UnsetFeatured 作品のピックアップを解除（管理者用）
+func (c *WorkController) UnsetFeatured(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	work, err := c.workService.UnsetFeatured(ctx.Request.Context(), uint(id))
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "work", work)
+}
+
+// UpdateMadeAt 作品を制作したイベント・場所を設定（作成者と編集権限のある共同編集者のみ）
+func (c *WorkController) UpdateMadeAt(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	var req services.MadeAtInput
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	work, err := c.workService.UpdateMadeAt(ctx.Request.Context(), uint(id), u.ID, req)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		if strings.Contains(err.Error(), "失敗しました") {
+			utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "work", work)
+}
+
+// ListByEvent イベントで制作された作品一覧を取得
+func (c *WorkController) ListByEvent(ctx *gin.Context) {
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	works, total, pages, err := c.workService.ListByEvent(ctx.Request.Context(), ctx.Param("event"), page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, works)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "works", items, total, page, limit, pages, nil)
+}
+
+// ListEvents 作品が登録されているイベント一覧を取得
+func (c *WorkController) ListEvents(ctx *gin.Context) {
+	events, err := c.workService.ListEvents(ctx.Request.Context())
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "events", events)
+}
+
+// ListLanguages 作品の言語一覧を作品数とともに取得
+func (c *WorkController) ListLanguages(ctx *gin.Context) {
+	languages, err := c.workService.ListLanguages(ctx.Request.Context())
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "languages", languages)
+}
+
+// Map 制作した場所の座標がある作品をGeoJSONで取得（地図ライブラリでそのまま読み込めるよう、レスポンスの形式によらずGeoJSONを返す）
+func (c *WorkController) Map(ctx *gin.Context) {
+	collection, err := c.workService.Map(ctx.Request.Context(), ctx.Query("event"))
+	if err != nil {
+		if strings.Contains(err.Error(), "イベント") {
+			utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	body, err := json.Marshal(collection)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+	ctx.Data(http.StatusOK, "application/geo+json; charset=utf-8", body)
+}
+
+// HasLiked ユーザーがいいねしているか確認
+func (c *WorkController) HasLiked(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// いいね状態を確認
+	liked, err := c.workService.HasLiked(ctx.Request.Context(), u.ID, uint(id))
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "liked", liked)
+}
+
+// ConversionStatus 作成・更新時に失敗した変換の再試行状況を取得（作者のみ）
+func (c *WorkController) ConversionStatus(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	job, err := c.conversionJobService.GetByWork(ctx.Request.Context(), uint(id), u.ID)
+	if err != nil {
+		if strings.Contains(err.Error(), "権限がありません") {
+			utils.RespondError(ctx, http.StatusForbidden, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "conversion_job", job)
+}
+
+// AddLike いいねを追加
+func (c *WorkController) AddLike(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// いいねを追加
+	likesCount, err := c.workService.AddLike(ctx.Request.Context(), u.ID, uint(id))
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "", gin.H{
+		"likes_count": likesCount,
+	})
+}
+
+// RemoveLike いいねを削除
+func (c *WorkController) RemoveLike(ctx *gin.Context) {
+	// IDを解析
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なIDです")
+		return
+	}
+
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// いいねを削除
+	likesCount, err := c.workService.RemoveLike(ctx.Request.Context(), u.ID, uint(id))
+	if err != nil {
+		if strings.Contains(err.Error(), "見つかりません") {
+			utils.RespondError(ctx, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.Respond(ctx, http.StatusOK, "", gin.H{
+		"likes_count": likesCount,
+	})
+}
+
+// GetUserWorks ユーザーの作品一覧を取得
+func (c *WorkController) GetUserWorks(ctx *gin.Context) {
+	// ユーザーIDを解析
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なユーザーIDです")
+		return
+	}
+
+	// クエリパラメータを取得
+	pageStr := ctx.DefaultQuery("page", "1")
+	limitStr := ctx.DefaultQuery("limit", "20")
+
+	// 数値パラメータを解析
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page < 1 {
 		page = 1
@@ -346,16 +1210,97 @@ func (c *WorkController) GetUserWorks(ctx *gin.Context) {
 	}
 
 	// 作品一覧を取得
-	works, total, pages, err := c.workService.GetUserWorks(uint(userID), page, limit)
+	works, total, pages, err := c.workService.GetUserWorks(ctx.Request.Context(), uint(userID), page, limit)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"works": works,
-		"total": total,
-		"pages": pages,
-		"page":  page,
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, works)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "works", items, total, page, limit, pages, nil)
+}
+
+// GetUserCollaborations ユーザーが共同編集者として参加している作品一覧を取得
+func (c *WorkController) GetUserCollaborations(ctx *gin.Context) {
+	// ユーザーIDを解析
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, "無効なユーザーIDです")
+		return
+	}
+
+	// クエリパラメータを取得
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	works, total, pages, err := c.workService.GetCollaborations(ctx.Request.Context(), uint(userID), page, limit)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// 指定された項目のみを返す（オプション）
+	items, err := applyFields(ctx, works)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondPaginated(ctx, "works", items, total, page, limit, pages, nil)
+}
+
+// Bulk 自分の作品に対して一括操作を行う
+func (c *WorkController) Bulk(ctx *gin.Context) {
+	// ユーザー情報を取得
+	user, exists := ctx.Get("user")
+	if !exists {
+		utils.RespondError(ctx, http.StatusUnauthorized, "認証が必要です")
+		return
+	}
+	u := user.(*models.User)
+
+	// JSONリクエストをバインド
+	var req struct {
+		Action     string   `json:"action" binding:"required"`
+		WorkIDs    []uint   `json:"work_ids" binding:"required"`
+		Tags       []string `json:"tags"`
+		CodeShared *bool    `json:"code_shared"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 一括操作を実行
+	results, err := c.workService.Bulk(ctx.Request.Context(), u.ID, req.Action, req.WorkIDs, req.Tags, req.CodeShared)
+	if err != nil {
+		utils.RespondError(ctx, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	succeeded := 0
+	for _, result := range results {
+		if result.Status == services.BulkStatusOK {
+			succeeded++
+		}
+	}
+
+	utils.Respond(ctx, http.StatusOK, "", gin.H{
+		"results":   results,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
 	})
 }
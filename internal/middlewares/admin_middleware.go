@@ -0,0 +1,28 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminMiddleware 管理者権限を確認するミドルウェア（AuthMiddlewareの後に使用する）
+func AdminMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, exists := ctx.Get("user")
+		if !exists {
+			utils.AbortWithError(ctx, http.StatusUnauthorized, "認証が必要です")
+			return
+		}
+
+		if u, ok := user.(*models.User); !ok || !u.IsAdmin() {
+			utils.AbortWithError(ctx, http.StatusForbidden, "管理者権限がありません")
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// v2を指定するメディアタイプ
+const apiV2MediaType = "application/vnd.sketchshifter.v2+json"
+
+// APIVersionMiddleware リクエストのAPIバージョンを決定するミドルウェア
+// /api/v2 へのリクエストに加え、/api/v1 でも Accept-Version: 2 ヘッダーか
+// Accept: application/vnd.sketchshifter.v2+json を指定した場合はv2として扱う。
+// v1として処理する場合は非推奨であることをヘッダーで通知する（sunsetは廃止予定日、空の場合は省略）。
+func APIVersionMiddleware(sunset string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		path := ctx.Request.URL.Path
+		if !strings.HasPrefix(path, "/api/") {
+			ctx.Next()
+			return
+		}
+
+		version := utils.APIVersion1
+		if strings.HasPrefix(path, "/api/v2/") ||
+			ctx.GetHeader("Accept-Version") == utils.APIVersion2 ||
+			strings.Contains(ctx.GetHeader("Accept"), apiV2MediaType) {
+			version = utils.APIVersion2
+		}
+		utils.SetAPIVersion(ctx, version)
+		ctx.Header("API-Version", version)
+
+		if version == utils.APIVersion1 {
+			ctx.Header("Deprecation", "true")
+			if sunset != "" {
+				ctx.Header("Sunset", sunset)
+			}
+			ctx.Writer.Header().Add("Link", `</api/v2>; rel="successor-version"`)
+			ctx.Next()
+			return
+		}
+
+		// v2は常にエンベロープ形式
+		utils.SetResponseFormat(ctx, utils.ResponseFormatEnvelope)
+
+		// カーソルをページ位置に変換して、各一覧APIのpage/limitとして渡す
+		if cursor := ctx.Query("cursor"); cursor != "" {
+			page, limit, err := utils.DecodeCursor(cursor)
+			if err != nil {
+				utils.AbortWithError(ctx, http.StatusBadRequest, err.Error())
+				return
+			}
+			query := ctx.Request.URL.Query()
+			query.Set("page", strconv.Itoa(page))
+			query.Set("limit", strconv.Itoa(limit))
+			ctx.Request.URL.RawQuery = query.Encode()
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestResponseFormatFollowsAPIVersion v1は従来の形式、v2はエンベロープ形式で返すことを確認
+func TestResponseFormatFollowsAPIVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ResponseFormatMiddleware(utils.ResponseFormatLegacy))
+	r.Use(APIVersionMiddleware(""))
+	format := func(ctx *gin.Context) {
+		if utils.IsLegacyResponse(ctx) {
+			ctx.String(http.StatusOK, utils.ResponseFormatLegacy)
+			return
+		}
+		ctx.String(http.StatusOK, utils.ResponseFormatEnvelope)
+	}
+	r.GET("/api/v1/works", format)
+	r.GET("/api/v2/works", format)
+
+	tests := []struct {
+		name    string
+		path    string
+		headers map[string]string
+		want    string
+	}{
+		{"v1", "/api/v1/works", nil, utils.ResponseFormatLegacy},
+		{"v1でエンベロープ形式を指定", "/api/v1/works", map[string]string{"X-Response-Format": "envelope"}, utils.ResponseFormatEnvelope},
+		{"v1でv2を指定", "/api/v1/works", map[string]string{"Accept-Version": "2"}, utils.ResponseFormatEnvelope},
+		{"v2", "/api/v2/works", nil, utils.ResponseFormatEnvelope},
+		{"v2で従来の形式を指定", "/api/v2/works", map[string]string{"X-Response-Format": "legacy"}, utils.ResponseFormatEnvelope},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		for key, value := range tt.headers {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if got := w.Body.String(); got != tt.want {
+			t.Errorf("%s: format = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -2,41 +2,60 @@ package middlewares
 
 import (
 	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware 認証ミドルウェア
-func AuthMiddleware(authService services.AuthService) gin.HandlerFunc {
+// APIのバージョンのパス（/api/v1など）
+var apiVersionPathPattern = regexp.MustCompile(`^/api/v[0-9]+`)
+
+// AuthMiddleware 認証ミドルウェア（JWTと個人用アクセストークンを受け付ける）
+func AuthMiddleware(authService services.AuthService, personalTokenService services.PersonalTokenService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// Authorizationヘッダーを取得
 		authHeader := ctx.GetHeader("Authorization")
 
 		// ヘッダーがない場合は認証エラー
 		if authHeader == "" {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "認証が必要です"})
-			ctx.Abort()
+			utils.AbortWithError(ctx, http.StatusUnauthorized, "認証が必要です")
 			return
 		}
 
 		// Bearer トークンの形式かチェック
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "無効な認証形式です"})
-			ctx.Abort()
+			utils.AbortWithError(ctx, http.StatusUnauthorized, "無効な認証形式です")
 			return
 		}
 
 		// トークンを抽出
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
+		// 個人用アクセストークンの場合はスコープを確認
+		if strings.HasPrefix(tokenString, services.PersonalTokenPrefix) {
+			user, token, err := personalTokenService.Authenticate(ctx.Request.Context(), tokenString)
+			if err != nil {
+				utils.AbortWithError(ctx, http.StatusUnauthorized, "無効なトークンです")
+				return
+			}
+			if !personalTokenService.Allows(token, ctx.Request.Method, apiPath(ctx)) {
+				utils.AbortWithError(ctx, http.StatusForbidden, "このトークンのスコープでは利用できません")
+				return
+			}
+			ctx.Set("user", user)
+			ctx.Set("personal_token", token)
+			ctx.Next()
+			return
+		}
+
 		// ユーザーを取得
-		user, err := authService.GetUserFromToken(tokenString)
+		user, err := authService.GetUserFromToken(ctx.Request.Context(), tokenString)
 		if err != nil {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "無効なトークンです"})
-			ctx.Abort()
+			utils.AbortWithError(ctx, http.StatusUnauthorized, "無効なトークンです")
 			return
 		}
 
@@ -47,7 +66,8 @@ func AuthMiddleware(authService services.AuthService) gin.HandlerFunc {
 }
 
 // OptionalAuthMiddleware オプショナル認証ミドルウェア（認証がない場合もエラーを返さない）
-func OptionalAuthMiddleware(authService services.AuthService) gin.HandlerFunc {
+// 個人用アクセストークンのスコープで利用できない場合は認証なしとして扱う
+func OptionalAuthMiddleware(authService services.AuthService, personalTokenService services.PersonalTokenService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// Authorizationヘッダーを取得
 		authHeader := ctx.GetHeader("Authorization")
@@ -67,8 +87,19 @@ func OptionalAuthMiddleware(authService services.AuthService) gin.HandlerFunc {
 		// トークンを抽出
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
+		// 個人用アクセストークンの場合はスコープを確認
+		if strings.HasPrefix(tokenString, services.PersonalTokenPrefix) {
+			user, token, err := personalTokenService.Authenticate(ctx.Request.Context(), tokenString)
+			if err == nil && personalTokenService.Allows(token, ctx.Request.Method, apiPath(ctx)) {
+				ctx.Set("user", user)
+				ctx.Set("personal_token", token)
+			}
+			ctx.Next()
+			return
+		}
+
 		// ユーザーを取得
-		user, err := authService.GetUserFromToken(tokenString)
+		user, err := authService.GetUserFromToken(ctx.Request.Context(), tokenString)
 		if err != nil {
 			ctx.Next()
 			return
@@ -79,3 +110,8 @@ func OptionalAuthMiddleware(authService services.AuthService) gin.HandlerFunc {
 		ctx.Next()
 	}
 }
+
+// apiPath APIのバージョンを除いたルートのパス（/api/v1/works/:id の場合は /works/:id）
+func apiPath(ctx *gin.Context) string {
+	return apiVersionPathPattern.ReplaceAllString(ctx.FullPath(), "")
+}
@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BlocklistMiddleware ブロックリストに含まれるIPアドレスからのリクエストと、制限された国からの書き込み系のリクエストを403で拒否するミドルウェア
+// 国はcountryHeader（CDNが付与するヘッダー）から判定し、exemptPrefixesに一致するパスはそのまま処理する
+func BlocklistMiddleware(blocklistService services.BlocklistService, countryHeader string, exemptPrefixes ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		for _, prefix := range exemptPrefixes {
+			if strings.HasPrefix(ctx.Request.URL.Path, prefix) {
+				ctx.Next()
+				return
+			}
+		}
+
+		if blocklistService.IsBlocked(ctx.Request.Context(), ctx.ClientIP()) {
+			utils.AbortWithError(ctx, http.StatusForbidden, "このIPアドレスからのアクセスは制限されています")
+			return
+		}
+
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			ctx.Next()
+			return
+		}
+
+		if countryHeader != "" && blocklistService.IsCountryRestricted(ctx.GetHeader(countryHeader)) {
+			utils.AbortWithError(ctx, http.StatusForbidden, "お住まいの地域からの投稿は制限されています")
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"runtime/debug"
 
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,9 +16,7 @@ func ErrorMiddleware() gin.HandlerFunc {
 			if err := recover(); err != nil {
 				// ここでパニックをキャッチしてエラーレスポンスを返す
 				debug.PrintStack()
-				ctx.JSON(http.StatusInternalServerError, gin.H{
-					"error": "サーバーエラーが発生しました",
-				})
+				utils.RespondError(ctx, http.StatusInternalServerError, "サーバーエラーが発生しました")
 			}
 		}()
 		ctx.Next()
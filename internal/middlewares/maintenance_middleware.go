@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceMiddleware メンテナンス中は書き込み系のリクエストを503で拒否するミドルウェア
+// 読み取り系のリクエストと、exemptPrefixesに一致するパスはそのまま処理する
+func MaintenanceMiddleware(maintenanceService services.MaintenanceService, exemptPrefixes ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			ctx.Next()
+			return
+		}
+
+		status := maintenanceService.Status()
+		if !status.Enabled {
+			ctx.Next()
+			return
+		}
+
+		for _, prefix := range exemptPrefixes {
+			if strings.HasPrefix(ctx.Request.URL.Path, prefix) {
+				ctx.Next()
+				return
+			}
+		}
+
+		if status.EndsAt != nil {
+			if retryAfter := int(time.Until(*status.EndsAt).Seconds()); retryAfter > 0 {
+				ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+			}
+		}
+
+		utils.RespondErrorWithMeta(ctx, http.StatusServiceUnavailable, status.Message, gin.H{"maintenance": status})
+		ctx.Abort()
+	}
+}
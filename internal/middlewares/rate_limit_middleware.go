@@ -0,0 +1,78 @@
+package middlewares
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rateLimitWindow リクエスト数を数える期間
+const rateLimitWindow = time.Minute
+
+// rateLimitCounter IPアドレスごとの期間内のリクエスト数
+type rateLimitCounter struct {
+	count   int
+	resetAt time.Time
+}
+
+// RateLimitMiddleware クライアントのIPアドレスごとに1分あたりのリクエスト数を制限するミドルウェア
+// 上限を超えた場合は429を返す（limitが0以下の場合は制限しない）
+// 数はプロセスごとに保持するため、複数台で動かす場合は台数分まで受け付ける
+func RateLimitMiddleware(limit int) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(ctx *gin.Context) {
+			ctx.Next()
+		}
+	}
+
+	var mu sync.Mutex
+	counters := map[string]*rateLimitCounter{}
+	nextSweep := time.Now().Add(rateLimitWindow)
+
+	return func(ctx *gin.Context) {
+		now := time.Now()
+
+		mu.Lock()
+		// 期間を過ぎたIPアドレスを定期的に削除する
+		if now.After(nextSweep) {
+			for ip, counter := range counters {
+				if now.After(counter.resetAt) {
+					delete(counters, ip)
+				}
+			}
+			nextSweep = now.Add(rateLimitWindow)
+		}
+
+		ip := ctx.ClientIP()
+		counter, ok := counters[ip]
+		if !ok || now.After(counter.resetAt) {
+			counter = &rateLimitCounter{resetAt: now.Add(rateLimitWindow)}
+			counters[ip] = counter
+		}
+		counter.count++
+		count, resetAt := counter.count, counter.resetAt
+		mu.Unlock()
+
+		remaining := limit - count
+		if remaining < 0 {
+			remaining = 0
+		}
+		ctx.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		ctx.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		ctx.Header("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+		if count > limit {
+			retryAfter := int(time.Until(resetAt).Seconds()) + 1
+			ctx.Header("Retry-After", strconv.Itoa(retryAfter))
+			utils.AbortWithError(ctx, http.StatusTooManyRequests, "リクエストが多すぎます。しばらくしてから再度お試しください")
+			return
+		}
+
+		ctx.Next()
+	}
+}
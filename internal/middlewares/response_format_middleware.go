@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseFormatMiddleware レスポンス形式（エンベロープ形式・従来の形式）を決定するミドルウェア
+// X-Response-Format ヘッダーでリクエストごとに切り替えられる
+func ResponseFormatMiddleware(defaultFormat string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		format := defaultFormat
+		switch requested := ctx.GetHeader("X-Response-Format"); requested {
+		case utils.ResponseFormatEnvelope, utils.ResponseFormatLegacy:
+			format = requested
+		}
+
+		utils.SetResponseFormat(ctx, format)
+		ctx.Next()
+	}
+}
+
+// FixedResponseFormatMiddleware ヘッダーに関わらずレスポンス形式を固定するミドルウェア
+// 公開APIのように、アプリのAPIの形式の切り替えと切り離す場合に使う
+func FixedResponseFormatMiddleware(format string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		utils.SetResponseFormat(ctx, format)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutMiddleware リクエストごとにタイムアウト付きのコンテキストを設定するミドルウェア
+// リポジトリ層までコンテキストが渡されるため、期限を過ぎたDB呼び出しは中断される。
+// エクスポートやファイルのアップロードのように時間のかかるルートが途中で打ち切られないよう、
+// ルートのパスが routeTimeouts のキーで終わる場合はその値を使う（0以下の場合はタイムアウトなし）。
+func TimeoutMiddleware(timeout time.Duration, routeTimeouts map[string]time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		timeout := timeout
+		for suffix, routeTimeout := range routeTimeouts {
+			if strings.HasSuffix(ctx.FullPath(), suffix) {
+				timeout = routeTimeout
+				break
+			}
+		}
+		if timeout <= 0 {
+			ctx.Next()
+			return
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Next()
+
+		// ハンドラーがレスポンスを書き込む前に期限切れになった場合
+		if errors.Is(reqCtx.Err(), context.DeadlineExceeded) && !ctx.Writer.Written() {
+			utils.AbortWithError(ctx, http.StatusGatewayTimeout, "リクエストがタイムアウトしました")
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestTimeoutMiddlewareUsesRouteTimeouts エクスポート・アップロードのルートには長いタイムアウトを使うことを確認
+func TestTimeoutMiddlewareUsesRouteTimeouts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(TimeoutMiddleware(time.Second, map[string]time.Duration{"/export": time.Hour, "/video": 2 * time.Hour}))
+	remaining := func(ctx *gin.Context) {
+		deadline, ok := ctx.Request.Context().Deadline()
+		if !ok {
+			ctx.String(http.StatusOK, "none")
+			return
+		}
+		ctx.String(http.StatusOK, time.Until(deadline).Round(time.Minute).String())
+	}
+	r.GET("/api/v1/works/:id", remaining)
+	r.GET("/api/v1/works/:id/comments/export", remaining)
+	r.PUT("/api/v1/works/:id/video", remaining)
+
+	tests := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodGet, "/api/v1/works/1", "0s"},
+		{http.MethodGet, "/api/v1/works/1/comments/export", "1h0m0s"},
+		{http.MethodPut, "/api/v1/works/1/video", "2h0m0s"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if got := w.Body.String(); got != tt.want {
+			t.Errorf("%s: remaining = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
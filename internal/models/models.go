@@ -1,6 +1,7 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,15 +9,44 @@ import (
 
 // User ユーザーモデル
 type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
-	Password  string         `json:"-" gorm:"not null"`
-	Name      string         `json:"name" gorm:"not null"`
-	Nickname  string         `json:"nickname" gorm:"not null"`
-	Bio       string         `json:"bio"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	Email      string         `json:"email" gorm:"uniqueIndex;not null"`
+	Password   string         `json:"-" gorm:"not null"`
+	Name       string         `json:"name" gorm:"not null"`
+	Nickname   string         `json:"nickname" gorm:"not null"`
+	Handle     *string        `json:"handle,omitempty" gorm:"size:30;uniqueIndex"` // プロフィールのURLに使う一意な名前（英小文字・数字・_）
+	Bio        string         `json:"bio"`
+	AvatarURL  string         `json:"avatar_url,omitempty"`
+	AvatarID   *uint          `json:"-"` // アバターの画像（差し替え時に古い画像を削除する）
+	Reputation int            `json:"reputation" gorm:"default:0;index"`
+	Role       string         `json:"role" gorm:"size:20;default:user;not null"`
+	Pending    bool           `json:"pending,omitempty" gorm:"default:false"` // 名簿の取り込みで仮登録され、招待が未承認
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// 招待（仮登録ユーザーのみ、トークンはハッシュ化して保存）
+	InviteTokenHash string     `json:"-" gorm:"size:64;index"`
+	InviteExpiresAt *time.Time `json:"-"`
+
+	// メールアドレスの変更（確認が済むまで新しいアドレスを保持し、トークンはハッシュ化して保存）
+	PendingEmail        string     `json:"-"`
+	EmailTokenHash      string     `json:"-" gorm:"size:64;index"`
+	EmailTokenExpiresAt *time.Time `json:"-"`
+
+	// この日時より前に発行されたトークンは無効（メールアドレスの変更時に設定）
+	TokensValidAfter *time.Time `json:"-"`
+
+	// 生まれた年（任意、未成年かどうかの判定に使い、本人以外には公開しない）
+	BirthYear *int `json:"-"`
+
+	// プライバシー設定
+	HideLikes      bool `json:"-" gorm:"default:false"` // いいねした作品を公開せず、作品にいいねしたユーザーの一覧にも表示しない
+	HideProjects   bool `json:"-" gorm:"default:false"` // 参加しているコンテストを公開しない
+	HideFromSearch bool `json:"-" gorm:"default:false"` // ランキングなどのユーザー一覧に表示しない
+
+	// 共同編集した作品の数（作成者・共同編集者のどちらも数える。プロフィールでのみ設定する）
+	CollaborativeWorksCount *int64 `json:"collaborative_works_count,omitempty" gorm:"-"`
 
 	// リレーション
 	Works    []Work    `json:"-"`
@@ -25,6 +55,25 @@ type User struct {
 	Projects []Project `json:"-" gorm:"foreignKey:OwnerID"`
 }
 
+// HandleHistory 変更前のハンドル（古いプロフィールのURLを使えるように、他のユーザーには使わせない）
+type HandleHistory struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	Handle    string    `json:"handle" gorm:"size:30;not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ユーザーの権限
+const (
+	UserRoleUser  = "user"
+	UserRoleAdmin = "admin"
+)
+
+// IsAdmin 管理者かどうか
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
 // Tag タグモデル
 type Tag struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
@@ -37,38 +86,162 @@ type Tag struct {
 
 // Work 作品モデル（ProcessingWorkを統合）
 type Work struct {
-	ID                uint           `json:"id" gorm:"primaryKey"`
-	Title             string         `json:"title" gorm:"not null"`
-	Description       string         `json:"description"`
-	PDEContent        string         `json:"pde_content" gorm:"type:text"`
-	JSContent         string         `json:"js_content" gorm:"type:text"`
-	ThumbnailURL      string         `json:"thumbnail_url"`
-	ThumbnailType     string         `json:"thumbnail_type"`
-	ThumbnailPublicID string         `json:"-"`
-	CodeShared        bool           `json:"code_shared" gorm:"default:false"`
-	Views             int            `json:"views" gorm:"default:0"`
-	UserID            uint           `json:"user_id" gorm:"not null"`
-	CreatedAt         time.Time      `json:"created_at"`
-	UpdatedAt         time.Time      `json:"updated_at"`
-	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
-
-	// リレーション
-	User     User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Tags     []Tag     `json:"tags,omitempty" gorm:"many2many:work_tags;"`
-	Likes    []Like    `json:"-"`
-	Comments []Comment `json:"-"`
-	Tasks    []Task    `json:"-" gorm:"many2many:task_works;"`
+	ID                 uint           `json:"id" gorm:"primaryKey"`
+	Title              string         `json:"title" gorm:"not null"`
+	Description        string         `json:"description"`
+	DescriptionHTML    string         `json:"description_html" gorm:"type:text"`
+	PDEContent         string         `json:"pde_content" gorm:"type:text"`
+	JSContent          string         `json:"js_content" gorm:"type:text"`
+	ConverterVersion   string         `json:"converter_version" gorm:"size:64;index"`
+	JSValidationStatus string         `json:"js_validation_status" gorm:"size:20;index"`
+	JSValidationIssues string         `json:"js_validation_issues,omitempty" gorm:"type:text"`
+	ConvertedAt        *time.Time     `json:"-"`                               // 最後にPDEを変換した日時
+	ConversionMs       int64          `json:"-" gorm:"not null;default:0"`     // 最後の変換でLambdaの呼び出しにかかった時間（ミリ秒）
+	ConversionCached   bool           `json:"-" gorm:"not null;default:false"` // 最後の変換で同じPDEの変換結果を再利用したかどうか
+	ThumbnailURL       string         `json:"thumbnail_url"`
+	ThumbnailType      string         `json:"thumbnail_type"`
+	ThumbnailPublicID  string         `json:"-"`
+	ThumbnailBlurhash  string         `json:"thumbnail_blurhash,omitempty" gorm:"size:64"` // サムネイルの読み込み中に表示するぼかし画像（アップロードした場合のみ）
+	VideoAssetID       *uint          `json:"video_asset_id,omitempty"`                    // デモ動画のアセット
+	VideoURL           string         `json:"video_url,omitempty"`
+	VideoStatus        string         `json:"video_status,omitempty" gorm:"size:20"` // processing, ready, failed
+	CodeShared         bool           `json:"code_shared" gorm:"default:false"`
+	License            string         `json:"license" gorm:"size:32;not null;default:all-rights-reserved;index"`
+	Language           string         `json:"language,omitempty" gorm:"size:8;index"`  // 説明文の言語（ISO 639-1のコード、未指定の場合は自動で判定）
+	ForkedFromID       *uint          `json:"forked_from_id,omitempty" gorm:"index"`   // フォーク元の作品
+	FeaturedFrom       *time.Time     `json:"featured_from,omitempty" gorm:"index"`    // ピックアップの開始日時
+	FeaturedUntil      *time.Time     `json:"featured_until,omitempty" gorm:"index"`   // ピックアップの終了日時（nilの場合は無期限）
+	SeriesID           *uint          `json:"series_id,omitempty" gorm:"index"`        // 所属するシリーズ
+	SeriesPosition     int            `json:"-" gorm:"not null;default:0"`             // シリーズ内の順番
+	Event              string         `json:"event,omitempty" gorm:"size:64;index"`    // 制作したイベント（ワークショップ・ハッカソンなど）の識別子
+	LocationName       string         `json:"location_name,omitempty" gorm:"size:255"` // 制作した場所の名前
+	Latitude           *float64       `json:"latitude,omitempty"`                      // 制作した場所の緯度
+	Longitude          *float64       `json:"longitude,omitempty"`                     // 制作した場所の経度
+	HiddenAt           *time.Time     `json:"hidden_at,omitempty" gorm:"index"`        // 通報により非表示になった日時
+	ReviewedAt         *time.Time     `json:"-"`                                       // 管理者が通報を確認した日時
+	ArchivedAt         *time.Time     `json:"archived_at,omitempty" gorm:"index"`      // PDEとJSをストレージに移した日時（取得時に戻す）
+	Views              int            `json:"views" gorm:"default:0"`
+	UserID             uint           `json:"user_id" gorm:"not null"`
+	Version            uint           `json:"version" gorm:"not null;default:1"` // 楽観的ロックのバージョン（更新のたびに1つ進める）
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// リレーション
+	User     User        `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Tags     []Tag       `json:"tags,omitempty" gorm:"many2many:work_tags;"`
+	Likes    []Like      `json:"-"`
+	Comments []Comment   `json:"-"`
+	Tasks    []Task      `json:"-" gorm:"many2many:task_works;"`
+	Badges   []WorkBadge `json:"badges,omitempty" gorm:"foreignKey:WorkID"`
+
+	// カウント（いいね・コメントの追加/削除時に更新し、定期的に再集計する）
+	LikesCount    int64 `json:"likes_count" gorm:"default:0;not null;index"`
+	CommentsCount int64 `json:"comments_count" gorm:"default:0;not null"`
+
+	// フォーク元のクレジット（近い順、サーバー側で解決する）
+	Attribution []WorkAttribution `json:"attribution,omitempty" gorm:"-"`
+
+	// 現在ピックアップ中かどうか（読み込み時に設定する）
+	Featured bool `json:"featured" gorm:"-"`
+
+	// 承認済みの共同編集者（サーバー側で設定する）
+	CoAuthors []User `json:"co_authors,omitempty" gorm:"-"`
 
-	// カウント (JSONレスポンス用)
-	LikesCount    int64 `json:"likes_count" gorm:"-"`
-	CommentsCount int64 `json:"comments_count" gorm:"-"`
+	// シリーズ内の位置と前後の作品（作品詳細でのみサーバー側で設定する）
+	Series *SeriesNavigation `json:"series,omitempty" gorm:"-"`
+
+	// 変換の来歴（作品詳細でのみサーバー側で設定する）
+	Pipeline *ConversionPipeline `json:"pipeline,omitempty" gorm:"-"`
+
+	// アップロードしたサムネイル画像と派生画像（アップロード時のレスポンスでのみ設定する）
+	ThumbnailImage *Image `json:"thumbnail_image,omitempty" gorm:"-"`
+}
+
+// ConversionPipeline 作品のJSを生成した変換の来歴（動作の違いを調べるため）
+type ConversionPipeline struct {
+	ConverterVersion string     `json:"converter_version"`
+	ConvertedAt      *time.Time `json:"converted_at,omitempty"` // 来歴を記録する前に変換した作品はnil
+	DurationMs       int64      `json:"duration_ms"`
+	Cached           bool       `json:"cached"`
+}
+
+// ConversionPipeline 変換の来歴を取得（変換していない作品はnil）
+func (w *Work) ConversionPipeline() *ConversionPipeline {
+	if w.ConverterVersion == "" {
+		return nil
+	}
+	return &ConversionPipeline{
+		ConverterVersion: w.ConverterVersion,
+		ConvertedAt:      w.ConvertedAt,
+		DurationMs:       w.ConversionMs,
+		Cached:           w.ConversionCached,
+	}
+}
+
+// IsFeaturedAt 指定日時にピックアップ中かどうか
+func (w *Work) IsFeaturedAt(now time.Time) bool {
+	if w.FeaturedFrom == nil || w.FeaturedFrom.After(now) {
+		return false
+	}
+	return w.FeaturedUntil == nil || w.FeaturedUntil.After(now)
+}
+
+// AfterFind 読み込み時にピックアップ中かどうかを設定
+func (w *Work) AfterFind(tx *gorm.DB) error {
+	w.Featured = w.IsFeaturedAt(time.Now())
+	return nil
+}
+
+// WorkAttribution フォーク元の作品のクレジット情報
+type WorkAttribution struct {
+	WorkID   uint   `json:"work_id"`
+	Title    string `json:"title"`
+	UserID   uint   `json:"user_id"`
+	Nickname string `json:"nickname"`
+	License  string `json:"license"`
+	Deleted  bool   `json:"deleted,omitempty"` // フォーク元が削除されている
+}
+
+// 作品のライセンス
+const (
+	WorkLicenseCCBY              = "CC-BY"
+	WorkLicenseCC0               = "CC0"
+	WorkLicenseMIT               = "MIT"
+	WorkLicenseAllRightsReserved = "all-rights-reserved"
+)
+
+// IsValidWorkLicense 指定できるライセンスかどうか
+func IsValidWorkLicense(license string) bool {
+	switch license {
+	case WorkLicenseCCBY, WorkLicenseCC0, WorkLicenseMIT, WorkLicenseAllRightsReserved:
+		return true
+	}
+	return false
+}
+
+// NormalizeWorkLanguage 指定された言語をISO 639-1のコードにする（ja-JPなどの地域は除く、無効な場合はfalse）
+func NormalizeWorkLanguage(language string) (string, bool) {
+	code := strings.ToLower(strings.TrimSpace(language))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	if len(code) != 2 || code[0] < 'a' || code[0] > 'z' || code[1] < 'a' || code[1] > 'z' {
+		return "", false
+	}
+	return code, true
+}
+
+// AllowsFork ライセンスがフォーク（改変・再配布）を許可しているかどうか
+func (w *Work) AllowsFork() bool {
+	return w.License != WorkLicenseAllRightsReserved && IsValidWorkLicense(w.License)
 }
 
 // Like いいねモデル
 type Like struct {
-	UserID    uint      `json:"user_id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"primaryKey;index:idx_likes_user_created,priority:1"`
 	WorkID    uint      `json:"work_id" gorm:"primaryKey"`
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt time.Time `json:"created_at" gorm:"index:idx_likes_user_created,priority:2"` // いいねした作品一覧を新しい順に取得するため
 
 	// リレーション
 	User User `json:"-"`
@@ -77,29 +250,76 @@ type Like struct {
 
 // Comment コメントモデル
 type Comment struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Content   string         `json:"content" gorm:"not null"`
-	WorkID    uint           `json:"work_id" gorm:"not null"`
-	UserID    uint           `json:"user_id" gorm:"not null"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	Content      string         `json:"content" gorm:"not null"`
+	WorkID       uint           `json:"work_id" gorm:"not null"`
+	UserID       uint           `json:"user_id" gorm:"not null"`
+	ParentID     *uint          `json:"parent_id,omitempty" gorm:"index"` // 返信先のコメント
+	RepliesCount int            `json:"replies_count" gorm:"not null;default:0"`
+	Pinned       bool           `json:"pinned" gorm:"not null;default:false"` // 作品の作者によるピン留め（作品ごとに1件）
+	ResolvedAt   *time.Time     `json:"resolved_at,omitempty"`                // 作品の作者が対応済みにした日時
+	HiddenAt     *time.Time     `json:"hidden_at,omitempty" gorm:"index"`     // 通報により非表示になった日時
+	ReviewedAt   *time.Time     `json:"-"`                                    // 管理者が通報を確認した日時
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// コメントに含まれるURLのリンクプレビュー（取得済みのもののみ）
+	LinkPreviews []LinkPreview `json:"link_previews,omitempty" gorm:"-"`
 
 	// リレーション
 	User User `json:"user" gorm:"foreignKey:UserID"`
 	Work Work `json:"-" gorm:"foreignKey:WorkID"`
 }
 
+// LinkPreview URLのOpenGraphのメタデータ（コメントのリンクプレビュー用のキャッシュ）
+type LinkPreview struct {
+	ID          uint      `json:"-" gorm:"primaryKey"`
+	URLHash     string    `json:"-" gorm:"size:64;uniqueIndex;not null"` // URLのSHA-256
+	URL         string    `json:"url" gorm:"type:text;not null"`
+	Title       string    `json:"title" gorm:"size:300"`
+	Description string    `json:"description,omitempty" gorm:"size:1000"`
+	ImageURL    string    `json:"image_url,omitempty" gorm:"type:text"`
+	SiteName    string    `json:"site_name,omitempty" gorm:"size:100"`
+	Failed      bool      `json:"-" gorm:"not null;default:false"` // 取得できなかった（再取得までは表示しない）
+	FetchedAt   time.Time `json:"-" gorm:"index"`
+}
+
+// CommentSubscription 作品のコメントの通知を受け取るかどうかの設定
+// 設定がない場合、作品の作者は受け取り、それ以外のユーザーは受け取らない（コメントすると受け取る設定になる）
+type CommentSubscription struct {
+	UserID     uint      `json:"-" gorm:"primaryKey"`
+	WorkID     uint      `json:"work_id" gorm:"primaryKey;index"`
+	Subscribed bool      `json:"subscribed" gorm:"not null"`
+	CreatedAt  time.Time `json:"-"`
+	UpdatedAt  time.Time `json:"-"`
+}
+
+// WorkRanking 人気順（sort=popular）の並び替えに使う作品の人気スコア
+// スケジューラが閲覧数・いいね数・コメント数と公開からの経過日数から定期的に計算し直す
+type WorkRanking struct {
+	WorkID     uint      `json:"work_id" gorm:"primaryKey"`
+	Score      float64   `json:"score" gorm:"not null;index"`
+	ComputedAt time.Time `json:"computed_at" gorm:"not null;index"`
+}
+
 // Project プロジェクトモデル
 type Project struct {
-	ID             uint           `json:"id" gorm:"primaryKey"`
-	Title          string         `json:"title" gorm:"not null"`
-	Description    string         `json:"description"`
-	InvitationCode string         `json:"invitation_code,omitempty" gorm:"uniqueIndex"`
-	OwnerID        uint           `json:"owner_id" gorm:"not null"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
-	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	Title           string         `json:"title" gorm:"not null"`
+	Description     string         `json:"description"`
+	DescriptionHTML string         `json:"description_html" gorm:"type:text"`
+	InvitationCode  string         `json:"invitation_code,omitempty" gorm:"uniqueIndex"`
+	OwnerID         uint           `json:"owner_id" gorm:"not null"`
+	OrganizationID  *uint          `json:"organization_id,omitempty" gorm:"index"` // 所属する組織（個人のプロジェクトの場合はnil）
+	IsContest       bool           `json:"is_contest" gorm:"default:false;index"`  // 誰でも参加できる公開コンテスト
+	ContestPhase    string         `json:"contest_phase,omitempty" gorm:"size:20"` // open, judging, finished
+	ContestTaskID   *uint          `json:"contest_task_id,omitempty"`              // 応募を受け付けるタスク
+	ContestVoteID   *uint          `json:"contest_vote_id,omitempty"`              // 審査の投票
+	Version         uint           `json:"version" gorm:"not null;default:1"`      // 楽観的ロックのバージョン
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// リレーション
 	Owner   User   `json:"owner" gorm:"foreignKey:OwnerID"`
@@ -107,28 +327,235 @@ type Project struct {
 	Tasks   []Task `json:"tasks,omitempty"`
 }
 
+// コンテストの進行状況
+const (
+	ContestPhaseOpen     = "open"     // 参加登録・応募を受付中
+	ContestPhaseJudging  = "judging"  // 審査（投票）中
+	ContestPhaseFinished = "finished" // 結果発表済み
+)
+
 // ProjectMember プロジェクトメンバーモデル
 type ProjectMember struct {
-	ProjectID uint      `json:"project_id" gorm:"primaryKey"`
-	UserID    uint      `json:"user_id" gorm:"primaryKey"`
-	IsOwner   bool      `json:"is_owner" gorm:"default:false"`
-	JoinedAt  time.Time `json:"joined_at"`
+	ProjectID  uint      `json:"project_id" gorm:"primaryKey"`
+	UserID     uint      `json:"user_id" gorm:"primaryKey"`
+	IsOwner    bool      `json:"is_owner" gorm:"default:false"`
+	Supervised bool      `json:"supervised" gorm:"default:false"` // 保護者の同意を確認し、オーナー（先生）が見守りを有効にした未成年のメンバー
+	JoinedAt   time.Time `json:"joined_at"`
+	// 最後に作品の提出・コメント・投票・講評をした日時（記録を始める前の活動は含まない）
+	LastActivityAt *time.Time `json:"last_activity_at"`
 
 	// リレーション
 	Project Project `json:"-"`
 	User    User    `json:"user"`
 }
 
+// 組織のメンバーの役割
+const (
+	OrganizationRoleOwner  = "owner"  // 組織の削除・役割の変更ができる
+	OrganizationRoleAdmin  = "admin"  // メンバーの追加・削除ができ、組織の全てのプロジェクトをオーナーとして管理できる
+	OrganizationRoleMember = "member" // 組織のプロジェクトの作成と一覧の閲覧ができる
+)
+
+// Organization 学校・クラブなど、複数のプロジェクトをまとめて管理する組織
+type Organization struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"size:100;not null"`
+	Description string    `json:"description" gorm:"type:text"`
+	MaxProjects int       `json:"max_projects" gorm:"not null;default:0"` // 作成できるプロジェクト数の上限（0の場合は無制限、管理者が設定する）
+	MaxMembers  int       `json:"max_members" gorm:"not null;default:0"`  // メンバー数の上限（0の場合は無制限、管理者が設定する）
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// 読み込み時に設定する
+	MembersCount  int64 `json:"members_count" gorm:"-"`
+	ProjectsCount int64 `json:"projects_count" gorm:"-"`
+}
+
+// OrganizationMember 組織のメンバーモデル（組織のプロジェクトのメンバーとは別に管理する）
+type OrganizationMember struct {
+	OrganizationID uint      `json:"organization_id" gorm:"primaryKey"`
+	UserID         uint      `json:"user_id" gorm:"primaryKey;index"`
+	Role           string    `json:"role" gorm:"size:20;not null"` // owner, admin, member
+	JoinedAt       time.Time `json:"joined_at"`
+
+	// リレーション
+	Organization Organization `json:"-"`
+	User         User         `json:"user"`
+}
+
+// 料金プラン
+const (
+	PlanFree = "free"
+	PlanPro  = "pro"
+	PlanOrg  = "org"
+)
+
+// Subscription ユーザーの有料プランの契約（StripeのWebhookで更新する）
+type Subscription struct {
+	UserID               uint       `json:"user_id" gorm:"primaryKey"`
+	Plan                 string     `json:"plan" gorm:"size:20;not null"`   // pro, org
+	Status               string     `json:"status" gorm:"size:32;not null"` // Stripeのサブスクリプションの状態（active, trialing, past_due, canceled など）
+	StripeCustomerID     string     `json:"-" gorm:"size:255;index"`
+	StripeSubscriptionID string     `json:"-" gorm:"size:255;uniqueIndex"`
+	CurrentPeriodEnd     *time.Time `json:"current_period_end,omitempty"`
+	EventAt              time.Time  `json:"-"` // 最後に反映したWebhookのイベントの作成日時（古いイベントで上書きしない）
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// IsActive 有料プランとして扱う状態か（支払いが遅れている間は猶予として有料プランのまま）
+func (s *Subscription) IsActive() bool {
+	switch s.Status {
+	case "active", "trialing", "past_due":
+		return true
+	}
+	return false
+}
+
+// OAuthプロバイダー
+const (
+	OAuthProviderGithub = "github"
+)
+
+// OAuthProvider ユーザーに連携した外部サービスのアカウント（1人のユーザーが複数のプロバイダーを連携できる）
+type OAuthProvider struct {
+	ID             uint      `json:"-" gorm:"primaryKey"`
+	UserID         uint      `json:"-" gorm:"not null;uniqueIndex:idx_oauth_user_provider"`
+	Provider       string    `json:"provider" gorm:"size:20;not null;uniqueIndex:idx_oauth_user_provider;uniqueIndex:idx_oauth_provider_user"`
+	ProviderUserID string    `json:"-" gorm:"size:64;not null;uniqueIndex:idx_oauth_provider_user"` // プロバイダーでのユーザーID（変更されない値）
+	Login          string    `json:"login" gorm:"size:100"`                                         // プロバイダーでのユーザー名（表示用）
+	Email          string    `json:"email"`                                                         // 連携時のプロバイダーの確認済みメールアドレス
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName テーブル名を指定
+func (OAuthProvider) TableName() string {
+	return "oauth_providers"
+}
+
+// RefreshToken アクセストークンを再発行するためのリフレッシュトークン
+// 使うたびに同じFamilyIDの新しいトークンに置き換え、使用済みのトークンが再び使われた場合は盗まれたものとしてFamilyIDごと無効にする
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"index;not null"`
+	FamilyID  string     `json:"-" gorm:"size:64;index;not null"`       // ログインごとのID
+	TokenHash string     `json:"-" gorm:"size:64;uniqueIndex;not null"` // トークンのSHA-256
+	ExpiresAt time.Time  `json:"expires_at" gorm:"index;not null"`
+	UsedAt    *time.Time `json:"used_at"`    // 新しいトークンに置き換えた日時
+	RevokedAt *time.Time `json:"revoked_at"` // ログアウトなどで無効にした日時
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RevokedToken ログアウトなどで有効期限より前に無効にしたアクセストークン（JWTのjti）
+// 有効期限を過ぎたトークンは検証で拒否されるため、期限を過ぎたら削除する
+type RevokedToken struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	JTI       string    `json:"-" gorm:"column:jti;size:64;uniqueIndex;not null"`
+	UserID    uint      `json:"user_id" gorm:"index;not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"index;not null"` // トークンの有効期限
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 個人用アクセストークンのスコープ
+const (
+	PersonalTokenScopeRead       = "read"        // 認証が必要なAPIの取得（GET）
+	PersonalTokenScopeWorksWrite = "works:write" // 作品・アップロードの作成・更新・削除
+)
+
+// PersonalToken スクリプトなどからAPIを使うための個人用アクセストークン（ユーザーが発行し、スコープの範囲でのみ使える）
+type PersonalToken struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"index;not null"`
+	Name       string     `json:"name" gorm:"size:100;not null"`
+	TokenHash  string     `json:"-" gorm:"size:64;uniqueIndex;not null"` // トークンのSHA-256
+	Prefix     string     `json:"prefix" gorm:"size:16;not null"`        // 一覧で見分けるためのトークンの先頭部分
+	Scopes     string     `json:"scopes" gorm:"size:255;not null"`       // スペース区切り
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"` // nullの場合は無期限
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// HasScope トークンにスコープが含まれているか確認
+func (t *PersonalToken) HasScope(scope string) bool {
+	for _, s := range strings.Fields(t.Scopes) {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkCollaborator 作品の共同編集者モデル（招待されたユーザーが承認すると権限が有効になる）
+type WorkCollaborator struct {
+	WorkID      uint       `json:"work_id" gorm:"primaryKey"`
+	UserID      uint       `json:"user_id" gorm:"primaryKey;index"`
+	Permission  string     `json:"permission" gorm:"size:20;not null"` // edit, manage
+	InvitedByID uint       `json:"invited_by_id" gorm:"not null"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"` // nilの場合は招待中
+	CreatedAt   time.Time  `json:"created_at"`
+
+	// リレーション
+	User User  `json:"user"`
+	Work *Work `json:"work,omitempty" gorm:"foreignKey:WorkID"` // 招待一覧でのみ読み込む
+}
+
+// 共同編集者の権限
+const (
+	CollaboratorPermissionEdit   = "edit"   // 作品の内容を更新できる
+	CollaboratorPermissionManage = "manage" // 更新に加えて作品を削除できる
+)
+
+// Event ワークショップ・ハッカソンなどのオフラインイベントモデル
+// 作品の event にSlugを設定すると、イベントのページに作品が表示される
+// 削除後に同じSlugで作り直せるよう、論理削除はしない
+type Event struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	Slug            string    `json:"slug" gorm:"size:64;not null;uniqueIndex"`
+	Name            string    `json:"name" gorm:"not null"`
+	Description     string    `json:"description" gorm:"type:text"`
+	DescriptionHTML string    `json:"description_html" gorm:"type:text"`
+	Venue           string    `json:"venue" gorm:"size:255"` // 会場の名前・住所
+	StartsAt        time.Time `json:"starts_at" gorm:"index"`
+	EndsAt          time.Time `json:"ends_at"`
+	Capacity        int       `json:"capacity" gorm:"not null;default:0"` // 参加登録の定員（0の場合は無制限）
+	ProjectID       *uint     `json:"project_id,omitempty" gorm:"index"`  // 参加登録したユーザーをメンバーに追加するプロジェクト
+	OrganizerID     uint      `json:"organizer_id" gorm:"not null;index"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	// リレーション
+	Organizer User `json:"organizer" gorm:"foreignKey:OrganizerID"`
+
+	// 参加登録したユーザー数（読み込み時に設定する）
+	AttendeesCount int64 `json:"attendees_count" gorm:"-"`
+}
+
+// EventAttendee イベントの参加登録モデル
+type EventAttendee struct {
+	EventID      uint      `json:"event_id" gorm:"primaryKey"`
+	UserID       uint      `json:"user_id" gorm:"primaryKey;index"`
+	RegisteredAt time.Time `json:"registered_at"`
+
+	// リレーション
+	Event Event `json:"-"`
+	User  User  `json:"user"`
+}
+
 // Task タスクモデル
 type Task struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	Title       string         `json:"title" gorm:"not null"`
-	Description string         `json:"description"`
-	ProjectID   uint           `json:"project_id" gorm:"not null"`
-	OrderIndex  int            `json:"order_index" gorm:"default:0"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	Title           string         `json:"title" gorm:"not null"`
+	Description     string         `json:"description"`
+	DescriptionHTML string         `json:"description_html" gorm:"type:text"`
+	ProjectID       uint           `json:"project_id" gorm:"not null"`
+	OrderIndex      int            `json:"order_index" gorm:"default:0"`
+	DueAt           *time.Time     `json:"due_at,omitempty"`                  // 提出の締め切り
+	Version         uint           `json:"version" gorm:"not null;default:1"` // 楽観的ロックのバージョン
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// リレーション
 	Project Project `json:"-" gorm:"foreignKey:ProjectID"`
@@ -150,10 +577,12 @@ type Vote struct {
 	Description string     `json:"description"`
 	TaskID      uint       `json:"task_id" gorm:"not null"`
 	MultiSelect bool       `json:"multi_select" gorm:"default:false"`
+	Anonymous   bool       `json:"anonymous" gorm:"default:false"` // 誰がどのオプションに投票したかをエクスポートしない
 	IsActive    bool       `json:"is_active" gorm:"default:true"`
 	CreatedBy   uint       `json:"created_by" gorm:"not null"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+	ClosesAt    *time.Time `json:"closes_at" gorm:"index"`
 	ClosedAt    *time.Time `json:"closed_at"`
 
 	// リレーション
@@ -179,12 +608,15 @@ type VoteOption struct {
 }
 
 // VoteResponse 投票回答モデル
+// 同時に投票された場合も重複しないよう、データベースの一意制約で1オプションにつき1回答
+// （単一選択の投票では1人につき1回答）に制限する
 type VoteResponse struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	VoteID    uint      `json:"vote_id" gorm:"not null"`
-	OptionID  uint      `json:"option_id" gorm:"not null"`
-	UserID    uint      `json:"user_id" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	VoteID       uint      `json:"vote_id" gorm:"not null;uniqueIndex:idx_vote_responses_option_user,priority:1;uniqueIndex:idx_vote_responses_single_user,priority:1"`
+	OptionID     uint      `json:"option_id" gorm:"not null;uniqueIndex:idx_vote_responses_option_user,priority:2"`
+	UserID       uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_vote_responses_option_user,priority:3"`
+	SingleUserID *uint     `json:"-" gorm:"uniqueIndex:idx_vote_responses_single_user,priority:2"` // 単一選択の投票のみUserIDと同じ値（NULLは重複とみなされない）
+	CreatedAt    time.Time `json:"created_at"`
 
 	// リレーション
 	Vote   Vote       `json:"-" gorm:"foreignKey:VoteID"`
@@ -192,6 +624,288 @@ type VoteResponse struct {
 	User   User       `json:"user" gorm:"foreignKey:UserID"`
 }
 
+// CritiqueCriterion タスクの講評の評価基準（1〜5の5段階で評価する）
+type CritiqueCriterion struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	TaskID      uint      `json:"task_id" gorm:"not null;index"`
+	Name        string    `json:"name" gorm:"size:100;not null"`
+	Description string    `json:"description,omitempty" gorm:"type:text"`
+	OrderIndex  int       `json:"order_index" gorm:"not null;default:0"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Critique タスクに提出された作品へのメンバーの講評（1人につき1作品1件）
+type Critique struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	TaskID    uint      `json:"task_id" gorm:"not null;uniqueIndex:idx_critiques_task_work_user,priority:1"`
+	WorkID    uint      `json:"work_id" gorm:"not null;uniqueIndex:idx_critiques_task_work_user,priority:2"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_critiques_task_work_user,priority:3;index"`
+	Comment   string    `json:"comment" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// リレーション
+	User   User            `json:"user" gorm:"foreignKey:UserID"`
+	Scores []CritiqueScore `json:"scores" gorm:"foreignKey:CritiqueID"`
+}
+
+// CritiqueScore 講評の評価基準ごとの点数
+type CritiqueScore struct {
+	CritiqueID  uint `json:"-" gorm:"primaryKey"`
+	CriterionID uint `json:"criterion_id" gorm:"primaryKey;index"`
+	Score       int  `json:"score" gorm:"not null"`
+}
+
+// 講評の点数の範囲
+const (
+	CritiqueScoreMin = 1
+	CritiqueScoreMax = 5
+)
+
+// Activity アクティビティモデル（プロジェクト内の出来事の記録）
+type Activity struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ProjectID   *uint     `json:"project_id" gorm:"index"`
+	UserID      *uint     `json:"user_id"`
+	Type        string    `json:"type" gorm:"size:64;not null"`
+	SubjectType string    `json:"subject_type" gorm:"size:64"`
+	SubjectID   uint      `json:"subject_id"`
+	Message     string    `json:"message" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// リレーション
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// Notification 通知モデル
+type Notification struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index"`
+	Type      string    `json:"type" gorm:"size:64;not null"`
+	Title     string    `json:"title" gorm:"not null"`
+	Message   string    `json:"message" gorm:"type:text"`
+	Link      string    `json:"link"`
+	IsRead    bool      `json:"is_read" gorm:"default:false"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WorkBadge 作品バッジモデル（投票の優勝など）
+type WorkBadge struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	WorkID    uint      `json:"work_id" gorm:"not null;index;uniqueIndex:idx_work_badges_work_type_vote,priority:1"`
+	Type      string    `json:"type" gorm:"size:64;not null;uniqueIndex:idx_work_badges_work_type_vote,priority:2"`
+	VoteID    *uint     `json:"vote_id" gorm:"uniqueIndex:idx_work_badges_work_type_vote,priority:3"` // 同じ投票のバッジは1つだけ（同時に終了しても二重に付与しない）
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Conversation ダイレクトメッセージの会話モデル
+type Conversation struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	LastMessageAt *time.Time `json:"last_message_at" gorm:"index"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// リレーション
+	Participants []ConversationParticipant `json:"participants,omitempty"`
+
+	// JSONレスポンス用
+	LastMessage *Message `json:"last_message,omitempty" gorm:"-"`
+	UnreadCount int64    `json:"unread_count" gorm:"-"`
+}
+
+// ConversationParticipant 会話の参加者モデル
+type ConversationParticipant struct {
+	ConversationID uint       `json:"conversation_id" gorm:"primaryKey"`
+	UserID         uint       `json:"user_id" gorm:"primaryKey;index"`
+	LastReadAt     *time.Time `json:"last_read_at"`
+	JoinedAt       time.Time  `json:"joined_at" gorm:"autoCreateTime"`
+
+	// リレーション
+	User User `json:"user" gorm:"foreignKey:UserID"`
+}
+
+// Message ダイレクトメッセージモデル
+type Message struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	ConversationID uint      `json:"conversation_id" gorm:"not null;index"`
+	SenderID       uint      `json:"sender_id" gorm:"not null"`
+	Content        string    `json:"content" gorm:"type:text;not null"`
+	CreatedAt      time.Time `json:"created_at" gorm:"index"`
+
+	// リレーション
+	Sender User `json:"sender" gorm:"foreignKey:SenderID"`
+}
+
+// デモ動画の状態
+const (
+	VideoStatusProcessing = "processing" // 変換中（変換前の動画は再生可能）
+	VideoStatusReady      = "ready"
+	VideoStatusFailed     = "failed" // 変換に失敗（変換前の動画を使用）
+)
+
+// WorkAsset 作品のアセット（スケッチの動画や大きなデータファイルなど）
+type WorkAsset struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	WorkID      uint      `json:"work_id" gorm:"not null;index"`
+	UserID      uint      `json:"user_id" gorm:"not null;index"`
+	Filename    string    `json:"filename" gorm:"not null"`
+	ContentType string    `json:"content_type" gorm:"size:128"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-" gorm:"size:255;not null"`
+	ImageID     *uint     `json:"image_id,omitempty"` // 画像の場合、変換で作成した派生画像
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WorkSnapshot 作品の再生中にキャプチャしたフレーム（作品ごとのギャラリーに表示し、サムネイルにも設定できる）
+type WorkSnapshot struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	WorkID     uint      `json:"work_id" gorm:"not null;index"`
+	UserID     uint      `json:"user_id" gorm:"not null"`
+	ImageID    uint      `json:"image_id" gorm:"not null"`
+	FrameCount int       `json:"frame_count"` // キャプチャした時点のフレーム数（不明な場合は0）
+	CreatedAt  time.Time `json:"created_at"`
+
+	// リレーション
+	Image Image `json:"image" gorm:"foreignKey:ImageID"`
+	User  User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
+
+	// 作品のサムネイルに設定されているか（サーバー側で設定する）
+	Thumbnail bool `json:"thumbnail" gorm:"-"`
+}
+
+// AssetUpload tusプロトコルによる再開可能なアップロードの状態
+// 全てのデータを受け取った時点でWorkAssetを作成する
+type AssetUpload struct {
+	ID          string    `json:"id" gorm:"primaryKey;size:32"`
+	UserID      uint      `json:"user_id" gorm:"not null;index"`
+	WorkID      uint      `json:"work_id" gorm:"not null"`
+	Filename    string    `json:"filename" gorm:"not null"`
+	ContentType string    `json:"content_type" gorm:"size:128"`
+	Length      int64     `json:"length" gorm:"not null"`
+	Offset      int64     `json:"offset" gorm:"not null;default:0"`
+	StorageKey  string    `json:"-" gorm:"size:255;not null"`
+	AssetID     *uint     `json:"asset_id"` // 完了後に作成されたアセット
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// IsComplete 全てのデータを受け取ったかどうか
+func (u *AssetUpload) IsComplete() bool {
+	return u.Offset >= u.Length
+}
+
+// Series 作品を順番に並べた連載・チュートリアル
+type Series struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Title       string         `json:"title" gorm:"not null"`
+	Description string         `json:"description" gorm:"type:text"`
+	UserID      uint           `json:"user_id" gorm:"not null;index"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// リレーション
+	User User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+
+	// シリーズの作品（順番通り、サーバー側で設定する）
+	Works []Work `json:"works,omitempty" gorm:"-"`
+}
+
+// SeriesNavigation 作品が属するシリーズ内の位置と前後の作品
+type SeriesNavigation struct {
+	ID       uint        `json:"id"`
+	Title    string      `json:"title"`
+	Position int         `json:"position"` // 1始まり
+	Total    int         `json:"total"`
+	Previous *SeriesPart `json:"previous,omitempty"`
+	Next     *SeriesPart `json:"next,omitempty"`
+}
+
+// SeriesPart シリーズの前後の作品
+type SeriesPart struct {
+	WorkID uint   `json:"work_id"`
+	Title  string `json:"title"`
+}
+
+// WorkAnnotation 作品のPDEコードの行に付ける注釈（チュートリアル用、作品の作者のみ編集可能）
+type WorkAnnotation struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	WorkID    uint      `json:"work_id" gorm:"not null;index"`
+	Line      int       `json:"line" gorm:"not null"` // 注釈を付ける行番号（1始まり）
+	Content   string    `json:"content" gorm:"type:text;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 通報の対象
+const (
+	ReportTargetWork    = "work"
+	ReportTargetComment = "comment"
+)
+
+// Report 作品・コメントへの通報（同じユーザーは同じ対象に1回のみ通報できる）
+type Report struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	TargetType string    `json:"target_type" gorm:"size:20;not null;uniqueIndex:idx_reports_target_reporter"`
+	TargetID   uint      `json:"target_id" gorm:"not null;uniqueIndex:idx_reports_target_reporter"`
+	ReporterID uint      `json:"reporter_id" gorm:"not null;uniqueIndex:idx_reports_target_reporter"`
+	Reason     string    `json:"reason" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// IPBlock アクセスを拒否するIPアドレスの範囲
+type IPBlock struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	CIDR      string     `json:"cidr" gorm:"column:cidr;size:64;not null;uniqueIndex"` // 単一のアドレスは /32（IPv6は /128）として保存する
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // nilの場合は無期限
+	CreatedBy uint       `json:"created_by" gorm:"not null"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// InviteCode 招待制の間の新規登録に使う招待コード
+type InviteCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	Code      string     `json:"code" gorm:"size:32;not null;uniqueIndex"`
+	Email     string     `json:"email,omitempty"` // 指定した場合はこのメールアドレスでのみ登録できる（順番待ちの承認で発行したコード）
+	MaxUses   int        `json:"max_uses" gorm:"not null;default:1"`
+	UsedCount int        `json:"used_count" gorm:"not null;default:0"`
+	Note      string     `json:"note,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // nilの場合は無期限
+	CreatedBy uint       `json:"created_by" gorm:"not null"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// 順番待ちの状態
+const (
+	WaitlistStatusWaiting  = "waiting"
+	WaitlistStatusApproved = "approved" // 招待コードを発行してメールで送信済み
+)
+
+// WaitlistEntry 招待制・登録停止中の新規登録の順番待ち
+type WaitlistEntry struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	Email        string     `json:"email" gorm:"size:255;not null;uniqueIndex"`
+	Name         string     `json:"name"`
+	Message      string     `json:"message,omitempty" gorm:"type:text"` // 利用したい理由など
+	Status       string     `json:"status" gorm:"size:20;not null;default:waiting;index"`
+	InviteCodeID *uint      `json:"invite_code_id,omitempty"`
+	ApprovedAt   *time.Time `json:"approved_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// アクティビティ・通知・バッジの種類
+const (
+	ActivityTypeVoteWinner       = "vote_winner"
+	NotificationTypeVoteWinner   = "vote_winner"
+	NotificationTypeMessage      = "message"
+	NotificationTypeReported     = "reported"
+	NotificationTypeConversion   = "conversion_failed"
+	NotificationTypeCollaborator = "collaborator_invited"
+	NotificationTypeComment      = "comment"
+	BadgeTypeWinner              = "winner"
+)
+
 // TableName テーブル名を指定
 func (ProjectMember) TableName() string {
 	return "project_members"
@@ -200,3 +914,232 @@ func (ProjectMember) TableName() string {
 func (TaskWork) TableName() string {
 	return "task_works"
 }
+
+// ConversionLog PDE変換の実行履歴（ユーザーごとの変換回数制限に使用）
+type ConversionLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;index:idx_conversion_logs_user_created"`
+	CreatedAt time.Time `json:"created_at" gorm:"index:idx_conversion_logs_user_created"`
+}
+
+// InvocationLog Lambda関数の呼び出し履歴（変換の費用と所要時間の分析に使用）
+// 一時的なエラーによる再試行も1回ずつ記録する
+type InvocationLog struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	FunctionName  string    `json:"function_name" gorm:"size:140;not null"`
+	Version       string    `json:"version" gorm:"size:64;not null;index"`
+	Attempt       int       `json:"attempt" gorm:"not null;default:0"` // 再試行の回数（初回は0）
+	DurationMs    int64     `json:"duration_ms" gorm:"not null"`
+	PayloadBytes  int       `json:"payload_bytes" gorm:"not null"`
+	ResponseBytes int       `json:"response_bytes" gorm:"not null;default:0"`
+	Success       bool      `json:"success" gorm:"not null"`
+	Error         string    `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt     time.Time `json:"created_at" gorm:"index"`
+}
+
+// 画像のアップロード種別（種別ごとに変換の手順を設定する）
+const (
+	ImageKindThumbnail = "thumbnail" // 作品のサムネイル
+	ImageKindAvatar    = "avatar"    // ユーザーのアバター
+	ImageKindAsset     = "asset"     // 作品のアセットのうち画像のもの
+	ImageKindSnapshot  = "snapshot"  // 作品の再生中にキャプチャしたフレーム
+)
+
+// Image アップロードされた画像（メタデータを除去してCloudinaryに保存）
+type Image struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	UserID    uint           `json:"user_id" gorm:"not null;index"`
+	Kind      string         `json:"kind" gorm:"size:20;not null;index"`
+	PublicID  string         `json:"-" gorm:"size:255;not null"`
+	URL       string         `json:"url"`
+	Format    string         `json:"format" gorm:"size:16"`
+	Width     int            `json:"width"`
+	Height    int            `json:"height"`
+	Bytes     int64          `json:"bytes"`
+	Blurhash  string         `json:"blurhash,omitempty" gorm:"size:64"` // 読み込み中に表示するぼかし画像
+	Variants  []ImageVariant `json:"variants" gorm:"foreignKey:ImageID"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// ImageVariant 変換で作成した派生画像（縮小・WebPなど）
+type ImageVariant struct {
+	ID      uint   `json:"-" gorm:"primaryKey"`
+	ImageID uint   `json:"-" gorm:"not null;index"`
+	Name    string `json:"name" gorm:"size:32;not null"`
+	URL     string `json:"url"`
+	Format  string `json:"format" gorm:"size:16"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// 変換の再試行ジョブの状態
+const (
+	ConversionJobStatusPending   = "pending"
+	ConversionJobStatusSucceeded = "succeeded"
+	ConversionJobStatusDead      = "dead" // 上限まで再試行しても失敗した（作者に通知済み）
+)
+
+// ConversionJob 作品の作成・更新時に失敗したPDE変換の再試行ジョブ（作品ごとに1件）
+type ConversionJob struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	WorkID      uint      `json:"work_id" gorm:"not null;uniqueIndex"`
+	Status      string    `json:"status" gorm:"size:20;not null;index:idx_conversion_jobs_status_next"`
+	Attempts    int       `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts int       `json:"max_attempts" gorm:"not null"`
+	NextRunAt   time.Time `json:"next_run_at" gorm:"index:idx_conversion_jobs_status_next"`
+	LastError   string    `json:"last_error,omitempty" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// 再変換キャンペーンの状態
+const (
+	ReconversionStatusRunning   = "running"
+	ReconversionStatusCompleted = "completed"
+	ReconversionStatusCancelled = "cancelled"
+)
+
+// ReconversionCampaign 古い変換バージョンで生成された作品を再変換するキャンペーン
+type ReconversionCampaign struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	TargetVersion string     `json:"target_version" gorm:"size:64;not null"`
+	Status        string     `json:"status" gorm:"size:20;not null;index"`
+	BatchSize     int        `json:"batch_size" gorm:"not null"`
+	Total         int        `json:"total"`
+	Processed     int        `json:"processed"`
+	Succeeded     int        `json:"succeeded"`
+	Failed        int        `json:"failed"`
+	LastWorkID    uint       `json:"-"`
+	LastError     string     `json:"last_error,omitempty" gorm:"type:text"`
+	CreatedBy     uint       `json:"created_by" gorm:"not null"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CompletedAt   *time.Time `json:"completed_at"`
+}
+
+// 一括タグ付けジョブの操作
+const (
+	RetagActionAdd    = "add"
+	RetagActionRemove = "remove"
+)
+
+// 一括タグ付けジョブの状態
+const (
+	RetagStatusRunning   = "running"
+	RetagStatusCompleted = "completed"
+	RetagStatusCancelled = "cancelled"
+)
+
+// RetagJob 検索条件に一致する作品にタグを一括で追加・削除するジョブ（管理者用）
+// UndoOfIDがある場合は、そのジョブでタグを変更した作品を元に戻すジョブ
+type RetagJob struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	Action      string     `json:"action" gorm:"size:10;not null"`
+	TagID       uint       `json:"tag_id" gorm:"not null"`
+	TagName     string     `json:"tag_name" gorm:"not null"`
+	Search      string     `json:"search,omitempty"`                  // タイトル・説明文の検索語
+	FilterTag   string     `json:"filter_tag,omitempty"`              // 既に付いているタグ
+	License     string     `json:"license,omitempty" gorm:"size:32"`  // ライセンス
+	Language    string     `json:"language,omitempty" gorm:"size:8"`  // 言語
+	UserID      *uint      `json:"user_id,omitempty"`                 // 作者
+	UndoOfID    *uint      `json:"undo_of_id,omitempty" gorm:"index"` // 取り消したジョブ
+	UndoneByID  *uint      `json:"undone_by_id,omitempty"`            // このジョブを取り消したジョブ
+	Status      string     `json:"status" gorm:"size:20;not null;index"`
+	BatchSize   int        `json:"batch_size" gorm:"not null"`
+	Total       int        `json:"total"`     // 開始時点で対象だった作品数
+	Processed   int        `json:"processed"` // 処理した作品数
+	Changed     int        `json:"changed"`   // タグを追加・削除した作品数（既に付いている・付いていない作品は変更しない）
+	LastWorkID  uint       `json:"-"`
+	LastError   string     `json:"last_error,omitempty" gorm:"type:text"`
+	CreatedBy   uint       `json:"created_by" gorm:"not null"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+// RetagJobWork 一括タグ付けジョブでタグを変更した作品（取り消しに使う）
+type RetagJobWork struct {
+	JobID  uint `json:"job_id" gorm:"primaryKey"`
+	WorkID uint `json:"work_id" gorm:"primaryKey"`
+}
+
+// 監査ログの操作と対象
+const (
+	AuditActionPurgeUser = "purge_user"
+	AuditActionPurgeWork = "purge_work"
+
+	AuditTargetUser = "user"
+	AuditTargetWork = "work"
+)
+
+// AuditLog 管理者による取り消せない操作の記録（対象が削除されても残す）
+type AuditLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Action     string    `json:"action" gorm:"size:32;not null;index"`
+	TargetType string    `json:"target_type" gorm:"size:20;not null"`
+	TargetID   uint      `json:"target_id" gorm:"not null;index"`
+	ActorID    uint      `json:"actor_id" gorm:"not null"`
+	Reason     string    `json:"reason" gorm:"type:text"` // 削除依頼の受付番号など
+	Detail     string    `json:"detail" gorm:"type:text"` // テーブルごとの削除件数（JSON、個人情報を含めない）
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AllModels マイグレーション対象の全てのモデル（作成順）
+func AllModels() []interface{} {
+	return []interface{}{
+		&User{},
+		&Tag{},
+		&Work{},
+		&Like{},
+		&Comment{},
+		&Project{},
+		&ProjectMember{},
+		&Task{},
+		&TaskWork{},
+		&Vote{},
+		&VoteOption{},
+		&VoteResponse{},
+		&Activity{},
+		&Notification{},
+		&WorkBadge{},
+		&Conversation{},
+		&ConversationParticipant{},
+		&Message{},
+		&ConversionLog{},
+		&ConversionJob{},
+		&InvocationLog{},
+		&ReconversionCampaign{},
+		&Image{},
+		&ImageVariant{},
+		&WorkAsset{},
+		&AssetUpload{},
+		&WorkAnnotation{},
+		&Series{},
+		&Report{},
+		&IPBlock{},
+		&HandleHistory{},
+		&Event{},
+		&EventAttendee{},
+		&WorkCollaborator{},
+		&WorkSnapshot{},
+		&InviteCode{},
+		&WaitlistEntry{},
+		&RetagJob{},
+		&RetagJobWork{},
+		&AuditLog{},
+		&CommentSubscription{},
+		&CritiqueCriterion{},
+		&Critique{},
+		&CritiqueScore{},
+		&WorkRanking{},
+		&Organization{},
+		&OrganizationMember{},
+		&Subscription{},
+		&OAuthProvider{},
+		&LinkPreview{},
+		&RefreshToken{},
+		&RevokedToken{},
+		&PersonalToken{},
+	}
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ActivityRepository アクティビティに関するデータベース操作を行うインターフェース
+type ActivityRepository interface {
+	Create(ctx context.Context, activity *models.Activity) error
+	ListByProject(ctx context.Context, projectID uint, limit int) ([]models.Activity, error)
+}
+
+// activityRepository ActivityRepositoryの実装
+type activityRepository struct {
+	db *gorm.DB
+}
+
+// NewActivityRepository ActivityRepositoryを作成
+func NewActivityRepository(db *gorm.DB) ActivityRepository {
+	return &activityRepository{db: db}
+}
+
+// Create 新しいアクティビティを作成
+func (r *activityRepository) Create(ctx context.Context, activity *models.Activity) error {
+	return r.db.WithContext(ctx).Create(activity).Error
+}
+
+// ListByProject プロジェクトのアクティビティ一覧を取得
+func (r *activityRepository) ListByProject(ctx context.Context, projectID uint, limit int) ([]models.Activity, error) {
+	var activities []models.Activity
+
+	if err := r.db.WithContext(ctx).Where("project_id = ?", projectID).
+		Preload("User").
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&activities).Error; err != nil {
+		return nil, err
+	}
+
+	return activities, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AnnotationRepository 作品のコード注釈に関するデータベース操作を行うインターフェース
+type AnnotationRepository interface {
+	Create(ctx context.Context, annotation *models.WorkAnnotation) error
+	FindByID(ctx context.Context, id uint) (*models.WorkAnnotation, error)
+	Update(ctx context.Context, annotation *models.WorkAnnotation) error
+	Delete(ctx context.Context, id uint) error
+	ListByWork(ctx context.Context, workID uint) ([]models.WorkAnnotation, error)
+	CountByWork(ctx context.Context, workID uint) (int64, error)
+}
+
+// annotationRepository AnnotationRepositoryの実装
+type annotationRepository struct {
+	db *gorm.DB
+}
+
+// NewAnnotationRepository AnnotationRepositoryを作成
+func NewAnnotationRepository(db *gorm.DB) AnnotationRepository {
+	return &annotationRepository{db: db}
+}
+
+// Create 新しい注釈を作成
+func (r *annotationRepository) Create(ctx context.Context, annotation *models.WorkAnnotation) error {
+	return r.db.WithContext(ctx).Create(annotation).Error
+}
+
+// FindByID IDで注釈を検索
+func (r *annotationRepository) FindByID(ctx context.Context, id uint) (*models.WorkAnnotation, error) {
+	var annotation models.WorkAnnotation
+	if err := r.db.WithContext(ctx).First(&annotation, id).Error; err != nil {
+		return nil, err
+	}
+	return &annotation, nil
+}
+
+// Update 注釈を更新
+func (r *annotationRepository) Update(ctx context.Context, annotation *models.WorkAnnotation) error {
+	return r.db.WithContext(ctx).Save(annotation).Error
+}
+
+// Delete 注釈を削除
+func (r *annotationRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.WorkAnnotation{}, id).Error
+}
+
+// ListByWork 作品の注釈一覧を行番号順に取得
+func (r *annotationRepository) ListByWork(ctx context.Context, workID uint) ([]models.WorkAnnotation, error) {
+	var annotations []models.WorkAnnotation
+	if err := r.db.WithContext(ctx).Where("work_id = ?", workID).
+		Order("line ASC, id ASC").
+		Find(&annotations).Error; err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
+
+// CountByWork 作品の注釈数を取得
+func (r *annotationRepository) CountByWork(ctx context.Context, workID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.WorkAnnotation{}).
+		Where("work_id = ?", workID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AssetRepository 作品のアセットとアップロードに関するデータベース操作を行うインターフェース
+type AssetRepository interface {
+	CreateUpload(ctx context.Context, upload *models.AssetUpload) error
+	FindUpload(ctx context.Context, id string) (*models.AssetUpload, error)
+	UpdateUploadOffset(ctx context.Context, id string, offset int64) error
+	CompleteUpload(ctx context.Context, upload *models.AssetUpload, asset *models.WorkAsset) error
+	DeleteUpload(ctx context.Context, id string) error
+	Create(ctx context.Context, asset *models.WorkAsset) error
+	FindByID(ctx context.Context, id uint) (*models.WorkAsset, error)
+	FindByWorkAndFilename(ctx context.Context, workID uint, filename string) (*models.WorkAsset, error)
+	ListByWork(ctx context.Context, workID uint) ([]models.WorkAsset, error)
+	SetImage(ctx context.Context, id, imageID uint) error
+}
+
+// assetRepository AssetRepositoryの実装
+type assetRepository struct {
+	db *gorm.DB
+}
+
+// NewAssetRepository AssetRepositoryを作成
+func NewAssetRepository(db *gorm.DB) AssetRepository {
+	return &assetRepository{db: db}
+}
+
+// CreateUpload 新しいアップロードを作成
+func (r *assetRepository) CreateUpload(ctx context.Context, upload *models.AssetUpload) error {
+	return r.db.WithContext(ctx).Create(upload).Error
+}
+
+// FindUpload IDでアップロードを検索
+func (r *assetRepository) FindUpload(ctx context.Context, id string) (*models.AssetUpload, error) {
+	var upload models.AssetUpload
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&upload).Error; err != nil {
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// UpdateUploadOffset 受け取り済みのバイト数を更新
+func (r *assetRepository) UpdateUploadOffset(ctx context.Context, id string, offset int64) error {
+	return r.db.WithContext(ctx).Model(&models.AssetUpload{}).Where("id = ?", id).Update("offset", offset).Error
+}
+
+// CompleteUpload アセットを作成し、アップロードを完了状態にする
+func (r *assetRepository) CompleteUpload(ctx context.Context, upload *models.AssetUpload, asset *models.WorkAsset) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(asset).Error; err != nil {
+			return err
+		}
+		upload.AssetID = &asset.ID
+		return tx.Model(&models.AssetUpload{}).Where("id = ?", upload.ID).Updates(map[string]interface{}{
+			"offset":   upload.Offset,
+			"asset_id": asset.ID,
+		}).Error
+	})
+}
+
+// DeleteUpload アップロードを削除
+func (r *assetRepository) DeleteUpload(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.AssetUpload{}).Error
+}
+
+// Create 新しいアセットを作成
+func (r *assetRepository) Create(ctx context.Context, asset *models.WorkAsset) error {
+	return r.db.WithContext(ctx).Create(asset).Error
+}
+
+// FindByID IDでアセットを検索
+func (r *assetRepository) FindByID(ctx context.Context, id uint) (*models.WorkAsset, error) {
+	var asset models.WorkAsset
+	if err := r.db.WithContext(ctx).First(&asset, id).Error; err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// FindByWorkAndFilename 作品のアセットをファイル名で検索（同名の場合は最新のもの）
+func (r *assetRepository) FindByWorkAndFilename(ctx context.Context, workID uint, filename string) (*models.WorkAsset, error) {
+	var asset models.WorkAsset
+	if err := r.db.WithContext(ctx).
+		Where("work_id = ? AND filename = ?", workID, filename).
+		Order("id DESC").
+		First(&asset).Error; err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// ListByWork 作品のアセット一覧を取得
+func (r *assetRepository) ListByWork(ctx context.Context, workID uint) ([]models.WorkAsset, error) {
+	var assets []models.WorkAsset
+	if err := r.db.WithContext(ctx).Where("work_id = ?", workID).Order("id ASC").Find(&assets).Error; err != nil {
+		return nil, err
+	}
+	return assets, nil
+}
+
+// SetImage 画像のアセットに変換で作成した派生画像を関連付ける
+func (r *assetRepository) SetImage(ctx context.Context, id, imageID uint) error {
+	return r.db.WithContext(ctx).Model(&models.WorkAsset{}).Where("id = ?", id).Update("image_id", imageID).Error
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository 監査ログに関するデータベース操作を行うインターフェース
+type AuditLogRepository interface {
+	Create(ctx context.Context, log *models.AuditLog) error
+	List(ctx context.Context, action string, page, limit int) ([]models.AuditLog, int64, error)
+}
+
+// auditLogRepository AuditLogRepositoryの実装
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository AuditLogRepositoryを作成
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+// Create 監査ログを記録
+func (r *auditLogRepository) Create(ctx context.Context, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// List 監査ログを新しい順に取得（actionが空の場合は全ての操作）
+func (r *auditLogRepository) List(ctx context.Context, action string, page, limit int) ([]models.AuditLog, int64, error) {
+	var logs []models.AuditLog
+	var total int64
+
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&models.AuditLog{})
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+
+	// 合計数を取得
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// データを取得
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC, id DESC").
+		Find(&logs).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrBadgeAlreadyAwarded 同じ投票のバッジが既に付与されている
+var ErrBadgeAlreadyAwarded = errors.New("このバッジは既に付与されています")
+
+// BadgeRepository 作品バッジに関するデータベース操作を行うインターフェース
+type BadgeRepository interface {
+	Create(ctx context.Context, badge *models.WorkBadge) error
+	ListByWork(ctx context.Context, workID uint) ([]models.WorkBadge, error)
+	Exists(ctx context.Context, workID uint, badgeType string, voteID *uint) (bool, error)
+}
+
+// badgeRepository BadgeRepositoryの実装
+type badgeRepository struct {
+	db *gorm.DB
+}
+
+// NewBadgeRepository BadgeRepositoryを作成
+func NewBadgeRepository(db *gorm.DB) BadgeRepository {
+	return &badgeRepository{db: db}
+}
+
+// Create 新しいバッジを作成（同じ作品・種類・投票のバッジがある場合は ErrBadgeAlreadyAwarded）
+func (r *badgeRepository) Create(ctx context.Context, badge *models.WorkBadge) error {
+	err := r.db.WithContext(ctx).Create(badge).Error
+	if isDuplicateKey(err) {
+		return ErrBadgeAlreadyAwarded
+	}
+	return err
+}
+
+// ListByWork 作品のバッジ一覧を取得
+func (r *badgeRepository) ListByWork(ctx context.Context, workID uint) ([]models.WorkBadge, error) {
+	var badges []models.WorkBadge
+	if err := r.db.WithContext(ctx).Where("work_id = ?", workID).
+		Order("created_at DESC").
+		Find(&badges).Error; err != nil {
+		return nil, err
+	}
+	return badges, nil
+}
+
+// Exists 同じバッジが既に付与されているか確認
+func (r *badgeRepository) Exists(ctx context.Context, workID uint, badgeType string, voteID *uint) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&models.WorkBadge{}).
+		Where("work_id = ? AND type = ?", workID, badgeType)
+	if voteID != nil {
+		query = query.Where("vote_id = ?", *voteID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
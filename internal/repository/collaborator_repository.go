@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrAlreadyCollaborator 既に共同編集者か招待済み（同時に招待された場合など）
+var ErrAlreadyCollaborator = errors.New("このユーザーは既に共同編集者か招待済みです")
+
+// CollaboratorRepository 作品の共同編集者に関するデータベース操作を行うインターフェース
+type CollaboratorRepository interface {
+	Create(ctx context.Context, collaborator *models.WorkCollaborator) error
+	Find(ctx context.Context, workID, userID uint) (*models.WorkCollaborator, error)
+	ListByWork(ctx context.Context, workID uint, includePending bool) ([]models.WorkCollaborator, error)
+	ListAcceptedByWorks(ctx context.Context, workIDs []uint) ([]models.WorkCollaborator, error)
+	ListInvitations(ctx context.Context, userID uint) ([]models.WorkCollaborator, error)
+	Accept(ctx context.Context, workID, userID uint, acceptedAt time.Time) error
+	UpdatePermission(ctx context.Context, workID, userID uint, permission string) error
+	Delete(ctx context.Context, workID, userID uint) error
+}
+
+// collaboratorRepository CollaboratorRepositoryの実装
+type collaboratorRepository struct {
+	db *gorm.DB
+}
+
+// NewCollaboratorRepository CollaboratorRepositoryを作成
+func NewCollaboratorRepository(db *gorm.DB) CollaboratorRepository {
+	return &collaboratorRepository{db: db}
+}
+
+// Create 共同編集者を招待（既に登録されている場合は ErrAlreadyCollaborator）
+func (r *collaboratorRepository) Create(ctx context.Context, collaborator *models.WorkCollaborator) error {
+	err := r.db.WithContext(ctx).Omit("User", "Work").Create(collaborator).Error
+	if isDuplicateKey(err) {
+		return ErrAlreadyCollaborator
+	}
+	return err
+}
+
+// Find 作品とユーザーで共同編集者を検索（招待中を含む）
+func (r *collaboratorRepository) Find(ctx context.Context, workID, userID uint) (*models.WorkCollaborator, error) {
+	var collaborator models.WorkCollaborator
+	if err := r.db.WithContext(ctx).Preload("User").
+		Where("work_id = ? AND user_id = ?", workID, userID).
+		First(&collaborator).Error; err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+// ListByWork 作品の共同編集者を招待の早い順に取得（includePendingがfalseの場合は承認済みのみ）
+func (r *collaboratorRepository) ListByWork(ctx context.Context, workID uint, includePending bool) ([]models.WorkCollaborator, error) {
+	var collaborators []models.WorkCollaborator
+	query := r.db.WithContext(ctx).Where("work_id = ?", workID)
+	if !includePending {
+		query = query.Where("accepted_at IS NOT NULL")
+	}
+	if err := query.Preload("User").
+		Order("created_at ASC, user_id ASC").
+		Find(&collaborators).Error; err != nil {
+		return nil, err
+	}
+	return collaborators, nil
+}
+
+// ListAcceptedByWorks 複数の作品の承認済みの共同編集者を招待の早い順に取得
+func (r *collaboratorRepository) ListAcceptedByWorks(ctx context.Context, workIDs []uint) ([]models.WorkCollaborator, error) {
+	collaborators := []models.WorkCollaborator{}
+	if len(workIDs) == 0 {
+		return collaborators, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("work_id IN ? AND accepted_at IS NOT NULL", workIDs).
+		Preload("User").
+		Order("created_at ASC, user_id ASC").
+		Find(&collaborators).Error; err != nil {
+		return nil, err
+	}
+	return collaborators, nil
+}
+
+// ListInvitations ユーザーが承認していない招待を新しい順に取得（削除された作品を除く）
+func (r *collaboratorRepository) ListInvitations(ctx context.Context, userID uint) ([]models.WorkCollaborator, error) {
+	var collaborators []models.WorkCollaborator
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN works ON works.id = work_collaborators.work_id AND works.deleted_at IS NULL").
+		Where("work_collaborators.user_id = ? AND work_collaborators.accepted_at IS NULL", userID).
+		Preload("User").
+		Preload("Work").
+		Preload("Work.User").
+		Order("work_collaborators.created_at DESC").
+		Find(&collaborators).Error; err != nil {
+		return nil, err
+	}
+	return collaborators, nil
+}
+
+// Accept 招待を承認
+func (r *collaboratorRepository) Accept(ctx context.Context, workID, userID uint, acceptedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.WorkCollaborator{}).
+		Where("work_id = ? AND user_id = ?", workID, userID).
+		Update("accepted_at", acceptedAt).Error
+}
+
+// UpdatePermission 共同編集者の権限を変更
+func (r *collaboratorRepository) UpdatePermission(ctx context.Context, workID, userID uint, permission string) error {
+	return r.db.WithContext(ctx).Model(&models.WorkCollaborator{}).
+		Where("work_id = ? AND user_id = ?", workID, userID).
+		Update("permission", permission).Error
+}
+
+// Delete 共同編集者を外す（招待の取り消し・辞退を含む）
+func (r *collaboratorRepository) Delete(ctx context.Context, workID, userID uint) error {
+	return r.db.WithContext(ctx).Where("work_id = ? AND user_id = ?", workID, userID).Delete(&models.WorkCollaborator{}).Error
+}
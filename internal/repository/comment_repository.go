@@ -1,20 +1,31 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CommentRepository コメントに関するデータベース操作を行うインターフェース
 type CommentRepository interface {
-	Create(comment *models.Comment) error
-	FindByID(id uint) (*models.Comment, error)
-	Update(comment *models.Comment) error
-	Delete(id uint) error
-	ListByWork(workID uint, page, limit int) ([]models.Comment, int64, error)
+	Create(ctx context.Context, comment *models.Comment) error
+	FindByID(ctx context.Context, id uint) (*models.Comment, error)
+	Update(ctx context.Context, comment *models.Comment) error
+	Delete(ctx context.Context, id uint) error
+	ListByWork(ctx context.Context, workID uint, page, limit int, sort string, afterID uint) ([]models.Comment, int64, error)
+	FindPinnedByWork(ctx context.Context, workID uint) (*models.Comment, error)
+	SetPinned(ctx context.Context, comment *models.Comment, pinned bool) error
+	SetResolved(ctx context.Context, comment *models.Comment, resolvedAt *time.Time) error
+	EachByWork(ctx context.Context, workID uint, batchSize int, fn func(comments []models.Comment) error) error
+	FindSubscription(ctx context.Context, workID, userID uint) (*models.CommentSubscription, error)
+	SetSubscription(ctx context.Context, subscription *models.CommentSubscription) error
+	CreateSubscription(ctx context.Context, subscription *models.CommentSubscription) error
+	ListSubscriptions(ctx context.Context, workID uint) ([]models.CommentSubscription, error)
 }
 
 // commentRepository CommentRepositoryの実装
@@ -27,39 +38,68 @@ func NewCommentRepository(db *gorm.DB) CommentRepository {
 	return &commentRepository{db: db}
 }
 
-// Create 新しいコメントを作成
-func (r *commentRepository) Create(comment *models.Comment) error {
-	return r.db.Create(comment).Error
+// Create 新しいコメントを作成し、作品のコメント数（返信の場合は返信先の返信数も）を加算
+func (r *commentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		if comment.ParentID != nil {
+			if err := tx.Model(&models.Comment{}).Where("id = ?", *comment.ParentID).
+				UpdateColumn("replies_count", gorm.Expr("replies_count + 1")).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Work{}).Where("id = ?", comment.WorkID).
+			UpdateColumn("comments_count", gorm.Expr("comments_count + 1")).Error
+	})
 }
 
 // FindByID IDでコメントを検索
-func (r *commentRepository) FindByID(id uint) (*models.Comment, error) {
+func (r *commentRepository) FindByID(ctx context.Context, id uint) (*models.Comment, error) {
 	var comment models.Comment
-	if err := r.db.Preload("User").First(&comment, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("User").First(&comment, id).Error; err != nil {
 		return nil, err
 	}
 	return &comment, nil
 }
 
 // Update コメントを更新
-func (r *commentRepository) Update(comment *models.Comment) error {
-	return r.db.Save(comment).Error
+func (r *commentRepository) Update(ctx context.Context, comment *models.Comment) error {
+	return r.db.WithContext(ctx).Save(comment).Error
 }
 
-// Delete コメントを削除
-func (r *commentRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Comment{}, id).Error
+// Delete コメントを削除し、作品のコメント数（返信の場合は返信先の返信数も）を減算
+func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var comment models.Comment
+		if err := tx.First(&comment, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&comment).Error; err != nil {
+			return err
+		}
+		if comment.ParentID != nil {
+			if err := tx.Model(&models.Comment{}).Where("id = ?", *comment.ParentID).
+				UpdateColumn("replies_count", gorm.Expr("GREATEST(replies_count - 1, 0)")).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Work{}).Where("id = ?", comment.WorkID).
+			UpdateColumn("comments_count", gorm.Expr("GREATEST(comments_count - 1, 0)")).Error
+	})
 }
 
 // ListByWork 作品のコメント一覧を取得
-func (r *commentRepository) ListByWork(workID uint, page, limit int) ([]models.Comment, int64, error) {
+// afterIDを指定した場合は、ソート順でそのコメントより後ろのコメントを返す（pageは無視される）
+func (r *commentRepository) ListByWork(ctx context.Context, workID uint, page, limit int, sort string, afterID uint) ([]models.Comment, int64, error) {
 	var comments []models.Comment
 	var total int64
 
 	offset := (page - 1) * limit
 
-	query := r.db.Model(&models.Comment{}).
-		Where("work_id = ?", workID).
+	query := r.db.WithContext(ctx).Model(&models.Comment{}).
+		Where("work_id = ? AND hidden_at IS NULL", workID).
 		Preload("User")
 
 	// 合計数を取得
@@ -67,14 +107,133 @@ func (r *commentRepository) ListByWork(workID uint, page, limit int) ([]models.C
 		return nil, 0, err
 	}
 
+	// 基準となるコメントを取得
+	var after *models.Comment
+	if afterID > 0 {
+		after = &models.Comment{}
+		if err := r.db.WithContext(ctx).Where("work_id = ?", workID).First(after, afterID).Error; err != nil {
+			return nil, 0, err
+		}
+		offset = 0
+	}
+
+	// ピン留めされたコメントは別途先頭に表示するため除外
+	query = query.Where("pinned = ?", false)
+
+	// ソート順を適用（同順位はIDで並べて順序を安定させる）
+	switch sort {
+	case "oldest":
+		if after != nil {
+			query = query.Where("id > ?", after.ID)
+		}
+		query = query.Order("id ASC")
+	case "top":
+		if after != nil {
+			query = query.Where("replies_count < ? OR (replies_count = ? AND id < ?)", after.RepliesCount, after.RepliesCount, after.ID)
+		}
+		query = query.Order("replies_count DESC, id DESC")
+	default: // "newest"
+		if after != nil {
+			query = query.Where("id < ?", after.ID)
+		}
+		query = query.Order("id DESC")
+	}
+
 	// データを取得
 	if err := query.
 		Offset(offset).
 		Limit(limit).
-		Order("created_at DESC").
 		Find(&comments).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, 0, err
 	}
 
 	return comments, total, nil
 }
+
+// FindPinnedByWork 作品のピン留めされたコメントを取得（ない場合はnil）
+func (r *commentRepository) FindPinnedByWork(ctx context.Context, workID uint) (*models.Comment, error) {
+	var comments []models.Comment
+	if err := r.db.WithContext(ctx).
+		Where("work_id = ? AND pinned = ? AND hidden_at IS NULL", workID, true).
+		Preload("User").
+		Limit(1).
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, nil
+	}
+	return &comments[0], nil
+}
+
+// SetPinned コメントのピン留めを設定（ピン留めする場合は同じ作品の他のコメントのピン留めを解除）
+func (r *commentRepository) SetPinned(ctx context.Context, comment *models.Comment, pinned bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if pinned {
+			if err := tx.Model(&models.Comment{}).
+				Where("work_id = ? AND pinned = ? AND id <> ?", comment.WorkID, true, comment.ID).
+				UpdateColumn("pinned", false).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&models.Comment{}).Where("id = ?", comment.ID).
+			UpdateColumn("pinned", pinned).Error; err != nil {
+			return err
+		}
+		comment.Pinned = pinned
+		return nil
+	})
+}
+
+// SetResolved コメントの対応済み日時を設定（nilで未対応に戻す）
+func (r *commentRepository) SetResolved(ctx context.Context, comment *models.Comment, resolvedAt *time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&models.Comment{}).Where("id = ?", comment.ID).
+		UpdateColumn("resolved_at", resolvedAt).Error; err != nil {
+		return err
+	}
+	comment.ResolvedAt = resolvedAt
+	return nil
+}
+
+// EachByWork 作品のコメントを古い順にbatchSize件ずつ読み込んでfnに渡す
+func (r *commentRepository) EachByWork(ctx context.Context, workID uint, batchSize int, fn func(comments []models.Comment) error) error {
+	var comments []models.Comment
+	return r.db.WithContext(ctx).
+		Where("work_id = ?", workID).
+		Preload("User").
+		Order("id ASC").
+		FindInBatches(&comments, batchSize, func(tx *gorm.DB, batch int) error {
+			return fn(comments)
+		}).Error
+}
+
+// FindSubscription ユーザーが設定した作品のコメントの通知の設定を検索
+func (r *commentRepository) FindSubscription(ctx context.Context, workID, userID uint) (*models.CommentSubscription, error) {
+	var subscription models.CommentSubscription
+	if err := r.db.WithContext(ctx).Where("work_id = ? AND user_id = ?", workID, userID).
+		First(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// SetSubscription 作品のコメントの通知の設定を保存（設定済みの場合は上書き）
+func (r *commentRepository) SetSubscription(ctx context.Context, subscription *models.CommentSubscription) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"subscribed", "updated_at"}),
+	}).Create(subscription).Error
+}
+
+// CreateSubscription 作品のコメントの通知の設定を保存（設定済みの場合は変更しない）
+func (r *commentRepository) CreateSubscription(ctx context.Context, subscription *models.CommentSubscription) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(subscription).Error
+}
+
+// ListSubscriptions 作品のコメントの通知の設定を全て取得
+func (r *commentRepository) ListSubscriptions(ctx context.Context, workID uint) ([]models.CommentSubscription, error) {
+	var subscriptions []models.CommentSubscription
+	if err := r.db.WithContext(ctx).Where("work_id = ?", workID).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ConversionJobRepository PDE変換の再試行ジョブに関するデータベース操作を行うインターフェース
+type ConversionJobRepository interface {
+	Enqueue(ctx context.Context, workID uint, maxAttempts int, runAt time.Time) error
+	FindByWorkID(ctx context.Context, workID uint) (*models.ConversionJob, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]models.ConversionJob, error)
+	Claim(ctx context.Context, job *models.ConversionJob, leaseUntil time.Time) (bool, error)
+	Update(ctx context.Context, job *models.ConversionJob) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// conversionJobRepository ConversionJobRepositoryの実装
+type conversionJobRepository struct {
+	db *gorm.DB
+}
+
+// NewConversionJobRepository ConversionJobRepositoryを作成
+func NewConversionJobRepository(db *gorm.DB) ConversionJobRepository {
+	return &conversionJobRepository{db: db}
+}
+
+// Enqueue 作品の変換ジョブを登録（既にある場合は試行回数を戻して待機中にする）
+func (r *conversionJobRepository) Enqueue(ctx context.Context, workID uint, maxAttempts int, runAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var job models.ConversionJob
+		err := tx.Where("work_id = ?", workID).First(&job).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		job.WorkID = workID
+		job.Status = models.ConversionJobStatusPending
+		job.Attempts = 0
+		job.MaxAttempts = maxAttempts
+		job.NextRunAt = runAt
+		job.LastError = ""
+		if job.ID == 0 {
+			return tx.Create(&job).Error
+		}
+		return tx.Save(&job).Error
+	})
+}
+
+// FindByWorkID 作品の変換ジョブを取得
+func (r *conversionJobRepository) FindByWorkID(ctx context.Context, workID uint) (*models.ConversionJob, error) {
+	var job models.ConversionJob
+	if err := r.db.WithContext(ctx).Where("work_id = ?", workID).First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListDue 実行時刻を過ぎた待機中のジョブを実行時刻の古い順に取得
+func (r *conversionJobRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.ConversionJob, error) {
+	var jobs []models.ConversionJob
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND next_run_at <= ?", models.ConversionJobStatusPending, now).
+		Order("next_run_at ASC, id ASC").
+		Limit(limit).
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// Claim ジョブの実行時刻を leaseUntil まで延ばして実行権を得る
+// 他のサーバーが先に取得していた場合（実行時刻が変わっている場合）はfalseを返す
+func (r *conversionJobRepository) Claim(ctx context.Context, job *models.ConversionJob, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.ConversionJob{}).
+		Where("id = ? AND status = ? AND next_run_at = ?", job.ID, models.ConversionJobStatusPending, job.NextRunAt).
+		Update("next_run_at", leaseUntil)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	job.NextRunAt = leaseUntil
+	return true, nil
+}
+
+// Update ジョブを更新
+func (r *conversionJobRepository) Update(ctx context.Context, job *models.ConversionJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+// Delete ジョブを削除
+func (r *conversionJobRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.ConversionJob{}, id).Error
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ConversionRepository PDE変換履歴に関するデータベース操作を行うインターフェース
+type ConversionRepository interface {
+	Create(ctx context.Context, userID uint) error
+	CountSince(ctx context.Context, userID uint, since time.Time) (int64, error)
+	OldestSince(ctx context.Context, userID uint, since time.Time) (*time.Time, error)
+}
+
+// conversionRepository ConversionRepositoryの実装
+type conversionRepository struct {
+	db *gorm.DB
+}
+
+// NewConversionRepository ConversionRepositoryを作成
+func NewConversionRepository(db *gorm.DB) ConversionRepository {
+	return &conversionRepository{db: db}
+}
+
+// Create 変換履歴を記録
+func (r *conversionRepository) Create(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Create(&models.ConversionLog{UserID: userID}).Error
+}
+
+// CountSince 指定日時以降の変換回数を取得
+func (r *conversionRepository) CountSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.ConversionLog{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// OldestSince 指定日時以降で最も古い変換日時を取得
+func (r *conversionRepository) OldestSince(ctx context.Context, userID uint, since time.Time) (*time.Time, error) {
+	var log models.ConversionLog
+	err := r.db.WithContext(ctx).Where("user_id = ? AND created_at >= ?", userID, since).
+		Order("created_at ASC").
+		First(&log).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &log.CreatedAt, nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrCritiquesSubmitted 講評が提出済みのため評価基準を変更できない
+var ErrCritiquesSubmitted = errors.New("講評が提出されているため、評価基準を変更できません")
+
+// CritiqueRepository 講評に関するデータベース操作を行うインターフェース
+type CritiqueRepository interface {
+	ListCriteria(ctx context.Context, taskID uint) ([]models.CritiqueCriterion, error)
+	ReplaceCriteria(ctx context.Context, taskID uint, criteria []models.CritiqueCriterion) error
+	Save(ctx context.Context, critique *models.Critique) error
+	Find(ctx context.Context, taskID, workID, userID uint) (*models.Critique, error)
+	Delete(ctx context.Context, taskID, workID, userID uint) error
+	ListByWork(ctx context.Context, taskID, workID uint) ([]models.Critique, error)
+	ListByWorks(ctx context.Context, taskID uint, workIDs []uint) ([]models.Critique, error)
+	Summarize(ctx context.Context, taskID uint) ([]CritiqueScoreSummary, error)
+}
+
+// CritiqueScoreSummary 作品ごと・評価基準ごとの点数の集計
+type CritiqueScoreSummary struct {
+	WorkID      uint
+	CriterionID uint
+	Average     float64
+	Count       int64
+}
+
+// critiqueRepository CritiqueRepositoryの実装
+type critiqueRepository struct {
+	db *gorm.DB
+}
+
+// NewCritiqueRepository CritiqueRepositoryを作成
+func NewCritiqueRepository(db *gorm.DB) CritiqueRepository {
+	return &critiqueRepository{db: db}
+}
+
+// ListCriteria タスクの評価基準を表示順に取得
+func (r *critiqueRepository) ListCriteria(ctx context.Context, taskID uint) ([]models.CritiqueCriterion, error) {
+	var criteria []models.CritiqueCriterion
+	if err := r.db.WithContext(ctx).Where("task_id = ?", taskID).
+		Order("order_index ASC, id ASC").Find(&criteria).Error; err != nil {
+		return nil, err
+	}
+	return criteria, nil
+}
+
+// ReplaceCriteria タスクの評価基準を置き換え（講評が提出済みの場合は ErrCritiquesSubmitted）
+func (r *critiqueRepository) ReplaceCriteria(ctx context.Context, taskID uint, criteria []models.CritiqueCriterion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var submitted int64
+		if err := tx.Model(&models.Critique{}).Where("task_id = ?", taskID).Count(&submitted).Error; err != nil {
+			return err
+		}
+		if submitted > 0 {
+			return ErrCritiquesSubmitted
+		}
+
+		if err := tx.Where("task_id = ?", taskID).Delete(&models.CritiqueCriterion{}).Error; err != nil {
+			return err
+		}
+		if len(criteria) == 0 {
+			return nil
+		}
+		return tx.Create(&criteria).Error
+	})
+}
+
+// Save 講評を保存（同じユーザーの講評がある場合は点数とコメントを置き換える）
+func (r *critiqueRepository) Save(ctx context.Context, critique *models.Critique) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		scores := critique.Scores
+		critique.Scores = nil
+		defer func() { critique.Scores = scores }()
+
+		if err := tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"comment", "updated_at"}),
+		}).Omit("User").Create(critique).Error; err != nil {
+			return err
+		}
+
+		// 既存の講評を更新した場合は作成したIDが返らないため取得し直す
+		var saved models.Critique
+		if err := tx.Select("id", "created_at").
+			Where("task_id = ? AND work_id = ? AND user_id = ?", critique.TaskID, critique.WorkID, critique.UserID).
+			First(&saved).Error; err != nil {
+			return err
+		}
+		critique.ID = saved.ID
+		critique.CreatedAt = saved.CreatedAt
+
+		if err := tx.Where("critique_id = ?", critique.ID).Delete(&models.CritiqueScore{}).Error; err != nil {
+			return err
+		}
+		for i := range scores {
+			scores[i].CritiqueID = critique.ID
+		}
+		if len(scores) == 0 {
+			return nil
+		}
+		return tx.Create(&scores).Error
+	})
+}
+
+// Find ユーザーの講評を検索
+func (r *critiqueRepository) Find(ctx context.Context, taskID, workID, userID uint) (*models.Critique, error) {
+	var critique models.Critique
+	if err := r.db.WithContext(ctx).Preload("User").Preload("Scores").
+		Where("task_id = ? AND work_id = ? AND user_id = ?", taskID, workID, userID).
+		First(&critique).Error; err != nil {
+		return nil, err
+	}
+	return &critique, nil
+}
+
+// Delete ユーザーの講評を点数とともに削除
+func (r *critiqueRepository) Delete(ctx context.Context, taskID, workID, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var critique models.Critique
+		if err := tx.Select("id").Where("task_id = ? AND work_id = ? AND user_id = ?", taskID, workID, userID).
+			First(&critique).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("critique_id = ?", critique.ID).Delete(&models.CritiqueScore{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Critique{}, critique.ID).Error
+	})
+}
+
+// ListByWork タスクに提出された作品の講評を新しい順に取得
+func (r *critiqueRepository) ListByWork(ctx context.Context, taskID, workID uint) ([]models.Critique, error) {
+	var critiques []models.Critique
+	if err := r.db.WithContext(ctx).Preload("User").Preload("Scores").
+		Where("task_id = ? AND work_id = ?", taskID, workID).
+		Order("updated_at DESC, id DESC").Find(&critiques).Error; err != nil {
+		return nil, err
+	}
+	return critiques, nil
+}
+
+// ListByWorks タスクに提出された複数の作品の講評を古い順に取得
+func (r *critiqueRepository) ListByWorks(ctx context.Context, taskID uint, workIDs []uint) ([]models.Critique, error) {
+	var critiques []models.Critique
+	if len(workIDs) == 0 {
+		return critiques, nil
+	}
+	if err := r.db.WithContext(ctx).Preload("User").Preload("Scores").
+		Where("task_id = ? AND work_id IN ?", taskID, workIDs).
+		Order("id ASC").Find(&critiques).Error; err != nil {
+		return nil, err
+	}
+	return critiques, nil
+}
+
+// Summarize タスクの講評の点数を作品ごと・評価基準ごとに集計
+func (r *critiqueRepository) Summarize(ctx context.Context, taskID uint) ([]CritiqueScoreSummary, error) {
+	var summaries []CritiqueScoreSummary
+	if err := r.db.WithContext(ctx).Model(&models.CritiqueScore{}).
+		Select("critiques.work_id, critique_scores.criterion_id, AVG(critique_scores.score) AS average, COUNT(*) AS count").
+		Joins("JOIN critiques ON critiques.id = critique_scores.critique_id").
+		Where("critiques.task_id = ?", taskID).
+		Group("critiques.work_id, critique_scores.criterion_id").
+		Scan(&summaries).Error; err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
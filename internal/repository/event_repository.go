@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAlreadyRegistered 既にイベントに参加登録している（同時に登録された場合など）
+var ErrAlreadyRegistered = errors.New("既にこのイベントに参加登録しています")
+
+// ErrEventSlugTaken イベントの識別子が既に使われている
+var ErrEventSlugTaken = errors.New("このイベントの識別子は既に使われています")
+
+// ErrEventFull イベントの参加登録が定員に達している
+var ErrEventFull = errors.New("このイベントは定員に達しています")
+
+// イベント一覧の絞り込み
+const (
+	EventPeriodUpcoming = "upcoming" // 終了していないイベント（開始の早い順）
+	EventPeriodPast     = "past"     // 終了したイベント（開始の新しい順）
+)
+
+// EventRepository イベントに関するデータベース操作を行うインターフェース
+type EventRepository interface {
+	Create(ctx context.Context, event *models.Event) error
+	FindByID(ctx context.Context, id uint) (*models.Event, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Event, error)
+	Update(ctx context.Context, event *models.Event) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, now time.Time, period string, page, limit int) ([]models.Event, int64, error)
+	Register(ctx context.Context, eventID, userID uint) error
+	Unregister(ctx context.Context, eventID, userID uint) error
+	IsRegistered(ctx context.Context, eventID, userID uint) (bool, error)
+	ListAttendees(ctx context.Context, eventID uint) ([]models.EventAttendee, error)
+}
+
+// eventRepository EventRepositoryの実装
+type eventRepository struct {
+	db *gorm.DB
+}
+
+// NewEventRepository EventRepositoryを作成
+func NewEventRepository(db *gorm.DB) EventRepository {
+	return &eventRepository{db: db}
+}
+
+// Create 新しいイベントを作成（識別子が使われている場合は ErrEventSlugTaken）
+func (r *eventRepository) Create(ctx context.Context, event *models.Event) error {
+	err := r.db.WithContext(ctx).Omit("Organizer").Create(event).Error
+	if isDuplicateKey(err) {
+		return ErrEventSlugTaken
+	}
+	return err
+}
+
+// FindByID IDでイベントを検索
+func (r *eventRepository) FindByID(ctx context.Context, id uint) (*models.Event, error) {
+	var event models.Event
+	if err := r.db.WithContext(ctx).Preload("Organizer").First(&event, id).Error; err != nil {
+		return nil, err
+	}
+	if err := r.countAttendees(ctx, []*models.Event{&event}); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// FindBySlug 識別子でイベントを検索
+func (r *eventRepository) FindBySlug(ctx context.Context, slug string) (*models.Event, error) {
+	var event models.Event
+	if err := r.db.WithContext(ctx).Preload("Organizer").Where("slug = ?", slug).First(&event).Error; err != nil {
+		return nil, err
+	}
+	if err := r.countAttendees(ctx, []*models.Event{&event}); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// Update イベントを更新（識別子が使われている場合は ErrEventSlugTaken）
+func (r *eventRepository) Update(ctx context.Context, event *models.Event) error {
+	err := r.db.WithContext(ctx).Omit("Organizer").Save(event).Error
+	if isDuplicateKey(err) {
+		return ErrEventSlugTaken
+	}
+	return err
+}
+
+// Delete イベントを参加登録とともに削除
+func (r *eventRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ?", id).Delete(&models.EventAttendee{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Event{}, id).Error
+	})
+}
+
+// List イベント一覧を取得（periodで開催前・開催中か終了済みかに絞り込む）
+func (r *eventRepository) List(ctx context.Context, now time.Time, period string, page, limit int) ([]models.Event, int64, error) {
+	var events []models.Event
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Event{})
+	order := "starts_at DESC, id DESC"
+	switch period {
+	case EventPeriodUpcoming:
+		query = query.Where("ends_at >= ?", now)
+		order = "starts_at ASC, id ASC"
+	case EventPeriodPast:
+		query = query.Where("ends_at < ?", now)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("Organizer").
+		Order(order).
+		Offset(offset).Limit(limit).
+		Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]*models.Event, len(events))
+	for i := range events {
+		items[i] = &events[i]
+	}
+	if err := r.countAttendees(ctx, items); err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+// Register イベントに参加登録（定員に達している場合は ErrEventFull、登録済みの場合は ErrAlreadyRegistered）
+// 同時に登録されても定員を超えないよう、イベントの行をロックして数える
+func (r *eventRepository) Register(ctx context.Context, eventID, userID uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var event models.Event
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&event, eventID).Error; err != nil {
+			return err
+		}
+
+		if event.Capacity > 0 {
+			var count int64
+			if err := tx.Model(&models.EventAttendee{}).Where("event_id = ?", eventID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count >= int64(event.Capacity) {
+				return ErrEventFull
+			}
+		}
+
+		return tx.Create(&models.EventAttendee{EventID: eventID, UserID: userID, RegisteredAt: time.Now()}).Error
+	})
+	if isDuplicateKey(err) {
+		return ErrAlreadyRegistered
+	}
+	return err
+}
+
+// Unregister イベントの参加登録を取り消す
+func (r *eventRepository) Unregister(ctx context.Context, eventID, userID uint) error {
+	return r.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&models.EventAttendee{}).Error
+}
+
+// IsRegistered ユーザーがイベントに参加登録しているか確認
+func (r *eventRepository) IsRegistered(ctx context.Context, eventID, userID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.EventAttendee{}).
+		Where("event_id = ? AND user_id = ?", eventID, userID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListAttendees イベントの参加者一覧を登録の早い順に取得
+func (r *eventRepository) ListAttendees(ctx context.Context, eventID uint) ([]models.EventAttendee, error) {
+	var attendees []models.EventAttendee
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).
+		Preload("User").
+		Order("registered_at ASC, user_id ASC").
+		Find(&attendees).Error; err != nil {
+		return nil, err
+	}
+	return attendees, nil
+}
+
+// countAttendees イベントごとの参加者数をまとめて集計して設定
+func (r *eventRepository) countAttendees(ctx context.Context, events []*models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+
+	var rows []struct {
+		EventID uint
+		Count   int64
+	}
+	if err := r.db.WithContext(ctx).Model(&models.EventAttendee{}).
+		Select("event_id, COUNT(*) AS count").
+		Where("event_id IN ?", ids).
+		Group("event_id").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.EventID] = row.Count
+	}
+	for _, event := range events {
+		event.AttendeesCount = counts[event.ID]
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ImageRepository アップロードされた画像と派生画像に関するデータベース操作を行うインターフェース
+type ImageRepository interface {
+	Create(ctx context.Context, image *models.Image) error
+	FindByID(ctx context.Context, id uint) (*models.Image, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// imageRepository ImageRepositoryの実装
+type imageRepository struct {
+	db *gorm.DB
+}
+
+// NewImageRepository ImageRepositoryを作成
+func NewImageRepository(db *gorm.DB) ImageRepository {
+	return &imageRepository{db: db}
+}
+
+// Create 画像を派生画像とともに作成
+func (r *imageRepository) Create(ctx context.Context, image *models.Image) error {
+	return r.db.WithContext(ctx).Create(image).Error
+}
+
+// FindByID IDで画像を検索（派生画像を含む）
+func (r *imageRepository) FindByID(ctx context.Context, id uint) (*models.Image, error) {
+	var image models.Image
+	if err := r.db.WithContext(ctx).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&image, id).Error; err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// Delete 画像を派生画像とともに削除
+func (r *imageRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("image_id = ?", id).Delete(&models.ImageVariant{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Image{}, id).Error
+	})
+}
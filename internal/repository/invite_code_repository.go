@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrInviteCodeUnavailable 招待コードが使用回数の上限に達しているか期限切れ（同時に使用された場合など）
+var ErrInviteCodeUnavailable = errors.New("この招待コードは使用できません")
+
+// InviteCodeRepository 招待コードに関するデータベース操作を行うインターフェース
+type InviteCodeRepository interface {
+	Create(ctx context.Context, code *models.InviteCode) error
+	FindByID(ctx context.Context, id uint) (*models.InviteCode, error)
+	FindByCode(ctx context.Context, code string) (*models.InviteCode, error)
+	List(ctx context.Context, page, limit int) ([]models.InviteCode, int64, error)
+	Use(ctx context.Context, id uint, now time.Time) error
+	Release(ctx context.Context, id uint) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// inviteCodeRepository InviteCodeRepositoryの実装
+type inviteCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewInviteCodeRepository InviteCodeRepositoryを作成
+func NewInviteCodeRepository(db *gorm.DB) InviteCodeRepository {
+	return &inviteCodeRepository{db: db}
+}
+
+// Create 新しい招待コードを作成
+func (r *inviteCodeRepository) Create(ctx context.Context, code *models.InviteCode) error {
+	return r.db.WithContext(ctx).Create(code).Error
+}
+
+// FindByID IDで招待コードを検索
+func (r *inviteCodeRepository) FindByID(ctx context.Context, id uint) (*models.InviteCode, error) {
+	var code models.InviteCode
+	if err := r.db.WithContext(ctx).First(&code, id).Error; err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+// FindByCode コードで招待コードを検索
+func (r *inviteCodeRepository) FindByCode(ctx context.Context, code string) (*models.InviteCode, error) {
+	var inviteCode models.InviteCode
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&inviteCode).Error; err != nil {
+		return nil, err
+	}
+	return &inviteCode, nil
+}
+
+// List 招待コード一覧を取得（新しい順）
+func (r *inviteCodeRepository) List(ctx context.Context, page, limit int) ([]models.InviteCode, int64, error) {
+	var codes []models.InviteCode
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.InviteCode{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&codes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return codes, total, nil
+}
+
+// Use 招待コードの使用回数を1つ増やす（上限に達しているか期限切れの場合は ErrInviteCodeUnavailable）
+func (r *inviteCodeRepository) Use(ctx context.Context, id uint, now time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.InviteCode{}).
+		Where("id = ? AND used_count < max_uses AND (expires_at IS NULL OR expires_at > ?)", id, now).
+		UpdateColumn("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInviteCodeUnavailable
+	}
+	return nil
+}
+
+// Release 使用回数を1つ戻す（登録に失敗した場合）
+func (r *inviteCodeRepository) Release(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&models.InviteCode{}).
+		Where("id = ? AND used_count > 0", id).
+		UpdateColumn("used_count", gorm.Expr("used_count - 1")).Error
+}
+
+// Delete 招待コードを削除
+func (r *inviteCodeRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.InviteCode{}, id).Error
+}
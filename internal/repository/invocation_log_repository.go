@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// InvocationSummary 日付・バージョンごとのLambda呼び出しの集計
+type InvocationSummary struct {
+	Date              string // YYYY-MM-DD
+	Version           string
+	Invocations       int64
+	Failures          int64
+	Retries           int64 // 一時的なエラーによる再試行の回数
+	TotalDurationMs   int64
+	MaxDurationMs     int64
+	TotalPayloadBytes int64
+}
+
+// InvocationLogRepository Lambda関数の呼び出し履歴に関するデータベース操作を行うインターフェース
+type InvocationLogRepository interface {
+	Create(ctx context.Context, log *models.InvocationLog) error
+	SummarizeDaily(ctx context.Context, since time.Time) ([]InvocationSummary, error)
+	ListRecentFailures(ctx context.Context, since time.Time, limit int) ([]models.InvocationLog, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// invocationLogRepository InvocationLogRepositoryの実装
+type invocationLogRepository struct {
+	db *gorm.DB
+}
+
+// NewInvocationLogRepository InvocationLogRepositoryを作成
+func NewInvocationLogRepository(db *gorm.DB) InvocationLogRepository {
+	return &invocationLogRepository{db: db}
+}
+
+// Create 呼び出し履歴を記録
+func (r *invocationLogRepository) Create(ctx context.Context, log *models.InvocationLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+// SummarizeDaily 指定日時以降の呼び出しを日付・バージョンごとに集計（日付の古い順）
+func (r *invocationLogRepository) SummarizeDaily(ctx context.Context, since time.Time) ([]InvocationSummary, error) {
+	var summaries []InvocationSummary
+	if err := r.db.WithContext(ctx).Model(&models.InvocationLog{}).
+		Select("DATE_FORMAT(created_at, '%Y-%m-%d') AS date, version, "+
+			"COUNT(*) AS invocations, "+
+			"SUM(CASE WHEN success THEN 0 ELSE 1 END) AS failures, "+
+			"SUM(CASE WHEN attempt > 0 THEN 1 ELSE 0 END) AS retries, "+
+			"SUM(duration_ms) AS total_duration_ms, "+
+			"MAX(duration_ms) AS max_duration_ms, "+
+			"SUM(payload_bytes) AS total_payload_bytes").
+		Where("created_at >= ?", since).
+		Group("date, version").
+		Order("date ASC, version ASC").
+		Scan(&summaries).Error; err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// ListRecentFailures 指定日時以降に失敗した呼び出しを新しい順に取得
+func (r *invocationLogRepository) ListRecentFailures(ctx context.Context, since time.Time, limit int) ([]models.InvocationLog, error) {
+	var logs []models.InvocationLog
+	if err := r.db.WithContext(ctx).
+		Where("success = ? AND created_at >= ?", false, since).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// DeleteBefore 指定日時より前の呼び出し履歴を削除し、削除した件数を返す
+func (r *invocationLogRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&models.InvocationLog{})
+	return result.RowsAffected, result.Error
+}
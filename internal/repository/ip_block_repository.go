@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// IPBlockRepository IPアドレスのブロックリストに関するデータベース操作を行うインターフェース
+type IPBlockRepository interface {
+	Create(ctx context.Context, block *models.IPBlock) error
+	FindByID(ctx context.Context, id uint) (*models.IPBlock, error)
+	FindByCIDR(ctx context.Context, cidr string) (*models.IPBlock, error)
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context) ([]models.IPBlock, error)
+	ListActive(ctx context.Context, now time.Time) ([]models.IPBlock, error)
+}
+
+// ipBlockRepository IPBlockRepositoryの実装
+type ipBlockRepository struct {
+	db *gorm.DB
+}
+
+// NewIPBlockRepository IPBlockRepositoryを作成
+func NewIPBlockRepository(db *gorm.DB) IPBlockRepository {
+	return &ipBlockRepository{db: db}
+}
+
+// Create 新しいブロックを作成
+func (r *ipBlockRepository) Create(ctx context.Context, block *models.IPBlock) error {
+	return r.db.WithContext(ctx).Create(block).Error
+}
+
+// FindByID IDでブロックを検索
+func (r *ipBlockRepository) FindByID(ctx context.Context, id uint) (*models.IPBlock, error) {
+	var block models.IPBlock
+	if err := r.db.WithContext(ctx).First(&block, id).Error; err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// FindByCIDR CIDRでブロックを検索
+func (r *ipBlockRepository) FindByCIDR(ctx context.Context, cidr string) (*models.IPBlock, error) {
+	var block models.IPBlock
+	if err := r.db.WithContext(ctx).Where("cidr = ?", cidr).First(&block).Error; err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// Delete ブロックを削除
+func (r *ipBlockRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.IPBlock{}, id).Error
+}
+
+// List 全てのブロックを取得（新しい順）
+func (r *ipBlockRepository) List(ctx context.Context) ([]models.IPBlock, error) {
+	var blocks []models.IPBlock
+	if err := r.db.WithContext(ctx).Order("created_at DESC, id DESC").Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// ListActive 期限切れでないブロックを取得
+func (r *ipBlockRepository) ListActive(ctx context.Context, now time.Time) ([]models.IPBlock, error) {
+	var blocks []models.IPBlock
+	if err := r.db.WithContext(ctx).
+		Where("expires_at IS NULL OR expires_at > ?", now).
+		Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LinkPreviewRepository リンクプレビューのキャッシュに関するデータベース操作を行うインターフェース
+type LinkPreviewRepository interface {
+	FindByHashes(ctx context.Context, hashes []string) ([]models.LinkPreview, error)
+	Save(ctx context.Context, preview *models.LinkPreview) error
+}
+
+// linkPreviewRepository LinkPreviewRepositoryの実装
+type linkPreviewRepository struct {
+	db *gorm.DB
+}
+
+// NewLinkPreviewRepository LinkPreviewRepositoryを作成
+func NewLinkPreviewRepository(db *gorm.DB) LinkPreviewRepository {
+	return &linkPreviewRepository{db: db}
+}
+
+// FindByHashes URLのハッシュでリンクプレビューを取得
+func (r *linkPreviewRepository) FindByHashes(ctx context.Context, hashes []string) ([]models.LinkPreview, error) {
+	var previews []models.LinkPreview
+	if len(hashes) == 0 {
+		return previews, nil
+	}
+	if err := r.db.WithContext(ctx).Where("url_hash IN ?", hashes).Find(&previews).Error; err != nil {
+		return nil, err
+	}
+	return previews, nil
+}
+
+// Save リンクプレビューを保存（同じURLのものは置き換える）
+func (r *linkPreviewRepository) Save(ctx context.Context, preview *models.LinkPreview) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "url_hash"}},
+		DoUpdates: clause.AssignmentColumns([]string{"title", "description", "image_url", "site_name", "failed", "fetched_at"}),
+	}).Create(preview).Error
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// activityRepository ActivityRepositoryのインメモリ実装
+type activityRepository struct {
+	s *Store
+}
+
+// NewActivityRepository ActivityRepositoryを作成
+func NewActivityRepository(s *Store) repository.ActivityRepository {
+	return &activityRepository{s: s}
+}
+
+// Create 新しいアクティビティを作成
+func (r *activityRepository) Create(ctx context.Context, activity *models.Activity) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("activities", &activity.ID)
+	stamp(&activity.CreatedAt, nil)
+	stored := *activity
+	stored.User = nil
+	r.s.activities[activity.ID] = stored
+	return nil
+}
+
+// ListByProject プロジェクトのアクティビティ一覧を取得
+func (r *activityRepository) ListByProject(ctx context.Context, projectID uint, limit int) ([]models.Activity, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	activities := []models.Activity{}
+	for _, activity := range r.s.activities {
+		if activity.ProjectID != nil && *activity.ProjectID == projectID {
+			activities = append(activities, activity)
+		}
+	}
+	sort.Slice(activities, func(i, j int) bool {
+		return newerFirst(activities[i].CreatedAt, activities[i].ID, activities[j].CreatedAt, activities[j].ID)
+	})
+
+	items := paginate(activities, 1, limit)
+	for i := range items {
+		if items[i].UserID != nil {
+			if user := r.s.loadUser(*items[i].UserID); user.ID != 0 {
+				items[i].User = &user
+			}
+		}
+	}
+	return items, nil
+}
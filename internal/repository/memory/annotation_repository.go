@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// annotationRepository AnnotationRepositoryのインメモリ実装
+type annotationRepository struct {
+	s *Store
+}
+
+// NewAnnotationRepository AnnotationRepositoryを作成
+func NewAnnotationRepository(s *Store) repository.AnnotationRepository {
+	return &annotationRepository{s: s}
+}
+
+// Create 新しい注釈を作成
+func (r *annotationRepository) Create(ctx context.Context, annotation *models.WorkAnnotation) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("work_annotations", &annotation.ID)
+	stamp(&annotation.CreatedAt, &annotation.UpdatedAt)
+	r.s.annotations[annotation.ID] = *annotation
+	return nil
+}
+
+// FindByID IDで注釈を検索
+func (r *annotationRepository) FindByID(ctx context.Context, id uint) (*models.WorkAnnotation, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	annotation, ok := r.s.annotations[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &annotation, nil
+}
+
+// Update 注釈を更新
+func (r *annotationRepository) Update(ctx context.Context, annotation *models.WorkAnnotation) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	annotation.UpdatedAt = time.Now()
+	r.s.annotations[annotation.ID] = *annotation
+	return nil
+}
+
+// Delete 注釈を削除
+func (r *annotationRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.annotations, id)
+	return nil
+}
+
+// ListByWork 作品の注釈一覧を行番号順に取得
+func (r *annotationRepository) ListByWork(ctx context.Context, workID uint) ([]models.WorkAnnotation, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	annotations := []models.WorkAnnotation{}
+	for _, annotation := range r.s.annotations {
+		if annotation.WorkID == workID {
+			annotations = append(annotations, annotation)
+		}
+	}
+	sort.Slice(annotations, func(i, j int) bool {
+		if annotations[i].Line != annotations[j].Line {
+			return annotations[i].Line < annotations[j].Line
+		}
+		return annotations[i].ID < annotations[j].ID
+	})
+	return annotations, nil
+}
+
+// CountByWork 作品の注釈数を取得
+func (r *annotationRepository) CountByWork(ctx context.Context, workID uint) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for _, annotation := range r.s.annotations {
+		if annotation.WorkID == workID {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// assetRepository AssetRepositoryのインメモリ実装
+type assetRepository struct {
+	s *Store
+}
+
+// NewAssetRepository AssetRepositoryを作成
+func NewAssetRepository(s *Store) repository.AssetRepository {
+	return &assetRepository{s: s}
+}
+
+// CreateUpload 新しいアップロードを作成
+func (r *assetRepository) CreateUpload(ctx context.Context, upload *models.AssetUpload) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.uploads[upload.ID]; ok {
+		return errDuplicate
+	}
+	stamp(&upload.CreatedAt, &upload.UpdatedAt)
+	r.s.uploads[upload.ID] = *upload
+	return nil
+}
+
+// FindUpload IDでアップロードを検索
+func (r *assetRepository) FindUpload(ctx context.Context, id string) (*models.AssetUpload, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	upload, ok := r.s.uploads[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &upload, nil
+}
+
+// UpdateUploadOffset 受け取り済みのバイト数を更新
+func (r *assetRepository) UpdateUploadOffset(ctx context.Context, id string, offset int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if upload, ok := r.s.uploads[id]; ok {
+		upload.Offset = offset
+		upload.UpdatedAt = time.Now()
+		r.s.uploads[id] = upload
+	}
+	return nil
+}
+
+// CompleteUpload アセットを作成し、アップロードを完了状態にする
+func (r *assetRepository) CompleteUpload(ctx context.Context, upload *models.AssetUpload, asset *models.WorkAsset) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.createAsset(asset)
+	upload.AssetID = &asset.ID
+	if stored, ok := r.s.uploads[upload.ID]; ok {
+		stored.Offset = upload.Offset
+		stored.AssetID = upload.AssetID
+		stored.UpdatedAt = time.Now()
+		r.s.uploads[upload.ID] = stored
+	}
+	return nil
+}
+
+// DeleteUpload アップロードを削除
+func (r *assetRepository) DeleteUpload(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.uploads, id)
+	return nil
+}
+
+// Create 新しいアセットを作成
+func (r *assetRepository) Create(ctx context.Context, asset *models.WorkAsset) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.createAsset(asset)
+	return nil
+}
+
+// FindByID IDでアセットを検索
+func (r *assetRepository) FindByID(ctx context.Context, id uint) (*models.WorkAsset, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	asset, ok := r.s.assets[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &asset, nil
+}
+
+// FindByWorkAndFilename 作品のアセットをファイル名で検索（同名の場合は最新のもの）
+func (r *assetRepository) FindByWorkAndFilename(ctx context.Context, workID uint, filename string) (*models.WorkAsset, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var found *models.WorkAsset
+	for _, asset := range r.s.assets {
+		if asset.WorkID != workID || asset.Filename != filename {
+			continue
+		}
+		if found == nil || asset.ID > found.ID {
+			a := asset
+			found = &a
+		}
+	}
+	if found == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return found, nil
+}
+
+// ListByWork 作品のアセット一覧を取得
+func (r *assetRepository) ListByWork(ctx context.Context, workID uint) ([]models.WorkAsset, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	assets := []models.WorkAsset{}
+	for _, asset := range r.s.assets {
+		if asset.WorkID == workID {
+			assets = append(assets, asset)
+		}
+	}
+	sort.Slice(assets, func(i, j int) bool { return assets[i].ID < assets[j].ID })
+	return assets, nil
+}
+
+// SetImage 画像のアセットに変換で作成した派生画像を関連付ける
+func (r *assetRepository) SetImage(ctx context.Context, id, imageID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if asset, ok := r.s.assets[id]; ok {
+		asset.ImageID = &imageID
+		r.s.assets[id] = asset
+	}
+	return nil
+}
+
+// createAsset アセットを保存（ロックを取得した状態で呼び出す）
+func (s *Store) createAsset(asset *models.WorkAsset) {
+	s.assignID("work_assets", &asset.ID)
+	stamp(&asset.CreatedAt, nil)
+	s.assets[asset.ID] = *asset
+}
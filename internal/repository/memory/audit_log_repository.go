@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// auditLogRepository AuditLogRepositoryのインメモリ実装
+type auditLogRepository struct {
+	s *Store
+}
+
+// NewAuditLogRepository AuditLogRepositoryを作成
+func NewAuditLogRepository(s *Store) repository.AuditLogRepository {
+	return &auditLogRepository{s: s}
+}
+
+// Create 監査ログを記録
+func (r *auditLogRepository) Create(ctx context.Context, log *models.AuditLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.createAuditLog(log)
+	return nil
+}
+
+// createAuditLog 監査ログを記録（ロックを保持して呼び出す）
+func (s *Store) createAuditLog(log *models.AuditLog) {
+	s.assignID("audit_logs", &log.ID)
+	stamp(&log.CreatedAt, nil)
+	s.auditLogs[log.ID] = *log
+}
+
+// List 監査ログを新しい順に取得（actionが空の場合は全ての操作）
+func (r *auditLogRepository) List(ctx context.Context, action string, page, limit int) ([]models.AuditLog, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	logs := []models.AuditLog{}
+	for _, log := range r.s.auditLogs {
+		if action != "" && log.Action != action {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		a, b := logs[i], logs[j]
+		return newerFirst(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+
+	return paginate(logs, page, limit), int64(len(logs)), nil
+}
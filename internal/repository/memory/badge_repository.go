@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// badgeRepository BadgeRepositoryのインメモリ実装
+type badgeRepository struct {
+	s *Store
+}
+
+// NewBadgeRepository BadgeRepositoryを作成
+func NewBadgeRepository(s *Store) repository.BadgeRepository {
+	return &badgeRepository{s: s}
+}
+
+// Create 新しいバッジを作成（同じ作品・種類・投票のバッジがある場合は ErrBadgeAlreadyAwarded）
+func (r *badgeRepository) Create(ctx context.Context, badge *models.WorkBadge) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if badge.VoteID != nil {
+		for _, existing := range r.s.badges {
+			if existing.WorkID == badge.WorkID && existing.Type == badge.Type &&
+				existing.VoteID != nil && *existing.VoteID == *badge.VoteID {
+				return repository.ErrBadgeAlreadyAwarded
+			}
+		}
+	}
+
+	r.s.assignID("work_badges", &badge.ID)
+	stamp(&badge.CreatedAt, nil)
+	r.s.badges[badge.ID] = *badge
+	return nil
+}
+
+// ListByWork 作品のバッジ一覧を取得
+func (r *badgeRepository) ListByWork(ctx context.Context, workID uint) ([]models.WorkBadge, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	badges := r.s.workBadgeList(workID)
+	sort.Slice(badges, func(i, j int) bool {
+		return newerFirst(badges[i].CreatedAt, badges[i].ID, badges[j].CreatedAt, badges[j].ID)
+	})
+	return badges, nil
+}
+
+// Exists 同じバッジが既に付与されているか確認
+func (r *badgeRepository) Exists(ctx context.Context, workID uint, badgeType string, voteID *uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, badge := range r.s.badges {
+		if badge.WorkID != workID || badge.Type != badgeType {
+			continue
+		}
+		if voteID != nil && (badge.VoteID == nil || *badge.VoteID != *voteID) {
+			continue
+		}
+		return true, nil
+	}
+	return false, nil
+}
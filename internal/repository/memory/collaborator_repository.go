@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// collaboratorRepository CollaboratorRepositoryのインメモリ実装
+type collaboratorRepository struct {
+	s *Store
+}
+
+// NewCollaboratorRepository CollaboratorRepositoryを作成
+func NewCollaboratorRepository(s *Store) repository.CollaboratorRepository {
+	return &collaboratorRepository{s: s}
+}
+
+// Create 共同編集者を招待（既に登録されている場合は ErrAlreadyCollaborator）
+func (r *collaboratorRepository) Create(ctx context.Context, collaborator *models.WorkCollaborator) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := pairKey{collaborator.WorkID, collaborator.UserID}
+	if _, ok := r.s.collaborators[key]; ok {
+		return repository.ErrAlreadyCollaborator
+	}
+	stamp(&collaborator.CreatedAt, nil)
+	stored := *collaborator
+	stored.User = models.User{}
+	stored.Work = nil
+	r.s.collaborators[key] = stored
+	return nil
+}
+
+// Find 作品とユーザーで共同編集者を検索（招待中を含む）
+func (r *collaboratorRepository) Find(ctx context.Context, workID, userID uint) (*models.WorkCollaborator, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	collaborator, ok := r.s.collaborators[pairKey{workID, userID}]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	collaborator.User = r.s.loadUser(userID)
+	return &collaborator, nil
+}
+
+// ListByWork 作品の共同編集者を招待の早い順に取得（includePendingがfalseの場合は承認済みのみ）
+func (r *collaboratorRepository) ListByWork(ctx context.Context, workID uint, includePending bool) ([]models.WorkCollaborator, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	collaborators := []models.WorkCollaborator{}
+	for key, collaborator := range r.s.collaborators {
+		if key.a != workID || (!includePending && collaborator.AcceptedAt == nil) {
+			continue
+		}
+		collaborator.User = r.s.loadUser(collaborator.UserID)
+		collaborators = append(collaborators, collaborator)
+	}
+	sort.Slice(collaborators, func(i, j int) bool {
+		a, b := collaborators[i], collaborators[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.UserID < b.UserID
+	})
+	return collaborators, nil
+}
+
+// ListAcceptedByWorks 複数の作品の承認済みの共同編集者を招待の早い順に取得
+func (r *collaboratorRepository) ListAcceptedByWorks(ctx context.Context, workIDs []uint) ([]models.WorkCollaborator, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	ids := make(map[uint]bool, len(workIDs))
+	for _, id := range workIDs {
+		ids[id] = true
+	}
+
+	collaborators := []models.WorkCollaborator{}
+	for key, collaborator := range r.s.collaborators {
+		if !ids[key.a] || collaborator.AcceptedAt == nil {
+			continue
+		}
+		collaborator.User = r.s.loadUser(collaborator.UserID)
+		collaborators = append(collaborators, collaborator)
+	}
+	sort.Slice(collaborators, func(i, j int) bool {
+		a, b := collaborators[i], collaborators[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.UserID < b.UserID
+	})
+	return collaborators, nil
+}
+
+// ListInvitations ユーザーが承認していない招待を新しい順に取得（削除された作品を除く）
+func (r *collaboratorRepository) ListInvitations(ctx context.Context, userID uint) ([]models.WorkCollaborator, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	collaborators := []models.WorkCollaborator{}
+	for key, collaborator := range r.s.collaborators {
+		if key.b != userID || collaborator.AcceptedAt != nil {
+			continue
+		}
+		work, ok := r.s.liveWork(key.a)
+		if !ok {
+			continue
+		}
+		work = r.s.loadWork(work)
+		collaborator.Work = &work
+		collaborator.User = r.s.loadUser(userID)
+		collaborators = append(collaborators, collaborator)
+	}
+	sort.Slice(collaborators, func(i, j int) bool {
+		a, b := collaborators[i], collaborators[j]
+		return newerFirst(a.CreatedAt, a.WorkID, b.CreatedAt, b.WorkID)
+	})
+	return collaborators, nil
+}
+
+// Accept 招待を承認
+func (r *collaboratorRepository) Accept(ctx context.Context, workID, userID uint, acceptedAt time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := pairKey{workID, userID}
+	if collaborator, ok := r.s.collaborators[key]; ok {
+		collaborator.AcceptedAt = &acceptedAt
+		r.s.collaborators[key] = collaborator
+	}
+	return nil
+}
+
+// UpdatePermission 共同編集者の権限を変更
+func (r *collaboratorRepository) UpdatePermission(ctx context.Context, workID, userID uint, permission string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := pairKey{workID, userID}
+	if collaborator, ok := r.s.collaborators[key]; ok {
+		collaborator.Permission = permission
+		r.s.collaborators[key] = collaborator
+	}
+	return nil
+}
+
+// Delete 共同編集者を外す（招待の取り消し・辞退を含む）
+func (r *collaboratorRepository) Delete(ctx context.Context, workID, userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.collaborators, pairKey{workID, userID})
+	return nil
+}
@@ -0,0 +1,331 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// commentRepository CommentRepositoryのインメモリ実装
+type commentRepository struct {
+	s *Store
+}
+
+// NewCommentRepository CommentRepositoryを作成
+func NewCommentRepository(s *Store) repository.CommentRepository {
+	return &commentRepository{s: s}
+}
+
+// Create 新しいコメントを作成し、作品のコメント数（返信の場合は返信先の返信数も）を加算
+func (r *commentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("comments", &comment.ID)
+	stamp(&comment.CreatedAt, &comment.UpdatedAt)
+	r.s.comments[comment.ID] = stripComment(*comment)
+
+	if comment.ParentID != nil {
+		r.s.addReplies(*comment.ParentID, 1)
+	}
+	r.s.addComments(comment.WorkID, 1)
+	return nil
+}
+
+// FindByID IDでコメントを検索
+func (r *commentRepository) FindByID(ctx context.Context, id uint) (*models.Comment, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	comment, ok := r.s.liveComment(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	comment.User = r.s.loadUser(comment.UserID)
+	return &comment, nil
+}
+
+// Update コメントを更新
+func (r *commentRepository) Update(ctx context.Context, comment *models.Comment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("comments", &comment.ID)
+	comment.UpdatedAt = time.Now()
+	r.s.comments[comment.ID] = stripComment(*comment)
+	return nil
+}
+
+// Delete コメントを削除し、作品のコメント数（返信の場合は返信先の返信数も）を減算
+func (r *commentRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	comment, ok := r.s.liveComment(id)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	comment.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.s.comments[id] = comment
+
+	if comment.ParentID != nil {
+		r.s.addReplies(*comment.ParentID, -1)
+	}
+	r.s.addComments(comment.WorkID, -1)
+	return nil
+}
+
+// ListByWork 作品のコメント一覧を取得
+// afterIDを指定した場合は、ソート順でそのコメントより後ろのコメントを返す（pageは無視される）
+func (r *commentRepository) ListByWork(ctx context.Context, workID uint, page, limit int, sort string, afterID uint) ([]models.Comment, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var total int64
+	for _, comment := range r.s.comments {
+		if comment.WorkID == workID && !comment.DeletedAt.Valid && comment.HiddenAt == nil {
+			total++
+		}
+	}
+
+	// 基準となるコメントを取得
+	var after *models.Comment
+	if afterID > 0 {
+		comment, ok := r.s.liveComment(afterID)
+		if !ok || comment.WorkID != workID {
+			return nil, 0, gorm.ErrRecordNotFound
+		}
+		after = &comment
+		page = 1
+	}
+
+	// ピン留めされたコメントは別途先頭に表示するため除外
+	comments := []models.Comment{}
+	for _, comment := range r.s.comments {
+		if comment.WorkID != workID || comment.DeletedAt.Valid || comment.HiddenAt != nil || comment.Pinned {
+			continue
+		}
+		if after != nil && !commentAfter(comment, *after, sort) {
+			continue
+		}
+		comments = append(comments, comment)
+	}
+	sortComments(comments, sort)
+
+	items := paginate(comments, page, limit)
+	for i := range items {
+		items[i].User = r.s.loadUser(items[i].UserID)
+	}
+	return items, total, nil
+}
+
+// FindPinnedByWork 作品のピン留めされたコメントを取得（ない場合はnil）
+func (r *commentRepository) FindPinnedByWork(ctx context.Context, workID uint) (*models.Comment, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var pinned *models.Comment
+	for _, comment := range r.s.comments {
+		if comment.WorkID != workID || comment.DeletedAt.Valid || comment.HiddenAt != nil || !comment.Pinned {
+			continue
+		}
+		if pinned == nil || comment.ID < pinned.ID {
+			c := comment
+			pinned = &c
+		}
+	}
+	if pinned != nil {
+		pinned.User = r.s.loadUser(pinned.UserID)
+	}
+	return pinned, nil
+}
+
+// SetPinned コメントのピン留めを設定（ピン留めする場合は同じ作品の他のコメントのピン留めを解除）
+func (r *commentRepository) SetPinned(ctx context.Context, comment *models.Comment, pinned bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, c := range r.s.comments {
+		switch {
+		case id == comment.ID:
+			c.Pinned = pinned
+		case pinned && c.WorkID == comment.WorkID && c.Pinned:
+			c.Pinned = false
+		default:
+			continue
+		}
+		r.s.comments[id] = c
+	}
+	comment.Pinned = pinned
+	return nil
+}
+
+// SetResolved コメントの対応済み日時を設定（nilで未対応に戻す）
+func (r *commentRepository) SetResolved(ctx context.Context, comment *models.Comment, resolvedAt *time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if c, ok := r.s.liveComment(comment.ID); ok {
+		c.ResolvedAt = resolvedAt
+		r.s.comments[comment.ID] = c
+	}
+	comment.ResolvedAt = resolvedAt
+	return nil
+}
+
+// EachByWork 作品のコメントを古い順にbatchSize件ずつ読み込んでfnに渡す
+func (r *commentRepository) EachByWork(ctx context.Context, workID uint, batchSize int, fn func(comments []models.Comment) error) error {
+	r.s.mu.RLock()
+	comments := []models.Comment{}
+	for _, comment := range r.s.comments {
+		if comment.WorkID == workID && !comment.DeletedAt.Valid {
+			comment.User = r.s.loadUser(comment.UserID)
+			comments = append(comments, comment)
+		}
+	}
+	r.s.mu.RUnlock()
+
+	sort.Slice(comments, func(i, j int) bool { return comments[i].ID < comments[j].ID })
+	if batchSize <= 0 {
+		batchSize = len(comments)
+	}
+
+	// fnの中で他のリポジトリを呼び出せるよう、ロックを解放してから渡す
+	for start := 0; start < len(comments); start += batchSize {
+		end := start + batchSize
+		if end > len(comments) {
+			end = len(comments)
+		}
+		if err := fn(comments[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindSubscription ユーザーが設定した作品のコメントの通知の設定を検索
+func (r *commentRepository) FindSubscription(ctx context.Context, workID, userID uint) (*models.CommentSubscription, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	subscription, ok := r.s.subscriptions[pairKey{workID, userID}]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &subscription, nil
+}
+
+// SetSubscription 作品のコメントの通知の設定を保存（設定済みの場合は上書き）
+func (r *commentRepository) SetSubscription(ctx context.Context, subscription *models.CommentSubscription) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := pairKey{subscription.WorkID, subscription.UserID}
+	if existing, ok := r.s.subscriptions[key]; ok {
+		subscription.CreatedAt = existing.CreatedAt
+	}
+	subscription.UpdatedAt = time.Now()
+	stamp(&subscription.CreatedAt, nil)
+	r.s.subscriptions[key] = *subscription
+	return nil
+}
+
+// CreateSubscription 作品のコメントの通知の設定を保存（設定済みの場合は変更しない）
+func (r *commentRepository) CreateSubscription(ctx context.Context, subscription *models.CommentSubscription) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := pairKey{subscription.WorkID, subscription.UserID}
+	if _, ok := r.s.subscriptions[key]; ok {
+		return nil
+	}
+	stamp(&subscription.CreatedAt, &subscription.UpdatedAt)
+	r.s.subscriptions[key] = *subscription
+	return nil
+}
+
+// ListSubscriptions 作品のコメントの通知の設定を全て取得
+func (r *commentRepository) ListSubscriptions(ctx context.Context, workID uint) ([]models.CommentSubscription, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	subscriptions := []models.CommentSubscription{}
+	for key, subscription := range r.s.subscriptions {
+		if key.a == workID {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+// liveComment 削除されていないコメントを取得（ロックを取得した状態で呼び出す）
+func (s *Store) liveComment(id uint) (models.Comment, bool) {
+	comment, ok := s.comments[id]
+	if !ok || comment.DeletedAt.Valid {
+		return models.Comment{}, false
+	}
+	return comment, true
+}
+
+// addReplies 返信数を加算（0未満にはしない、ロックを取得した状態で呼び出す）
+func (s *Store) addReplies(commentID uint, delta int) {
+	if comment, ok := s.liveComment(commentID); ok {
+		comment.RepliesCount += delta
+		if comment.RepliesCount < 0 {
+			comment.RepliesCount = 0
+		}
+		s.comments[commentID] = comment
+	}
+}
+
+// addComments 作品のコメント数を加算（0未満にはしない、ロックを取得した状態で呼び出す）
+func (s *Store) addComments(workID uint, delta int64) {
+	if work, ok := s.liveWork(workID); ok {
+		work.CommentsCount += delta
+		if work.CommentsCount < 0 {
+			work.CommentsCount = 0
+		}
+		s.works[workID] = work
+	}
+}
+
+// commentAfter ソート順でcommentがafterより後ろにあるか
+func commentAfter(comment, after models.Comment, order string) bool {
+	switch order {
+	case "oldest":
+		return comment.ID > after.ID
+	case "top":
+		return comment.RepliesCount < after.RepliesCount ||
+			(comment.RepliesCount == after.RepliesCount && comment.ID < after.ID)
+	default: // "newest"
+		return comment.ID < after.ID
+	}
+}
+
+// sortComments コメント一覧のソート順を適用（同順位はIDで並べて順序を安定させる）
+func sortComments(comments []models.Comment, order string) {
+	sort.Slice(comments, func(i, j int) bool {
+		a, b := comments[i], comments[j]
+		switch order {
+		case "oldest":
+			return a.ID < b.ID
+		case "top":
+			if a.RepliesCount != b.RepliesCount {
+				return a.RepliesCount > b.RepliesCount
+			}
+		}
+		return a.ID > b.ID
+	})
+}
+
+// stripComment 保存用にリレーションを取り除く
+func stripComment(comment models.Comment) models.Comment {
+	comment.User = models.User{}
+	comment.Work = models.Work{}
+	return comment
+}
@@ -0,0 +1,115 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// conversionJobRepository ConversionJobRepositoryのインメモリ実装
+type conversionJobRepository struct {
+	s *Store
+}
+
+// NewConversionJobRepository ConversionJobRepositoryを作成
+func NewConversionJobRepository(s *Store) repository.ConversionJobRepository {
+	return &conversionJobRepository{s: s}
+}
+
+// Enqueue 作品の変換ジョブを登録（既にある場合は試行回数を戻して待機中にする）
+func (r *conversionJobRepository) Enqueue(ctx context.Context, workID uint, maxAttempts int, runAt time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	job := models.ConversionJob{WorkID: workID}
+	for _, existing := range r.s.conversionJobs {
+		if existing.WorkID == workID {
+			job = existing
+			break
+		}
+	}
+
+	job.Status = models.ConversionJobStatusPending
+	job.Attempts = 0
+	job.MaxAttempts = maxAttempts
+	job.NextRunAt = runAt
+	job.LastError = ""
+	r.s.assignID("conversion_jobs", &job.ID)
+	stamp(&job.CreatedAt, nil)
+	job.UpdatedAt = time.Now()
+	r.s.conversionJobs[job.ID] = job
+	return nil
+}
+
+// FindByWorkID 作品の変換ジョブを取得
+func (r *conversionJobRepository) FindByWorkID(ctx context.Context, workID uint) (*models.ConversionJob, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, job := range r.s.conversionJobs {
+		if job.WorkID == workID {
+			return &job, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ListDue 実行時刻を過ぎた待機中のジョブを実行時刻の古い順に取得
+func (r *conversionJobRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.ConversionJob, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	jobs := []models.ConversionJob{}
+	for _, job := range r.s.conversionJobs {
+		if job.Status == models.ConversionJobStatusPending && !job.NextRunAt.After(now) {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].NextRunAt.Equal(jobs[j].NextRunAt) {
+			return jobs[i].NextRunAt.Before(jobs[j].NextRunAt)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+	return paginate(jobs, 1, limit), nil
+}
+
+// Claim ジョブの実行時刻を leaseUntil まで延ばして実行権を得る
+func (r *conversionJobRepository) Claim(ctx context.Context, job *models.ConversionJob, leaseUntil time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.conversionJobs[job.ID]
+	if !ok || stored.Status != models.ConversionJobStatusPending || !stored.NextRunAt.Equal(job.NextRunAt) {
+		return false, nil
+	}
+	stored.NextRunAt = leaseUntil
+	r.s.conversionJobs[job.ID] = stored
+	job.NextRunAt = leaseUntil
+	return true, nil
+}
+
+// Update ジョブを更新
+func (r *conversionJobRepository) Update(ctx context.Context, job *models.ConversionJob) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("conversion_jobs", &job.ID)
+	job.UpdatedAt = time.Now()
+	r.s.conversionJobs[job.ID] = *job
+	return nil
+}
+
+// Delete ジョブを削除
+func (r *conversionJobRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.conversionJobs, id)
+	return nil
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// conversionRepository ConversionRepositoryのインメモリ実装
+type conversionRepository struct {
+	s *Store
+}
+
+// NewConversionRepository ConversionRepositoryを作成
+func NewConversionRepository(s *Store) repository.ConversionRepository {
+	return &conversionRepository{s: s}
+}
+
+// Create 変換履歴を記録
+func (r *conversionRepository) Create(ctx context.Context, userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	log := models.ConversionLog{UserID: userID, CreatedAt: time.Now()}
+	r.s.assignID("conversion_logs", &log.ID)
+	r.s.conversionLogs[log.ID] = log
+	return nil
+}
+
+// CountSince 指定日時以降の変換回数を取得
+func (r *conversionRepository) CountSince(ctx context.Context, userID uint, since time.Time) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for _, log := range r.s.conversionLogs {
+		if log.UserID == userID && !log.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// OldestSince 指定日時以降で最も古い変換日時を取得
+func (r *conversionRepository) OldestSince(ctx context.Context, userID uint, since time.Time) (*time.Time, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var oldest *time.Time
+	for _, log := range r.s.conversionLogs {
+		if log.UserID != userID || log.CreatedAt.Before(since) {
+			continue
+		}
+		if oldest == nil || log.CreatedAt.Before(*oldest) {
+			createdAt := log.CreatedAt
+			oldest = &createdAt
+		}
+	}
+	return oldest, nil
+}
@@ -0,0 +1,201 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// critiqueRepository CritiqueRepositoryのインメモリ実装
+type critiqueRepository struct {
+	s *Store
+}
+
+// NewCritiqueRepository CritiqueRepositoryを作成
+func NewCritiqueRepository(s *Store) repository.CritiqueRepository {
+	return &critiqueRepository{s: s}
+}
+
+// ListCriteria タスクの評価基準を表示順に取得
+func (r *critiqueRepository) ListCriteria(ctx context.Context, taskID uint) ([]models.CritiqueCriterion, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	criteria := []models.CritiqueCriterion{}
+	for _, criterion := range r.s.criteria {
+		if criterion.TaskID == taskID {
+			criteria = append(criteria, criterion)
+		}
+	}
+	sort.Slice(criteria, func(i, j int) bool {
+		if criteria[i].OrderIndex != criteria[j].OrderIndex {
+			return criteria[i].OrderIndex < criteria[j].OrderIndex
+		}
+		return criteria[i].ID < criteria[j].ID
+	})
+	return criteria, nil
+}
+
+// ReplaceCriteria タスクの評価基準を置き換え（講評が提出済みの場合は ErrCritiquesSubmitted）
+func (r *critiqueRepository) ReplaceCriteria(ctx context.Context, taskID uint, criteria []models.CritiqueCriterion) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, critique := range r.s.critiques {
+		if critique.TaskID == taskID {
+			return repository.ErrCritiquesSubmitted
+		}
+	}
+
+	for id, criterion := range r.s.criteria {
+		if criterion.TaskID == taskID {
+			delete(r.s.criteria, id)
+		}
+	}
+	for i := range criteria {
+		r.s.assignID("critique_criteria", &criteria[i].ID)
+		stamp(&criteria[i].CreatedAt, nil)
+		r.s.criteria[criteria[i].ID] = criteria[i]
+	}
+	return nil
+}
+
+// Save 講評を保存（同じユーザーの講評がある場合は点数とコメントを置き換える）
+func (r *critiqueRepository) Save(ctx context.Context, critique *models.Critique) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if existing, ok := r.s.findCritique(critique.TaskID, critique.WorkID, critique.UserID); ok {
+		critique.ID = existing.ID
+		critique.CreatedAt = existing.CreatedAt
+	}
+	r.s.assignID("critiques", &critique.ID)
+	critique.UpdatedAt = time.Now()
+	if critique.CreatedAt.IsZero() {
+		critique.CreatedAt = critique.UpdatedAt
+	}
+	for i := range critique.Scores {
+		critique.Scores[i].CritiqueID = critique.ID
+	}
+
+	stored := *critique
+	stored.User = models.User{}
+	stored.Scores = append([]models.CritiqueScore(nil), critique.Scores...)
+	r.s.critiques[critique.ID] = stored
+	return nil
+}
+
+// Find ユーザーの講評を検索
+func (r *critiqueRepository) Find(ctx context.Context, taskID, workID, userID uint) (*models.Critique, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	critique, ok := r.s.findCritique(taskID, workID, userID)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	critique.User = r.s.loadUser(critique.UserID)
+	return &critique, nil
+}
+
+// Delete ユーザーの講評を点数とともに削除
+func (r *critiqueRepository) Delete(ctx context.Context, taskID, workID, userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	critique, ok := r.s.findCritique(taskID, workID, userID)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.s.critiques, critique.ID)
+	return nil
+}
+
+// ListByWork タスクに提出された作品の講評を新しい順に取得
+func (r *critiqueRepository) ListByWork(ctx context.Context, taskID, workID uint) ([]models.Critique, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	critiques := []models.Critique{}
+	for _, critique := range r.s.critiques {
+		if critique.TaskID == taskID && critique.WorkID == workID {
+			critique.User = r.s.loadUser(critique.UserID)
+			critique.Scores = append([]models.CritiqueScore(nil), critique.Scores...)
+			critiques = append(critiques, critique)
+		}
+	}
+	sort.Slice(critiques, func(i, j int) bool {
+		if !critiques[i].UpdatedAt.Equal(critiques[j].UpdatedAt) {
+			return critiques[i].UpdatedAt.After(critiques[j].UpdatedAt)
+		}
+		return critiques[i].ID > critiques[j].ID
+	})
+	return critiques, nil
+}
+
+// ListByWorks タスクに提出された複数の作品の講評を古い順に取得
+func (r *critiqueRepository) ListByWorks(ctx context.Context, taskID uint, workIDs []uint) ([]models.Critique, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := make(map[uint]bool, len(workIDs))
+	for _, id := range workIDs {
+		works[id] = true
+	}
+	critiques := []models.Critique{}
+	for _, critique := range r.s.critiques {
+		if critique.TaskID == taskID && works[critique.WorkID] {
+			critique.User = r.s.loadUser(critique.UserID)
+			critique.Scores = append([]models.CritiqueScore(nil), critique.Scores...)
+			critiques = append(critiques, critique)
+		}
+	}
+	sort.Slice(critiques, func(i, j int) bool { return critiques[i].ID < critiques[j].ID })
+	return critiques, nil
+}
+
+// Summarize タスクの講評の点数を作品ごと・評価基準ごとに集計
+func (r *critiqueRepository) Summarize(ctx context.Context, taskID uint) ([]repository.CritiqueScoreSummary, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	totals := map[pairKey]int{} // 作品ID, 評価基準ID
+	counts := map[pairKey]int64{}
+	for _, critique := range r.s.critiques {
+		if critique.TaskID != taskID {
+			continue
+		}
+		for _, score := range critique.Scores {
+			key := pairKey{critique.WorkID, score.CriterionID}
+			totals[key] += score.Score
+			counts[key]++
+		}
+	}
+
+	summaries := make([]repository.CritiqueScoreSummary, 0, len(counts))
+	for key, count := range counts {
+		summaries = append(summaries, repository.CritiqueScoreSummary{
+			WorkID:      key.a,
+			CriterionID: key.b,
+			Average:     float64(totals[key]) / float64(count),
+			Count:       count,
+		})
+	}
+	return summaries, nil
+}
+
+// findCritique ユーザーの講評を検索（ロックを取得した状態で呼び出す）
+func (s *Store) findCritique(taskID, workID, userID uint) (models.Critique, bool) {
+	for _, critique := range s.critiques {
+		if critique.TaskID == taskID && critique.WorkID == workID && critique.UserID == userID {
+			critique.Scores = append([]models.CritiqueScore(nil), critique.Scores...)
+			return critique, true
+		}
+	}
+	return models.Critique{}, false
+}
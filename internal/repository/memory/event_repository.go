@@ -0,0 +1,226 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// eventRepository EventRepositoryのインメモリ実装
+type eventRepository struct {
+	s *Store
+}
+
+// NewEventRepository EventRepositoryを作成
+func NewEventRepository(s *Store) repository.EventRepository {
+	return &eventRepository{s: s}
+}
+
+// Create 新しいイベントを作成（識別子が使われている場合は ErrEventSlugTaken）
+func (r *eventRepository) Create(ctx context.Context, event *models.Event) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if r.s.eventSlugTaken(event.Slug, 0) {
+		return repository.ErrEventSlugTaken
+	}
+	r.s.assignID("events", &event.ID)
+	stamp(&event.CreatedAt, &event.UpdatedAt)
+	r.s.events[event.ID] = stripEvent(*event)
+	return nil
+}
+
+// FindByID IDでイベントを検索
+func (r *eventRepository) FindByID(ctx context.Context, id uint) (*models.Event, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	event, ok := r.s.events[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	event = r.s.loadEvent(event)
+	return &event, nil
+}
+
+// FindBySlug 識別子でイベントを検索
+func (r *eventRepository) FindBySlug(ctx context.Context, slug string) (*models.Event, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, event := range r.s.events {
+		if event.Slug == slug {
+			event = r.s.loadEvent(event)
+			return &event, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Update イベントを更新（識別子が使われている場合は ErrEventSlugTaken）
+func (r *eventRepository) Update(ctx context.Context, event *models.Event) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if r.s.eventSlugTaken(event.Slug, event.ID) {
+		return repository.ErrEventSlugTaken
+	}
+	event.UpdatedAt = time.Now()
+	r.s.events[event.ID] = stripEvent(*event)
+	return nil
+}
+
+// Delete イベントを参加登録とともに削除
+func (r *eventRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for key := range r.s.attendees {
+		if key.a == id {
+			delete(r.s.attendees, key)
+		}
+	}
+	delete(r.s.events, id)
+	return nil
+}
+
+// List イベント一覧を取得（periodで開催前・開催中か終了済みかに絞り込む）
+func (r *eventRepository) List(ctx context.Context, now time.Time, period string, page, limit int) ([]models.Event, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	events := []models.Event{}
+	for _, event := range r.s.events {
+		switch {
+		case period == repository.EventPeriodUpcoming && event.EndsAt.Before(now):
+			continue
+		case period == repository.EventPeriodPast && !event.EndsAt.Before(now):
+			continue
+		}
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if period == repository.EventPeriodUpcoming {
+			if !a.StartsAt.Equal(b.StartsAt) {
+				return a.StartsAt.Before(b.StartsAt)
+			}
+			return a.ID < b.ID
+		}
+		return newerFirst(a.StartsAt, a.ID, b.StartsAt, b.ID)
+	})
+
+	items := paginate(events, page, limit)
+	for i := range items {
+		items[i] = r.s.loadEvent(items[i])
+	}
+	return items, int64(len(events)), nil
+}
+
+// Register イベントに参加登録（定員に達している場合は ErrEventFull、登録済みの場合は ErrAlreadyRegistered）
+func (r *eventRepository) Register(ctx context.Context, eventID, userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	event, ok := r.s.events[eventID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	key := pairKey{eventID, userID}
+	if _, ok := r.s.attendees[key]; ok {
+		return repository.ErrAlreadyRegistered
+	}
+	if event.Capacity > 0 && r.s.countAttendees(eventID) >= int64(event.Capacity) {
+		return repository.ErrEventFull
+	}
+
+	r.s.attendees[key] = models.EventAttendee{
+		EventID:      eventID,
+		UserID:       userID,
+		RegisteredAt: time.Now(),
+	}
+	return nil
+}
+
+// Unregister イベントの参加登録を取り消す
+func (r *eventRepository) Unregister(ctx context.Context, eventID, userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.attendees, pairKey{eventID, userID})
+	return nil
+}
+
+// IsRegistered ユーザーがイベントに参加登録しているか確認
+func (r *eventRepository) IsRegistered(ctx context.Context, eventID, userID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	_, ok := r.s.attendees[pairKey{eventID, userID}]
+	return ok, nil
+}
+
+// ListAttendees イベントの参加者一覧を登録の早い順に取得
+func (r *eventRepository) ListAttendees(ctx context.Context, eventID uint) ([]models.EventAttendee, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	attendees := []models.EventAttendee{}
+	for key, attendee := range r.s.attendees {
+		if key.a != eventID {
+			continue
+		}
+		attendee.User = r.s.loadUser(attendee.UserID)
+		attendees = append(attendees, attendee)
+	}
+	sort.Slice(attendees, func(i, j int) bool {
+		a, b := attendees[i], attendees[j]
+		if !a.RegisteredAt.Equal(b.RegisteredAt) {
+			return a.RegisteredAt.Before(b.RegisteredAt)
+		}
+		return a.UserID < b.UserID
+	})
+	return attendees, nil
+}
+
+// 以下のヘルパーはロックを取得した状態で呼び出す
+
+// loadEvent イベントに主催者と参加者数を読み込む
+func (s *Store) loadEvent(event models.Event) models.Event {
+	event.Organizer = s.loadUser(event.OrganizerID)
+	event.AttendeesCount = s.countAttendees(event.ID)
+	return event
+}
+
+// countAttendees イベントの参加者数を数える
+func (s *Store) countAttendees(eventID uint) int64 {
+	var count int64
+	for key := range s.attendees {
+		if key.a == eventID {
+			count++
+		}
+	}
+	return count
+}
+
+// eventSlugTaken 識別子がexcludeID以外のイベントで使われているか
+func (s *Store) eventSlugTaken(slug string, excludeID uint) bool {
+	for id, event := range s.events {
+		if id != excludeID && event.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// stripEvent 保存用にリレーションと集計値を取り除く
+func stripEvent(event models.Event) models.Event {
+	event.Organizer = models.User{}
+	event.AttendeesCount = 0
+	return event
+}
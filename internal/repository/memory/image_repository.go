@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// imageRepository ImageRepositoryのインメモリ実装
+type imageRepository struct {
+	s *Store
+}
+
+// NewImageRepository ImageRepositoryを作成
+func NewImageRepository(s *Store) repository.ImageRepository {
+	return &imageRepository{s: s}
+}
+
+// Create 画像を派生画像とともに作成
+func (r *imageRepository) Create(ctx context.Context, image *models.Image) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("images", &image.ID)
+	stamp(&image.CreatedAt, nil)
+	for i := range image.Variants {
+		r.s.assignID("image_variants", &image.Variants[i].ID)
+		image.Variants[i].ImageID = image.ID
+	}
+
+	stored := *image
+	stored.Variants = append([]models.ImageVariant(nil), image.Variants...)
+	r.s.images[image.ID] = stored
+	return nil
+}
+
+// FindByID IDで画像を検索（派生画像を含む）
+func (r *imageRepository) FindByID(ctx context.Context, id uint) (*models.Image, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	image, ok := r.s.images[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	image.Variants = append([]models.ImageVariant{}, image.Variants...)
+	return &image, nil
+}
+
+// Delete 画像を派生画像とともに削除
+func (r *imageRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.images, id)
+	return nil
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// inviteCodeRepository InviteCodeRepositoryのインメモリ実装
+type inviteCodeRepository struct {
+	s *Store
+}
+
+// NewInviteCodeRepository InviteCodeRepositoryを作成
+func NewInviteCodeRepository(s *Store) repository.InviteCodeRepository {
+	return &inviteCodeRepository{s: s}
+}
+
+// Create 新しい招待コードを作成
+func (r *inviteCodeRepository) Create(ctx context.Context, code *models.InviteCode) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.inviteCodes {
+		if existing.Code == code.Code {
+			return errDuplicate
+		}
+	}
+	r.s.assignID("invite_codes", &code.ID)
+	stamp(&code.CreatedAt, nil)
+	r.s.inviteCodes[code.ID] = *code
+	return nil
+}
+
+// FindByID IDで招待コードを検索
+func (r *inviteCodeRepository) FindByID(ctx context.Context, id uint) (*models.InviteCode, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	code, ok := r.s.inviteCodes[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &code, nil
+}
+
+// FindByCode コードで招待コードを検索
+func (r *inviteCodeRepository) FindByCode(ctx context.Context, code string) (*models.InviteCode, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, inviteCode := range r.s.inviteCodes {
+		if inviteCode.Code == code {
+			return &inviteCode, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// List 招待コード一覧を取得（新しい順）
+func (r *inviteCodeRepository) List(ctx context.Context, page, limit int) ([]models.InviteCode, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	codes := []models.InviteCode{}
+	for _, code := range r.s.inviteCodes {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return newerFirst(codes[i].CreatedAt, codes[i].ID, codes[j].CreatedAt, codes[j].ID)
+	})
+	return paginate(codes, page, limit), int64(len(codes)), nil
+}
+
+// Use 招待コードの使用回数を1つ増やす（上限に達しているか期限切れの場合は ErrInviteCodeUnavailable）
+func (r *inviteCodeRepository) Use(ctx context.Context, id uint, now time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	code, ok := r.s.inviteCodes[id]
+	if !ok || code.UsedCount >= code.MaxUses || (code.ExpiresAt != nil && !code.ExpiresAt.After(now)) {
+		return repository.ErrInviteCodeUnavailable
+	}
+	code.UsedCount++
+	r.s.inviteCodes[id] = code
+	return nil
+}
+
+// Release 使用回数を1つ戻す（登録に失敗した場合）
+func (r *inviteCodeRepository) Release(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if code, ok := r.s.inviteCodes[id]; ok && code.UsedCount > 0 {
+		code.UsedCount--
+		r.s.inviteCodes[id] = code
+	}
+	return nil
+}
+
+// Delete 招待コードを削除
+func (r *inviteCodeRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.inviteCodes, id)
+	return nil
+}
@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// invocationLogRepository InvocationLogRepositoryのインメモリ実装
+type invocationLogRepository struct {
+	s *Store
+}
+
+// NewInvocationLogRepository InvocationLogRepositoryを作成
+func NewInvocationLogRepository(s *Store) repository.InvocationLogRepository {
+	return &invocationLogRepository{s: s}
+}
+
+// Create 呼び出し履歴を記録
+func (r *invocationLogRepository) Create(ctx context.Context, log *models.InvocationLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("invocation_logs", &log.ID)
+	stamp(&log.CreatedAt, nil)
+	r.s.invocationLogs[log.ID] = *log
+	return nil
+}
+
+// SummarizeDaily 指定日時以降の呼び出しを日付・バージョンごとに集計（日付の古い順）
+func (r *invocationLogRepository) SummarizeDaily(ctx context.Context, since time.Time) ([]repository.InvocationSummary, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	type key struct {
+		date, version string
+	}
+	byKey := make(map[key]*repository.InvocationSummary)
+	for _, log := range r.s.invocationLogs {
+		if log.CreatedAt.Before(since) {
+			continue
+		}
+		k := key{date: log.CreatedAt.Local().Format("2006-01-02"), version: log.Version}
+		summary := byKey[k]
+		if summary == nil {
+			summary = &repository.InvocationSummary{Date: k.date, Version: k.version}
+			byKey[k] = summary
+		}
+		summary.Invocations++
+		if !log.Success {
+			summary.Failures++
+		}
+		if log.Attempt > 0 {
+			summary.Retries++
+		}
+		summary.TotalDurationMs += log.DurationMs
+		if log.DurationMs > summary.MaxDurationMs {
+			summary.MaxDurationMs = log.DurationMs
+		}
+		summary.TotalPayloadBytes += int64(log.PayloadBytes)
+	}
+
+	summaries := make([]repository.InvocationSummary, 0, len(byKey))
+	for _, summary := range byKey {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Date != summaries[j].Date {
+			return summaries[i].Date < summaries[j].Date
+		}
+		return summaries[i].Version < summaries[j].Version
+	})
+	return summaries, nil
+}
+
+// ListRecentFailures 指定日時以降に失敗した呼び出しを新しい順に取得
+func (r *invocationLogRepository) ListRecentFailures(ctx context.Context, since time.Time, limit int) ([]models.InvocationLog, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var logs []models.InvocationLog
+	for _, log := range r.s.invocationLogs {
+		if !log.Success && !log.CreatedAt.Before(since) {
+			logs = append(logs, log)
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return newerFirst(logs[i].CreatedAt, logs[i].ID, logs[j].CreatedAt, logs[j].ID)
+	})
+	if len(logs) > limit {
+		logs = logs[:limit]
+	}
+	return logs, nil
+}
+
+// DeleteBefore 指定日時より前の呼び出し履歴を削除し、削除した件数を返す
+func (r *invocationLogRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var deleted int64
+	for id, log := range r.s.invocationLogs {
+		if log.CreatedAt.Before(before) {
+			delete(r.s.invocationLogs, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
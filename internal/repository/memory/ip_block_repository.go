@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ipBlockRepository IPBlockRepositoryのインメモリ実装
+type ipBlockRepository struct {
+	s *Store
+}
+
+// NewIPBlockRepository IPBlockRepositoryを作成
+func NewIPBlockRepository(s *Store) repository.IPBlockRepository {
+	return &ipBlockRepository{s: s}
+}
+
+// Create 新しいブロックを作成
+func (r *ipBlockRepository) Create(ctx context.Context, block *models.IPBlock) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.ipBlocks {
+		if existing.CIDR == block.CIDR {
+			return errDuplicate
+		}
+	}
+	r.s.assignID("ip_blocks", &block.ID)
+	stamp(&block.CreatedAt, nil)
+	r.s.ipBlocks[block.ID] = *block
+	return nil
+}
+
+// FindByID IDでブロックを検索
+func (r *ipBlockRepository) FindByID(ctx context.Context, id uint) (*models.IPBlock, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	block, ok := r.s.ipBlocks[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &block, nil
+}
+
+// FindByCIDR CIDRでブロックを検索
+func (r *ipBlockRepository) FindByCIDR(ctx context.Context, cidr string) (*models.IPBlock, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, block := range r.s.ipBlocks {
+		if block.CIDR == cidr {
+			return &block, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Delete ブロックを削除
+func (r *ipBlockRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.ipBlocks, id)
+	return nil
+}
+
+// List 全てのブロックを取得（新しい順）
+func (r *ipBlockRepository) List(ctx context.Context) ([]models.IPBlock, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	blocks := []models.IPBlock{}
+	for _, block := range r.s.ipBlocks {
+		blocks = append(blocks, block)
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return newerFirst(blocks[i].CreatedAt, blocks[i].ID, blocks[j].CreatedAt, blocks[j].ID)
+	})
+	return blocks, nil
+}
+
+// ListActive 期限切れでないブロックを取得
+func (r *ipBlockRepository) ListActive(ctx context.Context, now time.Time) ([]models.IPBlock, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	blocks := []models.IPBlock{}
+	for _, block := range r.s.ipBlocks {
+		if block.ExpiresAt == nil || block.ExpiresAt.After(now) {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks, nil
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// linkPreviewRepository LinkPreviewRepositoryのインメモリ実装
+type linkPreviewRepository struct {
+	s *Store
+}
+
+// NewLinkPreviewRepository LinkPreviewRepositoryを作成
+func NewLinkPreviewRepository(s *Store) repository.LinkPreviewRepository {
+	return &linkPreviewRepository{s: s}
+}
+
+// FindByHashes URLのハッシュでリンクプレビューを取得
+func (r *linkPreviewRepository) FindByHashes(ctx context.Context, hashes []string) ([]models.LinkPreview, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	previews := []models.LinkPreview{}
+	for _, hash := range hashes {
+		if preview, ok := r.s.linkPreviews[hash]; ok {
+			previews = append(previews, preview)
+		}
+	}
+	return previews, nil
+}
+
+// Save リンクプレビューを保存（同じURLのものは置き換える）
+func (r *linkPreviewRepository) Save(ctx context.Context, preview *models.LinkPreview) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if existing, ok := r.s.linkPreviews[preview.URLHash]; ok {
+		preview.ID = existing.ID
+	} else {
+		r.s.assignID("link_previews", &preview.ID)
+	}
+	r.s.linkPreviews[preview.URLHash] = *preview
+	return nil
+}
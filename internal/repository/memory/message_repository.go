@@ -0,0 +1,273 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// messageRepository MessageRepositoryのインメモリ実装
+type messageRepository struct {
+	s *Store
+}
+
+// NewMessageRepository MessageRepositoryを作成
+func NewMessageRepository(s *Store) repository.MessageRepository {
+	return &messageRepository{s: s}
+}
+
+// CreateConversation 新しい会話を参加者とともに作成
+func (r *messageRepository) CreateConversation(ctx context.Context, userIDs []uint) (*models.Conversation, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	conversation := models.Conversation{}
+	r.s.assignID("conversations", &conversation.ID)
+	stamp(&conversation.CreatedAt, &conversation.UpdatedAt)
+	r.s.conversations[conversation.ID] = conversation
+
+	now := time.Now()
+	for _, userID := range userIDs {
+		key := pairKey{conversation.ID, userID}
+		if _, ok := r.s.participants[key]; ok {
+			return nil, errDuplicate
+		}
+		r.s.participants[key] = models.ConversationParticipant{
+			ConversationID: conversation.ID,
+			UserID:         userID,
+			JoinedAt:       now,
+		}
+	}
+
+	conversation = r.s.loadConversation(conversation)
+	return &conversation, nil
+}
+
+// FindConversationByID IDで会話を検索
+func (r *messageRepository) FindConversationByID(ctx context.Context, id uint) (*models.Conversation, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	conversation, ok := r.s.conversations[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	conversation = r.s.loadConversation(conversation)
+	return &conversation, nil
+}
+
+// FindConversationBetween 2人のユーザー間の会話を検索
+func (r *messageRepository) FindConversationBetween(ctx context.Context, userID, otherUserID uint) (*models.Conversation, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var conversationID uint
+	for key := range r.s.participants {
+		if key.b != userID {
+			continue
+		}
+		if _, ok := r.s.participants[pairKey{key.a, otherUserID}]; !ok {
+			continue
+		}
+		if conversationID == 0 || key.a < conversationID {
+			conversationID = key.a
+		}
+	}
+
+	conversation, ok := r.s.conversations[conversationID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	conversation = r.s.loadConversation(conversation)
+	return &conversation, nil
+}
+
+// IsParticipant ユーザーが会話の参加者かどうか確認
+func (r *messageRepository) IsParticipant(ctx context.Context, conversationID, userID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	_, ok := r.s.participants[pairKey{conversationID, userID}]
+	return ok, nil
+}
+
+// ListConversations ユーザーが参加している会話一覧を取得
+func (r *messageRepository) ListConversations(ctx context.Context, userID uint, page, limit int) ([]models.Conversation, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	conversations := []models.Conversation{}
+	for key := range r.s.participants {
+		if key.b != userID {
+			continue
+		}
+		if conversation, ok := r.s.conversations[key.a]; ok {
+			conversations = append(conversations, conversation)
+		}
+	}
+
+	// 最終メッセージの新しい順（メッセージのない会話は後ろ）
+	sort.Slice(conversations, func(i, j int) bool {
+		a, b := conversations[i].LastMessageAt, conversations[j].LastMessageAt
+		switch {
+		case a != nil && b != nil && !a.Equal(*b):
+			return a.After(*b)
+		case a != nil && b == nil:
+			return true
+		case a == nil && b != nil:
+			return false
+		}
+		return conversations[i].ID > conversations[j].ID
+	})
+
+	items := paginate(conversations, page, limit)
+	for i := range items {
+		items[i] = r.s.loadConversation(items[i])
+	}
+	return items, int64(len(conversations)), nil
+}
+
+// CreateMessage メッセージを作成し、会話の最終メッセージ日時を更新
+func (r *messageRepository) CreateMessage(ctx context.Context, message *models.Message) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("messages", &message.ID)
+	stamp(&message.CreatedAt, nil)
+	stored := *message
+	stored.Sender = models.User{}
+	r.s.messages[message.ID] = stored
+
+	if conversation, ok := r.s.conversations[message.ConversationID]; ok {
+		lastMessageAt := message.CreatedAt
+		conversation.LastMessageAt = &lastMessageAt
+		conversation.UpdatedAt = time.Now()
+		r.s.conversations[message.ConversationID] = conversation
+	}
+
+	// 送信者自身は既読扱い
+	r.s.markRead(message.ConversationID, message.SenderID, message.CreatedAt)
+	return nil
+}
+
+// ListMessages 会話のメッセージ一覧を取得（新しい順）
+func (r *messageRepository) ListMessages(ctx context.Context, conversationID uint, page, limit int) ([]models.Message, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	messages := r.s.messageList(conversationID)
+	items := paginate(messages, page, limit)
+	for i := range items {
+		items[i].Sender = r.s.loadUser(items[i].SenderID)
+	}
+	return items, int64(len(messages)), nil
+}
+
+// GetLastMessage 会話の最新メッセージを取得
+func (r *messageRepository) GetLastMessage(ctx context.Context, conversationID uint) (*models.Message, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	messages := r.s.messageList(conversationID)
+	if len(messages) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	message := messages[0]
+	message.Sender = r.s.loadUser(message.SenderID)
+	return &message, nil
+}
+
+// CountUnread 会話内の未読メッセージ数を取得
+func (r *messageRepository) CountUnread(ctx context.Context, conversationID, userID uint) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.s.countUnread(conversationID, userID), nil
+}
+
+// CountUnreadTotal ユーザーの全会話の未読メッセージ数を取得
+func (r *messageRepository) CountUnreadTotal(ctx context.Context, userID uint) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for key := range r.s.participants {
+		if key.b == userID {
+			count += r.s.countUnread(key.a, userID)
+		}
+	}
+	return count, nil
+}
+
+// MarkAsRead 会話を既読にする
+func (r *messageRepository) MarkAsRead(ctx context.Context, conversationID, userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.markRead(conversationID, userID, time.Now())
+	return nil
+}
+
+// 以下のヘルパーはロックを取得した状態で呼び出す
+
+// loadConversation 会話に参加者（ユーザーを含む）を読み込む
+func (s *Store) loadConversation(conversation models.Conversation) models.Conversation {
+	participants := []models.ConversationParticipant{}
+	for key, participant := range s.participants {
+		if key.a == conversation.ID {
+			participant.User = s.loadUser(participant.UserID)
+			participants = append(participants, participant)
+		}
+	}
+	sort.Slice(participants, func(i, j int) bool { return participants[i].UserID < participants[j].UserID })
+	conversation.Participants = participants
+	return conversation
+}
+
+// messageList 会話のメッセージを新しい順に取得
+func (s *Store) messageList(conversationID uint) []models.Message {
+	messages := []models.Message{}
+	for _, message := range s.messages {
+		if message.ConversationID == conversationID {
+			messages = append(messages, message)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool {
+		a, b := messages[i], messages[j]
+		return newerFirst(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+	return messages
+}
+
+// countUnread 会話内でユーザーが未読のメッセージ数を集計（参加者でない場合は0）
+func (s *Store) countUnread(conversationID, userID uint) int64 {
+	participant, ok := s.participants[pairKey{conversationID, userID}]
+	if !ok {
+		return 0
+	}
+
+	var count int64
+	for _, message := range s.messages {
+		if message.ConversationID != conversationID || message.SenderID == userID {
+			continue
+		}
+		if participant.LastReadAt == nil || message.CreatedAt.After(*participant.LastReadAt) {
+			count++
+		}
+	}
+	return count
+}
+
+// markRead 参加者の既読日時を更新
+func (s *Store) markRead(conversationID, userID uint, at time.Time) {
+	key := pairKey{conversationID, userID}
+	if participant, ok := s.participants[key]; ok {
+		participant.LastReadAt = &at
+		s.participants[key] = participant
+	}
+}
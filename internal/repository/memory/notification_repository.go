@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// notificationRepository NotificationRepositoryのインメモリ実装
+type notificationRepository struct {
+	s *Store
+}
+
+// NewNotificationRepository NotificationRepositoryを作成
+func NewNotificationRepository(s *Store) repository.NotificationRepository {
+	return &notificationRepository{s: s}
+}
+
+// Create 新しい通知を作成
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.createNotification(notification)
+	return nil
+}
+
+// CreateBatch 複数の通知をまとめて作成
+func (r *notificationRepository) CreateBatch(ctx context.Context, notifications []models.Notification) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for i := range notifications {
+		r.s.createNotification(&notifications[i])
+	}
+	return nil
+}
+
+// ListByUser ユーザーの通知一覧を取得
+func (r *notificationRepository) ListByUser(ctx context.Context, userID uint, page, limit int, unreadOnly bool) ([]models.Notification, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	notifications := []models.Notification{}
+	for _, notification := range r.s.notifications {
+		if notification.UserID != userID {
+			continue
+		}
+		// 未読のみに絞り込み
+		if unreadOnly && notification.IsRead {
+			continue
+		}
+		notifications = append(notifications, notification)
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		a, b := notifications[i], notifications[j]
+		return newerFirst(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+
+	return paginate(notifications, page, limit), int64(len(notifications)), nil
+}
+
+// CountUnread 未読の通知数を取得
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for _, notification := range r.s.notifications {
+		if notification.UserID == userID && !notification.IsRead {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MarkAsRead 通知を既読にする
+func (r *notificationRepository) MarkAsRead(ctx context.Context, id, userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	notification, ok := r.s.notifications[id]
+	if !ok || notification.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	notification.IsRead = true
+	r.s.notifications[id] = notification
+	return nil
+}
+
+// MarkAllAsRead ユーザーの通知をすべて既読にする
+func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, notification := range r.s.notifications {
+		if notification.UserID == userID && !notification.IsRead {
+			notification.IsRead = true
+			r.s.notifications[id] = notification
+		}
+	}
+	return nil
+}
+
+// createNotification 通知を保存（ロックを取得した状態で呼び出す）
+func (s *Store) createNotification(notification *models.Notification) {
+	s.assignID("notifications", &notification.ID)
+	stamp(&notification.CreatedAt, nil)
+	s.notifications[notification.ID] = *notification
+}
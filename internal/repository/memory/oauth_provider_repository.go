@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// oauthProviderRepository OAuthProviderRepositoryのインメモリ実装
+type oauthProviderRepository struct {
+	s *Store
+}
+
+// NewOAuthProviderRepository OAuthProviderRepositoryを作成
+func NewOAuthProviderRepository(s *Store) repository.OAuthProviderRepository {
+	return &oauthProviderRepository{s: s}
+}
+
+// Find プロバイダーでのユーザーIDから連携を検索
+func (r *oauthProviderRepository) Find(ctx context.Context, provider, providerUserID string) (*models.OAuthProvider, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, identity := range r.s.oauthIDs {
+		if identity.Provider == provider && identity.ProviderUserID == providerUserID {
+			return &identity, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ListByUser ユーザーが連携しているアカウントを連携した順に取得
+func (r *oauthProviderRepository) ListByUser(ctx context.Context, userID uint) ([]models.OAuthProvider, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	identities := []models.OAuthProvider{}
+	for _, identity := range r.s.oauthIDs {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	sort.Slice(identities, func(i, j int) bool { return identities[i].ID < identities[j].ID })
+	return identities, nil
+}
+
+// Create 連携を作成
+func (r *oauthProviderRepository) Create(ctx context.Context, identity *models.OAuthProvider) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.oauthIDs {
+		if existing.Provider != identity.Provider {
+			continue
+		}
+		if existing.UserID == identity.UserID || existing.ProviderUserID == identity.ProviderUserID {
+			return errDuplicate
+		}
+	}
+	r.s.assignID("oauth_providers", &identity.ID)
+	stamp(&identity.CreatedAt, &identity.UpdatedAt)
+	r.s.oauthIDs[identity.ID] = *identity
+	return nil
+}
+
+// Update 連携を更新
+func (r *oauthProviderRepository) Update(ctx context.Context, identity *models.OAuthProvider) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.oauthIDs[identity.ID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	identity.UpdatedAt = time.Now()
+	r.s.oauthIDs[identity.ID] = *identity
+	return nil
+}
+
+// Delete ユーザーのプロバイダーの連携を削除
+func (r *oauthProviderRepository) Delete(ctx context.Context, userID uint, provider string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, identity := range r.s.oauthIDs {
+		if identity.UserID == userID && identity.Provider == provider {
+			delete(r.s.oauthIDs, id)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
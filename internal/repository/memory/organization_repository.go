@@ -0,0 +1,268 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// organizationRepository OrganizationRepositoryのインメモリ実装
+type organizationRepository struct {
+	s *Store
+}
+
+// NewOrganizationRepository OrganizationRepositoryを作成
+func NewOrganizationRepository(s *Store) repository.OrganizationRepository {
+	return &organizationRepository{s: s}
+}
+
+// Create 新しい組織を作成し、作成者をオーナーとしてメンバーに追加
+func (r *organizationRepository) Create(ctx context.Context, organization *models.Organization, ownerID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("organizations", &organization.ID)
+	stamp(&organization.CreatedAt, &organization.UpdatedAt)
+	r.s.organizations[organization.ID] = stripOrganization(*organization)
+	r.s.orgMembers[pairKey{organization.ID, ownerID}] = models.OrganizationMember{
+		OrganizationID: organization.ID,
+		UserID:         ownerID,
+		Role:           models.OrganizationRoleOwner,
+		JoinedAt:       time.Now(),
+	}
+	return nil
+}
+
+// FindByID IDで組織を検索
+func (r *organizationRepository) FindByID(ctx context.Context, id uint) (*models.Organization, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	organization, ok := r.s.organizations[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	organization = r.s.loadOrganization(organization)
+	return &organization, nil
+}
+
+// Update 組織を更新
+func (r *organizationRepository) Update(ctx context.Context, organization *models.Organization) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("organizations", &organization.ID)
+	organization.UpdatedAt = time.Now()
+	r.s.organizations[organization.ID] = stripOrganization(*organization)
+	return nil
+}
+
+// Delete 組織をメンバーとともに削除（プロジェクトがある場合は ErrOrganizationHasProjects）
+// 削除済みのプロジェクトは個人のプロジェクトに戻す
+func (r *organizationRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.organizations[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if r.s.countOrganizationProjects(id) > 0 {
+		return repository.ErrOrganizationHasProjects
+	}
+
+	for projectID, project := range r.s.projects {
+		if project.OrganizationID != nil && *project.OrganizationID == id {
+			project.OrganizationID = nil
+			r.s.projects[projectID] = project
+		}
+	}
+	for key := range r.s.orgMembers {
+		if key.a == id {
+			delete(r.s.orgMembers, key)
+		}
+	}
+	delete(r.s.organizations, id)
+	return nil
+}
+
+// ListByUser ユーザーが所属する組織一覧を名前順に取得
+func (r *organizationRepository) ListByUser(ctx context.Context, userID uint) ([]models.Organization, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	organizations := []models.Organization{}
+	for key := range r.s.orgMembers {
+		if key.b != userID {
+			continue
+		}
+		if organization, ok := r.s.organizations[key.a]; ok {
+			organizations = append(organizations, r.s.loadOrganization(organization))
+		}
+	}
+	sort.Slice(organizations, func(i, j int) bool {
+		if organizations[i].Name != organizations[j].Name {
+			return organizations[i].Name < organizations[j].Name
+		}
+		return organizations[i].ID < organizations[j].ID
+	})
+	return organizations, nil
+}
+
+// GetRole ユーザーの組織での役割を取得（メンバーでない場合は空文字）
+func (r *organizationRepository) GetRole(ctx context.Context, organizationID, userID uint) (string, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.s.orgMembers[pairKey{organizationID, userID}].Role, nil
+}
+
+// ListMembers 組織のメンバー一覧を参加の早い順に取得
+func (r *organizationRepository) ListMembers(ctx context.Context, organizationID uint) ([]models.OrganizationMember, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	members := []models.OrganizationMember{}
+	for key, member := range r.s.orgMembers {
+		if key.a == organizationID {
+			member.User = r.s.loadUser(member.UserID)
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].JoinedAt.Equal(members[j].JoinedAt) {
+			return members[i].JoinedAt.Before(members[j].JoinedAt)
+		}
+		return members[i].UserID < members[j].UserID
+	})
+	return members, nil
+}
+
+// AddMember 組織にメンバーを追加（上限に達している場合は ErrOrganizationFull、追加済みの場合は ErrAlreadyOrganizationMember）
+func (r *organizationRepository) AddMember(ctx context.Context, organizationID, userID uint, role string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	organization, ok := r.s.organizations[organizationID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if _, ok := r.s.orgMembers[pairKey{organizationID, userID}]; ok {
+		return repository.ErrAlreadyOrganizationMember
+	}
+	if organization.MaxMembers > 0 && r.s.countOrganizationMembers(organizationID) >= int64(organization.MaxMembers) {
+		return repository.ErrOrganizationFull
+	}
+
+	r.s.orgMembers[pairKey{organizationID, userID}] = models.OrganizationMember{
+		OrganizationID: organizationID,
+		UserID:         userID,
+		Role:           role,
+		JoinedAt:       time.Now(),
+	}
+	return nil
+}
+
+// UpdateRole メンバーの役割を変更（最後のオーナーを外す場合は ErrLastOrganizationOwner）
+func (r *organizationRepository) UpdateRole(ctx context.Context, organizationID, userID uint, role string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	member, ok := r.s.orgMembers[pairKey{organizationID, userID}]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if member.Role == models.OrganizationRoleOwner && role != models.OrganizationRoleOwner &&
+		r.s.countOrganizationOwners(organizationID) <= 1 {
+		return repository.ErrLastOrganizationOwner
+	}
+	member.Role = role
+	r.s.orgMembers[pairKey{organizationID, userID}] = member
+	return nil
+}
+
+// RemoveMember メンバーを組織から削除（最後のオーナーの場合は ErrLastOrganizationOwner）
+func (r *organizationRepository) RemoveMember(ctx context.Context, organizationID, userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	member, ok := r.s.orgMembers[pairKey{organizationID, userID}]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if member.Role == models.OrganizationRoleOwner && r.s.countOrganizationOwners(organizationID) <= 1 {
+		return repository.ErrLastOrganizationOwner
+	}
+	delete(r.s.orgMembers, pairKey{organizationID, userID})
+	return nil
+}
+
+// ListProjects 組織のプロジェクト一覧を取得
+func (r *organizationRepository) ListProjects(ctx context.Context, organizationID uint, page, limit int, search string) ([]models.Project, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	projects := []models.Project{}
+	for _, project := range r.s.projects {
+		if project.DeletedAt.Valid || project.OrganizationID == nil || *project.OrganizationID != organizationID {
+			continue
+		}
+		if search != "" && !containsFold(project.Title, search) && !containsFold(project.Description, search) {
+			continue
+		}
+		projects = append(projects, project)
+	}
+
+	return r.s.pageProjects(projects, page, limit), int64(len(projects)), nil
+}
+
+// loadOrganization 組織のメンバー数とプロジェクト数を設定（ロックを取得した状態で呼び出す）
+func (s *Store) loadOrganization(organization models.Organization) models.Organization {
+	organization.MembersCount = s.countOrganizationMembers(organization.ID)
+	organization.ProjectsCount = s.countOrganizationProjects(organization.ID)
+	return organization
+}
+
+// countOrganizationMembers 組織のメンバー数を数える
+func (s *Store) countOrganizationMembers(organizationID uint) int64 {
+	var count int64
+	for key := range s.orgMembers {
+		if key.a == organizationID {
+			count++
+		}
+	}
+	return count
+}
+
+// countOrganizationOwners 組織のオーナーの人数を数える
+func (s *Store) countOrganizationOwners(organizationID uint) int64 {
+	var count int64
+	for key, member := range s.orgMembers {
+		if key.a == organizationID && member.Role == models.OrganizationRoleOwner {
+			count++
+		}
+	}
+	return count
+}
+
+// countOrganizationProjects 組織の削除されていないプロジェクト数を数える
+func (s *Store) countOrganizationProjects(organizationID uint) int64 {
+	var count int64
+	for _, project := range s.projects {
+		if !project.DeletedAt.Valid && project.OrganizationID != nil && *project.OrganizationID == organizationID {
+			count++
+		}
+	}
+	return count
+}
+
+// stripOrganization 保存用に読み込み時に設定する項目を取り除く
+func stripOrganization(organization models.Organization) models.Organization {
+	organization.MembersCount = 0
+	organization.ProjectsCount = 0
+	return organization
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// personalTokenRepository PersonalTokenRepositoryのインメモリ実装
+type personalTokenRepository struct {
+	s *Store
+}
+
+// NewPersonalTokenRepository PersonalTokenRepositoryを作成
+func NewPersonalTokenRepository(s *Store) repository.PersonalTokenRepository {
+	return &personalTokenRepository{s: s}
+}
+
+// Create 個人用アクセストークンを作成
+func (r *personalTokenRepository) Create(ctx context.Context, token *models.PersonalToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.personalTokens {
+		if existing.TokenHash == token.TokenHash {
+			return errDuplicate
+		}
+	}
+	r.s.assignID("personal_tokens", &token.ID)
+	stamp(&token.CreatedAt, &token.UpdatedAt)
+	r.s.personalTokens[token.ID] = *token
+	return nil
+}
+
+// FindByID IDで個人用アクセストークンを取得
+func (r *personalTokenRepository) FindByID(ctx context.Context, id uint) (*models.PersonalToken, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	token, ok := r.s.personalTokens[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &token, nil
+}
+
+// FindByHash トークンのハッシュで個人用アクセストークンを取得
+func (r *personalTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.PersonalToken, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, token := range r.s.personalTokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// ListByUser ユーザーの個人用アクセストークンを作成日時の新しい順に取得
+func (r *personalTokenRepository) ListByUser(ctx context.Context, userID uint) ([]models.PersonalToken, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	tokens := []models.PersonalToken{}
+	for _, token := range r.s.personalTokens {
+		if token.UserID == userID {
+			tokens = append(tokens, token)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if !tokens[i].CreatedAt.Equal(tokens[j].CreatedAt) {
+			return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+		}
+		return tokens[i].ID > tokens[j].ID
+	})
+	return tokens, nil
+}
+
+// CountByUser ユーザーの個人用アクセストークン数を数える
+func (r *personalTokenRepository) CountByUser(ctx context.Context, userID uint) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for _, token := range r.s.personalTokens {
+		if token.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Update 個人用アクセストークンの名前・スコープを更新
+func (r *personalTokenRepository) Update(ctx context.Context, token *models.PersonalToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	existing, ok := r.s.personalTokens[token.ID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	existing.Name = token.Name
+	existing.Scopes = token.Scopes
+	existing.UpdatedAt = time.Now()
+	r.s.personalTokens[token.ID] = existing
+	token.UpdatedAt = existing.UpdatedAt
+	return nil
+}
+
+// TouchLastUsed 最後に使われた日時を更新
+func (r *personalTokenRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if token, ok := r.s.personalTokens[id]; ok {
+		token.LastUsedAt = &at
+		r.s.personalTokens[id] = token
+	}
+	return nil
+}
+
+// Delete 個人用アクセストークンを削除
+func (r *personalTokenRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.personalTokens, id)
+	return nil
+}
@@ -0,0 +1,652 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// projectRepository ProjectRepositoryのインメモリ実装
+type projectRepository struct {
+	s *Store
+}
+
+// NewProjectRepository ProjectRepositoryを作成
+func NewProjectRepository(s *Store) repository.ProjectRepository {
+	return &projectRepository{s: s}
+}
+
+// Create 新しいプロジェクトを作成
+func (r *projectRepository) Create(ctx context.Context, project *models.Project) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// 招待コードの一意制約
+	if project.InvitationCode != "" {
+		for _, p := range r.s.projects {
+			if p.InvitationCode == project.InvitationCode {
+				return errDuplicate
+			}
+		}
+	}
+
+	r.s.assignID("projects", &project.ID)
+	stamp(&project.CreatedAt, &project.UpdatedAt)
+	initVersion(&project.Version)
+	r.s.projects[project.ID] = stripProject(*project)
+	return nil
+}
+
+// Clone sourceIDのプロジェクトのタスク・評価基準・投票をコピーしたプロジェクトを作成し、オーナーをメンバーに追加
+// 提出作品・メンバー・講評・投票の回答と、作品を選ぶ選択肢・締め切りはコピーしない
+func (r *projectRepository) Clone(ctx context.Context, sourceID uint, project *models.Project) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, p := range r.s.projects {
+		if p.InvitationCode == project.InvitationCode {
+			return errDuplicate
+		}
+	}
+
+	r.s.assignID("projects", &project.ID)
+	stamp(&project.CreatedAt, &project.UpdatedAt)
+	initVersion(&project.Version)
+	r.s.projects[project.ID] = stripProject(*project)
+	r.s.members[pairKey{project.ID, project.OwnerID}] = models.ProjectMember{
+		ProjectID: project.ID,
+		UserID:    project.OwnerID,
+		IsOwner:   true,
+		JoinedAt:  time.Now(),
+	}
+
+	for _, source := range r.s.projectTasks(sourceID) {
+		task := models.Task{
+			Title:           source.Title,
+			Description:     source.Description,
+			DescriptionHTML: source.DescriptionHTML,
+			ProjectID:       project.ID,
+			OrderIndex:      source.OrderIndex,
+			Version:         1,
+		}
+		r.s.assignID("tasks", &task.ID)
+		stamp(&task.CreatedAt, &task.UpdatedAt)
+		r.s.tasks[task.ID] = task
+		r.s.cloneTaskCriteria(source.ID, task.ID)
+		r.s.cloneTaskVotes(source.ID, task.ID, project.OwnerID)
+	}
+	return nil
+}
+
+// projectTasks プロジェクトの削除されていないタスクを表示順に取得（ロックを取得した状態で呼び出す）
+func (s *Store) projectTasks(projectID uint) []models.Task {
+	tasks := []models.Task{}
+	for _, task := range s.tasks {
+		if task.ProjectID == projectID && !task.DeletedAt.Valid {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].OrderIndex != tasks[j].OrderIndex {
+			return tasks[i].OrderIndex < tasks[j].OrderIndex
+		}
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+	return tasks
+}
+
+// cloneTaskCriteria タスクの講評の評価基準をコピー（ロックを取得した状態で呼び出す）
+func (s *Store) cloneTaskCriteria(sourceTaskID, taskID uint) {
+	criteria := []models.CritiqueCriterion{}
+	for _, criterion := range s.criteria {
+		if criterion.TaskID == sourceTaskID {
+			criteria = append(criteria, criterion)
+		}
+	}
+	sort.Slice(criteria, func(i, j int) bool {
+		if criteria[i].OrderIndex != criteria[j].OrderIndex {
+			return criteria[i].OrderIndex < criteria[j].OrderIndex
+		}
+		return criteria[i].ID < criteria[j].ID
+	})
+	for _, criterion := range criteria {
+		criterion.ID = 0
+		criterion.TaskID = taskID
+		criterion.CreatedAt = time.Time{}
+		s.assignID("critique_criteria", &criterion.ID)
+		stamp(&criterion.CreatedAt, nil)
+		s.criteria[criterion.ID] = criterion
+	}
+}
+
+// cloneTaskVotes タスクの投票を受付中としてコピー（作品を選ぶ選択肢は提出作品のためコピーしない、ロックを取得した状態で呼び出す）
+func (s *Store) cloneTaskVotes(sourceTaskID, taskID, ownerID uint) {
+	votes := []models.Vote{}
+	for _, vote := range s.votes {
+		if vote.TaskID == sourceTaskID {
+			votes = append(votes, vote)
+		}
+	}
+	sort.Slice(votes, func(i, j int) bool { return votes[i].ID < votes[j].ID })
+
+	for _, source := range votes {
+		vote := models.Vote{
+			Title:       source.Title,
+			Description: source.Description,
+			TaskID:      taskID,
+			MultiSelect: source.MultiSelect,
+			Anonymous:   source.Anonymous,
+			IsActive:    true,
+			CreatedBy:   ownerID,
+		}
+		s.assignID("votes", &vote.ID)
+		stamp(&vote.CreatedAt, &vote.UpdatedAt)
+		s.votes[vote.ID] = vote
+
+		options := []models.VoteOption{}
+		for _, option := range s.voteOptions {
+			if option.VoteID == source.ID && option.WorkID == nil {
+				options = append(options, option)
+			}
+		}
+		sort.Slice(options, func(i, j int) bool { return options[i].ID < options[j].ID })
+		for _, source := range options {
+			option := models.VoteOption{VoteID: vote.ID, OptionText: source.OptionText}
+			s.assignID("vote_options", &option.ID)
+			stamp(&option.CreatedAt, nil)
+			s.voteOptions[option.ID] = option
+		}
+	}
+}
+
+// FindByID IDでプロジェクトを検索
+func (r *projectRepository) FindByID(ctx context.Context, id uint) (*models.Project, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	project, ok := r.s.liveProject(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	project.Owner = r.s.loadUser(project.OwnerID)
+	return &project, nil
+}
+
+// FindByInvitationCode 招待コードでプロジェクトを検索
+func (r *projectRepository) FindByInvitationCode(ctx context.Context, code string) (*models.Project, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, project := range r.s.projects {
+		if project.InvitationCode == code && !project.DeletedAt.Valid {
+			project.Owner = r.s.loadUser(project.OwnerID)
+			return &project, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Update プロジェクト情報を更新
+func (r *projectRepository) Update(ctx context.Context, project *models.Project) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if stored, ok := r.s.projects[project.ID]; ok {
+		if err := bumpVersion(stored.Version, &project.Version); err != nil {
+			return err
+		}
+	}
+	r.s.assignID("projects", &project.ID)
+	project.UpdatedAt = time.Now()
+	r.s.projects[project.ID] = stripProject(*project)
+	return nil
+}
+
+// Delete プロジェクトを削除
+func (r *projectRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if project, ok := r.s.liveProject(id); ok {
+		project.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		r.s.projects[id] = project
+	}
+	return nil
+}
+
+// List プロジェクト一覧を取得
+func (r *projectRepository) List(ctx context.Context, page, limit int, search string, userID *uint) ([]models.Project, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	projects := []models.Project{}
+	for _, project := range r.s.projects {
+		if project.DeletedAt.Valid {
+			continue
+		}
+		// 検索条件を適用
+		if search != "" && !containsFold(project.Title, search) && !containsFold(project.Description, search) {
+			continue
+		}
+		// ユーザーIDが指定された場合は、そのユーザーが参加しているプロジェクトに限定
+		if userID != nil {
+			if _, ok := r.s.members[pairKey{project.ID, *userID}]; !ok {
+				continue
+			}
+		}
+		projects = append(projects, project)
+	}
+
+	return r.s.pageProjects(projects, page, limit), int64(len(projects)), nil
+}
+
+// ListContests コンテスト一覧を取得（phaseが空の場合は全ての進行状況）
+func (r *projectRepository) ListContests(ctx context.Context, page, limit int, phase string) ([]models.Project, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	projects := []models.Project{}
+	for _, project := range r.s.projects {
+		if project.DeletedAt.Valid || !project.IsContest {
+			continue
+		}
+		if phase != "" && project.ContestPhase != phase {
+			continue
+		}
+		projects = append(projects, project)
+	}
+
+	return r.s.pageProjects(projects, page, limit), int64(len(projects)), nil
+}
+
+// ListContestsByMember ユーザーが参加しているコンテスト一覧を取得
+func (r *projectRepository) ListContestsByMember(ctx context.Context, userID uint, page, limit int) ([]models.Project, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	projects := []models.Project{}
+	for _, project := range r.s.projects {
+		if project.DeletedAt.Valid || !project.IsContest {
+			continue
+		}
+		if _, ok := r.s.members[pairKey{project.ID, userID}]; ok {
+			projects = append(projects, project)
+		}
+	}
+
+	return r.s.pageProjects(projects, page, limit), int64(len(projects)), nil
+}
+
+// AddMember メンバーをプロジェクトに追加
+func (r *projectRepository) AddMember(ctx context.Context, projectID, userID uint, isOwner bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := pairKey{projectID, userID}
+	if _, ok := r.s.members[key]; ok {
+		return errDuplicate
+	}
+	r.s.members[key] = models.ProjectMember{
+		ProjectID: projectID,
+		UserID:    userID,
+		IsOwner:   isOwner,
+		JoinedAt:  time.Now(),
+	}
+	return nil
+}
+
+// RemoveMember メンバーをプロジェクトから削除
+func (r *projectRepository) RemoveMember(ctx context.Context, projectID, userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.members, pairKey{projectID, userID})
+	return nil
+}
+
+// TouchMember メンバーの最後の活動日時を更新（メンバーでない場合は何もしない）
+func (r *projectRepository) TouchMember(ctx context.Context, projectID, userID uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.touchMember(projectID, userID, at)
+	return nil
+}
+
+// TouchMembersByWork 作品が提出されているプロジェクトで、ユーザーのメンバーとしての最後の活動日時を更新
+func (r *projectRepository) TouchMembersByWork(ctx context.Context, workID, userID uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for key := range r.s.taskWorks {
+		if key.b != workID {
+			continue
+		}
+		if task, ok := r.s.liveTask(key.a); ok {
+			r.s.touchMember(task.ProjectID, userID, at)
+		}
+	}
+	return nil
+}
+
+// touchMember メンバーの最後の活動日時を更新（ロックを取得した状態で呼び出す）
+func (s *Store) touchMember(projectID, userID uint, at time.Time) {
+	key := pairKey{projectID, userID}
+	member, ok := s.members[key]
+	if !ok || (member.LastActivityAt != nil && !member.LastActivityAt.Before(at)) {
+		return
+	}
+	member.LastActivityAt = &at
+	s.members[key] = member
+}
+
+// GetMembers プロジェクトのメンバー一覧を取得
+func (r *projectRepository) GetMembers(ctx context.Context, projectID uint) ([]models.ProjectMember, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	members := []models.ProjectMember{}
+	for key, member := range r.s.members {
+		if key.a == projectID {
+			member.User = r.s.loadUser(member.UserID)
+			members = append(members, member)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if !members[i].JoinedAt.Equal(members[j].JoinedAt) {
+			return members[i].JoinedAt.Before(members[j].JoinedAt)
+		}
+		return members[i].UserID < members[j].UserID
+	})
+	return members, nil
+}
+
+// IsMember ユーザーがプロジェクトのメンバーかどうか確認（組織のオーナー・管理者は組織のプロジェクトのメンバーとして扱う）
+func (r *projectRepository) IsMember(ctx context.Context, projectID, userID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	if _, ok := r.s.members[pairKey{projectID, userID}]; ok {
+		return true, nil
+	}
+	return r.s.isOrganizationManager(projectID, userID), nil
+}
+
+// IsOwner ユーザーがプロジェクトのオーナーかどうか確認（組織のオーナー・管理者は組織のプロジェクトのオーナーとして扱う）
+func (r *projectRepository) IsOwner(ctx context.Context, projectID, userID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	if member, ok := r.s.members[pairKey{projectID, userID}]; ok && member.IsOwner {
+		return true, nil
+	}
+	return r.s.isOrganizationManager(projectID, userID), nil
+}
+
+// GetUserProjects ユーザーが参加しているプロジェクト一覧を取得
+func (r *projectRepository) GetUserProjects(ctx context.Context, userID uint, page, limit int) ([]models.Project, int64, error) {
+	return r.List(ctx, page, limit, "", &userID)
+}
+
+// CountOwned ユーザーがオーナーの個人のプロジェクト数を数える（組織のプロジェクトは含めない）
+func (r *projectRepository) CountOwned(ctx context.Context, userID uint) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for _, project := range r.s.projects {
+		if !project.DeletedAt.Valid && project.OwnerID == userID && project.OrganizationID == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// UpdateInvitationCode 招待コードを更新
+func (r *projectRepository) UpdateInvitationCode(ctx context.Context, projectID uint, code string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if project, ok := r.s.liveProject(projectID); ok {
+		project.InvitationCode = code
+		project.UpdatedAt = time.Now()
+		r.s.projects[projectID] = project
+	}
+	return nil
+}
+
+// SharesProject 2人のユーザーが同じプロジェクトに参加しているか確認
+func (r *projectRepository) SharesProject(ctx context.Context, userID, otherUserID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for key := range r.s.members {
+		if key.b != userID {
+			continue
+		}
+		if _, ok := r.s.liveProject(key.a); !ok {
+			continue
+		}
+		if _, ok := r.s.members[pairKey{key.a, otherUserID}]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SetSupervised メンバーの見守りを設定
+func (r *projectRepository) SetSupervised(ctx context.Context, projectID, userID uint, supervised bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := pairKey{projectID, userID}
+	if member, ok := r.s.members[key]; ok {
+		member.Supervised = supervised
+		r.s.members[key] = member
+	}
+	return nil
+}
+
+// IsSupervised ユーザーが見守りを有効にされているプロジェクトがあるか確認
+func (r *projectRepository) IsSupervised(ctx context.Context, userID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for key, member := range r.s.members {
+		if key.b != userID || !member.Supervised {
+			continue
+		}
+		if _, ok := r.s.liveProject(key.a); ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SharesSupervisedProject minorIDのユーザーが見守りを有効にされているプロジェクトに、otherUserIDのユーザーも参加しているか確認
+func (r *projectRepository) SharesSupervisedProject(ctx context.Context, minorID, otherUserID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for key, member := range r.s.members {
+		if key.b != minorID || !member.Supervised {
+			continue
+		}
+		if _, ok := r.s.liveProject(key.a); !ok {
+			continue
+		}
+		if _, ok := r.s.members[pairKey{key.a, otherUserID}]; ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CountDashboard メンバー数・タスク数・提出作品数・受付中の投票数を集計
+func (r *projectRepository) CountDashboard(ctx context.Context, projectID uint) (*repository.ProjectDashboardCounts, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var counts repository.ProjectDashboardCounts
+	for key := range r.s.members {
+		if key.a == projectID {
+			counts.Members++
+		}
+	}
+	for _, task := range r.s.tasks {
+		if task.ProjectID == projectID && !task.DeletedAt.Valid {
+			counts.Tasks++
+		}
+	}
+	for key := range r.s.taskWorks {
+		task, ok := r.s.liveTask(key.a)
+		if !ok || task.ProjectID != projectID {
+			continue
+		}
+		if _, ok := r.s.liveWork(key.b); ok {
+			counts.Submissions++
+		}
+	}
+	for _, vote := range r.s.votes {
+		task, ok := r.s.liveTask(vote.TaskID)
+		if ok && task.ProjectID == projectID && vote.IsActive {
+			counts.OpenVotes++
+		}
+	}
+
+	return &counts, nil
+}
+
+// ListMemberActivity メンバーごとの提出作品数と最後の提出・活動日時を集計
+func (r *projectRepository) ListMemberActivity(ctx context.Context, projectID uint) ([]repository.ProjectMemberActivity, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	byUser := map[uint]*repository.ProjectMemberActivity{}
+	for key := range r.s.members {
+		if key.a == projectID {
+			byUser[key.b] = &repository.ProjectMemberActivity{UserID: key.b}
+		}
+	}
+	for key, taskWork := range r.s.taskWorks {
+		task, ok := r.s.liveTask(key.a)
+		if !ok || task.ProjectID != projectID {
+			continue
+		}
+		work, ok := r.s.liveWork(key.b)
+		if !ok || byUser[work.UserID] == nil {
+			continue
+		}
+		activity := byUser[work.UserID]
+		activity.Submissions++
+		if activity.LastSubmittedAt == nil || taskWork.CreatedAt.After(*activity.LastSubmittedAt) {
+			createdAt := taskWork.CreatedAt
+			activity.LastSubmittedAt = &createdAt
+		}
+	}
+	for _, a := range r.s.activities {
+		if a.ProjectID == nil || *a.ProjectID != projectID || a.UserID == nil || byUser[*a.UserID] == nil {
+			continue
+		}
+		activity := byUser[*a.UserID]
+		if activity.LastActivityAt == nil || a.CreatedAt.After(*activity.LastActivityAt) {
+			createdAt := a.CreatedAt
+			activity.LastActivityAt = &createdAt
+		}
+	}
+
+	activities := make([]repository.ProjectMemberActivity, 0, len(byUser))
+	for _, activity := range byUser {
+		activities = append(activities, *activity)
+	}
+	return activities, nil
+}
+
+// ListUpcomingVotes 締め切りが近い受付中の投票を取得
+func (r *projectRepository) ListUpcomingVotes(ctx context.Context, projectID uint, limit int) ([]models.Vote, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	now := time.Now()
+	votes := []models.Vote{}
+	for _, vote := range r.s.votes {
+		task, ok := r.s.liveTask(vote.TaskID)
+		if !ok || task.ProjectID != projectID || !vote.IsActive || vote.ClosesAt == nil || !vote.ClosesAt.After(now) {
+			continue
+		}
+		votes = append(votes, vote)
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		if !votes[i].ClosesAt.Equal(*votes[j].ClosesAt) {
+			return votes[i].ClosesAt.Before(*votes[j].ClosesAt)
+		}
+		return votes[i].ID < votes[j].ID
+	})
+	return paginate(votes, 1, limit), nil
+}
+
+// ListScheduledVotes 締め切りが設定されている投票を全て取得（締め切りの早い順）
+func (r *projectRepository) ListScheduledVotes(ctx context.Context, projectID uint) ([]models.Vote, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	votes := []models.Vote{}
+	for _, vote := range r.s.votes {
+		task, ok := r.s.liveTask(vote.TaskID)
+		if !ok || task.ProjectID != projectID || vote.ClosesAt == nil {
+			continue
+		}
+		votes = append(votes, vote)
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		if !votes[i].ClosesAt.Equal(*votes[j].ClosesAt) {
+			return votes[i].ClosesAt.Before(*votes[j].ClosesAt)
+		}
+		return votes[i].ID < votes[j].ID
+	})
+	return votes, nil
+}
+
+// liveProject 削除されていないプロジェクトを取得（ロックを取得した状態で呼び出す）
+func (s *Store) liveProject(id uint) (models.Project, bool) {
+	project, ok := s.projects[id]
+	if !ok || project.DeletedAt.Valid {
+		return models.Project{}, false
+	}
+	return project, true
+}
+
+// isOrganizationManager ユーザーがプロジェクトの所属する組織のオーナーまたは管理者かどうか確認（ロックを取得した状態で呼び出す）
+func (s *Store) isOrganizationManager(projectID, userID uint) bool {
+	project, ok := s.liveProject(projectID)
+	if !ok || project.OrganizationID == nil {
+		return false
+	}
+	member, ok := s.orgMembers[pairKey{*project.OrganizationID, userID}]
+	return ok && (member.Role == models.OrganizationRoleOwner || member.Role == models.OrganizationRoleAdmin)
+}
+
+// pageProjects プロジェクトを新しい順に並べて切り出し、オーナーを読み込む（ロックを取得した状態で呼び出す）
+func (s *Store) pageProjects(projects []models.Project, page, limit int) []models.Project {
+	sort.Slice(projects, func(i, j int) bool {
+		return newerFirst(projects[i].CreatedAt, projects[i].ID, projects[j].CreatedAt, projects[j].ID)
+	})
+	items := paginate(projects, page, limit)
+	for i := range items {
+		items[i].Owner = s.loadUser(items[i].OwnerID)
+	}
+	return items
+}
+
+// stripProject 保存用にリレーションを取り除く
+func stripProject(project models.Project) models.Project {
+	project.Owner = models.User{}
+	project.Members = nil
+	project.Tasks = nil
+	return project
+}
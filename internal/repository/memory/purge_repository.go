@@ -0,0 +1,469 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// purgeRepository PurgeRepositoryのインメモリ実装
+type purgeRepository struct {
+	s *Store
+}
+
+// NewPurgeRepository PurgeRepositoryを作成
+func NewPurgeRepository(s *Store) repository.PurgeRepository {
+	return &purgeRepository{s: s}
+}
+
+// PurgeUser ユーザーと、ユーザーの作品・コメント・メッセージなどを完全に削除し、監査ログを記録
+// オーナーのプロジェクト・他にオーナーがいない組織・主催するイベントがある場合は ErrPurgeOwner を返す
+func (r *purgeRepository) PurgeUser(ctx context.Context, userID uint, log *models.AuditLog) (*repository.PurgeResult, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	// 削除済みのプロジェクトもオーナーを参照しているため含める
+	for _, project := range r.s.projects {
+		if project.OwnerID == userID {
+			return nil, repository.ErrPurgeOwner
+		}
+	}
+	for _, event := range r.s.events {
+		if event.OrganizerID == userID {
+			return nil, repository.ErrPurgeOwner
+		}
+	}
+	for key, member := range r.s.orgMembers {
+		if key.b == userID && member.Role == models.OrganizationRoleOwner && r.s.countOrganizationOwners(key.a) <= 1 {
+			return nil, repository.ErrPurgeOwner
+		}
+	}
+
+	result := &repository.PurgeResult{Rows: map[string]int64{}}
+
+	// 作品（削除済みを含む）
+	workIDs := []uint{}
+	for id, work := range r.s.works {
+		if work.UserID == userID {
+			workIDs = append(workIDs, id)
+		}
+	}
+	sort.Slice(workIDs, func(i, j int) bool { return workIDs[i] < workIDs[j] })
+	r.purgeWorks(workIDs, result)
+
+	// 他の作品へのコメント（返信は残し、返信先を外す）
+	purged := map[uint]bool{}
+	for id, comment := range r.s.comments {
+		if comment.UserID != userID {
+			continue
+		}
+		// 削除済みのコメントは削除した時点で数を減らしている
+		if !comment.DeletedAt.Valid {
+			if comment.ParentID != nil {
+				r.s.addReplies(*comment.ParentID, -1)
+			}
+			r.s.addComments(comment.WorkID, -1)
+		}
+		purged[id] = true
+	}
+	for id, comment := range r.s.comments {
+		if purged[id] {
+			delete(r.s.comments, id)
+			result.Add("comments", 1)
+		} else if comment.ParentID != nil && purged[*comment.ParentID] {
+			comment.ParentID = nil
+			r.s.comments[id] = comment
+		}
+	}
+	for id, report := range r.s.reports {
+		if report.TargetType == models.ReportTargetComment && purged[report.TargetID] {
+			delete(r.s.reports, id)
+			result.Add("reports", 1)
+		}
+	}
+
+	// 他の作品にアップロードしたアセットとスナップショット
+	r.purgeAssets(func(workID, ownerID uint) bool { return ownerID == userID }, result)
+	for id, snapshot := range r.s.snapshots {
+		if snapshot.UserID == userID {
+			r.purgeImage(snapshot.ImageID, result)
+			delete(r.s.snapshots, id)
+			result.Add("work_snapshots", 1)
+		}
+	}
+	// アバターなど、ユーザーがアップロードした残りの画像
+	for id, image := range r.s.images {
+		if image.UserID == userID {
+			r.purgeImage(id, result)
+		}
+	}
+
+	// 作成した投票はプロジェクトのオーナーが作成したものとして残す
+	for id, vote := range r.s.votes {
+		if vote.CreatedBy != userID {
+			continue
+		}
+		if task, ok := r.s.tasks[vote.TaskID]; ok {
+			if project, ok := r.s.projects[task.ProjectID]; ok {
+				vote.CreatedBy = project.OwnerID
+				r.s.votes[id] = vote
+			}
+		}
+	}
+
+	// いいねした作品のいいね数を減らす
+	for key := range r.s.likes {
+		if key.a != userID {
+			continue
+		}
+		if work, ok := r.s.liveWork(key.b); ok && work.LikesCount > 0 {
+			work.LikesCount--
+			r.s.works[key.b] = work
+		}
+		delete(r.s.likes, key)
+		result.Add("likes", 1)
+	}
+
+	// ユーザーに関連するレコード
+	for key := range r.s.members {
+		if key.b == userID {
+			delete(r.s.members, key)
+			result.Add("project_members", 1)
+		}
+	}
+	for key := range r.s.orgMembers {
+		if key.b == userID {
+			delete(r.s.orgMembers, key)
+			result.Add("organization_members", 1)
+		}
+	}
+	if _, ok := r.s.billingSubs[userID]; ok {
+		delete(r.s.billingSubs, userID)
+		result.Add("subscriptions", 1)
+	}
+	for id, identity := range r.s.oauthIDs {
+		if identity.UserID == userID {
+			delete(r.s.oauthIDs, id)
+			result.Add("oauth_providers", 1)
+		}
+	}
+	for id, token := range r.s.refreshTokens {
+		if token.UserID == userID {
+			delete(r.s.refreshTokens, id)
+			result.Add("refresh_tokens", 1)
+		}
+	}
+	for jti, token := range r.s.revokedTokens {
+		if token.UserID == userID {
+			delete(r.s.revokedTokens, jti)
+			result.Add("revoked_tokens", 1)
+		}
+	}
+	for id, token := range r.s.personalTokens {
+		if token.UserID == userID {
+			delete(r.s.personalTokens, id)
+			result.Add("personal_tokens", 1)
+		}
+	}
+	for key := range r.s.collaborators {
+		if key.b == userID {
+			delete(r.s.collaborators, key)
+			result.Add("work_collaborators", 1)
+		}
+	}
+	for key := range r.s.attendees {
+		if key.b == userID {
+			delete(r.s.attendees, key)
+			result.Add("event_attendees", 1)
+		}
+	}
+	for id, response := range r.s.voteResponses {
+		if response.UserID == userID {
+			delete(r.s.voteResponses, id)
+			result.Add("vote_responses", 1)
+		}
+	}
+	for id, activity := range r.s.activities {
+		if activity.UserID != nil && *activity.UserID == userID {
+			delete(r.s.activities, id)
+			result.Add("activities", 1)
+		}
+	}
+	for id, notification := range r.s.notifications {
+		if notification.UserID == userID {
+			delete(r.s.notifications, id)
+			result.Add("notifications", 1)
+		}
+	}
+	for id, critique := range r.s.critiques {
+		if critique.UserID == userID {
+			delete(r.s.critiques, id)
+			result.Add("critique_scores", int64(len(critique.Scores)))
+			result.Add("critiques", 1)
+		}
+	}
+	for key := range r.s.subscriptions {
+		if key.b == userID {
+			delete(r.s.subscriptions, key)
+			result.Add("comment_subscriptions", 1)
+		}
+	}
+	for key := range r.s.participants {
+		if key.b == userID {
+			delete(r.s.participants, key)
+			result.Add("conversation_participants", 1)
+		}
+	}
+	for id, message := range r.s.messages {
+		if message.SenderID == userID {
+			delete(r.s.messages, id)
+			result.Add("messages", 1)
+		}
+	}
+	for id, log := range r.s.conversionLogs {
+		if log.UserID == userID {
+			delete(r.s.conversionLogs, id)
+			result.Add("conversion_logs", 1)
+		}
+	}
+	for id, series := range r.s.series {
+		if series.UserID == userID {
+			delete(r.s.series, id)
+			result.Add("series", 1)
+		}
+	}
+	for id, report := range r.s.reports {
+		if report.ReporterID == userID {
+			delete(r.s.reports, id)
+			result.Add("reports", 1)
+		}
+	}
+	for id, history := range r.s.handles {
+		if history.UserID == userID {
+			delete(r.s.handles, id)
+			result.Add("handle_histories", 1)
+		}
+	}
+	for id, entry := range r.s.waitlist {
+		if entry.Email == user.Email {
+			delete(r.s.waitlist, id)
+			result.Add("waitlist_entries", 1)
+		}
+	}
+	for id, code := range r.s.inviteCodes {
+		if code.Email != "" && code.Email == user.Email {
+			code.Email = ""
+			r.s.inviteCodes[id] = code
+		}
+	}
+
+	delete(r.s.users, userID)
+	result.Add("users", 1)
+
+	if err := r.s.createPurgeAuditLog(log, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PurgeWork 作品と、作品へのコメント・いいね・アセットなどを完全に削除し、監査ログを記録
+func (r *purgeRepository) PurgeWork(ctx context.Context, workID uint, log *models.AuditLog) (*repository.PurgeResult, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.works[workID]; !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	result := &repository.PurgeResult{Rows: map[string]int64{}}
+	r.purgeWorks([]uint{workID}, result)
+
+	if err := r.s.createPurgeAuditLog(log, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// purgeWorks 作品と作品に関連するレコードを削除（ロックを取得した状態で呼び出す）
+func (r *purgeRepository) purgeWorks(ids []uint, result *repository.PurgeResult) {
+	if len(ids) == 0 {
+		return
+	}
+	result.WorkIDs = append(result.WorkIDs, ids...)
+
+	purge := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		purge[id] = true
+		if work := r.s.works[id]; work.ThumbnailPublicID != "" {
+			result.ImagePublicIDs = append(result.ImagePublicIDs, work.ThumbnailPublicID)
+		}
+	}
+
+	// アセット・スナップショットの画像
+	r.purgeAssets(func(workID, ownerID uint) bool { return purge[workID] }, result)
+	for id, snapshot := range r.s.snapshots {
+		if purge[snapshot.WorkID] {
+			r.purgeImage(snapshot.ImageID, result)
+			delete(r.s.snapshots, id)
+			result.Add("work_snapshots", 1)
+		}
+	}
+
+	// 作品と作品へのコメントの通報
+	comments := map[uint]bool{}
+	for id, comment := range r.s.comments {
+		if purge[comment.WorkID] {
+			comments[id] = true
+			delete(r.s.comments, id)
+			result.Add("comments", 1)
+		}
+	}
+	for id, report := range r.s.reports {
+		if (report.TargetType == models.ReportTargetWork && purge[report.TargetID]) ||
+			(report.TargetType == models.ReportTargetComment && comments[report.TargetID]) {
+			delete(r.s.reports, id)
+			result.Add("reports", 1)
+		}
+	}
+
+	for key := range r.s.workTags {
+		if purge[key.a] {
+			delete(r.s.workTags, key)
+			result.Add("work_tags", 1)
+		}
+	}
+	for key := range r.s.likes {
+		if purge[key.b] {
+			delete(r.s.likes, key)
+			result.Add("likes", 1)
+		}
+	}
+	for key := range r.s.taskWorks {
+		if purge[key.b] {
+			delete(r.s.taskWorks, key)
+			result.Add("task_works", 1)
+		}
+	}
+	for id, badge := range r.s.badges {
+		if purge[badge.WorkID] {
+			delete(r.s.badges, id)
+			result.Add("work_badges", 1)
+		}
+	}
+	for id, annotation := range r.s.annotations {
+		if purge[annotation.WorkID] {
+			delete(r.s.annotations, id)
+			result.Add("work_annotations", 1)
+		}
+	}
+	for key := range r.s.collaborators {
+		if purge[key.a] {
+			delete(r.s.collaborators, key)
+			result.Add("work_collaborators", 1)
+		}
+	}
+	for id, job := range r.s.conversionJobs {
+		if purge[job.WorkID] {
+			delete(r.s.conversionJobs, id)
+			result.Add("conversion_jobs", 1)
+		}
+	}
+	for key := range r.s.retagJobWorks {
+		if purge[key.b] {
+			delete(r.s.retagJobWorks, key)
+			result.Add("retag_job_works", 1)
+		}
+	}
+	for id, critique := range r.s.critiques {
+		if purge[critique.WorkID] {
+			delete(r.s.critiques, id)
+			result.Add("critique_scores", int64(len(critique.Scores)))
+			result.Add("critiques", 1)
+		}
+	}
+	for key := range r.s.subscriptions {
+		if purge[key.a] {
+			delete(r.s.subscriptions, key)
+			result.Add("comment_subscriptions", 1)
+		}
+	}
+	for id := range r.s.rankings {
+		if purge[id] {
+			delete(r.s.rankings, id)
+			result.Add("work_rankings", 1)
+		}
+	}
+
+	// 投票の選択肢は履歴として残し、作品との関連のみ外す（フォーク先の作品はフォーク元を外す）
+	for id, option := range r.s.voteOptions {
+		if option.WorkID != nil && purge[*option.WorkID] {
+			option.WorkID = nil
+			r.s.voteOptions[id] = option
+		}
+	}
+	for id, work := range r.s.works {
+		if work.ForkedFromID != nil && purge[*work.ForkedFromID] {
+			work.ForkedFromID = nil
+			r.s.works[id] = work
+		}
+	}
+
+	for _, id := range ids {
+		delete(r.s.works, id)
+		result.Add("works", 1)
+	}
+}
+
+// purgeAssets matchに一致するアセットとアップロード中のデータを削除し、ストレージのキーを記録（ロックを取得した状態で呼び出す）
+func (r *purgeRepository) purgeAssets(match func(workID, ownerID uint) bool, result *repository.PurgeResult) {
+	for id, asset := range r.s.assets {
+		if !match(asset.WorkID, asset.UserID) {
+			continue
+		}
+		result.StorageKeys = append(result.StorageKeys, asset.StorageKey)
+		if asset.ImageID != nil {
+			r.purgeImage(*asset.ImageID, result)
+		}
+		delete(r.s.assets, id)
+		result.Add("work_assets", 1)
+	}
+	for id, upload := range r.s.uploads {
+		if !match(upload.WorkID, upload.UserID) {
+			continue
+		}
+		result.StorageKeys = append(result.StorageKeys, upload.StorageKey)
+		delete(r.s.uploads, id)
+		result.Add("asset_uploads", 1)
+	}
+}
+
+// purgeImage 画像を派生画像とともに削除し、Cloudinaryの画像を記録（ロックを取得した状態で呼び出す）
+func (r *purgeRepository) purgeImage(id uint, result *repository.PurgeResult) {
+	image, ok := r.s.images[id]
+	if !ok {
+		return
+	}
+	result.ImagePublicIDs = append(result.ImagePublicIDs, image.PublicID)
+	delete(r.s.images, id)
+	result.Add("images", 1)
+}
+
+// createPurgeAuditLog 削除件数を含めて監査ログを記録（ロックを取得した状態で呼び出す）
+func (s *Store) createPurgeAuditLog(log *models.AuditLog, result *repository.PurgeResult) error {
+	detail, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	log.Detail = string(detail)
+	s.createAuditLog(log)
+	return nil
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// rankingRepository RankingRepositoryのインメモリ実装
+type rankingRepository struct {
+	s *Store
+}
+
+// NewRankingRepository RankingRepositoryを作成
+func NewRankingRepository(s *Store) repository.RankingRepository {
+	return &rankingRepository{s: s}
+}
+
+// ListSources afterIDより後の公開中の作品を、スコアの計算に使う列のみID順に取得
+func (r *rankingRepository) ListSources(ctx context.Context, afterID uint, limit int) ([]models.Work, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := []models.Work{}
+	for _, work := range r.s.works {
+		if work.ID <= afterID || work.DeletedAt.Valid || work.HiddenAt != nil {
+			continue
+		}
+		works = append(works, models.Work{
+			ID:            work.ID,
+			Views:         work.Views,
+			LikesCount:    work.LikesCount,
+			CommentsCount: work.CommentsCount,
+			CreatedAt:     work.CreatedAt,
+		})
+	}
+	sort.Slice(works, func(i, j int) bool { return works[i].ID < works[j].ID })
+	if len(works) > limit {
+		works = works[:limit]
+	}
+	return works, nil
+}
+
+// Replace 人気スコアを保存し、今回計算しなかった作品（削除・非表示になった作品）のスコアを削除
+func (r *rankingRepository) Replace(ctx context.Context, rankings []models.WorkRanking, computedAt time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, ranking := range rankings {
+		r.s.rankings[ranking.WorkID] = ranking
+	}
+	for id, ranking := range r.s.rankings {
+		if ranking.ComputedAt.Before(computedAt) {
+			delete(r.s.rankings, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// reconversionRepository ReconversionRepositoryのインメモリ実装
+type reconversionRepository struct {
+	s *Store
+}
+
+// NewReconversionRepository ReconversionRepositoryを作成
+func NewReconversionRepository(s *Store) repository.ReconversionRepository {
+	return &reconversionRepository{s: s}
+}
+
+// Create 新しいキャンペーンを作成
+func (r *reconversionRepository) Create(ctx context.Context, campaign *models.ReconversionCampaign) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("reconversion_campaigns", &campaign.ID)
+	stamp(&campaign.CreatedAt, &campaign.UpdatedAt)
+	r.s.reconversions[campaign.ID] = *campaign
+	return nil
+}
+
+// FindByID IDでキャンペーンを検索
+func (r *reconversionRepository) FindByID(ctx context.Context, id uint) (*models.ReconversionCampaign, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	campaign, ok := r.s.reconversions[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &campaign, nil
+}
+
+// Update キャンペーンを更新
+func (r *reconversionRepository) Update(ctx context.Context, campaign *models.ReconversionCampaign) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("reconversion_campaigns", &campaign.ID)
+	campaign.UpdatedAt = time.Now()
+	r.s.reconversions[campaign.ID] = *campaign
+	return nil
+}
+
+// List キャンペーン一覧を取得
+func (r *reconversionRepository) List(ctx context.Context, page, limit int) ([]models.ReconversionCampaign, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	campaigns := []models.ReconversionCampaign{}
+	for _, campaign := range r.s.reconversions {
+		campaigns = append(campaigns, campaign)
+	}
+	sort.Slice(campaigns, func(i, j int) bool {
+		a, b := campaigns[i], campaigns[j]
+		return newerFirst(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+
+	return paginate(campaigns, page, limit), int64(len(campaigns)), nil
+}
+
+// ListRunning 実行中のキャンペーンを作成順に取得
+func (r *reconversionRepository) ListRunning(ctx context.Context) ([]models.ReconversionCampaign, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	campaigns := []models.ReconversionCampaign{}
+	for _, campaign := range r.s.reconversions {
+		if campaign.Status == models.ReconversionStatusRunning {
+			campaigns = append(campaigns, campaign)
+		}
+	}
+	sort.Slice(campaigns, func(i, j int) bool { return campaigns[i].ID < campaigns[j].ID })
+	return campaigns, nil
+}
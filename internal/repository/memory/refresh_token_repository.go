@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// refreshTokenRepository RefreshTokenRepositoryのインメモリ実装
+type refreshTokenRepository struct {
+	s *Store
+}
+
+// NewRefreshTokenRepository RefreshTokenRepositoryを作成
+func NewRefreshTokenRepository(s *Store) repository.RefreshTokenRepository {
+	return &refreshTokenRepository{s: s}
+}
+
+// Create リフレッシュトークンを作成
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.create(token)
+	return nil
+}
+
+// create ロックを取得済みの状態でリフレッシュトークンを作成
+func (r *refreshTokenRepository) create(token *models.RefreshToken) {
+	r.s.assignID("refresh_tokens", &token.ID)
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	r.s.refreshTokens[token.ID] = *token
+}
+
+// FindByHash トークンのハッシュでリフレッシュトークンを取得
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, token := range r.s.refreshTokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Rotate 未使用のトークンを使用済みにして新しいトークンを作成
+func (r *refreshTokenRepository) Rotate(ctx context.Context, usedID uint, next *models.RefreshToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	used, ok := r.s.refreshTokens[usedID]
+	if !ok || used.UsedAt != nil || used.RevokedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	now := time.Now()
+	used.UsedAt = &now
+	r.s.refreshTokens[usedID] = used
+
+	r.create(next)
+	return nil
+}
+
+// RevokeFamily 同じログインで発行したリフレッシュトークンを全て無効にする
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	r.revoke(func(token models.RefreshToken) bool { return token.FamilyID == familyID })
+	return nil
+}
+
+// RevokeByUser ユーザーのリフレッシュトークンを全て無効にする
+func (r *refreshTokenRepository) RevokeByUser(ctx context.Context, userID uint) error {
+	r.revoke(func(token models.RefreshToken) bool { return token.UserID == userID })
+	return nil
+}
+
+// revoke matchに一致する有効なリフレッシュトークンを無効にする
+func (r *refreshTokenRepository) revoke(match func(token models.RefreshToken) bool) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	for id, token := range r.s.refreshTokens {
+		if token.RevokedAt == nil && match(token) {
+			token.RevokedAt = &now
+			r.s.refreshTokens[id] = token
+		}
+	}
+}
+
+// DeleteExpired 指定日時より前に有効期限が切れたリフレッシュトークンを削除し、削除した件数を返す
+func (r *refreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var deleted int64
+	for id, token := range r.s.refreshTokens {
+		if token.ExpiresAt.Before(before) {
+			delete(r.s.refreshTokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// reportRepository ReportRepositoryのインメモリ実装
+type reportRepository struct {
+	s *Store
+}
+
+// NewReportRepository ReportRepositoryを作成
+func NewReportRepository(s *Store) repository.ReportRepository {
+	return &reportRepository{s: s}
+}
+
+// Create 新しい通報を作成
+func (r *reportRepository) Create(ctx context.Context, report *models.Report) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.reports {
+		if existing.TargetType == report.TargetType && existing.TargetID == report.TargetID && existing.ReporterID == report.ReporterID {
+			return errDuplicate
+		}
+	}
+	r.s.assignID("reports", &report.ID)
+	stamp(&report.CreatedAt, nil)
+	r.s.reports[report.ID] = *report
+	return nil
+}
+
+// Exists ユーザーが既に通報しているか確認
+func (r *reportRepository) Exists(ctx context.Context, targetType string, targetID, reporterID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, report := range r.s.reports {
+		if report.TargetType == targetType && report.TargetID == targetID && report.ReporterID == reporterID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CountSince 指定日時より後に通報したユーザー数を取得（sinceがnilの場合は全て）
+func (r *reportRepository) CountSince(ctx context.Context, targetType string, targetID uint, since *time.Time) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	reporters := make(map[uint]bool)
+	for _, report := range r.s.reports {
+		if report.TargetType != targetType || report.TargetID != targetID {
+			continue
+		}
+		if since != nil && !report.CreatedAt.After(*since) {
+			continue
+		}
+		reporters[report.ReporterID] = true
+	}
+	return int64(len(reporters)), nil
+}
+
+// ListByTarget 対象への通報一覧を取得（新しい順）
+func (r *reportRepository) ListByTarget(ctx context.Context, targetType string, targetID uint) ([]models.Report, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	reports := []models.Report{}
+	for _, report := range r.s.reports {
+		if report.TargetType == targetType && report.TargetID == targetID {
+			reports = append(reports, report)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return newerFirst(reports[i].CreatedAt, reports[i].ID, reports[j].CreatedAt, reports[j].ID)
+	})
+	return reports, nil
+}
+
+// Hide 対象を非表示にする（既に非表示の場合はfalseを返す）
+func (r *reportRepository) Hide(ctx context.Context, targetType string, targetID uint, hiddenAt time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	switch targetType {
+	case models.ReportTargetWork:
+		work, ok := r.s.works[targetID]
+		if !ok || work.HiddenAt != nil {
+			return false, nil
+		}
+		work.HiddenAt = &hiddenAt
+		r.s.works[targetID] = work
+	case models.ReportTargetComment:
+		comment, ok := r.s.comments[targetID]
+		if !ok || comment.HiddenAt != nil {
+			return false, nil
+		}
+		comment.HiddenAt = &hiddenAt
+		r.s.comments[targetID] = comment
+	default:
+		return false, fmt.Errorf("無効な通報の対象です: %s", targetType)
+	}
+	return true, nil
+}
+
+// Restore 対象を再表示し、管理者が確認した日時を記録
+func (r *reportRepository) Restore(ctx context.Context, targetType string, targetID uint, reviewedAt time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	switch targetType {
+	case models.ReportTargetWork:
+		if work, ok := r.s.works[targetID]; ok {
+			work.HiddenAt = nil
+			work.ReviewedAt = &reviewedAt
+			r.s.works[targetID] = work
+		}
+	case models.ReportTargetComment:
+		if comment, ok := r.s.comments[targetID]; ok {
+			comment.HiddenAt = nil
+			comment.ReviewedAt = &reviewedAt
+			r.s.comments[targetID] = comment
+		}
+	default:
+		return fmt.Errorf("無効な通報の対象です: %s", targetType)
+	}
+	return nil
+}
+
+// ListHiddenWorks 非表示の作品一覧を取得（非表示になった新しい順）
+func (r *reportRepository) ListHiddenWorks(ctx context.Context, page, limit int) ([]models.Work, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := []models.Work{}
+	for _, work := range r.s.works {
+		if !work.DeletedAt.Valid && work.HiddenAt != nil {
+			works = append(works, work)
+		}
+	}
+	sort.Slice(works, func(i, j int) bool {
+		return newerFirst(*works[i].HiddenAt, works[i].ID, *works[j].HiddenAt, works[j].ID)
+	})
+
+	items := paginate(works, page, limit)
+	for i := range items {
+		items[i] = r.s.loadWork(items[i])
+	}
+	return items, int64(len(works)), nil
+}
+
+// ListHiddenComments 非表示のコメント一覧を取得（非表示になった新しい順）
+func (r *reportRepository) ListHiddenComments(ctx context.Context, page, limit int) ([]models.Comment, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	comments := []models.Comment{}
+	for _, comment := range r.s.comments {
+		if !comment.DeletedAt.Valid && comment.HiddenAt != nil {
+			comments = append(comments, comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return newerFirst(*comments[i].HiddenAt, comments[i].ID, *comments[j].HiddenAt, comments[j].ID)
+	})
+
+	items := paginate(comments, page, limit)
+	for i := range items {
+		items[i].User = r.s.loadUser(items[i].UserID)
+	}
+	return items, int64(len(comments)), nil
+}
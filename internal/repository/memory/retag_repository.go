@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// retagRepository RetagRepositoryのインメモリ実装
+type retagRepository struct {
+	s *Store
+}
+
+// NewRetagRepository RetagRepositoryを作成
+func NewRetagRepository(s *Store) repository.RetagRepository {
+	return &retagRepository{s: s}
+}
+
+// Create 新しいジョブを作成
+func (r *retagRepository) Create(ctx context.Context, job *models.RetagJob) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("retag_jobs", &job.ID)
+	stamp(&job.CreatedAt, &job.UpdatedAt)
+	r.s.retagJobs[job.ID] = *job
+	return nil
+}
+
+// FindByID IDでジョブを検索
+func (r *retagRepository) FindByID(ctx context.Context, id uint) (*models.RetagJob, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	job, ok := r.s.retagJobs[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &job, nil
+}
+
+// Update ジョブを更新
+func (r *retagRepository) Update(ctx context.Context, job *models.RetagJob) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("retag_jobs", &job.ID)
+	job.UpdatedAt = time.Now()
+	r.s.retagJobs[job.ID] = *job
+	return nil
+}
+
+// List ジョブ一覧を新しい順に取得
+func (r *retagRepository) List(ctx context.Context, page, limit int) ([]models.RetagJob, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	jobs := []models.RetagJob{}
+	for _, job := range r.s.retagJobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		a, b := jobs[i], jobs[j]
+		return newerFirst(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+
+	return paginate(jobs, page, limit), int64(len(jobs)), nil
+}
+
+// ListRunning 実行中のジョブを作成順に取得
+func (r *retagRepository) ListRunning(ctx context.Context) ([]models.RetagJob, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	jobs := []models.RetagJob{}
+	for _, job := range r.s.retagJobs {
+		if job.Status == models.RetagStatusRunning {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs, nil
+}
+
+// CountTargets ジョブの検索条件に一致する作品数を取得
+func (r *retagRepository) CountTargets(ctx context.Context, job *models.RetagJob) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return int64(len(r.targets(job, 0))), nil
+}
+
+// ListTargets ジョブの検索条件に一致する作品のIDをID順に取得
+func (r *retagRepository) ListTargets(ctx context.Context, job *models.RetagJob, afterID uint, limit int) ([]uint, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	ids := r.targets(job, afterID)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+// targets ジョブの検索条件に一致するafterIDより後の作品のID（作品一覧の絞り込みと同じ条件、非表示の作品も含む）
+func (r *retagRepository) targets(job *models.RetagJob, afterID uint) []uint {
+	ids := []uint{}
+	for _, work := range r.s.works {
+		if work.DeletedAt.Valid || work.ID <= afterID {
+			continue
+		}
+		if job.Search != "" && !containsFold(work.Title, job.Search) && !containsFold(work.Description, job.Search) {
+			continue
+		}
+		if job.FilterTag != "" && !r.s.workHasTag(work.ID, job.FilterTag) {
+			continue
+		}
+		if job.License != "" && work.License != job.License {
+			continue
+		}
+		if job.Language != "" && work.Language != job.Language {
+			continue
+		}
+		if job.UserID != nil && work.UserID != *job.UserID {
+			continue
+		}
+		ids = append(ids, work.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// CountChanged ジョブでタグを変更した作品数を取得
+func (r *retagRepository) CountChanged(ctx context.Context, jobID uint) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for key := range r.s.retagJobWorks {
+		if key.a == jobID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListChanged ジョブでタグを変更した作品のIDをID順に取得
+func (r *retagRepository) ListChanged(ctx context.Context, jobID, afterID uint, limit int) ([]uint, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	ids := []uint{}
+	for key := range r.s.retagJobWorks {
+		if key.a == jobID && key.b > afterID {
+			ids = append(ids, key.b)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+// ApplyBatch 作品にジョブのタグを追加・削除し、実際に変更した作品を記録して返す
+func (r *retagRepository) ApplyBatch(ctx context.Context, job *models.RetagJob, workIDs []uint) ([]uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var changed []uint
+	for _, workID := range workIDs {
+		key := pairKey{workID, job.TagID}
+		_, hasTag := r.s.workTags[key]
+		if hasTag != (job.Action == models.RetagActionRemove) {
+			continue
+		}
+		if job.Action == models.RetagActionAdd {
+			r.s.workTags[key] = struct{}{}
+		} else {
+			delete(r.s.workTags, key)
+		}
+		r.s.retagJobWorks[pairKey{job.ID, workID}] = struct{}{}
+		changed = append(changed, workID)
+	}
+	return changed, nil
+}
@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// revokedTokenRepository RevokedTokenRepositoryのインメモリ実装
+type revokedTokenRepository struct {
+	s *Store
+}
+
+// NewRevokedTokenRepository RevokedTokenRepositoryを作成
+func NewRevokedTokenRepository(s *Store) repository.RevokedTokenRepository {
+	return &revokedTokenRepository{s: s}
+}
+
+// Create 無効にしたトークンを記録（既に記録されている場合は何もしない）
+func (r *revokedTokenRepository) Create(ctx context.Context, token *models.RevokedToken) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.revokedTokens[token.JTI]; ok {
+		return nil
+	}
+	r.s.assignID("revoked_tokens", &token.ID)
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	r.s.revokedTokens[token.JTI] = *token
+	return nil
+}
+
+// Exists トークンが無効にされているか確認
+func (r *revokedTokenRepository) Exists(ctx context.Context, jti string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	_, ok := r.s.revokedTokens[jti]
+	return ok, nil
+}
+
+// DeleteExpired 指定日時より前に有効期限が切れたトークンの記録を削除し、削除した件数を返す
+func (r *revokedTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var deleted int64
+	for jti, token := range r.s.revokedTokens {
+		if token.ExpiresAt.Before(before) {
+			delete(r.s.revokedTokens, jti)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
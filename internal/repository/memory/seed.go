@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// デモデータのログイン情報
+const (
+	DemoUserEmail  = "demo@example.com"
+	DemoAdminEmail = "admin@example.com"
+	DemoPassword   = "password"
+)
+
+// demoSketch デモ用のスケッチ
+type demoSketch struct {
+	title       string
+	description string
+	pde         string
+	js          string
+	tags        []string
+	license     string
+}
+
+var demoSketches = []demoSketch{
+	{
+		title:       "赤い円",
+		description: "画面中央に赤い円を描くだけのシンプルなスケッチです。",
+		pde:         "void setup() {\n  size(400, 400);\n}\n\nvoid draw() {\n  background(255);\n  fill(255, 0, 0);\n  ellipse(200, 200, 100, 100);\n}\n",
+		js:          "function setup() {\n  createCanvas(400, 400);\n}\n\nfunction draw() {\n  background(255);\n  fill(255, 0, 0);\n  ellipse(200, 200, 100, 100);\n}\n",
+		tags:        []string{"basic"},
+		license:     models.WorkLicenseCC0,
+	},
+	{
+		title:       "マウスの軌跡",
+		description: "マウスを動かすと円が追いかけてきます。",
+		pde:         "void setup() {\n  size(400, 400);\n  noStroke();\n}\n\nvoid draw() {\n  fill(255, 20);\n  rect(0, 0, width, height);\n  fill(0, 120, 255);\n  ellipse(mouseX, mouseY, 30, 30);\n}\n",
+		js:          "function setup() {\n  createCanvas(400, 400);\n  noStroke();\n}\n\nfunction draw() {\n  fill(255, 20);\n  rect(0, 0, width, height);\n  fill(0, 120, 255);\n  ellipse(mouseX, mouseY, 30, 30);\n}\n",
+		tags:        []string{"interactive"},
+		license:     models.WorkLicenseCCBY,
+	},
+	{
+		title:       "回転する四角形",
+		description: "フレームごとに少しずつ回転する四角形のアニメーションです。",
+		pde:         "float angle = 0;\n\nvoid setup() {\n  size(400, 400);\n  rectMode(CENTER);\n}\n\nvoid draw() {\n  background(30);\n  translate(width / 2, height / 2);\n  rotate(angle);\n  fill(255, 200, 0);\n  rect(0, 0, 120, 120);\n  angle += 0.02;\n}\n",
+		js:          "let angle = 0;\n\nfunction setup() {\n  createCanvas(400, 400);\n  rectMode(CENTER);\n}\n\nfunction draw() {\n  background(30);\n  translate(width / 2, height / 2);\n  rotate(angle);\n  fill(255, 200, 0);\n  rect(0, 0, 120, 120);\n  angle += 0.02;\n}\n",
+		tags:        []string{"animation", "basic"},
+		license:     models.WorkLicenseMIT,
+	},
+}
+
+// Seed フロントエンド開発用のデモデータを登録
+// ユーザー・作品・いいね・コメント・プロジェクト・タスク・投票・通知をひととおり用意する
+func Seed(ctx context.Context, s *Store) error {
+	users := NewUserRepository(s)
+	works := NewWorkRepository(s)
+	tags := NewTagRepository(s)
+	comments := NewCommentRepository(s)
+	projects := NewProjectRepository(s)
+	tasks := NewTaskRepository(s)
+	votes := NewVoteRepository(s)
+	notifications := NewNotificationRepository(s)
+
+	// ユーザー
+	hashed, err := bcrypt.GenerateFromPassword([]byte(DemoPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	demoHandle, adminHandle := "demo", "demo_admin"
+	demo := &models.User{
+		Email:    DemoUserEmail,
+		Password: string(hashed),
+		Name:     "デモユーザー",
+		Nickname: "demo",
+		Handle:   &demoHandle,
+		Bio:      "デモモード用のユーザーです。",
+	}
+	admin := &models.User{
+		Email:    DemoAdminEmail,
+		Password: string(hashed),
+		Name:     "デモ管理者",
+		Nickname: "admin",
+		Handle:   &adminHandle,
+		Role:     models.UserRoleAdmin,
+	}
+	for _, user := range []*models.User{demo, admin} {
+		if err := users.Create(ctx, user); err != nil {
+			return err
+		}
+	}
+
+	// 作品とタグ
+	created := make([]*models.Work, 0, len(demoSketches))
+	for i, sketch := range demoSketches {
+		work := &models.Work{
+			Title:              sketch.title,
+			Description:        sketch.description,
+			DescriptionHTML:    "<p>" + sketch.description + "</p>",
+			PDEContent:         sketch.pde,
+			JSContent:          sketch.js,
+			JSValidationStatus: "passed",
+			CodeShared:         true,
+			License:            sketch.license,
+			Language:           utils.DetectLanguage(sketch.title + "\n" + sketch.description),
+			Views:              (len(demoSketches) - i) * 10,
+			UserID:             demo.ID,
+		}
+		if err := works.Create(ctx, work); err != nil {
+			return err
+		}
+
+		tagIDs := make([]uint, 0, len(sketch.tags))
+		for _, name := range sketch.tags {
+			tag, err := tags.FindOrCreate(ctx, name)
+			if err != nil {
+				return err
+			}
+			tagIDs = append(tagIDs, tag.ID)
+		}
+		if err := tags.AttachTagsToWork(ctx, work.ID, tagIDs); err != nil {
+			return err
+		}
+		created = append(created, work)
+	}
+
+	// いいねとコメント
+	if err := works.AddLike(ctx, admin.ID, created[0].ID); err != nil {
+		return err
+	}
+	if err := comments.Create(ctx, &models.Comment{
+		Content: "シンプルでわかりやすいですね！",
+		WorkID:  created[0].ID,
+		UserID:  admin.ID,
+	}); err != nil {
+		return err
+	}
+
+	// プロジェクト・タスク・投票
+	project := &models.Project{
+		Title:           "デモプロジェクト",
+		Description:     "作品を提出して投票するデモ用のプロジェクトです。",
+		DescriptionHTML: "<p>作品を提出して投票するデモ用のプロジェクトです。</p>",
+		InvitationCode:  "DEMO0000",
+		OwnerID:         demo.ID,
+	}
+	if err := projects.Create(ctx, project); err != nil {
+		return err
+	}
+	if err := projects.AddMember(ctx, project.ID, demo.ID, true); err != nil {
+		return err
+	}
+	if err := projects.AddMember(ctx, project.ID, admin.ID, false); err != nil {
+		return err
+	}
+
+	task := &models.Task{
+		Title:       "図形を動かしてみよう",
+		Description: "図形を使ったアニメーションを提出してください。",
+		ProjectID:   project.ID,
+	}
+	if err := tasks.Create(ctx, task); err != nil {
+		return err
+	}
+	for _, work := range created[1:] {
+		if err := tasks.AddWork(ctx, task.ID, work.ID); err != nil {
+			return err
+		}
+	}
+
+	closesAt := time.Now().AddDate(0, 0, 7)
+	vote := &models.Vote{
+		Title:     "お気に入りの作品",
+		TaskID:    task.ID,
+		IsActive:  true,
+		CreatedBy: demo.ID,
+		ClosesAt:  &closesAt,
+	}
+	if err := votes.Create(ctx, vote); err != nil {
+		return err
+	}
+	for _, work := range created[1:] {
+		workID := work.ID
+		if err := votes.CreateOption(ctx, &models.VoteOption{
+			VoteID:     vote.ID,
+			OptionText: work.Title,
+			WorkID:     &workID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// 通知
+	return notifications.Create(ctx, &models.Notification{
+		UserID:  demo.ID,
+		Type:    "demo",
+		Title:   "デモモードへようこそ",
+		Message: "このサーバーのデータはメモリ上にあり、再起動すると初期状態に戻ります。",
+	})
+}
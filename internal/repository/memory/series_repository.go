@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// seriesRepository SeriesRepositoryのインメモリ実装
+type seriesRepository struct {
+	s *Store
+}
+
+// NewSeriesRepository SeriesRepositoryを作成
+func NewSeriesRepository(s *Store) repository.SeriesRepository {
+	return &seriesRepository{s: s}
+}
+
+// Create 新しいシリーズを作成
+func (r *seriesRepository) Create(ctx context.Context, series *models.Series) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("series", &series.ID)
+	stamp(&series.CreatedAt, &series.UpdatedAt)
+	r.s.series[series.ID] = stripSeries(*series)
+	return nil
+}
+
+// FindByID IDでシリーズを検索
+func (r *seriesRepository) FindByID(ctx context.Context, id uint) (*models.Series, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	series, ok := r.s.series[id]
+	if !ok || series.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	series.User = r.s.loadUser(series.UserID)
+	return &series, nil
+}
+
+// Update シリーズを更新
+func (r *seriesRepository) Update(ctx context.Context, series *models.Series) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	series.UpdatedAt = time.Now()
+	r.s.series[series.ID] = stripSeries(*series)
+	return nil
+}
+
+// Delete シリーズを削除し、作品との関連を外す
+func (r *seriesRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	series, ok := r.s.series[id]
+	if !ok || series.DeletedAt.Valid {
+		return nil
+	}
+	r.s.clearSeriesWorks(id)
+	series.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.s.series[id] = series
+	return nil
+}
+
+// ListWorks シリーズの作品を順番通りに取得
+func (r *seriesRepository) ListWorks(ctx context.Context, seriesID uint) ([]models.Work, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := []models.Work{}
+	for _, work := range r.s.works {
+		if work.DeletedAt.Valid || work.SeriesID == nil || *work.SeriesID != seriesID {
+			continue
+		}
+		works = append(works, r.s.loadWork(work))
+	}
+	sort.Slice(works, func(i, j int) bool {
+		if works[i].SeriesPosition != works[j].SeriesPosition {
+			return works[i].SeriesPosition < works[j].SeriesPosition
+		}
+		return works[i].ID < works[j].ID
+	})
+	return works, nil
+}
+
+// SetWorks シリーズの作品を指定した順番に置き換える
+func (r *seriesRepository) SetWorks(ctx context.Context, seriesID uint, workIDs []uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.clearSeriesWorks(seriesID)
+	for i, workID := range workIDs {
+		work, ok := r.s.liveWork(workID)
+		if !ok {
+			continue
+		}
+		id := seriesID
+		work.SeriesID = &id
+		work.SeriesPosition = i + 1
+		r.s.works[workID] = work
+	}
+	return nil
+}
+
+// clearSeriesWorks 削除済みの作品も含めてシリーズとの関連を外す（ロックを取得した状態で呼び出す）
+func (s *Store) clearSeriesWorks(seriesID uint) {
+	for id, work := range s.works {
+		if work.SeriesID != nil && *work.SeriesID == seriesID {
+			work.SeriesID = nil
+			work.SeriesPosition = 0
+			s.works[id] = work
+		}
+	}
+}
+
+// stripSeries 保存用にリレーションを取り除く
+func stripSeries(series models.Series) models.Series {
+	series.User = models.User{}
+	series.Works = nil
+	return series
+}
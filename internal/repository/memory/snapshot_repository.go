@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// snapshotRepository SnapshotRepositoryのインメモリ実装
+type snapshotRepository struct {
+	s *Store
+}
+
+// NewSnapshotRepository SnapshotRepositoryを作成
+func NewSnapshotRepository(s *Store) repository.SnapshotRepository {
+	return &snapshotRepository{s: s}
+}
+
+// Create スナップショットを作成（画像は作成済みのものを関連付ける）
+func (r *snapshotRepository) Create(ctx context.Context, snapshot *models.WorkSnapshot) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("work_snapshots", &snapshot.ID)
+	stamp(&snapshot.CreatedAt, nil)
+	stored := *snapshot
+	stored.Image = models.Image{}
+	stored.User = models.User{}
+	r.s.snapshots[snapshot.ID] = stored
+	return nil
+}
+
+// FindByID IDでスナップショットを検索（画像と派生画像を含む）
+func (r *snapshotRepository) FindByID(ctx context.Context, id uint) (*models.WorkSnapshot, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	snapshot, ok := r.s.snapshots[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	snapshot = r.s.loadSnapshot(snapshot)
+	return &snapshot, nil
+}
+
+// ListByWork 作品のスナップショットを新しい順に取得
+func (r *snapshotRepository) ListByWork(ctx context.Context, workID uint) ([]models.WorkSnapshot, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	snapshots := []models.WorkSnapshot{}
+	for _, snapshot := range r.s.snapshots {
+		if snapshot.WorkID == workID {
+			snapshots = append(snapshots, r.s.loadSnapshot(snapshot))
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		a, b := snapshots[i], snapshots[j]
+		return newerFirst(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+	return snapshots, nil
+}
+
+// CountByWork 作品のスナップショットの数を取得
+func (r *snapshotRepository) CountByWork(ctx context.Context, workID uint) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for _, snapshot := range r.s.snapshots {
+		if snapshot.WorkID == workID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Delete スナップショットを削除（画像は呼び出し側で削除する）
+func (r *snapshotRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.snapshots, id)
+	return nil
+}
+
+// loadSnapshot スナップショットに画像とユーザーを読み込む
+func (s *Store) loadSnapshot(snapshot models.WorkSnapshot) models.WorkSnapshot {
+	if image, ok := s.images[snapshot.ImageID]; ok {
+		image.Variants = append([]models.ImageVariant{}, image.Variants...)
+		snapshot.Image = image
+	}
+	snapshot.User = s.loadUser(snapshot.UserID)
+	return snapshot
+}
@@ -0,0 +1,289 @@
+// Package memory リポジトリのインメモリ実装（デモモードやMySQLなしでの開発用）
+package memory
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// errDuplicate 一意制約に違反した場合のエラー
+var errDuplicate = errors.New("既に登録されています")
+
+// pairKey 複合主キー（中間テーブル用）
+type pairKey struct {
+	a, b uint
+}
+
+// Store インメモリリポジトリが共有するデータ
+// 複数のテーブルにまたがる操作を一貫させるため、全てのリポジトリで1つのロックを使う
+type Store struct {
+	mu sync.RWMutex
+
+	users          map[uint]models.User
+	tags           map[uint]models.Tag
+	works          map[uint]models.Work
+	workTags       map[pairKey]struct{}    // 作品ID, タグID
+	likes          map[pairKey]models.Like // ユーザーID, 作品ID
+	comments       map[uint]models.Comment
+	projects       map[uint]models.Project
+	members        map[pairKey]models.ProjectMember // プロジェクトID, ユーザーID
+	tasks          map[uint]models.Task
+	taskWorks      map[pairKey]models.TaskWork // タスクID, 作品ID
+	votes          map[uint]models.Vote
+	voteOptions    map[uint]models.VoteOption
+	voteResponses  map[uint]models.VoteResponse
+	activities     map[uint]models.Activity
+	notifications  map[uint]models.Notification
+	badges         map[uint]models.WorkBadge
+	conversations  map[uint]models.Conversation
+	participants   map[pairKey]models.ConversationParticipant // 会話ID, ユーザーID
+	messages       map[uint]models.Message
+	conversionLogs map[uint]models.ConversionLog
+	conversionJobs map[uint]models.ConversionJob
+	invocationLogs map[uint]models.InvocationLog
+	reconversions  map[uint]models.ReconversionCampaign
+	images         map[uint]models.Image // 派生画像を含めて保存する
+	assets         map[uint]models.WorkAsset
+	uploads        map[string]models.AssetUpload
+	annotations    map[uint]models.WorkAnnotation
+	series         map[uint]models.Series
+	reports        map[uint]models.Report
+	ipBlocks       map[uint]models.IPBlock
+	handles        map[uint]models.HandleHistory
+	events         map[uint]models.Event
+	attendees      map[pairKey]models.EventAttendee    // イベントID, ユーザーID
+	collaborators  map[pairKey]models.WorkCollaborator // 作品ID, ユーザーID
+	snapshots      map[uint]models.WorkSnapshot
+	inviteCodes    map[uint]models.InviteCode
+	waitlist       map[uint]models.WaitlistEntry
+	retagJobs      map[uint]models.RetagJob
+	retagJobWorks  map[pairKey]struct{} // ジョブID, 作品ID
+	auditLogs      map[uint]models.AuditLog
+	subscriptions  map[pairKey]models.CommentSubscription // 作品ID, ユーザーID
+	criteria       map[uint]models.CritiqueCriterion
+	critiques      map[uint]models.Critique    // 点数を含めて保存する
+	rankings       map[uint]models.WorkRanking // 作品ID
+	organizations  map[uint]models.Organization
+	orgMembers     map[pairKey]models.OrganizationMember // 組織ID, ユーザーID
+	billingSubs    map[uint]models.Subscription          // ユーザーID（有料プランの契約）
+	oauthIDs       map[uint]models.OAuthProvider
+	linkPreviews   map[string]models.LinkPreview // URLのハッシュ
+	refreshTokens  map[uint]models.RefreshToken
+	revokedTokens  map[string]models.RevokedToken // jti
+	personalTokens map[uint]models.PersonalToken
+
+	// テーブルごとの最後に採番したID
+	lastIDs map[string]uint
+}
+
+// NewStore 空のStoreを作成
+func NewStore() *Store {
+	return &Store{
+		users:          make(map[uint]models.User),
+		tags:           make(map[uint]models.Tag),
+		works:          make(map[uint]models.Work),
+		workTags:       make(map[pairKey]struct{}),
+		likes:          make(map[pairKey]models.Like),
+		comments:       make(map[uint]models.Comment),
+		projects:       make(map[uint]models.Project),
+		members:        make(map[pairKey]models.ProjectMember),
+		tasks:          make(map[uint]models.Task),
+		taskWorks:      make(map[pairKey]models.TaskWork),
+		votes:          make(map[uint]models.Vote),
+		voteOptions:    make(map[uint]models.VoteOption),
+		voteResponses:  make(map[uint]models.VoteResponse),
+		activities:     make(map[uint]models.Activity),
+		notifications:  make(map[uint]models.Notification),
+		badges:         make(map[uint]models.WorkBadge),
+		conversations:  make(map[uint]models.Conversation),
+		participants:   make(map[pairKey]models.ConversationParticipant),
+		messages:       make(map[uint]models.Message),
+		conversionLogs: make(map[uint]models.ConversionLog),
+		conversionJobs: make(map[uint]models.ConversionJob),
+		invocationLogs: make(map[uint]models.InvocationLog),
+		reconversions:  make(map[uint]models.ReconversionCampaign),
+		images:         make(map[uint]models.Image),
+		assets:         make(map[uint]models.WorkAsset),
+		uploads:        make(map[string]models.AssetUpload),
+		annotations:    make(map[uint]models.WorkAnnotation),
+		series:         make(map[uint]models.Series),
+		reports:        make(map[uint]models.Report),
+		ipBlocks:       make(map[uint]models.IPBlock),
+		handles:        make(map[uint]models.HandleHistory),
+		events:         make(map[uint]models.Event),
+		attendees:      make(map[pairKey]models.EventAttendee),
+		collaborators:  make(map[pairKey]models.WorkCollaborator),
+		snapshots:      make(map[uint]models.WorkSnapshot),
+		inviteCodes:    make(map[uint]models.InviteCode),
+		waitlist:       make(map[uint]models.WaitlistEntry),
+		retagJobs:      make(map[uint]models.RetagJob),
+		retagJobWorks:  make(map[pairKey]struct{}),
+		auditLogs:      make(map[uint]models.AuditLog),
+		subscriptions:  make(map[pairKey]models.CommentSubscription),
+		criteria:       make(map[uint]models.CritiqueCriterion),
+		critiques:      make(map[uint]models.Critique),
+		rankings:       make(map[uint]models.WorkRanking),
+		organizations:  make(map[uint]models.Organization),
+		orgMembers:     make(map[pairKey]models.OrganizationMember),
+		billingSubs:    make(map[uint]models.Subscription),
+		oauthIDs:       make(map[uint]models.OAuthProvider),
+		linkPreviews:   make(map[string]models.LinkPreview),
+		refreshTokens:  make(map[uint]models.RefreshToken),
+		revokedTokens:  make(map[string]models.RevokedToken),
+		personalTokens: make(map[uint]models.PersonalToken),
+		lastIDs:        make(map[string]uint),
+	}
+}
+
+// assignID IDが未設定の場合は採番する（指定済みの場合は以降の採番をそれより後にする）
+func (s *Store) assignID(table string, id *uint) {
+	if *id == 0 {
+		s.lastIDs[table]++
+		*id = s.lastIDs[table]
+		return
+	}
+	if *id > s.lastIDs[table] {
+		s.lastIDs[table] = *id
+	}
+}
+
+// stamp 作成日時・更新日時が未設定の場合は現在日時を設定
+func stamp(createdAt, updatedAt *time.Time) {
+	now := time.Now()
+	if createdAt != nil && createdAt.IsZero() {
+		*createdAt = now
+	}
+	if updatedAt != nil && updatedAt.IsZero() {
+		*updatedAt = now
+	}
+}
+
+// initVersion 楽観的ロックのバージョンが未設定の場合は1にする（DBの既定値に合わせる）
+func initVersion(version *uint) {
+	if *version == 0 {
+		*version = 1
+	}
+}
+
+// bumpVersion 保存済みのバージョンと一致するか確認し、バージョンを1つ進める
+// 一致しない場合は repository.ErrVersionConflict を返す
+func bumpVersion(stored uint, version *uint) error {
+	if stored != *version {
+		return repository.ErrVersionConflict
+	}
+	*version++
+	return nil
+}
+
+// paginate ページ番号と件数で切り出す（limitが0以下の場合は全件）
+func paginate[T any](items []T, page, limit int) []T {
+	if limit <= 0 {
+		return items
+	}
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
+// containsFold 大文字・小文字を区別せずに部分一致するか（MySQLのLIKEに合わせる）
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// newerFirst 作成日時の新しい順（同時刻はIDの大きい順）
+func newerFirst(aCreated time.Time, aID uint, bCreated time.Time, bID uint) bool {
+	if !aCreated.Equal(bCreated) {
+		return aCreated.After(bCreated)
+	}
+	return aID > bID
+}
+
+// 以下のヘルパーはロックを取得した状態で呼び出す
+
+// loadUser ユーザーを取得（存在しないか削除済みの場合はゼロ値）
+func (s *Store) loadUser(id uint) models.User {
+	user, ok := s.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return models.User{}
+	}
+	return user
+}
+
+// loadWork 作品にユーザーとタグを読み込み、ピックアップ中かどうかを設定する
+func (s *Store) loadWork(work models.Work) models.Work {
+	work.Featured = work.IsFeaturedAt(time.Now())
+	work.User = s.loadUser(work.UserID)
+	work.Tags = s.workTagList(work.ID)
+	return work
+}
+
+// workTagList 作品に関連付けられたタグをID順に取得
+func (s *Store) workTagList(workID uint) []models.Tag {
+	tags := []models.Tag{}
+	for key := range s.workTags {
+		if key.a != workID {
+			continue
+		}
+		if tag, ok := s.tags[key.b]; ok {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].ID < tags[j].ID })
+	return tags
+}
+
+// liveWork 削除されていない作品を取得
+func (s *Store) liveWork(id uint) (models.Work, bool) {
+	work, ok := s.works[id]
+	if !ok || work.DeletedAt.Valid {
+		return models.Work{}, false
+	}
+	return work, true
+}
+
+// liveTask 削除されていないタスクを取得
+func (s *Store) liveTask(id uint) (models.Task, bool) {
+	task, ok := s.tasks[id]
+	if !ok || task.DeletedAt.Valid {
+		return models.Task{}, false
+	}
+	return task, true
+}
+
+// optionVoteCounts オプションごとの投票数を集計
+func (s *Store) optionVoteCounts(voteID uint) map[uint]int64 {
+	counts := make(map[uint]int64)
+	for _, response := range s.voteResponses {
+		if response.VoteID == voteID {
+			counts[response.OptionID]++
+		}
+	}
+	return counts
+}
+
+// loadOption 投票オプションに作品と投票数を読み込む
+func (s *Store) loadOption(option models.VoteOption, counts map[uint]int64) models.VoteOption {
+	option.Work = nil
+	if option.WorkID != nil {
+		if work, ok := s.liveWork(*option.WorkID); ok {
+			option.Work = &work
+		}
+	}
+	option.VoteCount = counts[option.ID]
+	return option
+}
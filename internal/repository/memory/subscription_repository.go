@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// subscriptionRepository SubscriptionRepositoryのインメモリ実装
+type subscriptionRepository struct {
+	s *Store
+}
+
+// NewSubscriptionRepository SubscriptionRepositoryを作成
+func NewSubscriptionRepository(s *Store) repository.SubscriptionRepository {
+	return &subscriptionRepository{s: s}
+}
+
+// FindByUser ユーザーの契約を検索
+func (r *subscriptionRepository) FindByUser(ctx context.Context, userID uint) (*models.Subscription, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	subscription, ok := r.s.billingSubs[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &subscription, nil
+}
+
+// FindByStripeCustomer Stripeの顧客IDで契約を検索
+func (r *subscriptionRepository) FindByStripeCustomer(ctx context.Context, customerID string) (*models.Subscription, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var found *models.Subscription
+	for _, subscription := range r.s.billingSubs {
+		if subscription.StripeCustomerID != customerID {
+			continue
+		}
+		if found == nil || subscription.EventAt.After(found.EventAt) {
+			subscription := subscription
+			found = &subscription
+		}
+	}
+	if found == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return found, nil
+}
+
+// Save ユーザーの契約を保存（保存済みの契約より古いイベントや、保存済みと別のサブスクリプションの終了の場合は保存せずfalse）
+func (r *subscriptionRepository) Save(ctx context.Context, subscription *models.Subscription) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if existing, ok := r.s.billingSubs[subscription.UserID]; ok {
+		if existing.EventAt.After(subscription.EventAt) {
+			return false, nil
+		}
+		if !subscription.IsActive() && existing.StripeSubscriptionID != subscription.StripeSubscriptionID {
+			return false, nil
+		}
+		subscription.CreatedAt = existing.CreatedAt
+	}
+
+	// 別のユーザーに紐付いていた同じサブスクリプションは付け替える
+	for userID, other := range r.s.billingSubs {
+		if userID != subscription.UserID && other.StripeSubscriptionID == subscription.StripeSubscriptionID {
+			delete(r.s.billingSubs, userID)
+		}
+	}
+
+	subscription.UpdatedAt = time.Now()
+	if subscription.CreatedAt.IsZero() {
+		subscription.CreatedAt = subscription.UpdatedAt
+	}
+	r.s.billingSubs[subscription.UserID] = *subscription
+	return true, nil
+}
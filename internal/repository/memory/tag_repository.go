@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// tagRepository TagRepositoryのインメモリ実装
+type tagRepository struct {
+	s *Store
+}
+
+// NewTagRepository TagRepositoryを作成
+func NewTagRepository(s *Store) repository.TagRepository {
+	return &tagRepository{s: s}
+}
+
+// FindOrCreate タグを検索または作成
+func (r *tagRepository) FindOrCreate(ctx context.Context, name string) (*models.Tag, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("タグ名は空にできません")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if tag, ok := r.s.findTag(name); ok {
+		return &tag, nil
+	}
+
+	// タグが見つからない場合は新規作成
+	tag := models.Tag{Name: name, CreatedAt: time.Now()}
+	r.s.assignID("tags", &tag.ID)
+	r.s.tags[tag.ID] = tag
+	return &tag, nil
+}
+
+// List タグ一覧を取得
+func (r *tagRepository) List(ctx context.Context, search string, limit int) ([]models.Tag, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	tags := []models.Tag{}
+	for _, tag := range r.s.tags {
+		if search == "" || containsFold(tag.Name, search) {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return paginate(tags, 1, limit), nil
+}
+
+// ListPopular 公開中の作品の多いタグから順に取得
+func (r *tagRepository) ListPopular(ctx context.Context, limit int) ([]repository.TagSummary, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	counts := make(map[uint]int64)
+	for key := range r.s.workTags {
+		if work, ok := r.s.liveWork(key.a); ok && work.HiddenAt == nil {
+			counts[key.b]++
+		}
+	}
+
+	tags := make([]repository.TagSummary, 0, len(counts))
+	for id, count := range counts {
+		if tag, ok := r.s.tags[id]; ok {
+			tags = append(tags, repository.TagSummary{ID: tag.ID, Name: tag.Name, WorksCount: count})
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].WorksCount != tags[j].WorksCount {
+			return tags[i].WorksCount > tags[j].WorksCount
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	return paginate(tags, 1, limit), nil
+}
+
+// DeleteUnused beforeより前に作成され、どの作品（削除済みの作品を含む）にも付いていないタグを削除して返す
+// protectedのタグと、取り消せる一括タグ付けジョブのタグは削除しない
+func (r *tagRepository) DeleteUnused(ctx context.Context, before time.Time, protected []string) ([]models.Tag, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	used := make(map[uint]bool)
+	for key := range r.s.workTags {
+		used[key.b] = true
+	}
+	for _, job := range r.s.retagJobs {
+		if job.UndoneByID == nil {
+			used[job.TagID] = true
+		}
+	}
+
+	tags := []models.Tag{}
+	for id, tag := range r.s.tags {
+		if used[id] || !tag.CreatedAt.Before(before) || containsTagName(protected, tag.Name) {
+			continue
+		}
+		tags = append(tags, tag)
+		delete(r.s.tags, id)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+// containsTagName タグ名が一覧に含まれるか（大文字と小文字を区別しない）
+func containsTagName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindByID IDでタグを検索
+func (r *tagRepository) FindByID(ctx context.Context, id uint) (*models.Tag, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	tag, ok := r.s.tags[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &tag, nil
+}
+
+// FindByName 名前でタグを検索
+func (r *tagRepository) FindByName(ctx context.Context, name string) (*models.Tag, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	tag, ok := r.s.findTag(name)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &tag, nil
+}
+
+// AttachTagsToWork 作品にタグを関連付け（既存の関連付けは置き換える）
+func (r *tagRepository) AttachTagsToWork(ctx context.Context, workID uint, tagIDs []uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.detachTags(workID)
+	for _, tagID := range tagIDs {
+		r.s.workTags[pairKey{workID, tagID}] = struct{}{}
+	}
+	return nil
+}
+
+// DetachTagsFromWork 作品からすべてのタグの関連付けを解除
+func (r *tagRepository) DetachTagsFromWork(ctx context.Context, workID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.detachTags(workID)
+	return nil
+}
+
+// GetTagsForWork 作品に関連付けられたタグを取得
+func (r *tagRepository) GetTagsForWork(ctx context.Context, workID uint) ([]models.Tag, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.s.workTagList(workID), nil
+}
+
+// findTag 名前でタグを検索（ロックを取得した状態で呼び出す）
+func (s *Store) findTag(name string) (models.Tag, bool) {
+	for _, tag := range s.tags {
+		if tag.Name == name {
+			return tag, true
+		}
+	}
+	return models.Tag{}, false
+}
+
+// detachTags 作品のタグの関連付けを全て解除（ロックを取得した状態で呼び出す）
+func (s *Store) detachTags(workID uint) {
+	for key := range s.workTags {
+		if key.a == workID {
+			delete(s.workTags, key)
+		}
+	}
+}
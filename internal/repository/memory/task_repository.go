@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// taskRepository TaskRepositoryのインメモリ実装
+type taskRepository struct {
+	s *Store
+}
+
+// NewTaskRepository TaskRepositoryを作成
+func NewTaskRepository(s *Store) repository.TaskRepository {
+	return &taskRepository{s: s}
+}
+
+// Create 新しいタスクを作成
+func (r *taskRepository) Create(ctx context.Context, task *models.Task) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("tasks", &task.ID)
+	stamp(&task.CreatedAt, &task.UpdatedAt)
+	initVersion(&task.Version)
+	r.s.tasks[task.ID] = stripTask(*task)
+	return nil
+}
+
+// FindByID IDでタスクを検索
+func (r *taskRepository) FindByID(ctx context.Context, id uint) (*models.Task, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	task, ok := r.s.liveTask(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &task, nil
+}
+
+// Update タスク情報を更新
+func (r *taskRepository) Update(ctx context.Context, task *models.Task) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if stored, ok := r.s.tasks[task.ID]; ok {
+		if err := bumpVersion(stored.Version, &task.Version); err != nil {
+			return err
+		}
+	}
+	r.s.assignID("tasks", &task.ID)
+	task.UpdatedAt = time.Now()
+	r.s.tasks[task.ID] = stripTask(*task)
+	return nil
+}
+
+// Delete タスクを削除
+func (r *taskRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if task, ok := r.s.liveTask(id); ok {
+		task.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		r.s.tasks[id] = task
+	}
+	return nil
+}
+
+// ListByProject プロジェクトのタスク一覧を取得
+func (r *taskRepository) ListByProject(ctx context.Context, projectID uint) ([]models.Task, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	tasks := []models.Task{}
+	for _, task := range r.s.tasks {
+		if task.ProjectID == projectID && !task.DeletedAt.Valid {
+			tasks = append(tasks, task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if a.OrderIndex != b.OrderIndex {
+			return a.OrderIndex < b.OrderIndex
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+	return tasks, nil
+}
+
+// AddWork 作品をタスクに追加
+func (r *taskRepository) AddWork(ctx context.Context, taskID, workID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := pairKey{taskID, workID}
+	if _, ok := r.s.taskWorks[key]; ok {
+		return errDuplicate
+	}
+	r.s.taskWorks[key] = models.TaskWork{TaskID: taskID, WorkID: workID, CreatedAt: time.Now()}
+	return nil
+}
+
+// RemoveWork 作品をタスクから削除
+func (r *taskRepository) RemoveWork(ctx context.Context, taskID, workID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.taskWorks, pairKey{taskID, workID})
+	return nil
+}
+
+// HasWork 作品がタスクに提出されているか確認
+func (r *taskRepository) HasWork(ctx context.Context, taskID, workID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	_, ok := r.s.taskWorks[pairKey{taskID, workID}]
+	return ok, nil
+}
+
+// GetWorks タスクの作品一覧を取得（追加された新しい順）
+func (r *taskRepository) GetWorks(ctx context.Context, taskID uint, page, limit int) ([]models.Work, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	submissions := []models.TaskWork{}
+	for key, taskWork := range r.s.taskWorks {
+		if key.a != taskID {
+			continue
+		}
+		if _, ok := r.s.liveWork(key.b); ok {
+			submissions = append(submissions, taskWork)
+		}
+	}
+	sort.Slice(submissions, func(i, j int) bool {
+		a, b := submissions[i], submissions[j]
+		return newerFirst(a.CreatedAt, a.WorkID, b.CreatedAt, b.WorkID)
+	})
+
+	works := []models.Work{}
+	for _, taskWork := range paginate(submissions, page, limit) {
+		work, _ := r.s.liveWork(taskWork.WorkID)
+		works = append(works, r.s.loadWork(work))
+	}
+	return works, int64(len(submissions)), nil
+}
+
+// UpdateOrders タスクの表示順序を更新
+func (r *taskRepository) UpdateOrders(ctx context.Context, taskIDs []uint, orderIndices []int) error {
+	if len(taskIDs) != len(orderIndices) {
+		return errors.New("タスクIDと順序インデックスの数が一致しません")
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for i, taskID := range taskIDs {
+		if task, ok := r.s.liveTask(taskID); ok {
+			task.OrderIndex = orderIndices[i]
+			task.UpdatedAt = time.Now()
+			r.s.tasks[taskID] = task
+		}
+	}
+	return nil
+}
+
+// stripTask 保存用にリレーションを取り除く
+func stripTask(task models.Task) models.Task {
+	task.Project = models.Project{}
+	task.Works = nil
+	task.Votes = nil
+	return task
+}
@@ -0,0 +1,384 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// userRepository UserRepositoryのインメモリ実装
+type userRepository struct {
+	s *Store
+}
+
+// NewUserRepository UserRepositoryを作成
+func NewUserRepository(s *Store) repository.UserRepository {
+	return &userRepository{s: s}
+}
+
+// Create 新しいユーザーを作成
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// メールアドレス・ハンドルの一意制約（削除済みのユーザーも含む）
+	for _, u := range r.s.users {
+		if u.Email == user.Email || (user.Handle != nil && u.Handle != nil && *u.Handle == *user.Handle) {
+			return errDuplicate
+		}
+	}
+
+	r.s.assignID("users", &user.ID)
+	stamp(&user.CreatedAt, &user.UpdatedAt)
+	if user.Role == "" {
+		user.Role = models.UserRoleUser
+	}
+	r.s.users[user.ID] = stripUser(*user)
+	return nil
+}
+
+// FindByID IDでユーザーを検索
+func (r *userRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	user, ok := r.s.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+// FindByEmail メールアドレスでユーザーを検索
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, user := range r.s.users {
+		if user.Email == email && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// FindByInviteTokenHash 招待トークンのハッシュで仮登録ユーザーを検索
+func (r *userRepository) FindByInviteTokenHash(ctx context.Context, tokenHash string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, user := range r.s.users {
+		if user.Pending && user.InviteTokenHash == tokenHash && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// FindByEmailTokenHash メールアドレス変更の確認トークンのハッシュでユーザーを検索
+func (r *userRepository) FindByEmailTokenHash(ctx context.Context, tokenHash string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, user := range r.s.users {
+		if user.EmailTokenHash != "" && user.EmailTokenHash == tokenHash && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// Update ユーザー情報を更新
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("users", &user.ID)
+	user.UpdatedAt = time.Now()
+	updated := stripUser(*user)
+	if stored, ok := r.s.users[user.ID]; ok {
+		updated.Reputation = stored.Reputation
+	}
+	r.s.users[user.ID] = updated
+	return nil
+}
+
+// Delete ユーザーを削除
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return nil
+	}
+	user.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.s.users[id] = user
+	return nil
+}
+
+// AddReputation レピュテーションを加算（負の値で減算、0未満にはしない）
+func (r *userRepository) AddReputation(ctx context.Context, userID uint, delta int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[userID]
+	if !ok || user.DeletedAt.Valid {
+		return nil
+	}
+	user.Reputation += delta
+	if user.Reputation < 0 {
+		user.Reputation = 0
+	}
+	r.s.users[userID] = user
+	return nil
+}
+
+// SetReputation レピュテーションを設定
+func (r *userRepository) SetReputation(ctx context.Context, userID uint, reputation int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[userID]
+	if !ok || user.DeletedAt.Valid {
+		return nil
+	}
+	user.Reputation = reputation
+	r.s.users[userID] = user
+	return nil
+}
+
+// ListByReputation レピュテーション順にユーザー一覧を取得（一覧に表示しない設定のユーザーを除く）
+func (r *userRepository) ListByReputation(ctx context.Context, page, limit int) ([]models.User, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range r.s.users {
+		if !user.DeletedAt.Valid && !user.HideFromSearch {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].Reputation != users[j].Reputation {
+			return users[i].Reputation > users[j].Reputation
+		}
+		return users[i].ID < users[j].ID
+	})
+
+	return paginate(users, page, limit), int64(len(users)), nil
+}
+
+// GetReputationSources レピュテーションの算出元を集計
+func (r *userRepository) GetReputationSources(ctx context.Context, userID uint) (*repository.ReputationSources, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var sources repository.ReputationSources
+	owned := func(workID uint) bool {
+		work, ok := r.s.liveWork(workID)
+		return ok && work.UserID == userID
+	}
+
+	// 受け取ったいいね数（自分の作品へのいいねはレピュテーションに加算しないため除く）
+	for key := range r.s.likes {
+		if key.a != userID && owned(key.b) {
+			sources.LikesReceived++
+		}
+	}
+
+	// タスクに採用された作品数
+	for key := range r.s.taskWorks {
+		if owned(key.b) {
+			sources.AcceptedSubmissions++
+		}
+	}
+
+	// 優勝バッジ数
+	for _, badge := range r.s.badges {
+		if badge.Type == models.BadgeTypeWinner && owned(badge.WorkID) {
+			sources.ContestWins++
+		}
+	}
+
+	return &sources, nil
+}
+
+// CountDailyActivity 指定日時以降に投稿した作品とコメントの数を日ごとに集計（日付の古い順）
+func (r *userRepository) CountDailyActivity(ctx context.Context, userID uint, since time.Time) ([]repository.DailyActivityCount, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	byDate := make(map[string]*repository.DailyActivityCount)
+	entry := func(createdAt time.Time) *repository.DailyActivityCount {
+		date := createdAt.Local().Format("2006-01-02")
+		if byDate[date] == nil {
+			byDate[date] = &repository.DailyActivityCount{Date: date}
+		}
+		return byDate[date]
+	}
+
+	for _, work := range r.s.works {
+		if work.UserID == userID && !work.DeletedAt.Valid && !work.CreatedAt.Before(since) {
+			entry(work.CreatedAt).Works++
+		}
+	}
+	for _, comment := range r.s.comments {
+		if comment.UserID == userID && !comment.DeletedAt.Valid && !comment.CreatedAt.Before(since) {
+			entry(comment.CreatedAt).Comments++
+		}
+	}
+
+	counts := make([]repository.DailyActivityCount, 0, len(byDate))
+	for _, c := range byDate {
+		counts = append(counts, *c)
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Date < counts[j].Date })
+	return counts, nil
+}
+
+// ListAdminIDs 管理者のユーザーID一覧を取得
+func (r *userRepository) ListAdminIDs(ctx context.Context) ([]uint, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	ids := []uint{}
+	for id, user := range r.s.users {
+		if !user.DeletedAt.Valid && user.Role == models.UserRoleAdmin {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// FindByHandle 現在のハンドルでユーザーを検索
+func (r *userRepository) FindByHandle(ctx context.Context, handle string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, user := range r.s.users {
+		if user.Handle != nil && *user.Handle == handle && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// FindByPreviousHandle 変更前のハンドルでユーザーを検索
+func (r *userRepository) FindByPreviousHandle(ctx context.Context, handle string) (*models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, history := range r.s.handles {
+		if history.Handle != handle {
+			continue
+		}
+		if user, ok := r.s.users[history.UserID]; ok && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// IsHandleTaken ハンドルが他のユーザーに使われているか（変更前のハンドルを含む、削除済みのユーザーも含む）
+func (r *userRepository) IsHandleTaken(ctx context.Context, handle string, userID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.s.handleTaken(handle, userID), nil
+}
+
+// ChangeHandle ハンドルを変更し、変更前のハンドルを履歴に残す
+// 自分の変更前のハンドルに戻す場合は履歴から取り除く
+func (r *userRepository) ChangeHandle(ctx context.Context, userID uint, handle string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[userID]
+	if !ok || user.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+	if user.Handle != nil && *user.Handle == handle {
+		return nil
+	}
+	if r.s.handleTaken(handle, userID) {
+		return errDuplicate
+	}
+
+	for id, history := range r.s.handles {
+		if history.UserID == userID && history.Handle == handle {
+			delete(r.s.handles, id)
+		}
+	}
+	if user.Handle != nil {
+		history := models.HandleHistory{UserID: userID, Handle: *user.Handle}
+		r.s.assignID("handle_histories", &history.ID)
+		stamp(&history.CreatedAt, nil)
+		r.s.handles[history.ID] = history
+	}
+
+	user.Handle = &handle
+	user.UpdatedAt = time.Now()
+	r.s.users[userID] = user
+	return nil
+}
+
+// ListWithoutHandle ハンドルが未設定のユーザーをID順に取得
+func (r *userRepository) ListWithoutHandle(ctx context.Context, limit int) ([]models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range r.s.users {
+		if user.Handle == nil && !user.DeletedAt.Valid {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return paginate(users, 1, limit), nil
+}
+
+// ListByNickname ニックネームが一致するユーザーをID順に取得（仮登録のユーザーを除く）
+func (r *userRepository) ListByNickname(ctx context.Context, nickname string, limit int) ([]models.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	users := []models.User{}
+	for _, user := range r.s.users {
+		if user.Nickname == nickname && !user.Pending && !user.DeletedAt.Valid {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return paginate(users, 1, limit), nil
+}
+
+// handleTaken ハンドルが他のユーザーに使われているか（ロックを取得した状態で呼び出す）
+func (s *Store) handleTaken(handle string, userID uint) bool {
+	for id, user := range s.users {
+		if id != userID && user.Handle != nil && *user.Handle == handle {
+			return true
+		}
+	}
+	for _, history := range s.handles {
+		if history.UserID != userID && history.Handle == handle {
+			return true
+		}
+	}
+	return false
+}
+
+// stripUser 保存用にリレーションを取り除く
+func stripUser(user models.User) models.User {
+	user.Works = nil
+	user.Likes = nil
+	user.Comments = nil
+	user.Projects = nil
+	return user
+}
@@ -0,0 +1,311 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// voteRepository VoteRepositoryのインメモリ実装
+type voteRepository struct {
+	s *Store
+}
+
+// NewVoteRepository VoteRepositoryを作成
+func NewVoteRepository(s *Store) repository.VoteRepository {
+	return &voteRepository{s: s}
+}
+
+// Create 新しい投票を作成
+func (r *voteRepository) Create(ctx context.Context, vote *models.Vote) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("votes", &vote.ID)
+	stamp(&vote.CreatedAt, &vote.UpdatedAt)
+	r.s.votes[vote.ID] = stripVote(*vote)
+	return nil
+}
+
+// FindByID IDで投票を検索
+func (r *voteRepository) FindByID(ctx context.Context, id uint) (*models.Vote, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	vote, ok := r.s.votes[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	vote = r.s.loadVote(vote)
+	return &vote, nil
+}
+
+// Update 投票情報を更新
+func (r *voteRepository) Update(ctx context.Context, vote *models.Vote) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("votes", &vote.ID)
+	vote.UpdatedAt = time.Now()
+	r.s.votes[vote.ID] = stripVote(*vote)
+	return nil
+}
+
+// Delete 投票を削除
+func (r *voteRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.votes, id)
+	return nil
+}
+
+// ListByTask タスクの投票一覧を取得
+func (r *voteRepository) ListByTask(ctx context.Context, taskID uint) ([]models.Vote, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	votes := []models.Vote{}
+	for _, vote := range r.s.votes {
+		if vote.TaskID == taskID {
+			votes = append(votes, r.s.loadVote(vote))
+		}
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		return newerFirst(votes[i].CreatedAt, votes[i].ID, votes[j].CreatedAt, votes[j].ID)
+	})
+	return votes, nil
+}
+
+// CreateOption 新しい投票オプションを作成
+func (r *voteRepository) CreateOption(ctx context.Context, option *models.VoteOption) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("vote_options", &option.ID)
+	stamp(&option.CreatedAt, nil)
+	stored := *option
+	stored.Vote = models.Vote{}
+	stored.Work = nil
+	r.s.voteOptions[option.ID] = stored
+	return nil
+}
+
+// FindOptionByID IDで投票オプションを検索
+func (r *voteRepository) FindOptionByID(ctx context.Context, id uint) (*models.VoteOption, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	option, ok := r.s.voteOptions[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	option = r.s.loadOption(option, r.s.optionVoteCounts(option.VoteID))
+	return &option, nil
+}
+
+// DeleteOption 投票オプションを削除
+func (r *voteRepository) DeleteOption(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.voteOptions, id)
+	return nil
+}
+
+// GetOptions 投票のオプション一覧を取得
+func (r *voteRepository) GetOptions(ctx context.Context, voteID uint) ([]models.VoteOption, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.s.voteOptionList(voteID), nil
+}
+
+// AddResponse 投票回答を追加（単一選択の投票では既存の回答を置き換える）
+func (r *voteRepository) AddResponse(ctx context.Context, response *models.VoteResponse) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// 投票が有効かどうか確認
+	vote, ok := r.s.votes[response.VoteID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if !vote.IsActive {
+		return errors.New("この投票は既に終了しています")
+	}
+
+	// 単一選択の投票では既存の回答を置き換える
+	response.SingleUserID = nil
+	if !vote.MultiSelect {
+		for id, existing := range r.s.voteResponses {
+			if existing.VoteID == response.VoteID && existing.UserID == response.UserID {
+				delete(r.s.voteResponses, id)
+			}
+		}
+		userID := response.UserID
+		response.SingleUserID = &userID
+	}
+
+	// 一意制約（1オプションにつき1回答）
+	for _, existing := range r.s.voteResponses {
+		if existing.VoteID == response.VoteID && existing.OptionID == response.OptionID && existing.UserID == response.UserID {
+			return repository.ErrDuplicateResponse
+		}
+	}
+
+	// 回答を追加
+	r.s.assignID("vote_responses", &response.ID)
+	stamp(&response.CreatedAt, nil)
+	stored := *response
+	stored.Vote = models.Vote{}
+	stored.Option = models.VoteOption{}
+	stored.User = models.User{}
+	r.s.voteResponses[response.ID] = stored
+	return nil
+}
+
+// RemoveResponse 投票回答を削除
+func (r *voteRepository) RemoveResponse(ctx context.Context, voteID, optionID, userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, response := range r.s.voteResponses {
+		if response.VoteID == voteID && response.OptionID == optionID && response.UserID == userID {
+			delete(r.s.voteResponses, id)
+		}
+	}
+	return nil
+}
+
+// GetUserResponses ユーザーの投票回答を取得
+func (r *voteRepository) GetUserResponses(ctx context.Context, voteID, userID uint) ([]models.VoteResponse, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	responses := []models.VoteResponse{}
+	for _, response := range r.s.voteResponses {
+		if response.VoteID == voteID && response.UserID == userID {
+			responses = append(responses, response)
+		}
+	}
+	sort.Slice(responses, func(i, j int) bool { return responses[i].ID < responses[j].ID })
+	return responses, nil
+}
+
+// GetOptionVoteCounts オプションごとの投票数を取得
+func (r *voteRepository) GetOptionVoteCounts(ctx context.Context, voteID uint) (map[uint]int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	return r.s.optionVoteCounts(voteID), nil
+}
+
+// CloseVote 受付中の投票を終了（このリクエストで終了した場合はtrue、既に終了していた場合はfalse）
+func (r *voteRepository) CloseVote(ctx context.Context, voteID uint) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	vote, ok := r.s.votes[voteID]
+	if !ok || !vote.IsActive {
+		return false, nil
+	}
+	now := time.Now()
+	vote.IsActive = false
+	vote.ClosedAt = &now
+	vote.UpdatedAt = now
+	r.s.votes[voteID] = vote
+	return true, nil
+}
+
+// ListExpired 締め切りを過ぎたが終了していない投票一覧を取得
+func (r *voteRepository) ListExpired(ctx context.Context, now time.Time) ([]models.Vote, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	votes := []models.Vote{}
+	for _, vote := range r.s.votes {
+		if vote.IsActive && vote.ClosesAt != nil && !vote.ClosesAt.After(now) {
+			votes = append(votes, vote)
+		}
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		if !votes[i].ClosesAt.Equal(*votes[j].ClosesAt) {
+			return votes[i].ClosesAt.Before(*votes[j].ClosesAt)
+		}
+		return votes[i].ID < votes[j].ID
+	})
+	return votes, nil
+}
+
+// EachResponse 投票の回答を古い順にbatchSize件ずつ読み込んでfnに渡す
+func (r *voteRepository) EachResponse(ctx context.Context, voteID uint, batchSize int, fn func(responses []models.VoteResponse) error) error {
+	r.s.mu.RLock()
+	responses := []models.VoteResponse{}
+	for _, response := range r.s.voteResponses {
+		if response.VoteID == voteID {
+			response.User = r.s.loadUser(response.UserID)
+			responses = append(responses, response)
+		}
+	}
+	r.s.mu.RUnlock()
+
+	sort.Slice(responses, func(i, j int) bool { return responses[i].ID < responses[j].ID })
+	if batchSize <= 0 {
+		batchSize = len(responses)
+	}
+
+	// fnの中で他のリポジトリを呼び出せるよう、ロックを解放してから渡す
+	for start := 0; start < len(responses); start += batchSize {
+		end := start + batchSize
+		if end > len(responses) {
+			end = len(responses)
+		}
+		if err := fn(responses[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadVote 投票に作成者とオプション（投票数を含む）を読み込む（ロックを取得した状態で呼び出す）
+func (s *Store) loadVote(vote models.Vote) models.Vote {
+	vote.Creator = s.loadUser(vote.CreatedBy)
+	vote.Options = s.voteOptionList(vote.ID)
+	for i := range vote.Options {
+		vote.Options[i].Work = nil
+	}
+	return vote
+}
+
+// voteOptionList 投票のオプションを作成順に取得（ロックを取得した状態で呼び出す）
+func (s *Store) voteOptionList(voteID uint) []models.VoteOption {
+	counts := s.optionVoteCounts(voteID)
+	options := []models.VoteOption{}
+	for _, option := range s.voteOptions {
+		if option.VoteID == voteID {
+			options = append(options, s.loadOption(option, counts))
+		}
+	}
+	sort.Slice(options, func(i, j int) bool {
+		if !options[i].CreatedAt.Equal(options[j].CreatedAt) {
+			return options[i].CreatedAt.Before(options[j].CreatedAt)
+		}
+		return options[i].ID < options[j].ID
+	})
+	return options
+}
+
+// stripVote 保存用にリレーションを取り除く
+func stripVote(vote models.Vote) models.Vote {
+	vote.Task = models.Task{}
+	vote.Creator = models.User{}
+	vote.Options = nil
+	return vote
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// waitlistRepository WaitlistRepositoryのインメモリ実装
+type waitlistRepository struct {
+	s *Store
+}
+
+// NewWaitlistRepository WaitlistRepositoryを作成
+func NewWaitlistRepository(s *Store) repository.WaitlistRepository {
+	return &waitlistRepository{s: s}
+}
+
+// Create 順番待ちに登録（既に登録されている場合は ErrAlreadyOnWaitlist）
+func (r *waitlistRepository) Create(ctx context.Context, entry *models.WaitlistEntry) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// MySQLの照合順序と同じく大文字・小文字を区別しない
+	for _, existing := range r.s.waitlist {
+		if strings.EqualFold(existing.Email, entry.Email) {
+			return repository.ErrAlreadyOnWaitlist
+		}
+	}
+	if entry.Status == "" {
+		entry.Status = models.WaitlistStatusWaiting
+	}
+	r.s.assignID("waitlist_entries", &entry.ID)
+	stamp(&entry.CreatedAt, nil)
+	r.s.waitlist[entry.ID] = *entry
+	return nil
+}
+
+// FindByID IDで順番待ちを検索
+func (r *waitlistRepository) FindByID(ctx context.Context, id uint) (*models.WaitlistEntry, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	entry, ok := r.s.waitlist[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &entry, nil
+}
+
+// List 順番待ち一覧を登録の早い順に取得（statusが空の場合は全て）
+func (r *waitlistRepository) List(ctx context.Context, status string, page, limit int) ([]models.WaitlistEntry, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	entries := []models.WaitlistEntry{}
+	for _, entry := range r.s.waitlist {
+		if status == "" || entry.Status == status {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	})
+	return paginate(entries, page, limit), int64(len(entries)), nil
+}
+
+// Update 順番待ちを更新
+func (r *waitlistRepository) Update(ctx context.Context, entry *models.WaitlistEntry) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.waitlist[entry.ID]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	r.s.waitlist[entry.ID] = *entry
+	return nil
+}
+
+// Delete 順番待ちを削除
+func (r *waitlistRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.waitlist, id)
+	return nil
+}
@@ -0,0 +1,952 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// workRepository WorkRepositoryのインメモリ実装
+type workRepository struct {
+	s *Store
+}
+
+// NewWorkRepository WorkRepositoryを作成
+func NewWorkRepository(s *Store) repository.WorkRepository {
+	return &workRepository{s: s}
+}
+
+// Create 新しい作品を作成
+func (r *workRepository) Create(ctx context.Context, work *models.Work) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.assignID("works", &work.ID)
+	stamp(&work.CreatedAt, &work.UpdatedAt)
+	initVersion(&work.Version)
+	if work.License == "" {
+		work.License = models.WorkLicenseAllRightsReserved
+	}
+	r.s.works[work.ID] = stripWork(*work)
+	return nil
+}
+
+// FindByID IDで作品を検索
+func (r *workRepository) FindByID(ctx context.Context, id uint) (*models.Work, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	work, ok := r.s.liveWork(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	work = r.s.loadWork(work)
+	work.Badges = r.s.workBadgeList(id)
+	return &work, nil
+}
+
+// FindForAttribution クレジット表示に必要な項目のみで作品を検索（削除済みも含む）
+func (r *workRepository) FindForAttribution(ctx context.Context, id uint) (*models.Work, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	stored, ok := r.s.works[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	work := models.Work{
+		ID:           stored.ID,
+		Title:        stored.Title,
+		UserID:       stored.UserID,
+		License:      stored.License,
+		ForkedFromID: stored.ForkedFromID,
+		DeletedAt:    stored.DeletedAt,
+		User:         r.s.users[stored.UserID],
+	}
+	return &work, nil
+}
+
+// Update 作品情報を更新
+// カウンターは別途更新されるため上書きしない
+func (r *workRepository) Update(ctx context.Context, work *models.Work) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.works[work.ID]
+	if ok {
+		if err := bumpVersion(stored.Version, &work.Version); err != nil {
+			return err
+		}
+	}
+	r.s.assignID("works", &work.ID)
+	work.UpdatedAt = time.Now()
+	updated := stripWork(*work)
+	if ok {
+		updated.LikesCount = stored.LikesCount
+		updated.CommentsCount = stored.CommentsCount
+	}
+	r.s.works[work.ID] = updated
+	return nil
+}
+
+// Delete 作品を削除
+func (r *workRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.softDeleteWork(id, time.Now())
+	return nil
+}
+
+// IncrementViews 閲覧数を増加
+func (r *workRepository) IncrementViews(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if work, ok := r.s.liveWork(id); ok {
+		work.Views++
+		r.s.works[id] = work
+	}
+	return nil
+}
+
+// List 作品一覧を取得
+func (r *workRepository) List(ctx context.Context, page, limit int, search, tag, license, language string, userID *uint, sort string) ([]models.Work, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	now := time.Now()
+	works := []models.Work{}
+	for _, work := range r.s.works {
+		if work.DeletedAt.Valid || work.HiddenAt != nil {
+			continue
+		}
+		// 検索条件を適用
+		if search != "" && !containsFold(work.Title, search) && !containsFold(work.Description, search) {
+			continue
+		}
+		// タグでフィルタリング
+		if tag != "" && !r.s.workHasTag(work.ID, tag) {
+			continue
+		}
+		// ライセンスでフィルタリング
+		if license != "" && work.License != license {
+			continue
+		}
+		// 言語でフィルタリング
+		if language != "" && work.Language != language {
+			continue
+		}
+		// ユーザーでフィルタリング
+		if userID != nil && work.UserID != *userID {
+			continue
+		}
+		work.Featured = work.IsFeaturedAt(now)
+		works = append(works, work)
+	}
+
+	// ソート順を適用
+	if sort == "popular" {
+		r.s.sortByRanking(works)
+	} else {
+		sortWorks(works, sort)
+	}
+
+	items := paginate(works, page, limit)
+	for i := range items {
+		items[i] = r.s.loadWork(items[i])
+	}
+	return items, int64(len(works)), nil
+}
+
+// AddLike いいねを追加し、いいね数を加算
+func (r *workRepository) AddLike(ctx context.Context, userID, workID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := pairKey{userID, workID}
+	if _, ok := r.s.likes[key]; ok {
+		return errDuplicate
+	}
+	r.s.likes[key] = models.Like{UserID: userID, WorkID: workID, CreatedAt: time.Now()}
+	if work, ok := r.s.liveWork(workID); ok {
+		work.LikesCount++
+		r.s.works[workID] = work
+	}
+	return nil
+}
+
+// RemoveLike いいねを削除し、いいね数を減算
+func (r *workRepository) RemoveLike(ctx context.Context, userID, workID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key := pairKey{userID, workID}
+	if _, ok := r.s.likes[key]; !ok {
+		return nil
+	}
+	delete(r.s.likes, key)
+	if work, ok := r.s.liveWork(workID); ok && work.LikesCount > 0 {
+		work.LikesCount--
+		r.s.works[workID] = work
+	}
+	return nil
+}
+
+// GetLikesCount いいね数を取得
+func (r *workRepository) GetLikesCount(ctx context.Context, workID uint) (int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	work, _ := r.s.liveWork(workID)
+	return int(work.LikesCount), nil
+}
+
+// HasLiked ユーザーがいいねしているか確認
+func (r *workRepository) HasLiked(ctx context.Context, userID, workID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	_, ok := r.s.likes[pairKey{userID, workID}]
+	return ok, nil
+}
+
+// ListLikedByUser ユーザーがいいねした作品をいいねの新しい順に取得（作品を読み込む、削除済み・非表示の作品を除く）
+func (r *workRepository) ListLikedByUser(ctx context.Context, userID uint, page, limit int) ([]models.Like, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	likes := []models.Like{}
+	for key, like := range r.s.likes {
+		if key.a != userID {
+			continue
+		}
+		if work, ok := r.s.liveWork(key.b); ok && work.HiddenAt == nil {
+			likes = append(likes, like)
+		}
+	}
+	sort.Slice(likes, func(i, j int) bool {
+		return newerFirst(likes[i].CreatedAt, likes[i].WorkID, likes[j].CreatedAt, likes[j].WorkID)
+	})
+
+	items := paginate(likes, page, limit)
+	for i := range items {
+		items[i].Work = r.s.loadWork(r.s.works[items[i].WorkID])
+	}
+	return items, int64(len(likes)), nil
+}
+
+// ListLikers 作品にいいねしたユーザーをいいねの新しい順に取得（ユーザーを読み込む、削除済みといいねを非公開にしているユーザーを除く）
+func (r *workRepository) ListLikers(ctx context.Context, workID uint, page, limit int) ([]models.Like, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	likes := []models.Like{}
+	for key, like := range r.s.likes {
+		if key.b != workID {
+			continue
+		}
+		if user := r.s.loadUser(key.a); user.ID != 0 && !user.HideLikes {
+			likes = append(likes, like)
+		}
+	}
+	sort.Slice(likes, func(i, j int) bool {
+		return newerFirst(likes[i].CreatedAt, likes[i].UserID, likes[j].CreatedAt, likes[j].UserID)
+	})
+
+	items := paginate(likes, page, limit)
+	for i := range items {
+		items[i].User = r.s.loadUser(items[i].UserID)
+	}
+	return items, int64(len(likes)), nil
+}
+
+// ListByUser ユーザーの作品一覧を取得
+func (r *workRepository) ListByUser(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, error) {
+	return r.List(ctx, page, limit, "", "", "", "", &userID, "newest")
+}
+
+// ListByCollaborator ユーザーが共同編集者として参加している作品一覧を取得（新しい順、承認済みのみ、非表示の作品を除く）
+func (r *workRepository) ListByCollaborator(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := []models.Work{}
+	for key, collaborator := range r.s.collaborators {
+		if key.b != userID || collaborator.AcceptedAt == nil {
+			continue
+		}
+		if work, ok := r.s.liveWork(key.a); ok && work.HiddenAt == nil {
+			works = append(works, work)
+		}
+	}
+	sortWorks(works, "newest")
+
+	items := paginate(works, page, limit)
+	for i := range items {
+		items[i] = r.s.loadWork(items[i])
+	}
+	return items, int64(len(works)), nil
+}
+
+// CountCollaborative ユーザーが作成者か共同編集者として関わった、承認済みの共同編集者がいる作品の数を取得（非表示の作品を除く）
+func (r *workRepository) CountCollaborative(ctx context.Context, userID uint) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	workIDs := make(map[uint]bool)
+	for key, collaborator := range r.s.collaborators {
+		if collaborator.AcceptedAt == nil {
+			continue
+		}
+		work, ok := r.s.liveWork(key.a)
+		if !ok || work.HiddenAt != nil {
+			continue
+		}
+		if key.b == userID || work.UserID == userID {
+			workIDs[work.ID] = true
+		}
+	}
+	return int64(len(workIDs)), nil
+}
+
+// CountOutdatedConversions 指定バージョン以外で変換された作品数を取得（コードをストレージに移した作品を除く）
+func (r *workRepository) CountOutdatedConversions(ctx context.Context, version string) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for _, work := range r.s.works {
+		if !work.DeletedAt.Valid && work.ArchivedAt == nil && work.ConverterVersion != version {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListOutdatedConversions 指定バージョン以外で変換された作品をID順に取得（コードをストレージに移した作品を除く）
+func (r *workRepository) ListOutdatedConversions(ctx context.Context, version string, afterID uint, limit int) ([]models.Work, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := []models.Work{}
+	for _, work := range r.s.works {
+		if work.DeletedAt.Valid || work.ArchivedAt != nil || work.ConverterVersion == version || work.ID <= afterID {
+			continue
+		}
+		works = append(works, models.Work{
+			ID:               work.ID,
+			PDEContent:       work.PDEContent,
+			ConverterVersion: work.ConverterVersion,
+		})
+	}
+	sort.Slice(works, func(i, j int) bool { return works[i].ID < works[j].ID })
+	return paginate(works, 1, limit), nil
+}
+
+// UpdateConversion 変換結果と検証結果のみを更新
+func (r *workRepository) UpdateConversion(ctx context.Context, work *models.Work) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.liveWork(work.ID)
+	if !ok {
+		return nil
+	}
+	stored.JSContent = work.JSContent
+	stored.ConverterVersion = work.ConverterVersion
+	stored.JSValidationStatus = work.JSValidationStatus
+	stored.JSValidationIssues = work.JSValidationIssues
+	stored.ConvertedAt = work.ConvertedAt
+	stored.ConversionMs = work.ConversionMs
+	stored.ConversionCached = work.ConversionCached
+	stored.UpdatedAt = time.Now()
+	r.s.works[work.ID] = stored
+	return nil
+}
+
+// ListArchivable beforeより前から更新されておらず、PDEとJSの合計がminSizeバイト以上の作品をID順に取得
+func (r *workRepository) ListArchivable(ctx context.Context, before time.Time, minSize int, limit int) ([]models.Work, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := []models.Work{}
+	for _, work := range r.s.works {
+		if work.DeletedAt.Valid || work.ArchivedAt != nil || !work.UpdatedAt.Before(before) {
+			continue
+		}
+		if len(work.PDEContent)+len(work.JSContent) < minSize {
+			continue
+		}
+		works = append(works, models.Work{
+			ID:         work.ID,
+			PDEContent: work.PDEContent,
+			JSContent:  work.JSContent,
+			UpdatedAt:  work.UpdatedAt,
+		})
+	}
+	sort.Slice(works, func(i, j int) bool { return works[i].ID < works[j].ID })
+	return paginate(works, 1, limit), nil
+}
+
+// Archive 作品のPDEとJSを空にしてストレージに移したことを記録（取得後に更新された場合は何もせずfalseを返す）
+func (r *workRepository) Archive(ctx context.Context, work *models.Work, archivedAt time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.liveWork(work.ID)
+	if !ok || stored.ArchivedAt != nil || !stored.UpdatedAt.Equal(work.UpdatedAt) {
+		return false, nil
+	}
+	stored.PDEContent = ""
+	stored.JSContent = ""
+	stored.ArchivedAt = &archivedAt
+	r.s.works[work.ID] = stored
+	return true, nil
+}
+
+// Rehydrate ストレージから戻したPDEとJSを書き込む（既に戻されている場合は何もせずfalseを返す）
+func (r *workRepository) Rehydrate(ctx context.Context, work *models.Work) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.works[work.ID]
+	if !ok || stored.ArchivedAt == nil {
+		return false, nil
+	}
+	stored.PDEContent = work.PDEContent
+	stored.JSContent = work.JSContent
+	stored.ArchivedAt = nil
+	r.s.works[work.ID] = stored
+	return true, nil
+}
+
+// UpdateVideo デモ動画の項目のみを更新
+func (r *workRepository) UpdateVideo(ctx context.Context, work *models.Work) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.liveWork(work.ID)
+	if !ok {
+		return nil
+	}
+	stored.VideoAssetID = work.VideoAssetID
+	stored.VideoURL = work.VideoURL
+	stored.VideoStatus = work.VideoStatus
+	stored.UpdatedAt = time.Now()
+	r.s.works[work.ID] = stored
+	return nil
+}
+
+// StorageUsedByUser ユーザーの作品が使用しているストレージ容量（バイト）を取得
+// excludeWorkIDを指定した場合はその作品を除いて集計する
+func (r *workRepository) StorageUsedByUser(ctx context.Context, userID, excludeWorkID uint) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var used int64
+	for _, work := range r.s.works {
+		if work.DeletedAt.Valid || work.UserID != userID || (excludeWorkID != 0 && work.ID == excludeWorkID) {
+			continue
+		}
+		used += int64(len(work.PDEContent) + len(work.JSContent))
+	}
+	return used, nil
+}
+
+// ReconcileCounters いいね数とコメント数を実データから再集計し、ずれていた作品数を返す
+func (r *workRepository) ReconcileCounters(ctx context.Context) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	likes := make(map[uint]int64)
+	for key := range r.s.likes {
+		likes[key.b]++
+	}
+	comments := make(map[uint]int64)
+	for _, comment := range r.s.comments {
+		if !comment.DeletedAt.Valid {
+			comments[comment.WorkID]++
+		}
+	}
+
+	var fixed int64
+	for id, work := range r.s.works {
+		if work.DeletedAt.Valid {
+			continue
+		}
+		if work.LikesCount != likes[id] || work.CommentsCount != comments[id] {
+			work.LikesCount = likes[id]
+			work.CommentsCount = comments[id]
+			r.s.works[id] = work
+			fixed++
+		}
+	}
+	return fixed, nil
+}
+
+// FindByIDs 複数のIDで作品を検索（関連データは読み込まない）
+func (r *workRepository) FindByIDs(ctx context.Context, ids []uint) ([]models.Work, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := []models.Work{}
+	for _, id := range ids {
+		if work, ok := r.s.liveWork(id); ok {
+			works = append(works, models.Work{ID: work.ID, UserID: work.UserID})
+		}
+	}
+	return works, nil
+}
+
+// ApplyBulk 複数の作品に一括操作を適用
+func (r *workRepository) ApplyBulk(ctx context.Context, workIDs []uint, changes repository.BulkWorkChanges) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	for _, workID := range workIDs {
+		if changes.Delete {
+			r.s.softDeleteWork(workID, now)
+			continue
+		}
+
+		for _, tagID := range changes.AddTagIDs {
+			r.s.workTags[pairKey{workID, tagID}] = struct{}{}
+		}
+		for _, tagID := range changes.RemoveTagIDs {
+			delete(r.s.workTags, pairKey{workID, tagID})
+		}
+
+		if changes.CodeShared != nil {
+			if work, ok := r.s.liveWork(workID); ok {
+				work.CodeShared = *changes.CodeShared
+				work.UpdatedAt = now
+				r.s.works[workID] = work
+			}
+		}
+	}
+	return nil
+}
+
+// ListDeletedByUser ユーザーがsince以降に削除した作品の一覧を取得
+func (r *workRepository) ListDeletedByUser(ctx context.Context, userID uint, since time.Time, page, limit int) ([]models.Work, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := []models.Work{}
+	for _, work := range r.s.works {
+		if work.UserID == userID && work.DeletedAt.Valid && !work.DeletedAt.Time.Before(since) {
+			works = append(works, work)
+		}
+	}
+	sort.Slice(works, func(i, j int) bool {
+		return newerFirst(works[i].DeletedAt.Time, works[i].ID, works[j].DeletedAt.Time, works[j].ID)
+	})
+
+	items := paginate(works, page, limit)
+	for i := range items {
+		items[i].Tags = r.s.workTagList(items[i].ID)
+	}
+	return items, int64(len(works)), nil
+}
+
+// FindDeletedByID 削除済みの作品をIDで検索
+func (r *workRepository) FindDeletedByID(ctx context.Context, id uint) (*models.Work, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	work, ok := r.s.works[id]
+	if !ok || !work.DeletedAt.Valid {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &work, nil
+}
+
+// Restore 削除済みの作品を復元
+func (r *workRepository) Restore(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if work, ok := r.s.works[id]; ok {
+		work.DeletedAt = gorm.DeletedAt{}
+		r.s.works[id] = work
+	}
+	return nil
+}
+
+// PurgeDeletedBefore before より前に削除された作品と関連データを完全に削除し、削除した作品数を返す
+func (r *workRepository) PurgeDeletedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := []uint{}
+	for id, work := range r.s.works {
+		if work.DeletedAt.Valid && work.DeletedAt.Time.Before(before) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	ids = paginate(ids, 1, limit)
+
+	purge := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		purge[id] = true
+	}
+
+	for key := range r.s.workTags {
+		if purge[key.a] {
+			delete(r.s.workTags, key)
+		}
+	}
+	for key := range r.s.likes {
+		if purge[key.b] {
+			delete(r.s.likes, key)
+		}
+	}
+	for id, comment := range r.s.comments {
+		if purge[comment.WorkID] {
+			delete(r.s.comments, id)
+		}
+	}
+	for key := range r.s.taskWorks {
+		if purge[key.b] {
+			delete(r.s.taskWorks, key)
+		}
+	}
+	for id, badge := range r.s.badges {
+		if purge[badge.WorkID] {
+			delete(r.s.badges, id)
+		}
+	}
+	for id, annotation := range r.s.annotations {
+		if purge[annotation.WorkID] {
+			delete(r.s.annotations, id)
+		}
+	}
+	// 投票の選択肢は履歴として残し、作品との関連のみ外す
+	for id, option := range r.s.voteOptions {
+		if option.WorkID != nil && purge[*option.WorkID] {
+			option.WorkID = nil
+			r.s.voteOptions[id] = option
+		}
+	}
+	for _, id := range ids {
+		delete(r.s.works, id)
+	}
+
+	return int64(len(ids)), nil
+}
+
+// softDeleteWork 作品を論理削除（ロックを取得した状態で呼び出す）
+func (s *Store) softDeleteWork(id uint, now time.Time) {
+	if work, ok := s.liveWork(id); ok {
+		work.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+		s.works[id] = work
+	}
+}
+
+// workHasTag 作品に指定した名前のタグが付いているか（ロックを取得した状態で呼び出す）
+func (s *Store) workHasTag(workID uint, name string) bool {
+	for key := range s.workTags {
+		if key.a != workID {
+			continue
+		}
+		if tag, ok := s.tags[key.b]; ok && tag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// workBadgeList 作品のバッジをID順に取得（ロックを取得した状態で呼び出す）
+func (s *Store) workBadgeList(workID uint) []models.WorkBadge {
+	badges := []models.WorkBadge{}
+	for _, badge := range s.badges {
+		if badge.WorkID == workID {
+			badges = append(badges, badge)
+		}
+	}
+	sort.Slice(badges, func(i, j int) bool { return badges[i].ID < badges[j].ID })
+	return badges
+}
+
+// SetFeatured ピックアップ期間を設定（fromがnilの場合はピックアップを解除）
+func (r *workRepository) SetFeatured(ctx context.Context, id uint, from, until *time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if work, ok := r.s.liveWork(id); ok {
+		work.FeaturedFrom = from
+		work.FeaturedUntil = until
+		work.UpdatedAt = time.Now()
+		r.s.works[id] = work
+	}
+	return nil
+}
+
+// ListFeatured 指定日時にピックアップ中の作品一覧を取得（ピックアップ開始の新しい順）
+func (r *workRepository) ListFeatured(ctx context.Context, now time.Time, page, limit int) ([]models.Work, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := []models.Work{}
+	for _, work := range r.s.works {
+		if !work.DeletedAt.Valid && work.HiddenAt == nil && work.IsFeaturedAt(now) {
+			works = append(works, work)
+		}
+	}
+	sort.Slice(works, func(i, j int) bool {
+		a, b := works[i], works[j]
+		if !a.FeaturedFrom.Equal(*b.FeaturedFrom) {
+			return a.FeaturedFrom.After(*b.FeaturedFrom)
+		}
+		return a.ID > b.ID
+	})
+
+	items := paginate(works, page, limit)
+	for i := range items {
+		items[i] = r.s.loadWork(items[i])
+	}
+	return items, int64(len(works)), nil
+}
+
+// ListTrending since以降にいいねの多かった作品から順に取得（非表示の作品を除く）
+func (r *workRepository) ListTrending(ctx context.Context, since time.Time, limit int) ([]models.Work, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	recent := make(map[uint]int)
+	for _, like := range r.s.likes {
+		if !like.CreatedAt.Before(since) {
+			recent[like.WorkID]++
+		}
+	}
+
+	works := []models.Work{}
+	for id := range recent {
+		if work, ok := r.s.liveWork(id); ok && work.HiddenAt == nil {
+			works = append(works, work)
+		}
+	}
+	sort.Slice(works, func(i, j int) bool {
+		a, b := works[i], works[j]
+		if recent[a.ID] != recent[b.ID] {
+			return recent[a.ID] > recent[b.ID]
+		}
+		if a.LikesCount != b.LikesCount {
+			return a.LikesCount > b.LikesCount
+		}
+		return a.ID > b.ID
+	})
+
+	items := paginate(works, 1, limit)
+	for i := range items {
+		items[i] = r.s.loadWork(items[i])
+	}
+	return items, nil
+}
+
+// ListForSitemap サイトマップに載せる作品のIDと更新日時をID順に取得（非表示の作品を除く）
+func (r *workRepository) ListForSitemap(ctx context.Context, page, limit int) ([]models.Work, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := []models.Work{}
+	for _, work := range r.s.works {
+		if !work.DeletedAt.Valid && work.HiddenAt == nil {
+			works = append(works, models.Work{ID: work.ID, UpdatedAt: work.UpdatedAt})
+		}
+	}
+	sort.Slice(works, func(i, j int) bool { return works[i].ID < works[j].ID })
+
+	return paginate(works, page, limit), int64(len(works)), nil
+}
+
+// UpdateMadeAt 制作したイベント・場所の項目のみを更新
+func (r *workRepository) UpdateMadeAt(ctx context.Context, work *models.Work) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.liveWork(work.ID)
+	if !ok {
+		return nil
+	}
+	stored.Event = work.Event
+	stored.LocationName = work.LocationName
+	stored.Latitude = work.Latitude
+	stored.Longitude = work.Longitude
+	stored.UpdatedAt = time.Now()
+	r.s.works[work.ID] = stored
+	return nil
+}
+
+// UpdateThumbnail 作品のサムネイルのみを更新（バージョンは進めない）
+func (r *workRepository) UpdateThumbnail(ctx context.Context, work *models.Work) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.liveWork(work.ID)
+	if !ok {
+		return nil
+	}
+	stored.ThumbnailURL = work.ThumbnailURL
+	stored.ThumbnailType = work.ThumbnailType
+	stored.ThumbnailBlurhash = work.ThumbnailBlurhash
+	stored.UpdatedAt = time.Now()
+	r.s.works[work.ID] = stored
+	return nil
+}
+
+// ListByEvent イベントで制作された作品一覧を取得（新しい順、非表示の作品を除く）
+func (r *workRepository) ListByEvent(ctx context.Context, event string, page, limit int) ([]models.Work, int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := []models.Work{}
+	for _, work := range r.s.works {
+		if !work.DeletedAt.Valid && work.HiddenAt == nil && work.Event == event {
+			works = append(works, work)
+		}
+	}
+	sortWorks(works, "newest")
+
+	items := paginate(works, page, limit)
+	for i := range items {
+		items[i] = r.s.loadWork(items[i])
+	}
+	return items, int64(len(works)), nil
+}
+
+// ListEvents 作品が登録されているイベントを最後に投稿された順に取得
+func (r *workRepository) ListEvents(ctx context.Context, limit int) ([]repository.WorkEventSummary, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	byEvent := make(map[string]*repository.WorkEventSummary)
+	for _, work := range r.s.works {
+		if work.DeletedAt.Valid || work.HiddenAt != nil || work.Event == "" {
+			continue
+		}
+		summary, ok := byEvent[work.Event]
+		if !ok {
+			summary = &repository.WorkEventSummary{Event: work.Event}
+			byEvent[work.Event] = summary
+		}
+		summary.WorksCount++
+		if work.CreatedAt.After(summary.LatestWorkAt) {
+			summary.LatestWorkAt = work.CreatedAt
+		}
+	}
+
+	events := make([]repository.WorkEventSummary, 0, len(byEvent))
+	for _, summary := range byEvent {
+		events = append(events, *summary)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].LatestWorkAt.After(events[j].LatestWorkAt) })
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+// ListLanguages 作品の言語を作品数の多い順に取得（言語が判定できなかった作品を除く）
+func (r *workRepository) ListLanguages(ctx context.Context) ([]repository.WorkLanguageSummary, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	counts := make(map[string]int64)
+	for _, work := range r.s.works {
+		if work.DeletedAt.Valid || work.HiddenAt != nil || work.Language == "" {
+			continue
+		}
+		counts[work.Language]++
+	}
+
+	languages := make([]repository.WorkLanguageSummary, 0, len(counts))
+	for language, count := range counts {
+		languages = append(languages, repository.WorkLanguageSummary{Language: language, WorksCount: count})
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].WorksCount != languages[j].WorksCount {
+			return languages[i].WorksCount > languages[j].WorksCount
+		}
+		return languages[i].Language < languages[j].Language
+	})
+	return languages, nil
+}
+
+// ListWithLocation 制作した場所の座標がある作品を新しい順に取得（eventを指定した場合はそのイベントのみ、非表示の作品を除く）
+func (r *workRepository) ListWithLocation(ctx context.Context, event string, limit int) ([]models.Work, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	works := []models.Work{}
+	for _, work := range r.s.works {
+		if work.DeletedAt.Valid || work.HiddenAt != nil || work.Latitude == nil || work.Longitude == nil {
+			continue
+		}
+		if event != "" && work.Event != event {
+			continue
+		}
+		works = append(works, work)
+	}
+	sortWorks(works, "newest")
+	if len(works) > limit {
+		works = works[:limit]
+	}
+
+	for i := range works {
+		works[i].User = r.s.loadUser(works[i].UserID)
+	}
+	return works, nil
+}
+
+// sortWorks 作品一覧のソート順を適用
+func sortWorks(works []models.Work, order string) {
+	sort.Slice(works, func(i, j int) bool {
+		a, b := works[i], works[j]
+		switch order {
+		case "likes":
+			if a.LikesCount != b.LikesCount {
+				return a.LikesCount > b.LikesCount
+			}
+		case "featured":
+			// ピックアップ中の作品を先頭に、それ以外は新着順
+			if a.Featured != b.Featured {
+				return a.Featured
+			}
+		}
+		return newerFirst(a.CreatedAt, a.ID, b.CreatedAt, b.ID)
+	})
+}
+
+// sortByRanking 人気スコアの順に並べる（まだ計算していない作品は新着順で後ろに並べる、ロックを取得した状態で呼び出す）
+func (s *Store) sortByRanking(works []models.Work) {
+	sort.Slice(works, func(i, j int) bool {
+		a, b := s.rankings[works[i].ID].Score, s.rankings[works[j].ID].Score
+		if a != b {
+			return a > b
+		}
+		return newerFirst(works[i].CreatedAt, works[i].ID, works[j].CreatedAt, works[j].ID)
+	})
+}
+
+// stripWork 保存用にリレーションを取り除く
+func stripWork(work models.Work) models.Work {
+	work.User = models.User{}
+	work.Tags = nil
+	work.Likes = nil
+	work.Comments = nil
+	work.Tasks = nil
+	work.Badges = nil
+	work.Attribution = nil
+	work.CoAuthors = nil
+	work.Series = nil
+	return work
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// MessageRepository ダイレクトメッセージに関するデータベース操作を行うインターフェース
+type MessageRepository interface {
+	CreateConversation(ctx context.Context, userIDs []uint) (*models.Conversation, error)
+	FindConversationByID(ctx context.Context, id uint) (*models.Conversation, error)
+	FindConversationBetween(ctx context.Context, userID, otherUserID uint) (*models.Conversation, error)
+	IsParticipant(ctx context.Context, conversationID, userID uint) (bool, error)
+	ListConversations(ctx context.Context, userID uint, page, limit int) ([]models.Conversation, int64, error)
+	CreateMessage(ctx context.Context, message *models.Message) error
+	ListMessages(ctx context.Context, conversationID uint, page, limit int) ([]models.Message, int64, error)
+	GetLastMessage(ctx context.Context, conversationID uint) (*models.Message, error)
+	CountUnread(ctx context.Context, conversationID, userID uint) (int64, error)
+	CountUnreadTotal(ctx context.Context, userID uint) (int64, error)
+	MarkAsRead(ctx context.Context, conversationID, userID uint) error
+}
+
+// messageRepository MessageRepositoryの実装
+type messageRepository struct {
+	db *gorm.DB
+}
+
+// NewMessageRepository MessageRepositoryを作成
+func NewMessageRepository(db *gorm.DB) MessageRepository {
+	return &messageRepository{db: db}
+}
+
+// CreateConversation 新しい会話を参加者とともに作成
+func (r *messageRepository) CreateConversation(ctx context.Context, userIDs []uint) (*models.Conversation, error) {
+	conversation := &models.Conversation{}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(conversation).Error; err != nil {
+			return err
+		}
+
+		for _, userID := range userIDs {
+			participant := models.ConversationParticipant{
+				ConversationID: conversation.ID,
+				UserID:         userID,
+			}
+			if err := tx.Create(&participant).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.FindConversationByID(ctx, conversation.ID)
+}
+
+// FindConversationByID IDで会話を検索
+func (r *messageRepository) FindConversationByID(ctx context.Context, id uint) (*models.Conversation, error) {
+	var conversation models.Conversation
+	if err := r.db.WithContext(ctx).Preload("Participants.User").First(&conversation, id).Error; err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// FindConversationBetween 2人のユーザー間の会話を検索
+func (r *messageRepository) FindConversationBetween(ctx context.Context, userID, otherUserID uint) (*models.Conversation, error) {
+	var conversationID uint
+	err := r.db.WithContext(ctx).Table("conversation_participants AS a").
+		Select("a.conversation_id").
+		Joins("JOIN conversation_participants AS b ON a.conversation_id = b.conversation_id").
+		Where("a.user_id = ? AND b.user_id = ?", userID, otherUserID).
+		Limit(1).
+		Scan(&conversationID).Error
+	if err != nil {
+		return nil, err
+	}
+	if conversationID == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return r.FindConversationByID(ctx, conversationID)
+}
+
+// IsParticipant ユーザーが会話の参加者かどうか確認
+func (r *messageRepository) IsParticipant(ctx context.Context, conversationID, userID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListConversations ユーザーが参加している会話一覧を取得
+func (r *messageRepository) ListConversations(ctx context.Context, userID uint, page, limit int) ([]models.Conversation, int64, error) {
+	var conversations []models.Conversation
+	var total int64
+
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&models.Conversation{}).
+		Joins("JOIN conversation_participants ON conversations.id = conversation_participants.conversation_id").
+		Where("conversation_participants.user_id = ?", userID).
+		Preload("Participants.User")
+
+	// 合計数を取得
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// データを取得
+	if err := query.Offset(offset).Limit(limit).
+		Order("conversations.last_message_at DESC, conversations.id DESC").
+		Find(&conversations).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+
+	return conversations, total, nil
+}
+
+// CreateMessage メッセージを作成し、会話の最終メッセージ日時を更新
+func (r *messageRepository) CreateMessage(ctx context.Context, message *models.Message) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Conversation{}).
+			Where("id = ?", message.ConversationID).
+			Update("last_message_at", message.CreatedAt).Error; err != nil {
+			return err
+		}
+
+		// 送信者自身は既読扱い
+		return tx.Model(&models.ConversationParticipant{}).
+			Where("conversation_id = ? AND user_id = ?", message.ConversationID, message.SenderID).
+			Update("last_read_at", message.CreatedAt).Error
+	})
+}
+
+// ListMessages 会話のメッセージ一覧を取得（新しい順）
+func (r *messageRepository) ListMessages(ctx context.Context, conversationID uint, page, limit int) ([]models.Message, int64, error) {
+	var messages []models.Message
+	var total int64
+
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&models.Message{}).
+		Where("conversation_id = ?", conversationID).
+		Preload("Sender")
+
+	// 合計数を取得
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// データを取得
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC, id DESC").
+		Find(&messages).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+
+	return messages, total, nil
+}
+
+// GetLastMessage 会話の最新メッセージを取得
+func (r *messageRepository) GetLastMessage(ctx context.Context, conversationID uint) (*models.Message, error) {
+	var message models.Message
+	if err := r.db.WithContext(ctx).Where("conversation_id = ?", conversationID).
+		Preload("Sender").
+		Order("created_at DESC, id DESC").
+		First(&message).Error; err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// CountUnread 会話内の未読メッセージ数を取得
+func (r *messageRepository) CountUnread(ctx context.Context, conversationID, userID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Message{}).
+		Joins("JOIN conversation_participants ON conversation_participants.conversation_id = messages.conversation_id AND conversation_participants.user_id = ?", userID).
+		Where("messages.conversation_id = ? AND messages.sender_id <> ?", conversationID, userID).
+		Where("conversation_participants.last_read_at IS NULL OR messages.created_at > conversation_participants.last_read_at").
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountUnreadTotal ユーザーの全会話の未読メッセージ数を取得
+func (r *messageRepository) CountUnreadTotal(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Message{}).
+		Joins("JOIN conversation_participants ON conversation_participants.conversation_id = messages.conversation_id AND conversation_participants.user_id = ?", userID).
+		Where("messages.sender_id <> ?", userID).
+		Where("conversation_participants.last_read_at IS NULL OR messages.created_at > conversation_participants.last_read_at").
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkAsRead 会話を既読にする
+func (r *messageRepository) MarkAsRead(ctx context.Context, conversationID, userID uint) error {
+	return r.db.WithContext(ctx).Model(&models.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Update("last_read_at", time.Now()).Error
+}
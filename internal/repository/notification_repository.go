@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepository 通知に関するデータベース操作を行うインターフェース
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	CreateBatch(ctx context.Context, notifications []models.Notification) error
+	ListByUser(ctx context.Context, userID uint, page, limit int, unreadOnly bool) ([]models.Notification, int64, error)
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	MarkAsRead(ctx context.Context, id, userID uint) error
+	MarkAllAsRead(ctx context.Context, userID uint) error
+}
+
+// notificationRepository NotificationRepositoryの実装
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository NotificationRepositoryを作成
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Create 新しい通知を作成
+func (r *notificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+// CreateBatch 複数の通知をまとめて作成
+func (r *notificationRepository) CreateBatch(ctx context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&notifications).Error
+}
+
+// ListByUser ユーザーの通知一覧を取得
+func (r *notificationRepository) ListByUser(ctx context.Context, userID uint, page, limit int, unreadOnly bool) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&models.Notification{}).Where("user_id = ?", userID)
+
+	// 未読のみに絞り込み
+	if unreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+
+	// 合計数を取得
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// データを取得
+	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").
+		Find(&notifications).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+// CountUnread 未読の通知数を取得
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// MarkAsRead 通知を既読にする
+func (r *notificationRepository) MarkAsRead(ctx context.Context, id, userID uint) error {
+	result := r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("is_read", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// MarkAllAsRead ユーザーの通知をすべて既読にする
+func (r *notificationRepository) MarkAllAsRead(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Update("is_read", true).Error
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// OAuthProviderRepository 外部サービスのアカウントの連携に関するデータベース操作を行うインターフェース
+type OAuthProviderRepository interface {
+	Find(ctx context.Context, provider, providerUserID string) (*models.OAuthProvider, error)
+	ListByUser(ctx context.Context, userID uint) ([]models.OAuthProvider, error)
+	Create(ctx context.Context, identity *models.OAuthProvider) error
+	Update(ctx context.Context, identity *models.OAuthProvider) error
+	Delete(ctx context.Context, userID uint, provider string) error
+}
+
+// oauthProviderRepository OAuthProviderRepositoryの実装
+type oauthProviderRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthProviderRepository OAuthProviderRepositoryを作成
+func NewOAuthProviderRepository(db *gorm.DB) OAuthProviderRepository {
+	return &oauthProviderRepository{db: db}
+}
+
+// Find プロバイダーでのユーザーIDから連携を検索
+func (r *oauthProviderRepository) Find(ctx context.Context, provider, providerUserID string) (*models.OAuthProvider, error) {
+	var identity models.OAuthProvider
+	if err := r.db.WithContext(ctx).Where("provider = ? AND provider_user_id = ?", provider, providerUserID).
+		First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// ListByUser ユーザーが連携しているアカウントを連携した順に取得
+func (r *oauthProviderRepository) ListByUser(ctx context.Context, userID uint) ([]models.OAuthProvider, error) {
+	var identities []models.OAuthProvider
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id ASC").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+// Create 連携を作成
+func (r *oauthProviderRepository) Create(ctx context.Context, identity *models.OAuthProvider) error {
+	return r.db.WithContext(ctx).Create(identity).Error
+}
+
+// Update 連携を更新
+func (r *oauthProviderRepository) Update(ctx context.Context, identity *models.OAuthProvider) error {
+	return r.db.WithContext(ctx).Save(identity).Error
+}
+
+// Delete ユーザーのプロバイダーの連携を削除
+func (r *oauthProviderRepository) Delete(ctx context.Context, userID uint, provider string) error {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND provider = ?", userID, provider).Delete(&models.OAuthProvider{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAlreadyOrganizationMember 既に組織のメンバーになっている
+var ErrAlreadyOrganizationMember = errors.New("このユーザーは既に組織のメンバーです")
+
+// ErrOrganizationFull 組織のメンバー数が上限に達している
+var ErrOrganizationFull = errors.New("組織のメンバー数が上限に達しています")
+
+// ErrLastOrganizationOwner 組織の最後のオーナーは外せない
+var ErrLastOrganizationOwner = errors.New("組織にはオーナーが1人以上必要です")
+
+// ErrOrganizationHasProjects 組織にプロジェクトがあるため削除できない
+var ErrOrganizationHasProjects = errors.New("組織にプロジェクトがあるため削除できません")
+
+// OrganizationRepository 組織に関するデータベース操作を行うインターフェース
+type OrganizationRepository interface {
+	Create(ctx context.Context, organization *models.Organization, ownerID uint) error
+	FindByID(ctx context.Context, id uint) (*models.Organization, error)
+	Update(ctx context.Context, organization *models.Organization) error
+	Delete(ctx context.Context, id uint) error
+	ListByUser(ctx context.Context, userID uint) ([]models.Organization, error)
+	GetRole(ctx context.Context, organizationID, userID uint) (string, error)
+	ListMembers(ctx context.Context, organizationID uint) ([]models.OrganizationMember, error)
+	AddMember(ctx context.Context, organizationID, userID uint, role string) error
+	UpdateRole(ctx context.Context, organizationID, userID uint, role string) error
+	RemoveMember(ctx context.Context, organizationID, userID uint) error
+	ListProjects(ctx context.Context, organizationID uint, page, limit int, search string) ([]models.Project, int64, error)
+}
+
+// organizationRepository OrganizationRepositoryの実装
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository OrganizationRepositoryを作成
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+// Create 新しい組織を作成し、作成者をオーナーとしてメンバーに追加
+func (r *organizationRepository) Create(ctx context.Context, organization *models.Organization, ownerID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+		return tx.Omit("Organization", "User").Create(&models.OrganizationMember{
+			OrganizationID: organization.ID,
+			UserID:         ownerID,
+			Role:           models.OrganizationRoleOwner,
+			JoinedAt:       time.Now(),
+		}).Error
+	})
+}
+
+// FindByID IDで組織を検索
+func (r *organizationRepository) FindByID(ctx context.Context, id uint) (*models.Organization, error) {
+	var organization models.Organization
+	if err := r.db.WithContext(ctx).First(&organization, id).Error; err != nil {
+		return nil, err
+	}
+	if err := r.countOrganizations(ctx, []*models.Organization{&organization}); err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+// Update 組織を更新
+func (r *organizationRepository) Update(ctx context.Context, organization *models.Organization) error {
+	return r.db.WithContext(ctx).Save(organization).Error
+}
+
+// Delete 組織をメンバーとともに削除（プロジェクトがある場合は ErrOrganizationHasProjects）
+// 削除済みのプロジェクトは個人のプロジェクトに戻す
+func (r *organizationRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var organization models.Organization
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&organization, id).Error; err != nil {
+			return err
+		}
+
+		var projects int64
+		if err := tx.Model(&models.Project{}).Where("organization_id = ?", id).Count(&projects).Error; err != nil {
+			return err
+		}
+		if projects > 0 {
+			return ErrOrganizationHasProjects
+		}
+
+		if err := tx.Unscoped().Model(&models.Project{}).Where("organization_id = ?", id).
+			Update("organization_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_id = ?", id).Delete(&models.OrganizationMember{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Organization{}, id).Error
+	})
+}
+
+// ListByUser ユーザーが所属する組織一覧を名前順に取得
+func (r *organizationRepository) ListByUser(ctx context.Context, userID uint) ([]models.Organization, error) {
+	var organizations []models.Organization
+	if err := r.db.WithContext(ctx).Model(&models.Organization{}).
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Order("organizations.name ASC, organizations.id ASC").
+		Find(&organizations).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]*models.Organization, len(organizations))
+	for i := range organizations {
+		items[i] = &organizations[i]
+	}
+	if err := r.countOrganizations(ctx, items); err != nil {
+		return nil, err
+	}
+	return organizations, nil
+}
+
+// GetRole ユーザーの組織での役割を取得（メンバーでない場合は空文字）
+func (r *organizationRepository) GetRole(ctx context.Context, organizationID, userID uint) (string, error) {
+	var member models.OrganizationMember
+	err := r.db.WithContext(ctx).Select("role").
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&member).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// ListMembers 組織のメンバー一覧を参加の早い順に取得
+func (r *organizationRepository) ListMembers(ctx context.Context, organizationID uint) ([]models.OrganizationMember, error) {
+	var members []models.OrganizationMember
+	if err := r.db.WithContext(ctx).Where("organization_id = ?", organizationID).
+		Preload("User").
+		Order("joined_at ASC, user_id ASC").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// AddMember 組織にメンバーを追加（上限に達している場合は ErrOrganizationFull、追加済みの場合は ErrAlreadyOrganizationMember）
+// 同時に追加されても上限を超えないよう、組織の行をロックして数える
+func (r *organizationRepository) AddMember(ctx context.Context, organizationID, userID uint, role string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var organization models.Organization
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&organization, organizationID).Error; err != nil {
+			return err
+		}
+
+		if organization.MaxMembers > 0 {
+			var count int64
+			if err := tx.Model(&models.OrganizationMember{}).Where("organization_id = ?", organizationID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count >= int64(organization.MaxMembers) {
+				return ErrOrganizationFull
+			}
+		}
+
+		return tx.Omit("Organization", "User").Create(&models.OrganizationMember{
+			OrganizationID: organizationID,
+			UserID:         userID,
+			Role:           role,
+			JoinedAt:       time.Now(),
+		}).Error
+	})
+	if isDuplicateKey(err) {
+		return ErrAlreadyOrganizationMember
+	}
+	return err
+}
+
+// UpdateRole メンバーの役割を変更（最後のオーナーを外す場合は ErrLastOrganizationOwner）
+func (r *organizationRepository) UpdateRole(ctx context.Context, organizationID, userID uint, role string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		member, err := lockOrganizationMember(tx, organizationID, userID)
+		if err != nil {
+			return err
+		}
+		if member.Role == models.OrganizationRoleOwner && role != models.OrganizationRoleOwner {
+			if err := ensureAnotherOwner(tx, organizationID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.OrganizationMember{}).
+			Where("organization_id = ? AND user_id = ?", organizationID, userID).
+			Update("role", role).Error
+	})
+}
+
+// RemoveMember メンバーを組織から削除（最後のオーナーの場合は ErrLastOrganizationOwner）
+func (r *organizationRepository) RemoveMember(ctx context.Context, organizationID, userID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		member, err := lockOrganizationMember(tx, organizationID, userID)
+		if err != nil {
+			return err
+		}
+		if member.Role == models.OrganizationRoleOwner {
+			if err := ensureAnotherOwner(tx, organizationID); err != nil {
+				return err
+			}
+		}
+		return tx.Where("organization_id = ? AND user_id = ?", organizationID, userID).
+			Delete(&models.OrganizationMember{}).Error
+	})
+}
+
+// ListProjects 組織のプロジェクト一覧を取得
+func (r *organizationRepository) ListProjects(ctx context.Context, organizationID uint, page, limit int, search string) ([]models.Project, int64, error) {
+	var projects []models.Project
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Project{}).Where("organization_id = ?", organizationID)
+	if search != "" {
+		query = query.Where("title LIKE ? OR description LIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("Owner").
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&projects).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return projects, total, nil
+}
+
+// countOrganizations 組織ごとのメンバー数とプロジェクト数をまとめて集計して設定
+func (r *organizationRepository) countOrganizations(ctx context.Context, organizations []*models.Organization) error {
+	if len(organizations) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, len(organizations))
+	for i, organization := range organizations {
+		ids[i] = organization.ID
+	}
+
+	var members []struct {
+		OrganizationID uint
+		Count          int64
+	}
+	if err := r.db.WithContext(ctx).Model(&models.OrganizationMember{}).
+		Select("organization_id, COUNT(*) AS count").
+		Where("organization_id IN ?", ids).
+		Group("organization_id").
+		Scan(&members).Error; err != nil {
+		return err
+	}
+
+	var projects []struct {
+		OrganizationID uint
+		Count          int64
+	}
+	if err := r.db.WithContext(ctx).Model(&models.Project{}).
+		Select("organization_id, COUNT(*) AS count").
+		Where("organization_id IN ?", ids).
+		Group("organization_id").
+		Scan(&projects).Error; err != nil {
+		return err
+	}
+
+	memberCounts := make(map[uint]int64, len(members))
+	for _, row := range members {
+		memberCounts[row.OrganizationID] = row.Count
+	}
+	projectCounts := make(map[uint]int64, len(projects))
+	for _, row := range projects {
+		projectCounts[row.OrganizationID] = row.Count
+	}
+	for _, organization := range organizations {
+		organization.MembersCount = memberCounts[organization.ID]
+		organization.ProjectsCount = projectCounts[organization.ID]
+	}
+	return nil
+}
+
+// lockOrganizationMember 組織のメンバーを行ロックして取得
+func lockOrganizationMember(tx *gorm.DB, organizationID, userID uint) (*models.OrganizationMember, error) {
+	var member models.OrganizationMember
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// ensureAnotherOwner 他にオーナーがいるか確認（いない場合は ErrLastOrganizationOwner）
+// 同時に外されても0人にならないよう、オーナーの行をロックして数える
+func ensureAnotherOwner(tx *gorm.DB, organizationID uint) error {
+	var owners []models.OrganizationMember
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("organization_id = ? AND role = ?", organizationID, models.OrganizationRoleOwner).
+		Find(&owners).Error; err != nil {
+		return err
+	}
+	if len(owners) <= 1 {
+		return ErrLastOrganizationOwner
+	}
+	return nil
+}
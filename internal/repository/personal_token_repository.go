@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PersonalTokenRepository 個人用アクセストークンに関するデータベース操作を行うインターフェース
+type PersonalTokenRepository interface {
+	Create(ctx context.Context, token *models.PersonalToken) error
+	FindByID(ctx context.Context, id uint) (*models.PersonalToken, error)
+	FindByHash(ctx context.Context, tokenHash string) (*models.PersonalToken, error)
+	ListByUser(ctx context.Context, userID uint) ([]models.PersonalToken, error)
+	CountByUser(ctx context.Context, userID uint) (int64, error)
+	Update(ctx context.Context, token *models.PersonalToken) error
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// personalTokenRepository PersonalTokenRepositoryの実装
+type personalTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPersonalTokenRepository PersonalTokenRepositoryを作成
+func NewPersonalTokenRepository(db *gorm.DB) PersonalTokenRepository {
+	return &personalTokenRepository{db: db}
+}
+
+// Create 個人用アクセストークンを作成
+func (r *personalTokenRepository) Create(ctx context.Context, token *models.PersonalToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// FindByID IDで個人用アクセストークンを取得
+func (r *personalTokenRepository) FindByID(ctx context.Context, id uint) (*models.PersonalToken, error) {
+	var token models.PersonalToken
+	if err := r.db.WithContext(ctx).First(&token, id).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByHash トークンのハッシュで個人用アクセストークンを取得
+func (r *personalTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.PersonalToken, error) {
+	var token models.PersonalToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ListByUser ユーザーの個人用アクセストークンを作成日時の新しい順に取得
+func (r *personalTokenRepository) ListByUser(ctx context.Context, userID uint) ([]models.PersonalToken, error) {
+	var tokens []models.PersonalToken
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// CountByUser ユーザーの個人用アクセストークン数を数える
+func (r *personalTokenRepository) CountByUser(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.PersonalToken{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Update 個人用アクセストークンの名前・スコープを更新
+func (r *personalTokenRepository) Update(ctx context.Context, token *models.PersonalToken) error {
+	return r.db.WithContext(ctx).Model(token).Select("name", "scopes").Updates(token).Error
+}
+
+// TouchLastUsed 最後に使われた日時を更新
+func (r *personalTokenRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.PersonalToken{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error
+}
+
+// Delete 個人用アクセストークンを削除
+func (r *personalTokenRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.PersonalToken{}, id).Error
+}
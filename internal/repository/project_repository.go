@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"gorm.io/gorm"
@@ -9,19 +12,49 @@ import (
 
 // ProjectRepository プロジェクトに関するデータベース操作を行うインターフェース
 type ProjectRepository interface {
-	Create(project *models.Project) error
-	FindByID(id uint) (*models.Project, error)
-	FindByInvitationCode(code string) (*models.Project, error)
-	Update(project *models.Project) error
-	Delete(id uint) error
-	List(page, limit int, search string, userID *uint) ([]models.Project, int64, error)
-	AddMember(projectID, userID uint, isOwner bool) error
-	RemoveMember(projectID, userID uint) error
-	GetMembers(projectID uint) ([]models.ProjectMember, error)
-	IsMember(projectID, userID uint) (bool, error)
-	IsOwner(projectID, userID uint) (bool, error)
-	GetUserProjects(userID uint, page, limit int) ([]models.Project, int64, error)
-	UpdateInvitationCode(projectID uint, code string) error
+	Create(ctx context.Context, project *models.Project) error
+	Clone(ctx context.Context, sourceID uint, project *models.Project) error
+	FindByID(ctx context.Context, id uint) (*models.Project, error)
+	FindByInvitationCode(ctx context.Context, code string) (*models.Project, error)
+	Update(ctx context.Context, project *models.Project) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, page, limit int, search string, userID *uint) ([]models.Project, int64, error)
+	AddMember(ctx context.Context, projectID, userID uint, isOwner bool) error
+	RemoveMember(ctx context.Context, projectID, userID uint) error
+	TouchMember(ctx context.Context, projectID, userID uint, at time.Time) error
+	TouchMembersByWork(ctx context.Context, workID, userID uint, at time.Time) error
+	GetMembers(ctx context.Context, projectID uint) ([]models.ProjectMember, error)
+	IsMember(ctx context.Context, projectID, userID uint) (bool, error)
+	IsOwner(ctx context.Context, projectID, userID uint) (bool, error)
+	GetUserProjects(ctx context.Context, userID uint, page, limit int) ([]models.Project, int64, error)
+	CountOwned(ctx context.Context, userID uint) (int64, error)
+	UpdateInvitationCode(ctx context.Context, projectID uint, code string) error
+	SharesProject(ctx context.Context, userID, otherUserID uint) (bool, error)
+	SetSupervised(ctx context.Context, projectID, userID uint, supervised bool) error
+	IsSupervised(ctx context.Context, userID uint) (bool, error)
+	SharesSupervisedProject(ctx context.Context, minorID, otherUserID uint) (bool, error)
+	CountDashboard(ctx context.Context, projectID uint) (*ProjectDashboardCounts, error)
+	ListMemberActivity(ctx context.Context, projectID uint) ([]ProjectMemberActivity, error)
+	ListUpcomingVotes(ctx context.Context, projectID uint, limit int) ([]models.Vote, error)
+	ListScheduledVotes(ctx context.Context, projectID uint) ([]models.Vote, error)
+	ListContests(ctx context.Context, page, limit int, phase string) ([]models.Project, int64, error)
+	ListContestsByMember(ctx context.Context, userID uint, page, limit int) ([]models.Project, int64, error)
+}
+
+// ProjectMemberActivity プロジェクトのメンバーごとの提出作品数と最後の活動日時
+type ProjectMemberActivity struct {
+	UserID          uint       `json:"user_id"`
+	Submissions     int64      `json:"submissions"`
+	LastSubmittedAt *time.Time `json:"last_submitted_at"`
+	LastActivityAt  *time.Time `json:"last_activity_at"` // アクティビティに記録された最後の活動
+}
+
+// ProjectDashboardCounts プロジェクトダッシュボードの集計値
+type ProjectDashboardCounts struct {
+	Members     int64 `json:"members"`
+	Tasks       int64 `json:"tasks"`
+	Submissions int64 `json:"submissions"`
+	OpenVotes   int64 `json:"open_votes"`
 }
 
 // projectRepository ProjectRepositoryの実装
@@ -35,46 +68,137 @@ func NewProjectRepository(db *gorm.DB) ProjectRepository {
 }
 
 // Create 新しいプロジェクトを作成
-func (r *projectRepository) Create(project *models.Project) error {
-	return r.db.Create(project).Error
+func (r *projectRepository) Create(ctx context.Context, project *models.Project) error {
+	if project.Version == 0 {
+		project.Version = 1
+	}
+	return r.db.WithContext(ctx).Create(project).Error
+}
+
+// Clone sourceIDのプロジェクトのタスク・評価基準・投票をコピーしたプロジェクトを作成し、オーナーをメンバーに追加
+// 提出作品・メンバー・講評・投票の回答と、作品を選ぶ選択肢・締め切りはコピーしない
+func (r *projectRepository) Clone(ctx context.Context, sourceID uint, project *models.Project) error {
+	if project.Version == 0 {
+		project.Version = 1
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(project).Error; err != nil {
+			return err
+		}
+		owner := models.ProjectMember{ProjectID: project.ID, UserID: project.OwnerID, IsOwner: true, JoinedAt: time.Now()}
+		if err := tx.Create(&owner).Error; err != nil {
+			return err
+		}
+
+		var tasks []models.Task
+		if err := tx.Where("project_id = ?", sourceID).Order("order_index ASC, created_at ASC").Find(&tasks).Error; err != nil {
+			return err
+		}
+		for _, source := range tasks {
+			task := models.Task{
+				Title:           source.Title,
+				Description:     source.Description,
+				DescriptionHTML: source.DescriptionHTML,
+				ProjectID:       project.ID,
+				OrderIndex:      source.OrderIndex,
+				Version:         1,
+			}
+			if err := tx.Create(&task).Error; err != nil {
+				return err
+			}
+			if err := cloneTaskCriteria(tx, source.ID, task.ID); err != nil {
+				return err
+			}
+			if err := cloneTaskVotes(tx, source.ID, task.ID, project.OwnerID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// cloneTaskCriteria タスクの講評の評価基準をコピー
+func cloneTaskCriteria(tx *gorm.DB, sourceTaskID, taskID uint) error {
+	var criteria []models.CritiqueCriterion
+	if err := tx.Where("task_id = ?", sourceTaskID).Order("order_index ASC, id ASC").Find(&criteria).Error; err != nil {
+		return err
+	}
+	if len(criteria) == 0 {
+		return nil
+	}
+	for i := range criteria {
+		criteria[i].ID = 0
+		criteria[i].TaskID = taskID
+		criteria[i].CreatedAt = time.Time{}
+	}
+	return tx.Create(&criteria).Error
+}
+
+// cloneTaskVotes タスクの投票を受付中としてコピー（作品を選ぶ選択肢は提出作品のためコピーしない）
+func cloneTaskVotes(tx *gorm.DB, sourceTaskID, taskID, ownerID uint) error {
+	var votes []models.Vote
+	if err := tx.Preload("Options", "work_id IS NULL").Where("task_id = ?", sourceTaskID).
+		Order("id ASC").Find(&votes).Error; err != nil {
+		return err
+	}
+	for _, source := range votes {
+		vote := models.Vote{
+			Title:       source.Title,
+			Description: source.Description,
+			TaskID:      taskID,
+			MultiSelect: source.MultiSelect,
+			Anonymous:   source.Anonymous,
+			IsActive:    true,
+			CreatedBy:   ownerID,
+		}
+		if err := tx.Create(&vote).Error; err != nil {
+			return err
+		}
+		for _, option := range source.Options {
+			if err := tx.Create(&models.VoteOption{VoteID: vote.ID, OptionText: option.OptionText}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // FindByID IDでプロジェクトを検索
-func (r *projectRepository) FindByID(id uint) (*models.Project, error) {
+func (r *projectRepository) FindByID(ctx context.Context, id uint) (*models.Project, error) {
 	var project models.Project
-	if err := r.db.Preload("Owner").First(&project, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Owner").First(&project, id).Error; err != nil {
 		return nil, err
 	}
 	return &project, nil
 }
 
 // FindByInvitationCode 招待コードでプロジェクトを検索
-func (r *projectRepository) FindByInvitationCode(code string) (*models.Project, error) {
+func (r *projectRepository) FindByInvitationCode(ctx context.Context, code string) (*models.Project, error) {
 	var project models.Project
-	if err := r.db.Where("invitation_code = ?", code).Preload("Owner").First(&project).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("invitation_code = ?", code).Preload("Owner").First(&project).Error; err != nil {
 		return nil, err
 	}
 	return &project, nil
 }
 
-// Update プロジェクト情報を更新
-func (r *projectRepository) Update(project *models.Project) error {
-	return r.db.Save(project).Error
+// Update プロジェクト情報を更新（バージョンが一致しない場合は ErrVersionConflict）
+func (r *projectRepository) Update(ctx context.Context, project *models.Project) error {
+	return saveWithVersion(r.db.WithContext(ctx), project, &project.Version)
 }
 
 // Delete プロジェクトを削除
-func (r *projectRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Project{}, id).Error
+func (r *projectRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Project{}, id).Error
 }
 
 // List プロジェクト一覧を取得
-func (r *projectRepository) List(page, limit int, search string, userID *uint) ([]models.Project, int64, error) {
+func (r *projectRepository) List(ctx context.Context, page, limit int, search string, userID *uint) ([]models.Project, int64, error) {
 	var projects []models.Project
 	var total int64
 
 	offset := (page - 1) * limit
 
-	query := r.db.Model(&models.Project{}).Preload("Owner")
+	query := r.db.WithContext(ctx).Model(&models.Project{}).Preload("Owner")
 
 	// 検索条件を適用
 	if search != "" {
@@ -103,26 +227,47 @@ func (r *projectRepository) List(page, limit int, search string, userID *uint) (
 }
 
 // AddMember メンバーをプロジェクトに追加
-func (r *projectRepository) AddMember(projectID, userID uint, isOwner bool) error {
+func (r *projectRepository) AddMember(ctx context.Context, projectID, userID uint, isOwner bool) error {
 	member := models.ProjectMember{
 		ProjectID: projectID,
 		UserID:    userID,
 		IsOwner:   isOwner,
 	}
 
-	return r.db.Create(&member).Error
+	return r.db.WithContext(ctx).Create(&member).Error
 }
 
 // RemoveMember メンバーをプロジェクトから削除
-func (r *projectRepository) RemoveMember(projectID, userID uint) error {
-	return r.db.Where("project_id = ? AND user_id = ?", projectID, userID).Delete(&models.ProjectMember{}).Error
+func (r *projectRepository) RemoveMember(ctx context.Context, projectID, userID uint) error {
+	return r.db.WithContext(ctx).Where("project_id = ? AND user_id = ?", projectID, userID).Delete(&models.ProjectMember{}).Error
+}
+
+// TouchMember メンバーの最後の活動日時を更新（メンバーでない場合は何もしない）
+func (r *projectRepository) TouchMember(ctx context.Context, projectID, userID uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.ProjectMember{}).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Where("last_activity_at IS NULL OR last_activity_at < ?", at).
+		Update("last_activity_at", at).Error
+}
+
+// TouchMembersByWork 作品が提出されているプロジェクトで、ユーザーのメンバーとしての最後の活動日時を更新
+func (r *projectRepository) TouchMembersByWork(ctx context.Context, workID, userID uint, at time.Time) error {
+	projectIDs := r.db.Table("task_works").
+		Select("tasks.project_id").
+		Joins("JOIN tasks ON tasks.id = task_works.task_id AND tasks.deleted_at IS NULL").
+		Where("task_works.work_id = ?", workID)
+
+	return r.db.WithContext(ctx).Model(&models.ProjectMember{}).
+		Where("project_id IN (?) AND user_id = ?", projectIDs, userID).
+		Where("last_activity_at IS NULL OR last_activity_at < ?", at).
+		Update("last_activity_at", at).Error
 }
 
 // GetMembers プロジェクトのメンバー一覧を取得
-func (r *projectRepository) GetMembers(projectID uint) ([]models.ProjectMember, error) {
+func (r *projectRepository) GetMembers(ctx context.Context, projectID uint) ([]models.ProjectMember, error) {
 	var members []models.ProjectMember
 
-	if err := r.db.Where("project_id = ?", projectID).
+	if err := r.db.WithContext(ctx).Where("project_id = ?", projectID).
 		Preload("User").
 		Find(&members).Error; err != nil {
 		return nil, err
@@ -131,38 +276,58 @@ func (r *projectRepository) GetMembers(projectID uint) ([]models.ProjectMember,
 	return members, nil
 }
 
-// IsMember ユーザーがプロジェクトのメンバーかどうか確認
-func (r *projectRepository) IsMember(projectID, userID uint) (bool, error) {
+// IsMember ユーザーがプロジェクトのメンバーかどうか確認（組織のオーナー・管理者は組織のプロジェクトのメンバーとして扱う）
+func (r *projectRepository) IsMember(ctx context.Context, projectID, userID uint) (bool, error) {
 	var count int64
-	if err := r.db.Model(&models.ProjectMember{}).
+	if err := r.db.WithContext(ctx).Model(&models.ProjectMember{}).
 		Where("project_id = ? AND user_id = ?", projectID, userID).
 		Count(&count).Error; err != nil {
 		return false, err
 	}
+	if count > 0 {
+		return true, nil
+	}
 
-	return count > 0, nil
+	return r.isOrganizationManager(ctx, projectID, userID)
 }
 
-// IsOwner ユーザーがプロジェクトのオーナーかどうか確認
-func (r *projectRepository) IsOwner(projectID, userID uint) (bool, error) {
+// IsOwner ユーザーがプロジェクトのオーナーかどうか確認（組織のオーナー・管理者は組織のプロジェクトのオーナーとして扱う）
+func (r *projectRepository) IsOwner(ctx context.Context, projectID, userID uint) (bool, error) {
 	var count int64
-	if err := r.db.Model(&models.ProjectMember{}).
+	if err := r.db.WithContext(ctx).Model(&models.ProjectMember{}).
 		Where("project_id = ? AND user_id = ? AND is_owner = true", projectID, userID).
 		Count(&count).Error; err != nil {
 		return false, err
 	}
+	if count > 0 {
+		return true, nil
+	}
+
+	return r.isOrganizationManager(ctx, projectID, userID)
+}
+
+// isOrganizationManager ユーザーがプロジェクトの所属する組織のオーナーまたは管理者かどうか確認
+func (r *projectRepository) isOrganizationManager(ctx context.Context, projectID, userID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Project{}).
+		Joins("JOIN organization_members ON organization_members.organization_id = projects.organization_id").
+		Where("projects.id = ? AND organization_members.user_id = ? AND organization_members.role IN ?",
+			projectID, userID, []string{models.OrganizationRoleOwner, models.OrganizationRoleAdmin}).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
 
 	return count > 0, nil
 }
 
 // GetUserProjects ユーザーが参加しているプロジェクト一覧を取得
-func (r *projectRepository) GetUserProjects(userID uint, page, limit int) ([]models.Project, int64, error) {
+func (r *projectRepository) GetUserProjects(ctx context.Context, userID uint, page, limit int) ([]models.Project, int64, error) {
 	var projects []models.Project
 	var total int64
 
 	offset := (page - 1) * limit
 
-	query := r.db.Model(&models.Project{}).
+	query := r.db.WithContext(ctx).Model(&models.Project{}).
 		Joins("JOIN project_members ON projects.id = project_members.project_id").
 		Where("project_members.user_id = ?", userID).
 		Preload("Owner")
@@ -181,9 +346,191 @@ func (r *projectRepository) GetUserProjects(userID uint, page, limit int) ([]mod
 	return projects, total, nil
 }
 
+// CountOwned ユーザーがオーナーの個人のプロジェクト数を数える（組織のプロジェクトは含めない）
+func (r *projectRepository) CountOwned(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Project{}).
+		Where("owner_id = ? AND organization_id IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // UpdateInvitationCode 招待コードを更新
-func (r *projectRepository) UpdateInvitationCode(projectID uint, code string) error {
-	return r.db.Model(&models.Project{}).
+func (r *projectRepository) UpdateInvitationCode(ctx context.Context, projectID uint, code string) error {
+	return r.db.WithContext(ctx).Model(&models.Project{}).
 		Where("id = ?", projectID).
 		Update("invitation_code", code).Error
 }
+
+// SharesProject 2人のユーザーが同じプロジェクトに参加しているか確認
+func (r *projectRepository) SharesProject(ctx context.Context, userID, otherUserID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Table("project_members AS a").
+		Joins("JOIN project_members AS b ON a.project_id = b.project_id").
+		Joins("JOIN projects ON projects.id = a.project_id AND projects.deleted_at IS NULL").
+		Where("a.user_id = ? AND b.user_id = ?", userID, otherUserID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// SetSupervised メンバーの見守りを設定
+func (r *projectRepository) SetSupervised(ctx context.Context, projectID, userID uint, supervised bool) error {
+	return r.db.WithContext(ctx).Model(&models.ProjectMember{}).
+		Where("project_id = ? AND user_id = ?", projectID, userID).
+		Update("supervised", supervised).Error
+}
+
+// IsSupervised ユーザーが見守りを有効にされているプロジェクトがあるか確認
+func (r *projectRepository) IsSupervised(ctx context.Context, userID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Table("project_members").
+		Joins("JOIN projects ON projects.id = project_members.project_id AND projects.deleted_at IS NULL").
+		Where("project_members.user_id = ? AND project_members.supervised = ?", userID, true).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// SharesSupervisedProject minorIDのユーザーが見守りを有効にされているプロジェクトに、otherUserIDのユーザーも参加しているか確認
+func (r *projectRepository) SharesSupervisedProject(ctx context.Context, minorID, otherUserID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Table("project_members AS a").
+		Joins("JOIN project_members AS b ON a.project_id = b.project_id").
+		Joins("JOIN projects ON projects.id = a.project_id AND projects.deleted_at IS NULL").
+		Where("a.user_id = ? AND a.supervised = ? AND b.user_id = ?", minorID, true, otherUserID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// CountDashboard メンバー数・タスク数・提出作品数・受付中の投票数を1回のクエリで集計
+func (r *projectRepository) CountDashboard(ctx context.Context, projectID uint) (*ProjectDashboardCounts, error) {
+	var counts ProjectDashboardCounts
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM project_members
+				WHERE project_members.project_id = @project) AS members,
+			(SELECT COUNT(*) FROM tasks
+				WHERE tasks.project_id = @project AND tasks.deleted_at IS NULL) AS tasks,
+			(SELECT COUNT(*) FROM task_works
+				JOIN tasks ON tasks.id = task_works.task_id AND tasks.deleted_at IS NULL
+				JOIN works ON works.id = task_works.work_id AND works.deleted_at IS NULL
+				WHERE tasks.project_id = @project) AS submissions,
+			(SELECT COUNT(*) FROM votes
+				JOIN tasks ON tasks.id = votes.task_id AND tasks.deleted_at IS NULL
+				WHERE tasks.project_id = @project AND votes.is_active = @active) AS open_votes`,
+		sql.Named("project", projectID), sql.Named("active", true),
+	).Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	return &counts, nil
+}
+
+// ListMemberActivity メンバーごとの提出作品数と最後の提出・活動日時を1回のクエリで集計
+func (r *projectRepository) ListMemberActivity(ctx context.Context, projectID uint) ([]ProjectMemberActivity, error) {
+	var activities []ProjectMemberActivity
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			project_members.user_id,
+			COUNT(submissions.work_id) AS submissions,
+			MAX(submissions.created_at) AS last_submitted_at,
+			(SELECT MAX(activities.created_at) FROM activities
+				WHERE activities.project_id = @project AND activities.user_id = project_members.user_id) AS last_activity_at
+		FROM project_members
+		LEFT JOIN (
+			SELECT works.user_id, task_works.work_id, task_works.created_at FROM task_works
+				JOIN tasks ON tasks.id = task_works.task_id AND tasks.deleted_at IS NULL
+				JOIN works ON works.id = task_works.work_id AND works.deleted_at IS NULL
+				WHERE tasks.project_id = @project
+		) AS submissions ON submissions.user_id = project_members.user_id
+		WHERE project_members.project_id = @project
+		GROUP BY project_members.user_id`,
+		sql.Named("project", projectID),
+	).Scan(&activities).Error; err != nil {
+		return nil, err
+	}
+
+	return activities, nil
+}
+
+// ListUpcomingVotes 締め切りが近い受付中の投票を取得
+func (r *projectRepository) ListUpcomingVotes(ctx context.Context, projectID uint, limit int) ([]models.Vote, error) {
+	var votes []models.Vote
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN tasks ON tasks.id = votes.task_id AND tasks.deleted_at IS NULL").
+		Where("tasks.project_id = ? AND votes.is_active = ? AND votes.closes_at > ?", projectID, true, time.Now()).
+		Order("votes.closes_at ASC").
+		Limit(limit).
+		Find(&votes).Error; err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
+
+// ListScheduledVotes 締め切りが設定されている投票を全て取得（締め切りの早い順）
+func (r *projectRepository) ListScheduledVotes(ctx context.Context, projectID uint) ([]models.Vote, error) {
+	var votes []models.Vote
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN tasks ON tasks.id = votes.task_id AND tasks.deleted_at IS NULL").
+		Where("tasks.project_id = ? AND votes.closes_at IS NOT NULL", projectID).
+		Order("votes.closes_at ASC, votes.id ASC").
+		Find(&votes).Error; err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
+
+// ListContests コンテスト一覧を取得（phaseが空の場合は全ての進行状況）
+func (r *projectRepository) ListContests(ctx context.Context, page, limit int, phase string) ([]models.Project, int64, error) {
+	var projects []models.Project
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Project{}).Where("is_contest = ?", true)
+	if phase != "" {
+		query = query.Where("contest_phase = ?", phase)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("Owner").Offset(offset).Limit(limit).Order("created_at DESC").Find(&projects).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return projects, total, nil
+}
+
+// ListContestsByMember ユーザーが参加しているコンテスト一覧を取得
+func (r *projectRepository) ListContestsByMember(ctx context.Context, userID uint, page, limit int) ([]models.Project, int64, error) {
+	var projects []models.Project
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Project{}).
+		Joins("JOIN project_members ON projects.id = project_members.project_id").
+		Where("project_members.user_id = ? AND projects.is_contest = ?", userID, true)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("Owner").Offset(offset).Limit(limit).Order("projects.created_at DESC").Find(&projects).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return projects, total, nil
+}
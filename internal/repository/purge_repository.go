@@ -0,0 +1,422 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrPurgeOwner オーナーのプロジェクト・組織または主催するイベントがあり、ユーザーを完全に削除できない
+var ErrPurgeOwner = errors.New("オーナーのプロジェクト・組織または主催するイベントがあります")
+
+// PurgeRepository ユーザーと作品を完全に削除するデータベース操作を行うインターフェース
+type PurgeRepository interface {
+	PurgeUser(ctx context.Context, userID uint, log *models.AuditLog) (*PurgeResult, error)
+	PurgeWork(ctx context.Context, workID uint, log *models.AuditLog) (*PurgeResult, error)
+}
+
+// PurgeResult 完全に削除したレコードと、続けて削除する保存先のデータ
+type PurgeResult struct {
+	Rows           map[string]int64 `json:"rows"`     // テーブルごとの削除件数
+	WorkIDs        []uint           `json:"work_ids"` // 削除した作品
+	StorageKeys    []string         `json:"-"`        // アセットなどのストレージのキー
+	ImagePublicIDs []string         `json:"-"`        // Cloudinaryの画像
+}
+
+// Add テーブルの削除件数を加算
+func (r *PurgeResult) Add(table string, n int64) {
+	if n > 0 {
+		r.Rows[table] += n
+	}
+}
+
+// purgeRepository PurgeRepositoryの実装
+type purgeRepository struct {
+	db *gorm.DB
+}
+
+// NewPurgeRepository PurgeRepositoryを作成
+func NewPurgeRepository(db *gorm.DB) PurgeRepository {
+	return &purgeRepository{db: db}
+}
+
+// PurgeUser ユーザーと、ユーザーの作品・コメント・メッセージなどを1つのトランザクションで完全に削除し、監査ログを記録
+// オーナーのプロジェクト・他にオーナーがいない組織・主催するイベントがある場合は ErrPurgeOwner を返す
+func (r *purgeRepository) PurgeUser(ctx context.Context, userID uint, log *models.AuditLog) (*PurgeResult, error) {
+	result := &PurgeResult{Rows: map[string]int64{}}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Unscoped().First(&user, userID).Error; err != nil {
+			return err
+		}
+
+		// 削除済みのプロジェクトもオーナーを参照しているため含める
+		var owned int64
+		if err := tx.Unscoped().Model(&models.Project{}).Where("owner_id = ?", userID).Count(&owned).Error; err != nil {
+			return err
+		}
+		if owned == 0 {
+			if err := tx.Model(&models.Event{}).Where("organizer_id = ?", userID).Count(&owned).Error; err != nil {
+				return err
+			}
+		}
+		if owned == 0 {
+			if err := tx.Model(&models.OrganizationMember{}).
+				Where("user_id = ? AND role = ?", userID, models.OrganizationRoleOwner).
+				Where("NOT EXISTS (SELECT 1 FROM organization_members others WHERE others.organization_id = organization_members.organization_id AND others.role = ? AND others.user_id <> ?)",
+					models.OrganizationRoleOwner, userID).
+				Count(&owned).Error; err != nil {
+				return err
+			}
+		}
+		if owned > 0 {
+			return ErrPurgeOwner
+		}
+
+		// 作品（削除済みを含む）
+		var workIDs []uint
+		if err := tx.Unscoped().Model(&models.Work{}).Where("user_id = ?", userID).Pluck("id", &workIDs).Error; err != nil {
+			return err
+		}
+		if err := purgeWorks(tx, workIDs, result); err != nil {
+			return err
+		}
+
+		// 他の作品へのコメント（返信は残し、返信先を外す）
+		var comments []models.Comment
+		if err := tx.Unscoped().Select("id", "work_id", "parent_id", "deleted_at").Where("user_id = ?", userID).Find(&comments).Error; err != nil {
+			return err
+		}
+		if len(comments) > 0 {
+			commentIDs := make([]uint, 0, len(comments))
+			for _, comment := range comments {
+				commentIDs = append(commentIDs, comment.ID)
+				// 削除済みのコメントは削除した時点で数を減らしている
+				if comment.DeletedAt.Valid {
+					continue
+				}
+				if comment.ParentID != nil {
+					if err := tx.Model(&models.Comment{}).Where("id = ?", *comment.ParentID).
+						UpdateColumn("replies_count", gorm.Expr("GREATEST(replies_count - 1, 0)")).Error; err != nil {
+						return err
+					}
+				}
+				if err := tx.Model(&models.Work{}).Where("id = ?", comment.WorkID).
+					UpdateColumn("comments_count", gorm.Expr("GREATEST(comments_count - 1, 0)")).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Unscoped().Model(&models.Comment{}).Where("parent_id IN ?", commentIDs).
+				UpdateColumn("parent_id", nil).Error; err != nil {
+				return err
+			}
+			reports := tx.Where("target_type = ? AND target_id IN ?", models.ReportTargetComment, commentIDs).Delete(&models.Report{})
+			if reports.Error != nil {
+				return reports.Error
+			}
+			result.Add("reports", reports.RowsAffected)
+			deleted := tx.Unscoped().Where("id IN ?", commentIDs).Delete(&models.Comment{})
+			if deleted.Error != nil {
+				return deleted.Error
+			}
+			result.Add("comments", deleted.RowsAffected)
+		}
+
+		// 他の作品にアップロードしたアセットとスナップショット
+		if err := purgeAssets(tx, tx.Where("user_id = ?", userID), result); err != nil {
+			return err
+		}
+		var snapshotImageIDs []uint
+		if err := tx.Model(&models.WorkSnapshot{}).Where("user_id = ?", userID).Pluck("image_id", &snapshotImageIDs).Error; err != nil {
+			return err
+		}
+		snapshots := tx.Where("user_id = ?", userID).Delete(&models.WorkSnapshot{})
+		if snapshots.Error != nil {
+			return snapshots.Error
+		}
+		result.Add("work_snapshots", snapshots.RowsAffected)
+		if len(snapshotImageIDs) > 0 {
+			if err := purgeImages(tx, tx.Where("id IN ?", snapshotImageIDs), result); err != nil {
+				return err
+			}
+		}
+		// アバターなど、ユーザーがアップロードした残りの画像
+		if err := purgeImages(tx, tx.Where("user_id = ?", userID), result); err != nil {
+			return err
+		}
+
+		// 作成した投票はプロジェクトのオーナーが作成したものとして残す
+		if err := tx.Exec("UPDATE votes JOIN tasks ON tasks.id = votes.task_id JOIN projects ON projects.id = tasks.project_id "+
+			"SET votes.created_by = projects.owner_id WHERE votes.created_by = ?", userID).Error; err != nil {
+			return err
+		}
+
+		// いいねした作品のいいね数を減らす
+		if err := tx.Model(&models.Work{}).Where("id IN (SELECT work_id FROM likes WHERE user_id = ?)", userID).
+			UpdateColumn("likes_count", gorm.Expr("GREATEST(likes_count - 1, 0)")).Error; err != nil {
+			return err
+		}
+
+		// ユーザーに関連するレコード（講評の点数を先に削除する）
+		scores := tx.Where("critique_id IN (?)", tx.Model(&models.Critique{}).Select("id").Where("user_id = ?", userID)).
+			Delete(&models.CritiqueScore{})
+		if scores.Error != nil {
+			return scores.Error
+		}
+		result.Add("critique_scores", scores.RowsAffected)
+		for _, target := range []struct {
+			table string
+			query *gorm.DB
+			model interface{}
+		}{
+			{"likes", tx.Where("user_id = ?", userID), &models.Like{}},
+			{"project_members", tx.Where("user_id = ?", userID), &models.ProjectMember{}},
+			{"organization_members", tx.Where("user_id = ?", userID), &models.OrganizationMember{}},
+			{"subscriptions", tx.Where("user_id = ?", userID), &models.Subscription{}},
+			{"oauth_providers", tx.Where("user_id = ?", userID), &models.OAuthProvider{}},
+			{"refresh_tokens", tx.Where("user_id = ?", userID), &models.RefreshToken{}},
+			{"revoked_tokens", tx.Where("user_id = ?", userID), &models.RevokedToken{}},
+			{"personal_tokens", tx.Where("user_id = ?", userID), &models.PersonalToken{}},
+			{"work_collaborators", tx.Where("user_id = ?", userID), &models.WorkCollaborator{}},
+			{"event_attendees", tx.Where("user_id = ?", userID), &models.EventAttendee{}},
+			{"vote_responses", tx.Where("user_id = ?", userID), &models.VoteResponse{}},
+			{"activities", tx.Where("user_id = ?", userID), &models.Activity{}},
+			{"notifications", tx.Where("user_id = ?", userID), &models.Notification{}},
+			{"comment_subscriptions", tx.Where("user_id = ?", userID), &models.CommentSubscription{}},
+			{"critiques", tx.Where("user_id = ?", userID), &models.Critique{}},
+			{"conversation_participants", tx.Where("user_id = ?", userID), &models.ConversationParticipant{}},
+			{"messages", tx.Where("sender_id = ?", userID), &models.Message{}},
+			{"conversion_logs", tx.Where("user_id = ?", userID), &models.ConversionLog{}},
+			{"series", tx.Unscoped().Where("user_id = ?", userID), &models.Series{}},
+			{"reports", tx.Where("reporter_id = ?", userID), &models.Report{}},
+			{"handle_histories", tx.Where("user_id = ?", userID), &models.HandleHistory{}},
+			{"waitlist_entries", tx.Where("email = ?", user.Email), &models.WaitlistEntry{}},
+		} {
+			deleted := target.query.Delete(target.model)
+			if deleted.Error != nil {
+				return deleted.Error
+			}
+			result.Add(target.table, deleted.RowsAffected)
+		}
+		if err := tx.Model(&models.InviteCode{}).Where("email = ?", user.Email).UpdateColumn("email", "").Error; err != nil {
+			return err
+		}
+
+		deleted := tx.Unscoped().Delete(&models.User{}, userID)
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		result.Add("users", deleted.RowsAffected)
+
+		return createAuditLog(tx, log, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PurgeWork 作品と、作品へのコメント・いいね・アセットなどを1つのトランザクションで完全に削除し、監査ログを記録
+func (r *purgeRepository) PurgeWork(ctx context.Context, workID uint, log *models.AuditLog) (*PurgeResult, error) {
+	result := &PurgeResult{Rows: map[string]int64{}}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var work models.Work
+		if err := tx.Unscoped().Select("id").First(&work, workID).Error; err != nil {
+			return err
+		}
+		if err := purgeWorks(tx, []uint{workID}, result); err != nil {
+			return err
+		}
+		return createAuditLog(tx, log, result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// purgeWorks 作品と作品に関連するレコードを削除
+func purgeWorks(tx *gorm.DB, ids []uint, result *PurgeResult) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	result.WorkIDs = append(result.WorkIDs, ids...)
+
+	// サムネイル・アセット・スナップショットの画像
+	var thumbnails []string
+	if err := tx.Unscoped().Model(&models.Work{}).Where("id IN ? AND thumbnail_public_id <> ''", ids).
+		Pluck("thumbnail_public_id", &thumbnails).Error; err != nil {
+		return err
+	}
+	result.ImagePublicIDs = append(result.ImagePublicIDs, thumbnails...)
+	if err := purgeAssets(tx, tx.Where("work_id IN ?", ids), result); err != nil {
+		return err
+	}
+	var snapshotImageIDs []uint
+	if err := tx.Model(&models.WorkSnapshot{}).Where("work_id IN ?", ids).Pluck("image_id", &snapshotImageIDs).Error; err != nil {
+		return err
+	}
+	if len(snapshotImageIDs) > 0 {
+		if err := purgeImages(tx, tx.Where("id IN ?", snapshotImageIDs), result); err != nil {
+			return err
+		}
+	}
+
+	// 作品と作品へのコメントの通報
+	var commentIDs []uint
+	if err := tx.Unscoped().Model(&models.Comment{}).Where("work_id IN ?", ids).Pluck("id", &commentIDs).Error; err != nil {
+		return err
+	}
+	reports := tx.Where("target_type = ? AND target_id IN ?", models.ReportTargetWork, ids)
+	if len(commentIDs) > 0 {
+		reports = reports.Or("target_type = ? AND target_id IN ?", models.ReportTargetComment, commentIDs)
+	}
+	deleted := reports.Delete(&models.Report{})
+	if deleted.Error != nil {
+		return deleted.Error
+	}
+	result.Add("reports", deleted.RowsAffected)
+
+	scores := tx.Where("critique_id IN (?)", tx.Model(&models.Critique{}).Select("id").Where("work_id IN ?", ids)).
+		Delete(&models.CritiqueScore{})
+	if scores.Error != nil {
+		return scores.Error
+	}
+	result.Add("critique_scores", scores.RowsAffected)
+
+	tags := tx.Exec("DELETE FROM work_tags WHERE work_id IN ?", ids)
+	if tags.Error != nil {
+		return tags.Error
+	}
+	result.Add("work_tags", tags.RowsAffected)
+
+	for _, target := range []struct {
+		table string
+		query *gorm.DB
+		model interface{}
+	}{
+		{"likes", tx.Where("work_id IN ?", ids), &models.Like{}},
+		{"comments", tx.Unscoped().Where("work_id IN ?", ids), &models.Comment{}},
+		{"task_works", tx.Where("work_id IN ?", ids), &models.TaskWork{}},
+		{"work_badges", tx.Where("work_id IN ?", ids), &models.WorkBadge{}},
+		{"work_annotations", tx.Where("work_id IN ?", ids), &models.WorkAnnotation{}},
+		{"work_collaborators", tx.Where("work_id IN ?", ids), &models.WorkCollaborator{}},
+		{"work_snapshots", tx.Where("work_id IN ?", ids), &models.WorkSnapshot{}},
+		{"conversion_jobs", tx.Where("work_id IN ?", ids), &models.ConversionJob{}},
+		{"retag_job_works", tx.Where("work_id IN ?", ids), &models.RetagJobWork{}},
+		{"comment_subscriptions", tx.Where("work_id IN ?", ids), &models.CommentSubscription{}},
+		{"critiques", tx.Where("work_id IN ?", ids), &models.Critique{}},
+		{"work_rankings", tx.Where("work_id IN ?", ids), &models.WorkRanking{}},
+	} {
+		deleted := target.query.Delete(target.model)
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		result.Add(target.table, deleted.RowsAffected)
+	}
+
+	// 投票の選択肢は履歴として残し、作品との関連のみ外す（フォーク先の作品はフォーク元を外す）
+	if err := tx.Model(&models.VoteOption{}).Where("work_id IN ?", ids).UpdateColumn("work_id", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&models.Work{}).Where("forked_from_id IN ?", ids).UpdateColumn("forked_from_id", nil).Error; err != nil {
+		return err
+	}
+
+	works := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Work{})
+	if works.Error != nil {
+		return works.Error
+	}
+	result.Add("works", works.RowsAffected)
+	return nil
+}
+
+// purgeAssets queryに一致するアセットとアップロード中のデータを削除し、ストレージのキーを記録
+func purgeAssets(tx *gorm.DB, query *gorm.DB, result *PurgeResult) error {
+	var assets []models.WorkAsset
+	if err := query.Session(&gorm.Session{}).Select("id", "storage_key", "image_id").Find(&assets).Error; err != nil {
+		return err
+	}
+	var uploads []models.AssetUpload
+	if err := query.Session(&gorm.Session{}).Select("id", "storage_key").Find(&uploads).Error; err != nil {
+		return err
+	}
+
+	var imageIDs []uint
+	for _, asset := range assets {
+		result.StorageKeys = append(result.StorageKeys, asset.StorageKey)
+		if asset.ImageID != nil {
+			imageIDs = append(imageIDs, *asset.ImageID)
+		}
+	}
+	for _, upload := range uploads {
+		result.StorageKeys = append(result.StorageKeys, upload.StorageKey)
+	}
+
+	if len(assets) > 0 {
+		ids := make([]uint, 0, len(assets))
+		for _, asset := range assets {
+			ids = append(ids, asset.ID)
+		}
+		deleted := tx.Where("id IN ?", ids).Delete(&models.WorkAsset{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		result.Add("work_assets", deleted.RowsAffected)
+	}
+	if len(uploads) > 0 {
+		ids := make([]string, 0, len(uploads))
+		for _, upload := range uploads {
+			ids = append(ids, upload.ID)
+		}
+		deleted := tx.Where("id IN ?", ids).Delete(&models.AssetUpload{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		result.Add("asset_uploads", deleted.RowsAffected)
+	}
+	if len(imageIDs) > 0 {
+		return purgeImages(tx, tx.Where("id IN ?", imageIDs), result)
+	}
+	return nil
+}
+
+// purgeImages queryに一致する画像と派生画像を削除し、Cloudinaryの画像を記録
+func purgeImages(tx *gorm.DB, query *gorm.DB, result *PurgeResult) error {
+	var images []models.Image
+	if err := query.Select("id", "public_id").Find(&images).Error; err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		return nil
+	}
+
+	ids := make([]uint, 0, len(images))
+	for _, image := range images {
+		ids = append(ids, image.ID)
+		result.ImagePublicIDs = append(result.ImagePublicIDs, image.PublicID)
+	}
+	if err := tx.Where("image_id IN ?", ids).Delete(&models.ImageVariant{}).Error; err != nil {
+		return err
+	}
+	deleted := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Image{})
+	if deleted.Error != nil {
+		return deleted.Error
+	}
+	result.Add("images", deleted.RowsAffected)
+	return nil
+}
+
+// createAuditLog 削除件数を含めて監査ログを記録
+func createAuditLog(tx *gorm.DB, log *models.AuditLog, result *PurgeResult) error {
+	detail, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	log.Detail = string(detail)
+	return tx.Create(log).Error
+}
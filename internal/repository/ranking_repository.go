@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rankingBatchSize 人気スコアを1回のINSERTで保存する件数
+const rankingBatchSize = 1000
+
+// RankingRepository 作品の人気スコアに関するデータベース操作を行うインターフェース
+type RankingRepository interface {
+	ListSources(ctx context.Context, afterID uint, limit int) ([]models.Work, error)
+	Replace(ctx context.Context, rankings []models.WorkRanking, computedAt time.Time) error
+}
+
+// rankingRepository RankingRepositoryの実装
+type rankingRepository struct {
+	db *gorm.DB
+}
+
+// NewRankingRepository RankingRepositoryを作成
+func NewRankingRepository(db *gorm.DB) RankingRepository {
+	return &rankingRepository{db: db}
+}
+
+// ListSources afterIDより後の公開中の作品を、スコアの計算に使う列のみID順に取得
+func (r *rankingRepository) ListSources(ctx context.Context, afterID uint, limit int) ([]models.Work, error) {
+	var works []models.Work
+	if err := r.db.WithContext(ctx).Select("id", "views", "likes_count", "comments_count", "created_at").
+		Where("id > ? AND hidden_at IS NULL", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&works).Error; err != nil {
+		return nil, err
+	}
+	return works, nil
+}
+
+// Replace 人気スコアを保存し、今回計算しなかった作品（削除・非表示になった作品）のスコアを削除
+func (r *rankingRepository) Replace(ctx context.Context, rankings []models.WorkRanking, computedAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(rankings) > 0 {
+			if err := tx.Clauses(clause.OnConflict{
+				DoUpdates: clause.AssignmentColumns([]string{"score", "computed_at"}),
+			}).CreateInBatches(&rankings, rankingBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("computed_at < ?", computedAt).Delete(&models.WorkRanking{}).Error
+	})
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ReconversionRepository 再変換キャンペーンに関するデータベース操作を行うインターフェース
+type ReconversionRepository interface {
+	Create(ctx context.Context, campaign *models.ReconversionCampaign) error
+	FindByID(ctx context.Context, id uint) (*models.ReconversionCampaign, error)
+	Update(ctx context.Context, campaign *models.ReconversionCampaign) error
+	List(ctx context.Context, page, limit int) ([]models.ReconversionCampaign, int64, error)
+	ListRunning(ctx context.Context) ([]models.ReconversionCampaign, error)
+}
+
+// reconversionRepository ReconversionRepositoryの実装
+type reconversionRepository struct {
+	db *gorm.DB
+}
+
+// NewReconversionRepository ReconversionRepositoryを作成
+func NewReconversionRepository(db *gorm.DB) ReconversionRepository {
+	return &reconversionRepository{db: db}
+}
+
+// Create 新しいキャンペーンを作成
+func (r *reconversionRepository) Create(ctx context.Context, campaign *models.ReconversionCampaign) error {
+	return r.db.WithContext(ctx).Create(campaign).Error
+}
+
+// FindByID IDでキャンペーンを検索
+func (r *reconversionRepository) FindByID(ctx context.Context, id uint) (*models.ReconversionCampaign, error) {
+	var campaign models.ReconversionCampaign
+	if err := r.db.WithContext(ctx).First(&campaign, id).Error; err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// Update キャンペーンを更新
+func (r *reconversionRepository) Update(ctx context.Context, campaign *models.ReconversionCampaign) error {
+	return r.db.WithContext(ctx).Save(campaign).Error
+}
+
+// List キャンペーン一覧を取得
+func (r *reconversionRepository) List(ctx context.Context, page, limit int) ([]models.ReconversionCampaign, int64, error) {
+	var campaigns []models.ReconversionCampaign
+	var total int64
+
+	offset := (page - 1) * limit
+
+	// 合計数を取得
+	if err := r.db.WithContext(ctx).Model(&models.ReconversionCampaign{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// データを取得
+	if err := r.db.WithContext(ctx).Offset(offset).Limit(limit).Order("created_at DESC").
+		Find(&campaigns).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+
+	return campaigns, total, nil
+}
+
+// ListRunning 実行中のキャンペーンを作成順に取得
+func (r *reconversionRepository) ListRunning(ctx context.Context) ([]models.ReconversionCampaign, error) {
+	var campaigns []models.ReconversionCampaign
+	if err := r.db.WithContext(ctx).Where("status = ?", models.ReconversionStatusRunning).
+		Order("id ASC").
+		Find(&campaigns).Error; err != nil {
+		return nil, err
+	}
+	return campaigns, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepository リフレッシュトークンに関するデータベース操作を行うインターフェース
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	// Rotate 未使用のトークンを使用済みにして新しいトークンを作成（既に使用済み・無効の場合はgorm.ErrRecordNotFound）
+	Rotate(ctx context.Context, usedID uint, next *models.RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeByUser(ctx context.Context, userID uint) error
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// refreshTokenRepository RefreshTokenRepositoryの実装
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRefreshTokenRepository RefreshTokenRepositoryを作成
+func NewRefreshTokenRepository(db *gorm.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{db: db}
+}
+
+// Create リフレッシュトークンを作成
+func (r *refreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+// FindByHash トークンのハッシュでリフレッシュトークンを取得
+func (r *refreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate 未使用のトークンを使用済みにして新しいトークンを作成（同時に使われた場合は一方のみ成功する）
+func (r *refreshTokenRepository) Rotate(ctx context.Context, usedID uint, next *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", usedID).
+			UpdateColumn("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(next).Error
+	})
+}
+
+// RevokeFamily 同じログインで発行したリフレッシュトークンを全て無効にする
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		UpdateColumn("revoked_at", time.Now()).Error
+}
+
+// RevokeByUser ユーザーのリフレッシュトークンを全て無効にする
+func (r *refreshTokenRepository) RevokeByUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		UpdateColumn("revoked_at", time.Now()).Error
+}
+
+// DeleteExpired 指定日時より前に有効期限が切れたリフレッシュトークンを削除し、削除した件数を返す
+func (r *refreshTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&models.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
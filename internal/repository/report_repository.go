@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ReportRepository 通報と通報による非表示に関するデータベース操作を行うインターフェース
+type ReportRepository interface {
+	Create(ctx context.Context, report *models.Report) error
+	Exists(ctx context.Context, targetType string, targetID, reporterID uint) (bool, error)
+	CountSince(ctx context.Context, targetType string, targetID uint, since *time.Time) (int64, error)
+	ListByTarget(ctx context.Context, targetType string, targetID uint) ([]models.Report, error)
+	Hide(ctx context.Context, targetType string, targetID uint, hiddenAt time.Time) (bool, error)
+	Restore(ctx context.Context, targetType string, targetID uint, reviewedAt time.Time) error
+	ListHiddenWorks(ctx context.Context, page, limit int) ([]models.Work, int64, error)
+	ListHiddenComments(ctx context.Context, page, limit int) ([]models.Comment, int64, error)
+}
+
+// reportRepository ReportRepositoryの実装
+type reportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository ReportRepositoryを作成
+func NewReportRepository(db *gorm.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+// reportTarget 通報の対象のモデル
+func reportTarget(targetType string) (interface{}, error) {
+	switch targetType {
+	case models.ReportTargetWork:
+		return &models.Work{}, nil
+	case models.ReportTargetComment:
+		return &models.Comment{}, nil
+	default:
+		return nil, fmt.Errorf("無効な通報の対象です: %s", targetType)
+	}
+}
+
+// Create 新しい通報を作成
+func (r *reportRepository) Create(ctx context.Context, report *models.Report) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
+
+// Exists ユーザーが既に通報しているか確認
+func (r *reportRepository) Exists(ctx context.Context, targetType string, targetID, reporterID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Report{}).
+		Where("target_type = ? AND target_id = ? AND reporter_id = ?", targetType, targetID, reporterID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountSince 指定日時より後に通報したユーザー数を取得（sinceがnilの場合は全て）
+func (r *reportRepository) CountSince(ctx context.Context, targetType string, targetID uint, since *time.Time) (int64, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&models.Report{}).
+		Where("target_type = ? AND target_id = ?", targetType, targetID)
+	if since != nil {
+		query = query.Where("created_at > ?", *since)
+	}
+	if err := query.Distinct("reporter_id").Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListByTarget 対象への通報一覧を取得（新しい順）
+func (r *reportRepository) ListByTarget(ctx context.Context, targetType string, targetID uint) ([]models.Report, error) {
+	var reports []models.Report
+	if err := r.db.WithContext(ctx).
+		Where("target_type = ? AND target_id = ?", targetType, targetID).
+		Order("created_at DESC, id DESC").
+		Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// Hide 対象を非表示にする（既に非表示の場合はfalseを返す）
+func (r *reportRepository) Hide(ctx context.Context, targetType string, targetID uint, hiddenAt time.Time) (bool, error) {
+	target, err := reportTarget(targetType)
+	if err != nil {
+		return false, err
+	}
+	result := r.db.WithContext(ctx).Model(target).
+		Where("id = ? AND hidden_at IS NULL", targetID).
+		UpdateColumn("hidden_at", hiddenAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Restore 対象を再表示し、管理者が確認した日時を記録
+func (r *reportRepository) Restore(ctx context.Context, targetType string, targetID uint, reviewedAt time.Time) error {
+	target, err := reportTarget(targetType)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Model(target).Where("id = ?", targetID).
+		UpdateColumns(map[string]interface{}{"hidden_at": nil, "reviewed_at": reviewedAt}).Error
+}
+
+// ListHiddenWorks 非表示の作品一覧を取得（非表示になった新しい順）
+func (r *reportRepository) ListHiddenWorks(ctx context.Context, page, limit int) ([]models.Work, int64, error) {
+	var works []models.Work
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Work{}).Where("hidden_at IS NOT NULL")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("User").
+		Order("hidden_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&works).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return works, total, nil
+}
+
+// ListHiddenComments 非表示のコメント一覧を取得（非表示になった新しい順）
+func (r *reportRepository) ListHiddenComments(ctx context.Context, page, limit int) ([]models.Comment, int64, error) {
+	var comments []models.Comment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Comment{}).Where("hidden_at IS NOT NULL")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("User").
+		Order("hidden_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
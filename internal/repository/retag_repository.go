@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RetagRepository 一括タグ付けジョブに関するデータベース操作を行うインターフェース
+type RetagRepository interface {
+	Create(ctx context.Context, job *models.RetagJob) error
+	FindByID(ctx context.Context, id uint) (*models.RetagJob, error)
+	Update(ctx context.Context, job *models.RetagJob) error
+	List(ctx context.Context, page, limit int) ([]models.RetagJob, int64, error)
+	ListRunning(ctx context.Context) ([]models.RetagJob, error)
+	CountTargets(ctx context.Context, job *models.RetagJob) (int64, error)
+	ListTargets(ctx context.Context, job *models.RetagJob, afterID uint, limit int) ([]uint, error)
+	CountChanged(ctx context.Context, jobID uint) (int64, error)
+	ListChanged(ctx context.Context, jobID, afterID uint, limit int) ([]uint, error)
+	ApplyBatch(ctx context.Context, job *models.RetagJob, workIDs []uint) ([]uint, error)
+}
+
+// retagRepository RetagRepositoryの実装
+type retagRepository struct {
+	db *gorm.DB
+}
+
+// NewRetagRepository RetagRepositoryを作成
+func NewRetagRepository(db *gorm.DB) RetagRepository {
+	return &retagRepository{db: db}
+}
+
+// Create 新しいジョブを作成
+func (r *retagRepository) Create(ctx context.Context, job *models.RetagJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// FindByID IDでジョブを検索
+func (r *retagRepository) FindByID(ctx context.Context, id uint) (*models.RetagJob, error) {
+	var job models.RetagJob
+	if err := r.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Update ジョブを更新
+func (r *retagRepository) Update(ctx context.Context, job *models.RetagJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+// List ジョブ一覧を新しい順に取得
+func (r *retagRepository) List(ctx context.Context, page, limit int) ([]models.RetagJob, int64, error) {
+	var jobs []models.RetagJob
+	var total int64
+
+	offset := (page - 1) * limit
+
+	// 合計数を取得
+	if err := r.db.WithContext(ctx).Model(&models.RetagJob{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// データを取得
+	if err := r.db.WithContext(ctx).Offset(offset).Limit(limit).Order("created_at DESC, id DESC").
+		Find(&jobs).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+
+	return jobs, total, nil
+}
+
+// ListRunning 実行中のジョブを作成順に取得
+func (r *retagRepository) ListRunning(ctx context.Context) ([]models.RetagJob, error) {
+	var jobs []models.RetagJob
+	if err := r.db.WithContext(ctx).Where("status = ?", models.RetagStatusRunning).
+		Order("id ASC").
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// CountTargets ジョブの検索条件に一致する作品数を取得
+func (r *retagRepository) CountTargets(ctx context.Context, job *models.RetagJob) (int64, error) {
+	var count int64
+	if err := r.targets(ctx, job).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListTargets ジョブの検索条件に一致する作品のIDをID順に取得
+func (r *retagRepository) ListTargets(ctx context.Context, job *models.RetagJob, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	if err := r.targets(ctx, job).
+		Where("works.id > ?", afterID).
+		Order("works.id ASC").
+		Limit(limit).
+		Pluck("works.id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// targets ジョブの検索条件（作品一覧の絞り込みと同じ条件、非表示の作品も含む）
+func (r *retagRepository) targets(ctx context.Context, job *models.RetagJob) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.Work{})
+	if job.Search != "" {
+		query = query.Where("works.title LIKE ? OR works.description LIKE ?", "%"+job.Search+"%", "%"+job.Search+"%")
+	}
+	if job.FilterTag != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM work_tags JOIN tags ON tags.id = work_tags.tag_id WHERE work_tags.work_id = works.id AND tags.name = ?)", job.FilterTag)
+	}
+	if job.License != "" {
+		query = query.Where("works.license = ?", job.License)
+	}
+	if job.Language != "" {
+		query = query.Where("works.language = ?", job.Language)
+	}
+	if job.UserID != nil {
+		query = query.Where("works.user_id = ?", *job.UserID)
+	}
+	return query
+}
+
+// CountChanged ジョブでタグを変更した作品数を取得
+func (r *retagRepository) CountChanged(ctx context.Context, jobID uint) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.RetagJobWork{}).
+		Where("job_id = ?", jobID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListChanged ジョブでタグを変更した作品のIDをID順に取得
+func (r *retagRepository) ListChanged(ctx context.Context, jobID, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+	if err := r.db.WithContext(ctx).Model(&models.RetagJobWork{}).
+		Where("job_id = ? AND work_id > ?", jobID, afterID).
+		Order("work_id ASC").
+		Limit(limit).
+		Pluck("work_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ApplyBatch 作品にジョブのタグを追加・削除し、実際に変更した作品を記録してトランザクション内で返す
+func (r *retagRepository) ApplyBatch(ctx context.Context, job *models.RetagJob, workIDs []uint) ([]uint, error) {
+	if len(workIDs) == 0 {
+		return nil, nil
+	}
+
+	var changed []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tagged []uint
+		if err := tx.Table("work_tags").
+			Where("tag_id = ? AND work_id IN ?", job.TagID, workIDs).
+			Pluck("work_id", &tagged).Error; err != nil {
+			return err
+		}
+		hasTag := make(map[uint]bool, len(tagged))
+		for _, id := range tagged {
+			hasTag[id] = true
+		}
+
+		changed = nil
+		for _, id := range workIDs {
+			if hasTag[id] == (job.Action == models.RetagActionRemove) {
+				changed = append(changed, id)
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+
+		if job.Action == models.RetagActionAdd {
+			for _, id := range changed {
+				if err := tx.Exec("INSERT IGNORE INTO work_tags (work_id, tag_id) VALUES (?, ?)", id, job.TagID).Error; err != nil {
+					return err
+				}
+			}
+		} else if err := tx.Exec("DELETE FROM work_tags WHERE tag_id = ? AND work_id IN ?", job.TagID, changed).Error; err != nil {
+			return err
+		}
+
+		records := make([]models.RetagJobWork, 0, len(changed))
+		for _, id := range changed {
+			records = append(records, models.RetagJobWork{JobID: job.ID, WorkID: id})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&records).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
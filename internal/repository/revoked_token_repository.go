@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevokedTokenRepository 無効にしたアクセストークンに関するデータベース操作を行うインターフェース
+type RevokedTokenRepository interface {
+	Create(ctx context.Context, token *models.RevokedToken) error
+	Exists(ctx context.Context, jti string) (bool, error)
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// revokedTokenRepository RevokedTokenRepositoryの実装
+type revokedTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewRevokedTokenRepository RevokedTokenRepositoryを作成
+func NewRevokedTokenRepository(db *gorm.DB) RevokedTokenRepository {
+	return &revokedTokenRepository{db: db}
+}
+
+// Create 無効にしたトークンを記録（既に記録されている場合は何もしない）
+func (r *revokedTokenRepository) Create(ctx context.Context, token *models.RevokedToken) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+// Exists トークンが無効にされているか確認
+func (r *revokedTokenRepository) Exists(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeleteExpired 指定日時より前に有効期限が切れたトークンの記録を削除し、削除した件数を返す
+func (r *revokedTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&models.RevokedToken{})
+	return result.RowsAffected, result.Error
+}
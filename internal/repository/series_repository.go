@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SeriesRepository シリーズに関するデータベース操作を行うインターフェース
+type SeriesRepository interface {
+	Create(ctx context.Context, series *models.Series) error
+	FindByID(ctx context.Context, id uint) (*models.Series, error)
+	Update(ctx context.Context, series *models.Series) error
+	Delete(ctx context.Context, id uint) error
+	ListWorks(ctx context.Context, seriesID uint) ([]models.Work, error)
+	SetWorks(ctx context.Context, seriesID uint, workIDs []uint) error
+}
+
+// seriesRepository SeriesRepositoryの実装
+type seriesRepository struct {
+	db *gorm.DB
+}
+
+// NewSeriesRepository SeriesRepositoryを作成
+func NewSeriesRepository(db *gorm.DB) SeriesRepository {
+	return &seriesRepository{db: db}
+}
+
+// Create 新しいシリーズを作成
+func (r *seriesRepository) Create(ctx context.Context, series *models.Series) error {
+	return r.db.WithContext(ctx).Omit("User").Create(series).Error
+}
+
+// FindByID IDでシリーズを検索
+func (r *seriesRepository) FindByID(ctx context.Context, id uint) (*models.Series, error) {
+	var series models.Series
+	if err := r.db.WithContext(ctx).Preload("User").First(&series, id).Error; err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// Update シリーズを更新
+func (r *seriesRepository) Update(ctx context.Context, series *models.Series) error {
+	return r.db.WithContext(ctx).Omit("User").Save(series).Error
+}
+
+// Delete シリーズを削除し、作品との関連を外す
+func (r *seriesRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Work{}).Unscoped().Where("series_id = ?", id).
+			UpdateColumns(map[string]interface{}{"series_id": nil, "series_position": 0}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Series{}, id).Error
+	})
+}
+
+// ListWorks シリーズの作品を順番通りに取得
+func (r *seriesRepository) ListWorks(ctx context.Context, seriesID uint) ([]models.Work, error) {
+	var works []models.Work
+	if err := r.db.WithContext(ctx).
+		Where("series_id = ?", seriesID).
+		Preload("User").
+		Preload("Tags").
+		Order("series_position ASC, id ASC").
+		Find(&works).Error; err != nil {
+		return nil, err
+	}
+	return works, nil
+}
+
+// SetWorks シリーズの作品を指定した順番に置き換える
+func (r *seriesRepository) SetWorks(ctx context.Context, seriesID uint, workIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 削除済みの作品も含めて現在の関連を外す
+		if err := tx.Model(&models.Work{}).Unscoped().Where("series_id = ?", seriesID).
+			UpdateColumns(map[string]interface{}{"series_id": nil, "series_position": 0}).Error; err != nil {
+			return err
+		}
+		for i, workID := range workIDs {
+			if err := tx.Model(&models.Work{}).Where("id = ?", workID).
+				UpdateColumns(map[string]interface{}{"series_id": seriesID, "series_position": i + 1}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// SnapshotRepository 作品のスナップショット（キャプチャしたフレーム）に関するデータベース操作を行うインターフェース
+type SnapshotRepository interface {
+	Create(ctx context.Context, snapshot *models.WorkSnapshot) error
+	FindByID(ctx context.Context, id uint) (*models.WorkSnapshot, error)
+	ListByWork(ctx context.Context, workID uint) ([]models.WorkSnapshot, error)
+	CountByWork(ctx context.Context, workID uint) (int64, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// snapshotRepository SnapshotRepositoryの実装
+type snapshotRepository struct {
+	db *gorm.DB
+}
+
+// NewSnapshotRepository SnapshotRepositoryを作成
+func NewSnapshotRepository(db *gorm.DB) SnapshotRepository {
+	return &snapshotRepository{db: db}
+}
+
+// Create スナップショットを作成（画像は作成済みのものを関連付ける）
+func (r *snapshotRepository) Create(ctx context.Context, snapshot *models.WorkSnapshot) error {
+	return r.db.WithContext(ctx).Omit("Image", "User").Create(snapshot).Error
+}
+
+// FindByID IDでスナップショットを検索（画像と派生画像を含む）
+func (r *snapshotRepository) FindByID(ctx context.Context, id uint) (*models.WorkSnapshot, error) {
+	var snapshot models.WorkSnapshot
+	if err := r.db.WithContext(ctx).
+		Preload("Image").
+		Preload("Image.Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("User").
+		First(&snapshot, id).Error; err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// ListByWork 作品のスナップショットを新しい順に取得
+func (r *snapshotRepository) ListByWork(ctx context.Context, workID uint) ([]models.WorkSnapshot, error) {
+	var snapshots []models.WorkSnapshot
+	if err := r.db.WithContext(ctx).
+		Where("work_id = ?", workID).
+		Preload("Image").
+		Preload("Image.Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("User").
+		Order("created_at DESC, id DESC").
+		Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// CountByWork 作品のスナップショットの数を取得
+func (r *snapshotRepository) CountByWork(ctx context.Context, workID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.WorkSnapshot{}).
+		Where("work_id = ?", workID).
+		Count(&count).Error
+	return count, err
+}
+
+// Delete スナップショットを削除（画像は呼び出し側で削除する）
+func (r *snapshotRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.WorkSnapshot{}, id).Error
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SubscriptionRepository 有料プランの契約に関するデータベース操作を行うインターフェース
+type SubscriptionRepository interface {
+	FindByUser(ctx context.Context, userID uint) (*models.Subscription, error)
+	FindByStripeCustomer(ctx context.Context, customerID string) (*models.Subscription, error)
+	Save(ctx context.Context, subscription *models.Subscription) (bool, error)
+}
+
+// subscriptionRepository SubscriptionRepositoryの実装
+type subscriptionRepository struct {
+	db *gorm.DB
+}
+
+// NewSubscriptionRepository SubscriptionRepositoryを作成
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+// FindByUser ユーザーの契約を検索
+func (r *subscriptionRepository) FindByUser(ctx context.Context, userID uint) (*models.Subscription, error) {
+	var subscription models.Subscription
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// FindByStripeCustomer Stripeの顧客IDで契約を検索
+func (r *subscriptionRepository) FindByStripeCustomer(ctx context.Context, customerID string) (*models.Subscription, error) {
+	var subscription models.Subscription
+	if err := r.db.WithContext(ctx).Where("stripe_customer_id = ?", customerID).
+		Order("event_at DESC").First(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// Save ユーザーの契約を保存（保存済みの契約より古いイベントの場合は保存せずfalse）
+// Webhookは順不同・重複して届くため、ユーザーの行をロックしてイベントの作成日時を比べる。
+// プランの変更で古いサブスクリプションの終了が新しいサブスクリプションの作成より後に届いても、
+// 保存済みと別のサブスクリプションの有効でない状態では上書きしない。
+func (r *subscriptionRepository) Save(ctx context.Context, subscription *models.Subscription) (bool, error) {
+	saved := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.Subscription
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", subscription.UserID).First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil {
+			if existing.EventAt.After(subscription.EventAt) {
+				return nil
+			}
+			if !subscription.IsActive() && existing.StripeSubscriptionID != subscription.StripeSubscriptionID {
+				return nil
+			}
+			subscription.CreatedAt = existing.CreatedAt
+		}
+
+		// 別のユーザーに紐付いていた同じサブスクリプションは付け替える
+		if err := tx.Where("stripe_subscription_id = ? AND user_id <> ?", subscription.StripeSubscriptionID, subscription.UserID).
+			Delete(&models.Subscription{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(subscription).Error; err != nil {
+			return err
+		}
+		saved = true
+		return nil
+	})
+	return saved, err
+}
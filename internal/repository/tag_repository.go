@@ -1,23 +1,35 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TagRepository タグに関するデータベース操作を行うインターフェース
 type TagRepository interface {
-	FindOrCreate(name string) (*models.Tag, error)
-	List(search string, limit int) ([]models.Tag, error)
-	FindByID(id uint) (*models.Tag, error)
-	FindByName(name string) (*models.Tag, error)
-	AttachTagsToWork(workID uint, tagIDs []uint) error
-	DetachTagsFromWork(workID uint) error
-	GetTagsForWork(workID uint) ([]models.Tag, error)
+	FindOrCreate(ctx context.Context, name string) (*models.Tag, error)
+	List(ctx context.Context, search string, limit int) ([]models.Tag, error)
+	ListPopular(ctx context.Context, limit int) ([]TagSummary, error)
+	DeleteUnused(ctx context.Context, before time.Time, protected []string) ([]models.Tag, error)
+	FindByID(ctx context.Context, id uint) (*models.Tag, error)
+	FindByName(ctx context.Context, name string) (*models.Tag, error)
+	AttachTagsToWork(ctx context.Context, workID uint, tagIDs []uint) error
+	DetachTagsFromWork(ctx context.Context, workID uint) error
+	GetTagsForWork(ctx context.Context, workID uint) ([]models.Tag, error)
+}
+
+// TagSummary タグごとの作品数
+type TagSummary struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	WorksCount int64  `json:"works_count"`
 }
 
 // tagRepository TagRepositoryの実装
@@ -31,18 +43,18 @@ func NewTagRepository(db *gorm.DB) TagRepository {
 }
 
 // FindOrCreate タグを検索または作成
-func (r *tagRepository) FindOrCreate(name string) (*models.Tag, error) {
+func (r *tagRepository) FindOrCreate(ctx context.Context, name string) (*models.Tag, error) {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return nil, errors.New("タグ名は空にできません")
 	}
 
 	var tag models.Tag
-	if err := r.db.Where("name = ?", name).First(&tag).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&tag).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// タグが見つからない場合は新規作成
 			tag.Name = name
-			if err := r.db.Create(&tag).Error; err != nil {
+			if err := r.db.WithContext(ctx).Create(&tag).Error; err != nil {
 				return nil, err
 			}
 			return &tag, nil
@@ -53,9 +65,9 @@ func (r *tagRepository) FindOrCreate(name string) (*models.Tag, error) {
 }
 
 // List タグ一覧を取得
-func (r *tagRepository) List(search string, limit int) ([]models.Tag, error) {
+func (r *tagRepository) List(ctx context.Context, search string, limit int) ([]models.Tag, error) {
 	var tags []models.Tag
-	query := r.db.Model(&models.Tag{})
+	query := r.db.WithContext(ctx).Model(&models.Tag{})
 
 	if search != "" {
 		query = query.Where("name LIKE ?", "%"+search+"%")
@@ -71,34 +83,80 @@ func (r *tagRepository) List(search string, limit int) ([]models.Tag, error) {
 	return tags, nil
 }
 
+// ListPopular 公開中の作品の多いタグから順に取得
+func (r *tagRepository) ListPopular(ctx context.Context, limit int) ([]TagSummary, error) {
+	tags := []TagSummary{}
+	err := r.db.WithContext(ctx).Model(&models.Tag{}).
+		Select("tags.id, tags.name, COUNT(*) AS works_count").
+		Joins("JOIN work_tags ON work_tags.tag_id = tags.id").
+		Joins("JOIN works ON works.id = work_tags.work_id AND works.deleted_at IS NULL AND works.hidden_at IS NULL").
+		Group("tags.id, tags.name").
+		Order("works_count DESC, tags.name").
+		Limit(limit).
+		Scan(&tags).Error
+	return tags, err
+}
+
+// DeleteUnused beforeより前に作成され、どの作品（削除済みの作品を含む）にも付いていないタグを削除して返す
+// protectedのタグと、取り消せる一括タグ付けジョブのタグは削除しない
+func (r *tagRepository) DeleteUnused(ctx context.Context, before time.Time, protected []string) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 削除するまでの間に作品に付けられないようロックする
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("created_at < ?", before).
+			Where("NOT EXISTS (SELECT 1 FROM work_tags WHERE work_tags.tag_id = tags.id)").
+			Where("NOT EXISTS (SELECT 1 FROM retag_jobs WHERE retag_jobs.tag_id = tags.id AND retag_jobs.undone_by_id IS NULL)")
+		if len(protected) > 0 {
+			query = query.Where("name NOT IN ?", protected)
+		}
+		if err := query.Order("name ASC").Find(&tags).Error; err != nil {
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(tags))
+		for i := range tags {
+			ids[i] = tags[i].ID
+		}
+		return tx.Delete(&models.Tag{}, ids).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
 // FindByID IDでタグを検索
-func (r *tagRepository) FindByID(id uint) (*models.Tag, error) {
+func (r *tagRepository) FindByID(ctx context.Context, id uint) (*models.Tag, error) {
 	var tag models.Tag
-	if err := r.db.First(&tag, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&tag, id).Error; err != nil {
 		return nil, err
 	}
 	return &tag, nil
 }
 
 // FindByName 名前でタグを検索
-func (r *tagRepository) FindByName(name string) (*models.Tag, error) {
+func (r *tagRepository) FindByName(ctx context.Context, name string) (*models.Tag, error) {
 	var tag models.Tag
-	if err := r.db.Where("name = ?", name).First(&tag).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&tag).Error; err != nil {
 		return nil, err
 	}
 	return &tag, nil
 }
 
 // AttachTagsToWork 作品にタグを関連付け
-func (r *tagRepository) AttachTagsToWork(workID uint, tagIDs []uint) error {
+func (r *tagRepository) AttachTagsToWork(ctx context.Context, workID uint, tagIDs []uint) error {
 	// 既存のタグをすべて削除
-	if err := r.DetachTagsFromWork(workID); err != nil {
+	if err := r.DetachTagsFromWork(ctx, workID); err != nil {
 		return err
 	}
 
 	// 新しいタグを追加
 	for _, tagID := range tagIDs {
-		if err := r.db.Exec("INSERT INTO work_tags (work_id, tag_id) VALUES (?, ?)", workID, tagID).Error; err != nil {
+		if err := r.db.WithContext(ctx).Exec("INSERT INTO work_tags (work_id, tag_id) VALUES (?, ?)", workID, tagID).Error; err != nil {
 			return err
 		}
 	}
@@ -107,14 +165,14 @@ func (r *tagRepository) AttachTagsToWork(workID uint, tagIDs []uint) error {
 }
 
 // DetachTagsFromWork 作品からすべてのタグの関連付けを解除
-func (r *tagRepository) DetachTagsFromWork(workID uint) error {
-	return r.db.Exec("DELETE FROM work_tags WHERE work_id = ?", workID).Error
+func (r *tagRepository) DetachTagsFromWork(ctx context.Context, workID uint) error {
+	return r.db.WithContext(ctx).Exec("DELETE FROM work_tags WHERE work_id = ?", workID).Error
 }
 
 // GetTagsForWork 作品に関連付けられたタグを取得
-func (r *tagRepository) GetTagsForWork(workID uint) ([]models.Tag, error) {
+func (r *tagRepository) GetTagsForWork(ctx context.Context, workID uint) ([]models.Tag, error) {
 	var tags []models.Tag
-	if err := r.db.Model(&models.Tag{}).
+	if err := r.db.WithContext(ctx).Model(&models.Tag{}).
 		Joins("JOIN work_tags ON tags.id = work_tags.tag_id").
 		Where("work_tags.work_id = ?", workID).
 		Find(&tags).Error; err != nil {
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"errors"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
@@ -9,15 +10,16 @@ import (
 
 // TaskRepository タスクに関するデータベース操作を行うインターフェース
 type TaskRepository interface {
-	Create(task *models.Task) error
-	FindByID(id uint) (*models.Task, error)
-	Update(task *models.Task) error
-	Delete(id uint) error
-	ListByProject(projectID uint) ([]models.Task, error)
-	AddWork(taskID, workID uint) error
-	RemoveWork(taskID, workID uint) error
-	GetWorks(taskID uint, page, limit int) ([]models.Work, int64, error)
-	UpdateOrders(taskIDs []uint, orderIndices []int) error
+	Create(ctx context.Context, task *models.Task) error
+	FindByID(ctx context.Context, id uint) (*models.Task, error)
+	Update(ctx context.Context, task *models.Task) error
+	Delete(ctx context.Context, id uint) error
+	ListByProject(ctx context.Context, projectID uint) ([]models.Task, error)
+	AddWork(ctx context.Context, taskID, workID uint) error
+	RemoveWork(ctx context.Context, taskID, workID uint) error
+	HasWork(ctx context.Context, taskID, workID uint) (bool, error)
+	GetWorks(ctx context.Context, taskID uint, page, limit int) ([]models.Work, int64, error)
+	UpdateOrders(ctx context.Context, taskIDs []uint, orderIndices []int) error
 }
 
 // taskRepository TaskRepositoryの実装
@@ -31,34 +33,37 @@ func NewTaskRepository(db *gorm.DB) TaskRepository {
 }
 
 // Create 新しいタスクを作成
-func (r *taskRepository) Create(task *models.Task) error {
-	return r.db.Create(task).Error
+func (r *taskRepository) Create(ctx context.Context, task *models.Task) error {
+	if task.Version == 0 {
+		task.Version = 1
+	}
+	return r.db.WithContext(ctx).Create(task).Error
 }
 
 // FindByID IDでタスクを検索
-func (r *taskRepository) FindByID(id uint) (*models.Task, error) {
+func (r *taskRepository) FindByID(ctx context.Context, id uint) (*models.Task, error) {
 	var task models.Task
-	if err := r.db.First(&task, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&task, id).Error; err != nil {
 		return nil, err
 	}
 	return &task, nil
 }
 
-// Update タスク情報を更新
-func (r *taskRepository) Update(task *models.Task) error {
-	return r.db.Save(task).Error
+// Update タスク情報を更新（バージョンが一致しない場合は ErrVersionConflict）
+func (r *taskRepository) Update(ctx context.Context, task *models.Task) error {
+	return saveWithVersion(r.db.WithContext(ctx), task, &task.Version)
 }
 
 // Delete タスクを削除
-func (r *taskRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Task{}, id).Error
+func (r *taskRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Task{}, id).Error
 }
 
 // ListByProject プロジェクトのタスク一覧を取得
-func (r *taskRepository) ListByProject(projectID uint) ([]models.Task, error) {
+func (r *taskRepository) ListByProject(ctx context.Context, projectID uint) ([]models.Task, error) {
 	var tasks []models.Task
 
-	if err := r.db.Where("project_id = ?", projectID).
+	if err := r.db.WithContext(ctx).Where("project_id = ?", projectID).
 		Order("order_index ASC, created_at ASC").
 		Find(&tasks).Error; err != nil {
 		return nil, err
@@ -68,28 +73,38 @@ func (r *taskRepository) ListByProject(projectID uint) ([]models.Task, error) {
 }
 
 // AddWork 作品をタスクに追加
-func (r *taskRepository) AddWork(taskID, workID uint) error {
+func (r *taskRepository) AddWork(ctx context.Context, taskID, workID uint) error {
 	taskWork := models.TaskWork{
 		TaskID: taskID,
 		WorkID: workID,
 	}
 
-	return r.db.Create(&taskWork).Error
+	return r.db.WithContext(ctx).Create(&taskWork).Error
 }
 
 // RemoveWork 作品をタスクから削除
-func (r *taskRepository) RemoveWork(taskID, workID uint) error {
-	return r.db.Where("task_id = ? AND work_id = ?", taskID, workID).Delete(&models.TaskWork{}).Error
+func (r *taskRepository) RemoveWork(ctx context.Context, taskID, workID uint) error {
+	return r.db.WithContext(ctx).Where("task_id = ? AND work_id = ?", taskID, workID).Delete(&models.TaskWork{}).Error
+}
+
+// HasWork 作品がタスクに提出されているか確認
+func (r *taskRepository) HasWork(ctx context.Context, taskID, workID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.TaskWork{}).
+		Where("task_id = ? AND work_id = ?", taskID, workID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
 }
 
 // GetWorks タスクの作品一覧を取得
-func (r *taskRepository) GetWorks(taskID uint, page, limit int) ([]models.Work, int64, error) {
+func (r *taskRepository) GetWorks(ctx context.Context, taskID uint, page, limit int) ([]models.Work, int64, error) {
 	var works []models.Work
 	var total int64
 
 	offset := (page - 1) * limit
 
-	query := r.db.Model(&models.Work{}).
+	query := r.db.WithContext(ctx).Model(&models.Work{}).
 		Joins("JOIN task_works ON works.id = task_works.work_id").
 		Where("task_works.task_id = ?", taskID).
 		Preload("User").
@@ -106,22 +121,16 @@ func (r *taskRepository) GetWorks(taskID uint, page, limit int) ([]models.Work,
 		return nil, 0, err
 	}
 
-	// 各作品のいいね数とコメント数を取得
-	for i := range works {
-		r.db.Model(&models.Like{}).Where("work_id = ?", works[i].ID).Count(&works[i].LikesCount)
-		r.db.Model(&models.Comment{}).Where("work_id = ?", works[i].ID).Count(&works[i].CommentsCount)
-	}
-
 	return works, total, nil
 }
 
 // UpdateOrders タスクの表示順序を更新
-func (r *taskRepository) UpdateOrders(taskIDs []uint, orderIndices []int) error {
+func (r *taskRepository) UpdateOrders(ctx context.Context, taskIDs []uint, orderIndices []int) error {
 	if len(taskIDs) != len(orderIndices) {
 		return errors.New("タスクIDと順序インデックスの数が一致しません")
 	}
 
-	err := r.db.Transaction(func(tx *gorm.DB) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		for i, taskID := range taskIDs {
 			if err := tx.Model(&models.Task{}).
 				Where("id = ?", taskID).
@@ -1,6 +1,11 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 
 	"gorm.io/gorm"
@@ -8,11 +13,39 @@ import (
 
 // UserRepository ユーザーに関するデータベース操作を行うインターフェース
 type UserRepository interface {
-	Create(user *models.User) error
-	FindByID(id uint) (*models.User, error)
-	FindByEmail(email string) (*models.User, error)
-	Update(user *models.User) error
-	Delete(id uint) error
+	Create(ctx context.Context, user *models.User) error
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByInviteTokenHash(ctx context.Context, tokenHash string) (*models.User, error)
+	FindByEmailTokenHash(ctx context.Context, tokenHash string) (*models.User, error)
+	Update(ctx context.Context, user *models.User) error
+	Delete(ctx context.Context, id uint) error
+	AddReputation(ctx context.Context, userID uint, delta int) error
+	SetReputation(ctx context.Context, userID uint, reputation int) error
+	ListByReputation(ctx context.Context, page, limit int) ([]models.User, int64, error)
+	GetReputationSources(ctx context.Context, userID uint) (*ReputationSources, error)
+	CountDailyActivity(ctx context.Context, userID uint, since time.Time) ([]DailyActivityCount, error)
+	ListAdminIDs(ctx context.Context) ([]uint, error)
+	FindByHandle(ctx context.Context, handle string) (*models.User, error)
+	FindByPreviousHandle(ctx context.Context, handle string) (*models.User, error)
+	IsHandleTaken(ctx context.Context, handle string, userID uint) (bool, error)
+	ChangeHandle(ctx context.Context, userID uint, handle string) error
+	ListWithoutHandle(ctx context.Context, limit int) ([]models.User, error)
+	ListByNickname(ctx context.Context, nickname string, limit int) ([]models.User, error)
+}
+
+// ReputationSources レピュテーションの算出元となる集計値
+type ReputationSources struct {
+	LikesReceived       int64 `json:"likes_received"`
+	AcceptedSubmissions int64 `json:"accepted_submissions"`
+	ContestWins         int64 `json:"contest_wins"`
+}
+
+// DailyActivityCount 1日ごとの投稿数
+type DailyActivityCount struct {
+	Date     string // YYYY-MM-DD
+	Works    int64
+	Comments int64
 }
 
 // userRepository UserRepositoryの実装
@@ -26,34 +59,272 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 // Create 新しいユーザーを作成
-func (r *userRepository) Create(user *models.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
 }
 
 // FindByID IDでユーザーを検索
-func (r *userRepository) FindByID(id uint) (*models.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
 	var user models.User
-	if err := r.db.First(&user, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
 // FindByEmail メールアドレスでユーザーを検索
-func (r *userRepository) FindByEmail(email string) (*models.User, error) {
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByInviteTokenHash 招待トークンのハッシュで仮登録ユーザーを検索
+func (r *userRepository) FindByInviteTokenHash(ctx context.Context, tokenHash string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("pending = ? AND invite_token_hash = ?", true, tokenHash).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByEmailTokenHash メールアドレス変更の確認トークンのハッシュでユーザーを検索
+func (r *userRepository) FindByEmailTokenHash(ctx context.Context, tokenHash string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("email_token_hash = ?", tokenHash).First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
 // Update ユーザー情報を更新
-func (r *userRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+// レピュテーションはAddReputation・SetReputationで更新するため、古い値で上書きしない
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Omit("reputation").Save(user).Error
 }
 
 // Delete ユーザーを削除
-func (r *userRepository) Delete(id uint) error {
-	return r.db.Delete(&models.User{}, id).Error
+func (r *userRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.User{}, id).Error
+}
+
+// AddReputation レピュテーションを加算（負の値で減算、0未満にはしない）
+func (r *userRepository) AddReputation(ctx context.Context, userID uint, delta int) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).
+		Update("reputation", gorm.Expr("GREATEST(reputation + ?, 0)", delta)).Error
+}
+
+// SetReputation レピュテーションを設定
+func (r *userRepository) SetReputation(ctx context.Context, userID uint, reputation int) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).
+		Update("reputation", reputation).Error
+}
+
+// ListByReputation レピュテーション順にユーザー一覧を取得（一覧に表示しない設定のユーザーを除く）
+func (r *userRepository) ListByReputation(ctx context.Context, page, limit int) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Model(&models.User{}).Where("hide_from_search = ?", false)
+
+	// 合計数を取得
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// データを取得
+	if err := query.Offset(offset).Limit(limit).Order("reputation DESC, id ASC").
+		Find(&users).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// GetReputationSources レピュテーションの算出元を集計
+func (r *userRepository) GetReputationSources(ctx context.Context, userID uint) (*ReputationSources, error) {
+	var sources ReputationSources
+
+	// 受け取ったいいね数（自分の作品へのいいねはレピュテーションに加算しないため除く）
+	if err := r.db.WithContext(ctx).Model(&models.Like{}).
+		Joins("JOIN works ON works.id = likes.work_id").
+		Where("works.user_id = ? AND works.deleted_at IS NULL AND likes.user_id <> works.user_id", userID).
+		Count(&sources.LikesReceived).Error; err != nil {
+		return nil, err
+	}
+
+	// タスクに採用された作品数
+	if err := r.db.WithContext(ctx).Model(&models.TaskWork{}).
+		Joins("JOIN works ON works.id = task_works.work_id").
+		Where("works.user_id = ? AND works.deleted_at IS NULL", userID).
+		Count(&sources.AcceptedSubmissions).Error; err != nil {
+		return nil, err
+	}
+
+	// 優勝バッジ数
+	if err := r.db.WithContext(ctx).Model(&models.WorkBadge{}).
+		Joins("JOIN works ON works.id = work_badges.work_id").
+		Where("works.user_id = ? AND works.deleted_at IS NULL AND work_badges.type = ?", userID, models.BadgeTypeWinner).
+		Count(&sources.ContestWins).Error; err != nil {
+		return nil, err
+	}
+
+	return &sources, nil
+}
+
+// CountDailyActivity 指定日時以降に投稿した作品とコメントの数を日ごとに集計（日付の古い順）
+func (r *userRepository) CountDailyActivity(ctx context.Context, userID uint, since time.Time) ([]DailyActivityCount, error) {
+	type dayCount struct {
+		Day   string
+		Count int64
+	}
+	const day = "DATE_FORMAT(created_at, '%Y-%m-%d')"
+
+	// 作品数
+	var works []dayCount
+	if err := r.db.WithContext(ctx).Model(&models.Work{}).
+		Select(day+" AS day, COUNT(*) AS count").
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Group("day").
+		Scan(&works).Error; err != nil {
+		return nil, err
+	}
+
+	// コメント数
+	var comments []dayCount
+	if err := r.db.WithContext(ctx).Model(&models.Comment{}).
+		Select(day+" AS day, COUNT(*) AS count").
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Group("day").
+		Scan(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	// 日付ごとにまとめる
+	byDate := make(map[string]*DailyActivityCount)
+	entry := func(date string) *DailyActivityCount {
+		if byDate[date] == nil {
+			byDate[date] = &DailyActivityCount{Date: date}
+		}
+		return byDate[date]
+	}
+	for _, c := range works {
+		entry(c.Day).Works = c.Count
+	}
+	for _, c := range comments {
+		entry(c.Day).Comments = c.Count
+	}
+
+	counts := make([]DailyActivityCount, 0, len(byDate))
+	for _, c := range byDate {
+		counts = append(counts, *c)
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Date < counts[j].Date })
+	return counts, nil
+}
+
+// ListAdminIDs 管理者のユーザーID一覧を取得
+func (r *userRepository) ListAdminIDs(ctx context.Context) ([]uint, error) {
+	var ids []uint
+	if err := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("role = ?", models.UserRoleAdmin).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// FindByHandle 現在のハンドルでユーザーを検索
+func (r *userRepository) FindByHandle(ctx context.Context, handle string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("handle = ?", handle).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByPreviousHandle 変更前のハンドルでユーザーを検索
+func (r *userRepository) FindByPreviousHandle(ctx context.Context, handle string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).
+		Joins("JOIN handle_histories ON handle_histories.user_id = users.id").
+		Where("handle_histories.handle = ?", handle).
+		First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// IsHandleTaken ハンドルが他のユーザーに使われているか（変更前のハンドルを含む、削除済みのユーザーも含む）
+func (r *userRepository) IsHandleTaken(ctx context.Context, handle string, userID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Unscoped().Model(&models.User{}).
+		Where("handle = ? AND id <> ?", handle, userID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	if err := r.db.WithContext(ctx).Model(&models.HandleHistory{}).
+		Where("handle = ? AND user_id <> ?", handle, userID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ChangeHandle ハンドルを変更し、変更前のハンドルを履歴に残す
+// 自分の変更前のハンドルに戻す場合は履歴から取り除く
+func (r *userRepository) ChangeHandle(ctx context.Context, userID uint, handle string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var user models.User
+		if err := tx.Select("id", "handle").First(&user, userID).Error; err != nil {
+			return err
+		}
+		if user.Handle != nil && *user.Handle == handle {
+			return nil
+		}
+
+		if err := tx.Where("user_id = ? AND handle = ?", userID, handle).Delete(&models.HandleHistory{}).Error; err != nil {
+			return err
+		}
+		if user.Handle != nil {
+			if err := tx.Create(&models.HandleHistory{UserID: userID, Handle: *user.Handle}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).Update("handle", handle).Error
+	})
+}
+
+// ListWithoutHandle ハンドルが未設定のユーザーをID順に取得
+func (r *userRepository) ListWithoutHandle(ctx context.Context, limit int) ([]models.User, error) {
+	var users []models.User
+	if err := r.db.WithContext(ctx).
+		Where("handle IS NULL").
+		Order("id ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ListByNickname ニックネームが一致するユーザーをID順に取得（仮登録のユーザーを除く）
+func (r *userRepository) ListByNickname(ctx context.Context, nickname string, limit int) ([]models.User, error) {
+	var users []models.User
+	if err := r.db.WithContext(ctx).
+		Where("nickname = ? AND pending = ?", nickname, false).
+		Order("id ASC").
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
 }
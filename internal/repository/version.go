@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict 楽観的ロックのバージョンが一致しない（他の更新が先に保存された）
+var ErrVersionConflict = errors.New("他の更新と競合しました")
+
+// saveWithVersion バージョンが一致する場合のみ保存し、バージョンを1つ進める
+// 一致しない場合はバージョンを元に戻して ErrVersionConflict を返す
+func saveWithVersion(db *gorm.DB, value interface{}, version *uint) error {
+	current := *version
+	*version = current + 1
+
+	// Selectを指定して、更新件数が0の場合にSaveがINSERTへ切り替えないようにする
+	result := db.Select("*").Where("version = ?", current).Save(value)
+	if result.Error != nil {
+		*version = current
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		*version = current
+		return ErrVersionConflict
+	}
+	return nil
+}
@@ -1,29 +1,36 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/go-sql-driver/mysql"
 	"gorm.io/gorm"
 )
 
+// ErrDuplicateResponse 同じ投票回答が既に存在する（同時に投票された場合など）
+var ErrDuplicateResponse = errors.New("既に投票しています")
+
 // VoteRepository 投票に関するデータベース操作を行うインターフェース
 type VoteRepository interface {
-	Create(vote *models.Vote) error
-	FindByID(id uint) (*models.Vote, error)
-	Update(vote *models.Vote) error
-	Delete(id uint) error
-	ListByTask(taskID uint) ([]models.Vote, error)
-	CreateOption(option *models.VoteOption) error
-	FindOptionByID(id uint) (*models.VoteOption, error)
-	DeleteOption(id uint) error
-	GetOptions(voteID uint) ([]models.VoteOption, error)
-	AddResponse(response *models.VoteResponse) error
-	RemoveResponse(voteID, optionID, userID uint) error
-	GetUserResponses(voteID, userID uint) ([]models.VoteResponse, error)
-	GetOptionVoteCounts(voteID uint) (map[uint]int64, error)
-	CloseVote(voteID uint) error
+	Create(ctx context.Context, vote *models.Vote) error
+	FindByID(ctx context.Context, id uint) (*models.Vote, error)
+	Update(ctx context.Context, vote *models.Vote) error
+	Delete(ctx context.Context, id uint) error
+	ListByTask(ctx context.Context, taskID uint) ([]models.Vote, error)
+	CreateOption(ctx context.Context, option *models.VoteOption) error
+	FindOptionByID(ctx context.Context, id uint) (*models.VoteOption, error)
+	DeleteOption(ctx context.Context, id uint) error
+	GetOptions(ctx context.Context, voteID uint) ([]models.VoteOption, error)
+	AddResponse(ctx context.Context, response *models.VoteResponse) error
+	RemoveResponse(ctx context.Context, voteID, optionID, userID uint) error
+	GetUserResponses(ctx context.Context, voteID, userID uint) ([]models.VoteResponse, error)
+	GetOptionVoteCounts(ctx context.Context, voteID uint) (map[uint]int64, error)
+	CloseVote(ctx context.Context, voteID uint) (bool, error)
+	ListExpired(ctx context.Context, now time.Time) ([]models.Vote, error)
+	EachResponse(ctx context.Context, voteID uint, batchSize int, fn func(responses []models.VoteResponse) error) error
 }
 
 // voteRepository VoteRepositoryの実装
@@ -37,19 +44,19 @@ func NewVoteRepository(db *gorm.DB) VoteRepository {
 }
 
 // Create 新しい投票を作成
-func (r *voteRepository) Create(vote *models.Vote) error {
-	return r.db.Create(vote).Error
+func (r *voteRepository) Create(ctx context.Context, vote *models.Vote) error {
+	return r.db.WithContext(ctx).Create(vote).Error
 }
 
 // FindByID IDで投票を検索
-func (r *voteRepository) FindByID(id uint) (*models.Vote, error) {
+func (r *voteRepository) FindByID(ctx context.Context, id uint) (*models.Vote, error) {
 	var vote models.Vote
-	if err := r.db.Preload("Creator").Preload("Options").First(&vote, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Creator").Preload("Options").First(&vote, id).Error; err != nil {
 		return nil, err
 	}
 
 	// 各オプションの投票数を取得
-	voteCounts, err := r.GetOptionVoteCounts(id)
+	voteCounts, err := r.GetOptionVoteCounts(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -62,20 +69,20 @@ func (r *voteRepository) FindByID(id uint) (*models.Vote, error) {
 }
 
 // Update 投票情報を更新
-func (r *voteRepository) Update(vote *models.Vote) error {
-	return r.db.Save(vote).Error
+func (r *voteRepository) Update(ctx context.Context, vote *models.Vote) error {
+	return r.db.WithContext(ctx).Save(vote).Error
 }
 
 // Delete 投票を削除
-func (r *voteRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Vote{}, id).Error
+func (r *voteRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Vote{}, id).Error
 }
 
 // ListByTask タスクの投票一覧を取得
-func (r *voteRepository) ListByTask(taskID uint) ([]models.Vote, error) {
+func (r *voteRepository) ListByTask(ctx context.Context, taskID uint) ([]models.Vote, error) {
 	var votes []models.Vote
 
-	if err := r.db.Where("task_id = ?", taskID).
+	if err := r.db.WithContext(ctx).Where("task_id = ?", taskID).
 		Preload("Creator").
 		Preload("Options").
 		Order("created_at DESC").
@@ -85,7 +92,7 @@ func (r *voteRepository) ListByTask(taskID uint) ([]models.Vote, error) {
 
 	// 各投票の各オプションの投票数を取得
 	for i := range votes {
-		voteCounts, err := r.GetOptionVoteCounts(votes[i].ID)
+		voteCounts, err := r.GetOptionVoteCounts(ctx, votes[i].ID)
 		if err != nil {
 			return nil, err
 		}
@@ -99,20 +106,20 @@ func (r *voteRepository) ListByTask(taskID uint) ([]models.Vote, error) {
 }
 
 // CreateOption 新しい投票オプションを作成
-func (r *voteRepository) CreateOption(option *models.VoteOption) error {
-	return r.db.Create(option).Error
+func (r *voteRepository) CreateOption(ctx context.Context, option *models.VoteOption) error {
+	return r.db.WithContext(ctx).Create(option).Error
 }
 
 // FindOptionByID IDで投票オプションを検索
-func (r *voteRepository) FindOptionByID(id uint) (*models.VoteOption, error) {
+func (r *voteRepository) FindOptionByID(ctx context.Context, id uint) (*models.VoteOption, error) {
 	var option models.VoteOption
-	if err := r.db.Preload("Work").First(&option, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Work").First(&option, id).Error; err != nil {
 		return nil, err
 	}
 
 	// 投票数を取得
 	var count int64
-	if err := r.db.Model(&models.VoteResponse{}).
+	if err := r.db.WithContext(ctx).Model(&models.VoteResponse{}).
 		Where("option_id = ?", id).
 		Count(&count).Error; err != nil {
 		return nil, err
@@ -124,15 +131,15 @@ func (r *voteRepository) FindOptionByID(id uint) (*models.VoteOption, error) {
 }
 
 // DeleteOption 投票オプションを削除
-func (r *voteRepository) DeleteOption(id uint) error {
-	return r.db.Delete(&models.VoteOption{}, id).Error
+func (r *voteRepository) DeleteOption(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.VoteOption{}, id).Error
 }
 
 // GetOptions 投票のオプション一覧を取得
-func (r *voteRepository) GetOptions(voteID uint) ([]models.VoteOption, error) {
+func (r *voteRepository) GetOptions(ctx context.Context, voteID uint) ([]models.VoteOption, error) {
 	var options []models.VoteOption
 
-	if err := r.db.Where("vote_id = ?", voteID).
+	if err := r.db.WithContext(ctx).Where("vote_id = ?", voteID).
 		Preload("Work").
 		Order("created_at ASC").
 		Find(&options).Error; err != nil {
@@ -140,7 +147,7 @@ func (r *voteRepository) GetOptions(voteID uint) ([]models.VoteOption, error) {
 	}
 
 	// 各オプションの投票数を取得
-	voteCounts, err := r.GetOptionVoteCounts(voteID)
+	voteCounts, err := r.GetOptionVoteCounts(ctx, voteID)
 	if err != nil {
 		return nil, err
 	}
@@ -153,32 +160,50 @@ func (r *voteRepository) GetOptions(voteID uint) ([]models.VoteOption, error) {
 }
 
 // AddResponse 投票回答を追加
-func (r *voteRepository) AddResponse(response *models.VoteResponse) error {
-	// 投票が有効かどうか確認
-	var vote models.Vote
-	if err := r.db.Select("is_active").First(&vote, response.VoteID).Error; err != nil {
-		return err
-	}
+// 単一選択の投票では、同じトランザクション内でユーザーの既存の回答を置き換える
+// 一意制約に違反した場合は ErrDuplicateResponse を返す
+func (r *voteRepository) AddResponse(ctx context.Context, response *models.VoteResponse) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 投票が有効かどうか確認
+		var vote models.Vote
+		if err := tx.Select("is_active", "multi_select").First(&vote, response.VoteID).Error; err != nil {
+			return err
+		}
 
-	if !vote.IsActive {
-		return errors.New("この投票は既に終了しています")
-	}
+		if !vote.IsActive {
+			return errors.New("この投票は既に終了しています")
+		}
 
-	// 回答を追加
-	return r.db.Create(response).Error
+		response.SingleUserID = nil
+		if !vote.MultiSelect {
+			if err := tx.Where("vote_id = ? AND user_id = ?", response.VoteID, response.UserID).
+				Delete(&models.VoteResponse{}).Error; err != nil {
+				return err
+			}
+			userID := response.UserID
+			response.SingleUserID = &userID
+		}
+
+		// 回答を追加
+		return tx.Create(response).Error
+	})
+	if isDuplicateKey(err) {
+		return ErrDuplicateResponse
+	}
+	return err
 }
 
 // RemoveResponse 投票回答を削除
-func (r *voteRepository) RemoveResponse(voteID, optionID, userID uint) error {
-	return r.db.Where("vote_id = ? AND option_id = ? AND user_id = ?", voteID, optionID, userID).
+func (r *voteRepository) RemoveResponse(ctx context.Context, voteID, optionID, userID uint) error {
+	return r.db.WithContext(ctx).Where("vote_id = ? AND option_id = ? AND user_id = ?", voteID, optionID, userID).
 		Delete(&models.VoteResponse{}).Error
 }
 
 // GetUserResponses ユーザーの投票回答を取得
-func (r *voteRepository) GetUserResponses(voteID, userID uint) ([]models.VoteResponse, error) {
+func (r *voteRepository) GetUserResponses(ctx context.Context, voteID, userID uint) ([]models.VoteResponse, error) {
 	var responses []models.VoteResponse
 
-	if err := r.db.Where("vote_id = ? AND user_id = ?", voteID, userID).
+	if err := r.db.WithContext(ctx).Where("vote_id = ? AND user_id = ?", voteID, userID).
 		Find(&responses).Error; err != nil {
 		return nil, err
 	}
@@ -187,14 +212,14 @@ func (r *voteRepository) GetUserResponses(voteID, userID uint) ([]models.VoteRes
 }
 
 // GetOptionVoteCounts オプションごとの投票数を取得
-func (r *voteRepository) GetOptionVoteCounts(voteID uint) (map[uint]int64, error) {
+func (r *voteRepository) GetOptionVoteCounts(ctx context.Context, voteID uint) (map[uint]int64, error) {
 	type Result struct {
 		OptionID uint
 		Count    int64
 	}
 
 	var results []Result
-	err := r.db.Model(&models.VoteResponse{}).
+	err := r.db.WithContext(ctx).Model(&models.VoteResponse{}).
 		Select("option_id, count(*) as count").
 		Where("vote_id = ?", voteID).
 		Group("option_id").
@@ -212,13 +237,49 @@ func (r *voteRepository) GetOptionVoteCounts(voteID uint) (map[uint]int64, error
 	return counts, nil
 }
 
-// CloseVote 投票を終了
-func (r *voteRepository) CloseVote(voteID uint) error {
+// CloseVote 受付中の投票を終了（このリクエストで終了した場合はtrue、既に終了していた場合はfalse）
+// 手動での終了とスケジューラが重なっても、勝者の発表は1回だけ行われるようにする
+func (r *voteRepository) CloseVote(ctx context.Context, voteID uint) (bool, error) {
 	now := time.Now()
-	return r.db.Model(&models.Vote{}).
-		Where("id = ?", voteID).
+	result := r.db.WithContext(ctx).Model(&models.Vote{}).
+		Where("id = ? AND is_active = ?", voteID, true).
 		Updates(map[string]interface{}{
 			"is_active": false,
 			"closed_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ListExpired 締め切りを過ぎたが終了していない投票一覧を取得
+func (r *voteRepository) ListExpired(ctx context.Context, now time.Time) ([]models.Vote, error) {
+	var votes []models.Vote
+
+	if err := r.db.WithContext(ctx).Where("is_active = ? AND closes_at IS NOT NULL AND closes_at <= ?", true, now).
+		Order("closes_at ASC").
+		Find(&votes).Error; err != nil {
+		return nil, err
+	}
+
+	return votes, nil
+}
+
+// EachResponse 投票の回答を古い順にbatchSize件ずつ読み込んでfnに渡す
+func (r *voteRepository) EachResponse(ctx context.Context, voteID uint, batchSize int, fn func(responses []models.VoteResponse) error) error {
+	var responses []models.VoteResponse
+	return r.db.WithContext(ctx).
+		Where("vote_id = ?", voteID).
+		Preload("User").
+		Order("id ASC").
+		FindInBatches(&responses, batchSize, func(tx *gorm.DB, batch int) error {
+			return fn(responses)
 		}).Error
 }
+
+// isDuplicateKey MySQLの一意制約違反（Error 1062）か判定
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
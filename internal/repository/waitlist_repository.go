@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrAlreadyOnWaitlist 既に順番待ちに登録されている（同時に登録された場合など）
+var ErrAlreadyOnWaitlist = errors.New("このメールアドレスは既に順番待ちに登録されています")
+
+// WaitlistRepository 新規登録の順番待ちに関するデータベース操作を行うインターフェース
+type WaitlistRepository interface {
+	Create(ctx context.Context, entry *models.WaitlistEntry) error
+	FindByID(ctx context.Context, id uint) (*models.WaitlistEntry, error)
+	List(ctx context.Context, status string, page, limit int) ([]models.WaitlistEntry, int64, error)
+	Update(ctx context.Context, entry *models.WaitlistEntry) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// waitlistRepository WaitlistRepositoryの実装
+type waitlistRepository struct {
+	db *gorm.DB
+}
+
+// NewWaitlistRepository WaitlistRepositoryを作成
+func NewWaitlistRepository(db *gorm.DB) WaitlistRepository {
+	return &waitlistRepository{db: db}
+}
+
+// Create 順番待ちに登録（既に登録されている場合は ErrAlreadyOnWaitlist）
+func (r *waitlistRepository) Create(ctx context.Context, entry *models.WaitlistEntry) error {
+	err := r.db.WithContext(ctx).Create(entry).Error
+	if isDuplicateKey(err) {
+		return ErrAlreadyOnWaitlist
+	}
+	return err
+}
+
+// FindByID IDで順番待ちを検索
+func (r *waitlistRepository) FindByID(ctx context.Context, id uint) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	if err := r.db.WithContext(ctx).First(&entry, id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// List 順番待ち一覧を登録の早い順に取得（statusが空の場合は全て）
+func (r *waitlistRepository) List(ctx context.Context, status string, page, limit int) ([]models.WaitlistEntry, int64, error) {
+	var entries []models.WaitlistEntry
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.WaitlistEntry{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at ASC, id ASC").
+		Offset(offset).Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// Update 順番待ちを更新
+func (r *waitlistRepository) Update(ctx context.Context, entry *models.WaitlistEntry) error {
+	return r.db.WithContext(ctx).Save(entry).Error
+}
+
+// Delete 順番待ちを削除
+func (r *waitlistRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.WaitlistEntry{}, id).Error
+}
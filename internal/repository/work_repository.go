@@ -1,25 +1,79 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // WorkRepository 作品に関するデータベース操作を行うインターフェース
 type WorkRepository interface {
-	Create(work *models.Work) error
-	FindByID(id uint) (*models.Work, error)
-	Update(work *models.Work) error
-	Delete(id uint) error
-	List(page, limit int, search, tag string, userID *uint, sort string) ([]models.Work, int64, error)
-	IncrementViews(id uint) error
-	AddLike(userID, workID uint) error
-	RemoveLike(userID, workID uint) error
-	GetLikesCount(workID uint) (int, error)
-	HasLiked(userID, workID uint) (bool, error)
-	ListByUser(userID uint, page, limit int) ([]models.Work, int64, error)
+	Create(ctx context.Context, work *models.Work) error
+	FindByID(ctx context.Context, id uint) (*models.Work, error)
+	FindForAttribution(ctx context.Context, id uint) (*models.Work, error)
+	Update(ctx context.Context, work *models.Work) error
+	Delete(ctx context.Context, id uint) error
+	List(ctx context.Context, page, limit int, search, tag, license, language string, userID *uint, sort string) ([]models.Work, int64, error)
+	IncrementViews(ctx context.Context, id uint) error
+	AddLike(ctx context.Context, userID, workID uint) error
+	RemoveLike(ctx context.Context, userID, workID uint) error
+	GetLikesCount(ctx context.Context, workID uint) (int, error)
+	HasLiked(ctx context.Context, userID, workID uint) (bool, error)
+	ListLikedByUser(ctx context.Context, userID uint, page, limit int) ([]models.Like, int64, error)
+	ListLikers(ctx context.Context, workID uint, page, limit int) ([]models.Like, int64, error)
+	ListByUser(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, error)
+	ListByCollaborator(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, error)
+	CountCollaborative(ctx context.Context, userID uint) (int64, error)
+	CountOutdatedConversions(ctx context.Context, version string) (int64, error)
+	ListOutdatedConversions(ctx context.Context, version string, afterID uint, limit int) ([]models.Work, error)
+	UpdateConversion(ctx context.Context, work *models.Work) error
+	ListArchivable(ctx context.Context, before time.Time, minSize int, limit int) ([]models.Work, error)
+	Archive(ctx context.Context, work *models.Work, archivedAt time.Time) (bool, error)
+	Rehydrate(ctx context.Context, work *models.Work) (bool, error)
+	UpdateVideo(ctx context.Context, work *models.Work) error
+	StorageUsedByUser(ctx context.Context, userID, excludeWorkID uint) (int64, error)
+	ReconcileCounters(ctx context.Context) (int64, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]models.Work, error)
+	ApplyBulk(ctx context.Context, workIDs []uint, changes BulkWorkChanges) error
+	ListDeletedByUser(ctx context.Context, userID uint, since time.Time, page, limit int) ([]models.Work, int64, error)
+	FindDeletedByID(ctx context.Context, id uint) (*models.Work, error)
+	Restore(ctx context.Context, id uint) error
+	PurgeDeletedBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+	SetFeatured(ctx context.Context, id uint, from, until *time.Time) error
+	ListFeatured(ctx context.Context, now time.Time, page, limit int) ([]models.Work, int64, error)
+	ListTrending(ctx context.Context, since time.Time, limit int) ([]models.Work, error)
+	ListForSitemap(ctx context.Context, page, limit int) ([]models.Work, int64, error)
+	UpdateMadeAt(ctx context.Context, work *models.Work) error
+	UpdateThumbnail(ctx context.Context, work *models.Work) error
+	ListByEvent(ctx context.Context, event string, page, limit int) ([]models.Work, int64, error)
+	ListEvents(ctx context.Context, limit int) ([]WorkEventSummary, error)
+	ListLanguages(ctx context.Context) ([]WorkLanguageSummary, error)
+	ListWithLocation(ctx context.Context, event string, limit int) ([]models.Work, error)
+}
+
+// BulkWorkChanges 作品の一括操作の内容
+type BulkWorkChanges struct {
+	Delete       bool
+	AddTagIDs    []uint
+	RemoveTagIDs []uint
+	CodeShared   *bool
+}
+
+// WorkEventSummary イベントごとの作品数
+type WorkEventSummary struct {
+	Event        string    `json:"event"`
+	WorksCount   int64     `json:"works_count"`
+	LatestWorkAt time.Time `json:"latest_work_at"` // 最後に作品が投稿された日時
+}
+
+// WorkLanguageSummary 言語ごとの作品数
+type WorkLanguageSummary struct {
+	Language   string `json:"language"`
+	WorksCount int64  `json:"works_count"`
 }
 
 // workRepository WorkRepositoryの実装
@@ -33,48 +87,61 @@ func NewWorkRepository(db *gorm.DB) WorkRepository {
 }
 
 // Create 新しい作品を作成
-func (r *workRepository) Create(work *models.Work) error {
-	return r.db.Create(work).Error
+func (r *workRepository) Create(ctx context.Context, work *models.Work) error {
+	if work.Version == 0 {
+		work.Version = 1
+	}
+	return r.db.WithContext(ctx).Create(work).Error
 }
 
 // FindByID IDで作品を検索
-func (r *workRepository) FindByID(id uint) (*models.Work, error) {
+func (r *workRepository) FindByID(ctx context.Context, id uint) (*models.Work, error) {
 	var work models.Work
-	if err := r.db.Preload("User").Preload("Tags").First(&work, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("User").Preload("Tags").Preload("Badges").First(&work, id).Error; err != nil {
 		return nil, err
 	}
 
-	// いいね数とコメント数を取得
-	r.db.Model(&models.Like{}).Where("work_id = ?", work.ID).Count(&work.LikesCount)
-	r.db.Model(&models.Comment{}).Where("work_id = ?", work.ID).Count(&work.CommentsCount)
+	return &work, nil
+}
 
+// FindForAttribution クレジット表示に必要な項目のみで作品を検索（削除済みも含む）
+func (r *workRepository) FindForAttribution(ctx context.Context, id uint) (*models.Work, error) {
+	var work models.Work
+	if err := r.db.WithContext(ctx).Unscoped().
+		Select("id", "title", "user_id", "license", "forked_from_id", "deleted_at").
+		Preload("User", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		First(&work, id).Error; err != nil {
+		return nil, err
+	}
 	return &work, nil
 }
 
 // Update 作品情報を更新
-func (r *workRepository) Update(work *models.Work) error {
-	return r.db.Save(work).Error
+// カウンターは別途更新されるため上書きしない（バージョンが一致しない場合は ErrVersionConflict）
+func (r *workRepository) Update(ctx context.Context, work *models.Work) error {
+	return saveWithVersion(r.db.WithContext(ctx).Omit("likes_count", "comments_count"), work, &work.Version)
 }
 
 // Delete 作品を削除
-func (r *workRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Work{}, id).Error
+func (r *workRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Work{}, id).Error
 }
 
 // IncrementViews 閲覧数を増加
-func (r *workRepository) IncrementViews(id uint) error {
-	return r.db.Model(&models.Work{}).Where("id = ?", id).
+func (r *workRepository) IncrementViews(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&models.Work{}).Where("id = ?", id).
 		Update("views", gorm.Expr("views + 1")).Error
 }
 
 // List 作品一覧を取得
-func (r *workRepository) List(page, limit int, search, tag string, userID *uint, sort string) ([]models.Work, int64, error) {
+func (r *workRepository) List(ctx context.Context, page, limit int, search, tag, license, language string, userID *uint, sort string) ([]models.Work, int64, error) {
 	var works []models.Work
 	var total int64
 
 	offset := (page - 1) * limit
 
-	query := r.db.Model(&models.Work{}).Preload("User").Preload("Tags")
+	query := r.db.WithContext(ctx).Model(&models.Work{}).Preload("User").Preload("Tags").
+		Where("works.hidden_at IS NULL")
 
 	// 検索条件を適用
 	if search != "" {
@@ -88,6 +155,16 @@ func (r *workRepository) List(page, limit int, search, tag string, userID *uint,
 			Where("tags.name = ?", tag)
 	}
 
+	// ライセンスでフィルタリング
+	if license != "" {
+		query = query.Where("license = ?", license)
+	}
+
+	// 言語でフィルタリング
+	if language != "" {
+		query = query.Where("language = ?", language)
+	}
+
 	// ユーザーでフィルタリング
 	if userID != nil {
 		query = query.Where("user_id = ?", *userID)
@@ -101,9 +178,18 @@ func (r *workRepository) List(page, limit int, search, tag string, userID *uint,
 	// ソート順を適用
 	switch sort {
 	case "popular":
-		query = query.Order("views DESC, likes_count DESC")
+		// スケジューラが計算した人気スコアの順（まだ計算していない作品は新着順で後ろに並べる）
+		query = query.Joins("LEFT JOIN work_rankings ON work_rankings.work_id = works.id").
+			Order("COALESCE(work_rankings.score, 0) DESC, works.created_at DESC")
 	case "likes":
 		query = query.Order("likes_count DESC")
+	case "featured":
+		// ピックアップ中の作品を先頭に、それ以外は新着順
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "(featured_from IS NOT NULL AND featured_from <= ? AND (featured_until IS NULL OR featured_until > ?)) DESC, created_at DESC",
+			Vars:               []interface{}{time.Now(), time.Now()},
+			WithoutParentheses: true,
+		}})
 	default: // "newest"
 		query = query.Order("created_at DESC")
 	}
@@ -115,58 +201,117 @@ func (r *workRepository) List(page, limit int, search, tag string, userID *uint,
 		}
 	}
 
-	// 各作品のいいね数とコメント数を取得
-	for i := range works {
-		r.db.Model(&models.Like{}).Where("work_id = ?", works[i].ID).Count(&works[i].LikesCount)
-		r.db.Model(&models.Comment{}).Where("work_id = ?", works[i].ID).Count(&works[i].CommentsCount)
-	}
-
 	return works, total, nil
 }
 
-// AddLike いいねを追加
-func (r *workRepository) AddLike(userID, workID uint) error {
-	like := models.Like{
-		UserID: userID,
-		WorkID: workID,
-	}
-	return r.db.Create(&like).Error
+// AddLike いいねを追加し、いいね数を加算
+func (r *workRepository) AddLike(ctx context.Context, userID, workID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		like := models.Like{
+			UserID: userID,
+			WorkID: workID,
+		}
+		if err := tx.Create(&like).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Work{}).Where("id = ?", workID).
+			UpdateColumn("likes_count", gorm.Expr("likes_count + 1")).Error
+	})
 }
 
-// RemoveLike いいねを削除
-func (r *workRepository) RemoveLike(userID, workID uint) error {
-	return r.db.Where("user_id = ? AND work_id = ?", userID, workID).
-		Delete(&models.Like{}).Error
+// RemoveLike いいねを削除し、いいね数を減算
+func (r *workRepository) RemoveLike(ctx context.Context, userID, workID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ? AND work_id = ?", userID, workID).
+			Delete(&models.Like{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return tx.Model(&models.Work{}).Where("id = ?", workID).
+			UpdateColumn("likes_count", gorm.Expr("GREATEST(likes_count - 1, 0)")).Error
+	})
 }
 
 // GetLikesCount いいね数を取得
-func (r *workRepository) GetLikesCount(workID uint) (int, error) {
+func (r *workRepository) GetLikesCount(ctx context.Context, workID uint) (int, error) {
 	var count int64
-	if err := r.db.Model(&models.Like{}).Where("work_id = ?", workID).Count(&count).Error; err != nil {
+	if err := r.db.WithContext(ctx).Model(&models.Work{}).Select("likes_count").
+		Where("id = ?", workID).Scan(&count).Error; err != nil {
 		return 0, err
 	}
 	return int(count), nil
 }
 
 // HasLiked ユーザーがいいねしているか確認
-func (r *workRepository) HasLiked(userID, workID uint) (bool, error) {
+func (r *workRepository) HasLiked(ctx context.Context, userID, workID uint) (bool, error) {
 	var count int64
-	if err := r.db.Model(&models.Like{}).Where("user_id = ? AND work_id = ?", userID, workID).
+	if err := r.db.WithContext(ctx).Model(&models.Like{}).Where("user_id = ? AND work_id = ?", userID, workID).
 		Count(&count).Error; err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
 
+// ListLikedByUser ユーザーがいいねした作品をいいねの新しい順に取得（作品を読み込む、削除済み・非表示の作品を除く）
+func (r *workRepository) ListLikedByUser(ctx context.Context, userID uint, page, limit int) ([]models.Like, int64, error) {
+	var likes []models.Like
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Like{}).
+		Joins("JOIN works ON works.id = likes.work_id AND works.deleted_at IS NULL AND works.hidden_at IS NULL").
+		Where("likes.user_id = ?", userID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("Work.User").Preload("Work.Tags").
+		Order("likes.created_at DESC, likes.work_id DESC").
+		Offset(offset).Limit(limit).
+		Find(&likes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return likes, total, nil
+}
+
+// ListLikers 作品にいいねしたユーザーをいいねの新しい順に取得（ユーザーを読み込む、削除済みといいねを非公開にしているユーザーを除く）
+func (r *workRepository) ListLikers(ctx context.Context, workID uint, page, limit int) ([]models.Like, int64, error) {
+	var likes []models.Like
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Like{}).
+		Joins("JOIN users ON users.id = likes.user_id AND users.deleted_at IS NULL AND users.hide_likes = ?", false).
+		Where("likes.work_id = ?", workID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("User").
+		Order("likes.created_at DESC, likes.user_id DESC").
+		Offset(offset).Limit(limit).
+		Find(&likes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return likes, total, nil
+}
+
 // ListByUser ユーザーの作品一覧を取得
-func (r *workRepository) ListByUser(userID uint, page, limit int) ([]models.Work, int64, error) {
+func (r *workRepository) ListByUser(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, error) {
 	var works []models.Work
 	var total int64
 
 	offset := (page - 1) * limit
 
-	query := r.db.Model(&models.Work{}).
-		Where("user_id = ?", userID).
+	query := r.db.WithContext(ctx).Model(&models.Work{}).
+		Where("user_id = ? AND hidden_at IS NULL", userID).
 		Preload("User").
 		Preload("Tags")
 
@@ -181,11 +326,462 @@ func (r *workRepository) ListByUser(userID uint, page, limit int) ([]models.Work
 		return nil, 0, err
 	}
 
-	// 各作品のいいね数とコメント数を取得
-	for i := range works {
-		r.db.Model(&models.Like{}).Where("work_id = ?", works[i].ID).Count(&works[i].LikesCount)
-		r.db.Model(&models.Comment{}).Where("work_id = ?", works[i].ID).Count(&works[i].CommentsCount)
+	return works, total, nil
+}
+
+// ListByCollaborator ユーザーが共同編集者として参加している作品一覧を取得（新しい順、承認済みのみ、非表示の作品を除く）
+func (r *workRepository) ListByCollaborator(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, error) {
+	var works []models.Work
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Work{}).
+		Joins("JOIN work_collaborators ON work_collaborators.work_id = works.id AND work_collaborators.user_id = ? AND work_collaborators.accepted_at IS NOT NULL", userID).
+		Where("works.hidden_at IS NULL")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("User").Preload("Tags").
+		Order("works.created_at DESC, works.id DESC").
+		Offset(offset).Limit(limit).
+		Find(&works).Error; err != nil {
+		return nil, 0, err
 	}
 
 	return works, total, nil
 }
+
+// CountCollaborative ユーザーが作成者か共同編集者として関わった、承認済みの共同編集者がいる作品の数を取得（非表示の作品を除く）
+func (r *workRepository) CountCollaborative(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Work{}).
+		Where("works.hidden_at IS NULL").
+		Where("EXISTS (SELECT 1 FROM work_collaborators WHERE work_collaborators.work_id = works.id AND work_collaborators.accepted_at IS NOT NULL AND (works.user_id = ? OR work_collaborators.user_id = ?))", userID, userID).
+		Count(&count).Error
+	return count, err
+}
+
+// CountOutdatedConversions 指定バージョン以外で変換された作品数を取得（コードをストレージに移した作品を除く）
+func (r *workRepository) CountOutdatedConversions(ctx context.Context, version string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Work{}).
+		Where("converter_version <> ? OR converter_version IS NULL", version).
+		Where("archived_at IS NULL").
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListOutdatedConversions 指定バージョン以外で変換された作品をID順に取得（コードをストレージに移した作品を除く）
+func (r *workRepository) ListOutdatedConversions(ctx context.Context, version string, afterID uint, limit int) ([]models.Work, error) {
+	var works []models.Work
+	if err := r.db.WithContext(ctx).Select("id", "pde_content", "converter_version").
+		Where("converter_version <> ? OR converter_version IS NULL", version).
+		Where("archived_at IS NULL").
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&works).Error; err != nil {
+		return nil, err
+	}
+	return works, nil
+}
+
+// UpdateConversion 変換結果と検証結果のみを更新
+func (r *workRepository) UpdateConversion(ctx context.Context, work *models.Work) error {
+	return r.db.WithContext(ctx).Model(&models.Work{}).
+		Where("id = ?", work.ID).
+		Updates(map[string]interface{}{
+			"js_content":           work.JSContent,
+			"converter_version":    work.ConverterVersion,
+			"js_validation_status": work.JSValidationStatus,
+			"js_validation_issues": work.JSValidationIssues,
+			"converted_at":         work.ConvertedAt,
+			"conversion_ms":        work.ConversionMs,
+			"conversion_cached":    work.ConversionCached,
+		}).Error
+}
+
+// ListArchivable beforeより前から更新されておらず、PDEとJSの合計がminSizeバイト以上の作品をID順に取得
+func (r *workRepository) ListArchivable(ctx context.Context, before time.Time, minSize int, limit int) ([]models.Work, error) {
+	var works []models.Work
+	if err := r.db.WithContext(ctx).Select("id", "pde_content", "js_content", "updated_at").
+		Where("archived_at IS NULL AND updated_at < ?", before).
+		Where("LENGTH(pde_content) + COALESCE(LENGTH(js_content), 0) >= ?", minSize).
+		Order("id ASC").
+		Limit(limit).
+		Find(&works).Error; err != nil {
+		return nil, err
+	}
+	return works, nil
+}
+
+// Archive 作品のPDEとJSを空にしてストレージに移したことを記録（取得後に更新された場合は何もせずfalseを返す）
+// 更新日時は変えない
+func (r *workRepository) Archive(ctx context.Context, work *models.Work, archivedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Work{}).
+		Where("id = ? AND archived_at IS NULL AND updated_at = ?", work.ID, work.UpdatedAt).
+		UpdateColumns(map[string]interface{}{
+			"pde_content": "",
+			"js_content":  "",
+			"archived_at": archivedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// Rehydrate ストレージから戻したPDEとJSを書き込む（既に戻されている場合は何もせずfalseを返す）
+// 更新日時は変えない
+func (r *workRepository) Rehydrate(ctx context.Context, work *models.Work) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Work{}).
+		Where("id = ? AND archived_at IS NOT NULL", work.ID).
+		UpdateColumns(map[string]interface{}{
+			"pde_content": work.PDEContent,
+			"js_content":  work.JSContent,
+			"archived_at": nil,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateVideo デモ動画の項目のみを更新
+func (r *workRepository) UpdateVideo(ctx context.Context, work *models.Work) error {
+	return r.db.WithContext(ctx).Model(&models.Work{}).
+		Where("id = ?", work.ID).
+		Updates(map[string]interface{}{
+			"video_asset_id": work.VideoAssetID,
+			"video_url":      work.VideoURL,
+			"video_status":   work.VideoStatus,
+		}).Error
+}
+
+// StorageUsedByUser ユーザーの作品が使用しているストレージ容量（バイト）を取得
+// excludeWorkIDを指定した場合はその作品を除いて集計する
+func (r *workRepository) StorageUsedByUser(ctx context.Context, userID, excludeWorkID uint) (int64, error) {
+	var used int64
+	query := r.db.WithContext(ctx).Model(&models.Work{}).
+		Select("COALESCE(SUM(LENGTH(pde_content) + LENGTH(js_content)), 0)").
+		Where("user_id = ?", userID)
+	if excludeWorkID != 0 {
+		query = query.Where("id <> ?", excludeWorkID)
+	}
+	if err := query.Scan(&used).Error; err != nil {
+		return 0, err
+	}
+	return used, nil
+}
+
+// ReconcileCounters いいね数とコメント数を実データから再集計し、ずれていた作品数を返す
+func (r *workRepository) ReconcileCounters(ctx context.Context) (int64, error) {
+	likes := "(SELECT COUNT(*) FROM likes WHERE likes.work_id = works.id)"
+	comments := "(SELECT COUNT(*) FROM comments WHERE comments.work_id = works.id AND comments.deleted_at IS NULL)"
+
+	result := r.db.WithContext(ctx).Exec(
+		"UPDATE works SET likes_count = " + likes + ", comments_count = " + comments +
+			" WHERE deleted_at IS NULL AND (likes_count <> " + likes + " OR comments_count <> " + comments + ")",
+	)
+	return result.RowsAffected, result.Error
+}
+
+// FindByIDs 複数のIDで作品を検索（関連データは読み込まない）
+func (r *workRepository) FindByIDs(ctx context.Context, ids []uint) ([]models.Work, error) {
+	var works []models.Work
+	if len(ids) == 0 {
+		return works, nil
+	}
+	if err := r.db.WithContext(ctx).Select("id", "user_id").
+		Where("id IN ?", ids).
+		Find(&works).Error; err != nil {
+		return nil, err
+	}
+	return works, nil
+}
+
+// ApplyBulk 複数の作品に一括操作をトランザクション内で適用
+func (r *workRepository) ApplyBulk(ctx context.Context, workIDs []uint, changes BulkWorkChanges) error {
+	if len(workIDs) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if changes.Delete {
+			return tx.Where("id IN ?", workIDs).Delete(&models.Work{}).Error
+		}
+
+		for _, workID := range workIDs {
+			for _, tagID := range changes.AddTagIDs {
+				if err := tx.Exec("INSERT IGNORE INTO work_tags (work_id, tag_id) VALUES (?, ?)", workID, tagID).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(changes.RemoveTagIDs) > 0 {
+			if err := tx.Exec("DELETE FROM work_tags WHERE work_id IN ? AND tag_id IN ?", workIDs, changes.RemoveTagIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		if changes.CodeShared != nil {
+			if err := tx.Model(&models.Work{}).Where("id IN ?", workIDs).
+				Update("code_shared", *changes.CodeShared).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ListDeletedByUser ユーザーがsince以降に削除した作品の一覧を取得
+func (r *workRepository) ListDeletedByUser(ctx context.Context, userID uint, since time.Time, page, limit int) ([]models.Work, int64, error) {
+	var works []models.Work
+	var total int64
+
+	offset := (page - 1) * limit
+
+	query := r.db.WithContext(ctx).Unscoped().Model(&models.Work{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL AND deleted_at >= ?", userID, since)
+
+	// 合計数を取得
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// データを取得
+	if err := query.Preload("Tags").
+		Order("deleted_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&works).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+
+	return works, total, nil
+}
+
+// FindDeletedByID 削除済みの作品をIDで検索
+func (r *workRepository) FindDeletedByID(ctx context.Context, id uint) (*models.Work, error) {
+	var work models.Work
+	if err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		First(&work, id).Error; err != nil {
+		return nil, err
+	}
+	return &work, nil
+}
+
+// Restore 削除済みの作品を復元
+func (r *workRepository) Restore(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&models.Work{}).
+		Where("id = ?", id).
+		UpdateColumn("deleted_at", nil).Error
+}
+
+// PurgeDeletedBefore before より前に削除された作品と関連データを完全に削除し、削除した作品数を返す
+func (r *workRepository) PurgeDeletedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	var ids []uint
+	if err := r.db.WithContext(ctx).Unscoped().Model(&models.Work{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM work_tags WHERE work_id IN ?", ids).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("work_id IN ?", ids).Delete(&models.Like{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("work_id IN ?", ids).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("work_id IN ?", ids).Delete(&models.TaskWork{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("work_id IN ?", ids).Delete(&models.WorkBadge{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("work_id IN ?", ids).Delete(&models.WorkAnnotation{}).Error; err != nil {
+			return err
+		}
+		// 投票の選択肢は履歴として残し、作品との関連のみ外す
+		if err := tx.Model(&models.VoteOption{}).Where("work_id IN ?", ids).
+			UpdateColumn("work_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&models.Work{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(ids)), nil
+}
+
+// SetFeatured ピックアップ期間を設定（fromがnilの場合はピックアップを解除）
+func (r *workRepository) SetFeatured(ctx context.Context, id uint, from, until *time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Work{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"featured_from": from, "featured_until": until}).Error
+}
+
+// ListFeatured 指定日時にピックアップ中の作品一覧を取得（ピックアップ開始の新しい順）
+func (r *workRepository) ListFeatured(ctx context.Context, now time.Time, page, limit int) ([]models.Work, int64, error) {
+	var works []models.Work
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Work{}).
+		Where("featured_from IS NOT NULL AND featured_from <= ?", now).
+		Where("featured_until IS NULL OR featured_until > ?", now).
+		Where("hidden_at IS NULL")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("User").Preload("Tags").
+		Order("featured_from DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&works).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return works, total, nil
+}
+
+// ListTrending since以降にいいねの多かった作品から順に取得（非表示の作品を除く）
+func (r *workRepository) ListTrending(ctx context.Context, since time.Time, limit int) ([]models.Work, error) {
+	var works []models.Work
+	if err := r.db.WithContext(ctx).Model(&models.Work{}).Preload("User").Preload("Tags").
+		Joins("JOIN likes ON likes.work_id = works.id AND likes.created_at >= ?", since).
+		Where("works.hidden_at IS NULL").
+		Group("works.id").
+		Order("COUNT(*) DESC, works.likes_count DESC, works.id DESC").
+		Limit(limit).
+		Find(&works).Error; err != nil {
+		return nil, err
+	}
+	return works, nil
+}
+
+// ListForSitemap サイトマップに載せる作品のIDと更新日時をID順に取得（非表示の作品を除く）
+func (r *workRepository) ListForSitemap(ctx context.Context, page, limit int) ([]models.Work, int64, error) {
+	var works []models.Work
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Work{}).Where("hidden_at IS NULL")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Select("id", "updated_at").
+		Order("id ASC").
+		Offset(offset).Limit(limit).
+		Find(&works).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return works, total, nil
+}
+
+// UpdateMadeAt 制作したイベント・場所の項目のみを更新
+func (r *workRepository) UpdateMadeAt(ctx context.Context, work *models.Work) error {
+	return r.db.WithContext(ctx).Model(&models.Work{}).
+		Where("id = ?", work.ID).
+		Updates(map[string]interface{}{
+			"event":         work.Event,
+			"location_name": work.LocationName,
+			"latitude":      work.Latitude,
+			"longitude":     work.Longitude,
+		}).Error
+}
+
+// UpdateThumbnail 作品のサムネイルのみを更新（バージョンは進めない）
+func (r *workRepository) UpdateThumbnail(ctx context.Context, work *models.Work) error {
+	return r.db.WithContext(ctx).Model(&models.Work{}).
+		Where("id = ?", work.ID).
+		Updates(map[string]interface{}{
+			"thumbnail_url":      work.ThumbnailURL,
+			"thumbnail_type":     work.ThumbnailType,
+			"thumbnail_blurhash": work.ThumbnailBlurhash,
+		}).Error
+}
+
+// ListByEvent イベントで制作された作品一覧を取得（新しい順、非表示の作品を除く）
+func (r *workRepository) ListByEvent(ctx context.Context, event string, page, limit int) ([]models.Work, int64, error) {
+	var works []models.Work
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Work{}).
+		Where("event = ?", event).
+		Where("hidden_at IS NULL")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("User").Preload("Tags").
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&works).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return works, total, nil
+}
+
+// ListEvents 作品が登録されているイベントを最後に投稿された順に取得
+func (r *workRepository) ListEvents(ctx context.Context, limit int) ([]WorkEventSummary, error) {
+	events := []WorkEventSummary{}
+	err := r.db.WithContext(ctx).Model(&models.Work{}).
+		Select("event, COUNT(*) AS works_count, MAX(created_at) AS latest_work_at").
+		Where("event <> '' AND hidden_at IS NULL").
+		Group("event").
+		Order("latest_work_at DESC").
+		Limit(limit).
+		Scan(&events).Error
+	return events, err
+}
+
+// ListLanguages 作品の言語を作品数の多い順に取得（言語が判定できなかった作品を除く）
+func (r *workRepository) ListLanguages(ctx context.Context) ([]WorkLanguageSummary, error) {
+	languages := []WorkLanguageSummary{}
+	err := r.db.WithContext(ctx).Model(&models.Work{}).
+		Select("language, COUNT(*) AS works_count").
+		Where("language <> '' AND hidden_at IS NULL").
+		Group("language").
+		Order("works_count DESC, language").
+		Scan(&languages).Error
+	return languages, err
+}
+
+// ListWithLocation 制作した場所の座標がある作品を新しい順に取得（eventを指定した場合はそのイベントのみ、非表示の作品を除く）
+func (r *workRepository) ListWithLocation(ctx context.Context, event string, limit int) ([]models.Work, error) {
+	var works []models.Work
+
+	query := r.db.WithContext(ctx).Model(&models.Work{}).
+		Where("latitude IS NOT NULL AND longitude IS NOT NULL").
+		Where("hidden_at IS NULL")
+	if event != "" {
+		query = query.Where("event = ?", event)
+	}
+
+	if err := query.Preload("User").
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&works).Error; err != nil {
+		return nil, err
+	}
+
+	return works, nil
+}
@@ -0,0 +1,432 @@
+package routes
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/controllers"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository/memory"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+
+	"gorm.io/gorm"
+)
+
+// Repositories アプリケーションで使用するリポジトリ
+type Repositories struct {
+	User          repository.UserRepository
+	Work          repository.WorkRepository
+	Tag           repository.TagRepository
+	Comment       repository.CommentRepository
+	Project       repository.ProjectRepository
+	Task          repository.TaskRepository
+	Vote          repository.VoteRepository
+	Activity      repository.ActivityRepository
+	Notification  repository.NotificationRepository
+	Badge         repository.BadgeRepository
+	Message       repository.MessageRepository
+	Conversion    repository.ConversionRepository
+	Reconversion  repository.ReconversionRepository
+	Retag         repository.RetagRepository
+	Critique      repository.CritiqueRepository
+	Ranking       repository.RankingRepository
+	Purge         repository.PurgeRepository
+	AuditLog      repository.AuditLogRepository
+	ConversionJob repository.ConversionJobRepository
+	InvocationLog repository.InvocationLogRepository
+	Image         repository.ImageRepository
+	Asset         repository.AssetRepository
+	Annotation    repository.AnnotationRepository
+	Series        repository.SeriesRepository
+	Report        repository.ReportRepository
+	IPBlock       repository.IPBlockRepository
+	Event         repository.EventRepository
+	Organization  repository.OrganizationRepository
+	Collaborator  repository.CollaboratorRepository
+	Snapshot      repository.SnapshotRepository
+	InviteCode    repository.InviteCodeRepository
+	Waitlist      repository.WaitlistRepository
+	Subscription  repository.SubscriptionRepository
+	OAuthProvider repository.OAuthProviderRepository
+	LinkPreview   repository.LinkPreviewRepository
+	RefreshToken  repository.RefreshTokenRepository
+	RevokedToken  repository.RevokedTokenRepository
+	PersonalToken repository.PersonalTokenRepository
+}
+
+// NewRepositories 全てのリポジトリを作成
+func NewRepositories(db *gorm.DB) *Repositories {
+	return &Repositories{
+		User:          repository.NewUserRepository(db),
+		Work:          repository.NewWorkRepository(db),
+		Tag:           repository.NewTagRepository(db),
+		Comment:       repository.NewCommentRepository(db),
+		Project:       repository.NewProjectRepository(db),
+		Task:          repository.NewTaskRepository(db),
+		Vote:          repository.NewVoteRepository(db),
+		Activity:      repository.NewActivityRepository(db),
+		Notification:  repository.NewNotificationRepository(db),
+		Badge:         repository.NewBadgeRepository(db),
+		Message:       repository.NewMessageRepository(db),
+		Conversion:    repository.NewConversionRepository(db),
+		Reconversion:  repository.NewReconversionRepository(db),
+		Retag:         repository.NewRetagRepository(db),
+		Critique:      repository.NewCritiqueRepository(db),
+		Ranking:       repository.NewRankingRepository(db),
+		ConversionJob: repository.NewConversionJobRepository(db),
+		InvocationLog: repository.NewInvocationLogRepository(db),
+		Image:         repository.NewImageRepository(db),
+		Asset:         repository.NewAssetRepository(db),
+		Annotation:    repository.NewAnnotationRepository(db),
+		Series:        repository.NewSeriesRepository(db),
+		Report:        repository.NewReportRepository(db),
+		Purge:         repository.NewPurgeRepository(db),
+		AuditLog:      repository.NewAuditLogRepository(db),
+		IPBlock:       repository.NewIPBlockRepository(db),
+		Event:         repository.NewEventRepository(db),
+		Organization:  repository.NewOrganizationRepository(db),
+		Collaborator:  repository.NewCollaboratorRepository(db),
+		Snapshot:      repository.NewSnapshotRepository(db),
+		InviteCode:    repository.NewInviteCodeRepository(db),
+		Waitlist:      repository.NewWaitlistRepository(db),
+		Subscription:  repository.NewSubscriptionRepository(db),
+		OAuthProvider: repository.NewOAuthProviderRepository(db),
+		LinkPreview:   repository.NewLinkPreviewRepository(db),
+		RefreshToken:  repository.NewRefreshTokenRepository(db),
+		RevokedToken:  repository.NewRevokedTokenRepository(db),
+		PersonalToken: repository.NewPersonalTokenRepository(db),
+	}
+}
+
+// NewMemoryRepositories インメモリの全てのリポジトリを作成（seedがtrueの場合はデモデータを登録）
+func NewMemoryRepositories(ctx context.Context, seed bool) (*Repositories, error) {
+	store := memory.NewStore()
+	if seed {
+		if err := memory.Seed(ctx, store); err != nil {
+			return nil, fmt.Errorf("デモデータの登録に失敗しました: %v", err)
+		}
+	}
+
+	return &Repositories{
+		User:          memory.NewUserRepository(store),
+		Work:          memory.NewWorkRepository(store),
+		Tag:           memory.NewTagRepository(store),
+		Comment:       memory.NewCommentRepository(store),
+		Project:       memory.NewProjectRepository(store),
+		Task:          memory.NewTaskRepository(store),
+		Vote:          memory.NewVoteRepository(store),
+		Activity:      memory.NewActivityRepository(store),
+		Notification:  memory.NewNotificationRepository(store),
+		Badge:         memory.NewBadgeRepository(store),
+		Message:       memory.NewMessageRepository(store),
+		Conversion:    memory.NewConversionRepository(store),
+		Reconversion:  memory.NewReconversionRepository(store),
+		Retag:         memory.NewRetagRepository(store),
+		Critique:      memory.NewCritiqueRepository(store),
+		Ranking:       memory.NewRankingRepository(store),
+		ConversionJob: memory.NewConversionJobRepository(store),
+		InvocationLog: memory.NewInvocationLogRepository(store),
+		Image:         memory.NewImageRepository(store),
+		Asset:         memory.NewAssetRepository(store),
+		Annotation:    memory.NewAnnotationRepository(store),
+		Series:        memory.NewSeriesRepository(store),
+		Report:        memory.NewReportRepository(store),
+		Purge:         memory.NewPurgeRepository(store),
+		AuditLog:      memory.NewAuditLogRepository(store),
+		IPBlock:       memory.NewIPBlockRepository(store),
+		Event:         memory.NewEventRepository(store),
+		Organization:  memory.NewOrganizationRepository(store),
+		Collaborator:  memory.NewCollaboratorRepository(store),
+		Snapshot:      memory.NewSnapshotRepository(store),
+		InviteCode:    memory.NewInviteCodeRepository(store),
+		Waitlist:      memory.NewWaitlistRepository(store),
+		Subscription:  memory.NewSubscriptionRepository(store),
+		OAuthProvider: memory.NewOAuthProviderRepository(store),
+		LinkPreview:   memory.NewLinkPreviewRepository(store),
+		RefreshToken:  memory.NewRefreshTokenRepository(store),
+		RevokedToken:  memory.NewRevokedTokenRepository(store),
+		PersonalToken: memory.NewPersonalTokenRepository(store),
+	}, nil
+}
+
+// Services アプリケーションで使用するサービス
+type Services struct {
+	Maintenance     services.MaintenanceService
+	Blocklist       services.BlocklistService
+	Cloudinary      services.CloudinaryService // 未設定の場合はnil
+	Storage         services.StorageService
+	Image           services.ImageService
+	HTTPClients     services.HTTPClientFactory
+	Fetcher         services.URLFetcher
+	Lambda          services.LambdaService
+	ConversionQueue services.ConversionQueue
+	Invocation      services.InvocationAnalyticsService
+	Mail            services.MailService
+	MailTemplate    services.MailTemplateService
+	Reputation      services.ReputationService
+	Registration    services.RegistrationService
+	AgeGate         services.AgeGateService
+	Auth            services.AuthService
+	PersonalToken   services.PersonalTokenService
+	OAuth           services.OAuthService
+	Limit           services.LimitService
+	Billing         services.BillingService
+	ConversionQuota services.ConversionQuotaService
+	JSValidation    services.JSValidationService
+	StorageQuota    services.StorageQuotaService
+	Sitemap         services.SitemapService
+	Player          services.PlayerService
+	ConversionJob   services.ConversionJobService
+	Public          services.PublicService
+	Home            services.HomeService
+	Work            services.WorkService
+	WorkImport      services.WorkImportService
+	Tag             services.TagService
+	Comment         services.CommentService
+	LinkPreview     services.LinkPreviewService
+	Annotation      services.AnnotationService
+	Series          services.SeriesService
+	Collaborator    services.CollaboratorService
+	Snapshot        services.SnapshotService
+	Report          services.ReportService
+	User            services.UserService
+	Project         services.ProjectService
+	Roster          services.RosterService
+	Event           services.EventService
+	Organization    services.OrganizationService
+	Calendar        services.CalendarService
+	Task            services.TaskService
+	Notification    services.NotificationService
+	Message         services.MessageService
+	Reconversion    services.ReconversionService
+	Retag           services.RetagService
+	Critique        services.CritiqueService
+	Asset           services.AssetService
+	Video           services.VideoService
+	Vote            services.VoteService
+	Archive         services.ArchiveService
+	Ranking         services.RankingService
+	Purge           services.PurgeService
+	Backup          services.BackupService // DB_DRIVER=memoryの場合はnil
+	Health          services.HealthService
+}
+
+// NewServices 全てのサービスを依存関係の順に作成
+// dbがnilの場合（DB_DRIVER=memory）はバックアップとデータベースのヘルスチェックを作成しない
+func NewServices(cfg *config.Config, db *gorm.DB, repos *Repositories) (*Services, error) {
+	// 外部サービスが停止している間は呼び出しを遮断し、待たずに失敗させる
+	lambdaBreaker := services.NewCircuitBreaker("Lambda", cfg.Circuit, 0, services.IsLambdaUnavailable)
+	cloudinaryBreaker := services.NewCircuitBreaker("Cloudinary", cfg.Circuit, cfg.Cloudinary.Timeout, nil)
+
+	s := &Services{
+		Maintenance: services.NewMaintenanceService(cfg),
+		HTTPClients: services.NewHTTPClientFactory(cfg),
+	}
+	s.Fetcher = services.NewURLFetcher(s.HTTPClients, cfg)
+	s.Lambda = services.NewCircuitBreakerLambdaService(services.NewLambdaService(repos.InvocationLog, s.HTTPClients, cfg), lambdaBreaker)
+
+	// Cloudinaryサービスを作成（設定されている場合のみ）
+	if cfg.Cloudinary.CloudName != "" {
+		cld, err := services.NewCloudinaryService(s.HTTPClients, cfg)
+		if err != nil {
+			log.Printf("Cloudinaryサービスの作成に失敗しました: %v", err)
+		} else {
+			s.Cloudinary = services.NewCircuitBreakerCloudinaryService(cld, cloudinaryBreaker)
+		}
+	}
+
+	// アップロード種別ごとの画像変換の手順を作成
+	image, err := services.NewImageService(repos.Image, s.Cloudinary, cfg)
+	if err != nil {
+		return nil, err
+	}
+	s.Image = image
+
+	// アセットの保存先を作成
+	storage, err := services.NewStorageService(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("ストレージの初期化に失敗しました: %v", err)
+	}
+	s.Storage = storage
+
+	// ストレージに移した作品のコードは、作品を取得した時点で戻す
+	repos.Work = services.NewArchivingWorkRepository(repos.Work, s.Storage, cfg)
+
+	// メールの送信方法を作成
+	mailer, err := services.NewMailService(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("メール送信の初期化に失敗しました: %v", err)
+	}
+	s.Mail = mailer
+	s.MailTemplate, err = services.NewMailTemplateService(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.Blocklist = services.NewBlocklistService(repos.IPBlock, cfg)
+	s.Reputation = services.NewReputationService(repos.User, cfg)
+	s.Registration = services.NewRegistrationService(repos.InviteCode, repos.Waitlist, repos.User, s.Mail, s.MailTemplate, cfg)
+	s.Auth = services.NewAuthService(repos.User, repos.RefreshToken, repos.RevokedToken, s.Mail, s.MailTemplate, s.Registration, cfg)
+	s.PersonalToken = services.NewPersonalTokenService(repos.PersonalToken, repos.User)
+	s.OAuth = services.NewOAuthService(repos.OAuthProvider, repos.User, s.Auth, s.Registration, s.HTTPClients, cfg)
+	s.AgeGate = services.NewAgeGateService(repos.User, repos.Project, cfg)
+	s.ConversionQueue = services.NewConversionQueue(s.Lambda, cfg)
+	s.Invocation = services.NewInvocationAnalyticsService(repos.InvocationLog, cfg)
+	s.Limit = services.NewLimitService(repos.Subscription, repos.Project, repos.Work, repos.Conversion, cfg)
+	s.Billing = services.NewBillingService(repos.Subscription, cfg)
+	s.ConversionQuota = services.NewConversionQuotaService(repos.Conversion, s.Limit, cfg)
+	s.JSValidation = services.NewJSValidationService(cfg)
+	s.StorageQuota = services.NewStorageQuotaService(repos.Work, s.Limit, cfg)
+	s.Sitemap = services.NewSitemapService(repos.Work, cfg)
+	s.Player = services.NewPlayerService(repos.Work, cfg)
+	s.Notification = services.NewNotificationService(repos.Notification)
+	s.ConversionJob = services.NewConversionJobService(repos.ConversionJob, repos.Work, s.Lambda, s.ConversionQueue, s.JSValidation, s.Notification, cfg)
+	s.Work = services.NewWorkService(repos.Work, repos.Tag, s.Lambda, s.ConversionQueue, repos.Task, repos.Project, repos.Series, repos.Collaborator, s.Reputation, s.ConversionQuota, s.JSValidation, s.StorageQuota, s.Sitemap, s.ConversionJob, s.AgeGate, cfg)
+	s.WorkImport = services.NewWorkImportService(s.Work, s.Fetcher, cfg)
+	s.Tag = services.NewTagService(repos.Tag, cfg)
+	s.Public = services.NewPublicService(repos.Work, repos.Tag, cfg)
+	s.Home = services.NewHomeService(repos.Work, repos.Project, repos.Tag, repos.Collaborator, cfg)
+	s.LinkPreview = services.NewLinkPreviewService(repos.LinkPreview, s.Fetcher, cfg)
+	s.Comment = services.NewCommentService(repos.Comment, repos.Work, repos.Project, s.Notification, s.LinkPreview)
+	s.Annotation = services.NewAnnotationService(repos.Annotation, repos.Work)
+	s.Series = services.NewSeriesService(repos.Series, repos.Work)
+	s.Collaborator = services.NewCollaboratorService(repos.Collaborator, repos.Work, repos.User, s.Notification)
+	s.User = services.NewUserService(repos.User, repos.Work, repos.Project, s.Image)
+	s.Project = services.NewProjectService(repos.Project, repos.Task, repos.Vote, repos.Activity, repos.Organization, s.Reputation, s.Limit, cfg)
+	s.Roster = services.NewRosterService(repos.Project, repos.User, s.Mail, s.MailTemplate, cfg)
+	s.Event = services.NewEventService(repos.Event, repos.Work, repos.Project, s.Reputation, cfg)
+	s.Organization = services.NewOrganizationService(repos.Organization, repos.User, s.Reputation, cfg)
+	s.Calendar = services.NewCalendarService(repos.Project, repos.Task, cfg)
+	s.Task = services.NewTaskService(repos.Task, repos.Project, repos.Work, s.Reputation)
+	s.Critique = services.NewCritiqueService(repos.Critique, repos.Task, repos.Project, repos.Work, repos.Vote)
+	s.Message = services.NewMessageService(repos.Message, repos.Project, repos.User, s.Notification, s.AgeGate)
+	s.Reconversion = services.NewReconversionService(repos.Reconversion, repos.Work, s.Lambda, s.ConversionQueue, s.JSValidation)
+	s.Retag = services.NewRetagService(repos.Retag, repos.Tag)
+	s.Asset = services.NewAssetService(repos.Asset, repos.Work, s.Storage, s.Image, cfg)
+	s.Snapshot = services.NewSnapshotService(repos.Snapshot, repos.Work, repos.Collaborator, s.Image)
+	s.Video = services.NewVideoService(repos.Work, repos.Asset, s.Storage, cfg)
+	s.Report = services.NewReportService(repos.Report, repos.Work, repos.Comment, repos.User, s.Notification, cfg)
+	s.Purge = services.NewPurgeService(repos.Purge, repos.AuditLog, s.Storage, s.Cloudinary, s.Sitemap, cfg)
+	s.Vote = services.NewVoteService(repos.Vote, repos.Task, repos.Project, repos.Work, repos.Activity, repos.Badge, s.Notification, s.Reputation, cfg)
+	s.Archive = services.NewArchiveService(repos.Work, s.Storage, cfg)
+	s.Ranking = services.NewRankingService(repos.Ranking, cfg)
+	if db != nil {
+		s.Backup = services.NewBackupService(db, s.Storage, cfg)
+	}
+
+	// ヘルスチェックで確認する依存サービスを登録
+	s.Health = services.NewHealthService()
+	if db != nil {
+		s.Health.Register("database", func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		})
+	}
+	s.Health.Register("storage", s.Storage.Ping)
+	s.Health.Register("lambda", s.Lambda.Ping)
+	s.Health.RegisterCircuitBreaker("lambda", lambdaBreaker)
+	if s.Cloudinary != nil {
+		s.Health.Register("cloudinary", s.Cloudinary.Ping)
+		s.Health.RegisterCircuitBreaker("cloudinary", cloudinaryBreaker)
+	}
+
+	return s, nil
+}
+
+// Controllers アプリケーションで使用するコントローラー
+type Controllers struct {
+	Auth         *controllers.AuthController
+	Token        *controllers.PersonalTokenController
+	OAuth        *controllers.OAuthController
+	Registration *controllers.RegistrationController
+	AgeGate      *controllers.AgeGateController
+	Work         *controllers.WorkController
+	Tag          *controllers.TagController
+	Comment      *controllers.CommentController
+	Annotation   *controllers.AnnotationController
+	Series       *controllers.SeriesController
+	Collaborator *controllers.CollaboratorController
+	Snapshot     *controllers.SnapshotController
+	Sitemap      *controllers.SitemapController
+	Player       *controllers.PlayerController
+	Public       *controllers.PublicController
+	Home         *controllers.HomeController
+	Report       *controllers.ReportController
+	Purge        *controllers.PurgeController
+	User         *controllers.UserController
+	Health       *controllers.HealthController
+	Project      *controllers.ProjectController
+	Calendar     *controllers.CalendarController
+	Task         *controllers.TaskController
+	Vote         *controllers.VoteController
+	Contest      *controllers.ContestController
+	Event        *controllers.EventController
+	Organization *controllers.OrganizationController
+	Billing      *controllers.BillingController
+	Notification *controllers.NotificationController
+	Message      *controllers.MessageController
+	Reconversion *controllers.ReconversionController
+	Retag        *controllers.RetagController
+	Critique     *controllers.CritiqueController
+	Maintenance  *controllers.MaintenanceController
+	MailTemplate *controllers.MailTemplateController
+	Blocklist    *controllers.BlocklistController
+	Upload       *controllers.UploadController
+	Image        *controllers.ImageController
+	Asset        *controllers.AssetController
+	Static       *controllers.StaticController // ローカルストレージの場合のみ
+}
+
+// NewControllers 全てのコントローラーを作成
+func NewControllers(cfg *config.Config, s *Services) *Controllers {
+	c := &Controllers{
+		Auth:         controllers.NewAuthController(s.Auth),
+		Token:        controllers.NewPersonalTokenController(s.PersonalToken),
+		OAuth:        controllers.NewOAuthController(s.OAuth, s.Auth, cfg.IsProduction()),
+		Registration: controllers.NewRegistrationController(s.Registration),
+		AgeGate:      controllers.NewAgeGateController(s.AgeGate),
+		Work:         controllers.NewWorkController(s.Work, s.WorkImport, s.ConversionQuota, s.ConversionJob, s.Image, s.Video),
+		Tag:          controllers.NewTagController(s.Tag),
+		Comment:      controllers.NewCommentController(s.Comment),
+		Annotation:   controllers.NewAnnotationController(s.Annotation),
+		Series:       controllers.NewSeriesController(s.Series),
+		Collaborator: controllers.NewCollaboratorController(s.Collaborator),
+		Snapshot:     controllers.NewSnapshotController(s.Snapshot),
+		Sitemap:      controllers.NewSitemapController(s.Sitemap),
+		Player:       controllers.NewPlayerController(s.Player),
+		Public:       controllers.NewPublicController(s.Public, cfg.PublicAPI.CacheTTL),
+		Home:         controllers.NewHomeController(s.Home, cfg.Home.CacheTTL),
+		Report:       controllers.NewReportController(s.Report),
+		Purge:        controllers.NewPurgeController(s.Purge),
+		User:         controllers.NewUserController(s.User, s.Reputation, s.StorageQuota, s.ConversionQuota),
+		Health:       controllers.NewHealthController(s.Health, s.HTTPClients),
+		Project:      controllers.NewProjectController(s.Project, s.Roster),
+		Calendar:     controllers.NewCalendarController(s.Calendar),
+		Task:         controllers.NewTaskController(s.Task),
+		Vote:         controllers.NewVoteController(s.Vote),
+		Contest:      controllers.NewContestController(s.Project, s.Task),
+		Event:        controllers.NewEventController(s.Event),
+		Organization: controllers.NewOrganizationController(s.Organization, s.Project),
+		Billing:      controllers.NewBillingController(s.Billing, s.Limit),
+		Notification: controllers.NewNotificationController(s.Notification),
+		Message:      controllers.NewMessageController(s.Message),
+		Reconversion: controllers.NewReconversionController(s.Reconversion, s.ConversionQueue, s.Invocation),
+		Retag:        controllers.NewRetagController(s.Retag),
+		Critique:     controllers.NewCritiqueController(s.Critique),
+		Maintenance:  controllers.NewMaintenanceController(s.Maintenance),
+		MailTemplate: controllers.NewMailTemplateController(s.MailTemplate),
+		Blocklist:    controllers.NewBlocklistController(s.Blocklist),
+		Upload:       controllers.NewUploadController(s.Asset),
+		Image:        controllers.NewImageController(s.Image),
+		Asset:        controllers.NewAssetController(s.Asset),
+	}
+	if cfg.Storage.Provider == "" || cfg.Storage.Provider == "local" {
+		c.Static = controllers.NewStaticController(cfg.Storage.LocalDir)
+	}
+	return c
+}
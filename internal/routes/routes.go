@@ -1,173 +1,461 @@
 package routes
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/config"
-	"github.com/SketchShifter/sketchshifter_backend/internal/controllers"
 	"github.com/SketchShifter/sketchshifter_backend/internal/middlewares"
-	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
-	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+	"github.com/SketchShifter/sketchshifter_backend/internal/scheduler"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+// blocklistExemptPrefixes ブロックリストと国による制限の対象外にするパス
+// 管理者APIは制限を解除できなくならないように、Webhookは外部サービス（Stripe）からの通知を受け取れるように対象外にする
+var blocklistExemptPrefixes = []string{"/api/v1/admin", "/api/v2/admin", "/api/v1/webhooks", "/api/v2/webhooks"}
+
+// routeTimeouts 通常より長いタイムアウトを使うルート（パスの末尾とタイムアウト）
+// エクスポートはレスポンスを少しずつ書き出し、アップロードは大きなリクエストを受け取るため、SERVER_REQUEST_TIMEOUTでは足りない
+func routeTimeouts(cfg *config.Config) map[string]time.Duration {
+	return map[string]time.Duration{
+		// コメント・プロジェクトのメンバー・講評・投票結果のエクスポート
+		"/export": cfg.Server.ExportTimeout,
+		// 作品のファイル・動画・スナップショットの画像・アバター・名簿・アセット（tus）のアップロード
+		"/works/upload":                    cfg.Server.UploadTimeout,
+		"/works/:id/video":                 cfg.Server.UploadTimeout,
+		"/snapshots/:snapshotID/thumbnail": cfg.Server.UploadTimeout,
+		"/users/me/avatar":                 cfg.Server.UploadTimeout,
+		"/roster/import":                   cfg.Server.UploadTimeout,
+		"/uploads/:id":                     cfg.Server.UploadTimeout,
+	}
+}
+
 // SetupRouter ルーターを設定
 func SetupRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 	// Ginルーターを作成
 	r := gin.Default()
 
+	// リバースプロキシ経由の場合、信頼するプロキシのX-Forwarded-ForからクライアントのIPアドレスを取得する
+	if len(cfg.Access.TrustedProxies) > 0 {
+		if err := r.SetTrustedProxies(cfg.Access.TrustedProxies); err != nil {
+			log.Fatalf("信頼するプロキシの設定に失敗しました: %v", err)
+		}
+	}
+
 	// ミドルウェアを設定
+	// レスポンス形式はAPIバージョンで決まる（v1は従来の形式、v2はエンベロープ形式）
+	r.Use(middlewares.ResponseFormatMiddleware(cfg.Server.ResponseFormat))
+	r.Use(middlewares.APIVersionMiddleware(cfg.Server.APIV1Sunset))
 	r.Use(middlewares.ErrorMiddleware())
 	r.Use(middlewares.CORSMiddleware())
+	r.Use(middlewares.TimeoutMiddleware(cfg.Server.RequestTimeout, routeTimeouts(cfg)))
+
+	// リポジトリ・サービス・コントローラーを作成
+	// DB_DRIVER=memory ではデータベースの代わりにメモリ上のリポジトリを使用する（デモモードではデモデータを登録）
+	repos := NewRepositories(db)
+	if cfg.UsesMemoryDB() {
+		memoryRepos, err := NewMemoryRepositories(context.Background(), cfg.Demo.Enabled)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		repos = memoryRepos
+	}
+	svc, err := NewServices(cfg, db, repos)
+	if err != nil {
+		log.Fatalf("サービスの作成に失敗しました: %v", err)
+	}
+	ctrl := NewControllers(cfg, svc)
 
-	// リポジトリを作成
-	userRepo := repository.NewUserRepository(db)
-	workRepo := repository.NewWorkRepository(db)
-	tagRepo := repository.NewTagRepository(db)
-	commentRepo := repository.NewCommentRepository(db)
-	projectRepo := repository.NewProjectRepository(db)
-	taskRepo := repository.NewTaskRepository(db)
-	voteRepo := repository.NewVoteRepository(db)
-
-	// Cloudinaryサービスを作成
-	// cloudinaryService, err := services.NewCloudinaryService(cfg)
-
-	// Lambdaサービスを作成
-	lambdaService := services.NewLambdaService(cfg)
-
-	// サービスを作成
-	authService := services.NewAuthService(userRepo, cfg)
-	workService := services.NewWorkService(workRepo, tagRepo, lambdaService, taskRepo, projectRepo) // taskRepo, projectRepoを追加
-	tagService := services.NewTagService(tagRepo)
-	commentService := services.NewCommentService(commentRepo, workRepo)
-	userService := services.NewUserService(userRepo, workRepo)
-	projectService := services.NewProjectService(projectRepo, taskRepo)
-	taskService := services.NewTaskService(taskRepo, projectRepo, workRepo)
-	voteService := services.NewVoteService(voteRepo, taskRepo, projectRepo, workRepo)
-
-	// コントローラーを作成
-	authController := controllers.NewAuthController(authService)
-	workController := controllers.NewWorkController(workService)
-	tagController := controllers.NewTagController(tagService)
-	commentController := controllers.NewCommentController(commentService)
-	userController := controllers.NewUserController(userService)
-	healthController := controllers.NewHealthController()
-	projectController := controllers.NewProjectController(projectService)
-	taskController := controllers.NewTaskController(taskService)
-	voteController := controllers.NewVoteController(voteService)
+	// メンテナンス中も管理者API・ログイン・Webhookは利用できるようにする
+	r.Use(middlewares.MaintenanceMiddleware(svc.Maintenance, "/api/v1/admin", "/api/v1/auth/login", "/api/v2/admin", "/api/v2/auth/login", "/api/v1/webhooks", "/api/v2/webhooks"))
 
-	// 認証ミドルウェア
-	authMiddleware := middlewares.AuthMiddleware(authService)
+	// ブロックリストと国による書き込みの制限
+	r.Use(middlewares.BlocklistMiddleware(svc.Blocklist, cfg.Access.CountryHeader, blocklistExemptPrefixes...))
 
-	// APIグループを作成
-	api := r.Group("/api/v1")
+	// スケジューラを起動
+	if cfg.Scheduler.Enabled {
+		startScheduler(cfg, svc)
+	}
+
+	// ローカルストレージのファイルを配信
+	if ctrl.Static != nil {
+		r.GET("/uploads/*filepath", ctrl.Static.Serve)
+		r.HEAD("/uploads/*filepath", ctrl.Static.Serve)
+	}
+
+	// サイトマップ（検索エンジン向け）
+	r.GET("/sitemap.xml", ctrl.Sitemap.Index)
+	r.GET("/sitemaps/works/:page", ctrl.Sitemap.Works)
+
+	// 公開API（匿名の利用者向け、読み取り専用）
+	// アプリのAPIとは別にレート制限し、レスポンス形式は常にエンベロープ形式とする
+	public := r.Group("/public/v1",
+		middlewares.FixedResponseFormatMiddleware(utils.ResponseFormatEnvelope),
+		middlewares.RateLimitMiddleware(cfg.PublicAPI.RateLimit),
+	)
 	{
+		public.GET("/works", ctrl.Public.ListWorks)
+		public.GET("/works/:id", ctrl.Public.GetWork)
+		public.GET("/works/:id/embed", ctrl.Public.Embed)
+		public.GET("/tags", ctrl.Public.ListTags)
+	}
+
+	// 認証ミドルウェア
+	authMiddleware := middlewares.AuthMiddleware(svc.Auth, svc.PersonalToken)
+	optionalAuthMiddleware := middlewares.OptionalAuthMiddleware(svc.Auth, svc.PersonalToken)
+
+	// APIのルートを登録（v1とv2は同じコントローラーを共有し、レスポンスの形式のみが異なる）
+	registerAPI := func(api *gin.RouterGroup) {
 		// ヘルスチェックルート（認証不要）
-		api.GET("/health", healthController.Check)
+		api.GET("/health", ctrl.Health.Check)
 
 		// 認証ルート
 		auth := api.Group("/auth")
 		{
-			auth.POST("/register", authController.Register)
-			auth.POST("/login", authController.Login)
-			auth.GET("/me", authMiddleware, authController.GetMe)
-			auth.POST("/change-password", authMiddleware, authController.ChangePassword)
+			auth.GET("/registration", ctrl.Registration.Status)
+			auth.POST("/register", ctrl.Auth.Register)
+			auth.POST("/waitlist", ctrl.Registration.JoinWaitlist)
+			auth.POST("/login", ctrl.Auth.Login)
+			auth.POST("/refresh", ctrl.Auth.Refresh)
+			auth.POST("/logout", ctrl.Auth.Logout)
+			auth.POST("/logout-all", authMiddleware, ctrl.Auth.LogoutAll)
+			auth.GET("/github", ctrl.OAuth.GithubAuthorize)
+			auth.POST("/github", ctrl.OAuth.GithubLogin)
+			auth.POST("/github/link", authMiddleware, ctrl.OAuth.LinkGithub)
+			auth.GET("/providers", authMiddleware, ctrl.OAuth.ListProviders)
+			auth.DELETE("/providers/:provider", authMiddleware, ctrl.OAuth.Unlink)
+			auth.POST("/invitations/accept", ctrl.Auth.AcceptInvitation)
+			auth.POST("/email/confirm", ctrl.Auth.ConfirmEmailChange)
+			auth.GET("/me", authMiddleware, ctrl.Auth.GetMe)
+			auth.POST("/change-password", authMiddleware, ctrl.Auth.ChangePassword)
 		}
 
 		// 作品ルート
 		works := api.Group("/works")
 		{
 			// 認証不要
-			works.GET("", workController.List)
-			works.GET("/:id", workController.GetByID)
+			works.GET("", ctrl.Work.List)
+			works.GET("/compare", optionalAuthMiddleware, ctrl.Work.Compare)
+			works.GET("/featured", ctrl.Work.ListFeatured)
+			works.GET("/events", ctrl.Work.ListEvents)
+			works.GET("/events/:event", ctrl.Work.ListByEvent)
+			works.GET("/languages", ctrl.Work.ListLanguages)
+			works.GET("/map", ctrl.Work.Map)
+			works.GET("/:id", ctrl.Work.GetByID)
+			works.GET("/:id/assets", ctrl.Upload.ListByWork)
+			works.GET("/:id/assets/*filename", ctrl.Asset.Proxy)
+			works.GET("/:id/player", ctrl.Player.Show)
+			works.HEAD("/:id/assets/*filename", ctrl.Asset.Proxy)
 
 			// コメント関連
-			works.GET("/:id/comments", commentController.List)
-			works.POST("/:id/comments", authMiddleware, commentController.Create)
+			works.GET("/:id/comments", ctrl.Comment.List)
+			works.POST("/:id/comments", authMiddleware, ctrl.Comment.Create)
+			works.GET("/:id/comments/export", authMiddleware, ctrl.Comment.Export)
+			works.GET("/:id/subscription", authMiddleware, ctrl.Comment.GetSubscription)
+			works.POST("/:id/subscription", authMiddleware, ctrl.Comment.Subscribe)
+			works.DELETE("/:id/subscription", authMiddleware, ctrl.Comment.Unsubscribe)
+
+			// コードの注釈
+			works.GET("/:id/annotations", optionalAuthMiddleware, ctrl.Annotation.List)
+			works.POST("/:id/annotations", authMiddleware, ctrl.Annotation.Create)
+
+			// 共同編集者
+			works.GET("/:id/collaborators", optionalAuthMiddleware, ctrl.Collaborator.List)
+			works.POST("/:id/collaborators", authMiddleware, ctrl.Collaborator.Invite)
+			works.POST("/:id/collaborators/accept", authMiddleware, ctrl.Collaborator.Accept)
+			works.PUT("/:id/collaborators/:userID", authMiddleware, ctrl.Collaborator.UpdatePermission)
+			works.DELETE("/:id/collaborators/:userID", authMiddleware, ctrl.Collaborator.Remove)
+
+			// スナップショット（再生中にキャプチャしたフレーム）
+			works.GET("/:id/snapshots", ctrl.Snapshot.List)
+			works.POST("/:id/snapshots", authMiddleware, ctrl.Snapshot.Create)
+			works.PUT("/:id/snapshots/:snapshotID/thumbnail", authMiddleware, ctrl.Snapshot.SetThumbnail)
+			works.DELETE("/:id/snapshots/:snapshotID", authMiddleware, ctrl.Snapshot.Delete)
 
 			// 認証が必要
-			works.GET("/:id/liked", authMiddleware, workController.HasLiked)
-			works.POST("", authMiddleware, workController.Create)
-			works.PUT("/:id", authMiddleware, workController.Update)
-			works.DELETE("/:id", authMiddleware, workController.Delete)
-			works.POST("/:id/like", authMiddleware, workController.AddLike)
-			works.DELETE("/:id/like", authMiddleware, workController.RemoveLike)
+			works.GET("/:id/liked", authMiddleware, ctrl.Work.HasLiked)
+			works.GET("/:id/conversion", authMiddleware, ctrl.Work.ConversionStatus)
+			works.GET("/:id/likes", ctrl.Work.Likers)
+			works.POST("", authMiddleware, ctrl.Work.Create)
+			works.POST("/bulk", authMiddleware, ctrl.Work.Bulk)
+			works.POST("/preview", authMiddleware, ctrl.Work.Preview)
+			works.POST("/upload", authMiddleware, ctrl.Work.Upload)
+			works.POST("/import-url", authMiddleware, ctrl.Work.ImportURL)
+			works.PUT("/:id", authMiddleware, ctrl.Work.Update)
+			works.DELETE("/:id", authMiddleware, ctrl.Work.Delete)
+			works.POST("/:id/fork", authMiddleware, ctrl.Work.Fork)
+			works.PUT("/:id/video", authMiddleware, ctrl.Work.AttachVideo)
+			works.DELETE("/:id/video", authMiddleware, ctrl.Work.DetachVideo)
+			works.PUT("/:id/made-at", authMiddleware, ctrl.Work.UpdateMadeAt)
+			works.POST("/:id/restore", authMiddleware, ctrl.Work.Restore)
+			works.POST("/:id/like", authMiddleware, ctrl.Work.AddLike)
+			works.POST("/:id/report", authMiddleware, ctrl.Report.ReportWork)
+			works.DELETE("/:id/like", authMiddleware, ctrl.Work.RemoveLike)
+		}
+
+		// アセットの配信
+		api.GET("/assets/:id", ctrl.Asset.Get)
+		api.HEAD("/assets/:id", ctrl.Asset.Get)
+
+		// アップロードされた画像と派生画像
+		api.GET("/images/:id", ctrl.Image.GetByID)
+
+		// 再開可能なアップロード（tusプロトコル）
+		uploads := api.Group("/uploads", ctrl.Upload.TusMiddleware, authMiddleware)
+		{
+			uploads.POST("", ctrl.Upload.Create)
+			uploads.HEAD("/:id", ctrl.Upload.Head)
+			uploads.PATCH("/:id", ctrl.Upload.Patch)
+			uploads.DELETE("/:id", ctrl.Upload.Delete)
 		}
 
 		// コメントルート
 		comments := api.Group("/comments").Use(authMiddleware)
 		{
-			comments.PUT("/:id", commentController.Update)
-			comments.DELETE("/:id", commentController.Delete)
+			comments.PUT("/:id", ctrl.Comment.Update)
+			comments.DELETE("/:id", ctrl.Comment.Delete)
+			comments.POST("/:id/pin", ctrl.Comment.Pin)
+			comments.DELETE("/:id/pin", ctrl.Comment.Unpin)
+			comments.POST("/:id/resolve", ctrl.Comment.Resolve)
+			comments.DELETE("/:id/resolve", ctrl.Comment.Unresolve)
+			comments.POST("/:id/report", ctrl.Report.ReportComment)
+		}
+
+		// シリーズルート
+		series := api.Group("/series")
+		{
+			series.GET("/:id", ctrl.Series.GetByID)
+			series.POST("", authMiddleware, ctrl.Series.Create)
+			series.PUT("/:id", authMiddleware, ctrl.Series.Update)
+			series.DELETE("/:id", authMiddleware, ctrl.Series.Delete)
+		}
+
+		// 注釈ルート
+		annotations := api.Group("/annotations").Use(authMiddleware)
+		{
+			annotations.PUT("/:id", ctrl.Annotation.Update)
+			annotations.DELETE("/:id", ctrl.Annotation.Delete)
 		}
 
 		// タグルート
-		api.GET("/tags", tagController.List)
+		api.GET("/tags", ctrl.Tag.List)
+
+		// トップページのセクション（認証不要）
+		api.GET("/home", ctrl.Home.Get)
+
+		// StripeのWebhook（認証の代わりに署名を検証）
+		api.POST("/webhooks/stripe", ctrl.Billing.StripeWebhook)
 
 		// ユーザールート
 		users := api.Group("/users")
 		{
 			// 重要：順序に注意！まず静的なルートを定義
-			users.GET("/me", authMiddleware, userController.GetMe)
+			users.GET("/me", authMiddleware, ctrl.User.GetMe)
+			users.GET("/me/quota", authMiddleware, ctrl.User.GetQuota)
+			users.GET("/me/plan", authMiddleware, ctrl.Billing.GetPlan)
+			users.GET("/me/trash", authMiddleware, ctrl.Work.Trash)
+			users.GET("/me/likes", authMiddleware, ctrl.Work.Liked)
+			users.GET("/me/collaboration-invitations", authMiddleware, ctrl.Collaborator.ListInvitations)
+			users.POST("/me/email", authMiddleware, ctrl.Auth.RequestEmailChange)
+			users.PUT("/me/handle", authMiddleware, ctrl.User.ChangeHandle)
+			users.PUT("/me/avatar", authMiddleware, ctrl.User.UploadAvatar)
+			users.GET("/me/privacy", authMiddleware, ctrl.User.GetPrivacy)
+			users.PUT("/me/privacy", authMiddleware, ctrl.User.UpdatePrivacy)
+			users.GET("/me/age", authMiddleware, ctrl.AgeGate.GetAge)
+			users.PUT("/me/age", authMiddleware, ctrl.AgeGate.SetBirthYear)
+			users.GET("/me/tokens", authMiddleware, ctrl.Token.List)
+			users.POST("/me/tokens", authMiddleware, ctrl.Token.Create)
+			users.PUT("/me/tokens/:id", authMiddleware, ctrl.Token.Update)
+			users.DELETE("/me/tokens/:id", authMiddleware, ctrl.Token.Delete)
+			users.GET("/ranking", ctrl.User.Ranking)
 
 			// 次に動的パラメータを含むルートを定義
-			users.GET("/:id", userController.GetByID)            // 修正：idパラメータに統一
-			users.GET("/:id/works", workController.GetUserWorks) // 修正：userIDからidに変更
+			users.GET("/:id", ctrl.User.GetByID)            // 修正：idパラメータに統一（@handle も可）
+			users.GET("/:id/works", ctrl.Work.GetUserWorks) // 修正：userIDからidに変更
+			users.GET("/:id/collaborations", ctrl.Work.GetUserCollaborations)
+			users.GET("/:id/reputation", ctrl.User.GetReputation)
+			users.GET("/:id/activity-calendar", ctrl.User.GetActivityCalendar)
+			users.GET("/:id/likes", optionalAuthMiddleware, ctrl.User.GetLikedWorks)
+			users.GET("/:id/projects", optionalAuthMiddleware, ctrl.User.GetContests)
 
 			// プロフィール更新
-			users.PUT("/profile", authMiddleware, userController.UpdateProfile)
+			users.PUT("/profile", authMiddleware, ctrl.User.UpdateProfile)
 		}
 
+		// プロジェクトのカレンダー（カレンダーアプリから購読するため、URLのトークンで認証）
+		api.GET("/projects/:id/calendar.ics", ctrl.Calendar.Feed)
+
 		// プロジェクトルート
 		projects := api.Group("/projects").Use(authMiddleware)
 		{
-			projects.GET("", projectController.List)
-			projects.POST("", projectController.Create)
+			projects.GET("", ctrl.Project.List)
+			projects.POST("", ctrl.Project.Create)
 			// デバッグログを追加
 			projects.GET("/my", func(c *gin.Context) {
 				log.Printf("[DEBUG] /projects/my endpoint called - Method: %s, Path: %s", c.Request.Method, c.Request.URL.Path)
-				projectController.GetUserProjects(c)
+				ctrl.Project.GetUserProjects(c)
 			})
-			projects.POST("/join", projectController.JoinProject)
-			projects.GET("/:id", projectController.GetByID)
-			projects.PUT("/:id", projectController.Update)
-			projects.DELETE("/:id", projectController.Delete)
-			projects.GET("/:id/members", projectController.GetMembers)
-			projects.DELETE("/:id/members/:memberID", projectController.RemoveMember)
-			projects.POST("/:id/invitation-code", projectController.GenerateInvitationCode)
+			projects.POST("/join", ctrl.Project.JoinProject)
+			projects.GET("/:id", ctrl.Project.GetByID)
+			projects.PUT("/:id", ctrl.Project.Update)
+			projects.DELETE("/:id", ctrl.Project.Delete)
+			projects.POST("/:id/clone", ctrl.Project.Clone)
+			projects.GET("/:id/members", ctrl.Project.GetMembers)
+			projects.GET("/:id/members/export", ctrl.Project.ExportMembers)
+			projects.GET("/:id/members/inactive", ctrl.Project.ListInactiveMembers)
+			projects.GET("/:id/dashboard", ctrl.Project.GetDashboard)
+			projects.DELETE("/:id/members/:memberID", ctrl.Project.RemoveMember)
+			projects.PUT("/:id/members/:memberID/supervision", ctrl.AgeGate.SetSupervision)
+			projects.POST("/:id/invitation-code", ctrl.Project.GenerateInvitationCode)
+			projects.POST("/:id/roster/import", ctrl.Project.ImportRoster)
+			projects.GET("/:id/calendar", ctrl.Calendar.GetURL)
 		}
 
 		// タスクルート
 		tasks := api.Group("/tasks").Use(authMiddleware)
 		{
-			tasks.POST("", taskController.Create)
-			tasks.GET("/:id", taskController.GetByID)
-			tasks.PUT("/:id", taskController.Update)
-			tasks.DELETE("/:id", taskController.Delete)
-			tasks.GET("/project/:projectID", taskController.ListByProject)
-			tasks.POST("/:id/works", taskController.AddWork)
-			tasks.DELETE("/:id/works/:workID", taskController.RemoveWork)
-			tasks.GET("/:id/works", taskController.GetWorks)
-			tasks.PUT("/orders", taskController.UpdateOrders)
+			tasks.POST("", ctrl.Task.Create)
+			tasks.GET("/:id", ctrl.Task.GetByID)
+			tasks.PUT("/:id", ctrl.Task.Update)
+			tasks.DELETE("/:id", ctrl.Task.Delete)
+			tasks.GET("/project/:projectID", ctrl.Task.ListByProject)
+			tasks.POST("/:id/works", ctrl.Task.AddWork)
+			tasks.DELETE("/:id/works/:workID", ctrl.Task.RemoveWork)
+			tasks.GET("/:id/works", ctrl.Task.GetWorks)
+			tasks.GET("/:id/rubric", ctrl.Critique.GetRubric)
+			tasks.PUT("/:id/rubric", ctrl.Critique.SetRubric)
+			tasks.GET("/:id/critiques/summary", ctrl.Critique.Summary)
+			tasks.GET("/:id/critiques/export", ctrl.Critique.Export)
+			tasks.GET("/:id/works/:workID/critiques", ctrl.Critique.ListByWork)
+			tasks.PUT("/:id/works/:workID/critique", ctrl.Critique.Submit)
+			tasks.DELETE("/:id/works/:workID/critique", ctrl.Critique.Delete)
+			tasks.PUT("/orders", ctrl.Task.UpdateOrders)
 		}
 
 		// 投票ルート
 		votes := api.Group("/votes").Use(authMiddleware)
 		{
-			votes.POST("", voteController.Create)
-			votes.GET("/:id", voteController.GetByID)
-			votes.PUT("/:id", voteController.Update)
-			votes.DELETE("/:id", voteController.Delete)
-			votes.GET("/task/:taskID", voteController.ListByTask)
-			votes.POST("/:id/options", voteController.AddOption)
-			votes.DELETE("/:id/options/:optionID", voteController.DeleteOption)
-			votes.POST("/:id/vote", voteController.Vote)
-			votes.DELETE("/:id/vote/:optionID", voteController.RemoveVote)
-			votes.GET("/:id/user-votes", voteController.GetUserVotes)
-			votes.POST("/:id/close", voteController.CloseVote)
+			votes.POST("", ctrl.Vote.Create)
+			votes.GET("/:id", ctrl.Vote.GetByID)
+			votes.PUT("/:id", ctrl.Vote.Update)
+			votes.DELETE("/:id", ctrl.Vote.Delete)
+			votes.GET("/task/:taskID", ctrl.Vote.ListByTask)
+			votes.POST("/:id/options", ctrl.Vote.AddOption)
+			votes.DELETE("/:id/options/:optionID", ctrl.Vote.DeleteOption)
+			votes.POST("/:id/vote", ctrl.Vote.Vote)
+			votes.DELETE("/:id/vote/:optionID", ctrl.Vote.RemoveVote)
+			votes.GET("/:id/user-votes", ctrl.Vote.GetUserVotes)
+			votes.GET("/:id/results/export", ctrl.Vote.ExportResults)
+			votes.POST("/:id/close", ctrl.Vote.CloseVote)
+		}
+
+		// コンテストルート（一覧・応募作品・結果は認証不要）
+		contests := api.Group("/contests")
+		{
+			contests.GET("", ctrl.Contest.List)
+			contests.GET("/:id", ctrl.Contest.GetByID)
+			contests.GET("/:id/gallery", ctrl.Contest.Gallery)
+			contests.GET("/:id/results", ctrl.Contest.Results)
+			contests.POST("", authMiddleware, ctrl.Contest.Create)
+			contests.POST("/:id/register", authMiddleware, ctrl.Contest.Register)
+			contests.POST("/:id/judging", authMiddleware, ctrl.Contest.StartJudging)
+		}
+
+		// イベントルート（一覧・詳細・作品は認証不要、IDの代わりに識別子も指定できる）
+		events := api.Group("/events")
+		{
+			events.GET("", ctrl.Event.List)
+			events.GET("/:id", ctrl.Event.GetByID)
+			events.GET("/:id/works", ctrl.Event.ListWorks)
+			events.GET("/:id/attendees", authMiddleware, ctrl.Event.ListAttendees)
+			events.POST("", authMiddleware, ctrl.Event.Create)
+			events.PUT("/:id", authMiddleware, ctrl.Event.Update)
+			events.DELETE("/:id", authMiddleware, ctrl.Event.Delete)
+			events.POST("/:id/register", authMiddleware, ctrl.Event.Register)
+			events.DELETE("/:id/register", authMiddleware, ctrl.Event.Unregister)
+		}
+
+		// 組織ルート（プロジェクトの作成・一覧は /projects に X-Org-ID ヘッダーを指定しても同じ）
+		organizations := api.Group("/organizations").Use(authMiddleware)
+		{
+			organizations.GET("", ctrl.Organization.List)
+			organizations.POST("", ctrl.Organization.Create)
+			organizations.GET("/:id", ctrl.Organization.GetByID)
+			organizations.PUT("/:id", ctrl.Organization.Update)
+			organizations.DELETE("/:id", ctrl.Organization.Delete)
+			organizations.GET("/:id/members", ctrl.Organization.ListMembers)
+			organizations.POST("/:id/members", ctrl.Organization.AddMember)
+			organizations.PUT("/:id/members/:memberID", ctrl.Organization.UpdateMemberRole)
+			organizations.DELETE("/:id/members/:memberID", ctrl.Organization.RemoveMember)
+			organizations.GET("/:id/projects", ctrl.Organization.ListProjects)
+			organizations.POST("/:id/projects", ctrl.Organization.CreateProject)
+		}
+
+		// 通知ルート
+		notifications := api.Group("/notifications").Use(authMiddleware)
+		{
+			notifications.GET("", ctrl.Notification.List)
+			notifications.GET("/unread-count", ctrl.Notification.UnreadCount)
+			notifications.PUT("/read-all", ctrl.Notification.MarkAllAsRead)
+			notifications.PUT("/:id/read", ctrl.Notification.MarkAsRead)
+		}
+
+		// ダイレクトメッセージルート（同じプロジェクトのメンバー間のみ）
+		messages := api.Group("/messages").Use(authMiddleware)
+		{
+			messages.GET("", ctrl.Message.ListConversations)
+			messages.POST("", ctrl.Message.StartConversation)
+			messages.GET("/unread-count", ctrl.Message.UnreadCount)
+			messages.GET("/:id", ctrl.Message.ListMessages)
+			messages.POST("/:id", ctrl.Message.SendMessage)
+		}
+
+		// 管理者ルート
+		admin := api.Group("/admin").Use(authMiddleware, middlewares.AdminMiddleware())
+		{
+			admin.GET("/reconversions", ctrl.Reconversion.List)
+			admin.POST("/reconversions", ctrl.Reconversion.Start)
+			admin.GET("/reconversions/:id", ctrl.Reconversion.GetByID)
+			admin.POST("/reconversions/:id/cancel", ctrl.Reconversion.Cancel)
+			admin.GET("/conversions/queue", ctrl.Reconversion.QueueStats)
+			admin.GET("/retag-jobs", ctrl.Retag.List)
+			admin.POST("/retag-jobs", ctrl.Retag.Start)
+			admin.GET("/retag-jobs/:id", ctrl.Retag.GetByID)
+			admin.POST("/retag-jobs/:id/cancel", ctrl.Retag.Cancel)
+			admin.POST("/retag-jobs/:id/undo", ctrl.Retag.Undo)
+			admin.GET("/conversions/invocations", ctrl.Reconversion.Invocations)
+			admin.GET("/http-clients", ctrl.Health.HTTPClients)
+			admin.GET("/maintenance", ctrl.Maintenance.Get)
+			admin.PUT("/maintenance", ctrl.Maintenance.Update)
+			admin.GET("/mail-templates", ctrl.MailTemplate.List)
+			admin.GET("/mail-templates/:name/preview", ctrl.MailTemplate.Preview)
+			admin.PUT("/works/:id/featured", ctrl.Work.SetFeatured)
+			admin.DELETE("/works/:id/featured", ctrl.Work.UnsetFeatured)
+			admin.GET("/reports/works", ctrl.Report.ListHiddenWorks)
+			admin.GET("/reports/comments", ctrl.Report.ListHiddenComments)
+			admin.GET("/reports/:type/:id", ctrl.Report.ListReports)
+			admin.POST("/reports/:type/:id/restore", ctrl.Report.Restore)
+			admin.DELETE("/reports/:type/:id", ctrl.Report.Remove)
+			admin.POST("/users/:id/purge", ctrl.Purge.PurgeUser)
+			admin.POST("/users/:id/reputation/recalculate", ctrl.User.RecalculateReputation)
+			admin.POST("/works/:id/purge", ctrl.Purge.PurgeWork)
+			admin.GET("/audit-logs", ctrl.Purge.ListAuditLogs)
+			admin.POST("/tags/cleanup", ctrl.Tag.CleanupUnused)
+			admin.PUT("/organizations/:id/limits", ctrl.Organization.SetLimits)
+			admin.GET("/ip-blocks", ctrl.Blocklist.List)
+			admin.POST("/ip-blocks", ctrl.Blocklist.Create)
+			admin.DELETE("/ip-blocks/:id", ctrl.Blocklist.Delete)
+			admin.GET("/waitlist", ctrl.Registration.ListWaitlist)
+			admin.POST("/waitlist/:id/approve", ctrl.Registration.ApproveWaitlist)
+			admin.DELETE("/waitlist/:id", ctrl.Registration.DeleteWaitlist)
+			admin.GET("/invite-codes", ctrl.Registration.ListInviteCodes)
+			admin.POST("/invite-codes", ctrl.Registration.CreateInviteCode)
+			admin.DELETE("/invite-codes/:id", ctrl.Registration.DeleteInviteCode)
 		}
 
 		// デバッグルート（一時的）
@@ -183,5 +471,104 @@ func SetupRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		})
 	}
 
+	// APIグループを作成
+	registerAPI(r.Group("/api/v1"))
+	registerAPI(r.Group("/api/v2"))
+
 	return r
 }
+
+// startScheduler 定期実行するジョブを登録してスケジューラを起動
+func startScheduler(cfg *config.Config, svc *Services) {
+	sched := scheduler.New()
+	sched.Register("close-expired-votes", cfg.Scheduler.VoteCloseInterval, func(ctx context.Context) error {
+		closed, err := svc.Vote.CloseExpiredVotes(ctx)
+		if closed > 0 {
+			log.Printf("[SCHEDULER] 締め切りを過ぎた投票を %d 件終了しました", closed)
+		}
+		return err
+	})
+	sched.Register("reconversion", cfg.Scheduler.ReconversionInterval, func(ctx context.Context) error {
+		processed, err := svc.Reconversion.ProcessBatches(ctx)
+		if processed > 0 {
+			log.Printf("[SCHEDULER] 作品を %d 件再変換しました", processed)
+		}
+		return err
+	})
+	sched.Register("retag-works", cfg.Scheduler.RetagInterval, func(ctx context.Context) error {
+		processed, err := svc.Retag.ProcessBatches(ctx)
+		if processed > 0 {
+			log.Printf("[SCHEDULER] 作品 %d 件のタグを一括で変更しました", processed)
+		}
+		return err
+	})
+	sched.Register("retry-conversions", cfg.Scheduler.ConversionRetryInterval, func(ctx context.Context) error {
+		processed, err := svc.ConversionJob.ProcessDue(ctx)
+		if processed > 0 {
+			log.Printf("[SCHEDULER] 変換の再試行ジョブを %d 件処理しました", processed)
+		}
+		return err
+	})
+	sched.Register("purge-invocation-logs", cfg.Scheduler.InvocationPurgeInterval, func(ctx context.Context) error {
+		purged, err := svc.Invocation.Purge(ctx)
+		if purged > 0 {
+			log.Printf("[SCHEDULER] 保持期間を過ぎたLambdaの呼び出し履歴を %d 件削除しました", purged)
+		}
+		return err
+	})
+	sched.Register("purge-expired-tokens", cfg.Scheduler.TokenPurgeInterval, func(ctx context.Context) error {
+		purged, err := svc.Auth.PurgeExpiredTokens(ctx)
+		if purged > 0 {
+			log.Printf("[SCHEDULER] 有効期限を過ぎたトークンの記録を %d 件削除しました", purged)
+		}
+		return err
+	})
+	sched.Register("reconcile-work-counters", cfg.Scheduler.CounterInterval, func(ctx context.Context) error {
+		fixed, err := svc.Work.ReconcileCounters(ctx)
+		if fixed > 0 {
+			log.Printf("[SCHEDULER] いいね数・コメント数を %d 件修正しました", fixed)
+		}
+		return err
+	})
+	sched.Register("purge-deleted-works", cfg.Scheduler.TrashPurgeInterval, func(ctx context.Context) error {
+		purged, err := svc.Work.PurgeTrash(ctx)
+		if purged > 0 {
+			log.Printf("[SCHEDULER] 保持期間を過ぎた作品を %d 件完全に削除しました", purged)
+		}
+		return err
+	})
+	sched.Register("cleanup-unused-tags", cfg.TagCleanup.Interval, func(ctx context.Context) error {
+		tags, err := svc.Tag.CleanupUnused(ctx)
+		if len(tags) > 0 {
+			log.Printf("[SCHEDULER] どの作品にも付いていないタグを %d 件削除しました", len(tags))
+		}
+		return err
+	})
+	sched.Register("rank-works", cfg.Ranking.Interval, func(ctx context.Context) error {
+		ranked, err := svc.Ranking.Run(ctx)
+		if ranked > 0 {
+			log.Printf("[SCHEDULER] 作品 %d 件の人気スコアを計算しました", ranked)
+		}
+		return err
+	})
+	if cfg.Archive.AfterYears > 0 {
+		sched.Register("archive-works", cfg.Archive.Interval, func(ctx context.Context) error {
+			archived, err := svc.Archive.Run(ctx)
+			if archived > 0 {
+				log.Printf("[SCHEDULER] 長期間更新されていない作品 %d 件のコードをストレージに移しました", archived)
+			}
+			return err
+		})
+	}
+	if svc.Backup != nil && cfg.Backup.Interval > 0 {
+		sched.Register("backup-database", cfg.Backup.Interval, func(ctx context.Context) error {
+			backup, err := svc.Backup.Run(ctx)
+			if err != nil {
+				return err
+			}
+			log.Printf("[SCHEDULER] バックアップ %s を作成しました（%d バイト）", backup.Name, backup.Size)
+			return nil
+		})
+	}
+	sched.Start()
+}
@@ -0,0 +1,91 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/middlewares"
+	"github.com/SketchShifter/sketchshifter_backend/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// restrictAllCountries 全ての国からの書き込みを制限するBlocklistService
+type restrictAllCountries struct {
+	services.BlocklistService
+}
+
+func (restrictAllCountries) IsBlocked(ctx context.Context, ip string) bool { return false }
+
+func (restrictAllCountries) IsCountryRestricted(country string) bool { return true }
+
+func TestBlocklistAllowsWebhooksFromRestrictedCountries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middlewares.BlocklistMiddleware(restrictAllCountries{}, "CF-IPCountry", blocklistExemptPrefixes...))
+	ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+	r.POST("/api/v1/webhooks/stripe", ok)
+	r.POST("/api/v2/webhooks/stripe", ok)
+	r.POST("/api/v1/works", ok)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/api/v1/webhooks/stripe", http.StatusOK},
+		{"/api/v2/webhooks/stripe", http.StatusOK},
+		{"/api/v1/works", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+		req.Header.Set("CF-IPCountry", "XX")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("POST %s: ステータスコード %d（期待値 %d）", tt.path, w.Code, tt.want)
+		}
+	}
+}
+
+// TestRouteTimeoutsMatchUploadRoutes ファイルを受け取るルートにアップロードのタイムアウトが使われることを確認
+func TestRouteTimeoutsMatchUploadRoutes(t *testing.T) {
+	t.Setenv("DB_DRIVER", "memory")
+	t.Setenv("SCHEDULER_ENABLED", "false")
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("設定の読み込みに失敗しました: %v", err)
+	}
+	cfg.Server.UploadTimeout = 42 * time.Minute
+	timeouts := routeTimeouts(cfg)
+
+	uploads := map[string]bool{
+		"POST /api/v1/works/upload":                             false,
+		"PUT /api/v1/works/:id/video":                           false,
+		"PUT /api/v1/works/:id/snapshots/:snapshotID/thumbnail": false,
+		"PUT /api/v1/users/me/avatar":                           false,
+		"POST /api/v1/projects/:id/roster/import":               false,
+		"PATCH /api/v1/uploads/:id":                             false,
+		"PUT /api/v2/works/:id/video":                           false,
+	}
+	for _, route := range SetupRouter(cfg, nil).Routes() {
+		key := route.Method + " " + route.Path
+		if _, ok := uploads[key]; !ok {
+			continue
+		}
+		for suffix, timeout := range timeouts {
+			if strings.HasSuffix(route.Path, suffix) && timeout == cfg.Server.UploadTimeout {
+				uploads[key] = true
+			}
+		}
+	}
+	for route, matched := range uploads {
+		if !matched {
+			t.Errorf("%s にアップロードのタイムアウトが使われていません", route)
+		}
+	}
+}
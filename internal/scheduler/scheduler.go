@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// JobFunc 定期実行するジョブの関数
+// ctxはスケジューラの停止時、または実行間隔を過ぎた時点でキャンセルされる
+type JobFunc func(ctx context.Context) error
+
+// job 登録されたジョブ
+type job struct {
+	name     string
+	interval time.Duration
+	fn       JobFunc
+}
+
+// Scheduler 定期ジョブを実行するスケジューラ
+type Scheduler struct {
+	jobs   []job
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New Schedulerを作成
+func New() *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Register ジョブを登録（Start前に呼び出すこと）
+func (s *Scheduler) Register(name string, interval time.Duration, fn JobFunc) {
+	if interval <= 0 {
+		log.Printf("[SCHEDULER] ジョブ %s の実行間隔が不正なため登録をスキップします", name)
+		return
+	}
+	s.jobs = append(s.jobs, job{name: name, interval: interval, fn: fn})
+}
+
+// Start 登録されたジョブを実行開始
+func (s *Scheduler) Start() {
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.run(j)
+	}
+	log.Printf("[SCHEDULER] %d 件のジョブを開始しました", len(s.jobs))
+}
+
+// Stop すべてのジョブを停止し、終了を待つ
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// run ジョブを一定間隔で実行
+func (s *Scheduler) run(j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.execute(j)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// execute ジョブを1回実行（パニックしてもスケジューラは止めない）
+func (s *Scheduler) execute(j job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[SCHEDULER] ジョブ %s でパニックが発生しました: %v", j.name, r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(s.ctx, j.interval)
+	defer cancel()
+
+	if err := j.fn(ctx); err != nil {
+		log.Printf("[SCHEDULER] ジョブ %s の実行に失敗しました: %v", j.name, err)
+	}
+}
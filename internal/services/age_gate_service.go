@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// AgeGateService 未成年のユーザーの利用制限（作品の公開とダイレクトメッセージ）に関するサービスインターフェース
+// 未成年のユーザーは、参加しているプロジェクトのオーナー（先生）が見守りを有効にするまで制限される
+type AgeGateService interface {
+	GetStatus(ctx context.Context, userID uint) (*AgeStatus, error)
+	SetBirthYear(ctx context.Context, userID uint, birthYear int) (*AgeStatus, error)
+	SetSupervised(ctx context.Context, projectID, ownerID, memberID uint, supervised bool) error
+	CheckPosting(ctx context.Context, userID uint) error
+	CheckMessaging(ctx context.Context, senderID, recipientID uint) error
+}
+
+// AgeStatus ユーザーの年齢と利用制限の状態（本人にのみ返す）
+type AgeStatus struct {
+	BirthYear  *int `json:"birth_year"`
+	Minor      bool `json:"minor"`      // 未成年として扱われているか
+	Supervised bool `json:"supervised"` // 見守りが有効なプロジェクトに参加しているか
+	Restricted bool `json:"restricted"` // 作品の公開とダイレクトメッセージが制限されているか
+}
+
+// 生まれた年として受け付ける最も古い年
+const minBirthYear = 1900
+
+// ageGateService AgeGateServiceの実装
+type ageGateService struct {
+	userRepo    repository.UserRepository
+	projectRepo repository.ProjectRepository
+	config      *config.Config
+}
+
+// NewAgeGateService AgeGateServiceを作成
+func NewAgeGateService(userRepo repository.UserRepository, projectRepo repository.ProjectRepository, cfg *config.Config) AgeGateService {
+	return &ageGateService{
+		userRepo:    userRepo,
+		projectRepo: projectRepo,
+		config:      cfg,
+	}
+}
+
+// GetStatus ユーザーの年齢と利用制限の状態を取得
+func (s *ageGateService) GetStatus(ctx context.Context, userID uint) (*AgeStatus, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ユーザーが見つかりません")
+	}
+	return s.statusOf(ctx, user)
+}
+
+// SetBirthYear 生まれた年を登録（制限を外せないよう、一度登録すると本人は変更できない）
+func (s *ageGateService) SetBirthYear(ctx context.Context, userID uint, birthYear int) (*AgeStatus, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ユーザーが見つかりません")
+	}
+	if user.BirthYear != nil {
+		if *user.BirthYear == birthYear {
+			return s.statusOf(ctx, user)
+		}
+		return nil, errors.New("生まれた年は一度登録すると変更できません")
+	}
+	if birthYear < minBirthYear || birthYear > time.Now().Year() {
+		return nil, fmt.Errorf("生まれた年には%dから%dまでの値を指定してください", minBirthYear, time.Now().Year())
+	}
+
+	user.BirthYear = &birthYear
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("生まれた年の登録に失敗しました: %v", err)
+	}
+	return s.statusOf(ctx, user)
+}
+
+// SetSupervised プロジェクトのメンバーの見守りを設定（プロジェクトのオーナーのみ）
+// 保護者の同意を確認したうえで有効にする
+func (s *ageGateService) SetSupervised(ctx context.Context, projectID, ownerID, memberID uint, supervised bool) error {
+	if _, err := s.projectRepo.FindByID(ctx, projectID); err != nil {
+		return errors.New("プロジェクトが見つかりません")
+	}
+	isOwner, err := s.projectRepo.IsOwner(ctx, projectID, ownerID)
+	if err != nil || !isOwner {
+		return errors.New("このプロジェクトのメンバーの見守りを設定する権限がありません")
+	}
+	isMember, err := s.projectRepo.IsMember(ctx, projectID, memberID)
+	if err != nil || !isMember {
+		return errors.New("メンバーが見つかりません")
+	}
+
+	if err := s.projectRepo.SetSupervised(ctx, projectID, memberID, supervised); err != nil {
+		return fmt.Errorf("見守りの設定に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// CheckPosting 作品を公開できるか確認（見守りが有効になっていない未成年のユーザーは公開できない）
+func (s *ageGateService) CheckPosting(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("ユーザーが見つかりません")
+	}
+	if !s.isMinor(user) {
+		return nil
+	}
+
+	supervised, err := s.projectRepo.IsSupervised(ctx, userID)
+	if err != nil || !supervised {
+		return errors.New("未成年のユーザーには作品を公開する権限がありません（参加しているプロジェクトのオーナーに見守りを有効にしてもらってください）")
+	}
+	return nil
+}
+
+// CheckMessaging ダイレクトメッセージを送信できるか確認
+// 未成年のユーザーとは、そのユーザーの見守りが有効なプロジェクトのメンバー同士でのみやり取りできる
+func (s *ageGateService) CheckMessaging(ctx context.Context, senderID, recipientID uint) error {
+	for _, pair := range [][2]uint{{senderID, recipientID}, {recipientID, senderID}} {
+		user, err := s.userRepo.FindByID(ctx, pair[0])
+		if err != nil {
+			return errors.New("ユーザーが見つかりません")
+		}
+		if !s.isMinor(user) {
+			continue
+		}
+
+		shares, err := s.projectRepo.SharesSupervisedProject(ctx, pair[0], pair[1])
+		if err != nil || !shares {
+			if pair[0] == senderID {
+				return errors.New("未成年のユーザーには、見守りが有効なプロジェクトのメンバー以外にメッセージを送信する権限がありません")
+			}
+			return errors.New("このユーザーにメッセージを送信する権限がありません（未成年のユーザーには、見守りが有効なプロジェクトのメンバーのみ送信できます）")
+		}
+	}
+	return nil
+}
+
+// statusOf ユーザーの年齢と利用制限の状態
+func (s *ageGateService) statusOf(ctx context.Context, user *models.User) (*AgeStatus, error) {
+	status := &AgeStatus{
+		BirthYear: user.BirthYear,
+		Minor:     s.isMinor(user),
+	}
+
+	supervised, err := s.projectRepo.IsSupervised(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	status.Supervised = supervised
+	status.Restricted = status.Minor && !supervised
+	return status, nil
+}
+
+// isMinor 未成年として扱うか（誕生日が分からないため、その年の誕生日を迎える前の年齢で判定する）
+func (s *ageGateService) isMinor(user *models.User) bool {
+	if s.config.AgeGate.MinorAge <= 0 || user.BirthYear == nil {
+		return false
+	}
+	return time.Now().Year()-*user.BirthYear-1 < s.config.AgeGate.MinorAge
+}
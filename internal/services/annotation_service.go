@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// AnnotationService 作品のコード注釈に関するサービスインターフェース
+type AnnotationService interface {
+	ListByWork(ctx context.Context, workID uint, viewerID *uint) ([]models.WorkAnnotation, error)
+	Create(ctx context.Context, workID, userID uint, line int, content string) (*models.WorkAnnotation, error)
+	Update(ctx context.Context, id, userID uint, line int, content string) (*models.WorkAnnotation, error)
+	Delete(ctx context.Context, id, userID uint) error
+}
+
+const (
+	// 1つの作品に付けられる注釈の最大数
+	maxAnnotationsPerWork = 200
+	// 注釈1件の最大文字数
+	maxAnnotationLength = 2000
+)
+
+// annotationService AnnotationServiceの実装
+type annotationService struct {
+	annotationRepo repository.AnnotationRepository
+	workRepo       repository.WorkRepository
+}
+
+// NewAnnotationService AnnotationServiceを作成
+func NewAnnotationService(annotationRepo repository.AnnotationRepository, workRepo repository.WorkRepository) AnnotationService {
+	return &annotationService{
+		annotationRepo: annotationRepo,
+		workRepo:       workRepo,
+	}
+}
+
+// ListByWork 作品の注釈一覧を行番号順に取得
+// コードが公開されていない作品の注釈は作者のみ閲覧できる
+func (s *annotationService) ListByWork(ctx context.Context, workID uint, viewerID *uint) ([]models.WorkAnnotation, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	if !work.CodeShared && (viewerID == nil || *viewerID != work.UserID) {
+		return nil, errors.New("この作品の注釈を閲覧する権限がありません")
+	}
+
+	return s.annotationRepo.ListByWork(ctx, workID)
+}
+
+// Create 作品のコードに注釈を追加
+func (s *annotationService) Create(ctx context.Context, workID, userID uint, line int, content string) (*models.WorkAnnotation, error) {
+	work, err := s.ownedWork(ctx, workID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAnnotation(work, line, content); err != nil {
+		return nil, err
+	}
+
+	count, err := s.annotationRepo.CountByWork(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxAnnotationsPerWork {
+		return nil, fmt.Errorf("1つの作品に付けられる注釈は%d件までです", maxAnnotationsPerWork)
+	}
+
+	annotation := &models.WorkAnnotation{
+		WorkID:  workID,
+		Line:    line,
+		Content: strings.TrimSpace(content),
+	}
+	if err := s.annotationRepo.Create(ctx, annotation); err != nil {
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
+// Update 注釈の行番号と内容を更新
+func (s *annotationService) Update(ctx context.Context, id, userID uint, line int, content string) (*models.WorkAnnotation, error) {
+	annotation, err := s.annotationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("注釈が見つかりません")
+	}
+
+	work, err := s.ownedWork(ctx, annotation.WorkID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAnnotation(work, line, content); err != nil {
+		return nil, err
+	}
+
+	annotation.Line = line
+	annotation.Content = strings.TrimSpace(content)
+	if err := s.annotationRepo.Update(ctx, annotation); err != nil {
+		return nil, err
+	}
+
+	return annotation, nil
+}
+
+// Delete 注釈を削除
+func (s *annotationService) Delete(ctx context.Context, id, userID uint) error {
+	annotation, err := s.annotationRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.New("注釈が見つかりません")
+	}
+
+	if _, err := s.ownedWork(ctx, annotation.WorkID, userID); err != nil {
+		return err
+	}
+
+	return s.annotationRepo.Delete(ctx, id)
+}
+
+// ownedWork 作品を取得し、ユーザーが作者であることを確認
+func (s *annotationService) ownedWork(ctx context.Context, workID, userID uint) (*models.Work, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	if work.UserID != userID {
+		return nil, errors.New("この作品の注釈を編集する権限がありません")
+	}
+	return work, nil
+}
+
+// validateAnnotation 行番号がPDEコードの範囲内で、内容が空でないか確認
+func validateAnnotation(work *models.Work, line int, content string) error {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return errors.New("注釈の内容は必須です")
+	}
+	if utf8.RuneCountInString(content) > maxAnnotationLength {
+		return fmt.Errorf("注釈は%d文字以内で入力してください", maxAnnotationLength)
+	}
+
+	lines := strings.Count(strings.TrimSuffix(work.PDEContent, "\n"), "\n") + 1
+	if line < 1 || line > lines {
+		return fmt.Errorf("行番号は1〜%dで指定してください", lines)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// AnonymizeService 本番環境からコピーしたデータベースの個人情報を消すサービスインターフェース
+type AnonymizeService interface {
+	Run(ctx context.Context, password string) (*AnonymizeResult, error)
+}
+
+// AnonymizeResult 匿名化したレコード数
+type AnonymizeResult struct {
+	Users           int64
+	Messages        int64
+	Reports         int64
+	Notifications   int64
+	HandleHistories int64
+	IPBlocks        int64
+	WaitlistEntries int64
+	OAuthProviders  int64
+}
+
+// 匿名化したメッセージの本文
+const anonymizedMessage = "（匿名化されたメッセージ）"
+
+// anonymizeService AnonymizeServiceの実装
+type anonymizeService struct {
+	db *gorm.DB
+}
+
+// NewAnonymizeService AnonymizeServiceを作成
+func NewAnonymizeService(db *gorm.DB) AnonymizeService {
+	return &anonymizeService{
+		db: db,
+	}
+}
+
+// Run 1つのトランザクションで個人情報を置き換える
+// メールアドレス・氏名・ニックネーム・ハンドルはユーザーIDから作った値にし、全員のパスワードをpasswordにする
+// 他のユーザーの名前を含む通知と、IPアドレス・変更前のハンドル・順番待ち・外部サービスの連携は削除する
+func (s *anonymizeService) Run(ctx context.Context, password string) (*AnonymizeResult, error) {
+	if password == "" {
+		return nil, errors.New("パスワードを指定してください")
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, fmt.Errorf("パスワードのハッシュ化に失敗しました: %v", err)
+	}
+
+	result := &AnonymizeResult{}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 退会済みのユーザーも含めて置き換える（発行済みのトークンも無効にする）
+		users := tx.Unscoped().Model(&models.User{}).Where("1 = 1").UpdateColumns(map[string]interface{}{
+			"email":                  gorm.Expr("CONCAT('user', id, '@example.com')"),
+			"password":               string(hashed),
+			"name":                   gorm.Expr("CONCAT('ユーザー', id)"),
+			"nickname":               gorm.Expr("CONCAT('user', id)"),
+			"handle":                 gorm.Expr("CONCAT('user_', id)"),
+			"bio":                    "",
+			"avatar_url":             "",
+			"avatar_id":              nil,
+			"birth_year":             nil,
+			"invite_token_hash":      "",
+			"invite_expires_at":      nil,
+			"pending_email":          "",
+			"email_token_hash":       "",
+			"email_token_expires_at": nil,
+			"tokens_valid_after":     time.Now(),
+		})
+		if users.Error != nil {
+			return fmt.Errorf("ユーザー: %v", users.Error)
+		}
+		result.Users = users.RowsAffected
+
+		messages := tx.Model(&models.Message{}).Where("1 = 1").UpdateColumn("content", anonymizedMessage)
+		if messages.Error != nil {
+			return fmt.Errorf("メッセージ: %v", messages.Error)
+		}
+		result.Messages = messages.RowsAffected
+
+		reports := tx.Model(&models.Report{}).Where("1 = 1").UpdateColumn("reason", "")
+		if reports.Error != nil {
+			return fmt.Errorf("通報: %v", reports.Error)
+		}
+		result.Reports = reports.RowsAffected
+
+		notifications := tx.Where("1 = 1").Delete(&models.Notification{})
+		if notifications.Error != nil {
+			return fmt.Errorf("通知: %v", notifications.Error)
+		}
+		result.Notifications = notifications.RowsAffected
+
+		histories := tx.Where("1 = 1").Delete(&models.HandleHistory{})
+		if histories.Error != nil {
+			return fmt.Errorf("変更前のハンドル: %v", histories.Error)
+		}
+		result.HandleHistories = histories.RowsAffected
+
+		blocks := tx.Where("1 = 1").Delete(&models.IPBlock{})
+		if blocks.Error != nil {
+			return fmt.Errorf("IPアドレスの制限: %v", blocks.Error)
+		}
+		result.IPBlocks = blocks.RowsAffected
+
+		// 順番待ちは登録前の人のメールアドレスのため削除し、招待コードは宛先のみ消す
+		waitlist := tx.Where("1 = 1").Delete(&models.WaitlistEntry{})
+		if waitlist.Error != nil {
+			return fmt.Errorf("順番待ち: %v", waitlist.Error)
+		}
+		result.WaitlistEntries = waitlist.RowsAffected
+		if err := tx.Model(&models.InviteCode{}).Where("email <> ''").UpdateColumn("email", "").Error; err != nil {
+			return fmt.Errorf("招待コード: %v", err)
+		}
+
+		// 外部サービスのアカウントは連携し直せるため削除する
+		providers := tx.Where("1 = 1").Delete(&models.OAuthProvider{})
+		if providers.Error != nil {
+			return fmt.Errorf("外部サービスの連携: %v", providers.Error)
+		}
+		result.OAuthProviders = providers.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("匿名化に失敗しました: %v", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// ArchiveService 長期間更新されていない作品のコードをストレージに移すサービスインターフェース
+type ArchiveService interface {
+	Run(ctx context.Context) (int, error)
+}
+
+// archivedCode ストレージに移した作品のコード
+type archivedCode struct {
+	PDEContent string `json:"pde_content"`
+	JSContent  string `json:"js_content"`
+}
+
+// archiveService ArchiveServiceの実装
+type archiveService struct {
+	workRepo repository.WorkRepository
+	storage  StorageService
+	config   *config.Config
+}
+
+// NewArchiveService ArchiveServiceを作成
+func NewArchiveService(workRepo repository.WorkRepository, storage StorageService, cfg *config.Config) ArchiveService {
+	return &archiveService{
+		workRepo: workRepo,
+		storage:  storage,
+		config:   cfg,
+	}
+}
+
+// Run 設定した年数だけ更新されていない作品のPDEとJSをストレージに移し、移した作品数を返す（スケジューラから定期実行）
+func (s *archiveService) Run(ctx context.Context) (int, error) {
+	if s.config.Archive.AfterYears <= 0 {
+		return 0, nil
+	}
+
+	before := time.Now().AddDate(-s.config.Archive.AfterYears, 0, 0)
+	works, err := s.workRepo.ListArchivable(ctx, before, s.config.Archive.MinSizeKB*1024, s.config.Archive.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	archived := 0
+	for i := range works {
+		work := &works[i]
+		key := archiveKey(s.config, work.ID)
+		if err := writeArchivedCode(ctx, s.storage, key, work); err != nil {
+			return archived, fmt.Errorf("作品のコードの保存に失敗しました (ID=%d): %v", work.ID, err)
+		}
+
+		// 取得後に作品が更新された場合は移さず、保存したコードを削除する
+		ok, err := s.workRepo.Archive(ctx, work, time.Now())
+		if err != nil || !ok {
+			if deleteErr := s.storage.Delete(context.Background(), key); deleteErr != nil {
+				log.Printf("移さなかった作品のコード %s の削除に失敗しました: %v", key, deleteErr)
+			}
+		}
+		if err != nil {
+			return archived, fmt.Errorf("作品のアーカイブに失敗しました (ID=%d): %v", work.ID, err)
+		}
+		if ok {
+			archived++
+		}
+	}
+
+	return archived, nil
+}
+
+// archivingWorkRepository 取得した作品のコードがストレージに移されていれば戻してから返すWorkRepository
+type archivingWorkRepository struct {
+	repository.WorkRepository
+	storage StorageService
+	config  *config.Config
+}
+
+// NewArchivingWorkRepository ストレージに移した作品のコードを取得時に透過的に戻すWorkRepositoryを作成
+func NewArchivingWorkRepository(repo repository.WorkRepository, storage StorageService, cfg *config.Config) repository.WorkRepository {
+	return &archivingWorkRepository{
+		WorkRepository: repo,
+		storage:        storage,
+		config:         cfg,
+	}
+}
+
+// FindByID IDで作品を検索（コードがストレージに移されていれば戻す）
+func (r *archivingWorkRepository) FindByID(ctx context.Context, id uint) (*models.Work, error) {
+	work, err := r.WorkRepository.FindByID(ctx, id)
+	if err != nil || work.ArchivedAt == nil {
+		return work, err
+	}
+
+	if err := r.rehydrate(ctx, work); err != nil {
+		return nil, fmt.Errorf("作品のコードの復元に失敗しました (ID=%d): %v", id, err)
+	}
+	return work, nil
+}
+
+// rehydrate ストレージに移したコードを作品に書き戻し、ストレージから削除
+func (r *archivingWorkRepository) rehydrate(ctx context.Context, work *models.Work) error {
+	key := archiveKey(r.config, work.ID)
+	code, err := readArchivedCode(ctx, r.storage, key)
+	if err != nil {
+		// 同時に取得した別のリクエストが戻して削除した場合は、戻した作品を使う
+		latest, findErr := r.WorkRepository.FindByID(ctx, work.ID)
+		if findErr == nil && latest.ArchivedAt == nil {
+			*work = *latest
+			return nil
+		}
+		return err
+	}
+
+	work.PDEContent = code.PDEContent
+	work.JSContent = code.JSContent
+	if _, err := r.WorkRepository.Rehydrate(ctx, work); err != nil {
+		return err
+	}
+	work.ArchivedAt = nil
+
+	if err := r.storage.Delete(ctx, key); err != nil {
+		log.Printf("戻した作品のコード %s の削除に失敗しました: %v", key, err)
+	}
+	return nil
+}
+
+// archiveKey 作品のコードを保存するストレージのキー
+func archiveKey(cfg *config.Config, workID uint) string {
+	return fmt.Sprintf("%s/works/%d.json.gz", strings.TrimSuffix(cfg.Archive.Prefix, "/"), workID)
+}
+
+// writeArchivedCode 作品のコードをgzip圧縮したJSONでストレージに書き込む
+func writeArchivedCode(ctx context.Context, storage StorageService, key string, work *models.Work) error {
+	// WriteAtは既存のデータを切り詰めないため、前回削除できずに残ったデータを先に削除する
+	_ = storage.Delete(ctx, key)
+
+	pr, pw := io.Pipe()
+	go func() {
+		gz := gzip.NewWriter(pw)
+		err := json.NewEncoder(gz).Encode(archivedCode{PDEContent: work.PDEContent, JSContent: work.JSContent})
+		if closeErr := gz.Close(); err == nil {
+			err = closeErr
+		}
+		pw.CloseWithError(err)
+	}()
+	_, err := storage.WriteAt(ctx, key, 0, pr)
+	pr.Close()
+	return err
+}
+
+// readArchivedCode ストレージに移した作品のコードを読み込む
+func readArchivedCode(ctx context.Context, storage StorageService, key string) (*archivedCode, error) {
+	f, err := storage.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var code archivedCode
+	if err := json.NewDecoder(gz).Decode(&code); err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+)
+
+// AssetService 作品のアセットと再開可能なアップロードに関するサービスインターフェース
+type AssetService interface {
+	CreateUpload(ctx context.Context, userID, workID uint, filename string, length int64) (*models.AssetUpload, error)
+	GetUpload(ctx context.Context, id string, userID uint) (*models.AssetUpload, error)
+	WriteChunk(ctx context.Context, id string, userID uint, offset int64, r io.Reader) (*models.AssetUpload, error)
+	DeleteUpload(ctx context.Context, id string, userID uint) error
+	ListByWork(ctx context.Context, workID uint) ([]models.WorkAsset, error)
+	Open(ctx context.Context, id uint) (*models.WorkAsset, io.ReadSeekCloser, error)
+	OpenByFilename(ctx context.Context, workID uint, filename string) (*models.WorkAsset, io.ReadSeekCloser, error)
+	MaxSize() int64
+}
+
+// 画像のアセットの派生画像を作成する時間の上限
+const assetImageTimeout = 2 * time.Minute
+
+// 派生画像を作成するアセットの拡張子
+var assetImageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+}
+
+// assetContentTypes 配信するアセットの拡張子とContent-Type
+// スケッチが読み込む画像・音声・動画・フォント・テキストのみとし、HTML・SVGなど一覧にないものはダウンロードさせる
+var assetContentTypes = map[string]string{
+	".jpg":   "image/jpeg",
+	".jpeg":  "image/jpeg",
+	".png":   "image/png",
+	".gif":   "image/gif",
+	".webp":  "image/webp",
+	".bmp":   "image/bmp",
+	".mp3":   "audio/mpeg",
+	".wav":   "audio/wav",
+	".ogg":   "audio/ogg",
+	".m4a":   "audio/mp4",
+	".mp4":   "video/mp4",
+	".webm":  "video/webm",
+	".ttf":   "font/ttf",
+	".otf":   "font/otf",
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+	".txt":   "text/plain; charset=utf-8",
+	".csv":   "text/plain; charset=utf-8",
+	".tsv":   "text/plain; charset=utf-8",
+	".obj":   "text/plain; charset=utf-8",
+	".glsl":  "text/plain; charset=utf-8",
+	".frag":  "text/plain; charset=utf-8",
+	".vert":  "text/plain; charset=utf-8",
+	".json":  "application/json",
+}
+
+// AssetDownloadContentType 一覧にない拡張子のアセットのContent-Type（ダウンロードさせる）
+const AssetDownloadContentType = "application/octet-stream"
+
+// AssetContentType ファイル名の拡張子からアセットのContent-Typeを決定
+// クライアントが指定した形式は信用しない（一覧にない拡張子はAssetDownloadContentType）
+func AssetContentType(filename string) string {
+	if contentType, ok := assetContentTypes[strings.ToLower(filepath.Ext(filename))]; ok {
+		return contentType
+	}
+	return AssetDownloadContentType
+}
+
+// assetService AssetServiceの実装
+type assetService struct {
+	assetRepo    repository.AssetRepository
+	workRepo     repository.WorkRepository
+	storage      StorageService
+	imageService ImageService
+	config       *config.Config
+}
+
+// NewAssetService AssetServiceを作成
+func NewAssetService(assetRepo repository.AssetRepository, workRepo repository.WorkRepository, storage StorageService, imageService ImageService, cfg *config.Config) AssetService {
+	return &assetService{
+		assetRepo:    assetRepo,
+		workRepo:     workRepo,
+		storage:      storage,
+		imageService: imageService,
+		config:       cfg,
+	}
+}
+
+// MaxSize アセット1件あたりの最大サイズ（バイト）
+func (s *assetService) MaxSize() int64 {
+	return int64(s.config.Storage.AssetMaxSizeMB) * 1024 * 1024
+}
+
+// CreateUpload 作品のアセットのアップロードを開始
+// Content-Typeはファイル名の拡張子から決める
+func (s *assetService) CreateUpload(ctx context.Context, userID, workID uint, filename string, length int64) (*models.AssetUpload, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	if work.UserID != userID {
+		return nil, errors.New("この作品にアセットを追加する権限がありません")
+	}
+
+	if length <= 0 {
+		return nil, errors.New("アップロードするサイズを指定してください")
+	}
+	if length > s.MaxSize() {
+		return nil, fmt.Errorf("アセットのサイズが上限（%dMB）を超えています", s.config.Storage.AssetMaxSizeMB)
+	}
+
+	filename = filepath.Base(strings.TrimSpace(filename))
+	if filename == "" || filename == "." || filename == "/" {
+		return nil, errors.New("ファイル名を指定してください")
+	}
+
+	id := utils.GenerateRandomString(32)
+	upload := &models.AssetUpload{
+		ID:          id,
+		UserID:      userID,
+		WorkID:      workID,
+		Filename:    filename,
+		ContentType: AssetContentType(filename),
+		Length:      length,
+		StorageKey:  fmt.Sprintf("works/%d/assets/%s%s", workID, id, strings.ToLower(filepath.Ext(filename))),
+	}
+	if err := s.assetRepo.CreateUpload(ctx, upload); err != nil {
+		return nil, fmt.Errorf("アップロードの作成に失敗しました: %v", err)
+	}
+
+	return upload, nil
+}
+
+// GetUpload アップロードの状態を取得
+func (s *assetService) GetUpload(ctx context.Context, id string, userID uint) (*models.AssetUpload, error) {
+	upload, err := s.assetRepo.FindUpload(ctx, id)
+	if err != nil {
+		return nil, errors.New("アップロードが見つかりません")
+	}
+	if upload.UserID != userID {
+		return nil, errors.New("このアップロードを操作する権限がありません")
+	}
+	return upload, nil
+}
+
+// WriteChunk 受け取ったデータを保存し、全て揃った場合はアセットを作成
+func (s *assetService) WriteChunk(ctx context.Context, id string, userID uint, offset int64, r io.Reader) (*models.AssetUpload, error) {
+	upload, err := s.GetUpload(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 途中から再開する場合もサーバー側の受け取り済みバイト数と一致している必要がある
+	if offset != upload.Offset {
+		return nil, fmt.Errorf("オフセットが一致しません（現在: %d）", upload.Offset)
+	}
+	if upload.IsComplete() {
+		return upload, nil
+	}
+
+	// 宣言されたサイズを超える分は受け取らない
+	written, err := s.storage.WriteAt(ctx, upload.StorageKey, offset, io.LimitReader(r, upload.Length-offset))
+	upload.Offset += written
+	if err != nil {
+		// 途中まで書き込めた分は記録して、クライアントが続きから再開できるようにする
+		if written > 0 {
+			s.assetRepo.UpdateUploadOffset(ctx, upload.ID, upload.Offset)
+		}
+		return nil, fmt.Errorf("データの保存に失敗しました: %v", err)
+	}
+
+	if !upload.IsComplete() {
+		if err := s.assetRepo.UpdateUploadOffset(ctx, upload.ID, upload.Offset); err != nil {
+			return nil, fmt.Errorf("アップロードの更新に失敗しました: %v", err)
+		}
+		return upload, nil
+	}
+
+	// 全てのデータを受け取ったのでアセットを作成
+	asset := &models.WorkAsset{
+		WorkID:      upload.WorkID,
+		UserID:      upload.UserID,
+		Filename:    upload.Filename,
+		ContentType: upload.ContentType,
+		Size:        upload.Length,
+		StorageKey:  upload.StorageKey,
+	}
+	if err := s.assetRepo.CompleteUpload(ctx, upload, asset); err != nil {
+		return nil, fmt.Errorf("アセットの作成に失敗しました: %v", err)
+	}
+
+	// 画像の場合は一覧などで使う派生画像をバックグラウンドで作成する（スケッチが読み込む元のファイルはそのまま残す）
+	if assetImageExtensions[strings.ToLower(filepath.Ext(asset.Filename))] {
+		go s.processImage(asset)
+	}
+
+	return upload, nil
+}
+
+// processImage 画像のアセットから派生画像を作成し、アセットに関連付ける
+func (s *assetService) processImage(asset *models.WorkAsset) {
+	ctx, cancel := context.WithTimeout(context.Background(), assetImageTimeout)
+	defer cancel()
+
+	file, err := s.storage.Open(ctx, asset.StorageKey)
+	if err != nil {
+		log.Printf("アセット %d の読み込みに失敗しました: %v", asset.ID, err)
+		return
+	}
+	defer file.Close()
+
+	image, err := s.imageService.Upload(ctx, asset.UserID, models.ImageKindAsset, file)
+	if err != nil {
+		if !errors.Is(err, ErrImageUploadUnavailable) {
+			log.Printf("アセット %d の派生画像の作成に失敗しました: %v", asset.ID, err)
+		}
+		return
+	}
+	if err := s.assetRepo.SetImage(ctx, asset.ID, image.ID); err != nil {
+		log.Printf("アセット %d に派生画像を関連付けられませんでした: %v", asset.ID, err)
+	}
+}
+
+// DeleteUpload 完了していないアップロードを中止
+func (s *assetService) DeleteUpload(ctx context.Context, id string, userID uint) error {
+	upload, err := s.GetUpload(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if upload.AssetID != nil {
+		return errors.New("完了したアップロードは中止できません")
+	}
+
+	if err := s.storage.Delete(ctx, upload.StorageKey); err != nil {
+		return fmt.Errorf("データの削除に失敗しました: %v", err)
+	}
+	return s.assetRepo.DeleteUpload(ctx, upload.ID)
+}
+
+// ListByWork 作品のアセット一覧を取得
+func (s *assetService) ListByWork(ctx context.Context, workID uint) ([]models.WorkAsset, error) {
+	if _, err := s.workRepo.FindByID(ctx, workID); err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	return s.assetRepo.ListByWork(ctx, workID)
+}
+
+// Open アセットのデータを読み込み用に開く（作品が削除されている場合は開けない）
+func (s *assetService) Open(ctx context.Context, id uint) (*models.WorkAsset, io.ReadSeekCloser, error) {
+	asset, err := s.assetRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, errors.New("アセットが見つかりません")
+	}
+	return s.open(ctx, asset)
+}
+
+// OpenByFilename 作品のアセットをファイル名で開く
+// 変換後のスケッチが loadImage("cat.png") のようにファイル名で読み込むために使用する
+func (s *assetService) OpenByFilename(ctx context.Context, workID uint, filename string) (*models.WorkAsset, io.ReadSeekCloser, error) {
+	asset, err := s.assetRepo.FindByWorkAndFilename(ctx, workID, filename)
+	if err != nil {
+		return nil, nil, errors.New("アセットが見つかりません")
+	}
+	return s.open(ctx, asset)
+}
+
+// open アセットのデータを開く
+func (s *assetService) open(ctx context.Context, asset *models.WorkAsset) (*models.WorkAsset, io.ReadSeekCloser, error) {
+	if _, err := s.workRepo.FindByID(ctx, asset.WorkID); err != nil {
+		return nil, nil, errors.New("アセットが見つかりません")
+	}
+
+	file, err := s.storage.Open(ctx, asset.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("アセットの読み込みに失敗しました: %v", err)
+	}
+	return asset, file, nil
+}
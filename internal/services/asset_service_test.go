@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository/memory"
+)
+
+// TestAssetServiceCreateUploadUsesExtensionContentType アセットの形式を拡張子から決め、HTML・SVGはダウンロードさせることを確認
+func TestAssetServiceCreateUploadUsesExtensionContentType(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	workRepo := memory.NewWorkRepository(store)
+	work := &models.Work{Title: "作品", PDEContent: "void setup() {}", UserID: 1}
+	if err := workRepo.Create(ctx, work); err != nil {
+		t.Fatalf("作品の作成に失敗しました: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Storage.AssetMaxSizeMB = 1
+	s := &assetService{
+		assetRepo: memory.NewAssetRepository(store),
+		workRepo:  workRepo,
+		config:    cfg,
+	}
+
+	tests := []struct {
+		filename string
+		want     string
+	}{
+		{"cat.PNG", "image/png"},
+		{"bgm.mp3", "audio/mpeg"},
+		{"data.json", "application/json"},
+		{"x.html", AssetDownloadContentType},
+		{"x.svg", AssetDownloadContentType},
+		{"noext", AssetDownloadContentType},
+	}
+	for _, tt := range tests {
+		upload, err := s.CreateUpload(ctx, work.UserID, work.ID, tt.filename, 10)
+		if err != nil {
+			t.Fatalf("%s: アップロードの作成に失敗しました: %v", tt.filename, err)
+		}
+		if upload.ContentType != tt.want {
+			t.Errorf("%s: ContentType = %q, want %q", tt.filename, upload.ContentType, tt.want)
+		}
+	}
+}
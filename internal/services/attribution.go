@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+const (
+	// 辿るフォーク元の最大数
+	maxAttributionDepth = 32
+	// クレジット情報をキャッシュする時間
+	attributionCacheTTL = 10 * time.Minute
+)
+
+// attributionEntry キャッシュされたクレジット情報
+type attributionEntry struct {
+	chain     []models.WorkAttribution
+	expiresAt time.Time
+}
+
+// attributionResolver フォーク元を辿ってクレジット情報を解決する
+type attributionResolver struct {
+	workRepo repository.WorkRepository
+
+	mu    sync.RWMutex
+	cache map[uint]attributionEntry // キーはフォーク元の作品ID
+}
+
+// newAttributionResolver attributionResolverを作成
+func newAttributionResolver(workRepo repository.WorkRepository) *attributionResolver {
+	return &attributionResolver{
+		workRepo: workRepo,
+		cache:    map[uint]attributionEntry{},
+	}
+}
+
+// Resolve 作品のフォーク元を近い順に辿ってクレジット情報を返す
+func (r *attributionResolver) Resolve(ctx context.Context, work *models.Work) []models.WorkAttribution {
+	if work.ForkedFromID == nil {
+		return nil
+	}
+
+	parentID := *work.ForkedFromID
+	r.mu.RLock()
+	entry, ok := r.cache[parentID]
+	r.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.chain
+	}
+
+	chain := []models.WorkAttribution{}
+	visited := map[uint]bool{work.ID: true}
+	for id := &parentID; id != nil && len(chain) < maxAttributionDepth; {
+		// 循環している場合は打ち切る
+		if visited[*id] {
+			break
+		}
+		visited[*id] = true
+
+		source, err := r.workRepo.FindForAttribution(ctx, *id)
+		if err != nil {
+			break
+		}
+		chain = append(chain, models.WorkAttribution{
+			WorkID:   source.ID,
+			Title:    source.Title,
+			UserID:   source.UserID,
+			Nickname: source.User.Nickname,
+			License:  source.License,
+			Deleted:  source.DeletedAt.Valid,
+		})
+		id = source.ForkedFromID
+	}
+
+	r.mu.Lock()
+	r.cache[parentID] = attributionEntry{chain: chain, expiresAt: time.Now().Add(attributionCacheTTL)}
+	r.mu.Unlock()
+
+	return chain
+}
+
+// Invalidate キャッシュを破棄（作品のタイトルやライセンスが変わった場合）
+func (r *attributionResolver) Invalidate() {
+	r.mu.Lock()
+	r.cache = map[uint]attributionEntry{}
+	r.mu.Unlock()
+}
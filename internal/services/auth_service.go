@@ -1,37 +1,76 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
 	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/config"
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 
 	"github.com/dgrijalva/jwt-go"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // AuthService 認証に関するサービスインターフェース
 type AuthService interface {
-	Register(email, password, name, nickname string) (*models.User, string, error)
-	Login(email, password string) (*models.User, string, error)
+	Register(ctx context.Context, email, password, name, nickname, inviteCode string) (*models.User, string, error)
+	Login(ctx context.Context, email, password string) (*models.User, string, error)
 	ValidateToken(tokenString string) (*Claims, error)
-	GetUserFromToken(tokenString string) (*models.User, error)
-	ChangePassword(userID uint, currentPassword, newPassword string) error
+	GetUserFromToken(ctx context.Context, tokenString string) (*models.User, error)
+	ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) error
+	AcceptInvitation(ctx context.Context, token, password, nickname string) (*models.User, string, error)
+	RequestEmailChange(ctx context.Context, userID uint, newEmail, password string) error
+	ConfirmEmailChange(ctx context.Context, token string) (*models.User, string, error)
+	IssueToken(userID uint) (string, error)
+	IssueRefreshToken(ctx context.Context, userID uint) (string, error)
+	Refresh(ctx context.Context, refreshToken string) (*models.User, string, string, error)
+	RevokeRefreshToken(ctx context.Context, refreshToken string) error
+	RevokeToken(ctx context.Context, tokenString string) error
+	RevokeAllTokens(ctx context.Context, userID uint) error
+	PurgeExpiredTokens(ctx context.Context) (int64, error)
 }
 
+// メールアドレス変更の確認リンクの有効期限
+const emailChangeExpiry = 24 * time.Hour
+
+// リフレッシュトークンの長さ
+const refreshTokenLength = 64
+
+// アクセストークンのID（jti）の長さ
+const tokenIDLength = 32
+
+// errInvalidRefreshToken 見つからない・期限切れ・無効にされたリフレッシュトークン
+var errInvalidRefreshToken = errors.New("リフレッシュトークンが無効です")
+
 // authService AuthServiceの実装
 type authService struct {
-	userRepo repository.UserRepository
-	config   *config.Config
+	userRepo            repository.UserRepository
+	refreshTokenRepo    repository.RefreshTokenRepository
+	revokedTokenRepo    repository.RevokedTokenRepository
+	mailService         MailService
+	mailTemplates       MailTemplateService
+	registrationService RegistrationService
+	config              *config.Config
 }
 
 // NewAuthService AuthServiceを作成
-func NewAuthService(userRepo repository.UserRepository, cfg *config.Config) AuthService {
+func NewAuthService(userRepo repository.UserRepository, refreshTokenRepo repository.RefreshTokenRepository, revokedTokenRepo repository.RevokedTokenRepository, mailService MailService, mailTemplates MailTemplateService, registrationService RegistrationService, cfg *config.Config) AuthService {
 	return &authService{
-		userRepo: userRepo,
-		config:   cfg,
+		userRepo:            userRepo,
+		refreshTokenRepo:    refreshTokenRepo,
+		revokedTokenRepo:    revokedTokenRepo,
+		mailService:         mailService,
+		mailTemplates:       mailTemplates,
+		registrationService: registrationService,
+		config:              cfg,
 	}
 }
 
@@ -41,18 +80,46 @@ type Claims struct {
 	jwt.StandardClaims
 }
 
-// Register ユーザー登録
-func (s *authService) Register(email, password, name, nickname string) (*models.User, string, error) {
+// Register ユーザー登録（招待制の場合は招待コードが必要）
+func (s *authService) Register(ctx context.Context, email, password, name, nickname, inviteCode string) (*models.User, string, error) {
 	// メールアドレスが既に使用されているか確認
-	existingUser, err := s.userRepo.FindByEmail(email)
+	existingUser, err := s.userRepo.FindByEmail(ctx, email)
 	if err == nil && existingUser != nil {
 		return nil, "", errors.New("このメールアドレスは既に使用されています")
 	}
 
+	// 登録を受け付けているか確認し、招待コードを使用する
+	code, err := s.registrationService.Redeem(ctx, inviteCode, email)
+	if err != nil {
+		return nil, "", err
+	}
+	user, err := s.createUser(ctx, email, password, name, nickname)
+	if err != nil {
+		s.registrationService.Release(context.Background(), code)
+		return nil, "", err
+	}
+
+	// JWTトークンを生成
+	token, err := s.generateToken(user.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, token, nil
+}
+
+// createUser ハンドルを割り当ててユーザーを作成
+func (s *authService) createUser(ctx context.Context, email, password, name, nickname string) (*models.User, error) {
 	// パスワードをハッシュ化
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, "", err
+		return nil, err
+	}
+
+	// ニックネームを元にハンドルを割り当て（後から変更できる）
+	handle, err := generateHandle(ctx, s.userRepo, nickname)
+	if err != nil {
+		return nil, err
 	}
 
 	// 新しいユーザーを作成
@@ -61,25 +128,20 @@ func (s *authService) Register(email, password, name, nickname string) (*models.
 		Password: string(hashedPassword),
 		Name:     name,
 		Nickname: nickname,
+		Handle:   &handle,
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
-		return nil, "", err
-	}
-
-	// JWTトークンを生成
-	token, err := s.generateToken(user.ID)
-	if err != nil {
-		return nil, "", err
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
 	}
 
-	return user, token, nil
+	return user, nil
 }
 
 // Login ログイン
-func (s *authService) Login(email, password string) (*models.User, string, error) {
+func (s *authService) Login(ctx context.Context, email, password string) (*models.User, string, error) {
 	// ユーザーを検索
-	user, err := s.userRepo.FindByEmail(email)
+	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
 		return nil, "", errors.New("メールアドレスまたはパスワードが正しくありません")
 	}
@@ -119,31 +181,50 @@ func (s *authService) ValidateToken(tokenString string) (*Claims, error) {
 }
 
 // GetUserFromToken トークンからユーザーを取得
-func (s *authService) GetUserFromToken(tokenString string) (*models.User, error) {
+func (s *authService) GetUserFromToken(ctx context.Context, tokenString string) (*models.User, error) {
 	claims, err := s.ValidateToken(tokenString)
 	if err != nil {
 		return nil, err
 	}
 
-	user, err := s.userRepo.FindByID(claims.UserID)
+	user, err := s.userRepo.FindByID(ctx, claims.UserID)
 	if err != nil {
 		return nil, err
 	}
 
+	// メールアドレスの変更より前に発行されたトークンは使えない
+	if user.TokensValidAfter != nil && claims.IssuedAt < user.TokensValidAfter.Unix() {
+		return nil, errors.New("無効なトークンです")
+	}
+
+	// ログアウトなどで無効にされたトークンは使えない（確認できない場合も拒否する）
+	if claims.Id != "" {
+		revoked, err := s.revokedTokenRepo.Exists(ctx, claims.Id)
+		if err != nil {
+			log.Printf("トークンの無効化の確認に失敗しました (UserID=%d): %v", claims.UserID, err)
+			return nil, errors.New("無効なトークンです")
+		}
+		if revoked {
+			return nil, errors.New("無効なトークンです")
+		}
+	}
+
 	return user, nil
 }
 
 // ChangePassword ユーザーのパスワードを変更
-func (s *authService) ChangePassword(userID uint, currentPassword, newPassword string) error {
+func (s *authService) ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) error {
 	// ユーザーを取得
-	user, err := s.userRepo.FindByID(userID)
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return err
 	}
 
-	// 現在のパスワードを検証
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
-		return errors.New("現在のパスワードが正しくありません")
+	// 現在のパスワードを検証（GitHubで登録してパスワードを設定していない場合は不要）
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+			return errors.New("現在のパスワードが正しくありません")
+		}
 	}
 
 	// 新しいパスワードをハッシュ化
@@ -154,7 +235,300 @@ func (s *authService) ChangePassword(userID uint, currentPassword, newPassword s
 
 	// パスワードを更新
 	user.Password = string(hashedPassword)
-	return s.userRepo.Update(user)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	// 盗まれたリフレッシュトークンを使えないよう、全てのログインのリフレッシュトークンを無効にする
+	if err := s.refreshTokenRepo.RevokeByUser(ctx, userID); err != nil {
+		log.Printf("リフレッシュトークンの無効化に失敗しました (UserID=%d): %v", userID, err)
+	}
+	return nil
+}
+
+// AcceptInvitation 招待を承認してパスワードを設定し、仮登録ユーザーを有効にする
+// nicknameを省略した場合は仮登録時のものを使う
+func (s *authService) AcceptInvitation(ctx context.Context, token, password, nickname string) (*models.User, string, error) {
+	user, err := s.userRepo.FindByInviteTokenHash(ctx, hashInviteToken(token))
+	if err != nil {
+		return nil, "", errors.New("招待が見つかりません")
+	}
+
+	// 有効期限を確認
+	if user.InviteExpiresAt != nil && user.InviteExpiresAt.Before(time.Now()) {
+		return nil, "", errors.New("招待の有効期限が切れています")
+	}
+
+	// パスワードをハッシュ化
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user.Password = string(hashedPassword)
+	if nickname != "" {
+		user.Nickname = nickname
+	}
+	user.Pending = false
+	user.InviteTokenHash = ""
+	user.InviteExpiresAt = nil
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, "", err
+	}
+
+	// JWTトークンを生成
+	jwtToken, err := s.generateToken(user.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, jwtToken, nil
+}
+
+// RequestEmailChange 新しいメールアドレスに確認メールを送信
+// 確認リンクを開くまではメールアドレスを変更しない
+func (s *authService) RequestEmailChange(ctx context.Context, userID uint, newEmail, password string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("ユーザーが見つかりません")
+	}
+
+	// 本人確認のためパスワードを検証
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errors.New("パスワードが正しくありません")
+	}
+
+	// バリデーション
+	if address, err := mail.ParseAddress(newEmail); err != nil || address.Address != newEmail {
+		return errors.New("無効なメールアドレスです")
+	}
+	if strings.EqualFold(newEmail, user.Email) {
+		return errors.New("現在と同じメールアドレスです")
+	}
+	if _, err := s.userRepo.FindByEmail(ctx, newEmail); err == nil {
+		return errors.New("このメールアドレスは既に使用されています")
+	}
+
+	// 確認トークンを保存（再度リクエストした場合は以前のリンクを無効にする）
+	token := utils.GenerateRandomString(inviteTokenLength)
+	expiresAt := time.Now().Add(emailChangeExpiry)
+	user.PendingEmail = newEmail
+	user.EmailTokenHash = hashInviteToken(token)
+	user.EmailTokenExpiresAt = &expiresAt
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("メールアドレスの変更に失敗しました: %v", err)
+	}
+
+	data := emailChangeMail{
+		Name:      user.Name,
+		NewEmail:  newEmail,
+		Link:      strings.TrimSuffix(s.config.Mail.FrontendURL, "/") + "/email/confirm?token=" + token,
+		ExpiresAt: expiresAt.Format("2006-01-02 15:04"),
+	}
+	message, err := s.mailTemplates.Render(MailTemplateEmailChange, data)
+	if err == nil {
+		err = s.mailService.Send(ctx, newEmail, message)
+	}
+	if err != nil {
+		return fmt.Errorf("確認メールの送信に失敗しました: %v", err)
+	}
+
+	// 現在のアドレスにも通知する（乗っ取りに気づけるように、送信のエラーはログ出力のみとする）
+	notice, err := s.mailTemplates.Render(MailTemplateEmailChangeNotice, data)
+	if err == nil {
+		err = s.mailService.Send(ctx, user.Email, notice)
+	}
+	if err != nil {
+		log.Printf("メールアドレス変更の通知の送信に失敗しました (UserID=%d): %v", user.ID, err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange 確認トークンを検証してメールアドレスを変更
+// 変更前に発行されたトークンは全て無効にし、新しいトークンを返す
+func (s *authService) ConfirmEmailChange(ctx context.Context, token string) (*models.User, string, error) {
+	user, err := s.userRepo.FindByEmailTokenHash(ctx, hashInviteToken(token))
+	if err != nil {
+		return nil, "", errors.New("確認リンクが見つかりません")
+	}
+
+	// 有効期限を確認
+	if user.EmailTokenExpiresAt == nil || user.EmailTokenExpiresAt.Before(time.Now()) {
+		return nil, "", errors.New("確認リンクの有効期限が切れています")
+	}
+
+	// リクエストの後に他のユーザーが登録していないか確認
+	if other, err := s.userRepo.FindByEmail(ctx, user.PendingEmail); err == nil && other.ID != user.ID {
+		return nil, "", errors.New("このメールアドレスは既に使用されています")
+	}
+
+	// 秒単位で比較するため、変更と同じ秒に発行するトークンは有効にする
+	validAfter := time.Now().Truncate(time.Second)
+	user.Email = user.PendingEmail
+	user.PendingEmail = ""
+	user.EmailTokenHash = ""
+	user.EmailTokenExpiresAt = nil
+	user.TokensValidAfter = &validAfter
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, "", fmt.Errorf("メールアドレスの変更に失敗しました: %v", err)
+	}
+
+	// JWTトークンを生成
+	jwtToken, err := s.generateToken(user.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return user, jwtToken, nil
+}
+
+// IssueToken パスワード以外の方法（GitHubなど）で認証したユーザーのトークンを発行
+func (s *authService) IssueToken(userID uint) (string, error) {
+	return s.generateToken(userID)
+}
+
+// IssueRefreshToken 新しいログインのリフレッシュトークンを発行
+func (s *authService) IssueRefreshToken(ctx context.Context, userID uint) (string, error) {
+	token, err := s.createRefreshToken(ctx, userID, utils.GenerateRandomString(refreshTokenLength), 0)
+	if err != nil {
+		return "", fmt.Errorf("リフレッシュトークンの発行に失敗しました: %v", err)
+	}
+	return token, nil
+}
+
+// Refresh リフレッシュトークンを新しいものに置き換え、アクセストークンを再発行
+// 使用済みのトークンが使われた場合は盗まれたものとして、同じログインのトークンを全て無効にする
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*models.User, string, string, error) {
+	current, err := s.refreshTokenRepo.FindByHash(ctx, hashInviteToken(refreshToken))
+	if err != nil {
+		return nil, "", "", errInvalidRefreshToken
+	}
+	if current.RevokedAt != nil || current.ExpiresAt.Before(time.Now()) {
+		return nil, "", "", errInvalidRefreshToken
+	}
+	if current.UsedAt != nil {
+		s.revokeReusedFamily(ctx, current)
+		return nil, "", "", errInvalidRefreshToken
+	}
+
+	// メールアドレスの変更より前に発行されたトークンは使えない
+	user, err := s.userRepo.FindByID(ctx, current.UserID)
+	if err != nil {
+		return nil, "", "", errInvalidRefreshToken
+	}
+	if user.TokensValidAfter != nil && current.CreatedAt.Before(*user.TokensValidAfter) {
+		return nil, "", "", errInvalidRefreshToken
+	}
+
+	next, err := s.createRefreshToken(ctx, user.ID, current.FamilyID, current.ID)
+	if err != nil {
+		// 同時に使われて他方が先に置き換えた場合も再利用として扱う
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.revokeReusedFamily(ctx, current)
+			return nil, "", "", errInvalidRefreshToken
+		}
+		return nil, "", "", fmt.Errorf("リフレッシュトークンの発行に失敗しました: %v", err)
+	}
+
+	token, err := s.generateToken(user.ID)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	return user, token, next, nil
+}
+
+// RevokeRefreshToken リフレッシュトークンと同じログインのトークンを全て無効にする（ログアウト）
+func (s *authService) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	current, err := s.refreshTokenRepo.FindByHash(ctx, hashInviteToken(refreshToken))
+	if err != nil {
+		return errInvalidRefreshToken
+	}
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, current.FamilyID); err != nil {
+		return fmt.Errorf("リフレッシュトークンの無効化に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// RevokeToken アクセストークンを有効期限より前に無効にする（ログアウト）
+func (s *authService) RevokeToken(ctx context.Context, tokenString string) error {
+	claims, err := s.ValidateToken(tokenString)
+	if err != nil {
+		return errors.New("無効なトークンです")
+	}
+	// jtiのない古いトークンは個別に無効にできない
+	if claims.Id == "" {
+		return errors.New("このトークンは無効にできません")
+	}
+
+	if err := s.revokedTokenRepo.Create(ctx, &models.RevokedToken{
+		JTI:       claims.Id,
+		UserID:    claims.UserID,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}); err != nil {
+		return fmt.Errorf("トークンの無効化に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// RevokeAllTokens ユーザーに発行済みのアクセストークンとリフレッシュトークンを全て無効にする（全ての端末からのログアウト）
+func (s *authService) RevokeAllTokens(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("ユーザーが見つかりません")
+	}
+
+	// 秒単位で比較するため、同じ秒に発行されたトークンは有効のまま残る（呼び出し元で個別に無効にする）
+	validAfter := time.Now().Truncate(time.Second)
+	user.TokensValidAfter = &validAfter
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("トークンの無効化に失敗しました: %v", err)
+	}
+	if err := s.refreshTokenRepo.RevokeByUser(ctx, userID); err != nil {
+		return fmt.Errorf("リフレッシュトークンの無効化に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// PurgeExpiredTokens 有効期限を過ぎたリフレッシュトークンと、無効にしたアクセストークンの記録を削除
+func (s *authService) PurgeExpiredTokens(ctx context.Context) (int64, error) {
+	now := time.Now()
+	purged, err := s.refreshTokenRepo.DeleteExpired(ctx, now)
+	if err != nil {
+		return purged, err
+	}
+	revoked, err := s.revokedTokenRepo.DeleteExpired(ctx, now)
+	return purged + revoked, err
+}
+
+// createRefreshToken リフレッシュトークンを作成して保存
+// usedIDを指定した場合はそのトークンを使用済みにして置き換え、familyIDが空の場合は新しいログインとして扱う
+func (s *authService) createRefreshToken(ctx context.Context, userID uint, familyID string, usedID uint) (string, error) {
+	if usedID == 0 {
+		familyID = utils.GenerateRandomString(refreshTokenLength)
+	}
+	token := utils.GenerateRandomString(refreshTokenLength)
+	refreshToken := &models.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashInviteToken(token),
+		ExpiresAt: time.Now().Add(s.config.Auth.RefreshTokenExpiry),
+	}
+
+	if usedID == 0 {
+		err := s.refreshTokenRepo.Create(ctx, refreshToken)
+		return token, err
+	}
+	return token, s.refreshTokenRepo.Rotate(ctx, usedID, refreshToken)
+}
+
+// revokeReusedFamily 使用済みのリフレッシュトークンが再び使われたため、同じログインのトークンを全て無効にする
+func (s *authService) revokeReusedFamily(ctx context.Context, reused *models.RefreshToken) {
+	log.Printf("使用済みのリフレッシュトークンが使われたため、同じログインのトークンを無効にします (UserID=%d, TokenID=%d)", reused.UserID, reused.ID)
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, reused.FamilyID); err != nil {
+		log.Printf("リフレッシュトークンの無効化に失敗しました (UserID=%d): %v", reused.UserID, err)
+	}
 }
 
 // generateToken JWTトークンを生成
@@ -166,6 +540,7 @@ func (s *authService) generateToken(userID uint) (string, error) {
 	claims := &Claims{
 		UserID: userID,
 		StandardClaims: jwt.StandardClaims{
+			Id:        utils.GenerateRandomString(tokenIDLength),
 			ExpiresAt: expirationTime.Unix(),
 			IssuedAt:  time.Now().Unix(),
 		},
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// BackupService データベースのバックアップに関するサービスインターフェース
+type BackupService interface {
+	Run(ctx context.Context) (*BackupFile, error)
+	List(ctx context.Context) ([]BackupFile, error)
+	Restore(ctx context.Context, name string) (int64, error)
+}
+
+// BackupFile 保存されているバックアップ
+type BackupFile struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// backupHeader バックアップの先頭行（以降の行は1行に1レコード）
+type backupHeader struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Tables    []string  `json:"tables"`
+}
+
+// backupRecord バックアップの1レコード
+type backupRecord struct {
+	Table string                 `json:"table"`
+	Row   map[string]interface{} `json:"row"`
+}
+
+const (
+	// バックアップの形式のバージョン
+	backupVersion = 1
+	// バックアップのファイル名（UTCの日時で並べ替えられるようにする）
+	backupNameLayout = "backup-20060102-150405.jsonl.gz"
+	// 復元時に1回のINSERTで書き込むレコード数
+	backupRestoreBatchSize = 500
+	// 日時を書き込む形式（接続時のタイムゾーンのままMySQLに戻せるようにする）
+	backupTimeLayout = "2006-01-02 15:04:05.999999"
+)
+
+// backupService BackupServiceの実装
+type backupService struct {
+	db      *gorm.DB
+	storage StorageService
+	config  *config.Config
+}
+
+// NewBackupService BackupServiceを作成
+func NewBackupService(db *gorm.DB, storage StorageService, cfg *config.Config) BackupService {
+	return &backupService{
+		db:      db,
+		storage: storage,
+		config:  cfg,
+	}
+}
+
+// Run テーブルのレコードをgzip圧縮したJSON Linesで保存先に書き出し、保持数を超えた古いバックアップを削除
+func (s *backupService) Run(ctx context.Context) (*BackupFile, error) {
+	tables, err := s.tables()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	name := now.Format(backupNameLayout)
+	key := s.key(name)
+
+	// ストレージへの書き込みと並行してダンプする
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(s.dump(ctx, pw, tables, now))
+	}()
+	size, err := s.storage.WriteAt(ctx, key, 0, pr)
+	pr.Close()
+	if err != nil {
+		if deleteErr := s.storage.Delete(context.Background(), key); deleteErr != nil {
+			log.Printf("書き込みに失敗したバックアップ %s の削除に失敗しました: %v", name, deleteErr)
+		}
+		return nil, fmt.Errorf("バックアップに失敗しました: %v", err)
+	}
+
+	if err := s.prune(ctx); err != nil {
+		log.Printf("古いバックアップの削除に失敗しました: %v", err)
+	}
+
+	return &BackupFile{Name: name, Size: size, CreatedAt: now}, nil
+}
+
+// List 保存されているバックアップを新しい順に取得
+func (s *backupService) List(ctx context.Context) ([]BackupFile, error) {
+	objects, err := s.storage.List(ctx, s.config.Backup.Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("バックアップの一覧の取得に失敗しました: %v", err)
+	}
+
+	backups := []BackupFile{}
+	for _, object := range objects {
+		name := path.Base(object.Key)
+		createdAt, err := time.Parse(backupNameLayout, name)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, BackupFile{Name: name, Size: object.Size, CreatedAt: createdAt})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+	return backups, nil
+}
+
+// Restore バックアップに含まれるテーブルの内容を置き換え、書き込んだレコード数を返す
+// 外部キーの確認を外して1つのトランザクションで書き込むため、途中で失敗した場合は元の状態に戻る
+func (s *backupService) Restore(ctx context.Context, name string) (int64, error) {
+	if _, err := time.Parse(backupNameLayout, name); err != nil {
+		return 0, errors.New("無効なバックアップ名です")
+	}
+
+	file, err := s.storage.Open(ctx, s.key(name))
+	if err != nil {
+		return 0, errors.New("バックアップが見つかりません")
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return 0, fmt.Errorf("バックアップを読み込めません: %v", err)
+	}
+	defer gz.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(gz))
+	decoder.UseNumber()
+
+	var header backupHeader
+	if err := decoder.Decode(&header); err != nil {
+		return 0, fmt.Errorf("バックアップを読み込めません: %v", err)
+	}
+	if header.Version != backupVersion {
+		return 0, fmt.Errorf("対応していないバックアップの形式です（バージョン%d）", header.Version)
+	}
+
+	var restored int64
+	err = s.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SET FOREIGN_KEY_CHECKS = 0").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SET FOREIGN_KEY_CHECKS = 1")
+
+		return conn.Transaction(func(tx *gorm.DB) error {
+			for _, table := range header.Tables {
+				if err := tx.Exec(fmt.Sprintf("DELETE FROM `%s`", table)).Error; err != nil {
+					return fmt.Errorf("%s: %v", table, err)
+				}
+			}
+
+			// テーブルごとにまとめて書き込む（ダンプ時にテーブルの順に書き出している）
+			var table string
+			var rows []map[string]interface{}
+			flush := func() error {
+				if len(rows) == 0 {
+					return nil
+				}
+				if err := tx.Table(table).Create(&rows).Error; err != nil {
+					return fmt.Errorf("%s: %v", table, err)
+				}
+				restored += int64(len(rows))
+				rows = nil
+				return nil
+			}
+
+			for {
+				var record backupRecord
+				if err := decoder.Decode(&record); err == io.EOF {
+					break
+				} else if err != nil {
+					return fmt.Errorf("バックアップを読み込めません: %v", err)
+				}
+				if record.Table != table || len(rows) >= backupRestoreBatchSize {
+					if err := flush(); err != nil {
+						return err
+					}
+					table = record.Table
+				}
+				rows = append(rows, record.Row)
+			}
+			return flush()
+		})
+	})
+	if err != nil {
+		return 0, fmt.Errorf("バックアップの復元に失敗しました: %v", err)
+	}
+	return restored, nil
+}
+
+// dump テーブルのレコードを1つの読み取りトランザクションで書き出す
+func (s *backupService) dump(ctx context.Context, w io.Writer, tables []string, createdAt time.Time) error {
+	gz := gzip.NewWriter(w)
+	encoder := json.NewEncoder(gz)
+	if err := encoder.Encode(backupHeader{Version: backupVersion, CreatedAt: createdAt, Tables: tables}); err != nil {
+		return err
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables {
+			rows, err := tx.Table(table).Rows()
+			if err != nil {
+				return fmt.Errorf("%s: %v", table, err)
+			}
+			for rows.Next() {
+				row := map[string]interface{}{}
+				if err := tx.ScanRows(rows, &row); err != nil {
+					rows.Close()
+					return fmt.Errorf("%s: %v", table, err)
+				}
+				for column, value := range row {
+					if t, ok := value.(time.Time); ok {
+						row[column] = t.Format(backupTimeLayout)
+					}
+				}
+				if err := encoder.Encode(backupRecord{Table: table, Row: row}); err != nil {
+					rows.Close()
+					return err
+				}
+			}
+			if err := rows.Close(); err != nil {
+				return fmt.Errorf("%s: %v", table, err)
+			}
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// prune 保持数を超えた古いバックアップを削除
+func (s *backupService) prune(ctx context.Context) error {
+	if s.config.Backup.Retention <= 0 {
+		return nil
+	}
+
+	backups, err := s.List(ctx)
+	if err != nil {
+		return err
+	}
+	for i := s.config.Backup.Retention; i < len(backups); i++ {
+		if err := s.storage.Delete(ctx, s.key(backups[i].Name)); err != nil {
+			return err
+		}
+		log.Printf("古いバックアップ %s を削除しました", backups[i].Name)
+	}
+	return nil
+}
+
+// tables バックアップするテーブル（モデルのテーブルと多対多の中間テーブルから除外するテーブルを除く）
+func (s *backupService) tables() ([]string, error) {
+	excluded := map[string]bool{}
+	for _, table := range s.config.Backup.ExcludeTables {
+		excluded[table] = true
+	}
+
+	tables := []string{}
+	seen := map[string]bool{}
+	add := func(table string) {
+		if !seen[table] && !excluded[table] {
+			seen[table] = true
+			tables = append(tables, table)
+		}
+	}
+	for _, model := range models.AllModels() {
+		stmt := &gorm.Statement{DB: s.db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("テーブル名の取得に失敗しました: %v", err)
+		}
+		add(stmt.Schema.Table)
+		for _, rel := range stmt.Schema.Relationships.Many2Many {
+			add(rel.JoinTable.Table)
+		}
+	}
+	return tables, nil
+}
+
+// key バックアップのストレージのキー
+func (s *backupService) key(name string) string {
+	return strings.TrimSuffix(s.config.Backup.Prefix, "/") + "/" + name
+}
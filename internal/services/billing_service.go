@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidWebhookSignature Webhookの署名が正しくない
+var ErrInvalidWebhookSignature = errors.New("Webhookの署名が正しくありません")
+
+// BillingService 料金プランの契約（Stripe）に関するサービスインターフェース
+type BillingService interface {
+	HandleStripeWebhook(ctx context.Context, payload []byte, signature string) error
+}
+
+// stripeEvent StripeのWebhookのイベント（使用する項目のみ）
+type stripeEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object stripeSubscription `json:"object"`
+	} `json:"data"`
+}
+
+// stripeSubscription Stripeのサブスクリプション（使用する項目のみ）
+type stripeSubscription struct {
+	ID               string            `json:"id"`
+	Customer         string            `json:"customer"`
+	Status           string            `json:"status"`
+	CurrentPeriodEnd int64             `json:"current_period_end"`
+	Metadata         map[string]string `json:"metadata"` // Checkoutの作成時にuser_idを設定する
+	Items            struct {
+		Data []struct {
+			Price struct {
+				ID string `json:"id"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// billingService BillingServiceの実装
+type billingService struct {
+	subscriptionRepo repository.SubscriptionRepository
+	config           *config.Config
+}
+
+// NewBillingService BillingServiceを作成
+func NewBillingService(subscriptionRepo repository.SubscriptionRepository, cfg *config.Config) BillingService {
+	return &billingService{
+		subscriptionRepo: subscriptionRepo,
+		config:           cfg,
+	}
+}
+
+// HandleStripeWebhook StripeのWebhookを検証し、サブスクリプションの状態をユーザーの契約に反映
+// 対象外のイベントや、ユーザーを特定できないイベントは何もせずに受け付ける
+func (s *billingService) HandleStripeWebhook(ctx context.Context, payload []byte, signature string) error {
+	if err := s.verifySignature(payload, signature); err != nil {
+		return err
+	}
+
+	var event stripeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("Webhookの内容が正しくありません: %v", err)
+	}
+
+	switch event.Type {
+	case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+	default:
+		return nil
+	}
+
+	object := event.Data.Object
+	plan := s.planForSubscription(&object)
+	if plan == "" {
+		log.Printf("Stripeのイベント %s は料金プランに対応する価格IDがないため無視しました", event.ID)
+		return nil
+	}
+
+	userID, err := s.userForSubscription(ctx, &object)
+	if err != nil {
+		return err
+	}
+	if userID == 0 {
+		log.Printf("Stripeのイベント %s はユーザーを特定できないため無視しました", event.ID)
+		return nil
+	}
+
+	subscription := &models.Subscription{
+		UserID:               userID,
+		Plan:                 plan,
+		Status:               object.Status,
+		StripeCustomerID:     object.Customer,
+		StripeSubscriptionID: object.ID,
+		EventAt:              time.Unix(event.Created, 0),
+	}
+	if event.Type == "customer.subscription.deleted" {
+		subscription.Status = "canceled"
+	}
+	if object.CurrentPeriodEnd > 0 {
+		periodEnd := time.Unix(object.CurrentPeriodEnd, 0)
+		subscription.CurrentPeriodEnd = &periodEnd
+	}
+
+	if _, err := s.subscriptionRepo.Save(ctx, subscription); err != nil {
+		return fmt.Errorf("契約の保存に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// verifySignature Stripe-Signatureヘッダー（t=タイムスタンプ,v1=署名）を検証
+func (s *billingService) verifySignature(payload []byte, header string) error {
+	secret := s.config.Billing.StripeWebhookSecret
+	if secret == "" {
+		return ErrInvalidWebhookSignature
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidWebhookSignature
+	}
+	if tolerance := s.config.Billing.WebhookTolerance; tolerance > 0 {
+		age := time.Since(time.Unix(unix, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrInvalidWebhookSignature
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, signature := range signatures {
+		actual, err := hex.DecodeString(signature)
+		if err == nil && hmac.Equal(actual, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidWebhookSignature
+}
+
+// planForSubscription サブスクリプションの価格IDから料金プランを判定（該当しない場合は空文字）
+func (s *billingService) planForSubscription(object *stripeSubscription) string {
+	for _, item := range object.Items.Data {
+		switch {
+		case item.Price.ID == "":
+		case item.Price.ID == s.config.Billing.Org.StripePriceID:
+			return models.PlanOrg
+		case item.Price.ID == s.config.Billing.Pro.StripePriceID:
+			return models.PlanPro
+		}
+	}
+	return ""
+}
+
+// userForSubscription サブスクリプションの契約者を特定（metadataのuser_id、なければ顧客IDの既存の契約から）
+// 特定できない場合は0を返す
+func (s *billingService) userForSubscription(ctx context.Context, object *stripeSubscription) (uint, error) {
+	if value := object.Metadata["user_id"]; value != "" {
+		userID, err := strconv.ParseUint(value, 10, 32)
+		if err == nil && userID > 0 {
+			return uint(userID), nil
+		}
+	}
+
+	if object.Customer == "" {
+		return 0, nil
+	}
+	existing, err := s.subscriptionRepo.FindByStripeCustomer(ctx, object.Customer)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("契約の取得に失敗しました: %v", err)
+	}
+	return existing.UserID, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository/memory"
+)
+
+// TestBillingServiceIgnoresLateCancellationOfReplacedSubscription プランの変更後に古いサブスクリプションの終了が遅れて届いても、新しい契約が残ることを確認
+func TestBillingServiceIgnoresLateCancellationOfReplacedSubscription(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{}
+	cfg.Billing.StripeWebhookSecret = "whsec_test"
+	cfg.Billing.Pro.StripePriceID = "price_pro"
+	cfg.Billing.Org.StripePriceID = "price_org"
+	subscriptionRepo := memory.NewSubscriptionRepository(memory.NewStore())
+	s := NewBillingService(subscriptionRepo, cfg)
+
+	send := func(eventType, subscriptionID, status, priceID string, created time.Time) {
+		t.Helper()
+		payload := fmt.Sprintf(`{"id":"evt_%s_%d","type":%q,"created":%d,"data":{"object":{"id":%q,"customer":"cus_1","status":%q,"metadata":{"user_id":"1"},"items":{"data":[{"price":{"id":%q}}]}}}}`,
+			subscriptionID, created.Unix(), eventType, created.Unix(), subscriptionID, status, priceID)
+		timestamp := fmt.Sprint(time.Now().Unix())
+		mac := hmac.New(sha256.New, []byte(cfg.Billing.StripeWebhookSecret))
+		mac.Write([]byte(timestamp + "." + payload))
+		signature := "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+		if err := s.HandleStripeWebhook(ctx, []byte(payload), signature); err != nil {
+			t.Fatalf("%s: Webhookの処理に失敗しました: %v", eventType, err)
+		}
+	}
+
+	now := time.Now()
+	send("customer.subscription.created", "sub_old", "active", "price_pro", now.Add(-time.Hour))
+	send("customer.subscription.created", "sub_new", "active", "price_org", now.Add(-time.Minute))
+	// 古いサブスクリプションの終了が新しいサブスクリプションの作成より後に届く
+	send("customer.subscription.deleted", "sub_old", "canceled", "price_pro", now)
+
+	subscription, err := subscriptionRepo.FindByUser(ctx, 1)
+	if err != nil {
+		t.Fatalf("契約の取得に失敗しました: %v", err)
+	}
+	if subscription.StripeSubscriptionID != "sub_new" || subscription.Plan != models.PlanOrg || !subscription.IsActive() {
+		t.Errorf("契約 = %s %s %s, want sub_new org active", subscription.StripeSubscriptionID, subscription.Plan, subscription.Status)
+	}
+
+	// 現在のサブスクリプションの終了は反映される
+	send("customer.subscription.deleted", "sub_new", "canceled", "price_org", now.Add(time.Minute))
+	subscription, err = subscriptionRepo.FindByUser(ctx, 1)
+	if err != nil {
+		t.Fatalf("契約の取得に失敗しました: %v", err)
+	}
+	if subscription.IsActive() {
+		t.Errorf("現在のサブスクリプションの終了が反映されていません: %s", subscription.Status)
+	}
+}
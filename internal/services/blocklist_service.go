@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// BlocklistService IPアドレス・国によるアクセス制限に関するサービスインターフェース
+type BlocklistService interface {
+	List(ctx context.Context) ([]models.IPBlock, error)
+	Add(ctx context.Context, cidr, reason string, expiresAt *time.Time, userID uint) (*models.IPBlock, error)
+	Remove(ctx context.Context, id uint) error
+	IsBlocked(ctx context.Context, ip string) bool
+	IsCountryRestricted(country string) bool
+}
+
+// ブロックリストを読み込み直す間隔（複数のサーバーで動かしている場合の反映までの時間）
+const blocklistCacheTTL = time.Minute
+
+// blockedNetwork 読み込み済みのブロック
+type blockedNetwork struct {
+	network   *net.IPNet
+	expiresAt *time.Time
+}
+
+// blocklistService BlocklistServiceの実装
+type blocklistService struct {
+	ipBlockRepo repository.IPBlockRepository
+	blocked     map[string]bool // 書き込みを拒否する国コード
+	allowed     map[string]bool // 書き込みを許可する国コード（空の場合は全て）
+
+	mu       sync.RWMutex
+	networks []blockedNetwork
+	loadedAt time.Time
+}
+
+// NewBlocklistService BlocklistServiceを作成
+func NewBlocklistService(ipBlockRepo repository.IPBlockRepository, cfg *config.Config) BlocklistService {
+	return &blocklistService{
+		ipBlockRepo: ipBlockRepo,
+		blocked:     countrySet(cfg.Access.BlockedCountries),
+		allowed:     countrySet(cfg.Access.AllowedCountries),
+	}
+}
+
+// countrySet 国コードの一覧を大文字のセットにする
+func countrySet(countries []string) map[string]bool {
+	set := make(map[string]bool, len(countries))
+	for _, country := range countries {
+		set[strings.ToUpper(country)] = true
+	}
+	return set
+}
+
+// List ブロックリストを取得
+func (s *blocklistService) List(ctx context.Context) ([]models.IPBlock, error) {
+	return s.ipBlockRepo.List(ctx)
+}
+
+// Add IPアドレスまたはCIDRをブロックリストに追加
+func (s *blocklistService) Add(ctx context.Context, cidr, reason string, expiresAt *time.Time, userID uint) (*models.IPBlock, error) {
+	normalized, err := normalizeCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.New("有効期限は現在より後の日時を指定してください")
+	}
+
+	if _, err := s.ipBlockRepo.FindByCIDR(ctx, normalized); err == nil {
+		return nil, fmt.Errorf("%s は既にブロックリストに登録されています", normalized)
+	}
+
+	block := &models.IPBlock{
+		CIDR:      normalized,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+		CreatedBy: userID,
+	}
+	if err := s.ipBlockRepo.Create(ctx, block); err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	return block, nil
+}
+
+// Remove ブロックリストから削除
+func (s *blocklistService) Remove(ctx context.Context, id uint) error {
+	if _, err := s.ipBlockRepo.FindByID(ctx, id); err != nil {
+		return errors.New("ブロックが見つかりません")
+	}
+	if err := s.ipBlockRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// IsBlocked IPアドレスがブロックリストに含まれているか
+func (s *blocklistService) IsBlocked(ctx context.Context, ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+
+	now := time.Now()
+	for _, blocked := range s.load(ctx) {
+		if blocked.expiresAt != nil && !blocked.expiresAt.After(now) {
+			continue
+		}
+		if blocked.network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCountryRestricted 国からの書き込みが制限されているか（国が不明な場合は制限しない）
+func (s *blocklistService) IsCountryRestricted(country string) bool {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		return false
+	}
+	if s.blocked[country] {
+		return true
+	}
+	return len(s.allowed) > 0 && !s.allowed[country]
+}
+
+// load 読み込み済みのブロックリストを返す（古くなっている場合は読み込み直す）
+func (s *blocklistService) load(ctx context.Context) []blockedNetwork {
+	s.mu.RLock()
+	if time.Since(s.loadedAt) < blocklistCacheTTL {
+		networks := s.networks
+		s.mu.RUnlock()
+		return networks
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.loadedAt) < blocklistCacheTTL {
+		return s.networks
+	}
+
+	blocks, err := s.ipBlockRepo.ListActive(ctx, time.Now())
+	if err != nil {
+		// 読み込みに失敗した場合は前回のブロックリストを使い続ける
+		log.Printf("ブロックリストの読み込みに失敗しました: %v", err)
+		return s.networks
+	}
+
+	networks := make([]blockedNetwork, 0, len(blocks))
+	for _, block := range blocks {
+		_, network, err := net.ParseCIDR(block.CIDR)
+		if err != nil {
+			log.Printf("無効なブロックをスキップしました (ID=%d): %v", block.ID, err)
+			continue
+		}
+		networks = append(networks, blockedNetwork{network: network, expiresAt: block.ExpiresAt})
+	}
+	s.networks = networks
+	s.loadedAt = time.Now()
+	return networks
+}
+
+// invalidate 次のリクエストでブロックリストを読み込み直す
+func (s *blocklistService) invalidate() {
+	s.mu.Lock()
+	s.loadedAt = time.Time{}
+	s.mu.Unlock()
+}
+
+// normalizeCIDR IPアドレスまたはCIDRを正規化（単一のアドレスは /32 または /128 にする）
+func normalizeCIDR(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", errors.New("IPアドレスまたはCIDRを指定してください")
+	}
+
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return "", fmt.Errorf("無効なIPアドレスです: %s", value)
+		}
+		if ip.To4() != nil {
+			value += "/32"
+		} else {
+			value += "/128"
+		}
+	}
+
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return "", fmt.Errorf("無効なCIDRです: %s", value)
+	}
+	return network.String(), nil
+}
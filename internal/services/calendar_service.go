@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// CalendarService プロジェクトのカレンダー（iCal）に関するサービスインターフェース
+type CalendarService interface {
+	FeedURL(ctx context.Context, projectID, userID uint) (string, error)
+	Feed(ctx context.Context, projectID uint, token string) ([]byte, error)
+}
+
+// iCalの1行の最大オクテット数（これを超える行は折り返す）
+const icalLineLimit = 75
+
+// calendarService CalendarServiceの実装
+type calendarService struct {
+	projectRepo repository.ProjectRepository
+	taskRepo    repository.TaskRepository
+	config      *config.Config
+}
+
+// NewCalendarService CalendarServiceを作成
+func NewCalendarService(
+	projectRepo repository.ProjectRepository,
+	taskRepo repository.TaskRepository,
+	cfg *config.Config,
+) CalendarService {
+	return &calendarService{
+		projectRepo: projectRepo,
+		taskRepo:    taskRepo,
+		config:      cfg,
+	}
+}
+
+// FeedURL カレンダーアプリで購読するためのURLを取得（ユーザーごとの署名付きトークンを含む）
+func (s *calendarService) FeedURL(ctx context.Context, projectID, userID uint) (string, error) {
+	if _, err := s.projectRepo.FindByID(ctx, projectID); err != nil {
+		return "", errors.New("プロジェクトが見つかりません")
+	}
+
+	// 権限チェック
+	isMember, err := s.projectRepo.IsMember(ctx, projectID, userID)
+	if err != nil || !isMember {
+		return "", errors.New("このプロジェクトのカレンダーを取得する権限がありません")
+	}
+
+	return fmt.Sprintf("%s/api/v1/projects/%d/calendar.ics?token=%s",
+		strings.TrimSuffix(s.config.Server.APIBaseURL, "/"), projectID, s.sign(projectID, userID)), nil
+}
+
+// Feed タスクの締め切りと投票の締め切りをiCal形式で取得
+// カレンダーアプリはAuthorizationヘッダーを送れないため、URLのトークンで認証する
+func (s *calendarService) Feed(ctx context.Context, projectID uint, token string) ([]byte, error) {
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, errors.New("プロジェクトが見つかりません")
+	}
+
+	// トークンを検証し、プロジェクトを抜けたユーザーは取得できないようにする
+	userID, ok := s.verify(projectID, token)
+	if !ok {
+		return nil, errors.New("このプロジェクトのカレンダーを取得する権限がありません")
+	}
+	isMember, err := s.projectRepo.IsMember(ctx, projectID, userID)
+	if err != nil || !isMember {
+		return nil, errors.New("このプロジェクトのカレンダーを取得する権限がありません")
+	}
+
+	tasks, err := s.taskRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	votes, err := s.projectRepo.ListScheduledVotes(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//SketchShifter//Project Calendar//JA")
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	writeICalLine(&b, "METHOD:PUBLISH")
+	writeICalLine(&b, "X-WR-CALNAME:"+escapeICalText(project.Title))
+
+	for _, task := range tasks {
+		if task.DueAt == nil {
+			continue
+		}
+		writeICalEvent(&b, fmt.Sprintf("task-%d", task.ID), task.UpdatedAt, *task.DueAt,
+			"締め切り: "+task.Title, task.Description)
+	}
+	for _, vote := range votes {
+		writeICalEvent(&b, fmt.Sprintf("vote-%d", vote.ID), vote.UpdatedAt, *vote.ClosesAt,
+			"投票締め切り: "+vote.Title, vote.Description)
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+	return []byte(b.String()), nil
+}
+
+// sign プロジェクトとユーザーの組み合わせに対するトークンを作成（ユーザーID.署名）
+func (s *calendarService) sign(projectID, userID uint) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Auth.JWTSecret))
+	fmt.Fprintf(mac, "calendar:%d:%d", projectID, userID)
+	return fmt.Sprintf("%d.%s", userID, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// verify トークンを検証してユーザーIDを返す
+func (s *calendarService) verify(projectID uint, token string) (uint, bool) {
+	id, _, found := strings.Cut(token, ".")
+	if !found {
+		return 0, false
+	}
+	userID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	if !hmac.Equal([]byte(token), []byte(s.sign(projectID, uint(userID)))) {
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+// writeICalEvent 締め切りを時間の長さのない予定として書き込む
+func writeICalEvent(b *strings.Builder, uid string, updatedAt, at time.Time, summary, description string) {
+	writeICalLine(b, "BEGIN:VEVENT")
+	writeICalLine(b, "UID:"+uid+"@sketchshifter")
+	writeICalLine(b, "DTSTAMP:"+formatICalTime(updatedAt))
+	writeICalLine(b, "DTSTART:"+formatICalTime(at))
+	writeICalLine(b, "DTEND:"+formatICalTime(at))
+	writeICalLine(b, "SUMMARY:"+escapeICalText(summary))
+	if description != "" {
+		writeICalLine(b, "DESCRIPTION:"+escapeICalText(description))
+	}
+	writeICalLine(b, "END:VEVENT")
+}
+
+// writeICalLine 1行を書き込む（75オクテットを超える場合はマルチバイト文字の途中を避けて折り返す）
+func writeICalLine(b *strings.Builder, line string) {
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > icalLineLimit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+}
+
+// formatICalTime UTCの日時（例: 20260101T090000Z）
+func formatICalTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// escapeICalText テキストの値をエスケープ
+func escapeICalText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
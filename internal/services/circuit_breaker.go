@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+)
+
+// ErrCircuitOpen 外部サービスへの呼び出しを遮断している場合のエラー
+var ErrCircuitOpen = errors.New("外部サービスが一時的に利用できません。しばらくしてから再度お試しください")
+
+// サーキットブレーカーの状態
+const (
+	CircuitClosed   = "closed"    // 通常どおり呼び出す
+	CircuitOpen     = "open"      // 呼び出さずにすぐ失敗させる
+	CircuitHalfOpen = "half_open" // 復旧したか確認するため1件だけ呼び出している
+)
+
+// CircuitBreakerStatus サーキットブレーカーの状態（ヘルスチェックで表示）
+type CircuitBreakerStatus struct {
+	State    string     `json:"state"`
+	Failures int        `json:"consecutive_failures"`
+	RetryAt  *time.Time `json:"retry_at,omitempty"` // 遮断中の場合、次に試しに呼び出す日時
+}
+
+// CircuitBreaker 外部サービスが連続して失敗した場合に一定時間呼び出しを遮断し、待たずに失敗させる
+type CircuitBreaker struct {
+	name            string
+	threshold       int
+	openTimeout     time.Duration
+	callTimeout     time.Duration
+	countsAsFailure func(err error) bool
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+}
+
+// NewCircuitBreaker CircuitBreakerを作成
+// callTimeoutが0より大きい場合は1回の呼び出しをその時間で打ち切る
+// countsAsFailureがfalseを返すエラー（入力の問題など）はサービスが応答したものとして扱う（nilの場合は全てのエラーを失敗とする）
+func NewCircuitBreaker(name string, cfg config.CircuitBreakerConfig, callTimeout time.Duration, countsAsFailure func(err error) bool) *CircuitBreaker {
+	return &CircuitBreaker{
+		name:            name,
+		threshold:       cfg.FailureThreshold,
+		openTimeout:     cfg.OpenTimeout,
+		callTimeout:     callTimeout,
+		countsAsFailure: countsAsFailure,
+		state:           CircuitClosed,
+	}
+}
+
+// Execute 遮断していなければfnを呼び出し、結果を記録する
+// 遮断中はfnを呼び出さずにErrCircuitOpenを返す
+func (b *CircuitBreaker) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	callCtx := ctx
+	if b.callTimeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, b.callTimeout)
+		defer cancel()
+	}
+
+	err := fn(callCtx)
+	switch {
+	case err == nil:
+		b.recordSuccess()
+	case ctx.Err() != nil:
+		// 呼び出し元がキャンセルした場合はサービスの状態が分からないため記録しない
+		b.release()
+	case b.countsAsFailure != nil && !b.countsAsFailure(err):
+		b.recordSuccess()
+	default:
+		b.recordFailure(err)
+	}
+	return err
+}
+
+// Status 現在の状態を取得
+func (b *CircuitBreaker) Status() CircuitBreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := CircuitBreakerStatus{State: b.state, Failures: b.failures}
+	if b.state == CircuitOpen {
+		retryAt := b.openedAt.Add(b.openTimeout)
+		status.RetryAt = &retryAt
+	}
+	return status
+}
+
+// allow 呼び出してよいか確認（遮断時間を過ぎていれば1件だけ試しに通す）
+func (b *CircuitBreaker) allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		return nil
+	case CircuitHalfOpen:
+		// 試しの呼び出しが終わるまでは遮断を続ける
+		return ErrCircuitOpen
+	default:
+		return nil
+	}
+}
+
+// recordSuccess 成功を記録（遮断を解除する）
+func (b *CircuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != CircuitClosed {
+		log.Printf("%s への呼び出しが復旧しました", b.name)
+	}
+	b.state = CircuitClosed
+	b.failures = 0
+}
+
+// recordFailure 失敗を記録（連続して失敗した回数が上限に達するか、試しの呼び出しが失敗した場合は遮断する）
+func (b *CircuitBreaker) recordFailure(err error) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		if b.state != CircuitOpen {
+			log.Printf("%s への呼び出しを %s 遮断します (連続%d回失敗): %v", b.name, b.openTimeout, b.failures, err)
+		}
+		b.state = CircuitOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// release 試しの呼び出しの結果を記録せずに、次の呼び出しで再び試せるようにする
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen {
+		b.state = CircuitOpen
+		b.openedAt = time.Now().Add(-b.openTimeout)
+	}
+}
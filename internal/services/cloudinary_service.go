@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"mime/multipart"
+	"strings"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/config"
 	"github.com/cloudinary/cloudinary-go/v2"
@@ -13,8 +14,30 @@ import (
 
 // CloudinaryService Cloudinaryとの連携を管理するサービス
 type CloudinaryService interface {
-	UploadImage(file multipart.File, fileName string, compressionQuality int) (string, string, error)
-	DeleteImage(publicID string) error
+	UploadImage(ctx context.Context, file multipart.File, fileName string, compressionQuality int) (string, string, error)
+	UploadImageWithVariants(ctx context.Context, data []byte, fileName string, transformations []string) (*CloudinaryUpload, error)
+	DeleteImage(ctx context.Context, publicID string) error
+	Ping(ctx context.Context) error
+}
+
+// CloudinaryUpload Cloudinaryにアップロードした画像
+type CloudinaryUpload struct {
+	PublicID string
+	URL      string
+	Format   string
+	Width    int
+	Height   int
+	Bytes    int64
+	Variants []CloudinaryVariant // アップロード時に作成した派生画像（transformationsと同じ順）
+}
+
+// CloudinaryVariant Cloudinaryの変換で作成した派生画像
+type CloudinaryVariant struct {
+	URL    string
+	Format string
+	Width  int
+	Height int
+	Bytes  int64
 }
 
 type cloudinaryService struct {
@@ -23,7 +46,7 @@ type cloudinaryService struct {
 }
 
 // NewCloudinaryService CloudinaryServiceを作成
-func NewCloudinaryService(cfg *config.Config) (CloudinaryService, error) {
+func NewCloudinaryService(httpClients HTTPClientFactory, cfg *config.Config) (CloudinaryService, error) {
 	cld, err := cloudinary.NewFromParams(
 		cfg.Cloudinary.CloudName,
 		cfg.Cloudinary.APIKey,
@@ -33,6 +56,11 @@ func NewCloudinaryService(cfg *config.Config) (CloudinaryService, error) {
 		return nil, err
 	}
 
+	// アップロードと疎通確認（Admin API）の通信にタイムアウトと再試行を設定
+	client := httpClients.Client("cloudinary", cfg.Cloudinary.Timeout)
+	cld.Upload.Client = *client
+	cld.Admin.Client = *client
+
 	return &cloudinaryService{
 		cld: cld,
 		cfg: cfg,
@@ -40,7 +68,7 @@ func NewCloudinaryService(cfg *config.Config) (CloudinaryService, error) {
 }
 
 // UploadImage 画像をアップロード
-func (s *cloudinaryService) UploadImage(file multipart.File, fileName string, compressionQuality int) (string, string, error) {
+func (s *cloudinaryService) UploadImage(ctx context.Context, file multipart.File, fileName string, compressionQuality int) (string, string, error) {
 	// ファイルデータを読み込み
 	buf := new(bytes.Buffer)
 	if _, err := buf.ReadFrom(file); err != nil {
@@ -57,7 +85,6 @@ func (s *cloudinaryService) UploadImage(file multipart.File, fileName string, co
 	}
 
 	// アップロード
-	ctx := context.Background()
 	result, err := s.cld.Upload.Upload(ctx, buf, uploadParams)
 
 	if err != nil {
@@ -67,14 +94,55 @@ func (s *cloudinaryService) UploadImage(file multipart.File, fileName string, co
 	return result.PublicID, result.SecureURL, nil
 }
 
+// UploadImageWithVariants 画像をそのままアップロードし、transformationsごとの派生画像を同時に作成する
+func (s *cloudinaryService) UploadImageWithVariants(ctx context.Context, data []byte, fileName string, transformations []string) (*CloudinaryUpload, error) {
+	uploadParams := uploader.UploadParams{
+		Folder:       s.cfg.Cloudinary.Folder,
+		PublicID:     fileName,
+		ResourceType: "image",
+		// 派生画像は表示時ではなくアップロード時に作成する
+		Eager: strings.Join(transformations, "|"),
+	}
+
+	result, err := s.cld.Upload.Upload(ctx, bytes.NewReader(data), uploadParams)
+	if err != nil {
+		return nil, fmt.Errorf("Cloudinaryへのアップロードに失敗しました: %v", err)
+	}
+	if result.Error.Message != "" {
+		return nil, fmt.Errorf("Cloudinaryへのアップロードに失敗しました: %s", result.Error.Message)
+	}
+
+	upload := &CloudinaryUpload{
+		PublicID: result.PublicID,
+		URL:      result.SecureURL,
+		Format:   result.Format,
+		Width:    result.Width,
+		Height:   result.Height,
+		Bytes:    int64(result.Bytes),
+	}
+	for _, eager := range result.Eager {
+		upload.Variants = append(upload.Variants, CloudinaryVariant{
+			URL:    eager.SecureURL,
+			Format: eager.Format,
+			Width:  eager.Width,
+			Height: eager.Height,
+			Bytes:  int64(eager.Bytes),
+		})
+	}
+	if len(upload.Variants) != len(transformations) {
+		return nil, fmt.Errorf("Cloudinaryで派生画像の作成に失敗しました（%d件中%d件）", len(transformations), len(upload.Variants))
+	}
+	return upload, nil
+}
+
 // DeleteImage 画像を削除
-func (s *cloudinaryService) DeleteImage(publicID string) error {
+func (s *cloudinaryService) DeleteImage(ctx context.Context, publicID string) error {
 	if publicID == "" {
 		return nil
 	}
 
 	// Cloudinaryから画像を削除
-	_, err := s.cld.Upload.Destroy(context.Background(), uploader.DestroyParams{
+	_, err := s.cld.Upload.Destroy(ctx, uploader.DestroyParams{
 		PublicID: publicID,
 	})
 
@@ -84,3 +152,58 @@ func (s *cloudinaryService) DeleteImage(publicID string) error {
 
 	return nil
 }
+
+// Ping Cloudinary APIへの疎通を確認
+func (s *cloudinaryService) Ping(ctx context.Context) error {
+	result, err := s.cld.Admin.Ping(ctx)
+	if err != nil {
+		return fmt.Errorf("Cloudinaryに接続できません: %v", err)
+	}
+	if result.Error.Message != "" {
+		return fmt.Errorf("Cloudinaryに接続できません: %s", result.Error.Message)
+	}
+	return nil
+}
+
+// circuitBreakerCloudinaryService Cloudinaryが停止している間はアップロード・削除を待たずに失敗させるCloudinaryServiceのラッパー
+type circuitBreakerCloudinaryService struct {
+	CloudinaryService
+	breaker *CircuitBreaker
+}
+
+// NewCircuitBreakerCloudinaryService CloudinaryServiceのアップロード・削除をサーキットブレーカー経由で呼び出す
+func NewCircuitBreakerCloudinaryService(cloudinaryService CloudinaryService, breaker *CircuitBreaker) CloudinaryService {
+	return &circuitBreakerCloudinaryService{
+		CloudinaryService: cloudinaryService,
+		breaker:           breaker,
+	}
+}
+
+// UploadImage 遮断していなければ画像をアップロード
+func (s *circuitBreakerCloudinaryService) UploadImage(ctx context.Context, file multipart.File, fileName string, compressionQuality int) (string, string, error) {
+	var publicID, url string
+	err := s.breaker.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		publicID, url, err = s.CloudinaryService.UploadImage(ctx, file, fileName, compressionQuality)
+		return err
+	})
+	return publicID, url, err
+}
+
+// UploadImageWithVariants 遮断していなければ画像と派生画像をアップロード
+func (s *circuitBreakerCloudinaryService) UploadImageWithVariants(ctx context.Context, data []byte, fileName string, transformations []string) (*CloudinaryUpload, error) {
+	var upload *CloudinaryUpload
+	err := s.breaker.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		upload, err = s.CloudinaryService.UploadImageWithVariants(ctx, data, fileName, transformations)
+		return err
+	})
+	return upload, err
+}
+
+// DeleteImage 遮断していなければ画像を削除
+func (s *circuitBreakerCloudinaryService) DeleteImage(ctx context.Context, publicID string) error {
+	return s.breaker.Execute(ctx, func(ctx context.Context) error {
+		return s.CloudinaryService.DeleteImage(ctx, publicID)
+	})
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// CollaboratorService 作品の共同編集者に関するサービスインターフェース
+type CollaboratorService interface {
+	List(ctx context.Context, workID uint, viewerID *uint) ([]models.WorkCollaborator, error)
+	Invite(ctx context.Context, workID, ownerID uint, nickname, permission string) (*models.WorkCollaborator, error)
+	Accept(ctx context.Context, workID, userID uint) (*models.WorkCollaborator, error)
+	UpdatePermission(ctx context.Context, workID, ownerID, userID uint, permission string) (*models.WorkCollaborator, error)
+	Remove(ctx context.Context, workID, actorID, userID uint) error
+	ListInvitations(ctx context.Context, userID uint) ([]models.WorkCollaborator, error)
+}
+
+// 1つの作品に招待できる共同編集者の最大数
+const maxWorkCollaborators = 20
+
+// collaboratorService CollaboratorServiceの実装
+type collaboratorService struct {
+	collaboratorRepo    repository.CollaboratorRepository
+	workRepo            repository.WorkRepository
+	userRepo            repository.UserRepository
+	notificationService NotificationService
+}
+
+// NewCollaboratorService CollaboratorServiceを作成
+func NewCollaboratorService(
+	collaboratorRepo repository.CollaboratorRepository,
+	workRepo repository.WorkRepository,
+	userRepo repository.UserRepository,
+	notificationService NotificationService,
+) CollaboratorService {
+	return &collaboratorService{
+		collaboratorRepo:    collaboratorRepo,
+		workRepo:            workRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+	}
+}
+
+// List 作品の共同編集者一覧を取得（作成者には招待中のユーザーも含める）
+func (s *collaboratorService) List(ctx context.Context, workID uint, viewerID *uint) ([]models.WorkCollaborator, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+
+	includePending := viewerID != nil && *viewerID == work.UserID
+	return s.collaboratorRepo.ListByWork(ctx, workID, includePending)
+}
+
+// Invite ニックネーム（@から始まる場合はハンドル）で指定したユーザーを共同編集者に招待（作成者のみ）
+func (s *collaboratorService) Invite(ctx context.Context, workID, ownerID uint, nickname, permission string) (*models.WorkCollaborator, error) {
+	work, err := s.findOwnedWork(ctx, workID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateCollaboratorPermission(permission); err != nil {
+		return nil, err
+	}
+
+	user, err := s.resolveUser(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == work.UserID {
+		return nil, errors.New("作品の作成者は共同編集者に追加できません")
+	}
+
+	collaborators, err := s.collaboratorRepo.ListByWork(ctx, workID, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(collaborators) >= maxWorkCollaborators {
+		return nil, fmt.Errorf("1つの作品に招待できる共同編集者は%d人までです", maxWorkCollaborators)
+	}
+
+	collaborator := &models.WorkCollaborator{
+		WorkID:      workID,
+		UserID:      user.ID,
+		Permission:  permission,
+		InvitedByID: ownerID,
+	}
+	if err := s.collaboratorRepo.Create(ctx, collaborator); err != nil {
+		if errors.Is(err, repository.ErrAlreadyCollaborator) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("共同編集者の招待に失敗しました: %v", err)
+	}
+
+	// 招待されたユーザーに通知
+	message := fmt.Sprintf("%sさんから作品「%s」の共同編集に招待されました", work.User.Nickname, work.Title)
+	link := fmt.Sprintf("/works/%d", workID)
+	if err := s.notificationService.Notify(ctx, []uint{user.ID}, models.NotificationTypeCollaborator, "共同編集に招待されました", message, link); err != nil {
+		log.Printf("共同編集の招待の通知に失敗しました (WorkID=%d, UserID=%d): %v", workID, user.ID, err)
+	}
+
+	return s.collaboratorRepo.Find(ctx, workID, user.ID)
+}
+
+// Accept 共同編集の招待を承認
+func (s *collaboratorService) Accept(ctx context.Context, workID, userID uint) (*models.WorkCollaborator, error) {
+	if _, err := s.workRepo.FindByID(ctx, workID); err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+
+	collaborator, err := s.collaboratorRepo.Find(ctx, workID, userID)
+	if err != nil {
+		return nil, errors.New("共同編集の招待が見つかりません")
+	}
+	if collaborator.AcceptedAt != nil {
+		return collaborator, nil
+	}
+
+	if err := s.collaboratorRepo.Accept(ctx, workID, userID, time.Now()); err != nil {
+		return nil, fmt.Errorf("共同編集の招待の承認に失敗しました: %v", err)
+	}
+	return s.collaboratorRepo.Find(ctx, workID, userID)
+}
+
+// UpdatePermission 共同編集者の権限を変更（作成者のみ）
+func (s *collaboratorService) UpdatePermission(ctx context.Context, workID, ownerID, userID uint, permission string) (*models.WorkCollaborator, error) {
+	if _, err := s.findOwnedWork(ctx, workID, ownerID); err != nil {
+		return nil, err
+	}
+	if err := validateCollaboratorPermission(permission); err != nil {
+		return nil, err
+	}
+	if _, err := s.collaboratorRepo.Find(ctx, workID, userID); err != nil {
+		return nil, errors.New("共同編集者が見つかりません")
+	}
+
+	if err := s.collaboratorRepo.UpdatePermission(ctx, workID, userID, permission); err != nil {
+		return nil, fmt.Errorf("共同編集者の権限の変更に失敗しました: %v", err)
+	}
+	return s.collaboratorRepo.Find(ctx, workID, userID)
+}
+
+// Remove 共同編集者を外す（作成者は誰でも、共同編集者は自分のみ外せる。招待の取り消し・辞退を含む）
+func (s *collaboratorService) Remove(ctx context.Context, workID, actorID, userID uint) error {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return errors.New("作品が見つかりません")
+	}
+	if actorID != work.UserID && actorID != userID {
+		return errors.New("この共同編集者を外す権限がありません")
+	}
+	if _, err := s.collaboratorRepo.Find(ctx, workID, userID); err != nil {
+		return errors.New("共同編集者が見つかりません")
+	}
+
+	return s.collaboratorRepo.Delete(ctx, workID, userID)
+}
+
+// ListInvitations 承認していない共同編集の招待一覧を取得
+func (s *collaboratorService) ListInvitations(ctx context.Context, userID uint) ([]models.WorkCollaborator, error) {
+	return s.collaboratorRepo.ListInvitations(ctx, userID)
+}
+
+// findOwnedWork 作品を取得し、作成者か確認
+func (s *collaboratorService) findOwnedWork(ctx context.Context, workID, ownerID uint) (*models.Work, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	if work.UserID != ownerID {
+		return nil, errors.New("この作品の共同編集者を管理する権限がありません")
+	}
+	return work, nil
+}
+
+// resolveUser ニックネームでユーザーを検索（@から始まる場合と、ニックネームが一致しない場合はハンドルで検索）
+// 同じニックネームのユーザーが複数いる場合はハンドルでの指定を求める
+func (s *collaboratorService) resolveUser(ctx context.Context, nickname string) (*models.User, error) {
+	nickname = strings.TrimSpace(nickname)
+	if nickname == "" {
+		return nil, errors.New("招待するユーザーのニックネームを指定してください")
+	}
+
+	if !strings.HasPrefix(nickname, "@") {
+		users, err := s.userRepo.ListByNickname(ctx, nickname, 2)
+		if err != nil {
+			return nil, err
+		}
+		if len(users) > 1 {
+			return nil, fmt.Errorf("ニックネームが「%s」のユーザーが複数います。@ハンドルで指定してください", nickname)
+		}
+		if len(users) == 1 {
+			return &users[0], nil
+		}
+	}
+
+	user, err := s.userRepo.FindByHandle(ctx, strings.ToLower(strings.TrimPrefix(nickname, "@")))
+	if err != nil || user.Pending {
+		return nil, errors.New("招待するユーザーが見つかりません")
+	}
+	return user, nil
+}
+
+// validateCollaboratorPermission 共同編集者の権限が有効か確認
+func validateCollaboratorPermission(permission string) error {
+	switch permission {
+	case models.CollaboratorPermissionEdit, models.CollaboratorPermissionManage:
+		return nil
+	default:
+		return fmt.Errorf("権限には%sまたは%sを指定してください", models.CollaboratorPermissionEdit, models.CollaboratorPermissionManage)
+	}
+}
+
+// hasWorkPermission 作品の作成者か、承認済みでpermission以上の権限を持つ共同編集者か確認
+func hasWorkPermission(ctx context.Context, collaboratorRepo repository.CollaboratorRepository, work *models.Work, userID uint, permission string) bool {
+	if work.UserID == userID {
+		return true
+	}
+	collaborator, err := collaboratorRepo.Find(ctx, work.ID, userID)
+	if err != nil || collaborator.AcceptedAt == nil {
+		return false
+	}
+	// manage は edit を含む
+	return collaborator.Permission == models.CollaboratorPermissionManage || collaborator.Permission == permission
+}
+
+// coAuthorsByWork 作品ごとの承認済みの共同編集者を取得（取得に失敗した場合は空）
+func coAuthorsByWork(ctx context.Context, collaboratorRepo repository.CollaboratorRepository, workIDs []uint) map[uint][]models.User {
+	coAuthors := make(map[uint][]models.User)
+	collaborators, err := collaboratorRepo.ListAcceptedByWorks(ctx, workIDs)
+	if err != nil {
+		log.Printf("共同編集者の取得に失敗しました: %v", err)
+		return coAuthors
+	}
+	for _, collaborator := range collaborators {
+		coAuthors[collaborator.WorkID] = append(coAuthors[collaborator.WorkID], collaborator.User)
+	}
+	return coAuthors
+}
+
+// attachCoAuthors 作品一覧に承認済みの共同編集者を設定
+func attachCoAuthors(ctx context.Context, collaboratorRepo repository.CollaboratorRepository, works []models.Work) {
+	if len(works) == 0 {
+		return
+	}
+	workIDs := make([]uint, len(works))
+	for i := range works {
+		workIDs[i] = works[i].ID
+	}
+	coAuthors := coAuthorsByWork(ctx, collaboratorRepo, workIDs)
+	for i := range works {
+		works[i].CoAuthors = coAuthors[works[i].ID]
+	}
+}
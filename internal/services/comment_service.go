@@ -1,78 +1,164 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log"
 	"strings"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
 )
 
 // CommentService コメントに関するサービスインターフェース
 type CommentService interface {
-	Create(content string, workID uint, userID uint) (*models.Comment, error)
-	GetByID(id uint) (*models.Comment, error)
-	Update(id, userID uint, content string) (*models.Comment, error)
-	Delete(id, userID uint) error
-	ListByWork(workID uint, page, limit int) ([]models.Comment, int64, int, error)
+	Create(ctx context.Context, content string, workID uint, userID uint, parentID *uint) (*models.Comment, error)
+	GetByID(ctx context.Context, id uint) (*models.Comment, error)
+	Update(ctx context.Context, id, userID uint, content string) (*models.Comment, error)
+	Delete(ctx context.Context, id, userID uint) error
+	ListByWork(ctx context.Context, workID uint, page, limit int, sort string, afterID uint) ([]models.Comment, int64, int, error)
+	SetPinned(ctx context.Context, id, userID uint, pinned bool) (*models.Comment, error)
+	SetResolved(ctx context.Context, id, userID uint, resolved bool) (*models.Comment, error)
+	Export(ctx context.Context, workID, userID uint, fn func(comments []models.Comment) error) error
+	GetSubscription(ctx context.Context, workID, userID uint) (*models.CommentSubscription, error)
+	SetSubscription(ctx context.Context, workID, userID uint, subscribed bool) (*models.CommentSubscription, error)
 }
 
+// エクスポート時に一度に読み込むコメント数
+const commentExportBatchSize = 500
+
 // commentService CommentServiceの実装
 type commentService struct {
-	commentRepo repository.CommentRepository
-	workRepo    repository.WorkRepository
+	commentRepo         repository.CommentRepository
+	workRepo            repository.WorkRepository
+	projectRepo         repository.ProjectRepository
+	notificationService NotificationService
+	linkPreviewService  LinkPreviewService
 }
 
 // NewCommentService CommentServiceを作成
-func NewCommentService(commentRepo repository.CommentRepository, workRepo repository.WorkRepository) CommentService {
+func NewCommentService(commentRepo repository.CommentRepository, workRepo repository.WorkRepository, projectRepo repository.ProjectRepository, notificationService NotificationService, linkPreviewService LinkPreviewService) CommentService {
 	return &commentService{
-		commentRepo: commentRepo,
-		workRepo:    workRepo,
+		commentRepo:         commentRepo,
+		workRepo:            workRepo,
+		projectRepo:         projectRepo,
+		notificationService: notificationService,
+		linkPreviewService:  linkPreviewService,
 	}
 }
 
 // Create 新しいコメントを作成
-func (s *commentService) Create(content string, workID uint, userID uint) (*models.Comment, error) {
+func (s *commentService) Create(ctx context.Context, content string, workID uint, userID uint, parentID *uint) (*models.Comment, error) {
 	// コンテンツのバリデーション
 	if strings.TrimSpace(content) == "" {
 		return nil, errors.New("コメント内容は必須です")
 	}
 
 	// 作品が存在するか確認
-	_, err := s.workRepo.FindByID(workID)
+	work, err := s.workRepo.FindByID(ctx, workID)
 	if err != nil {
 		return nil, errors.New("作品が見つかりません")
 	}
 
+	// 返信先のコメントが同じ作品のものか確認
+	if parentID != nil {
+		parent, err := s.commentRepo.FindByID(ctx, *parentID)
+		if err != nil || parent.WorkID != workID {
+			return nil, errors.New("返信先のコメントが見つかりません")
+		}
+	}
+
 	// 新しいコメントを作成
 	comment := &models.Comment{
-		Content: content,
-		WorkID:  workID,
-		UserID:  userID,
+		Content:  content,
+		WorkID:   workID,
+		UserID:   userID,
+		ParentID: parentID,
 	}
 
 	// データベースに保存
-	if err := s.commentRepo.Create(comment); err != nil {
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
 		return nil, err
 	}
 
-	return s.GetByID(comment.ID)
+	// コメントしたユーザーは以降のコメントの通知を受け取る（通知を受け取らない設定にしている場合は変更しない）
+	subscription := &models.CommentSubscription{UserID: userID, WorkID: workID, Subscribed: true}
+	if err := s.commentRepo.CreateSubscription(ctx, subscription); err != nil {
+		log.Printf("コメントの通知の設定に失敗しました (WorkID=%d, UserID=%d): %v", workID, userID, err)
+	}
+
+	// 本文のURLのリンクプレビューを先に取得しておく
+	s.linkPreviewService.Refresh(content)
+
+	// 作品が提出されているプロジェクトでは、メンバーの活動として記録する
+	if err := s.projectRepo.TouchMembersByWork(ctx, workID, userID, time.Now()); err != nil {
+		log.Printf("メンバーの活動日時の更新に失敗しました (WorkID=%d, UserID=%d): %v", workID, userID, err)
+	}
+
+	created, err := s.GetByID(ctx, comment.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.notifySubscribers(ctx, work, created)
+
+	return created, nil
+}
+
+// notifySubscribers 作品のコメントの通知を受け取るユーザーに通知（コメントしたユーザーを除く）
+func (s *commentService) notifySubscribers(ctx context.Context, work *models.Work, comment *models.Comment) {
+	subscriptions, err := s.commentRepo.ListSubscriptions(ctx, work.ID)
+	if err != nil {
+		log.Printf("コメントの通知先の取得に失敗しました (WorkID=%d): %v", work.ID, err)
+		return
+	}
+
+	// 作品の作者は通知を受け取らない設定にしていなければ通知する
+	subscribed := map[uint]bool{work.UserID: true}
+	for _, subscription := range subscriptions {
+		subscribed[subscription.UserID] = subscription.Subscribed
+	}
+	userIDs := []uint{}
+	for userID, ok := range subscribed {
+		if ok && userID != comment.UserID {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("%sさんが作品「%s」にコメントしました", comment.User.Nickname, work.Title)
+	link := fmt.Sprintf("/works/%d", work.ID)
+	if err := s.notificationService.Notify(ctx, userIDs, models.NotificationTypeComment, "新しいコメントがあります", message, link); err != nil {
+		log.Printf("コメントの通知に失敗しました (CommentID=%d): %v", comment.ID, err)
+	}
 }
 
 // GetByID IDでコメントを取得
-func (s *commentService) GetByID(id uint) (*models.Comment, error) {
-	return s.commentRepo.FindByID(id)
+func (s *commentService) GetByID(ctx context.Context, id uint) (*models.Comment, error) {
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := []models.Comment{*comment}
+	s.linkPreviewService.Attach(ctx, comments)
+	return &comments[0], nil
 }
 
 // Update コメントを更新
-func (s *commentService) Update(id, userID uint, content string) (*models.Comment, error) {
+func (s *commentService) Update(ctx context.Context, id, userID uint, content string) (*models.Comment, error) {
 	// コンテンツのバリデーション
 	if strings.TrimSpace(content) == "" {
 		return nil, errors.New("コメント内容は必須です")
 	}
 
 	// コメントを取得
-	comment, err := s.commentRepo.FindByID(id)
+	comment, err := s.commentRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("コメントが見つかりません")
 	}
@@ -86,17 +172,18 @@ func (s *commentService) Update(id, userID uint, content string) (*models.Commen
 	comment.Content = content
 
 	// データベースを更新
-	if err := s.commentRepo.Update(comment); err != nil {
+	if err := s.commentRepo.Update(ctx, comment); err != nil {
 		return nil, err
 	}
+	s.linkPreviewService.Refresh(content)
 
-	return s.GetByID(id)
+	return s.GetByID(ctx, id)
 }
 
 // Delete コメントを削除
-func (s *commentService) Delete(id, userID uint) error {
+func (s *commentService) Delete(ctx context.Context, id, userID uint) error {
 	// コメントを取得
-	comment, err := s.commentRepo.FindByID(id)
+	comment, err := s.commentRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("コメントが見つかりません")
 	}
@@ -107,23 +194,40 @@ func (s *commentService) Delete(id, userID uint) error {
 	}
 
 	// データベースから削除
-	return s.commentRepo.Delete(id)
+	return s.commentRepo.Delete(ctx, id)
 }
 
 // ListByWork 作品のコメント一覧を取得
-func (s *commentService) ListByWork(workID uint, page, limit int) ([]models.Comment, int64, int, error) {
-	// 作品が存在するか確認
-	_, err := s.workRepo.FindByID(workID)
-	if err != nil {
+func (s *commentService) ListByWork(ctx context.Context, workID uint, page, limit int, sort string, afterID uint) ([]models.Comment, int64, int, error) {
+	// 作品が存在するか確認（通報により非表示の作品を含めない）
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil || work.HiddenAt != nil {
 		return nil, 0, 0, errors.New("作品が見つかりません")
 	}
 
 	// コメント一覧を取得
-	comments, total, err := s.commentRepo.ListByWork(workID, page, limit)
+	comments, total, err := s.commentRepo.ListByWork(ctx, workID, page, limit, sort, afterID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, 0, errors.New("基準となるコメントが見つかりません")
+		}
 		return nil, 0, 0, err
 	}
 
+	// 最初のページではピン留めされたコメントを先頭に表示
+	if page == 1 && afterID == 0 {
+		pinned, err := s.commentRepo.FindPinnedByWork(ctx, workID)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if pinned != nil {
+			comments = append([]models.Comment{*pinned}, comments...)
+		}
+	}
+
+	// 取得済みのリンクプレビューを設定
+	s.linkPreviewService.Attach(ctx, comments)
+
 	// 総ページ数を計算
 	pages := int(total) / limit
 	if int(total)%limit > 0 {
@@ -132,3 +236,101 @@ func (s *commentService) ListByWork(workID uint, page, limit int) ([]models.Comm
 
 	return comments, total, pages, nil
 }
+
+// SetPinned コメントのピン留めを設定（作品の作者のみ）
+func (s *commentService) SetPinned(ctx context.Context, id, userID uint, pinned bool) (*models.Comment, error) {
+	// コメントを取得
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("コメントが見つかりません")
+	}
+
+	// 作品の作者か確認
+	work, err := s.workRepo.FindByID(ctx, comment.WorkID)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	if work.UserID != userID {
+		return nil, errors.New("このコメントをピン留めする権限がありません")
+	}
+
+	if err := s.commentRepo.SetPinned(ctx, comment, pinned); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// SetResolved コメントを対応済み・未対応に設定（作品の作者のみ）
+func (s *commentService) SetResolved(ctx context.Context, id, userID uint, resolved bool) (*models.Comment, error) {
+	// コメントを取得
+	comment, err := s.commentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("コメントが見つかりません")
+	}
+
+	// 作品の作者か確認
+	work, err := s.workRepo.FindByID(ctx, comment.WorkID)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	if work.UserID != userID {
+		return nil, errors.New("このコメントを対応済みにする権限がありません")
+	}
+
+	var resolvedAt *time.Time
+	if resolved {
+		now := time.Now()
+		resolvedAt = &now
+	}
+	if err := s.commentRepo.SetResolved(ctx, comment, resolvedAt); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// Export 作品の全コメントを古い順に少しずつ読み込んでfnに渡す（作品の作者のみ）
+func (s *commentService) Export(ctx context.Context, workID, userID uint, fn func(comments []models.Comment) error) error {
+	// 作品の作者か確認
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return errors.New("作品が見つかりません")
+	}
+	if work.UserID != userID {
+		return errors.New("この作品のコメントをエクスポートする権限がありません")
+	}
+
+	return s.commentRepo.EachByWork(ctx, workID, commentExportBatchSize, fn)
+}
+
+// GetSubscription 作品のコメントの通知を受け取るかどうかを取得（設定していない場合は作品の作者のみ受け取る）
+func (s *commentService) GetSubscription(ctx context.Context, workID, userID uint) (*models.CommentSubscription, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil || work.HiddenAt != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+
+	subscription, err := s.commentRepo.FindSubscription(ctx, workID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.CommentSubscription{UserID: userID, WorkID: workID, Subscribed: work.UserID == userID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("コメントの通知の設定の取得に失敗しました: %v", err)
+	}
+	return subscription, nil
+}
+
+// SetSubscription 作品のコメントの通知を受け取るかどうかを設定
+func (s *commentService) SetSubscription(ctx context.Context, workID, userID uint, subscribed bool) (*models.CommentSubscription, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil || work.HiddenAt != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+
+	subscription := &models.CommentSubscription{UserID: userID, WorkID: workID, Subscribed: subscribed}
+	if err := s.commentRepo.SetSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("コメントの通知の設定に失敗しました: %v", err)
+	}
+	return subscription, nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+const (
+	// 1回の実行で処理する再試行ジョブの最大数
+	conversionJobBatchSize = 20
+	// 処理中のジョブを他のサーバーが取得しないようにする時間（変換のタイムアウトと再試行より長くする）
+	conversionJobLease = 5 * time.Minute
+)
+
+// ConversionJobService 作品の作成・更新時に失敗したPDE変換を再試行するサービスインターフェース
+type ConversionJobService interface {
+	Enqueue(ctx context.Context, workID uint) error
+	GetByWork(ctx context.Context, workID, userID uint) (*models.ConversionJob, error)
+	ProcessDue(ctx context.Context) (int, error)
+}
+
+// conversionJobService ConversionJobServiceの実装
+type conversionJobService struct {
+	jobRepo             repository.ConversionJobRepository
+	workRepo            repository.WorkRepository
+	lambdaService       LambdaService
+	conversionQueue     ConversionQueue
+	jsValidator         JSValidationService
+	notificationService NotificationService
+	config              *config.Config
+}
+
+// NewConversionJobService ConversionJobServiceを作成
+func NewConversionJobService(
+	jobRepo repository.ConversionJobRepository,
+	workRepo repository.WorkRepository,
+	lambdaService LambdaService,
+	conversionQueue ConversionQueue,
+	jsValidator JSValidationService,
+	notificationService NotificationService,
+	cfg *config.Config,
+) ConversionJobService {
+	return &conversionJobService{
+		jobRepo:             jobRepo,
+		workRepo:            workRepo,
+		lambdaService:       lambdaService,
+		conversionQueue:     conversionQueue,
+		jsValidator:         jsValidator,
+		notificationService: notificationService,
+		config:              cfg,
+	}
+}
+
+// Enqueue 作品の変換を再試行するジョブを登録（次回のスケジューラの実行で変換する）
+// 既にジョブがある場合は試行回数を戻してやり直す。再試行回数が0以下の場合は登録しない
+func (s *conversionJobService) Enqueue(ctx context.Context, workID uint) error {
+	maxAttempts := s.config.Conversion.RetryMaxAttempts
+	if maxAttempts <= 0 {
+		return nil
+	}
+	return s.jobRepo.Enqueue(ctx, workID, maxAttempts, time.Now())
+}
+
+// GetByWork 作品の変換の再試行状況を取得（作者のみ、ジョブがない場合はnil）
+func (s *conversionJobService) GetByWork(ctx context.Context, workID, userID uint) (*models.ConversionJob, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	if work.UserID != userID {
+		return nil, errors.New("この作品の変換状況を閲覧する権限がありません")
+	}
+
+	job, err := s.jobRepo.FindByWorkID(ctx, workID)
+	if err != nil {
+		return nil, nil
+	}
+	return job, nil
+}
+
+// ProcessDue 実行時刻を過ぎたジョブを順に処理する（スケジューラから定期実行）
+// 処理したジョブの数を返す
+func (s *conversionJobService) ProcessDue(ctx context.Context) (int, error) {
+	jobs, err := s.jobRepo.ListDue(ctx, time.Now(), conversionJobBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for i := range jobs {
+		// 実行間隔を過ぎた場合は残りを次回に回す
+		if ctx.Err() != nil {
+			break
+		}
+
+		job := &jobs[i]
+		claimed, err := s.jobRepo.Claim(ctx, job, time.Now().Add(conversionJobLease))
+		if err != nil {
+			return processed, err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.process(ctx, job); err != nil {
+			// Lambdaへの呼び出しを遮断している間は残りも次回に回す
+			if errors.Is(err, ErrCircuitOpen) {
+				break
+			}
+			log.Printf("変換の再試行ジョブの更新に失敗しました (WorkID=%d): %v", job.WorkID, err)
+			continue
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// process ジョブを1回実行し、結果に応じて完了・次回の再試行・失敗（dead）にする
+// Lambdaへの呼び出しを遮断している場合はErrCircuitOpenを返す
+func (s *conversionJobService) process(ctx context.Context, job *models.ConversionJob) error {
+	// 作品が削除された場合はジョブも削除
+	work, err := s.workRepo.FindByID(ctx, job.WorkID)
+	if err != nil {
+		return s.jobRepo.Delete(ctx, job.ID)
+	}
+
+	output, err := s.conversionQueue.Convert(ctx, ConversionPriorityBatch, work.PDEContent)
+	if err == nil {
+		s.jsValidator.Apply(work, output, s.lambdaService.ConverterVersion())
+		err = s.workRepo.UpdateConversion(ctx, work)
+	}
+
+	if err == nil {
+		job.Attempts++
+		job.Status = models.ConversionJobStatusSucceeded
+		job.LastError = ""
+		return s.jobRepo.Update(ctx, job)
+	}
+
+	// スケジューラの実行間隔を過ぎて中断した場合は回数に数えず、次回すぐに再試行する
+	if ctx.Err() != nil {
+		job.NextRunAt = time.Now()
+		return s.jobRepo.Update(context.Background(), job)
+	}
+
+	// Lambdaへの呼び出しを遮断している場合も回数に数えず、遮断が解けてから再試行する
+	if errors.Is(err, ErrCircuitOpen) {
+		job.NextRunAt = time.Now().Add(s.config.Circuit.OpenTimeout)
+		if err := s.jobRepo.Update(ctx, job); err != nil {
+			return err
+		}
+		return ErrCircuitOpen
+	}
+
+	job.Attempts++
+	job.LastError = err.Error()
+	log.Printf("PDE変換の再試行に失敗しました (WorkID=%d, %d/%d回目): %v", job.WorkID, job.Attempts, job.MaxAttempts, err)
+
+	if job.Attempts < job.MaxAttempts {
+		job.NextRunAt = time.Now().Add(conversionRetryDelay(s.config.Conversion.RetryBaseDelay, s.config.Conversion.RetryMaxDelay, job.Attempts))
+		return s.jobRepo.Update(ctx, job)
+	}
+
+	job.Status = models.ConversionJobStatusDead
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		return err
+	}
+	s.notifyDead(ctx, work, job)
+	return nil
+}
+
+// notifyDead 再試行の上限に達したことを作者に通知（通知の失敗はログ出力のみ）
+func (s *conversionJobService) notifyDead(ctx context.Context, work *models.Work, job *models.ConversionJob) {
+	message := fmt.Sprintf("「%s」のJavaScriptへの変換を%d回再試行しましたが失敗しました。コードを確認してから作品を更新してください。", work.Title, job.Attempts)
+	link := fmt.Sprintf("/works/%d", work.ID)
+	if err := s.notificationService.Notify(ctx, []uint{work.UserID}, models.NotificationTypeConversion, "作品の変換に失敗しました", message, link); err != nil {
+		log.Printf("通知の作成に失敗しました (WorkID=%d): %v", work.ID, err)
+	}
+}
+
+// conversionRetryDelay attempts回目の失敗から次の再試行までの待ち時間（base から倍々にして max を上限とする）
+func conversionRetryDelay(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if max > 0 && delay > max {
+		delay = max
+	}
+	return delay
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+)
+
+// ConversionPriority PDE変換の優先度（値が小さいほど先に処理する）
+type ConversionPriority int
+
+// 変換の優先度
+const (
+	ConversionPriorityPreview ConversionPriority = iota // 編集中のプレビュー
+	ConversionPriorityWork                              // 作品の作成・更新
+	ConversionPriorityBatch                             // 再変換キャンペーンや失敗した変換の再試行
+)
+
+// conversionLaneNames 優先度ごとのレーン名（メトリクスに使用）
+var conversionLaneNames = [...]string{"preview", "work", "batch"}
+
+// ConversionLaneStats 優先度ごとの変換のメトリクス（起動してからの累計）
+type ConversionLaneStats struct {
+	Lane      string `json:"lane"`
+	Queued    int    `json:"queued"`  // 待機中の件数
+	Running   int    `json:"running"` // 実行中の件数
+	Processed int64  `json:"processed"`
+	Cached    int64  `json:"cached"` // 同じPDEの変換結果を再利用した件数
+	Failed    int64  `json:"failed"`
+	Cancelled int64  `json:"cancelled"` // 待機中に呼び出し元がキャンセルした件数
+	AvgWaitMs int64  `json:"avg_wait_ms"`
+	MaxWaitMs int64  `json:"max_wait_ms"`
+	AvgRunMs  int64  `json:"avg_run_ms"`
+}
+
+// ConversionQueue PDE変換を優先度順にワーカーへ割り当てるキュー
+type ConversionQueue interface {
+	Convert(ctx context.Context, priority ConversionPriority, pdeContent string) (ConversionOutput, error)
+	Stats() []ConversionLaneStats
+}
+
+// ConversionOutput 変換結果と変換の来歴
+type ConversionOutput struct {
+	JSContent string
+	Duration  time.Duration // Lambdaの呼び出しにかかった時間（待機時間を含まない）
+	Cached    bool          // 同じバージョンで変換した同じPDEの結果を再利用した
+}
+
+// conversionRequest 待機中の変換リクエスト
+type conversionRequest struct {
+	ctx        context.Context
+	priority   ConversionPriority
+	pdeContent string
+	cacheKey   string
+	enqueuedAt time.Time
+	done       chan conversionResult
+}
+
+// conversionResult 変換結果
+type conversionResult struct {
+	output ConversionOutput
+	err    error
+}
+
+// conversionCacheEntry 変換結果のキャッシュ
+type conversionCacheEntry struct {
+	key       string
+	jsContent string
+}
+
+// conversionLane 優先度ごとの待ち行列とメトリクス
+type conversionLane struct {
+	queue     []*conversionRequest
+	running   int
+	processed int64
+	cached    int64
+	failed    int64
+	cancelled int64
+	waitTotal time.Duration
+	waitMax   time.Duration
+	runTotal  time.Duration
+}
+
+// conversionQueue ConversionQueueの実装
+type conversionQueue struct {
+	lambdaService LambdaService
+	batchLimit    int // 一括処理のレーンが同時に使えるワーカー数
+	cacheSize     int // キャッシュする変換結果の件数（0以下でキャッシュしない）
+
+	mu         sync.Mutex
+	cond       *sync.Cond
+	lanes      [len(conversionLaneNames)]conversionLane
+	cache      map[string]*list.Element
+	cacheOrder *list.List // 最近使った順
+}
+
+// NewConversionQueue ConversionQueueを作成し、ワーカーを起動する
+// 一括処理で全てのワーカーが埋まらないよう、ワーカーが2つ以上の場合は1つをプレビューと作品の保存用に空けておく
+func NewConversionQueue(lambdaService LambdaService, cfg *config.Config) ConversionQueue {
+	workers := cfg.Conversion.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	batchLimit := workers
+	if workers > 1 {
+		batchLimit = workers - 1
+	}
+
+	q := &conversionQueue{
+		lambdaService: lambdaService,
+		batchLimit:    batchLimit,
+		cacheSize:     cfg.Conversion.CacheSize,
+		cache:         map[string]*list.Element{},
+		cacheOrder:    list.New(),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+	return q
+}
+
+// Convert 変換を待ち行列に追加し、結果を待つ
+// 同じバージョンで変換した同じPDEの結果がキャッシュにあれば、変換せずに返す
+// 待機中にctxがキャンセルされた場合は変換せずに終了する
+func (q *conversionQueue) Convert(ctx context.Context, priority ConversionPriority, pdeContent string) (ConversionOutput, error) {
+	if priority < 0 || int(priority) >= len(q.lanes) {
+		priority = ConversionPriorityBatch
+	}
+
+	req := &conversionRequest{
+		ctx:        ctx,
+		priority:   priority,
+		pdeContent: pdeContent,
+		cacheKey:   conversionCacheKey(q.lambdaService.ConverterVersion(), pdeContent),
+		enqueuedAt: time.Now(),
+		done:       make(chan conversionResult, 1),
+	}
+
+	q.mu.Lock()
+	if elem, ok := q.cache[req.cacheKey]; ok {
+		q.cacheOrder.MoveToFront(elem)
+		q.lanes[priority].cached++
+		jsContent := elem.Value.(*conversionCacheEntry).jsContent
+		q.mu.Unlock()
+		return ConversionOutput{JSContent: jsContent, Cached: true}, nil
+	}
+	q.lanes[priority].queue = append(q.lanes[priority].queue, req)
+	q.mu.Unlock()
+	q.cond.Signal()
+
+	select {
+	case result := <-req.done:
+		return result.output, result.err
+	case <-ctx.Done():
+		return ConversionOutput{}, ctx.Err()
+	}
+}
+
+// Stats 優先度ごとのメトリクスを取得
+func (q *conversionQueue) Stats() []ConversionLaneStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := make([]ConversionLaneStats, 0, len(q.lanes))
+	for i := range q.lanes {
+		lane := &q.lanes[i]
+		stat := ConversionLaneStats{
+			Lane:      conversionLaneNames[i],
+			Queued:    len(lane.queue),
+			Running:   lane.running,
+			Processed: lane.processed,
+			Cached:    lane.cached,
+			Failed:    lane.failed,
+			Cancelled: lane.cancelled,
+			MaxWaitMs: lane.waitMax.Milliseconds(),
+		}
+		if started := lane.processed + lane.failed; started > 0 {
+			stat.AvgWaitMs = lane.waitTotal.Milliseconds() / started
+			stat.AvgRunMs = lane.runTotal.Milliseconds() / started
+		}
+		stats = append(stats, stat)
+	}
+	return stats
+}
+
+// work 優先度の高いリクエストから順に変換し続けるワーカー
+func (q *conversionQueue) work() {
+	for {
+		req := q.next()
+		started := time.Now()
+		jsContent, err := q.lambdaService.ConvertPDEToJS(req.ctx, req.pdeContent)
+		elapsed := time.Since(started)
+
+		q.mu.Lock()
+		lane := &q.lanes[req.priority]
+		lane.running--
+		lane.runTotal += elapsed
+		if err != nil {
+			lane.failed++
+		} else {
+			lane.processed++
+			q.store(req.cacheKey, jsContent)
+		}
+		q.mu.Unlock()
+		// 一括処理の同時実行数の上限で待っているワーカーを起こす
+		q.cond.Broadcast()
+
+		req.done <- conversionResult{output: ConversionOutput{JSContent: jsContent, Duration: elapsed}, err: err}
+	}
+}
+
+// store 変換結果をキャッシュに追加し、件数の上限を超えた分を古い順に削除する（q.muを保持して呼び出す）
+func (q *conversionQueue) store(key, jsContent string) {
+	if q.cacheSize <= 0 {
+		return
+	}
+	if elem, ok := q.cache[key]; ok {
+		elem.Value.(*conversionCacheEntry).jsContent = jsContent
+		q.cacheOrder.MoveToFront(elem)
+		return
+	}
+	q.cache[key] = q.cacheOrder.PushFront(&conversionCacheEntry{key: key, jsContent: jsContent})
+	for q.cacheOrder.Len() > q.cacheSize {
+		oldest := q.cacheOrder.Back()
+		q.cacheOrder.Remove(oldest)
+		delete(q.cache, oldest.Value.(*conversionCacheEntry).key)
+	}
+}
+
+// conversionCacheKey 変換結果のキャッシュのキー（変換に使用するバージョンとPDEのハッシュ）
+func conversionCacheKey(version, pdeContent string) string {
+	sum := sha256.Sum256([]byte(version + "\x00" + pdeContent))
+	return hex.EncodeToString(sum[:])
+}
+
+// next 次に処理するリクエストを取り出す（なければ待つ）
+// キャンセル済みのリクエストは変換せずに読み飛ばす
+func (q *conversionQueue) next() *conversionRequest {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		for i := range q.lanes {
+			lane := &q.lanes[i]
+			if len(lane.queue) == 0 {
+				continue
+			}
+			if ConversionPriority(i) == ConversionPriorityBatch && lane.running >= q.batchLimit {
+				continue
+			}
+
+			req := lane.queue[0]
+			lane.queue[0] = nil
+			lane.queue = lane.queue[1:]
+			if req.ctx.Err() != nil {
+				lane.cancelled++
+				req.done <- conversionResult{err: req.ctx.Err()}
+				continue
+			}
+
+			wait := time.Since(req.enqueuedAt)
+			lane.waitTotal += wait
+			if wait > lane.waitMax {
+				lane.waitMax = wait
+			}
+			lane.running++
+			return req
+		}
+		q.cond.Wait()
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// 変換回数を数える期間
+const conversionQuotaWindow = time.Hour
+
+// ConversionQuota ユーザーのPDE変換回数の状況
+type ConversionQuota struct {
+	Limit     int       `json:"limit"` // 1時間あたりの上限
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"reset_at"`
+
+	MonthlyLimit     int       `json:"monthly_limit"` // 料金プランの1か月あたりの上限（0は無制限）
+	MonthlyRemaining int       `json:"monthly_remaining"`
+	MonthlyResetAt   time.Time `json:"monthly_reset_at"`
+}
+
+// Unlimited 1時間あたりの変換回数が無制限かどうか
+func (q *ConversionQuota) Unlimited() bool {
+	return q.Limit <= 0
+}
+
+// MonthlyUnlimited 1か月あたりの変換回数が無制限かどうか
+func (q *ConversionQuota) MonthlyUnlimited() bool {
+	return q.MonthlyLimit <= 0
+}
+
+// Exhausted 1時間あたり・1か月あたりのいずれかの上限に達しているか
+func (q *ConversionQuota) Exhausted() bool {
+	return (!q.Unlimited() && q.Remaining <= 0) || (!q.MonthlyUnlimited() && q.MonthlyRemaining <= 0)
+}
+
+// RetryAt 再び変換できるようになる日時
+func (q *ConversionQuota) RetryAt() time.Time {
+	if !q.MonthlyUnlimited() && q.MonthlyRemaining <= 0 {
+		return q.MonthlyResetAt
+	}
+	return q.ResetAt
+}
+
+// ConversionQuotaService PDE変換回数の制限に関するサービスインターフェース
+type ConversionQuotaService interface {
+	Status(ctx context.Context, userID uint) (*ConversionQuota, error)
+	Consume(ctx context.Context, userID uint) (*ConversionQuota, error)
+}
+
+// conversionQuotaService ConversionQuotaServiceの実装
+type conversionQuotaService struct {
+	conversionRepo repository.ConversionRepository
+	limitService   LimitService
+	config         *config.Config
+}
+
+// NewConversionQuotaService ConversionQuotaServiceを作成
+func NewConversionQuotaService(conversionRepo repository.ConversionRepository, limitService LimitService, cfg *config.Config) ConversionQuotaService {
+	return &conversionQuotaService{
+		conversionRepo: conversionRepo,
+		limitService:   limitService,
+		config:         cfg,
+	}
+}
+
+// Status 現在の変換回数の状況を取得
+func (s *conversionQuotaService) Status(ctx context.Context, userID uint) (*ConversionQuota, error) {
+	limit := s.config.Conversion.QuotaPerHour
+	now := time.Now()
+	quota := &ConversionQuota{Limit: limit, ResetAt: now.Add(conversionQuotaWindow)}
+
+	// 料金プランの1か月あたりの上限
+	_, limits, err := s.limitService.Plan(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	month := monthStart(now)
+	quota.MonthlyLimit = limits.ConversionsPerMonth
+	quota.MonthlyResetAt = month.AddDate(0, 1, 0)
+	if !quota.MonthlyUnlimited() {
+		used, err := s.conversionRepo.CountSince(ctx, userID, month)
+		if err != nil {
+			return nil, fmt.Errorf("変換回数の取得に失敗しました: %v", err)
+		}
+		quota.MonthlyRemaining = quota.MonthlyLimit - int(used)
+		if quota.MonthlyRemaining < 0 {
+			quota.MonthlyRemaining = 0
+		}
+	}
+
+	if quota.Unlimited() {
+		return quota, nil
+	}
+
+	since := now.Add(-conversionQuotaWindow)
+	used, err := s.conversionRepo.CountSince(ctx, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("変換回数の取得に失敗しました: %v", err)
+	}
+
+	quota.Remaining = limit - int(used)
+	if quota.Remaining < 0 {
+		quota.Remaining = 0
+	}
+
+	// 期間内で最も古い変換が期間外になった時点で1回分回復する
+	oldest, err := s.conversionRepo.OldestSince(ctx, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("変換回数の取得に失敗しました: %v", err)
+	}
+	if oldest != nil {
+		quota.ResetAt = oldest.Add(conversionQuotaWindow)
+	}
+
+	return quota, nil
+}
+
+// Consume 変換回数を1回消費する（上限に達している場合はエラー）
+func (s *conversionQuotaService) Consume(ctx context.Context, userID uint) (*ConversionQuota, error) {
+	quota, err := s.Status(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if quota.Unlimited() && quota.MonthlyUnlimited() {
+		return quota, nil
+	}
+
+	if !quota.MonthlyUnlimited() && quota.MonthlyRemaining <= 0 {
+		return quota, fmt.Errorf("今月のPDE変換回数の上限に達しました（料金プランの上限は1か月あたり%d回まで）。%s以降に再度お試しください",
+			quota.MonthlyLimit, quota.MonthlyResetAt.Format("2006-01-02 15:04"))
+	}
+	if !quota.Unlimited() && quota.Remaining <= 0 {
+		return quota, fmt.Errorf("PDE変換回数の上限に達しました（1時間あたり%d回まで）。%s以降に再度お試しください",
+			quota.Limit, quota.ResetAt.Format("15:04:05"))
+	}
+
+	if err := s.conversionRepo.Create(ctx, userID); err != nil {
+		return nil, fmt.Errorf("変換履歴の記録に失敗しました: %v", err)
+	}
+	if !quota.Unlimited() {
+		quota.Remaining--
+	}
+	if !quota.MonthlyUnlimited() {
+		quota.MonthlyRemaining--
+	}
+
+	return quota, nil
+}
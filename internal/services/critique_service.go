@@ -0,0 +1,430 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// CritiqueService タスクの講評に関するサービスインターフェース
+type CritiqueService interface {
+	GetRubric(ctx context.Context, taskID, userID uint) ([]models.CritiqueCriterion, error)
+	SetRubric(ctx context.Context, taskID, userID uint, criteria []models.CritiqueCriterion) ([]models.CritiqueCriterion, error)
+	Submit(ctx context.Context, taskID, workID, userID uint, scores []models.CritiqueScore, comment string) (*models.Critique, error)
+	Delete(ctx context.Context, taskID, workID, userID uint) error
+	ListByWork(ctx context.Context, taskID, workID, userID uint) ([]models.Critique, error)
+	Summary(ctx context.Context, taskID, userID uint) ([]WorkCritiqueSummary, error)
+	Export(ctx context.Context, taskID, userID uint, start func(export *CritiqueExport) error, fn func(rows []CritiqueExportRow) error) error
+}
+
+const (
+	// 1つのタスクに設定できる評価基準の最大数
+	maxCritiqueCriteria = 20
+	// 評価基準の名前の最大文字数
+	maxCritiqueCriterionNameLength = 100
+	// 講評のコメントの最大文字数
+	maxCritiqueCommentLength = 2000
+	// エクスポート時に一度に読み込む作品数
+	critiqueExportBatchSize = 100
+)
+
+// WorkCritiqueSummary 作品ごとの講評の集計
+type WorkCritiqueSummary struct {
+	WorkID         uint                    `json:"work_id"`
+	CritiquesCount int64                   `json:"critiques_count"`
+	Average        float64                 `json:"average"` // 評価基準ごとの平均点の平均
+	Criteria       []CriterionScoreSummary `json:"criteria"`
+}
+
+// CriterionScoreSummary 評価基準ごとの平均点
+type CriterionScoreSummary struct {
+	CriterionID uint    `json:"criterion_id"`
+	Name        string  `json:"name"`
+	Average     float64 `json:"average"`
+	Count       int64   `json:"count"`
+}
+
+// CritiqueExport エクスポートする講評の列（評価基準とタスクの投票）
+type CritiqueExport struct {
+	Task     *models.Task
+	Criteria []models.CritiqueCriterion
+	Votes    []models.Vote
+}
+
+// CritiqueExportRow エクスポートする提出作品ごとの講評と投票結果
+type CritiqueExportRow struct {
+	Work      models.Work
+	Summary   WorkCritiqueSummary
+	Critiques []models.Critique
+	Votes     map[uint]int64 // 投票IDごとの作品の得票数
+}
+
+// critiqueService CritiqueServiceの実装
+type critiqueService struct {
+	critiqueRepo repository.CritiqueRepository
+	taskRepo     repository.TaskRepository
+	projectRepo  repository.ProjectRepository
+	workRepo     repository.WorkRepository
+	voteRepo     repository.VoteRepository
+}
+
+// NewCritiqueService CritiqueServiceを作成
+func NewCritiqueService(
+	critiqueRepo repository.CritiqueRepository,
+	taskRepo repository.TaskRepository,
+	projectRepo repository.ProjectRepository,
+	workRepo repository.WorkRepository,
+	voteRepo repository.VoteRepository,
+) CritiqueService {
+	return &critiqueService{
+		critiqueRepo: critiqueRepo,
+		taskRepo:     taskRepo,
+		projectRepo:  projectRepo,
+		workRepo:     workRepo,
+		voteRepo:     voteRepo,
+	}
+}
+
+// GetRubric タスクの評価基準を取得（プロジェクトのメンバーのみ）
+func (s *critiqueService) GetRubric(ctx context.Context, taskID, userID uint) ([]models.CritiqueCriterion, error) {
+	if _, err := s.memberTask(ctx, taskID, userID, "このタスクの評価基準を閲覧する権限がありません"); err != nil {
+		return nil, err
+	}
+	return s.critiqueRepo.ListCriteria(ctx, taskID)
+}
+
+// SetRubric タスクの評価基準を設定（プロジェクトのオーナーのみ、講評が提出される前のみ）
+// 空の場合は評価基準を削除する
+func (s *critiqueService) SetRubric(ctx context.Context, taskID, userID uint, criteria []models.CritiqueCriterion) ([]models.CritiqueCriterion, error) {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, errors.New("タスクが見つかりません")
+	}
+	isOwner, err := s.projectRepo.IsOwner(ctx, task.ProjectID, userID)
+	if err != nil || !isOwner {
+		return nil, errors.New("このタスクの評価基準を設定する権限がありません")
+	}
+
+	if len(criteria) > maxCritiqueCriteria {
+		return nil, fmt.Errorf("評価基準は%d件までです", maxCritiqueCriteria)
+	}
+	rubric := make([]models.CritiqueCriterion, 0, len(criteria))
+	for i, criterion := range criteria {
+		name := strings.TrimSpace(criterion.Name)
+		if name == "" {
+			return nil, errors.New("評価基準の名前は必須です")
+		}
+		if utf8.RuneCountInString(name) > maxCritiqueCriterionNameLength {
+			return nil, fmt.Errorf("評価基準の名前は%d文字以内で入力してください", maxCritiqueCriterionNameLength)
+		}
+		rubric = append(rubric, models.CritiqueCriterion{
+			TaskID:      taskID,
+			Name:        name,
+			Description: strings.TrimSpace(criterion.Description),
+			OrderIndex:  i,
+		})
+	}
+
+	if err := s.critiqueRepo.ReplaceCriteria(ctx, taskID, rubric); err != nil {
+		if errors.Is(err, repository.ErrCritiquesSubmitted) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("評価基準の設定に失敗しました: %v", err)
+	}
+	return s.critiqueRepo.ListCriteria(ctx, taskID)
+}
+
+// Submit タスクに提出された作品を講評（プロジェクトのメンバーのみ、自分の作品は除く）
+// 既に講評している場合は点数とコメントを置き換える
+func (s *critiqueService) Submit(ctx context.Context, taskID, workID, userID uint, scores []models.CritiqueScore, comment string) (*models.Critique, error) {
+	task, err := s.memberTask(ctx, taskID, userID, "このタスクの作品を講評する権限がありません")
+	if err != nil {
+		return nil, err
+	}
+	work, err := s.submittedWork(ctx, taskID, workID)
+	if err != nil {
+		return nil, err
+	}
+	if work.UserID == userID {
+		return nil, errors.New("自分の作品は講評できません")
+	}
+
+	comment = strings.TrimSpace(comment)
+	if utf8.RuneCountInString(comment) > maxCritiqueCommentLength {
+		return nil, fmt.Errorf("講評のコメントは%d文字以内で入力してください", maxCritiqueCommentLength)
+	}
+
+	// 全ての評価基準を1回ずつ採点しているか確認
+	criteria, err := s.critiqueRepo.ListCriteria(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("評価基準の取得に失敗しました: %v", err)
+	}
+	if len(criteria) == 0 {
+		return nil, errors.New("このタスクには評価基準が設定されていません")
+	}
+	scored := make(map[uint]int, len(scores))
+	for _, score := range scores {
+		if score.Score < models.CritiqueScoreMin || score.Score > models.CritiqueScoreMax {
+			return nil, fmt.Errorf("点数は%d〜%dで指定してください", models.CritiqueScoreMin, models.CritiqueScoreMax)
+		}
+		if _, ok := scored[score.CriterionID]; ok {
+			return nil, errors.New("同じ評価基準が複数回指定されています")
+		}
+		scored[score.CriterionID] = score.Score
+	}
+	critiqueScores := make([]models.CritiqueScore, 0, len(criteria))
+	for _, criterion := range criteria {
+		score, ok := scored[criterion.ID]
+		if !ok {
+			return nil, fmt.Errorf("評価基準「%s」の点数を指定してください", criterion.Name)
+		}
+		delete(scored, criterion.ID)
+		critiqueScores = append(critiqueScores, models.CritiqueScore{CriterionID: criterion.ID, Score: score})
+	}
+	if len(scored) > 0 {
+		return nil, errors.New("このタスクにない評価基準が指定されています")
+	}
+
+	critique := &models.Critique{
+		TaskID:  taskID,
+		WorkID:  workID,
+		UserID:  userID,
+		Comment: comment,
+		Scores:  critiqueScores,
+	}
+	if err := s.critiqueRepo.Save(ctx, critique); err != nil {
+		return nil, fmt.Errorf("講評の保存に失敗しました: %v", err)
+	}
+	touchProjectMember(ctx, s.projectRepo, task.ProjectID, userID)
+	return s.critiqueRepo.Find(ctx, taskID, workID, userID)
+}
+
+// Delete 自分の講評を削除
+func (s *critiqueService) Delete(ctx context.Context, taskID, workID, userID uint) error {
+	if err := s.critiqueRepo.Delete(ctx, taskID, workID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("講評が見つかりません")
+		}
+		return fmt.Errorf("講評の削除に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// ListByWork タスクに提出された作品の講評一覧を取得（プロジェクトのメンバーのみ）
+func (s *critiqueService) ListByWork(ctx context.Context, taskID, workID, userID uint) ([]models.Critique, error) {
+	if _, err := s.memberTask(ctx, taskID, userID, "このタスクの講評を閲覧する権限がありません"); err != nil {
+		return nil, err
+	}
+	if _, err := s.submittedWork(ctx, taskID, workID); err != nil {
+		return nil, err
+	}
+	return s.critiqueRepo.ListByWork(ctx, taskID, workID)
+}
+
+// Summary タスクに提出された作品ごとの講評の平均点を高い順に取得（プロジェクトのメンバーのみ）
+func (s *critiqueService) Summary(ctx context.Context, taskID, userID uint) ([]WorkCritiqueSummary, error) {
+	if _, err := s.memberTask(ctx, taskID, userID, "このタスクの講評を閲覧する権限がありません"); err != nil {
+		return nil, err
+	}
+
+	criteria, err := s.critiqueRepo.ListCriteria(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("評価基準の取得に失敗しました: %v", err)
+	}
+	rows, err := s.critiqueRepo.Summarize(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("講評の集計に失敗しました: %v", err)
+	}
+	return summarizeCritiques(criteria, rows), nil
+}
+
+// Export タスクに提出された作品ごとの講評と投票結果をエクスポートする（プロジェクトのオーナーのみ）
+// startに評価基準と投票を渡し、続けて提出作品を少しずつ読み込んでfnに渡す
+func (s *critiqueService) Export(ctx context.Context, taskID, userID uint, start func(export *CritiqueExport) error, fn func(rows []CritiqueExportRow) error) error {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return errors.New("タスクが見つかりません")
+	}
+	isOwner, err := s.projectRepo.IsOwner(ctx, task.ProjectID, userID)
+	if err != nil || !isOwner {
+		return errors.New("このタスクの講評をエクスポートする権限がありません")
+	}
+
+	criteria, err := s.critiqueRepo.ListCriteria(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("評価基準の取得に失敗しました: %v", err)
+	}
+	votes, err := s.voteRepo.ListByTask(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("投票の取得に失敗しました: %v", err)
+	}
+	// 投票ID → 作品ID → 得票数
+	votesByWork := make(map[uint]map[uint]int64, len(votes))
+	for _, vote := range votes {
+		counts := map[uint]int64{}
+		for _, option := range vote.Options {
+			if option.WorkID != nil {
+				counts[*option.WorkID] += option.VoteCount
+			}
+		}
+		votesByWork[vote.ID] = counts
+	}
+
+	if err := start(&CritiqueExport{Task: task, Criteria: criteria, Votes: votes}); err != nil {
+		return err
+	}
+
+	for page := 1; ; page++ {
+		works, _, err := s.taskRepo.GetWorks(ctx, taskID, page, critiqueExportBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(works) == 0 {
+			return nil
+		}
+
+		workIDs := make([]uint, 0, len(works))
+		for _, work := range works {
+			workIDs = append(workIDs, work.ID)
+		}
+		critiques, err := s.critiqueRepo.ListByWorks(ctx, taskID, workIDs)
+		if err != nil {
+			return err
+		}
+		byWork := map[uint][]models.Critique{}
+		for _, critique := range critiques {
+			byWork[critique.WorkID] = append(byWork[critique.WorkID], critique)
+		}
+		summaries := map[uint]WorkCritiqueSummary{}
+		for _, summary := range summarizeCritiques(criteria, critiqueScoreSummaries(critiques)) {
+			summaries[summary.WorkID] = summary
+		}
+
+		rows := make([]CritiqueExportRow, 0, len(works))
+		for _, work := range works {
+			row := CritiqueExportRow{
+				Work:      work,
+				Summary:   summaries[work.ID],
+				Critiques: byWork[work.ID],
+				Votes:     map[uint]int64{},
+			}
+			for voteID, counts := range votesByWork {
+				row.Votes[voteID] = counts[work.ID]
+			}
+			rows = append(rows, row)
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+		if len(works) < critiqueExportBatchSize {
+			return nil
+		}
+	}
+}
+
+// critiqueScoreSummaries 講評の点数を作品ごと・評価基準ごとに集計
+func critiqueScoreSummaries(critiques []models.Critique) []repository.CritiqueScoreSummary {
+	totals := map[[2]uint]int{} // 作品ID, 評価基準ID
+	counts := map[[2]uint]int64{}
+	for _, critique := range critiques {
+		for _, score := range critique.Scores {
+			key := [2]uint{critique.WorkID, score.CriterionID}
+			totals[key] += score.Score
+			counts[key]++
+		}
+	}
+
+	rows := make([]repository.CritiqueScoreSummary, 0, len(counts))
+	for key, count := range counts {
+		rows = append(rows, repository.CritiqueScoreSummary{
+			WorkID:      key[0],
+			CriterionID: key[1],
+			Average:     float64(totals[key]) / float64(count),
+			Count:       count,
+		})
+	}
+	return rows
+}
+
+// summarizeCritiques 評価基準ごとの集計を作品ごとにまとめ、評価基準の表示順に並べる
+func summarizeCritiques(criteria []models.CritiqueCriterion, rows []repository.CritiqueScoreSummary) []WorkCritiqueSummary {
+	byWork := map[uint]map[uint]repository.CritiqueScoreSummary{}
+	for _, row := range rows {
+		if byWork[row.WorkID] == nil {
+			byWork[row.WorkID] = map[uint]repository.CritiqueScoreSummary{}
+		}
+		byWork[row.WorkID][row.CriterionID] = row
+	}
+
+	summaries := make([]WorkCritiqueSummary, 0, len(byWork))
+	for workID, scores := range byWork {
+		summary := WorkCritiqueSummary{WorkID: workID, Criteria: []CriterionScoreSummary{}}
+		total := 0.0
+		for _, criterion := range criteria {
+			row, ok := scores[criterion.ID]
+			if !ok {
+				continue
+			}
+			summary.Criteria = append(summary.Criteria, CriterionScoreSummary{
+				CriterionID: criterion.ID,
+				Name:        criterion.Name,
+				Average:     row.Average,
+				Count:       row.Count,
+			})
+			total += row.Average
+			// 全ての評価基準を採点するため、最も多い件数が講評の数
+			if row.Count > summary.CritiquesCount {
+				summary.CritiquesCount = row.Count
+			}
+		}
+		if len(summary.Criteria) > 0 {
+			summary.Average = total / float64(len(summary.Criteria))
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Average != summaries[j].Average {
+			return summaries[i].Average > summaries[j].Average
+		}
+		return summaries[i].WorkID < summaries[j].WorkID
+	})
+	return summaries
+}
+
+// memberTask タスクを取得し、ユーザーがプロジェクトのメンバーか確認
+func (s *critiqueService) memberTask(ctx context.Context, taskID, userID uint, forbidden string) (*models.Task, error) {
+	task, err := s.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, errors.New("タスクが見つかりません")
+	}
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
+	if err != nil || !isMember {
+		return nil, errors.New(forbidden)
+	}
+	return task, nil
+}
+
+// submittedWork タスクに提出された作品を取得
+func (s *critiqueService) submittedWork(ctx context.Context, taskID, workID uint) (*models.Work, error) {
+	submitted, err := s.taskRepo.HasWork(ctx, taskID, workID)
+	if err != nil {
+		return nil, fmt.Errorf("作品の確認に失敗しました: %v", err)
+	}
+	if !submitted {
+		return nil, errors.New("タスクに提出された作品が見つかりません")
+	}
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	return work, nil
+}
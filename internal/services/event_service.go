@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+)
+
+// EventService ワークショップ・ハッカソンなどのオフラインイベントに関するサービスインターフェース
+type EventService interface {
+	Create(ctx context.Context, organizerID uint, input EventInput) (*models.Event, error)
+	GetByID(ctx context.Context, id uint) (*models.Event, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Event, error)
+	Update(ctx context.Context, id, userID uint, input EventInput) (*models.Event, error)
+	Delete(ctx context.Context, id, userID uint) error
+	List(ctx context.Context, period string, page, limit int) ([]models.Event, int64, int, error)
+	ListWorks(ctx context.Context, id uint, page, limit int) ([]models.Work, int64, int, error)
+	Register(ctx context.Context, id, userID uint) (*models.Event, error)
+	Unregister(ctx context.Context, id, userID uint) error
+	ListAttendees(ctx context.Context, id, userID uint) ([]models.EventAttendee, error)
+}
+
+// EventInput イベントの作成・更新の内容
+type EventInput struct {
+	Slug        string    `json:"slug"` // 作品の event に設定する識別子
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Venue       string    `json:"venue"`
+	StartsAt    time.Time `json:"starts_at"`
+	EndsAt      time.Time `json:"ends_at"`
+	Capacity    int       `json:"capacity"`   // 0の場合は無制限
+	ProjectID   *uint     `json:"project_id"` // 参加登録したユーザーをメンバーに追加するプロジェクト（オーナーのプロジェクトのみ）
+}
+
+// eventService EventServiceの実装
+type eventService struct {
+	eventRepo         repository.EventRepository
+	workRepo          repository.WorkRepository
+	projectRepo       repository.ProjectRepository
+	reputationService ReputationService
+	config            *config.Config
+}
+
+// NewEventService EventServiceを作成
+func NewEventService(
+	eventRepo repository.EventRepository,
+	workRepo repository.WorkRepository,
+	projectRepo repository.ProjectRepository,
+	reputationService ReputationService,
+	cfg *config.Config,
+) EventService {
+	return &eventService{
+		eventRepo:         eventRepo,
+		workRepo:          workRepo,
+		projectRepo:       projectRepo,
+		reputationService: reputationService,
+		config:            cfg,
+	}
+}
+
+// Create 新しいイベントを作成（プロジェクトの作成と同じレピュテーションが必要）
+func (s *eventService) Create(ctx context.Context, organizerID uint, input EventInput) (*models.Event, error) {
+	if err := s.reputationService.Require(ctx, organizerID, s.config.Reputation.MinToCreateProject); err != nil {
+		return nil, err
+	}
+
+	event := &models.Event{OrganizerID: organizerID}
+	if err := s.apply(ctx, event, organizerID, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		if errors.Is(err, repository.ErrEventSlugTaken) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("イベントの作成に失敗しました: %v", err)
+	}
+
+	return s.GetByID(ctx, event.ID)
+}
+
+// GetByID IDでイベントを取得
+func (s *eventService) GetByID(ctx context.Context, id uint) (*models.Event, error) {
+	event, err := s.eventRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("イベントが見つかりません")
+	}
+	return event, nil
+}
+
+// GetBySlug 識別子でイベントを取得
+func (s *eventService) GetBySlug(ctx context.Context, slug string) (*models.Event, error) {
+	event, err := s.eventRepo.FindBySlug(ctx, strings.ToLower(strings.TrimSpace(slug)))
+	if err != nil {
+		return nil, errors.New("イベントが見つかりません")
+	}
+	return event, nil
+}
+
+// Update イベントを更新（主催者のみ）
+func (s *eventService) Update(ctx context.Context, id, userID uint, input EventInput) (*models.Event, error) {
+	event, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event.OrganizerID != userID {
+		return nil, errors.New("このイベントを更新する権限がありません")
+	}
+
+	if err := s.apply(ctx, event, userID, input); err != nil {
+		return nil, err
+	}
+	if event.Capacity > 0 && int64(event.Capacity) < event.AttendeesCount {
+		return nil, fmt.Errorf("定員は参加登録済みの人数（%d人）以上にしてください", event.AttendeesCount)
+	}
+
+	if err := s.eventRepo.Update(ctx, event); err != nil {
+		if errors.Is(err, repository.ErrEventSlugTaken) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("イベントの更新に失敗しました: %v", err)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Delete イベントを削除（主催者のみ、作品とプロジェクトは削除しない）
+func (s *eventService) Delete(ctx context.Context, id, userID uint) error {
+	event, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if event.OrganizerID != userID {
+		return errors.New("このイベントを削除する権限がありません")
+	}
+
+	if err := s.eventRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("イベントの削除に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// List イベント一覧を取得（periodは upcoming・past、空の場合は全て）
+func (s *eventService) List(ctx context.Context, period string, page, limit int) ([]models.Event, int64, int, error) {
+	switch period {
+	case "", repository.EventPeriodUpcoming, repository.EventPeriodPast:
+	default:
+		return nil, 0, 0, errors.New("periodにはupcomingまたはpastを指定してください")
+	}
+
+	events, total, err := s.eventRepo.List(ctx, time.Now(), period, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return events, total, pages, nil
+}
+
+// ListWorks イベントで制作された作品一覧を取得（作品の event がイベントの識別子と一致するもの）
+func (s *eventService) ListWorks(ctx context.Context, id uint, page, limit int) ([]models.Work, int64, int, error) {
+	event, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	works, total, err := s.workRepo.ListByEvent(ctx, event.Slug, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return works, total, pages, nil
+}
+
+// Register イベントに参加登録し、イベントにプロジェクトがある場合はメンバーに追加
+func (s *eventService) Register(ctx context.Context, id, userID uint) (*models.Event, error) {
+	event, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event.EndsAt.Before(time.Now()) {
+		return nil, errors.New("このイベントは終了しています")
+	}
+
+	if err := s.eventRepo.Register(ctx, id, userID); err != nil {
+		if errors.Is(err, repository.ErrAlreadyRegistered) || errors.Is(err, repository.ErrEventFull) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("イベントへの参加登録に失敗しました: %v", err)
+	}
+
+	if event.ProjectID != nil {
+		if err := s.joinProject(ctx, *event.ProjectID, userID); err != nil {
+			// メンバーに追加できなかった場合は登録を取り消し、再度登録できるようにする
+			if unregisterErr := s.eventRepo.Unregister(ctx, id, userID); unregisterErr != nil {
+				log.Printf("イベント %d の参加登録の取り消しに失敗しました (user=%d): %v", id, userID, unregisterErr)
+			}
+			return nil, fmt.Errorf("イベントへの参加登録に失敗しました: %v", err)
+		}
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Unregister イベントの参加登録を取り消す（プロジェクトのメンバーからは外さない）
+func (s *eventService) Unregister(ctx context.Context, id, userID uint) error {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	registered, err := s.eventRepo.IsRegistered(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if !registered {
+		return errors.New("このイベントに参加登録していません")
+	}
+
+	return s.eventRepo.Unregister(ctx, id, userID)
+}
+
+// ListAttendees イベントの参加者一覧を取得（主催者のみ）
+func (s *eventService) ListAttendees(ctx context.Context, id, userID uint) ([]models.EventAttendee, error) {
+	event, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event.OrganizerID != userID {
+		return nil, errors.New("このイベントの参加者を閲覧する権限がありません")
+	}
+
+	return s.eventRepo.ListAttendees(ctx, id)
+}
+
+// apply 入力を検証してイベントに設定
+func (s *eventService) apply(ctx context.Context, event *models.Event, userID uint, input EventInput) error {
+	slug, err := normalizeEvent(input.Slug)
+	if err != nil {
+		return err
+	}
+	if slug == "" {
+		return errors.New("イベントの識別子は必須です")
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		return errors.New("イベント名は必須です")
+	}
+	if len([]rune(input.Venue)) > maxLocationNameLength {
+		return fmt.Errorf("会場は%d文字以内で指定してください", maxLocationNameLength)
+	}
+	if input.StartsAt.IsZero() || input.EndsAt.IsZero() {
+		return errors.New("開始日時と終了日時は必須です")
+	}
+	if !input.EndsAt.After(input.StartsAt) {
+		return errors.New("終了日時は開始日時より後にしてください")
+	}
+	if input.Capacity < 0 {
+		return errors.New("定員は0以上で指定してください")
+	}
+
+	// 参加者を追加するプロジェクトは、主催者がオーナーのもののみ指定できる
+	if input.ProjectID != nil {
+		if _, err := s.projectRepo.FindByID(ctx, *input.ProjectID); err != nil {
+			return errors.New("指定されたプロジェクトが見つかりません")
+		}
+		isOwner, err := s.projectRepo.IsOwner(ctx, *input.ProjectID, userID)
+		if err != nil || !isOwner {
+			return errors.New("このプロジェクトをイベントに設定する権限がありません")
+		}
+	}
+
+	event.Slug = slug
+	event.Name = strings.TrimSpace(input.Name)
+	event.Description = input.Description
+	event.DescriptionHTML = utils.RenderMarkdown(input.Description)
+	event.Venue = strings.TrimSpace(input.Venue)
+	event.StartsAt = input.StartsAt
+	event.EndsAt = input.EndsAt
+	event.Capacity = input.Capacity
+	event.ProjectID = input.ProjectID
+	return nil
+}
+
+// joinProject ユーザーをプロジェクトのメンバーに追加（既にメンバーの場合は何もしない）
+func (s *eventService) joinProject(ctx context.Context, projectID, userID uint) error {
+	isMember, err := s.projectRepo.IsMember(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if isMember {
+		return nil
+	}
+	return s.projectRepo.AddMember(ctx, projectID, userID, false)
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+)
+
+const (
+	// ハンドルの長さ
+	minHandleLength = 3
+	maxHandleLength = 30
+	// 自動で割り当てるハンドルの元にするニックネームの最大長（重複時の連番の分を空けておく）
+	maxHandleBaseLength = 24
+)
+
+// handlePattern ハンドルに使える文字（英小文字・数字・_）
+var handlePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// reservedHandles ルーティングや画面の名前と紛らわしいため使えないハンドル
+var reservedHandles = map[string]bool{
+	"admin":    true,
+	"api":      true,
+	"me":       true,
+	"profile":  true,
+	"ranking":  true,
+	"settings": true,
+	"support":  true,
+	"system":   true,
+}
+
+// normalizeHandle ハンドルを小文字にして検証（先頭の@は取り除く）
+func normalizeHandle(handle string) (string, error) {
+	handle = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+	if len(handle) < minHandleLength || len(handle) > maxHandleLength {
+		return "", fmt.Errorf("ハンドルは%d〜%d文字で指定してください", minHandleLength, maxHandleLength)
+	}
+	if !handlePattern.MatchString(handle) {
+		return "", errors.New("ハンドルには英小文字・数字・_のみ使用できます")
+	}
+	if reservedHandles[handle] {
+		return "", fmt.Errorf("%s はハンドルとして使用できません", handle)
+	}
+	return handle, nil
+}
+
+// generateHandle ニックネームなどを元に、使われていないハンドルを作成
+// 使える文字が残らない場合は user_ とランダムな文字列にする
+func generateHandle(ctx context.Context, userRepo repository.UserRepository, base string) (string, error) {
+	var b strings.Builder
+	for _, r := range strings.ToLower(base) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	candidate := b.String()
+	if len(candidate) > maxHandleBaseLength {
+		candidate = candidate[:maxHandleBaseLength]
+	}
+	if len(candidate) < minHandleLength || reservedHandles[candidate] {
+		candidate = "user_" + utils.GenerateRandomString(8)
+	}
+
+	for i := 1; i <= 20; i++ {
+		handle := candidate
+		if i > 1 {
+			handle = fmt.Sprintf("%s_%d", candidate, i)
+		}
+		taken, err := userRepo.IsHandleTaken(ctx, handle, 0)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return handle, nil
+		}
+	}
+
+	// 連番でも空いていない場合はランダムな文字列を付ける
+	return fmt.Sprintf("%s_%s", candidate, utils.GenerateRandomString(4)), nil
+}
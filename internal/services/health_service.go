@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// 依存サービスごとの確認のタイムアウト
+const healthCheckTimeout = 5 * time.Second
+
+// 依存サービスの状態
+const (
+	DependencyStatusOK    = "ok"
+	DependencyStatusError = "error"
+)
+
+// HealthCheck 依存サービスへの疎通を確認する関数
+type HealthCheck func(ctx context.Context) error
+
+// DependencyStatus 依存サービスの状態
+type DependencyStatus struct {
+	Name      string                `json:"name"`
+	Status    string                `json:"status"`
+	LatencyMS int64                 `json:"latency_ms"`
+	Error     string                `json:"error,omitempty"`
+	Circuit   *CircuitBreakerStatus `json:"circuit,omitempty"` // サーキットブレーカーを使用している場合のみ
+}
+
+// HealthService 依存サービス（DB・ストレージ・Lambdaなど）の状態を確認するサービスインターフェース
+type HealthService interface {
+	Register(name string, check HealthCheck)
+	RegisterCircuitBreaker(name string, breaker *CircuitBreaker)
+	CheckDependencies(ctx context.Context) ([]DependencyStatus, bool)
+}
+
+// healthService HealthServiceの実装
+type healthService struct {
+	mu       sync.RWMutex
+	checks   map[string]HealthCheck
+	breakers map[string]*CircuitBreaker
+}
+
+// NewHealthService HealthServiceを作成
+func NewHealthService() HealthService {
+	return &healthService{
+		checks:   map[string]HealthCheck{},
+		breakers: map[string]*CircuitBreaker{},
+	}
+}
+
+// Register 確認する依存サービスを登録
+func (s *healthService) Register(name string, check HealthCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks[name] = check
+}
+
+// RegisterCircuitBreaker 依存サービスのサーキットブレーカーを登録（確認結果に遮断の状態を含める）
+func (s *healthService) RegisterCircuitBreaker(name string, breaker *CircuitBreaker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.breakers[name] = breaker
+}
+
+// CheckDependencies 全ての依存サービスを並行して確認し、全て正常な場合はtrueを返す
+func (s *healthService) CheckDependencies(ctx context.Context) ([]DependencyStatus, bool) {
+	s.mu.RLock()
+	checks := make(map[string]HealthCheck, len(s.checks))
+	for name, check := range s.checks {
+		checks[name] = check
+	}
+	breakers := make(map[string]*CircuitBreaker, len(s.breakers))
+	for name, breaker := range s.breakers {
+		breakers[name] = breaker
+	}
+	s.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	results := make(chan DependencyStatus, len(checks))
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check HealthCheck) {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check(checkCtx)
+			status := DependencyStatus{
+				Name:      name,
+				Status:    DependencyStatusOK,
+				LatencyMS: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				status.Status = DependencyStatusError
+				status.Error = err.Error()
+			}
+			if breaker := breakers[name]; breaker != nil {
+				circuit := breaker.Status()
+				status.Circuit = &circuit
+			}
+			results <- status
+		}(name, check)
+	}
+	wg.Wait()
+	close(results)
+
+	healthy := true
+	statuses := make([]DependencyStatus, 0, len(checks))
+	for status := range results {
+		if status.Status != DependencyStatusOK {
+			healthy = false
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+
+	return statuses, healthy
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// HomeService トップページに表示するセクションに関するサービスインターフェース
+type HomeService interface {
+	Get(ctx context.Context) (*HomeSections, error)
+}
+
+// トップページの「今週の人気作品」でいいねを数える期間
+const homeTrendingPeriod = 7 * 24 * time.Hour
+
+// HomeSections トップページに表示するセクション（作品のコードは含まない）
+type HomeSections struct {
+	Featured    []models.Work           `json:"featured"` // ピックアップ中の作品
+	Trending    []models.Work           `json:"trending"` // 直近1週間にいいねの多かった作品
+	Newest      []models.Work           `json:"newest"`
+	Contests    []models.Project        `json:"contests"` // 応募を受け付けているコンテスト
+	Tags        []repository.TagSummary `json:"tags"`     // 作品の多いタグ
+	GeneratedAt time.Time               `json:"generated_at"`
+}
+
+// homeService HomeServiceの実装
+type homeService struct {
+	workRepo         repository.WorkRepository
+	projectRepo      repository.ProjectRepository
+	tagRepo          repository.TagRepository
+	collaboratorRepo repository.CollaboratorRepository
+	config           *config.Config
+
+	mu        sync.RWMutex
+	sections  *HomeSections
+	expiresAt time.Time
+}
+
+// NewHomeService HomeServiceを作成
+func NewHomeService(
+	workRepo repository.WorkRepository,
+	projectRepo repository.ProjectRepository,
+	tagRepo repository.TagRepository,
+	collaboratorRepo repository.CollaboratorRepository,
+	cfg *config.Config,
+) HomeService {
+	return &homeService{
+		workRepo:         workRepo,
+		projectRepo:      projectRepo,
+		tagRepo:          tagRepo,
+		collaboratorRepo: collaboratorRepo,
+		config:           cfg,
+	}
+}
+
+// Get トップページのセクションをまとめて取得（HOME_CACHE_TTL の間はキャッシュを返す）
+func (s *homeService) Get(ctx context.Context) (*HomeSections, error) {
+	if cached, ok := s.cached(); ok {
+		return cached, nil
+	}
+
+	cfg := s.config.Home
+	now := time.Now()
+
+	featured, _, err := s.workRepo.ListFeatured(ctx, now, 1, cfg.WorksLimit)
+	if err != nil {
+		return nil, err
+	}
+	trending, err := s.workRepo.ListTrending(ctx, now.Add(-homeTrendingPeriod), cfg.WorksLimit)
+	if err != nil {
+		return nil, err
+	}
+	// ピックアップ中の作品を先頭にしない、単純な新着順
+	newest, _, err := s.workRepo.List(ctx, 1, cfg.WorksLimit, "", "", "", "", nil, "newest")
+	if err != nil {
+		return nil, err
+	}
+	contests, _, err := s.projectRepo.ListContests(ctx, 1, cfg.ContestsLimit, models.ContestPhaseOpen)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.ListPopular(ctx, cfg.TagsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, works := range [][]models.Work{featured, trending, newest} {
+		attachCoAuthors(ctx, s.collaboratorRepo, works)
+		for i := range works {
+			works[i].PDEContent = ""
+			works[i].JSContent = ""
+		}
+	}
+	for i := range contests {
+		contests[i].InvitationCode = ""
+	}
+
+	sections := &HomeSections{
+		Featured:    featured,
+		Trending:    trending,
+		Newest:      newest,
+		Contests:    contests,
+		Tags:        tags,
+		GeneratedAt: now,
+	}
+	s.store(sections)
+	return sections, nil
+}
+
+// cached キャッシュされたセクションを取得
+func (s *homeService) cached() (*HomeSections, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.sections == nil || time.Now().After(s.expiresAt) {
+		return nil, false
+	}
+	return s.sections, true
+}
+
+// store セクションをキャッシュ（CacheTTLが0以下の場合はキャッシュしない）
+func (s *homeService) store(sections *HomeSections) {
+	ttl := s.config.Home.CacheTTL
+	if ttl <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sections = sections
+	s.expiresAt = time.Now().Add(ttl)
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+)
+
+// 1秒以上かかったリクエストはログに出力する
+const slowHTTPRequestThreshold = time.Second
+
+// HTTPClientStats 連携先ごとのHTTPリクエストの統計（起動してからの累計）
+type HTTPClientStats struct {
+	Name         string `json:"name"`
+	Requests     int64  `json:"requests"` // 再試行を含む
+	Failures     int64  `json:"failures"` // 通信エラー・429・5xx
+	Retries      int64  `json:"retries"`
+	AvgLatencyMs int64  `json:"avg_latency_ms"`
+	MaxLatencyMs int64  `json:"max_latency_ms"`
+}
+
+// HTTPClientFactory 外部サービスとの通信に使うHTTPクライアントを作成するインターフェース
+// 全ての連携先でタイムアウト・再試行・プロキシの設定を共通にし、連携先ごとに統計を取る
+type HTTPClientFactory interface {
+	// Client 連携先nameのHTTPクライアントを作成（timeoutが設定より長い場合はそちらを使う）
+	Client(name string, timeout time.Duration) *http.Client
+	// NewBareClient タイムアウトとプロキシだけを設定したHTTPクライアントを作成
+	// SDKがTransportに証明書などを設定する場合に使い、設定後にInstrumentで統計と再試行を追加する
+	NewBareClient(timeout time.Duration) *http.Client
+	// Instrument clientの通信を連携先nameの統計に記録し、一時的なエラーを再試行するようにする
+	Instrument(name string, client *http.Client)
+	Stats() []HTTPClientStats
+}
+
+// httpClientFactory HTTPClientFactoryの実装
+type httpClientFactory struct {
+	config config.HTTPClientConfig
+	proxy  func(*http.Request) (*url.URL, error)
+
+	mu    sync.Mutex
+	stats map[string]*httpClientCounters
+}
+
+// httpClientCounters 連携先ごとの集計途中の値
+type httpClientCounters struct {
+	requests       int64
+	failures       int64
+	retries        int64
+	totalLatencyMs int64
+	maxLatencyMs   int64
+}
+
+// NewHTTPClientFactory HTTPClientFactoryを作成
+func NewHTTPClientFactory(cfg *config.Config) HTTPClientFactory {
+	proxy := http.ProxyFromEnvironment
+	if cfg.HTTPClient.ProxyURL != "" {
+		// 設定の読み込み時に検証済み
+		if proxyURL, err := url.Parse(cfg.HTTPClient.ProxyURL); err == nil {
+			proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
+	return &httpClientFactory{
+		config: cfg.HTTPClient,
+		proxy:  proxy,
+		stats:  map[string]*httpClientCounters{},
+	}
+}
+
+// Client 連携先nameのHTTPクライアントを作成
+func (f *httpClientFactory) Client(name string, timeout time.Duration) *http.Client {
+	client := f.NewBareClient(timeout)
+	f.Instrument(name, client)
+	return client
+}
+
+// NewBareClient タイムアウトとプロキシだけを設定したHTTPクライアントを作成（連携先ごとに接続を分ける）
+func (f *httpClientFactory) NewBareClient(timeout time.Duration) *http.Client {
+	if timeout < f.config.Timeout {
+		timeout = f.config.Timeout
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   f.config.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                 f.proxy,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   10,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   f.config.ConnectTimeout,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
+
+// Instrument clientのTransportを統計の記録と再試行を行うRoundTripperで包む
+func (f *httpClientFactory) Instrument(name string, client *http.Client) {
+	f.mu.Lock()
+	if f.stats[name] == nil {
+		f.stats[name] = &httpClientCounters{}
+	}
+	f.mu.Unlock()
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	client.Transport = &instrumentedTransport{
+		name:       name,
+		base:       base,
+		maxRetries: f.config.MaxRetries,
+		backoff:    f.config.RetryBackoff,
+		factory:    f,
+	}
+}
+
+// Stats 連携先ごとの統計を名前順に取得
+func (f *httpClientFactory) Stats() []HTTPClientStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stats := make([]HTTPClientStats, 0, len(f.stats))
+	for name, c := range f.stats {
+		stat := HTTPClientStats{
+			Name:         name,
+			Requests:     c.requests,
+			Failures:     c.failures,
+			Retries:      c.retries,
+			MaxLatencyMs: c.maxLatencyMs,
+		}
+		if c.requests > 0 {
+			stat.AvgLatencyMs = c.totalLatencyMs / c.requests
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// record リクエスト1回の結果を記録
+func (f *httpClientFactory) record(name string, latency time.Duration, failed, retried bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c := f.stats[name]
+	c.requests++
+	if failed {
+		c.failures++
+	}
+	if retried {
+		c.retries++
+	}
+	ms := latency.Milliseconds()
+	c.totalLatencyMs += ms
+	if ms > c.maxLatencyMs {
+		c.maxLatencyMs = ms
+	}
+}
+
+// instrumentedTransport 統計の記録と、一時的なエラーの再試行を行うRoundTripper
+type instrumentedTransport struct {
+	name       string
+	base       http.RoundTripper
+	maxRetries int
+	backoff    time.Duration
+	factory    *httpClientFactory
+}
+
+// RoundTrip リクエストを送信し、冪等なリクエストが一時的なエラーになった場合は間隔を空けて再試行する
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		started := time.Now()
+		resp, err := t.base.RoundTrip(req)
+		latency := time.Since(started)
+
+		failed := isTransientHTTPFailure(resp, err)
+		t.factory.record(t.name, latency, failed, attempt > 0)
+		if err != nil {
+			log.Printf("[HTTP] %s %s %s の通信に失敗しました (%dms): %v", t.name, req.Method, req.URL.Host, latency.Milliseconds(), err)
+		} else if failed || latency >= slowHTTPRequestThreshold {
+			log.Printf("[HTTP] %s %s %s %d (%dms)", t.name, req.Method, req.URL.Host, resp.StatusCode, latency.Milliseconds())
+		}
+
+		if !failed || attempt >= t.maxRetries || !isRetryableRequest(req) || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		// 再試行のためにボディを作り直す
+		retryReq := req.Clone(req.Context())
+		if req.Body != nil && req.Body != http.NoBody {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			retryReq.Body = body
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(jitteredBackoff(t.backoff, attempt)):
+		}
+		req = retryReq
+	}
+}
+
+// isRetryableRequest 再試行しても副作用が重複しないリクエストか（ボディがある場合は作り直せるもののみ）
+func isRetryableRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// isTransientHTTPFailure 通信エラー・429・5xxなど、時間を置けば成功する可能性がある失敗か
+func isTransientHTTPFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// jitteredBackoff attempt回目の再試行までの待ち時間（倍々にした時間の半分から全体までのランダムな時間）
+func jitteredBackoff(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	delay := base << attempt
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
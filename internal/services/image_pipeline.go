@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+
+	// blurhashの計算で読み込む形式
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+)
+
+// blurhashの成分の数（横x縦）
+const (
+	blurhashXComponents = 4
+	blurhashYComponents = 3
+)
+
+// ImageJob パイプラインで変換中の画像
+type ImageJob struct {
+	Data     []byte             // アップロードする画像
+	Format   string             // 派生画像の形式（空の場合は元の画像と同じ）
+	Variants []ImageVariantSpec // 作成する派生画像
+	Blurhash string
+}
+
+// ImageVariantSpec 作成する派生画像
+type ImageVariantSpec struct {
+	Name   string
+	Width  int // 0の場合は縮小しない
+	Height int
+	Crop   string // limit または fill
+}
+
+// ImageStep 画像変換の手順
+type ImageStep interface {
+	Apply(job *ImageJob) error
+}
+
+// ImageStepFunc 関数をImageStepとして使うための型
+type ImageStepFunc func(job *ImageJob) error
+
+// Apply 手順を適用
+func (f ImageStepFunc) Apply(job *ImageJob) error {
+	return f(job)
+}
+
+// ImageStepFactory 種別ごとの設定から手順を作成する関数
+type ImageStepFactory func(cfg config.ImagePipelineConfig) (ImageStep, error)
+
+// imageSteps 設定（IMAGE_*_STEPS）で指定できる手順
+var imageSteps = map[string]ImageStepFactory{
+	"resize":     newResizeStep,
+	"strip_exif": func(config.ImagePipelineConfig) (ImageStep, error) { return ImageStepFunc(stripEXIF), nil },
+	"webp":       func(config.ImagePipelineConfig) (ImageStep, error) { return ImageStepFunc(convertToWebP), nil },
+	"blurhash":   func(config.ImagePipelineConfig) (ImageStep, error) { return ImageStepFunc(generateBlurhash), nil },
+}
+
+// RegisterImageStep 設定で指定できる画像変換の手順を追加（ImageServiceの作成前に呼び出す）
+func RegisterImageStep(name string, factory ImageStepFactory) {
+	imageSteps[name] = factory
+}
+
+// ImagePipeline アップロード種別ごとに設定された手順を順に適用する
+type ImagePipeline struct {
+	steps []ImageStep
+}
+
+// NewImagePipeline 設定からImagePipelineを作成
+func NewImagePipeline(cfg config.ImagePipelineConfig) (*ImagePipeline, error) {
+	pipeline := &ImagePipeline{}
+	for _, name := range cfg.Steps {
+		factory, ok := imageSteps[name]
+		if !ok {
+			return nil, fmt.Errorf("画像変換の手順 %s は存在しません", name)
+		}
+		step, err := factory(cfg)
+		if err != nil {
+			return nil, err
+		}
+		pipeline.steps = append(pipeline.steps, step)
+	}
+	return pipeline, nil
+}
+
+// Run 画像に手順を順に適用
+func (p *ImagePipeline) Run(data []byte) (*ImageJob, error) {
+	job := &ImageJob{Data: data}
+	for _, step := range p.steps {
+		if err := step.Apply(job); err != nil {
+			return nil, err
+		}
+	}
+	return job, nil
+}
+
+// newResizeStep 設定されたサイズごとに縮小した派生画像を作成する手順
+func newResizeStep(cfg config.ImagePipelineConfig) (ImageStep, error) {
+	crop := cfg.Crop
+	if crop == "" {
+		crop = "limit"
+	}
+	if crop != "limit" && crop != "fill" {
+		return nil, fmt.Errorf("画像の縮小方法にはlimitまたはfillを指定してください: %s", crop)
+	}
+
+	var specs []ImageVariantSpec
+	for _, size := range cfg.Sizes {
+		spec, err := parseImageSize(size)
+		if err != nil {
+			return nil, err
+		}
+		spec.Crop = crop
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("resizeで作成するサイズを指定してください")
+	}
+
+	return ImageStepFunc(func(job *ImageJob) error {
+		job.Variants = append(job.Variants, specs...)
+		return nil
+	}), nil
+}
+
+// parseImageSize サイズの設定（名前:幅x高さ）を解析
+func parseImageSize(value string) (ImageVariantSpec, error) {
+	name, size, ok := strings.Cut(value, ":")
+	widthStr, heightStr, ok2 := strings.Cut(size, "x")
+	width, err := strconv.Atoi(widthStr)
+	height, err2 := strconv.Atoi(heightStr)
+	if !ok || !ok2 || name == "" || err != nil || err2 != nil || width <= 0 || height <= 0 {
+		return ImageVariantSpec{}, fmt.Errorf("画像のサイズは 名前:幅x高さ の形式で指定してください: %s", value)
+	}
+	return ImageVariantSpec{Name: name, Width: width, Height: height}, nil
+}
+
+// stripEXIF 撮影場所などのメタデータを除去する手順
+func stripEXIF(job *ImageJob) error {
+	job.Data = utils.StripImageMetadata(job.Data)
+	return nil
+}
+
+// convertToWebP 派生画像をWebPで作成する手順
+func convertToWebP(job *ImageJob) error {
+	job.Format = "webp"
+	return nil
+}
+
+// generateBlurhash 読み込み中に表示するぼかし画像を作成する手順
+// 読み込めない形式（WebPなど）の場合は作成しない
+func generateBlurhash(job *ImageJob) error {
+	img, _, err := image.Decode(bytes.NewReader(job.Data))
+	if err != nil {
+		return nil
+	}
+	hash, err := utils.EncodeBlurhash(img, blurhashXComponents, blurhashYComponents)
+	if err != nil {
+		return nil
+	}
+	job.Blurhash = hash
+	return nil
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// ErrImageUploadUnavailable Cloudinaryが設定されていないため画像をアップロードできない場合のエラー
+var ErrImageUploadUnavailable = errors.New("画像のアップロードは利用できません")
+
+// アップロードできる画像の形式
+var imageContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// ImageService アップロードされた画像を種別ごとの手順で変換して保存するサービスインターフェース
+type ImageService interface {
+	Upload(ctx context.Context, userID uint, kind string, r io.Reader) (*models.Image, error)
+	GetByID(ctx context.Context, id uint) (*models.Image, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// imageService ImageServiceの実装
+type imageService struct {
+	imageRepo         repository.ImageRepository
+	cloudinaryService CloudinaryService // 未設定の場合はnil
+	pipelines         map[string]*ImagePipeline
+	qualities         map[string]int
+	config            *config.Config
+}
+
+// NewImageService ImageServiceを作成（手順の設定が正しくない場合はエラー）
+func NewImageService(imageRepo repository.ImageRepository, cloudinaryService CloudinaryService, cfg *config.Config) (ImageService, error) {
+	s := &imageService{
+		imageRepo:         imageRepo,
+		cloudinaryService: cloudinaryService,
+		pipelines:         map[string]*ImagePipeline{},
+		qualities:         map[string]int{},
+		config:            cfg,
+	}
+
+	for kind, pipelineCfg := range map[string]config.ImagePipelineConfig{
+		models.ImageKindThumbnail: cfg.Image.Thumbnail,
+		models.ImageKindAvatar:    cfg.Image.Avatar,
+		models.ImageKindAsset:     cfg.Image.Asset,
+		models.ImageKindSnapshot:  cfg.Image.Snapshot,
+	} {
+		pipeline, err := NewImagePipeline(pipelineCfg)
+		if err != nil {
+			return nil, fmt.Errorf("%sの画像変換の設定が正しくありません: %v", kind, err)
+		}
+		s.pipelines[kind] = pipeline
+		s.qualities[kind] = pipelineCfg.Quality
+	}
+
+	return s, nil
+}
+
+// Upload 画像を種別ごとの手順で変換し、派生画像とともに保存
+func (s *imageService) Upload(ctx context.Context, userID uint, kind string, r io.Reader) (*models.Image, error) {
+	if s.cloudinaryService == nil {
+		return nil, ErrImageUploadUnavailable
+	}
+	pipeline, ok := s.pipelines[kind]
+	if !ok {
+		return nil, fmt.Errorf("画像の種別 %s は存在しません", kind)
+	}
+
+	maxSize := int64(s.config.Image.MaxSizeMB) * 1024 * 1024
+	data, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("画像の読み込みに失敗しました: %v", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("画像のサイズが上限（%dMB）を超えています", s.config.Image.MaxSizeMB)
+	}
+	if !imageContentTypes[http.DetectContentType(data)] {
+		return nil, errors.New("対応していない画像形式です（JPEG・PNG・GIF・WebPのみ）")
+	}
+
+	job, err := pipeline.Run(data)
+	if err != nil {
+		return nil, fmt.Errorf("画像の変換に失敗しました: %v", err)
+	}
+
+	// 縮小しない場合も、形式を変える場合は元のサイズの派生画像を作成する
+	variants := job.Variants
+	if len(variants) == 0 && job.Format != "" {
+		variants = []ImageVariantSpec{{Name: "original"}}
+	}
+	transformations := make([]string, len(variants))
+	for i, variant := range variants {
+		transformations[i] = imageTransformation(variant, job.Format, s.qualities[kind])
+	}
+
+	fileName := fmt.Sprintf("%s_%d_%d", kind, userID, time.Now().UnixNano())
+	upload, err := s.cloudinaryService.UploadImageWithVariants(ctx, job.Data, fileName, transformations)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &models.Image{
+		UserID:   userID,
+		Kind:     kind,
+		PublicID: upload.PublicID,
+		URL:      upload.URL,
+		Format:   upload.Format,
+		Width:    upload.Width,
+		Height:   upload.Height,
+		Bytes:    upload.Bytes,
+		Blurhash: job.Blurhash,
+		Variants: []models.ImageVariant{},
+	}
+	for i, variant := range upload.Variants {
+		image.Variants = append(image.Variants, models.ImageVariant{
+			Name:   variants[i].Name,
+			URL:    variant.URL,
+			Format: variant.Format,
+			Width:  variant.Width,
+			Height: variant.Height,
+			Bytes:  variant.Bytes,
+		})
+	}
+	if err := s.imageRepo.Create(ctx, image); err != nil {
+		// 記録できなかった画像は参照されないため削除する
+		if deleteErr := s.cloudinaryService.DeleteImage(context.Background(), upload.PublicID); deleteErr != nil {
+			log.Printf("画像 %s の削除に失敗しました: %v", upload.PublicID, deleteErr)
+		}
+		return nil, fmt.Errorf("画像の保存に失敗しました: %v", err)
+	}
+
+	return image, nil
+}
+
+// GetByID IDで画像を取得
+func (s *imageService) GetByID(ctx context.Context, id uint) (*models.Image, error) {
+	image, err := s.imageRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("画像が見つかりません")
+	}
+	return image, nil
+}
+
+// Delete 画像を派生画像とともに削除
+func (s *imageService) Delete(ctx context.Context, id uint) error {
+	image, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if s.cloudinaryService != nil {
+		if err := s.cloudinaryService.DeleteImage(ctx, image.PublicID); err != nil {
+			return err
+		}
+	}
+	return s.imageRepo.Delete(ctx, id)
+}
+
+// imageTransformation 派生画像を作成するCloudinaryの変換（形式は末尾に拡張子として指定する）
+func imageTransformation(variant ImageVariantSpec, format string, quality int) string {
+	var params []string
+	if variant.Width > 0 {
+		params = append(params, fmt.Sprintf("c_%s,w_%d,h_%d", variant.Crop, variant.Width, variant.Height))
+	}
+	if quality > 0 {
+		params = append(params, fmt.Sprintf("q_%d", quality))
+	}
+	if len(params) == 0 {
+		params = append(params, "q_auto")
+	}
+	transformation := strings.Join(params, ",")
+	if format != "" {
+		transformation += "/" + format
+	}
+	return transformation
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// 分析の対象期間（日数）
+const (
+	defaultInvocationAnalyticsDays = 30
+	maxInvocationAnalyticsDays     = 365
+)
+
+// 分析結果に含める最近の失敗の件数
+const recentInvocationFailures = 20
+
+// InvocationStats Lambda呼び出しの集計値
+type InvocationStats struct {
+	Invocations      int64   `json:"invocations"`
+	Failures         int64   `json:"failures"`
+	Retries          int64   `json:"retries"`
+	FailureRate      float64 `json:"failure_rate"`
+	AvgDurationMs    int64   `json:"avg_duration_ms"`
+	MaxDurationMs    int64   `json:"max_duration_ms"`
+	AvgPayloadBytes  int64   `json:"avg_payload_bytes"`
+	GBSeconds        float64 `json:"gb_seconds"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+// InvocationDayStats 1日分の集計
+type InvocationDayStats struct {
+	Date string `json:"date"`
+	InvocationStats
+}
+
+// InvocationVersionStats 変換バージョンごとの集計
+type InvocationVersionStats struct {
+	Version   string `json:"version"`
+	FirstDate string `json:"first_date"` // 期間内で最初に呼び出した日
+	LastDate  string `json:"last_date"`  // 期間内で最後に呼び出した日
+	InvocationStats
+}
+
+// InvocationAnalytics Lambda呼び出しの分析結果
+type InvocationAnalytics struct {
+	From           string                   `json:"from"`
+	To             string                   `json:"to"`
+	Total          InvocationStats          `json:"total"`
+	Days           []InvocationDayStats     `json:"days"`     // 呼び出しのない日も含めて日付の古い順
+	Versions       []InvocationVersionStats `json:"versions"` // 期間内で最初に呼び出した順
+	RecentFailures []models.InvocationLog   `json:"recent_failures"`
+}
+
+// InvocationAnalyticsService Lambda呼び出しの費用と所要時間を分析するサービスインターフェース
+type InvocationAnalyticsService interface {
+	Analyze(ctx context.Context, days int) (*InvocationAnalytics, error)
+	Purge(ctx context.Context) (int64, error)
+}
+
+// invocationAnalyticsService InvocationAnalyticsServiceの実装
+type invocationAnalyticsService struct {
+	invocationRepo repository.InvocationLogRepository
+	config         *config.Config
+}
+
+// NewInvocationAnalyticsService InvocationAnalyticsServiceを作成
+func NewInvocationAnalyticsService(invocationRepo repository.InvocationLogRepository, cfg *config.Config) InvocationAnalyticsService {
+	return &invocationAnalyticsService{
+		invocationRepo: invocationRepo,
+		config:         cfg,
+	}
+}
+
+// invocationTotals 集計途中の合計値
+type invocationTotals struct {
+	invocations   int64
+	failures      int64
+	retries       int64
+	durationMs    int64
+	maxDurationMs int64
+	payloadBytes  int64
+}
+
+// add 日付・バージョンごとの集計を加算
+func (t *invocationTotals) add(summary repository.InvocationSummary) {
+	t.invocations += summary.Invocations
+	t.failures += summary.Failures
+	t.retries += summary.Retries
+	t.durationMs += summary.TotalDurationMs
+	t.payloadBytes += summary.TotalPayloadBytes
+	if summary.MaxDurationMs > t.maxDurationMs {
+		t.maxDurationMs = summary.MaxDurationMs
+	}
+}
+
+// Analyze 過去days日間（今日を含む）の呼び出しを日ごと・バージョンごとに集計
+func (s *invocationAnalyticsService) Analyze(ctx context.Context, days int) (*InvocationAnalytics, error) {
+	if days <= 0 {
+		days = defaultInvocationAnalyticsDays
+	}
+	if days > maxInvocationAnalyticsDays {
+		return nil, fmt.Errorf("期間は%d日以下で指定してください", maxInvocationAnalyticsDays)
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today.AddDate(0, 0, 1-days)
+
+	summaries, err := s.invocationRepo.SummarizeDaily(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	failures, err := s.invocationRepo.ListRecentFailures(ctx, from, recentInvocationFailures)
+	if err != nil {
+		return nil, err
+	}
+
+	var total invocationTotals
+	byDate := make(map[string]*invocationTotals)
+	byVersion := make(map[string]*invocationTotals)
+	versions := []InvocationVersionStats{}
+	versionIndex := make(map[string]int)
+	for _, summary := range summaries {
+		total.add(summary)
+
+		if byDate[summary.Date] == nil {
+			byDate[summary.Date] = &invocationTotals{}
+		}
+		byDate[summary.Date].add(summary)
+
+		// 集計は日付の古い順のため、最初に現れた日が期間内で最初に呼び出した日になる
+		if _, ok := versionIndex[summary.Version]; !ok {
+			versionIndex[summary.Version] = len(versions)
+			versions = append(versions, InvocationVersionStats{Version: summary.Version, FirstDate: summary.Date})
+			byVersion[summary.Version] = &invocationTotals{}
+		}
+		byVersion[summary.Version].add(summary)
+		versions[versionIndex[summary.Version]].LastDate = summary.Date
+	}
+
+	analytics := &InvocationAnalytics{
+		From:           from.Format("2006-01-02"),
+		To:             today.Format("2006-01-02"),
+		Total:          s.stats(total),
+		Versions:       versions,
+		RecentFailures: failures,
+	}
+	if analytics.RecentFailures == nil {
+		analytics.RecentFailures = []models.InvocationLog{}
+	}
+
+	// 呼び出しのない日も0件として埋める
+	for day := from; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		dayStats := InvocationDayStats{Date: date}
+		if totals := byDate[date]; totals != nil {
+			dayStats.InvocationStats = s.stats(*totals)
+		}
+		analytics.Days = append(analytics.Days, dayStats)
+	}
+	for i := range analytics.Versions {
+		analytics.Versions[i].InvocationStats = s.stats(*byVersion[analytics.Versions[i].Version])
+	}
+
+	return analytics, nil
+}
+
+// stats 合計値から平均と費用の見積もりを計算
+// 所要時間はサーバーから見た時間のため、Lambdaの課金対象の時間より通信の分だけ長くなる
+func (s *invocationAnalyticsService) stats(t invocationTotals) InvocationStats {
+	stats := InvocationStats{
+		Invocations:   t.invocations,
+		Failures:      t.failures,
+		Retries:       t.retries,
+		MaxDurationMs: t.maxDurationMs,
+	}
+	if t.invocations == 0 {
+		return stats
+	}
+
+	stats.FailureRate = roundTo(float64(t.failures)/float64(t.invocations), 4)
+	stats.AvgDurationMs = t.durationMs / t.invocations
+	stats.AvgPayloadBytes = t.payloadBytes / t.invocations
+
+	lambdaCfg := s.config.Lambda
+	gbSeconds := float64(t.durationMs) / 1000 * float64(lambdaCfg.MemoryMB) / 1024
+	cost := gbSeconds*lambdaCfg.PricePerGBSecond + float64(t.invocations)/1e6*lambdaCfg.PricePerMillionRequests
+	stats.GBSeconds = roundTo(gbSeconds, 3)
+	stats.EstimatedCostUSD = roundTo(cost, 6)
+	return stats
+}
+
+// Purge 保持期間を過ぎた呼び出し履歴を削除
+func (s *invocationAnalyticsService) Purge(ctx context.Context) (int64, error) {
+	if s.config.Lambda.LogRetentionDays <= 0 {
+		return 0, nil
+	}
+	return s.invocationRepo.DeleteBefore(ctx, time.Now().AddDate(0, 0, -s.config.Lambda.LogRetentionDays))
+}
+
+// roundTo 小数点以下digits桁に丸める
+func roundTo(value float64, digits int) float64 {
+	scale := math.Pow(10, float64(digits))
+	return math.Round(value*scale) / scale
+}
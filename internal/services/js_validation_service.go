@@ -0,0 +1,273 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+)
+
+// JS検証の結果
+const (
+	JSValidationPassed   = "passed"
+	JSValidationFlagged  = "flagged"
+	JSValidationRejected = "rejected"
+)
+
+// JS検証モード
+const (
+	JSValidationModeReject = "reject" // 問題のあるJSは保存しない
+	JSValidationModeFlag   = "flag"   // 問題のあるJSも保存し、フラグを立てる
+)
+
+// bannedJSAPI 変換後のJSで使用を禁止するAPI
+type bannedJSAPI struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// 他のユーザーのブラウザで実行されるため、通信・ストレージ・動的コード実行・画面遷移を禁止する
+var bannedJSAPIs = []bannedJSAPI{
+	{"fetch", regexp.MustCompile(`\bfetch\s*\(`)},
+	{"XMLHttpRequest", regexp.MustCompile(`\bXMLHttpRequest\b`)},
+	{"WebSocket", regexp.MustCompile(`\bWebSocket\b`)},
+	{"EventSource", regexp.MustCompile(`\bEventSource\b`)},
+	{"navigator.sendBeacon", regexp.MustCompile(`\bsendBeacon\b`)},
+	{"localStorage", regexp.MustCompile(`\blocalStorage\b`)},
+	{"sessionStorage", regexp.MustCompile(`\bsessionStorage\b`)},
+	{"indexedDB", regexp.MustCompile(`\bindexedDB\b`)},
+	{"document.cookie", regexp.MustCompile(`\bdocument\s*\.\s*cookie\b`)},
+	{"eval", regexp.MustCompile(`\beval\s*\(`)},
+	{"Function constructor", regexp.MustCompile(`\bnew\s+Function\b`)},
+	{"dynamic import", regexp.MustCompile(`\bimport\s*\(`)},
+	{"importScripts", regexp.MustCompile(`\bimportScripts\b`)},
+	{"location", regexp.MustCompile(`\b(?:window|document|top|parent)\s*\.\s*location\b|\blocation\s*\.\s*(?:href|assign|replace)\b`)},
+	{"window.open", regexp.MustCompile(`\bwindow\s*\.\s*open\s*\(`)},
+}
+
+// JSValidationResult JS検証結果
+type JSValidationResult struct {
+	Status string   `json:"status"`
+	Issues []string `json:"issues,omitempty"`
+}
+
+// JSValidationService 変換後のJSを検証するサービスインターフェース
+type JSValidationService interface {
+	Validate(jsContent string) *JSValidationResult
+	Apply(work *models.Work, output ConversionOutput, version string) *JSValidationResult
+}
+
+// jsValidationService JSValidationServiceの実装
+type jsValidationService struct {
+	config *config.Config
+}
+
+// NewJSValidationService JSValidationServiceを作成
+func NewJSValidationService(cfg *config.Config) JSValidationService {
+	return &jsValidationService{config: cfg}
+}
+
+// Validate サイズ・禁止API・構文を検証
+func (s *jsValidationService) Validate(jsContent string) *JSValidationResult {
+	var issues []string
+
+	// サイズ
+	maxSize := s.config.Validation.JSMaxSizeKB * 1024
+	if maxSize > 0 && len(jsContent) > maxSize {
+		issues = append(issues, fmt.Sprintf("JSのサイズが上限（%dKB）を超えています", s.config.Validation.JSMaxSizeKB))
+	}
+
+	// 禁止API
+	for _, api := range bannedJSAPIs {
+		if api.pattern.MatchString(jsContent) {
+			issues = append(issues, fmt.Sprintf("使用が禁止されているAPIが含まれています: %s", api.name))
+		}
+	}
+
+	// 構文
+	if err := checkJSSyntax(jsContent); err != nil {
+		issues = append(issues, fmt.Sprintf("構文エラー: %v", err))
+	}
+
+	result := &JSValidationResult{Status: JSValidationPassed, Issues: issues}
+	if len(issues) > 0 {
+		if s.config.Validation.JSMode == JSValidationModeFlag {
+			result.Status = JSValidationFlagged
+		} else {
+			result.Status = JSValidationRejected
+		}
+	}
+
+	return result
+}
+
+// Apply 検証結果に応じて変換後のJSと変換の来歴を作品に設定
+// 拒否された場合はJSを保存しない
+func (s *jsValidationService) Apply(work *models.Work, output ConversionOutput, version string) *JSValidationResult {
+	jsContent := output.JSContent
+	result := s.Validate(jsContent)
+
+	now := time.Now()
+	work.ConverterVersion = version
+	work.ConvertedAt = &now
+	work.ConversionMs = output.Duration.Milliseconds()
+	work.ConversionCached = output.Cached
+	work.JSValidationStatus = result.Status
+	work.JSValidationIssues = strings.Join(result.Issues, "\n")
+
+	if result.Status == JSValidationRejected {
+		work.JSContent = ""
+		log.Printf("変換後のJSを拒否しました (WorkID=%d): %s", work.ID, strings.Join(result.Issues, ", "))
+	} else {
+		work.JSContent = jsContent
+	}
+
+	return result
+}
+
+// checkJSSyntax 括弧の対応・文字列・コメントの終端を確認する簡易構文チェック
+func checkJSSyntax(src string) error {
+	var stack []byte
+	// テンプレートリテラル内の ${ } の入れ子を記録
+	var templateDepth []int
+
+	closing := map[byte]byte{')': '(', ']': '[', '}': '{'}
+	prevSignificant := byte(0)
+
+	// 直前の文字から正規表現リテラルの開始かどうかを判定
+	regexAllowed := func() bool {
+		return prevSignificant == 0 || strings.IndexByte("(,=:[!&|?{};+-*%<>~^", prevSignificant) >= 0
+	}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			// 行コメント
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			// ブロックコメント
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return fmt.Errorf("コメントが閉じられていません")
+			}
+			i += end + 3
+			continue
+		case c == '"' || c == '\'':
+			end, err := skipQuoted(src, i, c)
+			if err != nil {
+				return err
+			}
+			i = end
+		case c == '`':
+			end, opened, err := skipTemplate(src, i+1)
+			if err != nil {
+				return err
+			}
+			i = end
+			if opened {
+				stack = append(stack, '{')
+				templateDepth = append(templateDepth, len(stack))
+			}
+		case c == '/' && regexAllowed():
+			end, err := skipRegex(src, i)
+			if err != nil {
+				return err
+			}
+			i = end
+		case c == '(' || c == '[' || c == '{':
+			stack = append(stack, c)
+		case c == ')' || c == ']' || c == '}':
+			if len(stack) == 0 || stack[len(stack)-1] != closing[c] {
+				return fmt.Errorf("対応しない '%c' があります", c)
+			}
+			// テンプレートリテラルの ${ } が閉じた場合は続きを読む
+			if c == '}' && len(templateDepth) > 0 && templateDepth[len(templateDepth)-1] == len(stack) {
+				stack = stack[:len(stack)-1]
+				templateDepth = templateDepth[:len(templateDepth)-1]
+				end, opened, err := skipTemplate(src, i+1)
+				if err != nil {
+					return err
+				}
+				i = end
+				if opened {
+					stack = append(stack, '{')
+					templateDepth = append(templateDepth, len(stack))
+				}
+				prevSignificant = '`'
+				continue
+			}
+			stack = stack[:len(stack)-1]
+		}
+
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			prevSignificant = src[i]
+		}
+	}
+
+	if len(stack) > 0 {
+		return fmt.Errorf("'%c' が閉じられていません", stack[len(stack)-1])
+	}
+	return nil
+}
+
+// skipQuoted 文字列リテラルの終端の位置を返す
+func skipQuoted(src string, start int, quote byte) (int, error) {
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case quote:
+			return i, nil
+		case '\n':
+			return 0, fmt.Errorf("文字列が閉じられていません")
+		}
+	}
+	return 0, fmt.Errorf("文字列が閉じられていません")
+}
+
+// skipTemplate テンプレートリテラルを読み進め、終端または ${ の位置を返す
+func skipTemplate(src string, start int) (int, bool, error) {
+	for i := start; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '`':
+			return i, false, nil
+		case '$':
+			if i+1 < len(src) && src[i+1] == '{' {
+				return i + 1, true, nil
+			}
+		}
+	}
+	return 0, false, fmt.Errorf("テンプレートリテラルが閉じられていません")
+}
+
+// skipRegex 正規表現リテラルの終端の位置を返す
+func skipRegex(src string, start int) (int, error) {
+	inClass := false
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '[':
+			inClass = true
+		case ']':
+			inClass = false
+		case '/':
+			if !inClass {
+				return i, nil
+			}
+		case '\n':
+			return 0, fmt.Errorf("正規表現が閉じられていません")
+		}
+	}
+	return 0, fmt.Errorf("正規表現が閉じられていません")
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/lambda"
+)
+
+// LambdaService Lambda関数との通信を管理するサービス
+type LambdaService interface {
+	// PDEをJavaScriptに変換するLambdaを呼び出す
+	ConvertPDEToJS(ctx context.Context, pdeContent string) (string, error)
+	// 変換に使用するLambdaのバージョンを取得
+	ConverterVersion() string
+	// Lambda関数を呼び出せるか確認
+	Ping(ctx context.Context) error
+}
+
+// lambdaService LambdaServiceの実装
+type lambdaService struct {
+	config         *config.Config
+	lambdaClient   *lambda.Lambda
+	invocationRepo repository.InvocationLogRepository
+}
+
+// NewLambdaService LambdaServiceを作成（invocationRepoがnilの場合は呼び出し履歴を記録しない）
+func NewLambdaService(invocationRepo repository.InvocationLogRepository, httpClients HTTPClientFactory, cfg *config.Config) LambdaService {
+	// AWS セッション作成
+	// 再試行はConvertPDEToJSで行うため、SDKの自動再試行は無効にする
+	// AWS_CA_BUNDLEなどをSDKがTransportに設定できるよう、統計と再試行はセッションの作成後に追加する
+	httpClient := httpClients.NewBareClient(cfg.Lambda.Timeout)
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:     aws.String(cfg.Lambda.Region),
+		MaxRetries: aws.Int(0),
+		HTTPClient: httpClient,
+	}))
+	httpClients.Instrument("lambda", httpClient)
+
+	// Lambda クライアント作成
+	lambdaClient := lambda.New(sess)
+
+	return &lambdaService{
+		config:         cfg,
+		lambdaClient:   lambdaClient,
+		invocationRepo: invocationRepo,
+	}
+}
+
+// PDEConversionRequest Lambda関数に送るリクエスト構造体
+type PDEConversionRequest struct {
+	PDEContent string `json:"pdeContent"`
+	CanvasID   string `json:"canvasId"`
+}
+
+// PDEConversionResponse Lambda関数からのレスポンス構造体
+type PDEConversionResponse struct {
+	Success   bool   `json:"success"`
+	Message   string `json:"message,omitempty"`
+	JSContent string `json:"jsContent,omitempty"`
+}
+
+// ConverterVersion 変換に使用するLambdaのバージョンを取得
+func (s *lambdaService) ConverterVersion() string {
+	if s.config.Lambda.Version == "" {
+		return "$LATEST"
+	}
+	return s.config.Lambda.Version
+}
+
+// errConversionFailed 変換処理自体の失敗（PDEコードの問題など、再試行しても結果が変わらないもの）
+var errConversionFailed = errors.New("PDE変換処理が失敗しました")
+
+// ConvertPDEToJS PDEをJavaScriptに変換するLambdaを呼び出す
+// スロットリングやタイムアウトなど一時的なエラーの場合は、設定された回数まで間隔を空けて再試行する
+func (s *lambdaService) ConvertPDEToJS(ctx context.Context, pdeContent string) (string, error) {
+	if pdeContent == "" {
+		return "", fmt.Errorf("PDEコンテンツが空です")
+	}
+
+	backoff := s.config.Lambda.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		jsContent, err := s.invokeConverter(ctx, pdeContent, attempt)
+		if err == nil || errors.Is(err, errConversionFailed) || attempt >= s.config.Lambda.MaxRetries || ctx.Err() != nil {
+			return jsContent, err
+		}
+
+		log.Printf("PDE変換を再試行します (%d/%d): %v", attempt+1, s.config.Lambda.MaxRetries, err)
+		select {
+		case <-ctx.Done():
+			return "", err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// invokeConverter Lambda関数を1回呼び出し、呼び出し履歴を記録する
+func (s *lambdaService) invokeConverter(ctx context.Context, pdeContent string, attempt int) (jsContent string, err error) {
+	// 1回の呼び出しごとにタイムアウトを設定
+	if s.config.Lambda.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.Lambda.Timeout)
+		defer cancel()
+	}
+
+	// CanvasID生成（一意な識別子）
+	canvasID := fmt.Sprintf("canvas_%d", time.Now().UnixNano())
+
+	// Lambda関数のパラメータを作成
+	requestPayload := PDEConversionRequest{
+		PDEContent: pdeContent,
+		CanvasID:   canvasID,
+	}
+
+	// JSONに変換
+	payload, err := json.Marshal(requestPayload)
+	if err != nil {
+		return "", fmt.Errorf("%w: リクエストのJSONエンコードに失敗しました: %v", errConversionFailed, err)
+	}
+
+	// Lambda関数を呼び出し
+	input := &lambda.InvokeInput{
+		FunctionName:   aws.String(s.config.Lambda.FunctionName),
+		Payload:        payload,
+		InvocationType: aws.String("RequestResponse"),    // 同期呼び出し
+		Qualifier:      aws.String(s.ConverterVersion()), // バージョンを固定
+	}
+
+	// Lambda呼び出し実行
+	started := time.Now()
+	responseBytes := 0
+	defer func() {
+		s.recordInvocation(attempt, time.Since(started), len(payload), responseBytes, err)
+	}()
+
+	output, err := s.lambdaClient.InvokeWithContext(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("Lambda関数の呼び出しに失敗しました: %v", err)
+	}
+	responseBytes = len(output.Payload)
+
+	// 関数の実行時エラー（タイムアウトやメモリ不足など）は一時的なものとして再試行する
+	if output.FunctionError != nil {
+		return "", fmt.Errorf("Lambda関数の実行でエラーが発生しました: %s", aws.StringValue(output.FunctionError))
+	}
+
+	// レスポンスをパース
+	var lambdaResponse PDEConversionResponse
+	if err := json.Unmarshal(output.Payload, &lambdaResponse); err != nil {
+		return "", fmt.Errorf("Lambda関数のレスポンスをパースできませんでした: %v", err)
+	}
+
+	// 処理結果を確認
+	if !lambdaResponse.Success {
+		return "", fmt.Errorf("%w: %s", errConversionFailed, lambdaResponse.Message)
+	}
+
+	// JSコンテンツを確認
+	if lambdaResponse.JSContent == "" {
+		return "", fmt.Errorf("%w: Lambda関数から空のJSコンテンツが返されました", errConversionFailed)
+	}
+
+	return lambdaResponse.JSContent, nil
+}
+
+// recordInvocation 呼び出し履歴を記録（記録の失敗は変換に影響させずログ出力のみ）
+func (s *lambdaService) recordInvocation(attempt int, duration time.Duration, payloadBytes, responseBytes int, err error) {
+	if s.invocationRepo == nil {
+		return
+	}
+
+	invocation := &models.InvocationLog{
+		FunctionName:  s.config.Lambda.FunctionName,
+		Version:       s.ConverterVersion(),
+		Attempt:       attempt,
+		DurationMs:    duration.Milliseconds(),
+		PayloadBytes:  payloadBytes,
+		ResponseBytes: responseBytes,
+		Success:       err == nil,
+	}
+	if err != nil {
+		invocation.Error = err.Error()
+	}
+
+	// 呼び出し元がキャンセルしても記録できるよう、リクエストのコンテキストは使わない
+	if err := s.invocationRepo.Create(context.Background(), invocation); err != nil {
+		log.Printf("Lambdaの呼び出し履歴の記録に失敗しました: %v", err)
+	}
+}
+
+// Ping Lambda関数を実行せずに呼び出しの権限と関数の存在を確認（DryRun）
+func (s *lambdaService) Ping(ctx context.Context) error {
+	_, err := s.lambdaClient.InvokeWithContext(ctx, &lambda.InvokeInput{
+		FunctionName:   aws.String(s.config.Lambda.FunctionName),
+		InvocationType: aws.String(lambda.InvocationTypeDryRun),
+		Qualifier:      aws.String(s.ConverterVersion()),
+	})
+	if err != nil {
+		return fmt.Errorf("Lambda関数を呼び出せません: %v", err)
+	}
+	return nil
+}
+
+// circuitBreakerLambdaService Lambdaが停止している間は変換を待たずに失敗させるLambdaServiceのラッパー
+type circuitBreakerLambdaService struct {
+	LambdaService
+	breaker *CircuitBreaker
+}
+
+// NewCircuitBreakerLambdaService LambdaServiceの変換をサーキットブレーカー経由で呼び出す
+// PDEコードの問題による変換の失敗はLambdaが応答したものとして扱う。Pingは遮断中も呼び出して復旧を確認できるようにする
+func NewCircuitBreakerLambdaService(lambdaService LambdaService, breaker *CircuitBreaker) LambdaService {
+	return &circuitBreakerLambdaService{
+		LambdaService: lambdaService,
+		breaker:       breaker,
+	}
+}
+
+// IsLambdaUnavailable Lambda自体の障害による失敗か（PDEコードの問題による失敗ではないか）を判定
+func IsLambdaUnavailable(err error) bool {
+	return err != nil && !errors.Is(err, errConversionFailed)
+}
+
+// ConvertPDEToJS 遮断していなければPDEをJavaScriptに変換するLambdaを呼び出す
+func (s *circuitBreakerLambdaService) ConvertPDEToJS(ctx context.Context, pdeContent string) (string, error) {
+	var jsContent string
+	err := s.breaker.Execute(ctx, func(ctx context.Context) error {
+		var err error
+		jsContent, err = s.LambdaService.ConvertPDEToJS(ctx, pdeContent)
+		return err
+	})
+	return jsContent, err
+}
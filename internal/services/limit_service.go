@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// PlanLimits 料金プランの上限（0は無制限）
+type PlanLimits struct {
+	MaxProjects         int   `json:"max_projects"` // オーナーとして作成できる個人のプロジェクト数
+	StorageBytes        int64 `json:"storage_bytes"`
+	ConversionsPerMonth int   `json:"conversions_per_month"`
+}
+
+// PlanUsage 料金プランの上限に対する使用状況
+type PlanUsage struct {
+	Projects             int64 `json:"projects"`
+	StorageBytes         int64 `json:"storage_bytes"`
+	ConversionsThisMonth int64 `json:"conversions_this_month"`
+}
+
+// PlanStatus ユーザーの料金プランと上限・使用状況
+type PlanStatus struct {
+	Plan         string               `json:"plan"` // free, pro, org
+	Limits       PlanLimits           `json:"limits"`
+	Usage        PlanUsage            `json:"usage"`
+	Subscription *models.Subscription `json:"subscription,omitempty"` // 解約済みの場合も最後の契約を返す
+}
+
+// LimitService 料金プランごとの上限に関するサービスインターフェース
+// プロジェクトの作成・ストレージ・PDE変換の各サービスが上限を確認する際に使用する
+type LimitService interface {
+	Plan(ctx context.Context, userID uint) (string, *PlanLimits, error)
+	Status(ctx context.Context, userID uint) (*PlanStatus, error)
+	CheckProjects(ctx context.Context, userID uint) error
+}
+
+// limitService LimitServiceの実装
+type limitService struct {
+	subscriptionRepo repository.SubscriptionRepository
+	projectRepo      repository.ProjectRepository
+	workRepo         repository.WorkRepository
+	conversionRepo   repository.ConversionRepository
+	config           *config.Config
+}
+
+// NewLimitService LimitServiceを作成
+func NewLimitService(
+	subscriptionRepo repository.SubscriptionRepository,
+	projectRepo repository.ProjectRepository,
+	workRepo repository.WorkRepository,
+	conversionRepo repository.ConversionRepository,
+	cfg *config.Config,
+) LimitService {
+	return &limitService{
+		subscriptionRepo: subscriptionRepo,
+		projectRepo:      projectRepo,
+		workRepo:         workRepo,
+		conversionRepo:   conversionRepo,
+		config:           cfg,
+	}
+}
+
+// Plan ユーザーの現在の料金プランと上限を取得
+func (s *limitService) Plan(ctx context.Context, userID uint) (string, *PlanLimits, error) {
+	plan, _, err := s.currentPlan(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	return plan, planLimits(s.config, plan), nil
+}
+
+// Status ユーザーの料金プランと上限・使用状況を取得
+func (s *limitService) Status(ctx context.Context, userID uint) (*PlanStatus, error) {
+	plan, subscription, err := s.currentPlan(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	status := &PlanStatus{
+		Plan:         plan,
+		Limits:       *planLimits(s.config, plan),
+		Subscription: subscription,
+	}
+
+	if status.Usage.Projects, err = s.projectRepo.CountOwned(ctx, userID); err != nil {
+		return nil, fmt.Errorf("プロジェクト数の取得に失敗しました: %v", err)
+	}
+	if status.Usage.StorageBytes, err = s.workRepo.StorageUsedByUser(ctx, userID, 0); err != nil {
+		return nil, fmt.Errorf("ストレージ使用量の取得に失敗しました: %v", err)
+	}
+	if status.Usage.ConversionsThisMonth, err = s.conversionRepo.CountSince(ctx, userID, monthStart(time.Now())); err != nil {
+		return nil, fmt.Errorf("変換回数の取得に失敗しました: %v", err)
+	}
+
+	return status, nil
+}
+
+// CheckProjects 個人のプロジェクトを新しく作成できるか確認（組織のプロジェクトは組織の上限で確認する）
+func (s *limitService) CheckProjects(ctx context.Context, userID uint) error {
+	plan, limits, err := s.Plan(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if limits.MaxProjects == 0 {
+		return nil
+	}
+
+	count, err := s.projectRepo.CountOwned(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("プロジェクト数の取得に失敗しました: %v", err)
+	}
+	if count >= int64(limits.MaxProjects) {
+		return fmt.Errorf("プロジェクト数が料金プラン（%s）の上限（%d件）に達しています", plan, limits.MaxProjects)
+	}
+	return nil
+}
+
+// currentPlan ユーザーの契約から現在の料金プランを判定（契約がない・有効でない場合は無料プラン）
+func (s *limitService) currentPlan(ctx context.Context, userID uint) (string, *models.Subscription, error) {
+	subscription, err := s.subscriptionRepo.FindByUser(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.PlanFree, nil, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("料金プランの取得に失敗しました: %v", err)
+	}
+	if !subscription.IsActive() {
+		return models.PlanFree, subscription, nil
+	}
+	return subscription.Plan, subscription, nil
+}
+
+// planLimits 料金プランの設定から上限を作成（0以下は無制限として0にする）
+func planLimits(cfg *config.Config, plan string) *PlanLimits {
+	var p config.PlanConfig
+	switch plan {
+	case models.PlanPro:
+		p = cfg.Billing.Pro
+	case models.PlanOrg:
+		p = cfg.Billing.Org
+	default:
+		p = cfg.Billing.Free
+	}
+
+	limits := &PlanLimits{}
+	if p.MaxProjects > 0 {
+		limits.MaxProjects = p.MaxProjects
+	}
+	if p.StorageQuotaMB > 0 {
+		limits.StorageBytes = int64(p.StorageQuotaMB) * 1024 * 1024
+	}
+	if p.ConversionsPerMonth > 0 {
+		limits.ConversionsPerMonth = p.ConversionsPerMonth
+	}
+	return limits
+}
+
+// monthStart tを含む月の初め
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"html"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// LinkPreviewService コメントに含まれるURLのリンクプレビューに関するサービスインターフェース
+type LinkPreviewService interface {
+	// Attach コメントに取得済みのリンクプレビューを設定（未取得・期限切れのものはバックグラウンドで取得）
+	Attach(ctx context.Context, comments []models.Comment)
+	// Refresh 本文に含まれるURLのリンクプレビューをバックグラウンドで取得
+	Refresh(content string)
+}
+
+// リンクプレビューの各項目の最大文字数
+const (
+	linkPreviewMaxTitle       = 300
+	linkPreviewMaxDescription = 1000
+	linkPreviewMaxSiteName    = 100
+	linkPreviewMaxURL         = 2048
+)
+
+var (
+	// コメント本文中のURL
+	linkPreviewURLPattern = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+	// HTMLのmetaタグとtitleタグ
+	linkPreviewMetaPattern  = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	linkPreviewAttrPattern  = regexp.MustCompile(`(?is)([a-z:_-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	linkPreviewTitlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	linkPreviewSpacePattern = regexp.MustCompile(`\s+`)
+)
+
+// linkPreviewService LinkPreviewServiceの実装
+type linkPreviewService struct {
+	linkPreviewRepo repository.LinkPreviewRepository
+	fetcher         URLFetcher
+	config          config.LinkPreviewConfig
+
+	mu       sync.Mutex
+	fetching map[string]bool // 取得中のURLのハッシュ
+}
+
+// NewLinkPreviewService LinkPreviewServiceを作成
+func NewLinkPreviewService(linkPreviewRepo repository.LinkPreviewRepository, fetcher URLFetcher, cfg *config.Config) LinkPreviewService {
+	return &linkPreviewService{
+		linkPreviewRepo: linkPreviewRepo,
+		fetcher:         fetcher,
+		config:          cfg.LinkPreview,
+		fetching:        map[string]bool{},
+	}
+}
+
+// Attach コメントに取得済みのリンクプレビューを設定
+func (s *linkPreviewService) Attach(ctx context.Context, comments []models.Comment) {
+	if len(s.config.AllowedHosts) == 0 {
+		return
+	}
+
+	// コメントごとのURLをまとめて取得
+	urls := make([][]string, len(comments))
+	hashes := []string{}
+	for i := range comments {
+		urls[i] = s.extractURLs(comments[i].Content)
+		for _, rawURL := range urls[i] {
+			hashes = append(hashes, linkPreviewHash(rawURL))
+		}
+	}
+	if len(hashes) == 0 {
+		return
+	}
+
+	previews, err := s.linkPreviewRepo.FindByHashes(ctx, hashes)
+	if err != nil {
+		log.Printf("リンクプレビューの取得に失敗しました: %v", err)
+		return
+	}
+	byHash := make(map[string]models.LinkPreview, len(previews))
+	for _, preview := range previews {
+		byHash[preview.URLHash] = preview
+	}
+
+	now := time.Now()
+	for i := range comments {
+		for _, rawURL := range urls[i] {
+			preview, ok := byHash[linkPreviewHash(rawURL)]
+			if !ok || now.Sub(preview.FetchedAt) > s.config.CacheTTL {
+				s.fetchInBackground(rawURL)
+			}
+			if ok && !preview.Failed {
+				comments[i].LinkPreviews = append(comments[i].LinkPreviews, preview)
+			}
+		}
+	}
+}
+
+// Refresh 本文に含まれるURLのリンクプレビューをバックグラウンドで取得
+func (s *linkPreviewService) Refresh(content string) {
+	if len(s.config.AllowedHosts) == 0 {
+		return
+	}
+
+	urls := s.extractURLs(content)
+	if len(urls) == 0 {
+		return
+	}
+
+	hashes := make([]string, len(urls))
+	for i, rawURL := range urls {
+		hashes[i] = linkPreviewHash(rawURL)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	previews, err := s.linkPreviewRepo.FindByHashes(ctx, hashes)
+	if err != nil {
+		log.Printf("リンクプレビューの取得に失敗しました: %v", err)
+		return
+	}
+	fetched := map[string]bool{}
+	for _, preview := range previews {
+		if time.Since(preview.FetchedAt) <= s.config.CacheTTL {
+			fetched[preview.URLHash] = true
+		}
+	}
+
+	for i, rawURL := range urls {
+		if !fetched[hashes[i]] {
+			s.fetchInBackground(rawURL)
+		}
+	}
+}
+
+// extractURLs 本文からプレビューを取得するURLを取り出す（許可されたホストのもののみ、重複を除く）
+func (s *linkPreviewService) extractURLs(content string) []string {
+	urls := []string{}
+	seen := map[string]bool{}
+	for _, match := range linkPreviewURLPattern.FindAllString(content, -1) {
+		// 文末の句読点や閉じ括弧はURLに含めない
+		match = strings.TrimRight(match, ".,;:!?)]}")
+		if len(match) > linkPreviewMaxURL || seen[match] {
+			continue
+		}
+		u, err := url.Parse(match)
+		if err != nil || s.fetcher.Validate(u, s.config.AllowedHosts) != nil {
+			continue
+		}
+		seen[match] = true
+		urls = append(urls, match)
+		if len(urls) >= s.config.MaxPerComment {
+			break
+		}
+	}
+	return urls
+}
+
+// fetchInBackground URLのリンクプレビューをバックグラウンドで取得して保存（同じURLを同時に取得しない）
+func (s *linkPreviewService) fetchInBackground(rawURL string) {
+	hash := linkPreviewHash(rawURL)
+	s.mu.Lock()
+	if s.fetching[hash] {
+		s.mu.Unlock()
+		return
+	}
+	s.fetching[hash] = true
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			delete(s.fetching, hash)
+			s.mu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout+5*time.Second)
+		defer cancel()
+
+		preview, err := s.fetch(ctx, rawURL)
+		if err != nil {
+			// 失敗した場合も記録し、キャッシュの期限まで再取得しない
+			log.Printf("リンクプレビューの取得に失敗しました (%s): %v", rawURL, err)
+			preview = &models.LinkPreview{URL: rawURL, Failed: true}
+		}
+		preview.URLHash = hash
+		preview.FetchedAt = time.Now()
+		if err := s.linkPreviewRepo.Save(ctx, preview); err != nil {
+			log.Printf("リンクプレビューの保存に失敗しました (%s): %v", rawURL, err)
+		}
+	}()
+}
+
+// fetch URLのHTMLを取得してOpenGraphのメタデータを読み取る（metaタグはheadにあるため、最大サイズを超える部分は読まない）
+func (s *linkPreviewService) fetch(ctx context.Context, rawURL string) (*models.LinkPreview, error) {
+	result, err := s.fetcher.Fetch(ctx, rawURL, FetchOptions{
+		AllowedHosts: s.config.AllowedHosts,
+		ContentTypes: []string{"text/html", "application/xhtml+xml"},
+		MaxBytes:     s.config.MaxBytes,
+		Timeout:      s.config.Timeout,
+		Truncate:     true,
+		Accept:       "text/html",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	preview := parseLinkPreview(string(result.Body), result.URL)
+	preview.URL = rawURL
+	if preview.Title == "" {
+		return nil, errors.New("タイトルが見つかりません")
+	}
+	return preview, nil
+}
+
+// parseLinkPreview HTMLからOpenGraphのメタデータを読み取る（og:titleがなければtitleタグを使う）
+func parseLinkPreview(body string, base *url.URL) *models.LinkPreview {
+	meta := map[string]string{}
+	for _, tag := range linkPreviewMetaPattern.FindAllString(body, -1) {
+		attrs := map[string]string{}
+		for _, attr := range linkPreviewAttrPattern.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(attr[1])] = attr[2] + attr[3] + attr[4]
+		}
+		key := strings.ToLower(attrs["property"])
+		if key == "" {
+			key = strings.ToLower(attrs["name"])
+		}
+		if _, ok := meta[key]; key != "" && !ok {
+			meta[key] = attrs["content"]
+		}
+	}
+
+	preview := &models.LinkPreview{
+		Title:       linkPreviewText(meta["og:title"], linkPreviewMaxTitle),
+		Description: linkPreviewText(firstNonEmpty(meta["og:description"], meta["description"]), linkPreviewMaxDescription),
+		SiteName:    linkPreviewText(meta["og:site_name"], linkPreviewMaxSiteName),
+	}
+	if preview.Title == "" {
+		if match := linkPreviewTitlePattern.FindStringSubmatch(body); match != nil {
+			preview.Title = linkPreviewText(match[1], linkPreviewMaxTitle)
+		}
+	}
+
+	// 画像のURLは絶対URLにし、http・httpsのもののみ使う
+	if image := strings.TrimSpace(html.UnescapeString(meta["og:image"])); image != "" {
+		if imageURL, err := base.Parse(image); err == nil && (imageURL.Scheme == "http" || imageURL.Scheme == "https") {
+			if resolved := imageURL.String(); len(resolved) <= linkPreviewMaxURL {
+				preview.ImageURL = resolved
+			}
+		}
+	}
+
+	return preview
+}
+
+// linkPreviewText HTMLのテキストを表示用に整える（エスケープを戻し、空白をまとめて最大文字数で切り詰める）
+func linkPreviewText(value string, max int) string {
+	value = html.UnescapeString(value)
+	value = strings.TrimSpace(linkPreviewSpacePattern.ReplaceAllString(value, " "))
+	if !utf8.ValidString(value) {
+		value = strings.ToValidUTF8(value, "")
+	}
+	if runes := []rune(value); len(runes) > max {
+		value = string(runes[:max])
+	}
+	return value
+}
+
+// firstNonEmpty 最初の空でない値を返す
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// linkPreviewHash URLのSHA-256
+func linkPreviewHash(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:])
+}
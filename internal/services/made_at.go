@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+)
+
+const (
+	// イベントの識別子の最大長
+	maxEventLength = 64
+	// 場所の名前の最大長
+	maxLocationNameLength = 255
+	// 地図に表示する作品の最大数
+	maxMapWorks = 1000
+	// 一覧に表示するイベントの最大数
+	maxEventSummaries = 100
+)
+
+// eventPattern イベントの識別子に使える文字（英小文字・数字・-・_、先頭は英小文字か数字）
+var eventPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// MadeAtInput 作品を制作したイベント・場所（全て省略した場合は解除する）
+type MadeAtInput struct {
+	Event        string   `json:"event"`
+	LocationName string   `json:"location_name"`
+	Latitude     *float64 `json:"latitude"`
+	Longitude    *float64 `json:"longitude"`
+}
+
+// WorkFeatureCollection 制作した場所の座標がある作品のGeoJSON（FeatureCollection）
+type WorkFeatureCollection struct {
+	Type     string        `json:"type"` // 常に FeatureCollection
+	Features []WorkFeature `json:"features"`
+}
+
+// WorkFeature 地図上の作品1件（GeoJSONのFeature）
+type WorkFeature struct {
+	Type       string            `json:"type"` // 常に Feature
+	ID         uint              `json:"id"`
+	Geometry   WorkPoint         `json:"geometry"`
+	Properties WorkFeatureFields `json:"properties"`
+}
+
+// WorkPoint 作品を制作した場所（GeoJSONのPoint、座標は経度・緯度の順）
+type WorkPoint struct {
+	Type        string     `json:"type"` // 常に Point
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// WorkFeatureFields 地図上の作品に表示する情報
+type WorkFeatureFields struct {
+	Title        string    `json:"title"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	Event        string    `json:"event,omitempty"`
+	LocationName string    `json:"location_name,omitempty"`
+	UserID       uint      `json:"user_id"`
+	Nickname     string    `json:"nickname"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// normalizeEvent イベントの識別子を小文字にして検証（空の場合はそのまま返す）
+func normalizeEvent(event string) (string, error) {
+	event = strings.ToLower(strings.TrimSpace(event))
+	if event == "" {
+		return "", nil
+	}
+	if len(event) > maxEventLength {
+		return "", fmt.Errorf("イベントは%d文字以内で指定してください", maxEventLength)
+	}
+	if !eventPattern.MatchString(event) {
+		return "", errors.New("イベントには英小文字・数字・-・_のみ使用できます")
+	}
+	return event, nil
+}
+
+// validateMadeAt 制作したイベント・場所を検証し、正規化した値を返す
+func validateMadeAt(input MadeAtInput) (MadeAtInput, error) {
+	event, err := normalizeEvent(input.Event)
+	if err != nil {
+		return MadeAtInput{}, err
+	}
+	input.Event = event
+
+	input.LocationName = strings.TrimSpace(input.LocationName)
+	if len([]rune(input.LocationName)) > maxLocationNameLength {
+		return MadeAtInput{}, fmt.Errorf("場所の名前は%d文字以内で指定してください", maxLocationNameLength)
+	}
+
+	if (input.Latitude == nil) != (input.Longitude == nil) {
+		return MadeAtInput{}, errors.New("緯度と経度は両方指定してください")
+	}
+	if input.Latitude != nil {
+		if *input.Latitude < -90 || *input.Latitude > 90 {
+			return MadeAtInput{}, errors.New("緯度は-90〜90で指定してください")
+		}
+		if *input.Longitude < -180 || *input.Longitude > 180 {
+			return MadeAtInput{}, errors.New("経度は-180〜180で指定してください")
+		}
+	}
+	return input, nil
+}
+
+// newWorkFeatureCollection 作品一覧をGeoJSONに変換（座標がない作品は含めない）
+func newWorkFeatureCollection(works []models.Work) *WorkFeatureCollection {
+	collection := &WorkFeatureCollection{Type: "FeatureCollection", Features: []WorkFeature{}}
+	for _, work := range works {
+		if work.Latitude == nil || work.Longitude == nil {
+			continue
+		}
+		collection.Features = append(collection.Features, WorkFeature{
+			Type: "Feature",
+			ID:   work.ID,
+			Geometry: WorkPoint{
+				Type:        "Point",
+				Coordinates: [2]float64{*work.Longitude, *work.Latitude},
+			},
+			Properties: WorkFeatureFields{
+				Title:        work.Title,
+				ThumbnailURL: work.ThumbnailURL,
+				Event:        work.Event,
+				LocationName: work.LocationName,
+				UserID:       work.UserID,
+				Nickname:     work.User.Nickname,
+				CreatedAt:    work.CreatedAt,
+			},
+		})
+	}
+	return collection
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+)
+
+// MailService メール送信を抽象化するインターフェース
+type MailService interface {
+	Send(ctx context.Context, to string, message *MailMessage) error
+}
+
+// NewMailService 設定に応じたMailServiceを作成
+func NewMailService(cfg *config.Config) (MailService, error) {
+	switch cfg.Mail.Provider {
+	case "", "log":
+		return &logMailService{}, nil
+	case "smtp":
+		if cfg.Mail.SMTPHost == "" {
+			return nil, fmt.Errorf("SMTP_HOSTが設定されていません")
+		}
+		return &smtpMailService{config: cfg.Mail}, nil
+	default:
+		return nil, fmt.Errorf("不明なメール送信方法です: %s", cfg.Mail.Provider)
+	}
+}
+
+// logMailService 送信せずにログに出力するMailServiceの実装（開発用）
+type logMailService struct{}
+
+// Send メールの内容（テキストの本文）をログに出力
+func (s *logMailService) Send(ctx context.Context, to string, message *MailMessage) error {
+	log.Printf("[MAIL] To: %s Subject: %s\n%s", to, message.Subject, message.Text)
+	return nil
+}
+
+// smtpMailService SMTPサーバーから送信するMailServiceの実装
+type smtpMailService struct {
+	config config.MailConfig
+}
+
+// Send SMTPでメールを送信（テキストとHTMLのmultipart/alternative）
+func (s *smtpMailService) Send(ctx context.Context, to string, message *MailMessage) error {
+	// ヘッダーの改行によるインジェクションを防ぐ
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(message.Subject, "\r\n") {
+		return fmt.Errorf("無効な宛先または件名です")
+	}
+
+	addr := s.config.SMTPHost + ":" + strconv.Itoa(s.config.SMTPPort)
+	var auth smtp.Auth
+	if s.config.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", s.config.SMTPUsername, s.config.SMTPPassword, s.config.SMTPHost)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain", message.Text},
+		{"text/html", message.HTML},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=UTF-8"},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return fmt.Errorf("メールの作成に失敗しました: %v", err)
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return fmt.Errorf("メールの作成に失敗しました: %v", err)
+		}
+		if err := qp.Close(); err != nil {
+			return fmt.Errorf("メールの作成に失敗しました: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("メールの作成に失敗しました: %v", err)
+	}
+
+	header := strings.Join([]string{
+		"From: " + s.config.From,
+		"To: " + to,
+		"Subject: " + mime.BEncoding.Encode("UTF-8", message.Subject),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + writer.Boundary(),
+		"",
+		"",
+	}, "\r\n")
+
+	if err := smtp.SendMail(addr, auth, s.config.From, []string{to}, append([]byte(header), body.Bytes()...)); err != nil {
+		return fmt.Errorf("メールの送信に失敗しました: %v", err)
+	}
+	return nil
+}
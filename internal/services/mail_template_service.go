@@ -0,0 +1,397 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+)
+
+// メールのテンプレート名
+const (
+	MailTemplateEmailChange        = "email_change"        // メールアドレス変更の確認
+	MailTemplateEmailChangeNotice  = "email_change_notice" // メールアドレス変更のリクエストの通知（変更前のアドレス宛て）
+	MailTemplateWaitlistInvitation = "waitlist_invitation" // 順番待ちの承認と招待コード
+	MailTemplateProjectInvitation  = "project_invitation"  // 名簿から仮登録したユーザーのプロジェクトへの招待
+)
+
+// デフォルトの言語（指定された言語のテンプレートがない場合に使用）
+const defaultMailLanguage = "ja"
+
+// MailMessage 送信するメール（本文はテキストとHTMLの両方）
+type MailMessage struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// MailTemplateInfo テンプレートの一覧の項目
+type MailTemplateInfo struct {
+	Name      string   `json:"name"`
+	Languages []string `json:"languages"`
+}
+
+// MailTemplateService メールのテンプレートに関するサービスインターフェース
+type MailTemplateService interface {
+	Render(name string, data interface{}) (*MailMessage, error)
+	Preview(name, language string) (*MailMessage, error)
+	List() []MailTemplateInfo
+}
+
+// emailChangeMail メールアドレス変更の確認・通知のテンプレートに渡す値
+type emailChangeMail struct {
+	Name      string
+	NewEmail  string
+	Link      string
+	ExpiresAt string
+}
+
+// waitlistInvitationMail 順番待ちの招待のテンプレートに渡す値
+type waitlistInvitationMail struct {
+	Name      string
+	Code      string
+	Link      string
+	ExpiresAt string // 空の場合は無期限
+}
+
+// projectInvitationMail プロジェクトへの招待のテンプレートに渡す値
+type projectInvitationMail struct {
+	Name      string
+	Inviter   string
+	Project   string
+	Link      string
+	ExpiresAt string // 空の場合は無期限
+}
+
+// mailTemplateSource テンプレートの定義（本文は共通のレイアウトの "content" として埋め込む）
+type mailTemplateSource struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// mailLayouts 言語ごとの共通のレイアウト
+var mailLayouts = map[string]mailTemplateSource{
+	"ja": {
+		Text: `{{template "content" .}}
+--
+SketchShifter
+{{siteURL}}
+このメールは送信専用です。
+`,
+		HTML: `<!DOCTYPE html>
+<html lang="ja">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"></head>
+<body style="margin:0;padding:24px;background:#f4f4f5;font-family:sans-serif;color:#222;">
+<div style="max-width:560px;margin:0 auto;padding:24px;background:#fff;border-radius:8px;line-height:1.7;">
+<p style="margin:0 0 16px;font-weight:bold;font-size:18px;">SketchShifter</p>
+{{template "content" .}}
+<hr style="margin:24px 0 12px;border:none;border-top:1px solid #ddd;">
+<p style="margin:0;font-size:12px;color:#777;">このメールは送信専用です。<br><a href="{{siteURL}}" style="color:#777;">{{siteURL}}</a></p>
+</div>
+</body>
+</html>
+`,
+	},
+	"en": {
+		Text: `{{template "content" .}}
+--
+SketchShifter
+{{siteURL}}
+This is an automated message. Please do not reply.
+`,
+		HTML: `<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"></head>
+<body style="margin:0;padding:24px;background:#f4f4f5;font-family:sans-serif;color:#222;">
+<div style="max-width:560px;margin:0 auto;padding:24px;background:#fff;border-radius:8px;line-height:1.7;">
+<p style="margin:0 0 16px;font-weight:bold;font-size:18px;">SketchShifter</p>
+{{template "content" .}}
+<hr style="margin:24px 0 12px;border:none;border-top:1px solid #ddd;">
+<p style="margin:0;font-size:12px;color:#777;">This is an automated message. Please do not reply.<br><a href="{{siteURL}}" style="color:#777;">{{siteURL}}</a></p>
+</div>
+</body>
+</html>
+`,
+	},
+}
+
+// mailButton HTMLのメールのリンクボタン
+const mailButton = `<p style="margin:24px 0;"><a href="{{.Link}}" style="display:inline-block;padding:10px 20px;background:#2563eb;color:#fff;border-radius:6px;text-decoration:none;">`
+
+// mailTemplateSources テンプレート名・言語ごとの定義
+var mailTemplateSources = map[string]map[string]mailTemplateSource{
+	MailTemplateEmailChange: {
+		"ja": {
+			Subject: `メールアドレスの確認`,
+			Text: `{{.Name}} さん
+
+メールアドレスを {{.NewEmail}} に変更するには、以下のリンクを開いてください。
+
+{{.Link}}
+
+このリンクの有効期限は {{.ExpiresAt}} です。
+心当たりがない場合は、このメールを無視してください。`,
+			HTML: `<p>{{.Name}} さん</p>
+<p>メールアドレスを <strong>{{.NewEmail}}</strong> に変更するには、以下のボタンを押してください。</p>
+` + mailButton + `メールアドレスを確認する</a></p>
+<p>このリンクの有効期限は {{.ExpiresAt}} です。<br>心当たりがない場合は、このメールを無視してください。</p>`,
+		},
+		"en": {
+			Subject: `Confirm your email address`,
+			Text: `Hi {{.Name}},
+
+To change your email address to {{.NewEmail}}, open the link below.
+
+{{.Link}}
+
+This link expires at {{.ExpiresAt}}.
+If you did not request this, you can ignore this email.`,
+			HTML: `<p>Hi {{.Name}},</p>
+<p>To change your email address to <strong>{{.NewEmail}}</strong>, click the button below.</p>
+` + mailButton + `Confirm email address</a></p>
+<p>This link expires at {{.ExpiresAt}}.<br>If you did not request this, you can ignore this email.</p>`,
+		},
+	},
+	MailTemplateEmailChangeNotice: {
+		"ja": {
+			Subject: `メールアドレスの変更のリクエスト`,
+			Text: `{{.Name}} さん
+
+メールアドレスを {{.NewEmail}} に変更するリクエストを受け付けました。
+心当たりがない場合は、パスワードを変更してください。`,
+			HTML: `<p>{{.Name}} さん</p>
+<p>メールアドレスを <strong>{{.NewEmail}}</strong> に変更するリクエストを受け付けました。</p>
+<p>心当たりがない場合は、パスワードを変更してください。</p>`,
+		},
+		"en": {
+			Subject: `Email address change requested`,
+			Text: `Hi {{.Name}},
+
+We received a request to change your email address to {{.NewEmail}}.
+If you did not request this, please change your password.`,
+			HTML: `<p>Hi {{.Name}},</p>
+<p>We received a request to change your email address to <strong>{{.NewEmail}}</strong>.</p>
+<p>If you did not request this, please change your password.</p>`,
+		},
+	},
+	MailTemplateWaitlistInvitation: {
+		"ja": {
+			Subject: `SketchShifterへの招待`,
+			Text: `{{.Name}} さん
+
+SketchShifterの順番待ちにご登録いただきありがとうございます。
+以下のリンクから登録できます（招待コード: {{.Code}}）。
+
+{{.Link}}
+{{- if .ExpiresAt}}
+
+この招待コードの有効期限は {{.ExpiresAt}} です。
+{{- end}}`,
+			HTML: `<p>{{.Name}} さん</p>
+<p>SketchShifterの順番待ちにご登録いただきありがとうございます。<br>以下のボタンから登録できます（招待コード: <strong>{{.Code}}</strong>）。</p>
+` + mailButton + `登録する</a></p>
+{{- if .ExpiresAt}}
+<p>この招待コードの有効期限は {{.ExpiresAt}} です。</p>
+{{- end}}`,
+		},
+		"en": {
+			Subject: `Your invitation to SketchShifter`,
+			Text: `Hi {{.Name}},
+
+Thank you for joining the SketchShifter waitlist.
+You can now sign up with the link below (invite code: {{.Code}}).
+
+{{.Link}}
+{{- if .ExpiresAt}}
+
+This invite code expires at {{.ExpiresAt}}.
+{{- end}}`,
+			HTML: `<p>Hi {{.Name}},</p>
+<p>Thank you for joining the SketchShifter waitlist.<br>You can now sign up with the button below (invite code: <strong>{{.Code}}</strong>).</p>
+` + mailButton + `Sign up</a></p>
+{{- if .ExpiresAt}}
+<p>This invite code expires at {{.ExpiresAt}}.</p>
+{{- end}}`,
+		},
+	},
+	MailTemplateProjectInvitation: {
+		"ja": {
+			Subject: `「{{.Project}}」に招待されました`,
+			Text: `{{.Name}} さん
+
+{{.Inviter}} さんがあなたをプロジェクト「{{.Project}}」に招待しました。
+以下のリンクからパスワードを設定して参加してください。
+
+{{.Link}}
+{{- if .ExpiresAt}}
+
+このリンクの有効期限は {{.ExpiresAt}} です。
+{{- end}}`,
+			HTML: `<p>{{.Name}} さん</p>
+<p>{{.Inviter}} さんがあなたをプロジェクト「<strong>{{.Project}}</strong>」に招待しました。<br>以下のボタンからパスワードを設定して参加してください。</p>
+` + mailButton + `参加する</a></p>
+{{- if .ExpiresAt}}
+<p>このリンクの有効期限は {{.ExpiresAt}} です。</p>
+{{- end}}`,
+		},
+		"en": {
+			Subject: `You've been invited to "{{.Project}}"`,
+			Text: `Hi {{.Name}},
+
+{{.Inviter}} has invited you to the project "{{.Project}}".
+Set your password and join with the link below.
+
+{{.Link}}
+{{- if .ExpiresAt}}
+
+This link expires at {{.ExpiresAt}}.
+{{- end}}`,
+			HTML: `<p>Hi {{.Name}},</p>
+<p>{{.Inviter}} has invited you to the project "<strong>{{.Project}}</strong>".<br>Set your password and join with the button below.</p>
+` + mailButton + `Join project</a></p>
+{{- if .ExpiresAt}}
+<p>This link expires at {{.ExpiresAt}}.</p>
+{{- end}}`,
+		},
+	},
+}
+
+// mailTemplateSamples プレビューに使用するテンプレートごとの値（リンクはフロントエンドのURLを起点にする）
+var mailTemplateSamples = map[string]func(siteURL string) interface{}{
+	MailTemplateEmailChange: func(siteURL string) interface{} {
+		return emailChangeMail{Name: "山田 太郎", NewEmail: "new@example.com", Link: siteURL + "/email/confirm?token=preview", ExpiresAt: "2025-01-01 12:00"}
+	},
+	MailTemplateEmailChangeNotice: func(siteURL string) interface{} {
+		return emailChangeMail{Name: "山田 太郎", NewEmail: "new@example.com"}
+	},
+	MailTemplateWaitlistInvitation: func(siteURL string) interface{} {
+		return waitlistInvitationMail{Name: "山田 太郎", Code: "PREVIEW1", Link: siteURL + "/register?invite_code=PREVIEW1", ExpiresAt: "2025-01-01 12:00"}
+	},
+	MailTemplateProjectInvitation: func(siteURL string) interface{} {
+		return projectInvitationMail{Name: "山田 太郎", Inviter: "先生", Project: "サンプルプロジェクト", Link: siteURL + "/invitations/accept?token=preview", ExpiresAt: "2025-01-01 12:00"}
+	},
+}
+
+// compiledMailTemplate 読み込み済みのテンプレート
+type compiledMailTemplate struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *template.Template
+}
+
+// mailTemplateService MailTemplateServiceの実装
+type mailTemplateService struct {
+	templates map[string]map[string]*compiledMailTemplate // テンプレート名・言語ごと
+	config    *config.Config
+}
+
+// NewMailTemplateService MailTemplateServiceを作成（テンプレートの誤りは起動時にエラーにする）
+func NewMailTemplateService(cfg *config.Config) (MailTemplateService, error) {
+	if _, ok := mailLayouts[cfg.Mail.Language]; !ok {
+		return nil, fmt.Errorf("メールの言語 %s には対応していません", cfg.Mail.Language)
+	}
+
+	siteURL := strings.TrimSuffix(cfg.Mail.FrontendURL, "/")
+	funcs := map[string]interface{}{
+		"siteURL": func() string { return siteURL },
+	}
+
+	s := &mailTemplateService{
+		templates: make(map[string]map[string]*compiledMailTemplate),
+		config:    cfg,
+	}
+	for name, languages := range mailTemplateSources {
+		s.templates[name] = make(map[string]*compiledMailTemplate)
+		for language, source := range languages {
+			layout := mailLayouts[language]
+			compiled := &compiledMailTemplate{}
+			var err error
+			if compiled.subject, err = texttemplate.New("subject").Parse(source.Subject); err != nil {
+				return nil, fmt.Errorf("メールのテンプレート %s（%s）の件名が正しくありません: %v", name, language, err)
+			}
+			if compiled.text, err = texttemplate.New("layout").Funcs(funcs).Parse(layout.Text); err == nil {
+				_, err = compiled.text.New("content").Parse(source.Text)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("メールのテンプレート %s（%s）のテキストが正しくありません: %v", name, language, err)
+			}
+			if compiled.html, err = template.New("layout").Funcs(funcs).Parse(layout.HTML); err == nil {
+				_, err = compiled.html.New("content").Parse(source.HTML)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("メールのテンプレート %s（%s）のHTMLが正しくありません: %v", name, language, err)
+			}
+			s.templates[name][language] = compiled
+		}
+	}
+	return s, nil
+}
+
+// Render 設定された言語（MAIL_LANGUAGE）でメールを作成
+func (s *mailTemplateService) Render(name string, data interface{}) (*MailMessage, error) {
+	return s.render(name, s.config.Mail.Language, data)
+}
+
+// Preview サンプルの値でメールを作成（管理者の確認用）
+func (s *mailTemplateService) Preview(name, language string) (*MailMessage, error) {
+	sample, ok := mailTemplateSamples[name]
+	if !ok {
+		return nil, fmt.Errorf("メールのテンプレート %s が見つかりません", name)
+	}
+	if language == "" {
+		language = s.config.Mail.Language
+	}
+	if _, ok := s.templates[name][language]; !ok {
+		return nil, fmt.Errorf("メールのテンプレート %s の言語 %s が見つかりません", name, language)
+	}
+	return s.render(name, language, sample(strings.TrimSuffix(s.config.Mail.FrontendURL, "/")))
+}
+
+// List テンプレートと対応する言語の一覧
+func (s *mailTemplateService) List() []MailTemplateInfo {
+	list := make([]MailTemplateInfo, 0, len(s.templates))
+	for name, languages := range s.templates {
+		info := MailTemplateInfo{Name: name}
+		for language := range languages {
+			info.Languages = append(info.Languages, language)
+		}
+		sort.Strings(info.Languages)
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// render テンプレートからメールを作成（指定された言語がない場合はデフォルトの言語）
+func (s *mailTemplateService) render(name, language string, data interface{}) (*MailMessage, error) {
+	languages, ok := s.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("メールのテンプレート %s が見つかりません", name)
+	}
+	compiled, ok := languages[language]
+	if !ok {
+		compiled = languages[defaultMailLanguage]
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := compiled.subject.Execute(&subject, data); err != nil {
+		return nil, fmt.Errorf("メールの作成に失敗しました: %v", err)
+	}
+	if err := compiled.text.ExecuteTemplate(&text, "layout", data); err != nil {
+		return nil, fmt.Errorf("メールの作成に失敗しました: %v", err)
+	}
+	if err := compiled.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return nil, fmt.Errorf("メールの作成に失敗しました: %v", err)
+	}
+
+	return &MailMessage{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
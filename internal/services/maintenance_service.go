@@ -0,0 +1,76 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+)
+
+// デフォルトのメンテナンスメッセージ
+const defaultMaintenanceMessage = "現在メンテナンス中です。しばらくしてから再度お試しください"
+
+// MaintenanceStatus メンテナンスモードの状態
+type MaintenanceStatus struct {
+	Enabled   bool       `json:"enabled"`
+	Message   string     `json:"message"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"` // 終了予定時刻（目安）
+}
+
+// MaintenanceService メンテナンスモードを管理するサービスインターフェース
+type MaintenanceService interface {
+	Status() MaintenanceStatus
+	Enable(message string, endsAt *time.Time) MaintenanceStatus
+	Disable() MaintenanceStatus
+}
+
+// maintenanceService MaintenanceServiceの実装（プロセス内で状態を保持する）
+type maintenanceService struct {
+	mu     sync.RWMutex
+	status MaintenanceStatus
+}
+
+// NewMaintenanceService MaintenanceServiceを作成
+func NewMaintenanceService(cfg *config.Config) MaintenanceService {
+	s := &maintenanceService{}
+	if cfg.Maintenance.Enabled {
+		s.Enable(cfg.Maintenance.Message, nil)
+	}
+	return s
+}
+
+// Status 現在の状態を取得
+func (s *maintenanceService) Status() MaintenanceStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// Enable メンテナンスモードを開始
+func (s *maintenanceService) Enable(message string, endsAt *time.Time) MaintenanceStatus {
+	if message == "" {
+		message = defaultMaintenanceMessage
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if !s.status.Enabled {
+		s.status.StartedAt = &now
+	}
+	s.status.Enabled = true
+	s.status.Message = message
+	s.status.EndsAt = endsAt
+	return s.status
+}
+
+// Disable メンテナンスモードを終了
+func (s *maintenanceService) Disable() MaintenanceStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status = MaintenanceStatus{}
+	return s.status
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// メッセージ本文の最大文字数
+const maxMessageLength = 2000
+
+// MessageService ダイレクトメッセージに関するサービスインターフェース
+type MessageService interface {
+	StartConversation(ctx context.Context, userID, recipientID uint, content string) (*models.Conversation, *models.Message, error)
+	SendMessage(ctx context.Context, conversationID, userID uint, content string) (*models.Message, error)
+	ListConversations(ctx context.Context, userID uint, page, limit int) ([]models.Conversation, int64, int, error)
+	ListMessages(ctx context.Context, conversationID, userID uint, page, limit int) ([]models.Message, int64, int, error)
+	UnreadCount(ctx context.Context, userID uint) (int64, error)
+}
+
+// messageService MessageServiceの実装
+type messageService struct {
+	messageRepo         repository.MessageRepository
+	projectRepo         repository.ProjectRepository
+	userRepo            repository.UserRepository
+	notificationService NotificationService
+	ageGate             AgeGateService
+}
+
+// NewMessageService MessageServiceを作成
+func NewMessageService(
+	messageRepo repository.MessageRepository,
+	projectRepo repository.ProjectRepository,
+	userRepo repository.UserRepository,
+	notificationService NotificationService,
+	ageGate AgeGateService,
+) MessageService {
+	return &messageService{
+		messageRepo:         messageRepo,
+		projectRepo:         projectRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		ageGate:             ageGate,
+	}
+}
+
+// validateContent メッセージ本文のバリデーション
+func validateContent(content string) error {
+	if strings.TrimSpace(content) == "" {
+		return errors.New("メッセージ内容は必須です")
+	}
+	if utf8.RuneCountInString(content) > maxMessageLength {
+		return fmt.Errorf("メッセージは%d文字以内で入力してください", maxMessageLength)
+	}
+	return nil
+}
+
+// StartConversation 相手との会話を開始（既存の会話があれば再利用）し、最初のメッセージを送信
+func (s *messageService) StartConversation(ctx context.Context, userID, recipientID uint, content string) (*models.Conversation, *models.Message, error) {
+	if userID == recipientID {
+		return nil, nil, errors.New("自分自身にメッセージを送信することはできません")
+	}
+
+	if err := validateContent(content); err != nil {
+		return nil, nil, err
+	}
+
+	// 相手が存在するか確認
+	if _, err := s.userRepo.FindByID(ctx, recipientID); err != nil {
+		return nil, nil, errors.New("ユーザーが見つかりません")
+	}
+
+	// 同じプロジェクトのメンバー同士か確認
+	shares, err := s.projectRepo.SharesProject(ctx, userID, recipientID)
+	if err != nil || !shares {
+		return nil, nil, errors.New("このユーザーにメッセージを送信する権限がありません（同じプロジェクトのメンバーのみ送信できます）")
+	}
+
+	// 未成年のユーザーとは見守りが有効なプロジェクトのメンバー同士でのみやり取りできる
+	if err := s.ageGate.CheckMessaging(ctx, userID, recipientID); err != nil {
+		return nil, nil, err
+	}
+
+	// 既存の会話を検索し、なければ作成
+	conversation, err := s.messageRepo.FindConversationBetween(ctx, userID, recipientID)
+	if err != nil {
+		conversation, err = s.messageRepo.CreateConversation(ctx, []uint{userID, recipientID})
+		if err != nil {
+			return nil, nil, fmt.Errorf("会話の作成に失敗しました: %v", err)
+		}
+	}
+
+	message, err := s.SendMessage(ctx, conversation.ID, userID, content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return conversation, message, nil
+}
+
+// SendMessage 会話にメッセージを送信
+func (s *messageService) SendMessage(ctx context.Context, conversationID, userID uint, content string) (*models.Message, error) {
+	if err := validateContent(content); err != nil {
+		return nil, err
+	}
+
+	// 会話を取得
+	conversation, err := s.messageRepo.FindConversationByID(ctx, conversationID)
+	if err != nil {
+		return nil, errors.New("会話が見つかりません")
+	}
+
+	// 参加者か確認し、通知先を収集
+	isParticipant := false
+	var recipientIDs []uint
+	for _, participant := range conversation.Participants {
+		if participant.UserID == userID {
+			isParticipant = true
+			continue
+		}
+		recipientIDs = append(recipientIDs, participant.UserID)
+	}
+	if !isParticipant {
+		return nil, errors.New("この会話にメッセージを送信する権限がありません")
+	}
+
+	// 現在も同じプロジェクトに所属しているか確認
+	for _, recipientID := range recipientIDs {
+		shares, err := s.projectRepo.SharesProject(ctx, userID, recipientID)
+		if err != nil || !shares {
+			return nil, errors.New("このユーザーにメッセージを送信する権限がありません（同じプロジェクトのメンバーのみ送信できます）")
+		}
+		if err := s.ageGate.CheckMessaging(ctx, userID, recipientID); err != nil {
+			return nil, err
+		}
+	}
+
+	// メッセージを保存
+	message := &models.Message{
+		ConversationID: conversationID,
+		SenderID:       userID,
+		Content:        content,
+	}
+	if err := s.messageRepo.CreateMessage(ctx, message); err != nil {
+		return nil, fmt.Errorf("メッセージの送信に失敗しました: %v", err)
+	}
+
+	// 相手に通知
+	link := fmt.Sprintf("/messages/%d", conversationID)
+	if err := s.notificationService.Notify(ctx, recipientIDs, models.NotificationTypeMessage, "新しいメッセージが届きました", content, link); err != nil {
+		log.Printf("メッセージ通知の作成に失敗しました (ConversationID=%d): %v", conversationID, err)
+	}
+
+	return message, nil
+}
+
+// ListConversations ユーザーの会話一覧を取得
+func (s *messageService) ListConversations(ctx context.Context, userID uint, page, limit int) ([]models.Conversation, int64, int, error) {
+	conversations, total, err := s.messageRepo.ListConversations(ctx, userID, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 最新メッセージと未読数を設定
+	for i := range conversations {
+		if last, err := s.messageRepo.GetLastMessage(ctx, conversations[i].ID); err == nil {
+			conversations[i].LastMessage = last
+		}
+		if unread, err := s.messageRepo.CountUnread(ctx, conversations[i].ID, userID); err == nil {
+			conversations[i].UnreadCount = unread
+		}
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return conversations, total, pages, nil
+}
+
+// ListMessages 会話のメッセージ一覧を取得し、既読にする
+func (s *messageService) ListMessages(ctx context.Context, conversationID, userID uint, page, limit int) ([]models.Message, int64, int, error) {
+	// 会話が存在するか確認
+	if _, err := s.messageRepo.FindConversationByID(ctx, conversationID); err != nil {
+		return nil, 0, 0, errors.New("会話が見つかりません")
+	}
+
+	// 参加者か確認
+	isParticipant, err := s.messageRepo.IsParticipant(ctx, conversationID, userID)
+	if err != nil || !isParticipant {
+		return nil, 0, 0, errors.New("この会話を閲覧する権限がありません")
+	}
+
+	messages, total, err := s.messageRepo.ListMessages(ctx, conversationID, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 既読にする
+	if err := s.messageRepo.MarkAsRead(ctx, conversationID, userID); err != nil {
+		log.Printf("既読の更新に失敗しました (ConversationID=%d): %v", conversationID, err)
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return messages, total, pages, nil
+}
+
+// UnreadCount ユーザーの未読メッセージ総数を取得
+func (s *messageService) UnreadCount(ctx context.Context, userID uint) (int64, error) {
+	return s.messageRepo.CountUnreadTotal(ctx, userID)
+}
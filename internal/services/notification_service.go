@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// NotificationService 通知に関するサービスインターフェース
+type NotificationService interface {
+	Notify(ctx context.Context, userIDs []uint, notificationType, title, message, link string) error
+	List(ctx context.Context, userID uint, page, limit int, unreadOnly bool) ([]models.Notification, int64, int, error)
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	MarkAsRead(ctx context.Context, id, userID uint) error
+	MarkAllAsRead(ctx context.Context, userID uint) error
+}
+
+// notificationService NotificationServiceの実装
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewNotificationService NotificationServiceを作成
+func NewNotificationService(notificationRepo repository.NotificationRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+	}
+}
+
+// Notify 指定したユーザーに通知を送信
+func (s *notificationService) Notify(ctx context.Context, userIDs []uint, notificationType, title, message, link string) error {
+	// 重複したユーザーを除外
+	seen := make(map[uint]bool)
+	notifications := make([]models.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		notifications = append(notifications, models.Notification{
+			UserID:  userID,
+			Type:    notificationType,
+			Title:   title,
+			Message: message,
+			Link:    link,
+		})
+	}
+
+	return s.notificationRepo.CreateBatch(ctx, notifications)
+}
+
+// List ユーザーの通知一覧を取得
+func (s *notificationService) List(ctx context.Context, userID uint, page, limit int, unreadOnly bool) ([]models.Notification, int64, int, error) {
+	notifications, total, err := s.notificationRepo.ListByUser(ctx, userID, page, limit, unreadOnly)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return notifications, total, pages, nil
+}
+
+// CountUnread 未読の通知数を取得
+func (s *notificationService) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	return s.notificationRepo.CountUnread(ctx, userID)
+}
+
+// MarkAsRead 通知を既読にする
+func (s *notificationService) MarkAsRead(ctx context.Context, id, userID uint) error {
+	if err := s.notificationRepo.MarkAsRead(ctx, id, userID); err != nil {
+		return errors.New("通知が見つかりません")
+	}
+	return nil
+}
+
+// MarkAllAsRead ユーザーの通知をすべて既読にする
+func (s *notificationService) MarkAllAsRead(ctx context.Context, userID uint) error {
+	return s.notificationRepo.MarkAllAsRead(ctx, userID)
+}
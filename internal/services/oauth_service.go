@@ -0,0 +1,437 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrGithubLoginDisabled GitHubでのログインが設定されていない
+var ErrGithubLoginDisabled = errors.New("GitHubでのログインは利用できません")
+
+// GitHubのAPIの呼び出しのタイムアウト
+const githubTimeout = 10 * time.Second
+
+// GithubStateExpiry 認可画面に移動してから戻ってくるまでのstateの有効期限
+const GithubStateExpiry = 10 * time.Minute
+
+// OAuthService 外部サービス（GitHub）のアカウントでのログインと連携に関するサービスインターフェース
+type OAuthService interface {
+	GithubAuthorizeURL() (string, string, error)
+	SignGithubState(state string) string
+	VerifyGithubState(signed, state string) error
+	LoginWithGithub(ctx context.Context, code, inviteCode string) (*models.User, string, error)
+	LinkGithub(ctx context.Context, userID uint, code string) (*models.OAuthProvider, error)
+	ListProviders(ctx context.Context, userID uint) ([]models.OAuthProvider, error)
+	Unlink(ctx context.Context, userID uint, provider string) error
+}
+
+// githubProfile GitHubのユーザー情報
+type githubProfile struct {
+	ID     string
+	Login  string
+	Name   string
+	Emails []string // 確認済みのメールアドレス（プライマリを先頭にする）
+}
+
+// oauthService OAuthServiceの実装
+type oauthService struct {
+	oauthRepo           repository.OAuthProviderRepository
+	userRepo            repository.UserRepository
+	authService         AuthService
+	registrationService RegistrationService
+	client              *http.Client
+	config              *config.Config
+}
+
+// NewOAuthService OAuthServiceを作成
+func NewOAuthService(
+	oauthRepo repository.OAuthProviderRepository,
+	userRepo repository.UserRepository,
+	authService AuthService,
+	registrationService RegistrationService,
+	httpClients HTTPClientFactory,
+	cfg *config.Config,
+) OAuthService {
+	return &oauthService{
+		oauthRepo:           oauthRepo,
+		userRepo:            userRepo,
+		authService:         authService,
+		registrationService: registrationService,
+		client:              httpClients.Client("github", githubTimeout),
+		config:              cfg,
+	}
+}
+
+// GithubAuthorizeURL GitHubの認可画面のURLとstateを作成
+// stateはSignGithubStateで署名してブラウザのCookieに保存し、認可後にVerifyGithubStateで確認する
+func (s *oauthService) GithubAuthorizeURL() (string, string, error) {
+	if s.config.Auth.GithubClientID == "" {
+		return "", "", ErrGithubLoginDisabled
+	}
+
+	state := utils.GenerateRandomString(32)
+	query := url.Values{
+		"client_id": {s.config.Auth.GithubClientID},
+		"scope":     {"read:user user:email"},
+		"state":     {state},
+	}
+	if s.config.Auth.GithubRedirectURL != "" {
+		query.Set("redirect_uri", s.config.Auth.GithubRedirectURL)
+	}
+	return strings.TrimSuffix(s.config.Auth.GithubOAuthURL, "/") + "/login/oauth/authorize?" + query.Encode(), state, nil
+}
+
+// SignGithubState stateに有効期限と署名を付ける（state.有効期限.署名）
+func (s *oauthService) SignGithubState(state string) string {
+	expires := strconv.FormatInt(time.Now().Add(GithubStateExpiry).Unix(), 10)
+	return state + "." + expires + "." + s.githubStateSignature(state, expires)
+}
+
+// VerifyGithubState 認可後に戻ってきたstateが、このブラウザに発行した有効期限内のstateと一致するか確認
+func (s *oauthService) VerifyGithubState(signed, state string) error {
+	parts := strings.Split(signed, ".")
+	if state == "" || len(parts) != 3 {
+		return errors.New("GitHubの認可の状態が一致しません。もう一度ログインしてください")
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(s.githubStateSignature(parts[0], parts[1]))) ||
+		!hmac.Equal([]byte(parts[0]), []byte(state)) {
+		return errors.New("GitHubの認可の状態が一致しません。もう一度ログインしてください")
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return errors.New("GitHubの認可の有効期限が切れました。もう一度ログインしてください")
+	}
+	return nil
+}
+
+// githubStateSignature stateと有効期限に対する署名
+func (s *oauthService) githubStateSignature(state, expires string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Auth.JWTSecret))
+	fmt.Fprintf(mac, "github-state:%s:%s", state, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// LoginWithGithub GitHubの認可コードでログイン
+// 連携済みのアカウントはそのユーザー、GitHubの確認済みメールアドレスと一致するユーザーがいれば連携してそのユーザー、
+// いなければ新しいユーザーを作成する（招待制の場合は招待コードが必要）
+func (s *oauthService) LoginWithGithub(ctx context.Context, code, inviteCode string) (*models.User, string, error) {
+	profile, err := s.fetchGithubProfile(ctx, code)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := s.findOrCreateGithubUser(ctx, profile, inviteCode)
+	if err != nil {
+		return nil, "", err
+	}
+
+	token, err := s.authService.IssueToken(user.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	return user, token, nil
+}
+
+// findOrCreateGithubUser GitHubのアカウントに対応するユーザーを取得（いなければ連携・作成）
+func (s *oauthService) findOrCreateGithubUser(ctx context.Context, profile *githubProfile, inviteCode string) (*models.User, error) {
+	// 連携済みのアカウント
+	identity, err := s.oauthRepo.Find(ctx, models.OAuthProviderGithub, profile.ID)
+	if err == nil {
+		user, err := s.userRepo.FindByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, errors.New("ユーザーが見つかりません")
+		}
+		s.refreshIdentity(ctx, identity, profile)
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("連携の取得に失敗しました: %v", err)
+	}
+
+	if len(profile.Emails) == 0 {
+		return nil, errors.New("GitHubアカウントに確認済みのメールアドレスがありません")
+	}
+
+	// 確認済みのメールアドレスが一致するユーザーに連携する
+	for _, email := range profile.Emails {
+		user, err := s.userRepo.FindByEmail(ctx, email)
+		if err != nil {
+			continue
+		}
+		if err := s.link(ctx, user.ID, profile, email); err != nil {
+			return nil, err
+		}
+
+		// 名簿で仮登録されたユーザーは、メールアドレスを確認できたため招待を承認したものとする
+		if user.Pending {
+			user.Pending = false
+			user.InviteTokenHash = ""
+			user.InviteExpiresAt = nil
+			if err := s.userRepo.Update(ctx, user); err != nil {
+				return nil, fmt.Errorf("ユーザーの更新に失敗しました: %v", err)
+			}
+		}
+		return user, nil
+	}
+
+	// 新しいユーザーを作成（パスワードは設定しない）
+	email := profile.Emails[0]
+	code, err := s.registrationService.Redeem(ctx, inviteCode, email)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.createUser(ctx, profile, email)
+	if err == nil {
+		err = s.link(ctx, user.ID, profile, email)
+	}
+	if err != nil {
+		s.registrationService.Release(context.Background(), code)
+		return nil, err
+	}
+	return user, nil
+}
+
+// createUser GitHubのアカウントから新しいユーザーを作成
+func (s *oauthService) createUser(ctx context.Context, profile *githubProfile, email string) (*models.User, error) {
+	name := profile.Name
+	if name == "" {
+		name = profile.Login
+	}
+	handle, err := generateHandle(ctx, s.userRepo, profile.Login)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Email:    email,
+		Name:     name,
+		Nickname: profile.Login,
+		Handle:   &handle,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("ユーザーの作成に失敗しました: %v", err)
+	}
+	return user, nil
+}
+
+// LinkGithub ログイン中のユーザーにGitHubのアカウントを連携
+func (s *oauthService) LinkGithub(ctx context.Context, userID uint, code string) (*models.OAuthProvider, error) {
+	profile, err := s.fetchGithubProfile(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.oauthRepo.Find(ctx, models.OAuthProviderGithub, profile.ID)
+	if err == nil {
+		if identity.UserID != userID {
+			return nil, errors.New("このGitHubアカウントは既に別のユーザーに連携されています")
+		}
+		s.refreshIdentity(ctx, identity, profile)
+		return identity, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("連携の取得に失敗しました: %v", err)
+	}
+
+	email := ""
+	if len(profile.Emails) > 0 {
+		email = profile.Emails[0]
+	}
+	if err := s.link(ctx, userID, profile, email); err != nil {
+		return nil, err
+	}
+	return s.oauthRepo.Find(ctx, models.OAuthProviderGithub, profile.ID)
+}
+
+// ListProviders ユーザーが連携しているアカウントの一覧
+func (s *oauthService) ListProviders(ctx context.Context, userID uint) ([]models.OAuthProvider, error) {
+	identities, err := s.oauthRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("連携の取得に失敗しました: %v", err)
+	}
+	return identities, nil
+}
+
+// Unlink アカウントの連携を解除（パスワードを設定していない場合、最後の連携は解除できない）
+func (s *oauthService) Unlink(ctx context.Context, userID uint, provider string) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("ユーザーが見つかりません")
+	}
+	identities, err := s.oauthRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("連携の取得に失敗しました: %v", err)
+	}
+
+	linked := false
+	for _, identity := range identities {
+		if identity.Provider == provider {
+			linked = true
+		}
+	}
+	if !linked {
+		return errors.New("連携が見つかりません")
+	}
+	if user.Password == "" && len(identities) == 1 {
+		return errors.New("パスワードを設定していないため、最後の連携は解除できません")
+	}
+
+	if err := s.oauthRepo.Delete(ctx, userID, provider); err != nil {
+		return fmt.Errorf("連携の解除に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// link ユーザーにGitHubのアカウントの連携を作成
+func (s *oauthService) link(ctx context.Context, userID uint, profile *githubProfile, email string) error {
+	identities, err := s.oauthRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("連携の取得に失敗しました: %v", err)
+	}
+	for _, identity := range identities {
+		if identity.Provider == models.OAuthProviderGithub {
+			return errors.New("このユーザーには既に別のGitHubアカウントが連携されています")
+		}
+	}
+
+	if err := s.oauthRepo.Create(ctx, &models.OAuthProvider{
+		UserID:         userID,
+		Provider:       models.OAuthProviderGithub,
+		ProviderUserID: profile.ID,
+		Login:          profile.Login,
+		Email:          email,
+	}); err != nil {
+		return fmt.Errorf("GitHubアカウントの連携に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// refreshIdentity GitHubのユーザー名が変わっていれば更新（失敗しても認証は続ける）
+func (s *oauthService) refreshIdentity(ctx context.Context, identity *models.OAuthProvider, profile *githubProfile) {
+	if identity.Login == profile.Login {
+		return
+	}
+	identity.Login = profile.Login
+	_ = s.oauthRepo.Update(ctx, identity)
+}
+
+// fetchGithubProfile 認可コードをアクセストークンに交換し、GitHubのユーザー情報を取得
+func (s *oauthService) fetchGithubProfile(ctx context.Context, code string) (*githubProfile, error) {
+	if s.config.Auth.GithubClientID == "" {
+		return nil, ErrGithubLoginDisabled
+	}
+	if code == "" {
+		return nil, errors.New("認可コード（code）は必須です")
+	}
+
+	// 認可コードをアクセストークンに交換
+	form := url.Values{
+		"client_id":     {s.config.Auth.GithubClientID},
+		"client_secret": {s.config.Auth.GithubClientSecret},
+		"code":          {code},
+	}
+	if s.config.Auth.GithubRedirectURL != "" {
+		form.Set("redirect_uri", s.config.Auth.GithubRedirectURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		strings.TrimSuffix(s.config.Auth.GithubOAuthURL, "/")+"/login/oauth/access_token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("GitHubとの通信に失敗しました: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+	if err := s.doGithub(req, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		// 期限切れ・使用済みの認可コードなど
+		return nil, fmt.Errorf("GitHubの認証に失敗しました: %s", token.Error)
+	}
+
+	// ユーザー情報とメールアドレスを取得
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := s.getGithubAPI(ctx, token.AccessToken, "/user", &user); err != nil {
+		return nil, err
+	}
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := s.getGithubAPI(ctx, token.AccessToken, "/user/emails", &emails); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, errors.New("GitHubの認証に失敗しました: ユーザー情報を取得できません")
+	}
+
+	profile := &githubProfile{ID: strconv.FormatInt(user.ID, 10), Login: user.Login, Name: user.Name}
+	for _, email := range emails {
+		if !email.Verified {
+			continue
+		}
+		if email.Primary {
+			profile.Emails = append([]string{email.Email}, profile.Emails...)
+		} else {
+			profile.Emails = append(profile.Emails, email.Email)
+		}
+	}
+	return profile, nil
+}
+
+// getGithubAPI アクセストークンでGitHubのAPIを呼び出す
+func (s *oauthService) getGithubAPI(ctx context.Context, accessToken, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(s.config.Auth.GithubAPIURL, "/")+path, nil)
+	if err != nil {
+		return fmt.Errorf("GitHubとの通信に失敗しました: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	return s.doGithub(req, v)
+}
+
+// doGithub GitHubにリクエストを送り、JSONのレスポンスを読み込む
+func (s *oauthService) doGithub(req *http.Request, v interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHubとの通信に失敗しました: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return fmt.Errorf("GitHubの認証に失敗しました: ステータス %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHubとの通信に失敗しました: ステータス %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v); err != nil {
+		return fmt.Errorf("GitHubとの通信に失敗しました: %v", err)
+	}
+	return nil
+}
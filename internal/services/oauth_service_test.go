@@ -0,0 +1,40 @@
+package services
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+)
+
+func TestVerifyGithubState(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Auth.JWTSecret = "test-secret"
+	s := &oauthService{config: cfg}
+
+	signed := s.SignGithubState("browser-state")
+	expired := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	other := &oauthService{config: &config.Config{}}
+	other.config.Auth.JWTSecret = "other-secret"
+
+	tests := []struct {
+		name    string
+		signed  string
+		state   string
+		wantErr bool
+	}{
+		{"一致する", signed, "browser-state", false},
+		{"Cookieがない", "", "browser-state", true},
+		{"別のstate（攻撃者の認可）", signed, "attacker-state", true},
+		{"stateがない", signed, "", true},
+		{"署名が異なる", other.SignGithubState("browser-state"), "browser-state", true},
+		{"有効期限切れ", "browser-state." + expired + "." + s.githubStateSignature("browser-state", expired), "browser-state", true},
+	}
+	for _, tt := range tests {
+		err := s.VerifyGithubState(tt.signed, tt.state)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
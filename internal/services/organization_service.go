@@ -0,0 +1,326 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// OrganizationService 学校・クラブなどの組織に関するサービスインターフェース
+type OrganizationService interface {
+	Create(ctx context.Context, userID uint, input OrganizationInput) (*models.Organization, error)
+	GetByID(ctx context.Context, id, userID uint) (*models.Organization, error)
+	Update(ctx context.Context, id, userID uint, input OrganizationInput) (*models.Organization, error)
+	Delete(ctx context.Context, id, userID uint) error
+	ListMine(ctx context.Context, userID uint) ([]models.Organization, error)
+	ListMembers(ctx context.Context, id, userID uint) ([]models.OrganizationMember, error)
+	AddMember(ctx context.Context, id, userID, memberID uint, role string) ([]models.OrganizationMember, error)
+	UpdateRole(ctx context.Context, id, userID, memberID uint, role string) ([]models.OrganizationMember, error)
+	RemoveMember(ctx context.Context, id, userID, memberID uint) error
+	SetLimits(ctx context.Context, id uint, maxProjects, maxMembers int) (*models.Organization, error)
+}
+
+// OrganizationInput 組織の作成・更新の内容
+type OrganizationInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// 組織名の最大文字数
+const organizationNameMaxLength = 100
+
+// organizationService OrganizationServiceの実装
+type organizationService struct {
+	organizationRepo  repository.OrganizationRepository
+	userRepo          repository.UserRepository
+	reputationService ReputationService
+	config            *config.Config
+}
+
+// NewOrganizationService OrganizationServiceを作成
+func NewOrganizationService(
+	organizationRepo repository.OrganizationRepository,
+	userRepo repository.UserRepository,
+	reputationService ReputationService,
+	cfg *config.Config,
+) OrganizationService {
+	return &organizationService{
+		organizationRepo:  organizationRepo,
+		userRepo:          userRepo,
+		reputationService: reputationService,
+		config:            cfg,
+	}
+}
+
+// Create 新しい組織を作成し、作成者をオーナーにする（プロジェクトの作成と同じレピュテーションが必要）
+func (s *organizationService) Create(ctx context.Context, userID uint, input OrganizationInput) (*models.Organization, error) {
+	if err := s.reputationService.Require(ctx, userID, s.config.Reputation.MinToCreateProject); err != nil {
+		return nil, err
+	}
+
+	organization := &models.Organization{
+		MaxProjects: s.config.Organization.DefaultMaxProjects,
+		MaxMembers:  s.config.Organization.DefaultMaxMembers,
+	}
+	if err := applyOrganizationInput(organization, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.organizationRepo.Create(ctx, organization, userID); err != nil {
+		return nil, fmt.Errorf("組織の作成に失敗しました: %v", err)
+	}
+
+	return s.find(ctx, organization.ID)
+}
+
+// GetByID IDで組織を取得（メンバーのみ）
+func (s *organizationService) GetByID(ctx context.Context, id, userID uint) (*models.Organization, error) {
+	organization, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.requireRole(ctx, id, userID, "この組織を閲覧する権限がありません", organizationRoles...); err != nil {
+		return nil, err
+	}
+	return organization, nil
+}
+
+// Update 組織の名前と説明を更新（オーナー・管理者のみ）
+func (s *organizationService) Update(ctx context.Context, id, userID uint, input OrganizationInput) (*models.Organization, error) {
+	organization, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.requireRole(ctx, id, userID, "この組織を更新する権限がありません", organizationManagerRoles...); err != nil {
+		return nil, err
+	}
+
+	if err := applyOrganizationInput(organization, input); err != nil {
+		return nil, err
+	}
+	if err := s.organizationRepo.Update(ctx, organization); err != nil {
+		return nil, fmt.Errorf("組織の更新に失敗しました: %v", err)
+	}
+
+	return s.find(ctx, id)
+}
+
+// Delete 組織を削除（オーナーのみ、プロジェクトが残っている場合は削除できない）
+func (s *organizationService) Delete(ctx context.Context, id, userID uint) error {
+	if _, err := s.find(ctx, id); err != nil {
+		return err
+	}
+	if _, err := s.requireRole(ctx, id, userID, "この組織を削除する権限がありません", models.OrganizationRoleOwner); err != nil {
+		return err
+	}
+
+	if err := s.organizationRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrOrganizationHasProjects) {
+			return err
+		}
+		return fmt.Errorf("組織の削除に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// ListMine ユーザーが所属する組織一覧を取得
+func (s *organizationService) ListMine(ctx context.Context, userID uint) ([]models.Organization, error) {
+	organizations, err := s.organizationRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("組織一覧の取得に失敗しました: %v", err)
+	}
+	return organizations, nil
+}
+
+// ListMembers 組織のメンバー一覧を取得（メンバーのみ）
+func (s *organizationService) ListMembers(ctx context.Context, id, userID uint) ([]models.OrganizationMember, error) {
+	if _, err := s.find(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := s.requireRole(ctx, id, userID, "この組織のメンバーを閲覧する権限がありません", organizationRoles...); err != nil {
+		return nil, err
+	}
+
+	members, err := s.organizationRepo.ListMembers(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("メンバー一覧の取得に失敗しました: %v", err)
+	}
+	return members, nil
+}
+
+// AddMember 組織にメンバーを追加（オーナー・管理者のみ、オーナーを追加できるのはオーナーのみ）
+func (s *organizationService) AddMember(ctx context.Context, id, userID, memberID uint, role string) ([]models.OrganizationMember, error) {
+	if _, err := s.find(ctx, id); err != nil {
+		return nil, err
+	}
+	if role == "" {
+		role = models.OrganizationRoleMember
+	}
+	if !isOrganizationRole(role) {
+		return nil, errors.New("roleにはowner・admin・memberのいずれかを指定してください")
+	}
+
+	current, err := s.requireRole(ctx, id, userID, "この組織にメンバーを追加する権限がありません", organizationManagerRoles...)
+	if err != nil {
+		return nil, err
+	}
+	if role == models.OrganizationRoleOwner && current != models.OrganizationRoleOwner {
+		return nil, errors.New("オーナーを追加する権限がありません")
+	}
+
+	if _, err := s.userRepo.FindByID(ctx, memberID); err != nil {
+		return nil, errors.New("ユーザーが見つかりません")
+	}
+
+	if err := s.organizationRepo.AddMember(ctx, id, memberID, role); err != nil {
+		if errors.Is(err, repository.ErrAlreadyOrganizationMember) || errors.Is(err, repository.ErrOrganizationFull) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("メンバーの追加に失敗しました: %v", err)
+	}
+
+	return s.organizationRepo.ListMembers(ctx, id)
+}
+
+// UpdateRole メンバーの役割を変更（オーナーのみ）
+func (s *organizationService) UpdateRole(ctx context.Context, id, userID, memberID uint, role string) ([]models.OrganizationMember, error) {
+	if _, err := s.find(ctx, id); err != nil {
+		return nil, err
+	}
+	if !isOrganizationRole(role) {
+		return nil, errors.New("roleにはowner・admin・memberのいずれかを指定してください")
+	}
+	if _, err := s.requireRole(ctx, id, userID, "メンバーの役割を変更する権限がありません", models.OrganizationRoleOwner); err != nil {
+		return nil, err
+	}
+
+	if err := s.organizationRepo.UpdateRole(ctx, id, memberID, role); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("メンバーが見つかりません")
+		}
+		if errors.Is(err, repository.ErrLastOrganizationOwner) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("役割の変更に失敗しました: %v", err)
+	}
+
+	return s.organizationRepo.ListMembers(ctx, id)
+}
+
+// RemoveMember メンバーを組織から削除（オーナー・管理者のみ、管理者はオーナーを削除できない）
+// メンバーは自分自身を削除して組織から抜けられる
+func (s *organizationService) RemoveMember(ctx context.Context, id, userID, memberID uint) error {
+	if _, err := s.find(ctx, id); err != nil {
+		return err
+	}
+
+	target, err := s.organizationRepo.GetRole(ctx, id, memberID)
+	if err != nil {
+		return fmt.Errorf("メンバーの削除に失敗しました: %v", err)
+	}
+	if memberID != userID {
+		current, err := s.requireRole(ctx, id, userID, "この組織のメンバーを削除する権限がありません", organizationManagerRoles...)
+		if err != nil {
+			return err
+		}
+		if target == models.OrganizationRoleOwner && current != models.OrganizationRoleOwner {
+			return errors.New("オーナーを削除する権限がありません")
+		}
+	}
+	if target == "" {
+		return errors.New("メンバーが見つかりません")
+	}
+
+	if err := s.organizationRepo.RemoveMember(ctx, id, memberID); err != nil {
+		if errors.Is(err, repository.ErrLastOrganizationOwner) {
+			return err
+		}
+		return fmt.Errorf("メンバーの削除に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// SetLimits 組織のプロジェクト数・メンバー数の上限を変更（管理者用、0の場合は無制限）
+// 既に上限を超えている場合も、既存のプロジェクトとメンバーはそのまま残す
+func (s *organizationService) SetLimits(ctx context.Context, id uint, maxProjects, maxMembers int) (*models.Organization, error) {
+	if maxProjects < 0 || maxMembers < 0 {
+		return nil, errors.New("max_projects・max_membersには0以上の数を指定してください")
+	}
+
+	organization, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	organization.MaxProjects = maxProjects
+	organization.MaxMembers = maxMembers
+	if err := s.organizationRepo.Update(ctx, organization); err != nil {
+		return nil, fmt.Errorf("組織の上限の変更に失敗しました: %v", err)
+	}
+
+	return s.find(ctx, id)
+}
+
+// find IDで組織を取得
+func (s *organizationService) find(ctx context.Context, id uint) (*models.Organization, error) {
+	organization, err := s.organizationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("組織が見つかりません")
+	}
+	return organization, nil
+}
+
+// requireRole ユーザーが組織でいずれかの役割を持っているか確認し、役割を返す（持っていない場合はmessageのエラー）
+func (s *organizationService) requireRole(ctx context.Context, id, userID uint, message string, roles ...string) (string, error) {
+	return requireOrganizationRole(ctx, s.organizationRepo, id, userID, message, roles...)
+}
+
+// 組織の全ての役割と、メンバーを管理できる役割
+var (
+	organizationRoles        = []string{models.OrganizationRoleOwner, models.OrganizationRoleAdmin, models.OrganizationRoleMember}
+	organizationManagerRoles = []string{models.OrganizationRoleOwner, models.OrganizationRoleAdmin}
+)
+
+// requireOrganizationRole ユーザーが組織でいずれかの役割を持っているか確認し、役割を返す（持っていない場合はmessageのエラー）
+func requireOrganizationRole(ctx context.Context, organizationRepo repository.OrganizationRepository, id, userID uint, message string, roles ...string) (string, error) {
+	role, err := organizationRepo.GetRole(ctx, id, userID)
+	if err != nil {
+		return "", fmt.Errorf("組織の権限の確認に失敗しました: %v", err)
+	}
+	for _, allowed := range roles {
+		if role == allowed {
+			return role, nil
+		}
+	}
+	return "", errors.New(message)
+}
+
+// isOrganizationRole 組織の役割として有効か確認
+func isOrganizationRole(role string) bool {
+	for _, r := range organizationRoles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+// applyOrganizationInput 組織の作成・更新の内容を検証して設定
+func applyOrganizationInput(organization *models.Organization, input OrganizationInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return errors.New("組織名は必須です")
+	}
+	if len([]rune(name)) > organizationNameMaxLength {
+		return fmt.Errorf("組織名は%d文字以内で指定してください", organizationNameMaxLength)
+	}
+	organization.Name = name
+	organization.Description = strings.TrimSpace(input.Description)
+	return nil
+}
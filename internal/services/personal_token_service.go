@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+)
+
+// PersonalTokenService 個人用アクセストークンに関するサービスインターフェース
+type PersonalTokenService interface {
+	// Create トークンを発行（トークン自体は発行時にのみ返す、expiresInDaysが0の場合は無期限）
+	Create(ctx context.Context, userID uint, name string, scopes []string, expiresInDays int) (*models.PersonalToken, string, error)
+	List(ctx context.Context, userID uint) ([]models.PersonalToken, error)
+	Update(ctx context.Context, id, userID uint, name string, scopes []string) (*models.PersonalToken, error)
+	Delete(ctx context.Context, id, userID uint) error
+	// Authenticate トークンを検証してユーザーを取得
+	Authenticate(ctx context.Context, token string) (*models.User, *models.PersonalToken, error)
+	// Allows トークンのスコープでAPIを使えるか確認（pathはバージョンを除いたルートのパス）
+	Allows(token *models.PersonalToken, method, path string) bool
+}
+
+// PersonalTokenPrefix 個人用アクセストークンの先頭の文字列（JWTと区別するため）
+const PersonalTokenPrefix = "ssp_"
+
+const (
+	personalTokenLength        = 40          // トークンの長さ（先頭の文字列を除く）
+	personalTokenPrefixLength  = 12          // 一覧に表示するトークンの先頭部分の長さ
+	personalTokenMaxName       = 100         // トークンの名前の最大文字数
+	maxPersonalTokens          = 20          // 1人のユーザーが発行できるトークン数
+	maxPersonalTokenExpiryDays = 365         // 有効期限の最大日数
+	personalTokenTouchInterval = time.Minute // 最後に使われた日時を更新する間隔
+)
+
+var (
+	// personalTokenScopes 発行できるスコープ（並び順は保存時の順序）
+	personalTokenScopes = []string{models.PersonalTokenScopeRead, models.PersonalTokenScopeWorksWrite}
+	// personalTokenWritePaths works:writeスコープで変更できるAPIのパス
+	personalTokenWritePaths = []string{"/works", "/uploads"}
+	// personalTokenDeniedPaths スコープに関わらず使えないAPIのパス（トークン自体の管理や管理者の操作による権限の拡大を防ぐ）
+	personalTokenDeniedPaths = []string{"/admin", "/users/me/tokens"}
+)
+
+// errInvalidPersonalToken 見つからない・期限切れ・無効にされた個人用アクセストークン
+var errInvalidPersonalToken = errors.New("無効なトークンです")
+
+// personalTokenService PersonalTokenServiceの実装
+type personalTokenService struct {
+	personalTokenRepo repository.PersonalTokenRepository
+	userRepo          repository.UserRepository
+}
+
+// NewPersonalTokenService PersonalTokenServiceを作成
+func NewPersonalTokenService(personalTokenRepo repository.PersonalTokenRepository, userRepo repository.UserRepository) PersonalTokenService {
+	return &personalTokenService{
+		personalTokenRepo: personalTokenRepo,
+		userRepo:          userRepo,
+	}
+}
+
+// Create トークンを発行
+func (s *personalTokenService) Create(ctx context.Context, userID uint, name string, scopes []string, expiresInDays int) (*models.PersonalToken, string, error) {
+	name, err := validatePersonalTokenName(name)
+	if err != nil {
+		return nil, "", err
+	}
+	scopeValue, err := normalizePersonalTokenScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if expiresInDays < 0 || expiresInDays > maxPersonalTokenExpiryDays {
+		return nil, "", fmt.Errorf("有効期限は%d日以内で指定してください", maxPersonalTokenExpiryDays)
+	}
+
+	count, err := s.personalTokenRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("トークンの取得に失敗しました: %v", err)
+	}
+	if count >= maxPersonalTokens {
+		return nil, "", fmt.Errorf("発行できるトークンは%d個までです", maxPersonalTokens)
+	}
+
+	tokenString := PersonalTokenPrefix + utils.GenerateRandomString(personalTokenLength)
+	token := &models.PersonalToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashInviteToken(tokenString),
+		Prefix:    tokenString[:personalTokenPrefixLength],
+		Scopes:    scopeValue,
+	}
+	if expiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, expiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+	if err := s.personalTokenRepo.Create(ctx, token); err != nil {
+		return nil, "", fmt.Errorf("トークンの発行に失敗しました: %v", err)
+	}
+
+	return token, tokenString, nil
+}
+
+// List ユーザーのトークンを作成日時の新しい順に取得
+func (s *personalTokenService) List(ctx context.Context, userID uint) ([]models.PersonalToken, error) {
+	return s.personalTokenRepo.ListByUser(ctx, userID)
+}
+
+// Update トークンの名前・スコープを変更
+func (s *personalTokenService) Update(ctx context.Context, id, userID uint, name string, scopes []string) (*models.PersonalToken, error) {
+	token, err := s.findOwn(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Name, err = validatePersonalTokenName(name); err != nil {
+		return nil, err
+	}
+	if token.Scopes, err = normalizePersonalTokenScopes(scopes); err != nil {
+		return nil, err
+	}
+	if err := s.personalTokenRepo.Update(ctx, token); err != nil {
+		return nil, fmt.Errorf("トークンの更新に失敗しました: %v", err)
+	}
+
+	return token, nil
+}
+
+// Delete トークンを削除（以降そのトークンは使えない）
+func (s *personalTokenService) Delete(ctx context.Context, id, userID uint) error {
+	if _, err := s.findOwn(ctx, id, userID); err != nil {
+		return err
+	}
+	if err := s.personalTokenRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("トークンの削除に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// Authenticate トークンを検証してユーザーを取得
+func (s *personalTokenService) Authenticate(ctx context.Context, tokenString string) (*models.User, *models.PersonalToken, error) {
+	if !strings.HasPrefix(tokenString, PersonalTokenPrefix) {
+		return nil, nil, errInvalidPersonalToken
+	}
+	token, err := s.personalTokenRepo.FindByHash(ctx, hashInviteToken(tokenString))
+	if err != nil {
+		return nil, nil, errInvalidPersonalToken
+	}
+	now := time.Now()
+	if token.ExpiresAt != nil && token.ExpiresAt.Before(now) {
+		return nil, nil, errInvalidPersonalToken
+	}
+
+	user, err := s.userRepo.FindByID(ctx, token.UserID)
+	if err != nil {
+		return nil, nil, errInvalidPersonalToken
+	}
+	// 全ての端末からのログアウト・メールアドレスの変更より前に発行されたトークンは使えない
+	if user.TokensValidAfter != nil && token.CreatedAt.Before(*user.TokensValidAfter) {
+		return nil, nil, errInvalidPersonalToken
+	}
+
+	// リクエストごとに書き込まないよう、一定の間隔でのみ更新する
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > personalTokenTouchInterval {
+		if err := s.personalTokenRepo.TouchLastUsed(ctx, token.ID, now); err != nil {
+			log.Printf("トークンの使用日時の更新に失敗しました (TokenID=%d): %v", token.ID, err)
+		}
+	}
+
+	return user, token, nil
+}
+
+// Allows トークンのスコープでAPIを使えるか確認
+// 取得（GET・HEAD）はreadスコープ、作品・アップロードの変更はworks:writeスコープが必要で、それ以外の変更には使えない
+func (s *personalTokenService) Allows(token *models.PersonalToken, method, path string) bool {
+	for _, denied := range personalTokenDeniedPaths {
+		if hasPathPrefix(path, denied) {
+			return false
+		}
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return token.HasScope(models.PersonalTokenScopeRead)
+	}
+	for _, writable := range personalTokenWritePaths {
+		if hasPathPrefix(path, writable) {
+			return token.HasScope(models.PersonalTokenScopeWorksWrite)
+		}
+	}
+	return false
+}
+
+// findOwn ユーザーのトークンを取得
+func (s *personalTokenService) findOwn(ctx context.Context, id, userID uint) (*models.PersonalToken, error) {
+	token, err := s.personalTokenRepo.FindByID(ctx, id)
+	if err != nil || token.UserID != userID {
+		return nil, errors.New("トークンが見つかりません")
+	}
+	return token, nil
+}
+
+// validatePersonalTokenName トークンの名前を検証して前後の空白を除く
+func validatePersonalTokenName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("トークンの名前は必須です")
+	}
+	if utf8.RuneCountInString(name) > personalTokenMaxName {
+		return "", fmt.Errorf("トークンの名前は%d文字以内で入力してください", personalTokenMaxName)
+	}
+	return name, nil
+}
+
+// normalizePersonalTokenScopes スコープを検証し、重複を除いてスペース区切りにする
+func normalizePersonalTokenScopes(scopes []string) (string, error) {
+	requested := map[string]bool{}
+	for _, scope := range scopes {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			requested[scope] = true
+		}
+	}
+	normalized := []string{}
+	for _, scope := range personalTokenScopes {
+		if requested[scope] {
+			normalized = append(normalized, scope)
+			delete(requested, scope)
+		}
+	}
+	for scope := range requested {
+		return "", fmt.Errorf("無効なスコープです: %s", scope)
+	}
+	if len(normalized) == 0 {
+		return "", errors.New("スコープを1つ以上指定してください")
+	}
+	return strings.Join(normalized, " "), nil
+}
+
+// hasPathPrefix パスがprefixと同じか、その下位のパスか確認
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
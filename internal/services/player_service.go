@@ -0,0 +1,163 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// PlayerService 作品のプレイヤーページ（SPAを使わずに作品を再生する単体のHTML）に関するサービスインターフェース
+type PlayerService interface {
+	Render(ctx context.Context, workID uint) ([]byte, error)
+}
+
+// playerPage プレイヤーページのテンプレートに渡す値
+type playerPage struct {
+	Title        string
+	Author       string
+	Description  string
+	ThumbnailURL string
+	WorkURL      string
+	P5URL        string
+	Script       template.JS // 空の場合は再生できない旨を表示する
+}
+
+// scriptBreakout スクリプトの途中でscript要素が閉じられないよう、</script と <!-- の直後に\を入れる
+// （JSの文字列や正規表現の中では \/ と \! は元の文字と同じ意味になる）
+var scriptBreakout = regexp.MustCompile(`(?i)<(/script|!--)`)
+
+// playerTemplate プレイヤーページのテンプレート
+// 作品のアセットはスケッチから相対パスで読み込めるよう、base要素で /works/:id/assets/ を起点にする
+var playerTemplate = template.Must(template.New("player").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} - SketchShifter</title>
+<base href="assets/">
+<meta property="og:type" content="website">
+<meta property="og:title" content="{{.Title}}">
+<meta property="og:url" content="{{.WorkURL}}">
+{{- if .Description}}
+<meta property="og:description" content="{{.Description}}">
+{{- end}}
+{{- if .ThumbnailURL}}
+<meta property="og:image" content="{{.ThumbnailURL}}">
+{{- end}}
+<style>
+html, body { margin: 0; height: 100%; background: #111; color: #eee; font-family: sans-serif; }
+body { display: flex; flex-direction: column; }
+main { flex: 1; display: flex; align-items: center; justify-content: center; overflow: hidden; }
+main canvas { max-width: 100%; max-height: 100%; }
+nav { display: flex; align-items: center; gap: 8px; padding: 8px 12px; background: #222; font-size: 14px; }
+nav button { min-width: 36px; padding: 4px 8px; border: 1px solid #555; border-radius: 4px; background: #333; color: #eee; cursor: pointer; }
+nav button:disabled { opacity: 0.4; cursor: default; }
+nav .title { flex: 1; overflow: hidden; white-space: nowrap; text-overflow: ellipsis; }
+nav a { color: #8cf; }
+.message { color: #aaa; }
+</style>
+</head>
+<body>
+<main id="sketch">
+{{- if not .Script}}
+<p class="message">この作品はまだ再生できません</p>
+{{- end}}
+</main>
+<nav>
+<button type="button" id="toggle" title="一時停止" {{- if not .Script}} disabled{{end}}>❚❚</button>
+<button type="button" id="restart" title="最初から再生" {{- if not .Script}} disabled{{end}}>↻</button>
+<span class="title">{{.Title}} / {{.Author}}</span>
+<a href="{{.WorkURL}}" target="_blank" rel="noopener">SketchShifterで見る</a>
+</nav>
+{{- if .Script}}
+<script src="{{.P5URL}}"></script>
+<script>
+{{.Script}}
+</script>
+<script>
+(function () {
+  var toggle = document.getElementById("toggle");
+  var restart = document.getElementById("restart");
+  var paused = false;
+
+  // キャンバスをプレイヤーの表示領域に移動する
+  window.addEventListener("load", function () {
+    var canvas = document.querySelector("body > canvas");
+    if (canvas) {
+      document.getElementById("sketch").appendChild(canvas);
+    }
+  });
+
+  toggle.addEventListener("click", function () {
+    if (typeof loop !== "function" || typeof noLoop !== "function") {
+      return;
+    }
+    paused = !paused;
+    if (paused) {
+      noLoop();
+      toggle.textContent = "▶";
+      toggle.title = "再生";
+    } else {
+      loop();
+      toggle.textContent = "❚❚";
+      toggle.title = "一時停止";
+    }
+  });
+
+  // グローバル変数も含めて初期化するため、ページを読み込み直す
+  restart.addEventListener("click", function () {
+    window.location.reload();
+  });
+})();
+</script>
+{{- end}}
+</body>
+</html>
+`))
+
+// playerService PlayerServiceの実装
+type playerService struct {
+	workRepo repository.WorkRepository
+	config   *config.Config
+}
+
+// NewPlayerService PlayerServiceを作成
+func NewPlayerService(workRepo repository.WorkRepository, cfg *config.Config) PlayerService {
+	return &playerService{
+		workRepo: workRepo,
+		config:   cfg,
+	}
+}
+
+// Render 作品のプレイヤーページを描画（変換が済んでいない作品は再生できない旨を表示する）
+func (s *playerService) Render(ctx context.Context, workID uint) ([]byte, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil || work.HiddenAt != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+
+	page := playerPage{
+		Title:        work.Title,
+		Author:       work.User.Nickname,
+		Description:  work.Description,
+		ThumbnailURL: work.ThumbnailURL,
+		WorkURL:      fmt.Sprintf("%s/works/%d", strings.TrimSuffix(s.config.Sitemap.SiteURL, "/"), work.ID),
+		P5URL:        s.config.Player.P5URL,
+	}
+	if work.JSContent != "" {
+		page.Script = template.JS(scriptBreakout.ReplaceAllString(work.JSContent, `<\$1`))
+	}
+
+	var buf bytes.Buffer
+	if err := playerTemplate.Execute(&buf, page); err != nil {
+		return nil, fmt.Errorf("プレイヤーページの描画に失敗しました: %v", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -1,87 +1,244 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log"
 	"math/rand"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 )
 
 // ProjectService プロジェクトに関するサービスインターフェース
 type ProjectService interface {
-	Create(title, description string, userID uint) (*models.Project, error)
-	GetByID(id uint) (*models.Project, error)
-	Update(id, userID uint, title, description string) (*models.Project, error)
-	Delete(id, userID uint) error
-	List(page, limit int, search string, userID *uint) ([]models.Project, int64, int, error)
-	GetMembers(projectID uint) ([]models.ProjectMember, error)
-	AddMember(projectID, userID uint, isOwner bool) error
-	RemoveMember(projectID, ownerID, userID uint) error
-	JoinByInvitationCode(code string, userID uint) (*models.Project, error)
-	GenerateInvitationCode(projectID, userID uint) (string, error)
-	IsUserAllowed(projectID, userID uint) (bool, error)
-	IsOwner(projectID, userID uint) (bool, error)
-	GetUserProjects(userID uint, page, limit int) ([]models.Project, int64, int, error)
+	Create(ctx context.Context, title, description string, userID uint, organizationID *uint) (*models.Project, error)
+	Clone(ctx context.Context, id, userID uint, title string) (*models.Project, error)
+	GetByID(ctx context.Context, id uint) (*models.Project, error)
+	Update(ctx context.Context, id, userID uint, title, description string, version *uint) (*models.Project, error)
+	Delete(ctx context.Context, id, userID uint) error
+	List(ctx context.Context, page, limit int, search string, userID *uint) ([]models.Project, int64, int, error)
+	ListByOrganization(ctx context.Context, organizationID, userID uint, page, limit int, search string) ([]models.Project, int64, int, error)
+	GetMembers(ctx context.Context, projectID uint) ([]models.ProjectMember, error)
+	ExportMembers(ctx context.Context, projectID, userID uint) ([]ProjectMemberExport, error)
+	ListInactiveMembers(ctx context.Context, projectID, userID uint, days int) ([]InactiveProjectMember, error)
+	AddMember(ctx context.Context, projectID, userID uint, isOwner bool) error
+	RemoveMember(ctx context.Context, projectID, ownerID, userID uint) error
+	JoinByInvitationCode(ctx context.Context, code string, userID uint) (*models.Project, error)
+	GenerateInvitationCode(ctx context.Context, projectID, userID uint) (string, error)
+	IsUserAllowed(ctx context.Context, projectID, userID uint) (bool, error)
+	IsOwner(ctx context.Context, projectID, userID uint) (bool, error)
+	GetUserProjects(ctx context.Context, userID uint, page, limit int) ([]models.Project, int64, int, error)
+	GetDashboard(ctx context.Context, projectID, userID uint) (*ProjectDashboard, error)
+	CreateContest(ctx context.Context, title, description string, userID uint) (*models.Project, error)
+	ListContests(ctx context.Context, page, limit int, phase string) ([]models.Project, int64, int, error)
+	GetContest(ctx context.Context, id uint) (*models.Project, error)
+	RegisterForContest(ctx context.Context, projectID, userID uint) (*models.Project, error)
+	StartJudging(ctx context.Context, projectID, userID uint, closesAt *time.Time) (*models.Vote, error)
+	GetContestResults(ctx context.Context, projectID uint) (*ContestResults, error)
+}
+
+// ダッシュボードに表示する件数
+const (
+	dashboardActivityLimit = 10
+	dashboardDeadlineLimit = 5
+)
+
+// ProjectDashboard プロジェクトのダッシュボード
+type ProjectDashboard struct {
+	Counts            *repository.ProjectDashboardCounts `json:"counts"`
+	RecentActivity    []models.Activity                  `json:"recent_activity"`
+	UpcomingDeadlines []models.Vote                      `json:"upcoming_deadlines"` // 締め切りが近い受付中の投票
+}
+
+// ProjectMemberExport エクスポートするメンバーごとの行
+type ProjectMemberExport struct {
+	Member         models.ProjectMember
+	Role           string // owner または member
+	Submissions    int64
+	LastActivityAt *time.Time // 作品の提出・コメント・投票・講評とアクティビティに記録された活動のうち最後のもの
+}
+
+// 活動のないメンバーとして扱う日数（指定しない場合と上限）
+const (
+	defaultInactiveMemberDays = 30
+	maxInactiveMemberDays     = 365
+)
+
+// InactiveProjectMember 一定期間活動のないメンバー
+type InactiveProjectMember struct {
+	Member         models.ProjectMember `json:"member"`
+	Submissions    int64                `json:"submissions"`
+	LastActivityAt *time.Time           `json:"last_activity_at"` // 活動がない場合はnull
+	InactiveDays   int                  `json:"inactive_days"`    // 最後の活動（活動がない場合は参加）からの日数
+}
+
+// コンテストの応募を受け付けるタスクのタイトル
+const contestTaskTitle = "応募作品"
+
+// 審査の投票を作成する際に一度に取得する応募作品数
+const contestEntryBatchSize = 100
+
+// ContestResults コンテストの結果
+type ContestResults struct {
+	Project *models.Project `json:"project"`
+	Vote    *models.Vote    `json:"vote"`
+	Entries []ContestEntry  `json:"entries"` // 得票数の多い順
+}
+
+// ContestEntry コンテストの応募作品ごとの結果
+type ContestEntry struct {
+	Rank      int          `json:"rank"` // 同票の場合は同じ順位
+	VoteCount int64        `json:"vote_count"`
+	Work      *models.Work `json:"work"`
 }
 
 // projectService ProjectServiceの実装
 type projectService struct {
-	projectRepo repository.ProjectRepository
-	taskRepo    repository.TaskRepository
+	projectRepo       repository.ProjectRepository
+	taskRepo          repository.TaskRepository
+	voteRepo          repository.VoteRepository
+	activityRepo      repository.ActivityRepository
+	organizationRepo  repository.OrganizationRepository
+	reputationService ReputationService
+	limitService      LimitService
+	config            *config.Config
 }
 
 // NewProjectService ProjectServiceを作成
-func NewProjectService(projectRepo repository.ProjectRepository, taskRepo repository.TaskRepository) ProjectService {
+func NewProjectService(
+	projectRepo repository.ProjectRepository,
+	taskRepo repository.TaskRepository,
+	voteRepo repository.VoteRepository,
+	activityRepo repository.ActivityRepository,
+	organizationRepo repository.OrganizationRepository,
+	reputationService ReputationService,
+	limitService LimitService,
+	cfg *config.Config,
+) ProjectService {
 	return &projectService{
-		projectRepo: projectRepo,
-		taskRepo:    taskRepo,
+		projectRepo:       projectRepo,
+		taskRepo:          taskRepo,
+		voteRepo:          voteRepo,
+		activityRepo:      activityRepo,
+		organizationRepo:  organizationRepo,
+		reputationService: reputationService,
+		limitService:      limitService,
+		config:            cfg,
 	}
 }
 
-// Create 新しいプロジェクトを作成
-func (s *projectService) Create(title, description string, userID uint) (*models.Project, error) {
+// Create 新しいプロジェクトを作成（organizationIDを指定した場合は組織のプロジェクトとして作成）
+func (s *projectService) Create(ctx context.Context, title, description string, userID uint, organizationID *uint) (*models.Project, error) {
 	// タイトルのバリデーション
 	if strings.TrimSpace(title) == "" {
 		return nil, errors.New("タイトルは必須です")
 	}
 
+	// 組織のメンバーであることと、プロジェクト数の上限を確認（個人のプロジェクトは料金プランの上限）
+	if organizationID != nil {
+		if err := s.checkOrganizationProject(ctx, *organizationID, userID); err != nil {
+			return nil, err
+		}
+	} else if err := s.limitService.CheckProjects(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// レピュテーションの条件を確認
+	if err := s.reputationService.Require(ctx, userID, s.config.Reputation.MinToCreateProject); err != nil {
+		return nil, err
+	}
+
 	// 招待コードを生成
 	code := generateInvitationCode()
 
 	// プロジェクトを作成
 	project := &models.Project{
-		Title:          title,
-		Description:    description,
-		OwnerID:        userID,
-		InvitationCode: code,
+		Title:           title,
+		Description:     description,
+		DescriptionHTML: utils.RenderMarkdown(description),
+		OwnerID:         userID,
+		OrganizationID:  organizationID,
+		InvitationCode:  code,
 	}
 
 	// データベースに保存
-	if err := s.projectRepo.Create(project); err != nil {
+	if err := s.projectRepo.Create(ctx, project); err != nil {
 		return nil, fmt.Errorf("プロジェクトの作成に失敗しました: %v", err)
 	}
 
 	// 作成者をオーナーとしてメンバーに追加
-	if err := s.projectRepo.AddMember(project.ID, userID, true); err != nil {
+	if err := s.projectRepo.AddMember(ctx, project.ID, userID, true); err != nil {
 		return nil, fmt.Errorf("オーナー情報の登録に失敗しました: %v", err)
 	}
 
-	return s.GetByID(project.ID)
+	return s.GetByID(ctx, project.ID)
+}
+
+// Clone プロジェクトのタスク・評価基準・投票をコピーして次の学期・回のプロジェクトを作成（オーナーのみ）
+// titleを省略した場合は元のタイトルに「（コピー）」を付ける（組織のプロジェクトは同じ組織に作成する）
+func (s *projectService) Clone(ctx context.Context, id, userID uint, title string) (*models.Project, error) {
+	source, err := s.projectRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("プロジェクトが見つかりません")
+	}
+
+	// 権限チェック
+	isOwner, err := s.projectRepo.IsOwner(ctx, id, userID)
+	if err != nil || !isOwner {
+		return nil, errors.New("このプロジェクトを複製する権限がありません")
+	}
+	if source.IsContest {
+		return nil, errors.New("コンテストは複製できません")
+	}
+	if source.OrganizationID != nil {
+		if err := s.checkOrganizationProject(ctx, *source.OrganizationID, userID); err != nil {
+			return nil, err
+		}
+	} else if err := s.limitService.CheckProjects(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	// レピュテーションの条件を確認
+	if err := s.reputationService.Require(ctx, userID, s.config.Reputation.MinToCreateProject); err != nil {
+		return nil, err
+	}
+
+	title = strings.TrimSpace(title)
+	if title == "" {
+		title = source.Title + "（コピー）"
+	}
+
+	project := &models.Project{
+		Title:           title,
+		Description:     source.Description,
+		DescriptionHTML: source.DescriptionHTML,
+		OwnerID:         userID,
+		OrganizationID:  source.OrganizationID,
+		InvitationCode:  generateInvitationCode(),
+	}
+	if err := s.projectRepo.Clone(ctx, source.ID, project); err != nil {
+		return nil, fmt.Errorf("プロジェクトの複製に失敗しました: %v", err)
+	}
+
+	return s.GetByID(ctx, project.ID)
 }
 
 // GetByID IDでプロジェクトを取得
-func (s *projectService) GetByID(id uint) (*models.Project, error) {
-	project, err := s.projectRepo.FindByID(id)
+func (s *projectService) GetByID(ctx context.Context, id uint) (*models.Project, error) {
+	project, err := s.projectRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("プロジェクトが見つかりません")
 	}
 
 	// タスク一覧を取得してセット
-	tasks, err := s.taskRepo.ListByProject(id)
+	tasks, err := s.taskRepo.ListByProject(ctx, id)
 	if err == nil {
 		project.Tasks = tasks
 	}
@@ -90,18 +247,21 @@ func (s *projectService) GetByID(id uint) (*models.Project, error) {
 }
 
 // Update プロジェクトを更新
-func (s *projectService) Update(id, userID uint, title, description string) (*models.Project, error) {
+func (s *projectService) Update(ctx context.Context, id, userID uint, title, description string, version *uint) (*models.Project, error) {
 	// プロジェクトを取得
-	project, err := s.projectRepo.FindByID(id)
+	project, err := s.projectRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("プロジェクトが見つかりません")
 	}
 
 	// 権限チェック
-	isOwner, err := s.projectRepo.IsOwner(id, userID)
+	isOwner, err := s.projectRepo.IsOwner(ctx, id, userID)
 	if err != nil || !isOwner {
 		return nil, errors.New("このプロジェクトを更新する権限がありません")
 	}
+	if err := checkVersion(version, project.Version); err != nil {
+		return nil, err
+	}
 
 	// タイトルのバリデーション
 	if strings.TrimSpace(title) == "" {
@@ -111,31 +271,37 @@ func (s *projectService) Update(id, userID uint, title, description string) (*mo
 	// フィールドを更新
 	project.Title = title
 	project.Description = description
+	project.DescriptionHTML = utils.RenderMarkdown(description)
 
 	// データベースを更新
-	if err := s.projectRepo.Update(project); err != nil {
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			if latest, findErr := s.projectRepo.FindByID(ctx, id); findErr == nil {
+				return nil, &VersionConflictError{CurrentVersion: latest.Version}
+			}
+		}
 		return nil, fmt.Errorf("プロジェクトの更新に失敗しました: %v", err)
 	}
 
-	return s.GetByID(id)
+	return s.GetByID(ctx, id)
 }
 
 // Delete プロジェクトを削除
-func (s *projectService) Delete(id, userID uint) error {
+func (s *projectService) Delete(ctx context.Context, id, userID uint) error {
 	// プロジェクトを取得
-	_, err := s.projectRepo.FindByID(id)
+	_, err := s.projectRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("プロジェクトが見つかりません")
 	}
 
 	// 権限チェック
-	isOwner, err := s.projectRepo.IsOwner(id, userID)
+	isOwner, err := s.projectRepo.IsOwner(ctx, id, userID)
 	if err != nil || !isOwner {
 		return errors.New("このプロジェクトを削除する権限がありません")
 	}
 
 	// プロジェクトを削除
-	if err := s.projectRepo.Delete(id); err != nil {
+	if err := s.projectRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("プロジェクトの削除に失敗しました: %v", err)
 	}
 
@@ -143,8 +309,8 @@ func (s *projectService) Delete(id, userID uint) error {
 }
 
 // List プロジェクト一覧を取得
-func (s *projectService) List(page, limit int, search string, userID *uint) ([]models.Project, int64, int, error) {
-	projects, total, err := s.projectRepo.List(page, limit, search, userID)
+func (s *projectService) List(ctx context.Context, page, limit int, search string, userID *uint) ([]models.Project, int64, int, error) {
+	projects, total, err := s.projectRepo.List(ctx, page, limit, search, userID)
 	if err != nil {
 		return nil, 0, 0, err
 	}
@@ -159,27 +325,154 @@ func (s *projectService) List(page, limit int, search string, userID *uint) ([]m
 }
 
 // GetMembers プロジェクトのメンバー一覧を取得
-func (s *projectService) GetMembers(projectID uint) ([]models.ProjectMember, error) {
+func (s *projectService) GetMembers(ctx context.Context, projectID uint) ([]models.ProjectMember, error) {
 	// プロジェクトが存在するか確認
-	_, err := s.projectRepo.FindByID(projectID)
+	_, err := s.projectRepo.FindByID(ctx, projectID)
 	if err != nil {
 		return nil, errors.New("プロジェクトが見つかりません")
 	}
 
 	// メンバー一覧を取得
-	return s.projectRepo.GetMembers(projectID)
+	return s.projectRepo.GetMembers(ctx, projectID)
+}
+
+// ExportMembers 出欠や成績の管理のため、メンバーを参加日時の順に提出作品数・最後の活動日時とともに取得（オーナーのみ）
+func (s *projectService) ExportMembers(ctx context.Context, projectID, userID uint) ([]ProjectMemberExport, error) {
+	if _, err := s.projectRepo.FindByID(ctx, projectID); err != nil {
+		return nil, errors.New("プロジェクトが見つかりません")
+	}
+	isOwner, err := s.projectRepo.IsOwner(ctx, projectID, userID)
+	if err != nil || !isOwner {
+		return nil, errors.New("このプロジェクトのメンバーをエクスポートする権限がありません")
+	}
+
+	members, byUser, err := s.memberActivities(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(members, func(i, j int) bool {
+		if !members[i].JoinedAt.Equal(members[j].JoinedAt) {
+			return members[i].JoinedAt.Before(members[j].JoinedAt)
+		}
+		return members[i].UserID < members[j].UserID
+	})
+
+	rows := make([]ProjectMemberExport, 0, len(members))
+	for _, member := range members {
+		row := ProjectMemberExport{Member: member, Role: "member"}
+		if member.IsOwner {
+			row.Role = "owner"
+		}
+		activity := byUser[member.UserID]
+		row.Submissions = activity.Submissions
+		row.LastActivityAt = lastMemberActivity(member, activity)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ListInactiveMembers 整理の候補として、days日以上活動のない（作品の提出・コメント・投票・講評をしていない）メンバーを活動の古い順に取得（オーナーのみ、オーナーは含めない）
+func (s *projectService) ListInactiveMembers(ctx context.Context, projectID, userID uint, days int) ([]InactiveProjectMember, error) {
+	if days <= 0 {
+		days = defaultInactiveMemberDays
+	}
+	if days > maxInactiveMemberDays {
+		return nil, fmt.Errorf("日数は%d日以内で指定してください", maxInactiveMemberDays)
+	}
+
+	if _, err := s.projectRepo.FindByID(ctx, projectID); err != nil {
+		return nil, errors.New("プロジェクトが見つかりません")
+	}
+	isOwner, err := s.projectRepo.IsOwner(ctx, projectID, userID)
+	if err != nil || !isOwner {
+		return nil, errors.New("このプロジェクトのメンバーを管理する権限がありません")
+	}
+
+	members, byUser, err := s.memberActivities(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	threshold := now.AddDate(0, 0, -days)
+	inactive := []InactiveProjectMember{}
+	for _, member := range members {
+		if member.IsOwner {
+			continue
+		}
+		activity := byUser[member.UserID]
+		lastActivityAt := lastMemberActivity(member, activity)
+		since := member.JoinedAt
+		if lastActivityAt != nil {
+			since = *lastActivityAt
+		}
+		if !since.Before(threshold) {
+			continue
+		}
+		inactive = append(inactive, InactiveProjectMember{
+			Member:         member,
+			Submissions:    activity.Submissions,
+			LastActivityAt: lastActivityAt,
+			InactiveDays:   int(now.Sub(since).Hours() / 24),
+		})
+	}
+
+	sort.SliceStable(inactive, func(i, j int) bool {
+		if inactive[i].InactiveDays != inactive[j].InactiveDays {
+			return inactive[i].InactiveDays > inactive[j].InactiveDays
+		}
+		return inactive[i].Member.UserID < inactive[j].Member.UserID
+	})
+	return inactive, nil
+}
+
+// memberActivities メンバー一覧と、ユーザーIDごとの提出作品数・活動日時を取得
+func (s *projectService) memberActivities(ctx context.Context, projectID uint) ([]models.ProjectMember, map[uint]repository.ProjectMemberActivity, error) {
+	members, err := s.projectRepo.GetMembers(ctx, projectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("メンバーの取得に失敗しました: %v", err)
+	}
+	activities, err := s.projectRepo.ListMemberActivity(ctx, projectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("メンバーの活動の集計に失敗しました: %v", err)
+	}
+	byUser := make(map[uint]repository.ProjectMemberActivity, len(activities))
+	for _, activity := range activities {
+		byUser[activity.UserID] = activity
+	}
+	return members, byUser, nil
+}
+
+// lastMemberActivity メンバーに記録された活動日時・最後の提出・アクティビティのうち最後のもの（活動の記録を始める前に参加したメンバーの提出も含める）
+func lastMemberActivity(member models.ProjectMember, activity repository.ProjectMemberActivity) *time.Time {
+	var last *time.Time
+	for _, at := range []*time.Time{member.LastActivityAt, activity.LastSubmittedAt, activity.LastActivityAt} {
+		if at != nil && (last == nil || at.After(*last)) {
+			last = at
+		}
+	}
+	return last
+}
+
+// touchProjectMember メンバーの最後の活動日時を更新
+// 活動自体は完了しているため、エラーはログ出力のみとする
+func touchProjectMember(ctx context.Context, projectRepo repository.ProjectRepository, projectID, userID uint) {
+	if err := projectRepo.TouchMember(ctx, projectID, userID, time.Now()); err != nil {
+		log.Printf("メンバーの活動日時の更新に失敗しました (ProjectID=%d, UserID=%d): %v", projectID, userID, err)
+	}
 }
 
 // AddMember メンバーをプロジェクトに追加
-func (s *projectService) AddMember(projectID, userID uint, isOwner bool) error {
+func (s *projectService) AddMember(ctx context.Context, projectID, userID uint, isOwner bool) error {
 	// プロジェクトが存在するか確認
-	_, err := s.projectRepo.FindByID(projectID)
+	_, err := s.projectRepo.FindByID(ctx, projectID)
 	if err != nil {
 		return errors.New("プロジェクトが見つかりません")
 	}
 
 	// 既にメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(projectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, projectID, userID)
 	if err != nil {
 		return err
 	}
@@ -189,19 +482,19 @@ func (s *projectService) AddMember(projectID, userID uint, isOwner bool) error {
 	}
 
 	// メンバーを追加
-	return s.projectRepo.AddMember(projectID, userID, isOwner)
+	return s.projectRepo.AddMember(ctx, projectID, userID, isOwner)
 }
 
 // RemoveMember メンバーをプロジェクトから削除
-func (s *projectService) RemoveMember(projectID, ownerID, userID uint) error {
+func (s *projectService) RemoveMember(ctx context.Context, projectID, ownerID, userID uint) error {
 	// プロジェクトが存在するか確認
-	_, err := s.projectRepo.FindByID(projectID)
+	_, err := s.projectRepo.FindByID(ctx, projectID)
 	if err != nil {
 		return errors.New("プロジェクトが見つかりません")
 	}
 
 	// 権限チェック
-	isOwner, err := s.projectRepo.IsOwner(projectID, ownerID)
+	isOwner, err := s.projectRepo.IsOwner(ctx, projectID, ownerID)
 	if err != nil || !isOwner {
 		return errors.New("このプロジェクトからメンバーを削除する権限がありません")
 	}
@@ -212,7 +505,7 @@ func (s *projectService) RemoveMember(projectID, ownerID, userID uint) error {
 	}
 
 	// メンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(projectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, projectID, userID)
 	if err != nil {
 		return err
 	}
@@ -222,19 +515,19 @@ func (s *projectService) RemoveMember(projectID, ownerID, userID uint) error {
 	}
 
 	// メンバーを削除
-	return s.projectRepo.RemoveMember(projectID, userID)
+	return s.projectRepo.RemoveMember(ctx, projectID, userID)
 }
 
 // JoinByInvitationCode 招待コードを使用してプロジェクトに参加
-func (s *projectService) JoinByInvitationCode(code string, userID uint) (*models.Project, error) {
+func (s *projectService) JoinByInvitationCode(ctx context.Context, code string, userID uint) (*models.Project, error) {
 	// 招待コードが有効かどうか確認
-	project, err := s.projectRepo.FindByInvitationCode(code)
+	project, err := s.projectRepo.FindByInvitationCode(ctx, code)
 	if err != nil {
 		return nil, errors.New("無効な招待コードです")
 	}
 
 	// 既にメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(project.ID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, project.ID, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -244,23 +537,23 @@ func (s *projectService) JoinByInvitationCode(code string, userID uint) (*models
 	}
 
 	// メンバーとして追加（オーナーではない）
-	if err := s.projectRepo.AddMember(project.ID, userID, false); err != nil {
+	if err := s.projectRepo.AddMember(ctx, project.ID, userID, false); err != nil {
 		return nil, fmt.Errorf("プロジェクトへの参加に失敗しました: %v", err)
 	}
 
-	return s.GetByID(project.ID)
+	return s.GetByID(ctx, project.ID)
 }
 
 // GenerateInvitationCode 新しい招待コードを生成
-func (s *projectService) GenerateInvitationCode(projectID, userID uint) (string, error) {
+func (s *projectService) GenerateInvitationCode(ctx context.Context, projectID, userID uint) (string, error) {
 	// プロジェクトが存在するか確認
-	_, err := s.projectRepo.FindByID(projectID)
+	_, err := s.projectRepo.FindByID(ctx, projectID)
 	if err != nil {
 		return "", errors.New("プロジェクトが見つかりません")
 	}
 
 	// 権限チェック
-	isOwner, err := s.projectRepo.IsOwner(projectID, userID)
+	isOwner, err := s.projectRepo.IsOwner(ctx, projectID, userID)
 	if err != nil || !isOwner {
 		return "", errors.New("招待コードを生成する権限がありません")
 	}
@@ -269,26 +562,64 @@ func (s *projectService) GenerateInvitationCode(projectID, userID uint) (string,
 	code := generateInvitationCode()
 
 	// 招待コードを更新
-	if err := s.projectRepo.UpdateInvitationCode(projectID, code); err != nil {
+	if err := s.projectRepo.UpdateInvitationCode(ctx, projectID, code); err != nil {
 		return "", fmt.Errorf("招待コードの更新に失敗しました: %v", err)
 	}
 
 	return code, nil
 }
 
+// ListByOrganization 組織のプロジェクト一覧を取得（組織のメンバーのみ）
+func (s *projectService) ListByOrganization(ctx context.Context, organizationID, userID uint, page, limit int, search string) ([]models.Project, int64, int, error) {
+	if _, err := s.organizationRepo.FindByID(ctx, organizationID); err != nil {
+		return nil, 0, 0, errors.New("組織が見つかりません")
+	}
+	if _, err := requireOrganizationRole(ctx, s.organizationRepo, organizationID, userID, "この組織のプロジェクトを閲覧する権限がありません", organizationRoles...); err != nil {
+		return nil, 0, 0, err
+	}
+
+	projects, total, err := s.organizationRepo.ListProjects(ctx, organizationID, page, limit, search)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("組織のプロジェクト一覧の取得に失敗しました: %v", err)
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return projects, total, pages, nil
+}
+
+// checkOrganizationProject 組織にプロジェクトを作成できるか確認（組織のメンバーのみ、プロジェクト数の上限まで）
+func (s *projectService) checkOrganizationProject(ctx context.Context, organizationID, userID uint) error {
+	organization, err := s.organizationRepo.FindByID(ctx, organizationID)
+	if err != nil {
+		return errors.New("組織が見つかりません")
+	}
+	if _, err := requireOrganizationRole(ctx, s.organizationRepo, organizationID, userID, "この組織にプロジェクトを作成する権限がありません", organizationRoles...); err != nil {
+		return err
+	}
+	if organization.MaxProjects > 0 && organization.ProjectsCount >= int64(organization.MaxProjects) {
+		return fmt.Errorf("組織のプロジェクト数が上限（%d件）に達しています", organization.MaxProjects)
+	}
+	return nil
+}
+
 // IsUserAllowed ユーザーがプロジェクトにアクセスできるか確認
-func (s *projectService) IsUserAllowed(projectID, userID uint) (bool, error) {
-	return s.projectRepo.IsMember(projectID, userID)
+func (s *projectService) IsUserAllowed(ctx context.Context, projectID, userID uint) (bool, error) {
+	return s.projectRepo.IsMember(ctx, projectID, userID)
 }
 
 // IsOwner ユーザーがプロジェクトのオーナーかどうか確認
-func (s *projectService) IsOwner(projectID, userID uint) (bool, error) {
-	return s.projectRepo.IsOwner(projectID, userID)
+func (s *projectService) IsOwner(ctx context.Context, projectID, userID uint) (bool, error) {
+	return s.projectRepo.IsOwner(ctx, projectID, userID)
 }
 
 // GetUserProjects ユーザーが参加しているプロジェクト一覧を取得
-func (s *projectService) GetUserProjects(userID uint, page, limit int) ([]models.Project, int64, int, error) {
-	projects, total, err := s.projectRepo.GetUserProjects(userID, page, limit)
+func (s *projectService) GetUserProjects(ctx context.Context, userID uint, page, limit int) ([]models.Project, int64, int, error) {
+	projects, total, err := s.projectRepo.GetUserProjects(ctx, userID, page, limit)
 	if err != nil {
 		return nil, 0, 0, err
 	}
@@ -317,3 +648,248 @@ func generateInvitationCode() string {
 
 	return string(code)
 }
+
+// GetDashboard プロジェクトの集計値・最近のアクティビティ・締め切りをまとめて取得
+func (s *projectService) GetDashboard(ctx context.Context, projectID, userID uint) (*ProjectDashboard, error) {
+	// プロジェクトが存在するか確認
+	if _, err := s.projectRepo.FindByID(ctx, projectID); err != nil {
+		return nil, errors.New("プロジェクトが見つかりません")
+	}
+
+	// メンバーか確認
+	isMember, err := s.projectRepo.IsMember(ctx, projectID, userID)
+	if err != nil || !isMember {
+		return nil, errors.New("このプロジェクトにアクセスする権限がありません")
+	}
+
+	counts, err := s.projectRepo.CountDashboard(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	activities, err := s.activityRepo.ListByProject(ctx, projectID, dashboardActivityLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	deadlines, err := s.projectRepo.ListUpcomingVotes(ctx, projectID, dashboardDeadlineLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProjectDashboard{
+		Counts:            counts,
+		RecentActivity:    activities,
+		UpcomingDeadlines: deadlines,
+	}, nil
+}
+
+// CreateContest 誰でも参加登録できるコンテストを作成
+// 応募を受け付けるタスクを合わせて作成する
+func (s *projectService) CreateContest(ctx context.Context, title, description string, userID uint) (*models.Project, error) {
+	project, err := s.Create(ctx, title, description, userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &models.Task{
+		Title:     contestTaskTitle,
+		ProjectID: project.ID,
+	}
+	if err := s.taskRepo.Create(ctx, task); err != nil {
+		return nil, fmt.Errorf("応募タスクの作成に失敗しました: %v", err)
+	}
+
+	project.IsContest = true
+	project.ContestPhase = models.ContestPhaseOpen
+	project.ContestTaskID = &task.ID
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		return nil, fmt.Errorf("コンテストの作成に失敗しました: %v", err)
+	}
+
+	return s.GetByID(ctx, project.ID)
+}
+
+// ListContests コンテスト一覧を取得（認証不要）
+func (s *projectService) ListContests(ctx context.Context, page, limit int, phase string) ([]models.Project, int64, int, error) {
+	projects, total, err := s.projectRepo.ListContests(ctx, page, limit, phase)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	for i := range projects {
+		projects[i].InvitationCode = ""
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return projects, total, pages, nil
+}
+
+// GetContest コンテストを取得（認証不要のため招待コードは返さない）
+func (s *projectService) GetContest(ctx context.Context, id uint) (*models.Project, error) {
+	project, err := s.findContest(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	project.InvitationCode = ""
+	return project, nil
+}
+
+// findContest コンテストのプロジェクトを取得
+func (s *projectService) findContest(ctx context.Context, id uint) (*models.Project, error) {
+	project, err := s.projectRepo.FindByID(ctx, id)
+	if err != nil || !project.IsContest {
+		return nil, errors.New("コンテストが見つかりません")
+	}
+	return project, nil
+}
+
+// RegisterForContest コンテストに参加登録（招待コード不要）
+func (s *projectService) RegisterForContest(ctx context.Context, projectID, userID uint) (*models.Project, error) {
+	project, err := s.findContest(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if project.ContestPhase == models.ContestPhaseFinished {
+		return nil, errors.New("このコンテストは終了しています")
+	}
+
+	// 既にメンバーかどうか確認
+	isMember, err := s.projectRepo.IsMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if isMember {
+		return nil, errors.New("あなたは既にこのコンテストに参加しています")
+	}
+
+	if err := s.projectRepo.AddMember(ctx, projectID, userID, false); err != nil {
+		return nil, fmt.Errorf("コンテストへの参加に失敗しました: %v", err)
+	}
+
+	return s.GetByID(ctx, projectID)
+}
+
+// StartJudging 応募を締め切って審査を開始し、応募作品を選択肢とする投票を作成
+func (s *projectService) StartJudging(ctx context.Context, projectID, userID uint, closesAt *time.Time) (*models.Vote, error) {
+	project, err := s.findContest(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 権限チェック
+	isOwner, err := s.projectRepo.IsOwner(ctx, projectID, userID)
+	if err != nil || !isOwner {
+		return nil, errors.New("審査を開始する権限がありません")
+	}
+
+	if project.ContestPhase != models.ContestPhaseOpen || project.ContestTaskID == nil {
+		return nil, errors.New("このコンテストは既に審査を開始しています")
+	}
+
+	// 締め切りのバリデーション
+	if closesAt != nil && closesAt.Before(time.Now()) {
+		return nil, errors.New("締め切りは未来の日時を指定してください")
+	}
+
+	// 応募作品を全て取得
+	var entries []models.Work
+	for page := 1; ; page++ {
+		works, total, err := s.taskRepo.GetWorks(ctx, *project.ContestTaskID, page, contestEntryBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, works...)
+		if len(works) == 0 || int64(len(entries)) >= total {
+			break
+		}
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("応募作品がないため審査を開始できません")
+	}
+
+	// 審査の投票を作成
+	vote := &models.Vote{
+		Title:       project.Title + " 審査",
+		Description: "応募作品の中から優れた作品に投票してください。",
+		TaskID:      *project.ContestTaskID,
+		IsActive:    true,
+		CreatedBy:   userID,
+		ClosesAt:    closesAt,
+	}
+	if err := s.voteRepo.Create(ctx, vote); err != nil {
+		return nil, fmt.Errorf("投票の作成に失敗しました: %v", err)
+	}
+
+	// 応募順に選択肢を作成
+	for i := len(entries) - 1; i >= 0; i-- {
+		workID := entries[i].ID
+		option := &models.VoteOption{
+			VoteID:     vote.ID,
+			OptionText: entries[i].Title,
+			WorkID:     &workID,
+		}
+		if err := s.voteRepo.CreateOption(ctx, option); err != nil {
+			return nil, fmt.Errorf("投票オプションの作成に失敗しました: %v", err)
+		}
+	}
+
+	project.ContestPhase = models.ContestPhaseJudging
+	project.ContestVoteID = &vote.ID
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		return nil, fmt.Errorf("コンテストの更新に失敗しました: %v", err)
+	}
+
+	return s.voteRepo.FindByID(ctx, vote.ID)
+}
+
+// GetContestResults 審査の投票結果を順位付きで取得（認証不要、結果発表後のみ）
+func (s *projectService) GetContestResults(ctx context.Context, projectID uint) (*ContestResults, error) {
+	project, err := s.GetContest(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if project.ContestPhase != models.ContestPhaseFinished || project.ContestVoteID == nil {
+		return nil, errors.New("結果はまだ発表されていません")
+	}
+
+	vote, err := s.voteRepo.FindByID(ctx, *project.ContestVoteID)
+	if err != nil {
+		return nil, errors.New("審査の投票が見つかりません")
+	}
+
+	options, err := s.voteRepo.GetOptions(ctx, vote.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 得票数の多い順（同票は応募順）
+	sort.SliceStable(options, func(i, j int) bool {
+		return options[i].VoteCount > options[j].VoteCount
+	})
+
+	entries := make([]ContestEntry, 0, len(options))
+	for i, option := range options {
+		rank := i + 1
+		if i > 0 && option.VoteCount == options[i-1].VoteCount {
+			rank = entries[i-1].Rank
+		}
+		entries = append(entries, ContestEntry{
+			Rank:      rank,
+			VoteCount: option.VoteCount,
+			Work:      option.Work,
+		})
+	}
+
+	return &ContestResults{
+		Project: project,
+		Vote:    vote,
+		Entries: entries,
+	}, nil
+}
@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// PublicService 匿名の利用者向けの公開API（読み取り専用）に関するサービスインターフェース
+type PublicService interface {
+	ListWorks(ctx context.Context, page, limit int, tag, sort string) ([]PublicWork, int64, int, error)
+	GetWork(ctx context.Context, id uint) (*PublicWork, error)
+	ListTags(ctx context.Context, limit int) ([]models.Tag, error)
+	Embed(ctx context.Context, id uint, maxWidth, maxHeight int) (*EmbedMetadata, error)
+}
+
+const (
+	// キャッシュするレスポンスの最大数（超えた場合はまとめて破棄する）
+	maxPublicCacheEntries = 1000
+	// 埋め込みの既定の大きさ
+	defaultEmbedWidth  = 640
+	defaultEmbedHeight = 480
+)
+
+// PublicAuthor 公開APIで返す作者
+type PublicAuthor struct {
+	ID       uint    `json:"id"`
+	Nickname string  `json:"nickname"`
+	Handle   *string `json:"handle,omitempty"`
+}
+
+// PublicWork 公開APIで返す作品（メールアドレスなどの内部の項目を含まない）
+type PublicWork struct {
+	ID                uint         `json:"id"`
+	Title             string       `json:"title"`
+	Description       string       `json:"description"`
+	DescriptionHTML   string       `json:"description_html"`
+	ThumbnailURL      string       `json:"thumbnail_url,omitempty"`
+	ThumbnailBlurhash string       `json:"thumbnail_blurhash,omitempty"`
+	JSContent         string       `json:"js_content,omitempty"`  // 詳細のみ
+	PDEContent        string       `json:"pde_content,omitempty"` // 詳細で、コードが公開されている場合のみ
+	License           string       `json:"license"`
+	ForkedFromID      *uint        `json:"forked_from_id,omitempty"`
+	Tags              []string     `json:"tags"`
+	Author            PublicAuthor `json:"author"`
+	Views             int          `json:"views"`
+	LikesCount        int64        `json:"likes_count"`
+	CommentsCount     int64        `json:"comments_count"`
+	URL               string       `json:"url"` // 作品ページ（フロントエンド）のURL
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+// EmbedMetadata 作品を埋め込むためのメタデータ（oEmbedのrich形式）
+type EmbedMetadata struct {
+	Type         string `json:"type"`
+	Version      string `json:"version"`
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	ProviderName string `json:"provider_name"`
+	ProviderURL  string `json:"provider_url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+	HTML         string `json:"html"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	CacheAge     int    `json:"cache_age"` // 秒
+}
+
+// publicWorkPage キャッシュする作品一覧の1ページ
+type publicWorkPage struct {
+	works []PublicWork
+	total int64
+	pages int
+}
+
+// publicService PublicServiceの実装
+type publicService struct {
+	workRepo repository.WorkRepository
+	tagRepo  repository.TagRepository
+	config   *config.Config
+
+	mu        sync.RWMutex
+	entries   map[string]interface{} // キーは取得条件
+	expiresAt time.Time
+}
+
+// NewPublicService PublicServiceを作成
+func NewPublicService(workRepo repository.WorkRepository, tagRepo repository.TagRepository, cfg *config.Config) PublicService {
+	return &publicService{
+		workRepo: workRepo,
+		tagRepo:  tagRepo,
+		config:   cfg,
+		entries:  map[string]interface{}{},
+	}
+}
+
+// ListWorks 公開されている作品の一覧を取得（コードは含まない）
+func (s *publicService) ListWorks(ctx context.Context, page, limit int, tag, sort string) ([]PublicWork, int64, int, error) {
+	key := fmt.Sprintf("works:%d:%d:%s:%s", page, limit, tag, sort)
+	if cached, ok := s.cached(key); ok {
+		result := cached.(publicWorkPage)
+		return result.works, result.total, result.pages, nil
+	}
+
+	works, total, err := s.workRepo.List(ctx, page, limit, "", tag, "", "", nil, sort)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	items := make([]PublicWork, 0, len(works))
+	for i := range works {
+		items = append(items, s.toPublicWork(&works[i], false))
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	s.store(key, publicWorkPage{works: items, total: total, pages: pages})
+	return items, total, pages, nil
+}
+
+// GetWork 公開されている作品の詳細を取得（閲覧数は増やさない）
+func (s *publicService) GetWork(ctx context.Context, id uint) (*PublicWork, error) {
+	key := fmt.Sprintf("work:%d", id)
+	if cached, ok := s.cached(key); ok {
+		work := cached.(PublicWork)
+		return &work, nil
+	}
+
+	work, err := s.findWork(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	item := s.toPublicWork(work, true)
+	s.store(key, item)
+	return &item, nil
+}
+
+// ListTags タグ一覧を取得
+func (s *publicService) ListTags(ctx context.Context, limit int) ([]models.Tag, error) {
+	key := fmt.Sprintf("tags:%d", limit)
+	if cached, ok := s.cached(key); ok {
+		return cached.([]models.Tag), nil
+	}
+
+	tags, err := s.tagRepo.List(ctx, "", limit)
+	if err != nil {
+		return nil, err
+	}
+
+	s.store(key, tags)
+	return tags, nil
+}
+
+// Embed 作品を埋め込むためのメタデータを取得
+// maxWidth・maxHeight（0の場合は指定なし）に収まるよう、既定の縦横比のまま縮小する
+func (s *publicService) Embed(ctx context.Context, id uint, maxWidth, maxHeight int) (*EmbedMetadata, error) {
+	work, err := s.GetWork(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := defaultEmbedWidth, defaultEmbedHeight
+	if maxWidth > 0 && width > maxWidth {
+		height = height * maxWidth / width
+		width = maxWidth
+	}
+	if maxHeight > 0 && height > maxHeight {
+		width = width * maxHeight / height
+		height = maxHeight
+	}
+
+	src := fmt.Sprintf("%s/works/%d/embed", s.siteURL(), work.ID)
+	return &EmbedMetadata{
+		Type:         "rich",
+		Version:      "1.0",
+		Title:        work.Title,
+		AuthorName:   work.Author.Nickname,
+		ProviderName: "SketchShifter",
+		ProviderURL:  s.siteURL(),
+		ThumbnailURL: work.ThumbnailURL,
+		HTML: fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" title="%s" frameborder="0" allowfullscreen></iframe>`,
+			html.EscapeString(src), width, height, html.EscapeString(work.Title)),
+		Width:    width,
+		Height:   height,
+		CacheAge: int(s.config.PublicAPI.CacheTTL.Seconds()),
+	}, nil
+}
+
+// findWork 公開されている作品を取得（通報により非表示の作品は見つからない扱いにする）
+func (s *publicService) findWork(ctx context.Context, id uint) (*models.Work, error) {
+	work, err := s.workRepo.FindByID(ctx, id)
+	if err != nil || work.HiddenAt != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	return work, nil
+}
+
+// toPublicWork 公開APIで返す形式に変換（detailがfalseの場合はコードを含めない）
+func (s *publicService) toPublicWork(work *models.Work, detail bool) PublicWork {
+	tags := make([]string, 0, len(work.Tags))
+	for _, tag := range work.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	item := PublicWork{
+		ID:                work.ID,
+		Title:             work.Title,
+		Description:       work.Description,
+		DescriptionHTML:   work.DescriptionHTML,
+		ThumbnailURL:      work.ThumbnailURL,
+		ThumbnailBlurhash: work.ThumbnailBlurhash,
+		License:           work.License,
+		ForkedFromID:      work.ForkedFromID,
+		Tags:              tags,
+		Author: PublicAuthor{
+			ID:       work.User.ID,
+			Nickname: work.User.Nickname,
+			Handle:   work.User.Handle,
+		},
+		Views:         work.Views,
+		LikesCount:    work.LikesCount,
+		CommentsCount: work.CommentsCount,
+		URL:           fmt.Sprintf("%s/works/%d", s.siteURL(), work.ID),
+		CreatedAt:     work.CreatedAt,
+		UpdatedAt:     work.UpdatedAt,
+	}
+	if detail {
+		item.JSContent = work.JSContent
+		if work.CodeShared {
+			item.PDEContent = work.PDEContent
+		}
+	}
+	return item
+}
+
+// siteURL 作品ページ（フロントエンド）のURLの起点
+func (s *publicService) siteURL() string {
+	return strings.TrimSuffix(s.config.Sitemap.SiteURL, "/")
+}
+
+// cached キャッシュされたレスポンスを取得
+func (s *publicService) cached(key string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if time.Now().After(s.expiresAt) {
+		return nil, false
+	}
+	value, ok := s.entries[key]
+	return value, ok
+}
+
+// store レスポンスをキャッシュ（CacheTTLが0以下の場合はキャッシュしない）
+func (s *publicService) store(key string, value interface{}) {
+	ttl := s.config.PublicAPI.CacheTTL
+	if ttl <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 期限切れか件数が上限に達した場合はまとめて破棄してから保存する
+	if time.Now().After(s.expiresAt) || len(s.entries) >= maxPublicCacheEntries {
+		s.entries = map[string]interface{}{}
+		s.expiresAt = time.Now().Add(ttl)
+	}
+	s.entries[key] = value
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// PurgeService 削除依頼に応じてユーザーと作品を完全に削除するサービスインターフェース（管理者用）
+type PurgeService interface {
+	PurgeUser(ctx context.Context, adminID, userID uint, reason string) (*PurgeSummary, error)
+	PurgeWork(ctx context.Context, adminID, workID uint, reason string) (*PurgeSummary, error)
+	ListAuditLogs(ctx context.Context, action string, page, limit int) ([]models.AuditLog, int64, int, error)
+}
+
+// PurgeSummary 完全に削除した結果
+type PurgeSummary struct {
+	AuditLog       *models.AuditLog `json:"audit_log"`
+	Rows           map[string]int64 `json:"rows"`            // テーブルごとの削除件数
+	StorageObjects int              `json:"storage_objects"` // 削除したストレージのデータとCloudinaryの画像
+	StorageErrors  int              `json:"storage_errors"`  // 削除に失敗したデータ（ログに出力する）
+}
+
+// purgeService PurgeServiceの実装
+type purgeService struct {
+	purgeRepo      repository.PurgeRepository
+	auditLogRepo   repository.AuditLogRepository
+	storage        StorageService
+	cloudinary     CloudinaryService
+	sitemapService SitemapService
+	config         *config.Config
+}
+
+// NewPurgeService PurgeServiceを作成（cloudinaryがnilの場合はCloudinaryの画像を削除しない）
+func NewPurgeService(
+	purgeRepo repository.PurgeRepository,
+	auditLogRepo repository.AuditLogRepository,
+	storage StorageService,
+	cloudinary CloudinaryService,
+	sitemapService SitemapService,
+	cfg *config.Config,
+) PurgeService {
+	return &purgeService{
+		purgeRepo:      purgeRepo,
+		auditLogRepo:   auditLogRepo,
+		storage:        storage,
+		cloudinary:     cloudinary,
+		sitemapService: sitemapService,
+		config:         cfg,
+	}
+}
+
+// PurgeUser ユーザーと、ユーザーの作品・コメント・メッセージなどを完全に削除（元に戻せない）
+func (s *purgeService) PurgeUser(ctx context.Context, adminID, userID uint, reason string) (*PurgeSummary, error) {
+	if userID == adminID {
+		return nil, errors.New("自分のアカウントは完全に削除できません")
+	}
+	auditLog, err := newPurgeAuditLog(models.AuditActionPurgeUser, models.AuditTargetUser, userID, adminID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.purgeRepo.PurgeUser(ctx, userID, auditLog)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("ユーザーが見つかりません")
+	}
+	if errors.Is(err, repository.ErrPurgeOwner) {
+		return nil, fmt.Errorf("%v（オーナーを変更するか、先に削除してください）", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ユーザーの完全な削除に失敗しました: %v", err)
+	}
+
+	return s.finish(ctx, auditLog, result), nil
+}
+
+// PurgeWork 作品と、作品へのコメント・いいね・アセットなどを完全に削除（元に戻せない）
+func (s *purgeService) PurgeWork(ctx context.Context, adminID, workID uint, reason string) (*PurgeSummary, error) {
+	auditLog, err := newPurgeAuditLog(models.AuditActionPurgeWork, models.AuditTargetWork, workID, adminID, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.purgeRepo.PurgeWork(ctx, workID, auditLog)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("作品が見つかりません")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("作品の完全な削除に失敗しました: %v", err)
+	}
+
+	return s.finish(ctx, auditLog, result), nil
+}
+
+// ListAuditLogs 監査ログを新しい順に取得
+func (s *purgeService) ListAuditLogs(ctx context.Context, action string, page, limit int) ([]models.AuditLog, int64, int, error) {
+	logs, total, err := s.auditLogRepo.List(ctx, action, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return logs, total, pages, nil
+}
+
+// finish データベースから削除した後に、ストレージのデータとCloudinaryの画像を削除
+// データベースの削除は確定しているため、失敗した場合はログに出力して続ける
+func (s *purgeService) finish(ctx context.Context, auditLog *models.AuditLog, result *repository.PurgeResult) *PurgeSummary {
+	summary := &PurgeSummary{AuditLog: auditLog, Rows: result.Rows}
+
+	for _, key := range result.StorageKeys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			summary.StorageErrors++
+			log.Printf("完全に削除したデータ %s の削除に失敗しました (AuditLogID=%d): %v", key, auditLog.ID, err)
+			continue
+		}
+		summary.StorageObjects++
+	}
+
+	// ストレージに移した作品のコードも削除する（移していない作品は何もしない）
+	for _, workID := range result.WorkIDs {
+		key := archiveKey(s.config, workID)
+		if err := s.storage.Delete(ctx, key); err != nil {
+			summary.StorageErrors++
+			log.Printf("完全に削除した作品のコード %s の削除に失敗しました (AuditLogID=%d): %v", key, auditLog.ID, err)
+		}
+	}
+
+	if s.cloudinary != nil {
+		for _, publicID := range result.ImagePublicIDs {
+			if publicID == "" {
+				continue
+			}
+			if err := s.cloudinary.DeleteImage(ctx, publicID); err != nil {
+				summary.StorageErrors++
+				log.Printf("完全に削除した画像 %s の削除に失敗しました (AuditLogID=%d): %v", publicID, auditLog.ID, err)
+				continue
+			}
+			summary.StorageObjects++
+		}
+	}
+
+	if len(result.WorkIDs) > 0 {
+		s.sitemapService.Invalidate()
+	}
+	return summary
+}
+
+// newPurgeAuditLog 完全な削除の監査ログを作成（理由は必須）
+func newPurgeAuditLog(action, targetType string, targetID, adminID uint, reason string) (*models.AuditLog, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("削除の理由（削除依頼の受付番号など）を指定してください")
+	}
+	return &models.AuditLog{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		ActorID:    adminID,
+		Reason:     reason,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// rankingBatchSize 人気スコアを計算するために1回に読み込む作品数
+const rankingBatchSize = 1000
+
+// RankingService 人気順（sort=popular）に使う作品の人気スコアを計算するサービスインターフェース
+type RankingService interface {
+	Run(ctx context.Context) (int, error)
+}
+
+// rankingService RankingServiceの実装
+type rankingService struct {
+	rankingRepo repository.RankingRepository
+	config      *config.Config
+}
+
+// NewRankingService RankingServiceを作成
+func NewRankingService(rankingRepo repository.RankingRepository, cfg *config.Config) RankingService {
+	return &rankingService{
+		rankingRepo: rankingRepo,
+		config:      cfg,
+	}
+}
+
+// Run 公開中のすべての作品の人気スコアを計算し直し、計算した作品数を返す（スケジューラから定期実行）
+func (s *rankingService) Run(ctx context.Context) (int, error) {
+	// 保存時に丸められても古いスコアと区別できるよう秒単位にそろえる
+	now := time.Now().Truncate(time.Second)
+
+	rankings := []models.WorkRanking{}
+	var afterID uint
+	for {
+		works, err := s.rankingRepo.ListSources(ctx, afterID, rankingBatchSize)
+		if err != nil {
+			return 0, err
+		}
+		for i := range works {
+			rankings = append(rankings, models.WorkRanking{
+				WorkID:     works[i].ID,
+				Score:      s.score(&works[i], now),
+				ComputedAt: now,
+			})
+		}
+		if len(works) < rankingBatchSize {
+			break
+		}
+		afterID = works[len(works)-1].ID
+	}
+
+	if err := s.rankingRepo.Replace(ctx, rankings, now); err != nil {
+		return 0, err
+	}
+	return len(rankings), nil
+}
+
+// score 閲覧数・いいね数・コメント数の重み付きの合計を、公開からの経過日数に応じて半減期で減衰させる
+func (s *rankingService) score(work *models.Work, now time.Time) float64 {
+	cfg := s.config.Ranking
+	score := float64(work.Views)*cfg.ViewWeight +
+		float64(work.LikesCount)*cfg.LikeWeight +
+		float64(work.CommentsCount)*cfg.CommentWeight
+
+	if cfg.HalfLifeDays > 0 {
+		ageDays := now.Sub(work.CreatedAt).Hours() / 24
+		if ageDays > 0 {
+			score *= math.Pow(0.5, ageDays/cfg.HalfLifeDays)
+		}
+	}
+	return score
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// 1バッチあたりの再変換件数
+const (
+	defaultReconversionBatchSize = 20
+	maxReconversionBatchSize     = 100
+)
+
+// ReconversionService 古い変換バージョンの作品を再変換するサービスインターフェース
+type ReconversionService interface {
+	Start(ctx context.Context, userID uint, batchSize int) (*models.ReconversionCampaign, error)
+	GetByID(ctx context.Context, id uint) (*models.ReconversionCampaign, error)
+	List(ctx context.Context, page, limit int) ([]models.ReconversionCampaign, int64, int, error)
+	Cancel(ctx context.Context, id uint) (*models.ReconversionCampaign, error)
+	ProcessBatches(ctx context.Context) (int, error)
+}
+
+// reconversionService ReconversionServiceの実装
+type reconversionService struct {
+	reconversionRepo repository.ReconversionRepository
+	workRepo         repository.WorkRepository
+	lambdaService    LambdaService
+	conversionQueue  ConversionQueue
+	jsValidator      JSValidationService
+}
+
+// NewReconversionService ReconversionServiceを作成
+func NewReconversionService(
+	reconversionRepo repository.ReconversionRepository,
+	workRepo repository.WorkRepository,
+	lambdaService LambdaService,
+	conversionQueue ConversionQueue,
+	jsValidator JSValidationService,
+) ReconversionService {
+	return &reconversionService{
+		reconversionRepo: reconversionRepo,
+		workRepo:         workRepo,
+		lambdaService:    lambdaService,
+		conversionQueue:  conversionQueue,
+		jsValidator:      jsValidator,
+	}
+}
+
+// Start 現在の変換バージョンへの再変換キャンペーンを開始
+func (s *reconversionService) Start(ctx context.Context, userID uint, batchSize int) (*models.ReconversionCampaign, error) {
+	if batchSize <= 0 {
+		batchSize = defaultReconversionBatchSize
+	}
+	if batchSize > maxReconversionBatchSize {
+		return nil, fmt.Errorf("バッチサイズは%d以下で指定してください", maxReconversionBatchSize)
+	}
+
+	// 同時に複数のキャンペーンは実行しない
+	running, err := s.reconversionRepo.ListRunning(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(running) > 0 {
+		return nil, fmt.Errorf("実行中の再変換キャンペーンがあります (ID=%d)", running[0].ID)
+	}
+
+	version := s.lambdaService.ConverterVersion()
+	total, err := s.workRepo.CountOutdatedConversions(ctx, version)
+	if err != nil {
+		return nil, fmt.Errorf("対象作品数の取得に失敗しました: %v", err)
+	}
+
+	campaign := &models.ReconversionCampaign{
+		TargetVersion: version,
+		Status:        models.ReconversionStatusRunning,
+		BatchSize:     batchSize,
+		Total:         int(total),
+		CreatedBy:     userID,
+	}
+
+	// 対象がなければ即完了
+	if total == 0 {
+		now := time.Now()
+		campaign.Status = models.ReconversionStatusCompleted
+		campaign.CompletedAt = &now
+	}
+
+	if err := s.reconversionRepo.Create(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("再変換キャンペーンの作成に失敗しました: %v", err)
+	}
+
+	return campaign, nil
+}
+
+// GetByID IDでキャンペーンを取得
+func (s *reconversionService) GetByID(ctx context.Context, id uint) (*models.ReconversionCampaign, error) {
+	campaign, err := s.reconversionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("再変換キャンペーンが見つかりません")
+	}
+	return campaign, nil
+}
+
+// List キャンペーン一覧を取得
+func (s *reconversionService) List(ctx context.Context, page, limit int) ([]models.ReconversionCampaign, int64, int, error) {
+	campaigns, total, err := s.reconversionRepo.List(ctx, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return campaigns, total, pages, nil
+}
+
+// Cancel 実行中のキャンペーンを中止
+func (s *reconversionService) Cancel(ctx context.Context, id uint) (*models.ReconversionCampaign, error) {
+	campaign, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.Status != models.ReconversionStatusRunning {
+		return nil, errors.New("実行中のキャンペーンではありません")
+	}
+
+	now := time.Now()
+	campaign.Status = models.ReconversionStatusCancelled
+	campaign.CompletedAt = &now
+	if err := s.reconversionRepo.Update(ctx, campaign); err != nil {
+		return nil, fmt.Errorf("再変換キャンペーンの更新に失敗しました: %v", err)
+	}
+
+	return campaign, nil
+}
+
+// ProcessBatches 実行中のキャンペーンを1バッチずつ進める（スケジューラから定期実行）
+func (s *reconversionService) ProcessBatches(ctx context.Context) (int, error) {
+	campaigns, err := s.reconversionRepo.ListRunning(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for i := range campaigns {
+		n, err := s.processBatch(ctx, &campaigns[i])
+		processed += n
+		if err != nil {
+			return processed, err
+		}
+	}
+
+	return processed, nil
+}
+
+// processBatch キャンペーンの次のバッチを処理
+func (s *reconversionService) processBatch(ctx context.Context, campaign *models.ReconversionCampaign) (int, error) {
+	now := time.Now()
+
+	// 途中で変換バージョンが切り替わった場合は中止する
+	if s.lambdaService.ConverterVersion() != campaign.TargetVersion {
+		campaign.Status = models.ReconversionStatusCancelled
+		campaign.LastError = "変換バージョンが変更されたため中止しました"
+		campaign.CompletedAt = &now
+		return 0, s.reconversionRepo.Update(ctx, campaign)
+	}
+
+	works, err := s.workRepo.ListOutdatedConversions(ctx, campaign.TargetVersion, campaign.LastWorkID, campaign.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	interrupted := false
+	for i := range works {
+		work := &works[i]
+		output, err := s.conversionQueue.Convert(ctx, ConversionPriorityBatch, work.PDEContent)
+		// Lambdaへの呼び出しを遮断している間は失敗に数えず、残りを次回に回す
+		if errors.Is(err, ErrCircuitOpen) {
+			interrupted = true
+			break
+		}
+		campaign.LastWorkID = work.ID
+		campaign.Processed++
+		processed++
+
+		if err == nil {
+			if result := s.jsValidator.Apply(work, output, campaign.TargetVersion); result.Status == JSValidationRejected {
+				err = fmt.Errorf("変換後のJSが検証で拒否されました: %s", strings.Join(result.Issues, ", "))
+			}
+			if updateErr := s.workRepo.UpdateConversion(ctx, work); updateErr != nil {
+				err = updateErr
+			}
+		}
+		if err != nil {
+			campaign.Failed++
+			campaign.LastError = fmt.Sprintf("作品ID=%d: %v", work.ID, err)
+			log.Printf("再変換に失敗しました (CampaignID=%d, WorkID=%d): %v", campaign.ID, work.ID, err)
+			continue
+		}
+		campaign.Succeeded++
+	}
+
+	// 最後のバッチであれば完了
+	if !interrupted && len(works) < campaign.BatchSize {
+		campaign.Status = models.ReconversionStatusCompleted
+		campaign.CompletedAt = &now
+	}
+
+	if err := s.reconversionRepo.Update(ctx, campaign); err != nil {
+		return processed, err
+	}
+
+	return processed, nil
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+)
+
+// RegistrationService 新規登録の受け付け方（招待制・順番待ち）に関するサービスインターフェース
+type RegistrationService interface {
+	Status() *RegistrationStatus
+	Redeem(ctx context.Context, code, email string) (*models.InviteCode, error)
+	Release(ctx context.Context, code *models.InviteCode)
+	JoinWaitlist(ctx context.Context, email, name, message string) (*models.WaitlistEntry, error)
+	ListWaitlist(ctx context.Context, status string, page, limit int) ([]models.WaitlistEntry, int64, int, error)
+	ApproveWaitlist(ctx context.Context, id, adminID uint) (*models.WaitlistEntry, error)
+	DeleteWaitlist(ctx context.Context, id uint) error
+	CreateInviteCode(ctx context.Context, adminID uint, maxUses int, expiresAt *time.Time, note string) (*models.InviteCode, error)
+	ListInviteCodes(ctx context.Context, page, limit int) ([]models.InviteCode, int64, int, error)
+	DeleteInviteCode(ctx context.Context, id uint) error
+}
+
+// RegistrationStatus 新規登録の受け付け状況（登録画面の表示の切り替えに使う）
+type RegistrationStatus struct {
+	Mode               string `json:"mode"`
+	InviteCodeRequired bool   `json:"invite_code_required"`
+	WaitlistOpen       bool   `json:"waitlist_open"`
+}
+
+const (
+	// 招待コードの長さ（16進数の文字数）
+	inviteCodeLength = 12
+	// 1つの招待コードで登録できる人数の上限
+	maxInviteCodeUses = 1000
+)
+
+// registrationService RegistrationServiceの実装
+type registrationService struct {
+	inviteCodeRepo repository.InviteCodeRepository
+	waitlistRepo   repository.WaitlistRepository
+	userRepo       repository.UserRepository
+	mailService    MailService
+	mailTemplates  MailTemplateService
+	config         *config.Config
+}
+
+// NewRegistrationService RegistrationServiceを作成
+func NewRegistrationService(
+	inviteCodeRepo repository.InviteCodeRepository,
+	waitlistRepo repository.WaitlistRepository,
+	userRepo repository.UserRepository,
+	mailService MailService,
+	mailTemplates MailTemplateService,
+	cfg *config.Config,
+) RegistrationService {
+	return &registrationService{
+		inviteCodeRepo: inviteCodeRepo,
+		waitlistRepo:   waitlistRepo,
+		userRepo:       userRepo,
+		mailService:    mailService,
+		mailTemplates:  mailTemplates,
+		config:         cfg,
+	}
+}
+
+// Status 新規登録の受け付け状況を取得
+func (s *registrationService) Status() *RegistrationStatus {
+	mode := s.config.Registration.Mode
+	return &RegistrationStatus{
+		Mode:               mode,
+		InviteCodeRequired: mode == config.RegistrationModeInvite,
+		WaitlistOpen:       mode != config.RegistrationModeOpen,
+	}
+}
+
+// Redeem 新規登録の前に受け付けているか確認し、招待制の場合は招待コードを使用する
+// 誰でも登録できる場合はnilを返す（登録に失敗した場合はReleaseで使用回数を戻すこと）
+func (s *registrationService) Redeem(ctx context.Context, code, email string) (*models.InviteCode, error) {
+	switch s.config.Registration.Mode {
+	case config.RegistrationModeOpen:
+		return nil, nil
+	case config.RegistrationModeClosed:
+		return nil, errors.New("現在は新規登録を受け付けていません")
+	}
+
+	code = normalizeInviteCode(code)
+	if code == "" {
+		return nil, errors.New("招待コードが必要です")
+	}
+	inviteCode, err := s.inviteCodeRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, errors.New("招待コードが正しくありません")
+	}
+	if inviteCode.Email != "" && !strings.EqualFold(inviteCode.Email, email) {
+		return nil, errors.New("この招待コードは招待されたメールアドレスでのみ使用できます")
+	}
+	if err := s.inviteCodeRepo.Use(ctx, inviteCode.ID, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrInviteCodeUnavailable) {
+			return nil, errors.New("この招待コードは使用済みか有効期限が切れています")
+		}
+		return nil, fmt.Errorf("招待コードの確認に失敗しました: %v", err)
+	}
+	return inviteCode, nil
+}
+
+// Release Redeemで使用した招待コードの使用回数を戻す
+func (s *registrationService) Release(ctx context.Context, code *models.InviteCode) {
+	if code == nil {
+		return
+	}
+	if err := s.inviteCodeRepo.Release(ctx, code.ID); err != nil {
+		log.Printf("招待コード %d の使用回数を戻せませんでした: %v", code.ID, err)
+	}
+}
+
+// JoinWaitlist 新規登録の順番待ちに登録（誰でも登録できる間は不要）
+func (s *registrationService) JoinWaitlist(ctx context.Context, email, name, message string) (*models.WaitlistEntry, error) {
+	if s.config.Registration.Mode == config.RegistrationModeOpen {
+		return nil, errors.New("現在は誰でも登録できるため、順番待ちは不要です")
+	}
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		return nil, errors.New("無効なメールアドレスです")
+	}
+	if _, err := s.userRepo.FindByEmail(ctx, email); err == nil {
+		return nil, errors.New("このメールアドレスは既に使用されています")
+	}
+
+	entry := &models.WaitlistEntry{
+		Email:   email,
+		Name:    strings.TrimSpace(name),
+		Message: strings.TrimSpace(message),
+		Status:  models.WaitlistStatusWaiting,
+	}
+	if err := s.waitlistRepo.Create(ctx, entry); err != nil {
+		if errors.Is(err, repository.ErrAlreadyOnWaitlist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("順番待ちの登録に失敗しました: %v", err)
+	}
+	return entry, nil
+}
+
+// ListWaitlist 順番待ち一覧を登録の早い順に取得（管理者用）
+func (s *registrationService) ListWaitlist(ctx context.Context, status string, page, limit int) ([]models.WaitlistEntry, int64, int, error) {
+	if status != "" && status != models.WaitlistStatusWaiting && status != models.WaitlistStatusApproved {
+		return nil, 0, 0, errors.New("無効な状態です")
+	}
+	entries, total, err := s.waitlistRepo.List(ctx, status, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return entries, total, pages, nil
+}
+
+// ApproveWaitlist 順番待ちを承認し、そのメールアドレスでのみ使える招待コードを発行してメールで送る（管理者用）
+func (s *registrationService) ApproveWaitlist(ctx context.Context, id, adminID uint) (*models.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("順番待ちが見つかりません")
+	}
+	if entry.Status != models.WaitlistStatusWaiting {
+		return nil, errors.New("この順番待ちは既に承認されています")
+	}
+
+	var expiresAt *time.Time
+	if s.config.Registration.InviteCodeExpiryDays > 0 {
+		expiry := time.Now().AddDate(0, 0, s.config.Registration.InviteCodeExpiryDays)
+		expiresAt = &expiry
+	}
+	code, err := s.createInviteCode(ctx, &models.InviteCode{
+		Email:     entry.Email,
+		MaxUses:   1,
+		Note:      fmt.Sprintf("順番待ち #%d", entry.ID),
+		ExpiresAt: expiresAt,
+		CreatedBy: adminID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	entry.Status = models.WaitlistStatusApproved
+	entry.InviteCodeID = &code.ID
+	entry.ApprovedAt = &now
+	if err := s.waitlistRepo.Update(ctx, entry); err != nil {
+		return nil, fmt.Errorf("順番待ちの承認に失敗しました: %v", err)
+	}
+
+	data := waitlistInvitationMail{
+		Name: entry.Name,
+		Code: code.Code,
+		Link: strings.TrimSuffix(s.config.Mail.FrontendURL, "/") + "/register?invite_code=" + url.QueryEscape(code.Code),
+	}
+	if expiresAt != nil {
+		data.ExpiresAt = expiresAt.Format("2006-01-02 15:04")
+	}
+	// 承認は取り消さない（送信に失敗した場合は管理者が招待コードを直接伝えられる）
+	message, err := s.mailTemplates.Render(MailTemplateWaitlistInvitation, data)
+	if err == nil {
+		err = s.mailService.Send(ctx, entry.Email, message)
+	}
+	if err != nil {
+		log.Printf("順番待ちの招待メールの送信に失敗しました (ID=%d): %v", entry.ID, err)
+	}
+
+	return entry, nil
+}
+
+// DeleteWaitlist 順番待ちを削除（管理者用）
+func (s *registrationService) DeleteWaitlist(ctx context.Context, id uint) error {
+	if _, err := s.waitlistRepo.FindByID(ctx, id); err != nil {
+		return errors.New("順番待ちが見つかりません")
+	}
+	if err := s.waitlistRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("順番待ちの削除に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// CreateInviteCode 招待コードを発行（管理者用）
+func (s *registrationService) CreateInviteCode(ctx context.Context, adminID uint, maxUses int, expiresAt *time.Time, note string) (*models.InviteCode, error) {
+	if maxUses < 1 || maxUses > maxInviteCodeUses {
+		return nil, fmt.Errorf("使用回数には1から%dまでの値を指定してください", maxInviteCodeUses)
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.New("有効期限には未来の日時を指定してください")
+	}
+
+	return s.createInviteCode(ctx, &models.InviteCode{
+		MaxUses:   maxUses,
+		Note:      strings.TrimSpace(note),
+		ExpiresAt: expiresAt,
+		CreatedBy: adminID,
+	})
+}
+
+// ListInviteCodes 招待コード一覧を取得（管理者用）
+func (s *registrationService) ListInviteCodes(ctx context.Context, page, limit int) ([]models.InviteCode, int64, int, error) {
+	codes, total, err := s.inviteCodeRepo.List(ctx, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return codes, total, pages, nil
+}
+
+// DeleteInviteCode 招待コードを削除して使えなくする（管理者用）
+func (s *registrationService) DeleteInviteCode(ctx context.Context, id uint) error {
+	if _, err := s.inviteCodeRepo.FindByID(ctx, id); err != nil {
+		return errors.New("招待コードが見つかりません")
+	}
+	if err := s.inviteCodeRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("招待コードの削除に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// createInviteCode ランダムなコードを割り当てて招待コードを保存
+func (s *registrationService) createInviteCode(ctx context.Context, code *models.InviteCode) (*models.InviteCode, error) {
+	code.Code = normalizeInviteCode(utils.GenerateRandomString(inviteCodeLength))
+	if err := s.inviteCodeRepo.Create(ctx, code); err != nil {
+		return nil, fmt.Errorf("招待コードの発行に失敗しました: %v", err)
+	}
+	return code, nil
+}
+
+// normalizeInviteCode 入力された招待コードを保存している形式（前後の空白を除いた大文字）にする
+func normalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// ReportService 作品・コメントの通報と、通報による自動非表示に関するサービスインターフェース
+type ReportService interface {
+	Report(ctx context.Context, targetType string, targetID, reporterID uint, reason string) (*models.Report, error)
+	ListReports(ctx context.Context, targetType string, targetID uint) ([]models.Report, error)
+	ListHiddenWorks(ctx context.Context, page, limit int) ([]models.Work, int64, int, error)
+	ListHiddenComments(ctx context.Context, page, limit int) ([]models.Comment, int64, int, error)
+	Restore(ctx context.Context, targetType string, targetID uint) error
+	Remove(ctx context.Context, targetType string, targetID uint) error
+}
+
+// reportService ReportServiceの実装
+type reportService struct {
+	reportRepo          repository.ReportRepository
+	workRepo            repository.WorkRepository
+	commentRepo         repository.CommentRepository
+	userRepo            repository.UserRepository
+	notificationService NotificationService
+	config              *config.Config
+}
+
+// NewReportService ReportServiceを作成
+func NewReportService(
+	reportRepo repository.ReportRepository,
+	workRepo repository.WorkRepository,
+	commentRepo repository.CommentRepository,
+	userRepo repository.UserRepository,
+	notificationService NotificationService,
+	cfg *config.Config,
+) ReportService {
+	return &reportService{
+		reportRepo:          reportRepo,
+		workRepo:            workRepo,
+		commentRepo:         commentRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		config:              cfg,
+	}
+}
+
+// reportTargetState 通報の対象の作者と非表示の状態
+type reportTargetState struct {
+	label      string // 通知に表示する名前
+	link       string
+	userID     uint
+	hiddenAt   *time.Time
+	reviewedAt *time.Time
+}
+
+// findTarget 通報の対象を取得
+func (s *reportService) findTarget(ctx context.Context, targetType string, targetID uint) (*reportTargetState, error) {
+	switch targetType {
+	case models.ReportTargetWork:
+		work, err := s.workRepo.FindByID(ctx, targetID)
+		if err != nil {
+			return nil, errors.New("作品が見つかりません")
+		}
+		return &reportTargetState{
+			label:      fmt.Sprintf("作品「%s」", work.Title),
+			link:       fmt.Sprintf("/works/%d", work.ID),
+			userID:     work.UserID,
+			hiddenAt:   work.HiddenAt,
+			reviewedAt: work.ReviewedAt,
+		}, nil
+	case models.ReportTargetComment:
+		comment, err := s.commentRepo.FindByID(ctx, targetID)
+		if err != nil {
+			return nil, errors.New("コメントが見つかりません")
+		}
+		return &reportTargetState{
+			label:      fmt.Sprintf("作品ID %d へのコメント", comment.WorkID),
+			link:       fmt.Sprintf("/works/%d", comment.WorkID),
+			userID:     comment.UserID,
+			hiddenAt:   comment.HiddenAt,
+			reviewedAt: comment.ReviewedAt,
+		}, nil
+	default:
+		return nil, fmt.Errorf("無効な通報の対象です: %s", targetType)
+	}
+}
+
+// Report 作品・コメントを通報する
+// 通報したユーザー数がしきい値を超えた場合は、管理者が確認するまで非表示にして管理者に通知する
+func (s *reportService) Report(ctx context.Context, targetType string, targetID, reporterID uint, reason string) (*models.Report, error) {
+	target, err := s.findTarget(ctx, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.userID == reporterID {
+		return nil, errors.New("自分の投稿は通報できません")
+	}
+
+	exists, err := s.reportRepo.Exists(ctx, targetType, targetID, reporterID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.New("既に通報済みです")
+	}
+
+	report := &models.Report{
+		TargetType: targetType,
+		TargetID:   targetID,
+		ReporterID: reporterID,
+		Reason:     reason,
+	}
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if target.hiddenAt == nil {
+		s.hideIfNeeded(ctx, targetType, targetID, target)
+	}
+
+	return report, nil
+}
+
+// hideIfNeeded 通報したユーザー数がしきい値を超えていれば非表示にする
+// 管理者が確認して再表示した後は、確認後の通報のみを数え、より大きいしきい値を使う（一斉通報で何度も非表示にされるのを防ぐ）
+func (s *reportService) hideIfNeeded(ctx context.Context, targetType string, targetID uint, target *reportTargetState) {
+	threshold := s.config.Report.HideThreshold
+	if target.reviewedAt != nil {
+		threshold = s.config.Report.RehideThreshold
+	}
+	if threshold <= 0 {
+		return
+	}
+
+	count, err := s.reportRepo.CountSince(ctx, targetType, targetID, target.reviewedAt)
+	if err != nil {
+		log.Printf("通報数の取得に失敗しました (%s ID=%d): %v", targetType, targetID, err)
+		return
+	}
+	if count <= int64(threshold) {
+		return
+	}
+
+	hidden, err := s.reportRepo.Hide(ctx, targetType, targetID, time.Now())
+	if err != nil {
+		log.Printf("通報された投稿の非表示に失敗しました (%s ID=%d): %v", targetType, targetID, err)
+		return
+	}
+	if !hidden {
+		// 他のリクエストで既に非表示になっている
+		return
+	}
+
+	adminIDs, err := s.userRepo.ListAdminIDs(ctx)
+	if err != nil {
+		log.Printf("管理者の取得に失敗しました: %v", err)
+		return
+	}
+	message := fmt.Sprintf("%sが%d人から通報されたため非表示にしました。内容を確認してください。", target.label, count)
+	if err := s.notificationService.Notify(ctx, adminIDs, models.NotificationTypeReported, "通報により投稿を非表示にしました", message, target.link); err != nil {
+		log.Printf("通知の作成に失敗しました (%s ID=%d): %v", targetType, targetID, err)
+	}
+}
+
+// ListReports 対象への通報一覧を取得（管理者用）
+func (s *reportService) ListReports(ctx context.Context, targetType string, targetID uint) ([]models.Report, error) {
+	if _, err := s.findTarget(ctx, targetType, targetID); err != nil {
+		return nil, err
+	}
+	return s.reportRepo.ListByTarget(ctx, targetType, targetID)
+}
+
+// ListHiddenWorks 通報により非表示になっている作品一覧を取得（管理者用）
+func (s *reportService) ListHiddenWorks(ctx context.Context, page, limit int) ([]models.Work, int64, int, error) {
+	works, total, err := s.reportRepo.ListHiddenWorks(ctx, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return works, total, pages, nil
+}
+
+// ListHiddenComments 通報により非表示になっているコメント一覧を取得（管理者用）
+func (s *reportService) ListHiddenComments(ctx context.Context, page, limit int) ([]models.Comment, int64, int, error) {
+	comments, total, err := s.reportRepo.ListHiddenComments(ctx, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return comments, total, pages, nil
+}
+
+// Restore 問題がないと確認した投稿を再表示する（管理者用）
+func (s *reportService) Restore(ctx context.Context, targetType string, targetID uint) error {
+	if _, err := s.findTarget(ctx, targetType, targetID); err != nil {
+		return err
+	}
+	return s.reportRepo.Restore(ctx, targetType, targetID, time.Now())
+}
+
+// Remove 問題があると確認した投稿を削除する（管理者用）
+func (s *reportService) Remove(ctx context.Context, targetType string, targetID uint) error {
+	if _, err := s.findTarget(ctx, targetType, targetID); err != nil {
+		return err
+	}
+
+	switch targetType {
+	case models.ReportTargetWork:
+		return s.workRepo.Delete(ctx, targetID)
+	default:
+		return s.commentRepo.Delete(ctx, targetID)
+	}
+}
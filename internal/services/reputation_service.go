@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// ReputationEvent レピュテーションが変動するイベント
+type ReputationEvent string
+
+// レピュテーションイベントの種類
+const (
+	ReputationLikeReceived       ReputationEvent = "like_received"
+	ReputationLikeRemoved        ReputationEvent = "like_removed"
+	ReputationSubmissionAccepted ReputationEvent = "submission_accepted"
+	ReputationSubmissionRemoved  ReputationEvent = "submission_removed"
+	ReputationContestWin         ReputationEvent = "contest_win"
+)
+
+// ReputationService レピュテーションに関するサービスインターフェース
+type ReputationService interface {
+	Apply(ctx context.Context, userID uint, event ReputationEvent)
+	Breakdown(ctx context.Context, userID uint) (int, *repository.ReputationSources, error)
+	Recalculate(ctx context.Context, userID uint) (int, *repository.ReputationSources, error)
+	Require(ctx context.Context, userID uint, minimum int) error
+	Ranking(ctx context.Context, page, limit int) ([]RankedUser, int64, int, error)
+}
+
+// RankedUser ランキングのユーザー（誰でも閲覧できるため、メールアドレスなどは含めない）
+type RankedUser struct {
+	ID         uint    `json:"id"`
+	Name       string  `json:"name"`
+	Nickname   string  `json:"nickname"`
+	Handle     *string `json:"handle,omitempty"`
+	Reputation int     `json:"reputation"`
+}
+
+// reputationService ReputationServiceの実装
+type reputationService struct {
+	userRepo repository.UserRepository
+	config   *config.Config
+}
+
+// NewReputationService ReputationServiceを作成
+func NewReputationService(userRepo repository.UserRepository, cfg *config.Config) ReputationService {
+	return &reputationService{
+		userRepo: userRepo,
+		config:   cfg,
+	}
+}
+
+// points イベントごとの増減ポイントを取得
+func (s *reputationService) points(event ReputationEvent) int {
+	switch event {
+	case ReputationLikeReceived:
+		return s.config.Reputation.LikePoints
+	case ReputationLikeRemoved:
+		return -s.config.Reputation.LikePoints
+	case ReputationSubmissionAccepted:
+		return s.config.Reputation.SubmissionPoints
+	case ReputationSubmissionRemoved:
+		return -s.config.Reputation.SubmissionPoints
+	case ReputationContestWin:
+		return s.config.Reputation.ContestWinPoints
+	default:
+		return 0
+	}
+}
+
+// Apply イベントに応じてレピュテーションを増減
+// 本処理の成否に影響させないため、エラーはログ出力のみとする
+func (s *reputationService) Apply(ctx context.Context, userID uint, event ReputationEvent) {
+	delta := s.points(event)
+	if delta == 0 {
+		return
+	}
+
+	if err := s.userRepo.AddReputation(ctx, userID, delta); err != nil {
+		log.Printf("レピュテーションの更新に失敗しました (UserID=%d, Event=%s): %v", userID, event, err)
+	}
+}
+
+// Breakdown 現在のレピュテーションと算出元の集計値を取得（保存はしない）
+func (s *reputationService) Breakdown(ctx context.Context, userID uint) (int, *repository.ReputationSources, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return 0, nil, errors.New("ユーザーが見つかりません")
+	}
+
+	sources, err := s.userRepo.GetReputationSources(ctx, userID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return user.Reputation, sources, nil
+}
+
+// Recalculate 集計値からレピュテーションを再計算して保存（管理者用）
+func (s *reputationService) Recalculate(ctx context.Context, userID uint) (int, *repository.ReputationSources, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return 0, nil, errors.New("ユーザーが見つかりません")
+	}
+
+	sources, err := s.userRepo.GetReputationSources(ctx, userID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	reputation := int(sources.LikesReceived)*s.config.Reputation.LikePoints +
+		int(sources.AcceptedSubmissions)*s.config.Reputation.SubmissionPoints +
+		int(sources.ContestWins)*s.config.Reputation.ContestWinPoints
+
+	if err := s.userRepo.SetReputation(ctx, userID, reputation); err != nil {
+		return 0, nil, err
+	}
+
+	return reputation, sources, nil
+}
+
+// Require ユーザーが必要なレピュテーションを満たしているか確認
+func (s *reputationService) Require(ctx context.Context, userID uint, minimum int) error {
+	if minimum <= 0 {
+		return nil
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("ユーザーが見つかりません")
+	}
+
+	if user.Reputation < minimum {
+		return fmt.Errorf("この操作を行う権限がありません（レピュテーション %d 以上が必要です。現在: %d）", minimum, user.Reputation)
+	}
+
+	return nil
+}
+
+// Ranking レピュテーション順のユーザー一覧を取得
+func (s *reputationService) Ranking(ctx context.Context, page, limit int) ([]RankedUser, int64, int, error) {
+	users, total, err := s.userRepo.ListByReputation(ctx, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	ranked := make([]RankedUser, 0, len(users))
+	for _, user := range users {
+		ranked = append(ranked, RankedUser{
+			ID:         user.ID,
+			Name:       user.Name,
+			Nickname:   user.Nickname,
+			Handle:     user.Handle,
+			Reputation: user.Reputation,
+		})
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return ranked, total, pages, nil
+}
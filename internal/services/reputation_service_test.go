@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository/memory"
+)
+
+// TestReputationServiceRankingOmitsEmail 誰でも閲覧できるランキングにメールアドレスが含まれないことを確認
+func TestReputationServiceRankingOmitsEmail(t *testing.T) {
+	ctx := context.Background()
+	userRepo := memory.NewUserRepository(memory.NewStore())
+	user := &models.User{Email: "secret@example.com", Name: "name", Nickname: "nick", Reputation: 10}
+	if err := userRepo.Create(ctx, user); err != nil {
+		t.Fatalf("ユーザーの作成に失敗しました: %v", err)
+	}
+
+	s := NewReputationService(userRepo, &config.Config{})
+	users, total, _, err := s.Ranking(ctx, 1, 20)
+	if err != nil {
+		t.Fatalf("ランキングの取得に失敗しました: %v", err)
+	}
+	if total != 1 || len(users) != 1 || users[0].Reputation != 10 {
+		t.Fatalf("ランキング = %+v (total %d)", users, total)
+	}
+
+	body, err := json.Marshal(users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), "secret@example.com") || strings.Contains(string(body), "email") {
+		t.Errorf("ランキングにメールアドレスが含まれています: %s", body)
+	}
+}
+
+// TestUserUpdateKeepsConcurrentReputation プロフィールの更新が、読み込み後に加算されたレピュテーションを上書きしないことを確認
+func TestUserUpdateKeepsConcurrentReputation(t *testing.T) {
+	ctx := context.Background()
+	userRepo := memory.NewUserRepository(memory.NewStore())
+	user := &models.User{Email: "user@example.com", Name: "name", Nickname: "nick"}
+	if err := userRepo.Create(ctx, user); err != nil {
+		t.Fatalf("ユーザーの作成に失敗しました: %v", err)
+	}
+
+	stale, err := userRepo.FindByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("ユーザーの取得に失敗しました: %v", err)
+	}
+	if err := userRepo.AddReputation(ctx, user.ID, 5); err != nil {
+		t.Fatalf("レピュテーションの加算に失敗しました: %v", err)
+	}
+	stale.Nickname = "renamed"
+	if err := userRepo.Update(ctx, stale); err != nil {
+		t.Fatalf("ユーザーの更新に失敗しました: %v", err)
+	}
+
+	updated, err := userRepo.FindByID(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("ユーザーの取得に失敗しました: %v", err)
+	}
+	if updated.Nickname != "renamed" || updated.Reputation != 5 {
+		t.Errorf("nickname = %q, reputation = %d, want renamed, 5", updated.Nickname, updated.Reputation)
+	}
+}
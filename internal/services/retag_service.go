@@ -0,0 +1,319 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// 1バッチあたりの作品数
+const (
+	defaultRetagBatchSize = 100
+	maxRetagBatchSize     = 1000
+)
+
+// RetagService 検索条件に一致する作品のタグを一括で追加・削除するサービスインターフェース（管理者用）
+type RetagService interface {
+	Start(ctx context.Context, adminID uint, input RetagInput) (*models.RetagJob, error)
+	GetByID(ctx context.Context, id uint) (*models.RetagJob, error)
+	List(ctx context.Context, page, limit int) ([]models.RetagJob, int64, int, error)
+	Cancel(ctx context.Context, id uint) (*models.RetagJob, error)
+	Undo(ctx context.Context, id, adminID uint) (*models.RetagJob, error)
+	ProcessBatches(ctx context.Context) (int, error)
+}
+
+// RetagInput 一括タグ付けの内容と対象作品の検索条件（作品一覧の絞り込みと同じ）
+type RetagInput struct {
+	Action    string `json:"action"`
+	Tag       string `json:"tag"`
+	Search    string `json:"search"`
+	FilterTag string `json:"filter_tag"`
+	License   string `json:"license"`
+	Language  string `json:"language"`
+	UserID    *uint  `json:"user_id"`
+	BatchSize int    `json:"batch_size"`
+}
+
+// retagService RetagServiceの実装
+type retagService struct {
+	retagRepo repository.RetagRepository
+	tagRepo   repository.TagRepository
+}
+
+// NewRetagService RetagServiceを作成
+func NewRetagService(retagRepo repository.RetagRepository, tagRepo repository.TagRepository) RetagService {
+	return &retagService{
+		retagRepo: retagRepo,
+		tagRepo:   tagRepo,
+	}
+}
+
+// Start 一括タグ付けジョブを開始（スケジューラがバッチごとに処理する）
+func (s *retagService) Start(ctx context.Context, adminID uint, input RetagInput) (*models.RetagJob, error) {
+	if input.Action != models.RetagActionAdd && input.Action != models.RetagActionRemove {
+		return nil, fmt.Errorf("無効な操作です: %s", input.Action)
+	}
+	tagName := strings.TrimSpace(input.Tag)
+	if tagName == "" {
+		return nil, errors.New("タグを指定してください")
+	}
+	batchSize, err := retagBatchSize(input.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// 全ての作品を誤って変更しないよう、検索条件を1つ以上必須にする
+	job := &models.RetagJob{
+		Action:    input.Action,
+		TagName:   tagName,
+		Search:    strings.TrimSpace(input.Search),
+		FilterTag: strings.TrimSpace(input.FilterTag),
+		License:   input.License,
+		UserID:    input.UserID,
+		Status:    models.RetagStatusRunning,
+		BatchSize: batchSize,
+		CreatedBy: adminID,
+	}
+	if job.License != "" && !models.IsValidWorkLicense(job.License) {
+		return nil, fmt.Errorf("無効なライセンスです: %s", job.License)
+	}
+	if input.Language != "" {
+		language, ok := models.NormalizeWorkLanguage(input.Language)
+		if !ok {
+			return nil, fmt.Errorf("無効な言語です: %s", input.Language)
+		}
+		job.Language = language
+	}
+	if job.Search == "" && job.FilterTag == "" && job.License == "" && job.Language == "" && job.UserID == nil {
+		return nil, errors.New("対象の作品の検索条件を1つ以上指定してください")
+	}
+
+	if err := s.checkNotRunning(ctx); err != nil {
+		return nil, err
+	}
+
+	// 追加の場合は存在しないタグを作成し、削除の場合は存在するタグのみ指定できる
+	var tag *models.Tag
+	if job.Action == models.RetagActionAdd {
+		tag, err = s.tagRepo.FindOrCreate(ctx, tagName)
+	} else {
+		tag, err = s.tagRepo.FindByName(ctx, tagName)
+	}
+	if err != nil {
+		return nil, errors.New("タグが見つかりません")
+	}
+	job.TagID = tag.ID
+	job.TagName = tag.Name
+
+	total, err := s.retagRepo.CountTargets(ctx, job)
+	if err != nil {
+		return nil, fmt.Errorf("対象作品数の取得に失敗しました: %v", err)
+	}
+	return s.create(ctx, job, total)
+}
+
+// GetByID IDでジョブを取得
+func (s *retagService) GetByID(ctx context.Context, id uint) (*models.RetagJob, error) {
+	job, err := s.retagRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("一括タグ付けジョブが見つかりません")
+	}
+	return job, nil
+}
+
+// List ジョブ一覧を取得
+func (s *retagService) List(ctx context.Context, page, limit int) ([]models.RetagJob, int64, int, error) {
+	jobs, total, err := s.retagRepo.List(ctx, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return jobs, total, pages, nil
+}
+
+// Cancel 実行中のジョブを中止（変更済みの作品はそのまま残り、取り消しで元に戻せる）
+func (s *retagService) Cancel(ctx context.Context, id uint) (*models.RetagJob, error) {
+	job, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status != models.RetagStatusRunning {
+		return nil, errors.New("実行中のジョブではありません")
+	}
+
+	now := time.Now()
+	job.Status = models.RetagStatusCancelled
+	job.CompletedAt = &now
+	if err := s.retagRepo.Update(ctx, job); err != nil {
+		return nil, fmt.Errorf("一括タグ付けジョブの更新に失敗しました: %v", err)
+	}
+
+	return job, nil
+}
+
+// Undo 終了したジョブでタグを変更した作品を元に戻すジョブを開始
+func (s *retagService) Undo(ctx context.Context, id, adminID uint) (*models.RetagJob, error) {
+	original, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if original.Status == models.RetagStatusRunning {
+		return nil, errors.New("実行中のジョブは取り消せません（中止してから取り消してください）")
+	}
+	if original.UndoOfID != nil {
+		return nil, errors.New("取り消しのジョブは取り消せません")
+	}
+	if original.UndoneByID != nil {
+		return nil, fmt.Errorf("このジョブは既に取り消されています (ID=%d)", *original.UndoneByID)
+	}
+	if err := s.checkNotRunning(ctx); err != nil {
+		return nil, err
+	}
+
+	action := models.RetagActionRemove
+	if original.Action == models.RetagActionRemove {
+		action = models.RetagActionAdd
+	}
+	job := &models.RetagJob{
+		Action:    action,
+		TagID:     original.TagID,
+		TagName:   original.TagName,
+		Search:    original.Search,
+		FilterTag: original.FilterTag,
+		License:   original.License,
+		Language:  original.Language,
+		UserID:    original.UserID,
+		UndoOfID:  &original.ID,
+		Status:    models.RetagStatusRunning,
+		BatchSize: original.BatchSize,
+		CreatedBy: adminID,
+	}
+
+	total, err := s.retagRepo.CountChanged(ctx, original.ID)
+	if err != nil {
+		return nil, fmt.Errorf("対象作品数の取得に失敗しました: %v", err)
+	}
+	job, err = s.create(ctx, job, total)
+	if err != nil {
+		return nil, err
+	}
+
+	original.UndoneByID = &job.ID
+	if err := s.retagRepo.Update(ctx, original); err != nil {
+		return nil, fmt.Errorf("一括タグ付けジョブの更新に失敗しました: %v", err)
+	}
+	return job, nil
+}
+
+// ProcessBatches 実行中のジョブを1バッチずつ進める（スケジューラから定期実行）
+func (s *retagService) ProcessBatches(ctx context.Context) (int, error) {
+	jobs, err := s.retagRepo.ListRunning(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for i := range jobs {
+		n, err := s.processBatch(ctx, &jobs[i])
+		processed += n
+		if err != nil {
+			return processed, err
+		}
+	}
+
+	return processed, nil
+}
+
+// processBatch ジョブの次のバッチを処理
+func (s *retagService) processBatch(ctx context.Context, job *models.RetagJob) (int, error) {
+	// 取り消しのジョブは元のジョブで変更した作品、それ以外は検索条件に一致する作品を対象にする
+	var workIDs []uint
+	var err error
+	if job.UndoOfID != nil {
+		workIDs, err = s.retagRepo.ListChanged(ctx, *job.UndoOfID, job.LastWorkID, job.BatchSize)
+	} else {
+		workIDs, err = s.retagRepo.ListTargets(ctx, job, job.LastWorkID, job.BatchSize)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	changed, err := s.retagRepo.ApplyBatch(ctx, job, workIDs)
+	if err != nil {
+		// 次回に同じバッチをやり直す
+		job.LastError = err.Error()
+		if updateErr := s.retagRepo.Update(ctx, job); updateErr != nil {
+			return 0, updateErr
+		}
+		return 0, fmt.Errorf("一括タグ付けに失敗しました (JobID=%d): %v", job.ID, err)
+	}
+
+	if len(workIDs) > 0 {
+		job.LastWorkID = workIDs[len(workIDs)-1]
+	}
+	job.Processed += len(workIDs)
+	job.Changed += len(changed)
+	job.LastError = ""
+
+	// 最後のバッチであれば完了
+	if len(workIDs) < job.BatchSize {
+		now := time.Now()
+		job.Status = models.RetagStatusCompleted
+		job.CompletedAt = &now
+	}
+
+	if err := s.retagRepo.Update(ctx, job); err != nil {
+		return len(workIDs), err
+	}
+	return len(workIDs), nil
+}
+
+// create 対象作品数を設定してジョブを保存（対象がなければ即完了）
+func (s *retagService) create(ctx context.Context, job *models.RetagJob, total int64) (*models.RetagJob, error) {
+	job.Total = int(total)
+	if total == 0 {
+		now := time.Now()
+		job.Status = models.RetagStatusCompleted
+		job.CompletedAt = &now
+	}
+
+	if err := s.retagRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("一括タグ付けジョブの作成に失敗しました: %v", err)
+	}
+	return job, nil
+}
+
+// checkNotRunning 実行中のジョブがないか確認（同じ作品を同時に変更して取り消しの情報がずれないよう、1つずつ実行する）
+func (s *retagService) checkNotRunning(ctx context.Context) error {
+	running, err := s.retagRepo.ListRunning(ctx)
+	if err != nil {
+		return err
+	}
+	if len(running) > 0 {
+		return fmt.Errorf("実行中の一括タグ付けジョブがあります (ID=%d)", running[0].ID)
+	}
+	return nil
+}
+
+// retagBatchSize 1バッチあたりの作品数（未指定の場合はデフォルト）
+func retagBatchSize(batchSize int) (int, error) {
+	if batchSize <= 0 {
+		return defaultRetagBatchSize, nil
+	}
+	if batchSize > maxRetagBatchSize {
+		return 0, fmt.Errorf("バッチサイズは%d以下で指定してください", maxRetagBatchSize)
+	}
+	return batchSize, nil
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+)
+
+// RosterService 名簿（クラスの生徒一覧など）の取り込みに関するサービスインターフェース
+type RosterService interface {
+	Import(ctx context.Context, projectID, userID uint, r io.Reader) (*RosterImportResult, error)
+}
+
+// 一度に取り込める名簿の行数
+const maxRosterRows = 500
+
+// 招待トークンの長さ（16進数の文字数）
+const inviteTokenLength = 64
+
+// 名簿の行ごとの取り込み結果
+const (
+	RosterStatusAdded         = "added"          // 既存のユーザーをメンバーに追加
+	RosterStatusInvited       = "invited"        // 仮登録して招待メールを送信し、メンバーに追加
+	RosterStatusAlreadyMember = "already_member" // 既にメンバー
+	RosterStatusInvalid       = "invalid"        // 名前またはメールアドレスが不正
+	RosterStatusFailed        = "failed"         // 登録に失敗
+)
+
+// RosterImportRow 名簿の行ごとの取り込み結果
+type RosterImportRow struct {
+	Line   int    `json:"line"` // CSVの行番号（1始まり）
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Status string `json:"status"`
+	UserID uint   `json:"user_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// RosterImportResult 名簿の取り込み結果
+type RosterImportResult struct {
+	Added         int               `json:"added"`
+	Invited       int               `json:"invited"`
+	AlreadyMember int               `json:"already_member"`
+	Failed        int               `json:"failed"` // invalid と failed の合計
+	Rows          []RosterImportRow `json:"rows"`
+}
+
+// rosterService RosterServiceの実装
+type rosterService struct {
+	projectRepo   repository.ProjectRepository
+	userRepo      repository.UserRepository
+	mailService   MailService
+	mailTemplates MailTemplateService
+	config        *config.Config
+}
+
+// NewRosterService RosterServiceを作成
+func NewRosterService(
+	projectRepo repository.ProjectRepository,
+	userRepo repository.UserRepository,
+	mailService MailService,
+	mailTemplates MailTemplateService,
+	cfg *config.Config,
+) RosterService {
+	return &rosterService{
+		projectRepo:   projectRepo,
+		userRepo:      userRepo,
+		mailService:   mailService,
+		mailTemplates: mailTemplates,
+		config:        cfg,
+	}
+}
+
+// rosterEntry 名簿の1行
+type rosterEntry struct {
+	line  int
+	name  string
+	email string
+}
+
+// Import CSV（name,email）の名簿を取り込み、全員をプロジェクトのメンバーに追加する
+// 登録されていないメールアドレスは仮登録して招待メールを送る
+func (s *rosterService) Import(ctx context.Context, projectID, userID uint, r io.Reader) (*RosterImportResult, error) {
+	// プロジェクトが存在するか確認
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, errors.New("プロジェクトが見つかりません")
+	}
+
+	// 権限チェック
+	isOwner, err := s.projectRepo.IsOwner(ctx, projectID, userID)
+	if err != nil || !isOwner {
+		return nil, errors.New("このプロジェクトに名簿を取り込む権限がありません")
+	}
+
+	entries, err := parseRoster(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RosterImportResult{Rows: make([]RosterImportRow, 0, len(entries))}
+	for _, entry := range entries {
+		row := s.importEntry(ctx, project, entry)
+		switch row.Status {
+		case RosterStatusAdded:
+			result.Added++
+		case RosterStatusInvited:
+			result.Invited++
+		case RosterStatusAlreadyMember:
+			result.AlreadyMember++
+		default:
+			result.Failed++
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result, nil
+}
+
+// importEntry 名簿の1行を取り込む
+func (s *rosterService) importEntry(ctx context.Context, project *models.Project, entry rosterEntry) RosterImportRow {
+	row := RosterImportRow{Line: entry.line, Name: entry.name, Email: entry.email}
+
+	// バリデーション
+	if entry.name == "" {
+		row.Status = RosterStatusInvalid
+		row.Error = "名前は必須です"
+		return row
+	}
+	if address, err := mail.ParseAddress(entry.email); err != nil || address.Address != entry.email {
+		row.Status = RosterStatusInvalid
+		row.Error = "無効なメールアドレスです"
+		return row
+	}
+
+	// ユーザーを検索し、見つからない場合は仮登録
+	status := RosterStatusAdded
+	user, err := s.userRepo.FindByEmail(ctx, entry.email)
+	if err != nil {
+		token := utils.GenerateRandomString(inviteTokenLength)
+		user, err = s.provision(ctx, entry, token)
+		if err != nil {
+			row.Status = RosterStatusFailed
+			row.Error = err.Error()
+			return row
+		}
+		s.sendInvitation(ctx, user, project, token)
+		status = RosterStatusInvited
+	}
+	row.UserID = user.ID
+
+	// メンバーに追加
+	isMember, err := s.projectRepo.IsMember(ctx, project.ID, user.ID)
+	if err != nil {
+		row.Status = RosterStatusFailed
+		row.Error = err.Error()
+		return row
+	}
+	if isMember {
+		row.Status = RosterStatusAlreadyMember
+		return row
+	}
+	if err := s.projectRepo.AddMember(ctx, project.ID, user.ID, false); err != nil {
+		row.Status = RosterStatusFailed
+		row.Error = fmt.Sprintf("メンバーの追加に失敗しました: %v", err)
+		return row
+	}
+
+	row.Status = status
+	return row
+}
+
+// provision 招待を承認するまでログインできない仮登録ユーザーを作成
+func (s *rosterService) provision(ctx context.Context, entry rosterEntry, token string) (*models.User, error) {
+	// 名前は日本語のことが多いため、メールアドレスのローカル部を元にハンドルを割り当てる
+	local, _, _ := strings.Cut(entry.email, "@")
+	handle, err := generateHandle(ctx, s.userRepo, local)
+	if err != nil {
+		return nil, fmt.Errorf("ユーザーの仮登録に失敗しました: %v", err)
+	}
+
+	user := &models.User{
+		Email:           entry.email,
+		Name:            entry.name,
+		Nickname:        entry.name,
+		Handle:          &handle,
+		Pending:         true,
+		InviteTokenHash: hashInviteToken(token),
+	}
+	if s.config.Mail.InviteExpiryDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, s.config.Mail.InviteExpiryDays)
+		user.InviteExpiresAt = &expiresAt
+	}
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("ユーザーの仮登録に失敗しました: %v", err)
+	}
+	return user, nil
+}
+
+// sendInvitation 招待メールを送信
+// 仮登録とメンバーの追加は完了しているため、送信のエラーはログ出力のみとする
+func (s *rosterService) sendInvitation(ctx context.Context, user *models.User, project *models.Project, token string) {
+	data := projectInvitationMail{
+		Name:    user.Name,
+		Inviter: project.Owner.Nickname,
+		Project: project.Title,
+		Link:    strings.TrimSuffix(s.config.Mail.FrontendURL, "/") + "/invitations/accept?token=" + token,
+	}
+	if user.InviteExpiresAt != nil {
+		data.ExpiresAt = user.InviteExpiresAt.Format("2006-01-02 15:04")
+	}
+
+	message, err := s.mailTemplates.Render(MailTemplateProjectInvitation, data)
+	if err == nil {
+		err = s.mailService.Send(ctx, user.Email, message)
+	}
+	if err != nil {
+		log.Printf("招待メールの送信に失敗しました (UserID=%d): %v", user.ID, err)
+	}
+}
+
+// parseRoster CSVの名簿を読み込む
+// 1行目が見出し（name,email）の場合は読み飛ばし、空行は無視する（BOM付きのUTF-8にも対応）
+func parseRoster(r io.Reader) ([]rosterEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var entries []rosterEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("CSVの読み込みに失敗しました: %v", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		name, email := "", ""
+		if len(record) > 0 {
+			name = strings.TrimSpace(strings.TrimPrefix(record[0], "\ufeff"))
+		}
+		if len(record) > 1 {
+			email = strings.TrimSpace(record[1])
+		}
+		if name == "" && email == "" {
+			continue
+		}
+		if line == 1 && strings.EqualFold(name, "name") && strings.EqualFold(email, "email") {
+			continue
+		}
+
+		entries = append(entries, rosterEntry{line: line, name: name, email: email})
+		if len(entries) > maxRosterRows {
+			return nil, fmt.Errorf("一度に取り込める名簿は%d行までです", maxRosterRows)
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("名簿が空です")
+	}
+	return entries, nil
+}
+
+// hashInviteToken 招待トークンを保存用にハッシュ化
+func hashInviteToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// SeriesService シリーズ（作品を順番に並べた連載・チュートリアル）に関するサービスインターフェース
+type SeriesService interface {
+	Create(ctx context.Context, userID uint, title, description string, workIDs []uint) (*models.Series, error)
+	GetByID(ctx context.Context, id uint) (*models.Series, error)
+	Update(ctx context.Context, id, userID uint, title, description string, workIDs []uint) (*models.Series, error)
+	Delete(ctx context.Context, id, userID uint) error
+}
+
+// 1つのシリーズに含められる作品の最大数
+const maxSeriesWorks = 100
+
+// seriesService SeriesServiceの実装
+type seriesService struct {
+	seriesRepo repository.SeriesRepository
+	workRepo   repository.WorkRepository
+}
+
+// NewSeriesService SeriesServiceを作成
+func NewSeriesService(seriesRepo repository.SeriesRepository, workRepo repository.WorkRepository) SeriesService {
+	return &seriesService{
+		seriesRepo: seriesRepo,
+		workRepo:   workRepo,
+	}
+}
+
+// Create 自分の作品を指定した順番に並べたシリーズを作成
+func (s *seriesService) Create(ctx context.Context, userID uint, title, description string, workIDs []uint) (*models.Series, error) {
+	if strings.TrimSpace(title) == "" {
+		return nil, errors.New("タイトルは必須です")
+	}
+	if err := s.validateWorks(ctx, 0, userID, workIDs); err != nil {
+		return nil, err
+	}
+
+	series := &models.Series{
+		Title:       title,
+		Description: description,
+		UserID:      userID,
+	}
+	if err := s.seriesRepo.Create(ctx, series); err != nil {
+		return nil, err
+	}
+	if err := s.seriesRepo.SetWorks(ctx, series.ID, workIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, series.ID)
+}
+
+// GetByID IDでシリーズを取得（作品を順番通りに含める）
+func (s *seriesService) GetByID(ctx context.Context, id uint) (*models.Series, error) {
+	series, err := s.seriesRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("シリーズが見つかりません")
+	}
+
+	works, err := s.seriesRepo.ListWorks(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	series.Works = works
+
+	return series, nil
+}
+
+// Update シリーズのタイトル・説明を更新し、作品の順番を置き換える（workIDsがnilの場合は作品を変更しない）
+func (s *seriesService) Update(ctx context.Context, id, userID uint, title, description string, workIDs []uint) (*models.Series, error) {
+	series, err := s.seriesRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("シリーズが見つかりません")
+	}
+	if series.UserID != userID {
+		return nil, errors.New("このシリーズを更新する権限がありません")
+	}
+
+	if strings.TrimSpace(title) != "" {
+		series.Title = title
+	}
+	series.Description = description
+
+	if workIDs != nil {
+		if err := s.validateWorks(ctx, id, userID, workIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.seriesRepo.Update(ctx, series); err != nil {
+		return nil, err
+	}
+	if workIDs != nil {
+		if err := s.seriesRepo.SetWorks(ctx, id, workIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Delete シリーズを削除（作品は削除せず、シリーズから外す）
+func (s *seriesService) Delete(ctx context.Context, id, userID uint) error {
+	series, err := s.seriesRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.New("シリーズが見つかりません")
+	}
+	if series.UserID != userID {
+		return errors.New("このシリーズを削除する権限がありません")
+	}
+
+	return s.seriesRepo.Delete(ctx, id)
+}
+
+// validateWorks シリーズに含める作品が全て自分の作品で、重複や他のシリーズへの所属がないか確認
+func (s *seriesService) validateWorks(ctx context.Context, seriesID, userID uint, workIDs []uint) error {
+	if len(workIDs) > maxSeriesWorks {
+		return fmt.Errorf("1つのシリーズに含められる作品は%d件までです", maxSeriesWorks)
+	}
+
+	seen := make(map[uint]bool, len(workIDs))
+	for _, workID := range workIDs {
+		if seen[workID] {
+			return fmt.Errorf("作品が重複しています (ID=%d)", workID)
+		}
+		seen[workID] = true
+
+		work, err := s.workRepo.FindByID(ctx, workID)
+		if err != nil {
+			return fmt.Errorf("作品が見つかりません (ID=%d)", workID)
+		}
+		if work.UserID != userID {
+			return fmt.Errorf("この作品をシリーズに追加する権限がありません (ID=%d)", workID)
+		}
+		if work.SeriesID != nil && *work.SeriesID != seriesID {
+			return fmt.Errorf("作品「%s」は既に別のシリーズに含まれています", work.Title)
+		}
+	}
+	return nil
+}
+
+// seriesNavigation シリーズの作品一覧から、作品の位置と前後の作品を求める
+func seriesNavigation(series *models.Series, works []models.Work, workID uint) *models.SeriesNavigation {
+	for i, work := range works {
+		if work.ID != workID {
+			continue
+		}
+
+		nav := &models.SeriesNavigation{
+			ID:       series.ID,
+			Title:    series.Title,
+			Position: i + 1,
+			Total:    len(works),
+		}
+		if i > 0 {
+			nav.Previous = &models.SeriesPart{WorkID: works[i-1].ID, Title: works[i-1].Title}
+		}
+		if i < len(works)-1 {
+			nav.Next = &models.SeriesPart{WorkID: works[i+1].ID, Title: works[i+1].Title}
+		}
+		return nav
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// SitemapService 公開ギャラリーのサイトマップに関するサービスインターフェース
+type SitemapService interface {
+	Index(ctx context.Context) ([]byte, error)
+	Works(ctx context.Context, page int) ([]byte, error)
+	Invalidate()
+}
+
+const (
+	// 1つのサイトマップに載せるURLの最大数（サイトマップのプロトコルの上限）
+	maxSitemapURLs = 50000
+	// 生成したサイトマップをキャッシュする時間（作品の作成・更新時は即座に破棄する）
+	sitemapCacheTTL = time.Hour
+)
+
+// sitemapIndex サイトマップインデックス
+type sitemapIndex struct {
+	XMLName  xml.Name       `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+// sitemapEntry サイトマップインデックスの1件
+type sitemapEntry struct {
+	Loc string `xml:"loc"`
+}
+
+// urlSet サイトマップ
+type urlSet struct {
+	XMLName xml.Name   `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []urlEntry `xml:"url"`
+}
+
+// urlEntry サイトマップの1件
+type urlEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// sitemapService SitemapServiceの実装
+type sitemapService struct {
+	workRepo repository.WorkRepository
+	config   *config.Config
+
+	mu        sync.RWMutex
+	index     []byte
+	pages     map[int][]byte // キーはページ番号
+	expiresAt time.Time
+}
+
+// NewSitemapService SitemapServiceを作成
+func NewSitemapService(workRepo repository.WorkRepository, cfg *config.Config) SitemapService {
+	return &sitemapService{
+		workRepo: workRepo,
+		config:   cfg,
+		pages:    map[int][]byte{},
+	}
+}
+
+// Index 作品のサイトマップの一覧（サイトマップインデックス）を生成
+// 作品が50,000件を超える場合は複数のサイトマップに分割する
+func (s *sitemapService) Index(ctx context.Context) ([]byte, error) {
+	if cached, ok := s.cached(0); ok {
+		return cached, nil
+	}
+
+	_, total, err := s.workRepo.ListForSitemap(ctx, 1, 1)
+	if err != nil {
+		return nil, err
+	}
+	pages := int((total + maxSitemapURLs - 1) / maxSitemapURLs)
+	if pages < 1 {
+		pages = 1
+	}
+
+	baseURL := strings.TrimSuffix(s.config.Sitemap.BaseURL, "/")
+	index := sitemapIndex{Sitemaps: make([]sitemapEntry, 0, pages)}
+	for page := 1; page <= pages; page++ {
+		index.Sitemaps = append(index.Sitemaps, sitemapEntry{
+			Loc: fmt.Sprintf("%s/sitemaps/works/%d.xml", baseURL, page),
+		})
+	}
+
+	body, err := marshalSitemap(index)
+	if err != nil {
+		return nil, err
+	}
+	s.store(0, body)
+	return body, nil
+}
+
+// Works 作品のサイトマップを生成（pageは1始まり）
+func (s *sitemapService) Works(ctx context.Context, page int) ([]byte, error) {
+	if page < 1 {
+		return nil, errors.New("サイトマップが見つかりません")
+	}
+	if cached, ok := s.cached(page); ok {
+		return cached, nil
+	}
+
+	works, total, err := s.workRepo.ListForSitemap(ctx, page, maxSitemapURLs)
+	if err != nil {
+		return nil, err
+	}
+	// 1ページ目は作品がなくても空のサイトマップを返す
+	if len(works) == 0 && (page > 1 || total > 0) {
+		return nil, errors.New("サイトマップが見つかりません")
+	}
+
+	siteURL := strings.TrimSuffix(s.config.Sitemap.SiteURL, "/")
+	set := urlSet{URLs: make([]urlEntry, 0, len(works))}
+	for _, work := range works {
+		set.URLs = append(set.URLs, urlEntry{
+			Loc:     fmt.Sprintf("%s/works/%d", siteURL, work.ID),
+			LastMod: work.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	body, err := marshalSitemap(set)
+	if err != nil {
+		return nil, err
+	}
+	s.store(page, body)
+	return body, nil
+}
+
+// Invalidate 生成済みのサイトマップを破棄
+// ID順に分割しているため、作品の作成・削除で後続のページもずれる可能性があり、全て破棄する
+func (s *sitemapService) Invalidate() {
+	s.mu.Lock()
+	s.index = nil
+	s.pages = map[int][]byte{}
+	s.mu.Unlock()
+}
+
+// cached キャッシュされたサイトマップを取得（pageが0の場合はインデックス）
+func (s *sitemapService) cached(page int) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if time.Now().After(s.expiresAt) {
+		return nil, false
+	}
+	if page == 0 {
+		return s.index, s.index != nil
+	}
+	body, ok := s.pages[page]
+	return body, ok
+}
+
+// store 生成したサイトマップをキャッシュ（pageが0の場合はインデックス）
+func (s *sitemapService) store(page int, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 期限切れの場合はまとめて破棄してから保存する
+	if time.Now().After(s.expiresAt) {
+		s.index = nil
+		s.pages = map[int][]byte{}
+		s.expiresAt = time.Now().Add(sitemapCacheTTL)
+	}
+	if page == 0 {
+		s.index = body
+		return
+	}
+	s.pages[page] = body
+}
+
+// marshalSitemap XML宣言を付けてエンコード
+func marshalSitemap(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("サイトマップの生成に失敗しました: %v", err)
+	}
+	return append([]byte(xml.Header), body...), nil
+}
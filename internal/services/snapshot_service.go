@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// SnapshotService 作品のスナップショット（再生中にキャプチャしたフレーム）に関するサービスインターフェース
+type SnapshotService interface {
+	Create(ctx context.Context, workID, userID uint, r io.Reader, frameCount int, setThumbnail bool) (*models.WorkSnapshot, error)
+	List(ctx context.Context, workID uint) ([]models.WorkSnapshot, error)
+	SetThumbnail(ctx context.Context, workID, snapshotID, userID uint) (*models.WorkSnapshot, error)
+	Delete(ctx context.Context, workID, snapshotID, userID uint) error
+}
+
+// 1つの作品に保存できるスナップショットの最大数
+const maxWorkSnapshots = 30
+
+// snapshotService SnapshotServiceの実装
+type snapshotService struct {
+	snapshotRepo     repository.SnapshotRepository
+	workRepo         repository.WorkRepository
+	collaboratorRepo repository.CollaboratorRepository
+	imageService     ImageService
+}
+
+// NewSnapshotService SnapshotServiceを作成
+func NewSnapshotService(
+	snapshotRepo repository.SnapshotRepository,
+	workRepo repository.WorkRepository,
+	collaboratorRepo repository.CollaboratorRepository,
+	imageService ImageService,
+) SnapshotService {
+	return &snapshotService{
+		snapshotRepo:     snapshotRepo,
+		workRepo:         workRepo,
+		collaboratorRepo: collaboratorRepo,
+		imageService:     imageService,
+	}
+}
+
+// Create キャプチャしたフレームを変換して保存（作成者と編集権限のある共同編集者のみ）
+// setThumbnailがtrueの場合は作品のサムネイルにも設定する
+func (s *snapshotService) Create(ctx context.Context, workID, userID uint, r io.Reader, frameCount int, setThumbnail bool) (*models.WorkSnapshot, error) {
+	work, err := s.findEditableWork(ctx, workID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if frameCount < 0 {
+		return nil, errors.New("フレーム数には0以上の値を指定してください")
+	}
+
+	count, err := s.snapshotRepo.CountByWork(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxWorkSnapshots {
+		return nil, fmt.Errorf("1つの作品に保存できるスナップショットは%d枚までです", maxWorkSnapshots)
+	}
+
+	image, err := s.imageService.Upload(ctx, userID, models.ImageKindSnapshot, r)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.WorkSnapshot{
+		WorkID:     workID,
+		UserID:     userID,
+		ImageID:    image.ID,
+		FrameCount: frameCount,
+	}
+	if err := s.snapshotRepo.Create(ctx, snapshot); err != nil {
+		if deleteErr := s.imageService.Delete(context.Background(), image.ID); deleteErr != nil {
+			log.Printf("スナップショットの画像 %d の削除に失敗しました: %v", image.ID, deleteErr)
+		}
+		return nil, fmt.Errorf("スナップショットの保存に失敗しました: %v", err)
+	}
+
+	created, err := s.snapshotRepo.FindByID(ctx, snapshot.ID)
+	if err != nil {
+		return nil, err
+	}
+	if setThumbnail {
+		if err := s.applyThumbnail(ctx, work, created); err != nil {
+			return nil, err
+		}
+	}
+	created.Thumbnail = isSnapshotThumbnail(work, created)
+	return created, nil
+}
+
+// List 作品のスナップショットを新しい順に取得
+func (s *snapshotService) List(ctx context.Context, workID uint) ([]models.WorkSnapshot, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil || work.HiddenAt != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+
+	snapshots, err := s.snapshotRepo.ListByWork(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range snapshots {
+		snapshots[i].Thumbnail = isSnapshotThumbnail(work, &snapshots[i])
+	}
+	return snapshots, nil
+}
+
+// SetThumbnail スナップショットを作品のサムネイルに設定（作成者と編集権限のある共同編集者のみ）
+func (s *snapshotService) SetThumbnail(ctx context.Context, workID, snapshotID, userID uint) (*models.WorkSnapshot, error) {
+	work, err := s.findEditableWork(ctx, workID, userID)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.findSnapshot(ctx, workID, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.applyThumbnail(ctx, work, snapshot); err != nil {
+		return nil, err
+	}
+	snapshot.Thumbnail = true
+	return snapshot, nil
+}
+
+// Delete スナップショットを画像とともに削除（作成者と編集権限のある共同編集者のみ）
+// サムネイルに設定されている場合は、作品のサムネイルも外す
+func (s *snapshotService) Delete(ctx context.Context, workID, snapshotID, userID uint) error {
+	work, err := s.findEditableWork(ctx, workID, userID)
+	if err != nil {
+		return err
+	}
+	snapshot, err := s.findSnapshot(ctx, workID, snapshotID)
+	if err != nil {
+		return err
+	}
+
+	if isSnapshotThumbnail(work, snapshot) {
+		work.ThumbnailURL = ""
+		work.ThumbnailType = ""
+		work.ThumbnailBlurhash = ""
+		if err := s.workRepo.UpdateThumbnail(ctx, work); err != nil {
+			return fmt.Errorf("サムネイルの更新に失敗しました: %v", err)
+		}
+	}
+
+	if err := s.snapshotRepo.Delete(ctx, snapshot.ID); err != nil {
+		return fmt.Errorf("スナップショットの削除に失敗しました: %v", err)
+	}
+	if err := s.imageService.Delete(ctx, snapshot.ImageID); err != nil {
+		log.Printf("スナップショットの画像 %d の削除に失敗しました: %v", snapshot.ImageID, err)
+	}
+	return nil
+}
+
+// findEditableWork 作品を取得し、作成者か編集権限のある共同編集者か確認
+func (s *snapshotService) findEditableWork(ctx context.Context, workID, userID uint) (*models.Work, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	if !hasWorkPermission(ctx, s.collaboratorRepo, work, userID, models.CollaboratorPermissionEdit) {
+		return nil, errors.New("この作品のスナップショットを編集する権限がありません")
+	}
+	return work, nil
+}
+
+// findSnapshot 作品のスナップショットを取得（別の作品のスナップショットは見つからない扱いにする）
+func (s *snapshotService) findSnapshot(ctx context.Context, workID, snapshotID uint) (*models.WorkSnapshot, error) {
+	snapshot, err := s.snapshotRepo.FindByID(ctx, snapshotID)
+	if err != nil || snapshot.WorkID != workID {
+		return nil, errors.New("スナップショットが見つかりません")
+	}
+	return snapshot, nil
+}
+
+// applyThumbnail スナップショットの画像を作品のサムネイルに設定
+func (s *snapshotService) applyThumbnail(ctx context.Context, work *models.Work, snapshot *models.WorkSnapshot) error {
+	work.ThumbnailURL = snapshotThumbnailURL(&snapshot.Image)
+	work.ThumbnailType = "image/" + snapshot.Image.Format
+	if len(snapshot.Image.Variants) > 0 {
+		work.ThumbnailType = "image/" + snapshot.Image.Variants[0].Format
+	}
+	work.ThumbnailBlurhash = snapshot.Image.Blurhash
+	if err := s.workRepo.UpdateThumbnail(ctx, work); err != nil {
+		return fmt.Errorf("サムネイルの更新に失敗しました: %v", err)
+	}
+	return nil
+}
+
+// snapshotThumbnailURL サムネイルに使う画像のURL（アップロードしたサムネイルと同じく最初の派生画像）
+func snapshotThumbnailURL(image *models.Image) string {
+	if len(image.Variants) > 0 {
+		return image.Variants[0].URL
+	}
+	return image.URL
+}
+
+// isSnapshotThumbnail スナップショットが作品のサムネイルに設定されているか
+func isSnapshotThumbnail(work *models.Work, snapshot *models.WorkSnapshot) bool {
+	return work.ThumbnailURL != "" && work.ThumbnailURL == snapshotThumbnailURL(&snapshot.Image)
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+)
+
+// StorageQuota ユーザーのストレージ使用状況と各種上限
+type StorageQuota struct {
+	UsedBytes      int64 `json:"used_bytes"`
+	LimitBytes     int64 `json:"limit_bytes"` // 料金プランの上限（0は無制限）
+	RemainingBytes int64 `json:"remaining_bytes"`
+	PDEMaxBytes    int   `json:"pde_max_bytes"`
+	JSMaxBytes     int   `json:"js_max_bytes"`
+}
+
+// StorageQuotaService 作品のサイズ制限とストレージ容量に関するサービスインターフェース
+type StorageQuotaService interface {
+	GetQuota(ctx context.Context, userID uint) (*StorageQuota, error)
+	CheckPDESize(pdeContent string) error
+	CheckStorage(ctx context.Context, userID, excludeWorkID uint, additionalBytes int64) error
+}
+
+// storageQuotaService StorageQuotaServiceの実装
+type storageQuotaService struct {
+	workRepo     repository.WorkRepository
+	limitService LimitService
+	config       *config.Config
+}
+
+// NewStorageQuotaService StorageQuotaServiceを作成
+func NewStorageQuotaService(workRepo repository.WorkRepository, limitService LimitService, cfg *config.Config) StorageQuotaService {
+	return &storageQuotaService{
+		workRepo:     workRepo,
+		limitService: limitService,
+		config:       cfg,
+	}
+}
+
+// GetQuota ユーザーのストレージ使用状況を取得
+func (s *storageQuotaService) GetQuota(ctx context.Context, userID uint) (*StorageQuota, error) {
+	_, limits, err := s.limitService.Plan(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.workRepo.StorageUsedByUser(ctx, userID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("ストレージ使用量の取得に失敗しました: %v", err)
+	}
+
+	quota := &StorageQuota{
+		UsedBytes:   used,
+		LimitBytes:  limits.StorageBytes,
+		PDEMaxBytes: s.config.Limits.PDEMaxSizeKB * 1024,
+		JSMaxBytes:  s.config.Validation.JSMaxSizeKB * 1024,
+	}
+	if quota.LimitBytes > 0 {
+		quota.RemainingBytes = quota.LimitBytes - used
+		if quota.RemainingBytes < 0 {
+			quota.RemainingBytes = 0
+		}
+	}
+
+	return quota, nil
+}
+
+// CheckPDESize PDEコードのサイズを確認
+func (s *storageQuotaService) CheckPDESize(pdeContent string) error {
+	maxSize := s.config.Limits.PDEMaxSizeKB * 1024
+	if maxSize > 0 && len(pdeContent) > maxSize {
+		return fmt.Errorf("PDEコードのサイズが上限（%dKB）を超えています", s.config.Limits.PDEMaxSizeKB)
+	}
+	return nil
+}
+
+// CheckStorage 作品を保存した場合に料金プランのストレージ容量を超えないか確認
+func (s *storageQuotaService) CheckStorage(ctx context.Context, userID, excludeWorkID uint, additionalBytes int64) error {
+	_, limits, err := s.limitService.Plan(ctx, userID)
+	if err != nil {
+		return err
+	}
+	limit := limits.StorageBytes
+	if limit == 0 {
+		return nil
+	}
+
+	used, err := s.workRepo.StorageUsedByUser(ctx, userID, excludeWorkID)
+	if err != nil {
+		return fmt.Errorf("ストレージ使用量の取得に失敗しました: %v", err)
+	}
+
+	if used+additionalBytes > limit {
+		return fmt.Errorf("ストレージ容量の上限（%dMB）を超えています", limit/1024/1024)
+	}
+	return nil
+}
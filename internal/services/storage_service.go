@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+)
+
+// StorageService アセットの保存先を抽象化するインターフェース
+type StorageService interface {
+	// WriteAt keyのoffsetの位置からデータを書き込み、書き込んだバイト数を返す
+	WriteAt(ctx context.Context, key string, offset int64, r io.Reader) (int64, error)
+	// Open keyのデータを読み込み用に開く
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete keyのデータを削除
+	Delete(ctx context.Context, key string) error
+	// List prefixの下に保存されているデータをキーの順に取得
+	List(ctx context.Context, prefix string) ([]StorageObject, error)
+	// Ping 保存先に接続できるか確認
+	Ping(ctx context.Context) error
+}
+
+// StorageObject 保存先に保存されているデータ
+type StorageObject struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// NewStorageService 設定に応じたStorageServiceを作成
+func NewStorageService(cfg *config.Config) (StorageService, error) {
+	switch cfg.Storage.Provider {
+	case "", "local":
+		if err := os.MkdirAll(cfg.Storage.LocalDir, 0o755); err != nil {
+			return nil, fmt.Errorf("保存先ディレクトリを作成できませんでした: %v", err)
+		}
+		return &localStorageService{dir: cfg.Storage.LocalDir}, nil
+	default:
+		return nil, fmt.Errorf("不明なストレージです: %s", cfg.Storage.Provider)
+	}
+}
+
+// localStorageService ローカルディスクに保存するStorageServiceの実装
+type localStorageService struct {
+	dir string
+}
+
+// path keyに対応するファイルパス（保存先ディレクトリの外を指すkeyは拒否）
+func (s *localStorageService) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if cleaned == "/" || strings.Contains(key, "..") {
+		return "", errors.New("無効なキーです")
+	}
+	return filepath.Join(s.dir, cleaned), nil
+}
+
+// WriteAt keyのoffsetの位置からデータを書き込み
+func (s *localStorageService) WriteAt(ctx context.Context, key string, offset int64, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return io.Copy(file, r)
+}
+
+// Open keyのデータを読み込み用に開く
+func (s *localStorageService) Open(ctx context.Context, key string) (io.ReadSeekCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Delete keyのデータを削除
+func (s *localStorageService) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List prefixのディレクトリの下に保存されているファイルをキーの順に取得
+func (s *localStorageService) List(ctx context.Context, prefix string) ([]StorageObject, error) {
+	root, err := s.path(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []StorageObject{}
+	err = filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, StorageObject{
+			Key:     filepath.ToSlash(rel),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Key < objects[j].Key
+	})
+	return objects, nil
+}
+
+// Ping 保存先ディレクトリに書き込めるか確認
+func (s *localStorageService) Ping(ctx context.Context) error {
+	file, err := os.CreateTemp(s.dir, ".healthcheck-")
+	if err != nil {
+		return fmt.Errorf("保存先ディレクトリに書き込めません: %v", err)
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
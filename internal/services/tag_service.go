@@ -1,28 +1,47 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
 )
 
 // TagService タグに関するサービスインターフェース
 type TagService interface {
-	List(search string, limit int) ([]models.Tag, error)
+	List(ctx context.Context, search string, limit int) ([]models.Tag, error)
+	CleanupUnused(ctx context.Context) ([]models.Tag, error)
 }
 
 // tagService TagServiceの実装
 type tagService struct {
 	tagRepo repository.TagRepository
+	config  *config.Config
 }
 
 // NewTagService TagServiceを作成
-func NewTagService(tagRepo repository.TagRepository) TagService {
+func NewTagService(tagRepo repository.TagRepository, cfg *config.Config) TagService {
 	return &tagService{
 		tagRepo: tagRepo,
+		config:  cfg,
 	}
 }
 
 // List タグ一覧を取得
-func (s *tagService) List(search string, limit int) ([]models.Tag, error) {
-	return s.tagRepo.List(search, limit)
+func (s *tagService) List(ctx context.Context, search string, limit int) ([]models.Tag, error) {
+	return s.tagRepo.List(ctx, search, limit)
+}
+
+// CleanupUnused 作成から一定の日数が経っても作品に付いていないタグを削除し、削除したタグを返す
+// （スケジューラから定期実行、管理者は即座に実行できる）
+func (s *tagService) CleanupUnused(ctx context.Context) ([]models.Tag, error) {
+	before := time.Now().AddDate(0, 0, -s.config.TagCleanup.MinAgeDays)
+	tags, err := s.tagRepo.DeleteUnused(ctx, before, s.config.TagCleanup.Protected)
+	if err != nil {
+		return nil, fmt.Errorf("タグの削除に失敗しました: %v", err)
+	}
+	return tags, nil
 }
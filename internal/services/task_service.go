@@ -1,32 +1,37 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 )
 
 // TaskService タスクに関するサービスインターフェース
 type TaskService interface {
-	Create(title, description string, projectID, userID uint) (*models.Task, error)
-	GetByID(id uint, userID uint) (*models.Task, error)
-	Update(id, userID uint, title, description string) (*models.Task, error)
-	Delete(id, userID uint) error
-	ListByProject(projectID, userID uint) ([]models.Task, error)
-	AddWork(taskID, workID, userID uint) error
-	RemoveWork(taskID, workID, userID uint) error
-	GetWorks(taskID, userID uint, page, limit int) ([]models.Work, int64, int, error)
-	UpdateOrders(taskIDs []uint, orderIndices []int, userID uint) error
+	Create(ctx context.Context, title, description string, dueAt *time.Time, projectID, userID uint) (*models.Task, error)
+	GetByID(ctx context.Context, id uint, userID uint) (*models.Task, error)
+	Update(ctx context.Context, id, userID uint, title, description string, dueAt *time.Time, version *uint) (*models.Task, error)
+	Delete(ctx context.Context, id, userID uint) error
+	ListByProject(ctx context.Context, projectID, userID uint) ([]models.Task, error)
+	AddWork(ctx context.Context, taskID, workID, userID uint) error
+	RemoveWork(ctx context.Context, taskID, workID, userID uint) error
+	GetWorks(ctx context.Context, taskID, userID uint, page, limit int) ([]models.Work, int64, int, error)
+	UpdateOrders(ctx context.Context, taskIDs []uint, orderIndices []int, userID uint) error
+	GetContestGallery(ctx context.Context, projectID uint, page, limit int) ([]models.Work, int64, int, error)
 }
 
 // taskService TaskServiceの実装
 type taskService struct {
-	taskRepo    repository.TaskRepository
-	projectRepo repository.ProjectRepository
-	workRepo    repository.WorkRepository
+	taskRepo          repository.TaskRepository
+	projectRepo       repository.ProjectRepository
+	workRepo          repository.WorkRepository
+	reputationService ReputationService
 }
 
 // NewTaskService TaskServiceを作成
@@ -34,35 +39,37 @@ func NewTaskService(
 	taskRepo repository.TaskRepository,
 	projectRepo repository.ProjectRepository,
 	workRepo repository.WorkRepository,
+	reputationService ReputationService,
 ) TaskService {
 	return &taskService{
-		taskRepo:    taskRepo,
-		projectRepo: projectRepo,
-		workRepo:    workRepo,
+		taskRepo:          taskRepo,
+		projectRepo:       projectRepo,
+		workRepo:          workRepo,
+		reputationService: reputationService,
 	}
 }
 
 // Create 新しいタスクを作成
-func (s *taskService) Create(title, description string, projectID, userID uint) (*models.Task, error) {
+func (s *taskService) Create(ctx context.Context, title, description string, dueAt *time.Time, projectID, userID uint) (*models.Task, error) {
 	// タイトルのバリデーション
 	if strings.TrimSpace(title) == "" {
 		return nil, errors.New("タイトルは必須です")
 	}
 
 	// プロジェクトが存在するか確認
-	_, err := s.projectRepo.FindByID(projectID)
+	_, err := s.projectRepo.FindByID(ctx, projectID)
 	if err != nil {
 		return nil, errors.New("プロジェクトが見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(projectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, projectID, userID)
 	if err != nil || !isMember {
 		return nil, errors.New("このプロジェクトにタスクを追加する権限がありません")
 	}
 
 	// 既存のタスク数を取得して順序を決定
-	tasks, err := s.taskRepo.ListByProject(projectID)
+	tasks, err := s.taskRepo.ListByProject(ctx, projectID)
 	orderIndex := 0
 	if err == nil {
 		orderIndex = len(tasks)
@@ -70,14 +77,16 @@ func (s *taskService) Create(title, description string, projectID, userID uint)
 
 	// タスクを作成
 	task := &models.Task{
-		Title:       title,
-		Description: description,
-		ProjectID:   projectID,
-		OrderIndex:  orderIndex,
+		Title:           title,
+		Description:     description,
+		DescriptionHTML: utils.RenderMarkdown(description),
+		ProjectID:       projectID,
+		OrderIndex:      orderIndex,
+		DueAt:           dueAt,
 	}
 
 	// データベースに保存
-	if err := s.taskRepo.Create(task); err != nil {
+	if err := s.taskRepo.Create(ctx, task); err != nil {
 		return nil, fmt.Errorf("タスクの作成に失敗しました: %v", err)
 	}
 
@@ -85,21 +94,21 @@ func (s *taskService) Create(title, description string, projectID, userID uint)
 }
 
 // GetByID IDでタスクを取得
-func (s *taskService) GetByID(id uint, userID uint) (*models.Task, error) {
+func (s *taskService) GetByID(ctx context.Context, id uint, userID uint) (*models.Task, error) {
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(id)
+	task, err := s.taskRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("タスクが見つかりません")
 	}
 
 	// プロジェクトが存在するか確認
-	_, err = s.projectRepo.FindByID(task.ProjectID)
+	_, err = s.projectRepo.FindByID(ctx, task.ProjectID)
 	if err != nil {
 		return nil, errors.New("プロジェクトが見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 	if err != nil || !isMember {
 		return nil, errors.New("このタスクを閲覧する権限がありません")
 	}
@@ -108,9 +117,9 @@ func (s *taskService) GetByID(id uint, userID uint) (*models.Task, error) {
 }
 
 // Update タスクを更新
-func (s *taskService) Update(id, userID uint, title, description string) (*models.Task, error) {
+func (s *taskService) Update(ctx context.Context, id, userID uint, title, description string, dueAt *time.Time, version *uint) (*models.Task, error) {
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(id)
+	task, err := s.taskRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("タスクが見つかりません")
 	}
@@ -121,17 +130,27 @@ func (s *taskService) Update(id, userID uint, title, description string) (*model
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 	if err != nil || !isMember {
 		return nil, errors.New("このタスクを更新する権限がありません")
 	}
+	if err := checkVersion(version, task.Version); err != nil {
+		return nil, err
+	}
 
 	// フィールドを更新
 	task.Title = title
 	task.Description = description
+	task.DescriptionHTML = utils.RenderMarkdown(description)
+	task.DueAt = dueAt
 
 	// データベースを更新
-	if err := s.taskRepo.Update(task); err != nil {
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			if latest, findErr := s.taskRepo.FindByID(ctx, id); findErr == nil {
+				return nil, &VersionConflictError{CurrentVersion: latest.Version}
+			}
+		}
 		return nil, fmt.Errorf("タスクの更新に失敗しました: %v", err)
 	}
 
@@ -139,21 +158,21 @@ func (s *taskService) Update(id, userID uint, title, description string) (*model
 }
 
 // Delete タスクを削除
-func (s *taskService) Delete(id, userID uint) error {
+func (s *taskService) Delete(ctx context.Context, id, userID uint) error {
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(id)
+	task, err := s.taskRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("タスクが見つかりません")
 	}
 
 	// ユーザーがプロジェクトのオーナーかどうか確認
-	isOwner, err := s.projectRepo.IsOwner(task.ProjectID, userID)
+	isOwner, err := s.projectRepo.IsOwner(ctx, task.ProjectID, userID)
 	if err != nil || !isOwner {
 		return errors.New("このタスクを削除する権限がありません")
 	}
 
 	// タスクを削除
-	if err := s.taskRepo.Delete(id); err != nil {
+	if err := s.taskRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("タスクの削除に失敗しました: %v", err)
 	}
 
@@ -161,105 +180,130 @@ func (s *taskService) Delete(id, userID uint) error {
 }
 
 // ListByProject プロジェクトのタスク一覧を取得
-func (s *taskService) ListByProject(projectID, userID uint) ([]models.Task, error) {
+func (s *taskService) ListByProject(ctx context.Context, projectID, userID uint) ([]models.Task, error) {
 	// プロジェクトが存在するか確認
-	_, err := s.projectRepo.FindByID(projectID)
+	_, err := s.projectRepo.FindByID(ctx, projectID)
 	if err != nil {
 		return nil, errors.New("プロジェクトが見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(projectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, projectID, userID)
 	if err != nil || !isMember {
 		return nil, errors.New("このプロジェクトのタスク一覧を閲覧する権限がありません")
 	}
 
 	// タスク一覧を取得
-	return s.taskRepo.ListByProject(projectID)
+	return s.taskRepo.ListByProject(ctx, projectID)
 }
 
 // AddWork 作品をタスクに追加
-func (s *taskService) AddWork(taskID, workID, userID uint) error {
+func (s *taskService) AddWork(ctx context.Context, taskID, workID, userID uint) error {
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(taskID)
+	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil {
 		return errors.New("タスクが見つかりません")
 	}
 
 	// 作品を取得
-	work, err := s.workRepo.FindByID(workID)
+	work, err := s.workRepo.FindByID(ctx, workID)
 	if err != nil {
 		return errors.New("作品が見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 	if err != nil || !isMember {
 		return errors.New("このタスクに作品を追加する権限がありません")
 	}
 
+	// コンテストの場合は受付中のみ
+	if err := s.checkContestOpen(ctx, task.ProjectID); err != nil {
+		return err
+	}
+
 	// 作品の所有者かどうか確認
 	if work.UserID != userID {
 		// オーナーは他のメンバーの作品も追加できる
-		isOwner, err := s.projectRepo.IsOwner(task.ProjectID, userID)
+		isOwner, err := s.projectRepo.IsOwner(ctx, task.ProjectID, userID)
 		if err != nil || !isOwner {
 			return errors.New("他のユーザーの作品をタスクに追加する権限がありません")
 		}
 	}
 
 	// 作品をタスクに追加
-	return s.taskRepo.AddWork(taskID, workID)
+	if err := s.taskRepo.AddWork(ctx, taskID, workID); err != nil {
+		return err
+	}
+
+	// 作者のレピュテーションを加算
+	s.reputationService.Apply(ctx, work.UserID, ReputationSubmissionAccepted)
+	touchProjectMember(ctx, s.projectRepo, task.ProjectID, work.UserID)
+
+	return nil
 }
 
 // RemoveWork 作品をタスクから削除
-func (s *taskService) RemoveWork(taskID, workID, userID uint) error {
+func (s *taskService) RemoveWork(ctx context.Context, taskID, workID, userID uint) error {
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(taskID)
+	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil {
 		return errors.New("タスクが見つかりません")
 	}
 
 	// 作品を取得
-	work, err := s.workRepo.FindByID(workID)
+	work, err := s.workRepo.FindByID(ctx, workID)
 	if err != nil {
 		return errors.New("作品が見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 	if err != nil || !isMember {
 		return errors.New("このタスクから作品を削除する権限がありません")
 	}
 
+	// コンテストの場合は受付中のみ
+	if err := s.checkContestOpen(ctx, task.ProjectID); err != nil {
+		return err
+	}
+
 	// 作品の所有者かどうか確認
 	if work.UserID != userID {
 		// オーナーは他のメンバーの作品も削除できる
-		isOwner, err := s.projectRepo.IsOwner(task.ProjectID, userID)
+		isOwner, err := s.projectRepo.IsOwner(ctx, task.ProjectID, userID)
 		if err != nil || !isOwner {
 			return errors.New("他のユーザーの作品をタスクから削除する権限がありません")
 		}
 	}
 
 	// 作品をタスクから削除
-	return s.taskRepo.RemoveWork(taskID, workID)
+	if err := s.taskRepo.RemoveWork(ctx, taskID, workID); err != nil {
+		return err
+	}
+
+	// 作者のレピュテーションを減算
+	s.reputationService.Apply(ctx, work.UserID, ReputationSubmissionRemoved)
+
+	return nil
 }
 
 // GetWorks タスクの作品一覧を取得
-func (s *taskService) GetWorks(taskID, userID uint, page, limit int) ([]models.Work, int64, int, error) {
+func (s *taskService) GetWorks(ctx context.Context, taskID, userID uint, page, limit int) ([]models.Work, int64, int, error) {
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(taskID)
+	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil {
 		return nil, 0, 0, errors.New("タスクが見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 	if err != nil || !isMember {
 		return nil, 0, 0, errors.New("このタスクの作品一覧を閲覧する権限がありません")
 	}
 
 	// 作品一覧を取得
-	works, total, err := s.taskRepo.GetWorks(taskID, page, limit)
+	works, total, err := s.taskRepo.GetWorks(ctx, taskID, page, limit)
 	if err != nil {
 		return nil, 0, 0, err
 	}
@@ -274,13 +318,13 @@ func (s *taskService) GetWorks(taskID, userID uint, page, limit int) ([]models.W
 }
 
 // UpdateOrders タスクの表示順序を更新
-func (s *taskService) UpdateOrders(taskIDs []uint, orderIndices []int, userID uint) error {
+func (s *taskService) UpdateOrders(ctx context.Context, taskIDs []uint, orderIndices []int, userID uint) error {
 	if len(taskIDs) == 0 || len(taskIDs) != len(orderIndices) {
 		return errors.New("無効なタスクIDまたは順序インデックスです")
 	}
 
 	// 最初のタスクからプロジェクトIDを取得
-	task, err := s.taskRepo.FindByID(taskIDs[0])
+	task, err := s.taskRepo.FindByID(ctx, taskIDs[0])
 	if err != nil {
 		return errors.New("タスクが見つかりません")
 	}
@@ -289,7 +333,7 @@ func (s *taskService) UpdateOrders(taskIDs []uint, orderIndices []int, userID ui
 
 	// 全てのタスクが同じプロジェクトに属しているか確認
 	for _, taskID := range taskIDs {
-		task, err := s.taskRepo.FindByID(taskID)
+		task, err := s.taskRepo.FindByID(ctx, taskID)
 		if err != nil {
 			return errors.New("タスクが見つかりません")
 		}
@@ -300,11 +344,44 @@ func (s *taskService) UpdateOrders(taskIDs []uint, orderIndices []int, userID ui
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(projectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, projectID, userID)
 	if err != nil || !isMember {
 		return errors.New("このプロジェクトのタスク順序を更新する権限がありません")
 	}
 
 	// タスクの順序を更新
-	return s.taskRepo.UpdateOrders(taskIDs, orderIndices)
+	return s.taskRepo.UpdateOrders(ctx, taskIDs, orderIndices)
+}
+
+// GetContestGallery コンテストの応募作品一覧を取得（認証不要）
+func (s *taskService) GetContestGallery(ctx context.Context, projectID uint, page, limit int) ([]models.Work, int64, int, error) {
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil || !project.IsContest || project.ContestTaskID == nil {
+		return nil, 0, 0, errors.New("コンテストが見つかりません")
+	}
+
+	works, total, err := s.taskRepo.GetWorks(ctx, *project.ContestTaskID, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return works, total, pages, nil
+}
+
+// checkContestOpen コンテストのプロジェクトで応募の受付が終了していないか確認
+func (s *taskService) checkContestOpen(ctx context.Context, projectID uint) error {
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return errors.New("プロジェクトが見つかりません")
+	}
+	if project.IsContest && project.ContestPhase != models.ContestPhaseOpen {
+		return errors.New("このコンテストの応募の受付は終了しています")
+	}
+	return nil
 }
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+)
+
+// URLFetcher 利用者が指定したURLを安全に取得するインターフェース
+// 内部のアドレス（ループバック・プライベート・リンクローカルなど）には接続せず、リダイレクト先も同様に検証する
+type URLFetcher interface {
+	// Fetch URLを取得（ステータスコードが200以外・Content-Typeが対象外・サイズ超過の場合はエラー）
+	Fetch(ctx context.Context, rawURL string, opts FetchOptions) (*FetchResult, error)
+	// Validate URLが取得の対象か確認（接続先のアドレスは取得時に確認する）
+	Validate(u *url.URL, allowedHosts []string) error
+}
+
+// FetchOptions 取得ごとの条件（設定より緩い上限は無視する）
+type FetchOptions struct {
+	AllowedHosts []string      // 取得できるホスト（サブドメインを含む、空の場合は全て）
+	ContentTypes []string      // 受け付けるContent-Type（空の場合は全て）
+	MaxBytes     int64         // レスポンスの最大サイズ
+	Timeout      time.Duration // 取得全体のタイムアウト
+	Truncate     bool          // MaxBytesを超える部分を読み捨てる（エラーにしない）
+	Accept       string        // Acceptヘッダー
+}
+
+// FetchResult 取得結果
+type FetchResult struct {
+	URL         *url.URL // リダイレクト後のURL
+	ContentType string   // パラメータを除いたContent-Type
+	Body        []byte
+}
+
+var (
+	// ErrFetchTooLarge レスポンスが最大サイズを超えている
+	ErrFetchTooLarge = errors.New("取得するデータが大きすぎます")
+	// ErrFetchBlocked 接続先（リダイレクト先を含む）が内部のアドレス
+	ErrFetchBlocked = errors.New("内部のアドレスには接続できません")
+)
+
+// 100.64.0.0/10（キャリアグレードNAT）
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// urlFetcher URLFetcherの実装
+type urlFetcher struct {
+	client *http.Client
+	config config.FetchConfig
+}
+
+// NewURLFetcher URLFetcherを作成
+func NewURLFetcher(httpClients HTTPClientFactory, cfg *config.Config) URLFetcher {
+	// 接続先のアドレスを検証するため、プロキシは使わずに直接接続する
+	client := httpClients.NewBareClient(cfg.Fetch.Timeout)
+	client.Timeout = cfg.Fetch.Timeout
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		dialer := &net.Dialer{
+			Timeout: cfg.HTTPClient.ConnectTimeout,
+			Control: fetchDialControl,
+		}
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+	}
+	httpClients.Instrument("fetch", client)
+
+	return &urlFetcher{
+		client: client,
+		config: cfg.Fetch,
+	}
+}
+
+// Fetch URLを取得
+func (f *urlFetcher) Fetch(ctx context.Context, rawURL string, opts FetchOptions) (*FetchResult, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.New("URLが不正です")
+	}
+	if err := f.Validate(u, opts.AllowedHosts); err != nil {
+		return nil, err
+	}
+
+	maxBytes := f.config.MaxBytes
+	if opts.MaxBytes > 0 && opts.MaxBytes < maxBytes {
+		maxBytes = opts.MaxBytes
+	}
+	timeout := f.config.Timeout
+	if opts.Timeout > 0 && opts.Timeout < timeout {
+		timeout = opts.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// リダイレクト先も同じ条件で検証する
+	client := *f.client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > f.config.MaxRedirects {
+			return errors.New("リダイレクトが多すぎます")
+		}
+		return f.Validate(req.URL, opts.AllowedHosts)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Accept != "" {
+		req.Header.Set("Accept", opts.Accept)
+	}
+	req.Header.Set("User-Agent", "SketchShifterFetcher/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ステータスコード %d が返されました", resp.StatusCode)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if len(opts.ContentTypes) > 0 {
+		accepted := false
+		for _, t := range opts.ContentTypes {
+			if t == contentType {
+				accepted = true
+				break
+			}
+		}
+		if !accepted {
+			return nil, fmt.Errorf("対応していない形式です: %s", contentType)
+		}
+	}
+
+	if !opts.Truncate && resp.ContentLength > maxBytes {
+		return nil, ErrFetchTooLarge
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > maxBytes {
+		if !opts.Truncate {
+			return nil, ErrFetchTooLarge
+		}
+		body = body[:maxBytes]
+	}
+
+	return &FetchResult{
+		URL:         resp.Request.URL,
+		ContentType: contentType,
+		Body:        body,
+	}, nil
+}
+
+// Validate URLが取得の対象か確認（http・httpsの標準のポートで、allowedHostsを指定した場合はそのホストのもののみ）
+func (f *urlFetcher) Validate(u *url.URL, allowedHosts []string) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("対応していないURLです")
+	}
+	if u.User != nil {
+		return errors.New("認証情報を含むURLには対応していません")
+	}
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		return errors.New("対応していないポートです")
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return errors.New("URLが不正です")
+	}
+	if len(allowedHosts) == 0 {
+		return nil
+	}
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed != "" && (host == allowed || strings.HasSuffix(host, "."+allowed)) {
+			return nil
+		}
+	}
+	return errors.New("取得できないホストです")
+}
+
+// fetchDialControl 接続先が内部のアドレスの場合は接続しない（DNSの応答で内部のアドレスに向けられる場合を含む）
+func fetchDialControl(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("接続先のアドレスが不正です: %s", host)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() ||
+		sharedAddressSpace.Contains(ip) || ip.Equal(net.IPv4bcast) {
+		return ErrFetchBlocked
+	}
+	return nil
+}
@@ -1,8 +1,14 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"log"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
@@ -10,40 +16,176 @@ import (
 
 // UserService ユーザーに関するサービスインターフェース
 type UserService interface {
-	GetByID(id uint) (*models.User, error)
-	GetUserWorks(userID uint, page, limit int) ([]models.Work, int64, int, error)
-	UpdateProfile(userID uint, name, nickname, bio string) (*models.User, error)
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+	GetUserWorks(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, int, error)
+	UpdateProfile(ctx context.Context, userID uint, name, nickname, bio string) (*models.User, error)
+	UpdateAvatar(ctx context.Context, userID uint, r io.Reader) (*models.Image, error)
+	GetActivityCalendar(ctx context.Context, userID uint) (*ActivityCalendar, error)
+	GetByHandle(ctx context.Context, handle string) (*models.User, error)
+	ChangeHandle(ctx context.Context, userID uint, handle string) (*models.User, error)
+	AssignMissingHandles(ctx context.Context) (int64, error)
+	GetPrivacy(ctx context.Context, userID uint) (*PrivacySettings, error)
+	UpdatePrivacy(ctx context.Context, userID uint, update PrivacyUpdate) (*PrivacySettings, error)
+	GetLikedWorks(ctx context.Context, userID uint, viewerID *uint, page, limit int) ([]LikedWork, int64, int, error)
+	GetContests(ctx context.Context, userID uint, viewerID *uint, page, limit int) ([]models.Project, int64, int, error)
+}
+
+// PrivacySettings ユーザーのプライバシー設定
+type PrivacySettings struct {
+	HideLikes    bool `json:"hide_likes"`    // いいねした作品を公開しない
+	HideProjects bool `json:"hide_projects"` // 参加しているコンテストを公開しない
+	Searchable   bool `json:"searchable"`    // ランキングなどのユーザー一覧に表示する
+}
+
+// PrivacyUpdate プライバシー設定の変更（nilの項目は変更しない）
+type PrivacyUpdate struct {
+	HideLikes    *bool `json:"hide_likes"`
+	HideProjects *bool `json:"hide_projects"`
+	Searchable   *bool `json:"searchable"`
+}
+
+// 活動カレンダーをキャッシュする時間
+const activityCalendarCacheTTL = 10 * time.Minute
+
+// ActivityDay 活動カレンダーの1日分
+type ActivityDay struct {
+	Date     string `json:"date"` // YYYY-MM-DD
+	Works    int64  `json:"works"`
+	Comments int64  `json:"comments"`
+	Total    int64  `json:"total"`
+}
+
+// ActivityCalendar 過去1年間の日ごとの活動量（ヒートマップ表示用）
+type ActivityCalendar struct {
+	From          string        `json:"from"`
+	To            string        `json:"to"`
+	TotalWorks    int64         `json:"total_works"`
+	TotalComments int64         `json:"total_comments"`
+	Days          []ActivityDay `json:"days"` // 活動のない日も含めて日付の古い順
+}
+
+// activityCalendarEntry キャッシュされた活動カレンダー
+type activityCalendarEntry struct {
+	calendar  *ActivityCalendar
+	expiresAt time.Time
 }
 
 // userService UserServiceの実装
 type userService struct {
-	userRepo repository.UserRepository
-	workRepo repository.WorkRepository
+	userRepo     repository.UserRepository
+	workRepo     repository.WorkRepository
+	projectRepo  repository.ProjectRepository
+	imageService ImageService
+
+	mu            sync.RWMutex
+	calendarCache map[uint]activityCalendarEntry // キーはユーザーID
 }
 
 // NewUserService UserServiceを作成
-func NewUserService(userRepo repository.UserRepository, workRepo repository.WorkRepository) UserService {
+func NewUserService(
+	userRepo repository.UserRepository,
+	workRepo repository.WorkRepository,
+	projectRepo repository.ProjectRepository,
+	imageService ImageService,
+) UserService {
 	return &userService{
-		userRepo: userRepo,
-		workRepo: workRepo,
+		userRepo:      userRepo,
+		workRepo:      workRepo,
+		projectRepo:   projectRepo,
+		imageService:  imageService,
+		calendarCache: map[uint]activityCalendarEntry{},
 	}
 }
 
 // GetByID IDでユーザーを取得
-func (s *userService) GetByID(id uint) (*models.User, error) {
-	return s.userRepo.FindByID(id)
+func (s *userService) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.setCollaborativeWorksCount(ctx, user)
+	return user, nil
+}
+
+// GetByHandle ハンドルでユーザーを取得（変更前のハンドルでも取得できる）
+func (s *userService) GetByHandle(ctx context.Context, handle string) (*models.User, error) {
+	handle = strings.ToLower(strings.TrimPrefix(handle, "@"))
+	user, err := s.userRepo.FindByHandle(ctx, handle)
+	if err != nil {
+		if user, err = s.userRepo.FindByPreviousHandle(ctx, handle); err != nil {
+			return nil, errors.New("ユーザーが見つかりません")
+		}
+	}
+	s.setCollaborativeWorksCount(ctx, user)
+	return user, nil
+}
+
+// setCollaborativeWorksCount プロフィールに共同編集した作品の数を設定（取得に失敗した場合は設定しない）
+func (s *userService) setCollaborativeWorksCount(ctx context.Context, user *models.User) {
+	count, err := s.workRepo.CountCollaborative(ctx, user.ID)
+	if err != nil {
+		log.Printf("共同編集した作品の数の取得に失敗しました (UserID=%d): %v", user.ID, err)
+		return
+	}
+	user.CollaborativeWorksCount = &count
+}
+
+// ChangeHandle ハンドルを変更（変更前のハンドルは引き続き自分のプロフィールを指す）
+func (s *userService) ChangeHandle(ctx context.Context, userID uint, handle string) (*models.User, error) {
+	handle, err := normalizeHandle(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	taken, err := s.userRepo.IsHandleTaken(ctx, handle, userID)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, errors.New("このハンドルは既に使用されています")
+	}
+
+	if err := s.userRepo.ChangeHandle(ctx, userID, handle); err != nil {
+		return nil, fmt.Errorf("ハンドルの変更に失敗しました: %v", err)
+	}
+	return s.userRepo.FindByID(ctx, userID)
+}
+
+// AssignMissingHandles ハンドルが未設定のユーザーにニックネームを元にしたハンドルを割り当て（マイグレーション用）
+func (s *userService) AssignMissingHandles(ctx context.Context) (int64, error) {
+	var assigned int64
+	for {
+		users, err := s.userRepo.ListWithoutHandle(ctx, 100)
+		if err != nil {
+			return assigned, err
+		}
+		if len(users) == 0 {
+			return assigned, nil
+		}
+
+		for _, user := range users {
+			handle, err := generateHandle(ctx, s.userRepo, user.Nickname)
+			if err != nil {
+				return assigned, err
+			}
+			if err := s.userRepo.ChangeHandle(ctx, user.ID, handle); err != nil {
+				return assigned, fmt.Errorf("ユーザー(ID=%d)のハンドルの割り当てに失敗しました: %v", user.ID, err)
+			}
+			assigned++
+		}
+	}
 }
 
 // GetUserWorks ユーザーの作品一覧を取得
-func (s *userService) GetUserWorks(userID uint, page, limit int) ([]models.Work, int64, int, error) {
+func (s *userService) GetUserWorks(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, int, error) {
 	// ユーザーが存在するか確認
-	_, err := s.userRepo.FindByID(userID)
+	_, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, 0, 0, errors.New("ユーザーが見つかりません")
 	}
 
 	// 作品一覧を取得
-	works, total, err := s.workRepo.ListByUser(userID, page, limit)
+	works, total, err := s.workRepo.ListByUser(ctx, userID, page, limit)
 	if err != nil {
 		return nil, 0, 0, err
 	}
@@ -58,9 +200,9 @@ func (s *userService) GetUserWorks(userID uint, page, limit int) ([]models.Work,
 }
 
 // UpdateProfile ユーザープロフィールを更新
-func (s *userService) UpdateProfile(userID uint, name, nickname, bio string) (*models.User, error) {
+func (s *userService) UpdateProfile(ctx context.Context, userID uint, name, nickname, bio string) (*models.User, error) {
 	// ユーザーを取得
-	user, err := s.userRepo.FindByID(userID)
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -77,9 +219,191 @@ func (s *userService) UpdateProfile(userID uint, name, nickname, bio string) (*m
 	user.Bio = bio
 
 	// データベースを更新
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
+
+// UpdateAvatar アバター画像を変換して保存し、差し替える（最初の派生画像をアバターのURLにする）
+func (s *userService) UpdateAvatar(ctx context.Context, userID uint, r io.Reader) (*models.Image, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ユーザーが見つかりません")
+	}
+
+	image, err := s.imageService.Upload(ctx, userID, models.ImageKindAvatar, r)
+	if err != nil {
+		return nil, err
+	}
+
+	previousID := user.AvatarID
+	user.AvatarID = &image.ID
+	user.AvatarURL = image.URL
+	if len(image.Variants) > 0 {
+		user.AvatarURL = image.Variants[0].URL
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		s.imageService.Delete(ctx, image.ID)
+		return nil, fmt.Errorf("アバターの更新に失敗しました: %v", err)
+	}
+
+	// 差し替え前の画像は参照されなくなるため削除する
+	if previousID != nil {
+		if err := s.imageService.Delete(ctx, *previousID); err != nil {
+			log.Printf("ユーザー %d の古いアバター画像の削除に失敗しました: %v", userID, err)
+		}
+	}
+
+	return image, nil
+}
+
+// GetPrivacy プライバシー設定を取得
+func (s *userService) GetPrivacy(ctx context.Context, userID uint) (*PrivacySettings, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ユーザーが見つかりません")
+	}
+	return privacyOf(user), nil
+}
+
+// UpdatePrivacy プライバシー設定を変更
+func (s *userService) UpdatePrivacy(ctx context.Context, userID uint, update PrivacyUpdate) (*PrivacySettings, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("ユーザーが見つかりません")
+	}
+
+	if update.HideLikes != nil {
+		user.HideLikes = *update.HideLikes
+	}
+	if update.HideProjects != nil {
+		user.HideProjects = *update.HideProjects
+	}
+	if update.Searchable != nil {
+		user.HideFromSearch = !*update.Searchable
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, fmt.Errorf("プライバシー設定の変更に失敗しました: %v", err)
+	}
+	return privacyOf(user), nil
+}
+
+// privacyOf ユーザーのプライバシー設定
+func privacyOf(user *models.User) *PrivacySettings {
+	return &PrivacySettings{
+		HideLikes:    user.HideLikes,
+		HideProjects: user.HideProjects,
+		Searchable:   !user.HideFromSearch,
+	}
+}
+
+// GetLikedWorks ユーザーがいいねした作品一覧を取得（いいねを非公開にしている場合は本人のみ）
+func (s *userService) GetLikedWorks(ctx context.Context, userID uint, viewerID *uint, page, limit int) ([]LikedWork, int64, int, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, 0, 0, errors.New("ユーザーが見つかりません")
+	}
+	if user.HideLikes && (viewerID == nil || *viewerID != userID) {
+		return nil, 0, 0, errors.New("このユーザーのいいねを閲覧する権限がありません")
+	}
+
+	likes, total, err := s.workRepo.ListLikedByUser(ctx, userID, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	works := make([]LikedWork, 0, len(likes))
+	for _, like := range likes {
+		works = append(works, LikedWork{Work: like.Work, LikedAt: like.CreatedAt})
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return works, total, pages, nil
+}
+
+// GetContests ユーザーが参加しているコンテスト一覧を取得（参加しているプロジェクトを非公開にしている場合は本人のみ）
+// 招待制のプロジェクトはメンバー以外に見せないため、コンテストのみを対象とする
+func (s *userService) GetContests(ctx context.Context, userID uint, viewerID *uint, page, limit int) ([]models.Project, int64, int, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, 0, 0, errors.New("ユーザーが見つかりません")
+	}
+	if user.HideProjects && (viewerID == nil || *viewerID != userID) {
+		return nil, 0, 0, errors.New("このユーザーのプロジェクトを閲覧する権限がありません")
+	}
+
+	projects, total, err := s.projectRepo.ListContestsByMember(ctx, userID, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return projects, total, pages, nil
+}
+
+// GetActivityCalendar ユーザーの過去1年間の活動量を日ごとに取得
+func (s *userService) GetActivityCalendar(ctx context.Context, userID uint) (*ActivityCalendar, error) {
+	s.mu.RLock()
+	entry, ok := s.calendarCache[userID]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.calendar, nil
+	}
+
+	// ユーザーが存在するか確認
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, errors.New("ユーザーが見つかりません")
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today.AddDate(-1, 0, 1)
+
+	counts, err := s.userRepo.CountDailyActivity(ctx, userID, from)
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[string]repository.DailyActivityCount, len(counts))
+	for _, c := range counts {
+		byDate[c.Date] = c
+	}
+
+	// 活動のない日も0件として埋める
+	calendar := &ActivityCalendar{
+		From: from.Format("2006-01-02"),
+		To:   today.Format("2006-01-02"),
+	}
+	for day := from; !day.After(today); day = day.AddDate(0, 0, 1) {
+		c := byDate[day.Format("2006-01-02")]
+		calendar.Days = append(calendar.Days, ActivityDay{
+			Date:     day.Format("2006-01-02"),
+			Works:    c.Works,
+			Comments: c.Comments,
+			Total:    c.Works + c.Comments,
+		})
+		calendar.TotalWorks += c.Works
+		calendar.TotalComments += c.Comments
+	}
+
+	s.mu.Lock()
+	s.calendarCache[userID] = activityCalendarEntry{
+		calendar:  calendar,
+		expiresAt: now.Add(activityCalendarCacheTTL),
+	}
+	s.mu.Unlock()
+
+	return calendar, nil
+}
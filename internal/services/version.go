@@ -0,0 +1,19 @@
+package services
+
+// VersionConflictError 楽観的ロックのバージョンが古い場合のエラー（最新のバージョンを含む）
+type VersionConflictError struct {
+	CurrentVersion uint
+}
+
+// Error エラーメッセージ
+func (e *VersionConflictError) Error() string {
+	return "他の更新と競合しました。最新の内容を取得してからやり直してください"
+}
+
+// checkVersion クライアントが編集を始めた時点のバージョンが最新か確認する（未指定の場合は確認しない）
+func checkVersion(expected *uint, current uint) error {
+	if expected != nil && *expected != current {
+		return &VersionConflictError{CurrentVersion: current}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+)
+
+// 動画の変換のタイムアウト
+const videoTranscodeTimeout = 10 * time.Minute
+
+// videoContentTypes 添付できる動画の拡張子とContent-Type
+var videoContentTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".webm": "video/webm",
+}
+
+// VideoService 作品のデモ動画に関するサービスインターフェース
+// JSに正しく変換できないスケッチの動作を動画で紹介するために使用する
+type VideoService interface {
+	Upload(ctx context.Context, workID, userID uint, filename string, size int64, r io.Reader) (*models.Work, error)
+	Attach(ctx context.Context, workID, userID, assetID uint) (*models.Work, error)
+	Detach(ctx context.Context, workID, userID uint) (*models.Work, error)
+}
+
+// videoService VideoServiceの実装
+type videoService struct {
+	workRepo  repository.WorkRepository
+	assetRepo repository.AssetRepository
+	storage   StorageService
+	config    *config.Config
+}
+
+// NewVideoService VideoServiceを作成
+func NewVideoService(workRepo repository.WorkRepository, assetRepo repository.AssetRepository, storage StorageService, cfg *config.Config) VideoService {
+	return &videoService{
+		workRepo:  workRepo,
+		assetRepo: assetRepo,
+		storage:   storage,
+		config:    cfg,
+	}
+}
+
+// findOwnWork 自分の作品を取得
+func (s *videoService) findOwnWork(ctx context.Context, workID, userID uint) (*models.Work, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	if work.UserID != userID {
+		return nil, errors.New("この作品の動画を変更する権限がありません")
+	}
+	return work, nil
+}
+
+// validate 動画のファイル名とサイズを確認し、Content-Typeを返す
+func (s *videoService) validate(filename string, size int64) (string, error) {
+	contentType, ok := videoContentTypes[strings.ToLower(filepath.Ext(filename))]
+	if !ok {
+		return "", errors.New("動画の形式はmp4またはwebmである必要があります")
+	}
+	if size > int64(s.config.Video.MaxSizeMB)*1024*1024 {
+		return "", fmt.Errorf("動画のサイズが上限（%dMB）を超えています", s.config.Video.MaxSizeMB)
+	}
+	return contentType, nil
+}
+
+// Upload 動画をアップロードして作品に添付
+func (s *videoService) Upload(ctx context.Context, workID, userID uint, filename string, size int64, r io.Reader) (*models.Work, error) {
+	if _, err := s.findOwnWork(ctx, workID, userID); err != nil {
+		return nil, err
+	}
+	contentType, err := s.validate(filename, size)
+	if err != nil {
+		return nil, err
+	}
+
+	asset := &models.WorkAsset{
+		WorkID:      workID,
+		UserID:      userID,
+		Filename:    filepath.Base(filename),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  fmt.Sprintf("works/%d/assets/%s%s", workID, utils.GenerateRandomString(32), strings.ToLower(filepath.Ext(filename))),
+	}
+	if _, err := s.storage.WriteAt(ctx, asset.StorageKey, 0, io.LimitReader(r, size)); err != nil {
+		return nil, fmt.Errorf("動画の保存に失敗しました: %v", err)
+	}
+	if err := s.assetRepo.Create(ctx, asset); err != nil {
+		s.storage.Delete(ctx, asset.StorageKey)
+		return nil, fmt.Errorf("動画の保存に失敗しました: %v", err)
+	}
+
+	return s.Attach(ctx, workID, userID, asset.ID)
+}
+
+// Attach アップロード済みのアセットを作品の動画として添付
+func (s *videoService) Attach(ctx context.Context, workID, userID, assetID uint) (*models.Work, error) {
+	work, err := s.findOwnWork(ctx, workID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	asset, err := s.assetRepo.FindByID(ctx, assetID)
+	if err != nil || asset.WorkID != workID {
+		return nil, errors.New("アセットが見つかりません")
+	}
+	if _, err := s.validate(asset.Filename, asset.Size); err != nil {
+		return nil, err
+	}
+	if err := s.checkSignature(ctx, asset); err != nil {
+		return nil, err
+	}
+
+	work.VideoAssetID = &asset.ID
+	work.VideoURL = s.assetURL(asset.ID)
+	work.VideoStatus = models.VideoStatusReady
+	if s.config.Video.Transcode {
+		work.VideoStatus = models.VideoStatusProcessing
+	}
+	if err := s.workRepo.UpdateVideo(ctx, work); err != nil {
+		return nil, fmt.Errorf("動画の添付に失敗しました: %v", err)
+	}
+
+	// 変換はバックグラウンドで行い、完了するまでは元の動画を表示する
+	if s.config.Video.Transcode {
+		go s.transcode(work.ID, asset)
+	}
+
+	return work, nil
+}
+
+// Detach 作品の動画を外す（アセットは残る）
+func (s *videoService) Detach(ctx context.Context, workID, userID uint) (*models.Work, error) {
+	work, err := s.findOwnWork(ctx, workID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	work.VideoAssetID = nil
+	work.VideoURL = ""
+	work.VideoStatus = ""
+	if err := s.workRepo.UpdateVideo(ctx, work); err != nil {
+		return nil, fmt.Errorf("動画の削除に失敗しました: %v", err)
+	}
+	return work, nil
+}
+
+// checkSignature ファイルの先頭を読み、拡張子どおりの動画か確認
+func (s *videoService) checkSignature(ctx context.Context, asset *models.WorkAsset) error {
+	file, err := s.storage.Open(ctx, asset.StorageKey)
+	if err != nil {
+		return fmt.Errorf("動画の読み込みに失敗しました: %v", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return errors.New("動画ファイルが壊れています")
+	}
+
+	switch videoContentTypes[strings.ToLower(filepath.Ext(asset.Filename))] {
+	case "video/mp4":
+		if string(header[4:8]) == "ftyp" {
+			return nil
+		}
+	case "video/webm":
+		if bytes.Equal(header[:4], []byte{0x1A, 0x45, 0xDF, 0xA3}) {
+			return nil
+		}
+	}
+	return errors.New("動画ファイルの内容が拡張子と一致しません")
+}
+
+// assetURL アセットを配信するURL
+func (s *videoService) assetURL(assetID uint) string {
+	return fmt.Sprintf("%s/api/v1/assets/%d", strings.TrimSuffix(s.config.Server.APIBaseURL, "/"), assetID)
+}
+
+// transcode 動画をブラウザで再生しやすいmp4（H.264/AAC）に変換して差し替える
+func (s *videoService) transcode(workID uint, source *models.WorkAsset) {
+	ctx, cancel := context.WithTimeout(context.Background(), videoTranscodeTimeout)
+	defer cancel()
+
+	status := models.VideoStatusReady
+	asset, err := s.runTranscode(ctx, source)
+	if err != nil {
+		log.Printf("[VIDEO] 作品 %d の動画の変換に失敗しました: %v", workID, err)
+		status = models.VideoStatusFailed
+	}
+
+	// 変換中に別の動画に差し替えられた場合は何もしない
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil || work.VideoAssetID == nil || *work.VideoAssetID != source.ID {
+		return
+	}
+	if asset != nil {
+		work.VideoAssetID = &asset.ID
+		work.VideoURL = s.assetURL(asset.ID)
+	}
+	work.VideoStatus = status
+	if err := s.workRepo.UpdateVideo(ctx, work); err != nil {
+		log.Printf("[VIDEO] 作品 %d の動画の更新に失敗しました: %v", workID, err)
+	}
+}
+
+// runTranscode ffmpegで変換し、変換後のアセットを作成
+func (s *videoService) runTranscode(ctx context.Context, source *models.WorkAsset) (*models.WorkAsset, error) {
+	dir, err := os.MkdirTemp("", "video-transcode-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	// ffmpegはローカルのファイルを扱うため、一時ディレクトリにコピーする
+	input := filepath.Join(dir, "input"+filepath.Ext(source.Filename))
+	output := filepath.Join(dir, "output.mp4")
+	if err := s.copyToFile(ctx, source.StorageKey, input); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, s.config.Video.FFmpegPath,
+		"-y", "-i", input,
+		"-c:v", "libx264", "-preset", "veryfast", "-crf", "28", "-pix_fmt", "yuv420p",
+		"-c:a", "aac", "-movflags", "+faststart",
+		output,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+	}
+
+	file, err := os.Open(output)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	asset := &models.WorkAsset{
+		WorkID:      source.WorkID,
+		UserID:      source.UserID,
+		Filename:    strings.TrimSuffix(source.Filename, filepath.Ext(source.Filename)) + ".mp4",
+		ContentType: "video/mp4",
+		Size:        info.Size(),
+		StorageKey:  fmt.Sprintf("works/%d/assets/%s.mp4", source.WorkID, utils.GenerateRandomString(32)),
+	}
+	if _, err := s.storage.WriteAt(ctx, asset.StorageKey, 0, file); err != nil {
+		return nil, err
+	}
+	if err := s.assetRepo.Create(ctx, asset); err != nil {
+		s.storage.Delete(ctx, asset.StorageKey)
+		return nil, err
+	}
+	return asset, nil
+}
+
+// copyToFile ストレージのデータをローカルのファイルにコピー
+func (s *videoService) copyToFile(ctx context.Context, key, path string) error {
+	src, err := s.storage.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	return err
+}
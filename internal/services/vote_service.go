@@ -1,35 +1,49 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
+	"time"
 
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
 )
 
 // VoteService 投票に関するサービスインターフェース
 type VoteService interface {
-	Create(title, description string, taskID uint, multiSelect bool, userID uint) (*models.Vote, error)
-	GetByID(id, userID uint) (*models.Vote, error)
-	Update(id, userID uint, title, description string, multiSelect bool) (*models.Vote, error)
-	Delete(id, userID uint) error
-	ListByTask(taskID, userID uint) ([]models.Vote, error)
-	AddOption(voteID, userID uint, optionText string, workID *uint) (*models.VoteOption, error)
-	DeleteOption(optionID, userID uint) error
-	Vote(voteID, optionID, userID uint) error
-	RemoveVote(voteID, optionID, userID uint) error
-	GetUserVotes(voteID, userID uint) ([]models.VoteResponse, error)
-	CloseVote(voteID, userID uint) error
+	Create(ctx context.Context, title, description string, taskID uint, multiSelect, anonymous bool, closesAt *time.Time, userID uint) (*models.Vote, error)
+	GetByID(ctx context.Context, id, userID uint) (*models.Vote, error)
+	Update(ctx context.Context, id, userID uint, title, description string, multiSelect bool, closesAt *time.Time) (*models.Vote, error)
+	Delete(ctx context.Context, id, userID uint) error
+	ListByTask(ctx context.Context, taskID, userID uint) ([]models.Vote, error)
+	AddOption(ctx context.Context, voteID, userID uint, optionText string, workID *uint) (*models.VoteOption, error)
+	DeleteOption(ctx context.Context, optionID, userID uint) error
+	Vote(ctx context.Context, voteID, optionID, userID uint) error
+	RemoveVote(ctx context.Context, voteID, optionID, userID uint) error
+	GetUserVotes(ctx context.Context, voteID, userID uint) ([]models.VoteResponse, error)
+	CloseVote(ctx context.Context, voteID, userID uint) error
+	CloseExpiredVotes(ctx context.Context) (int, error)
+	ExportResults(ctx context.Context, voteID, userID uint, start func(vote *models.Vote) error, fn func(responses []models.VoteResponse) error) error
 }
 
+// 投票結果のエクスポートで一度に読み込む回答の件数
+const voteExportBatchSize = 500
+
 // voteService VoteServiceの実装
 type voteService struct {
-	voteRepo    repository.VoteRepository
-	taskRepo    repository.TaskRepository
-	projectRepo repository.ProjectRepository
-	workRepo    repository.WorkRepository
+	voteRepo            repository.VoteRepository
+	taskRepo            repository.TaskRepository
+	projectRepo         repository.ProjectRepository
+	workRepo            repository.WorkRepository
+	activityRepo        repository.ActivityRepository
+	badgeRepo           repository.BadgeRepository
+	notificationService NotificationService
+	reputationService   ReputationService
+	config              *config.Config
 }
 
 // NewVoteService VoteServiceを作成
@@ -38,30 +52,45 @@ func NewVoteService(
 	taskRepo repository.TaskRepository,
 	projectRepo repository.ProjectRepository,
 	workRepo repository.WorkRepository,
+	activityRepo repository.ActivityRepository,
+	badgeRepo repository.BadgeRepository,
+	notificationService NotificationService,
+	reputationService ReputationService,
+	cfg *config.Config,
 ) VoteService {
 	return &voteService{
-		voteRepo:    voteRepo,
-		taskRepo:    taskRepo,
-		projectRepo: projectRepo,
-		workRepo:    workRepo,
+		voteRepo:            voteRepo,
+		taskRepo:            taskRepo,
+		projectRepo:         projectRepo,
+		workRepo:            workRepo,
+		activityRepo:        activityRepo,
+		badgeRepo:           badgeRepo,
+		notificationService: notificationService,
+		reputationService:   reputationService,
+		config:              cfg,
 	}
 }
 
 // Create 新しい投票を作成
-func (s *voteService) Create(title, description string, taskID uint, multiSelect bool, userID uint) (*models.Vote, error) {
+func (s *voteService) Create(ctx context.Context, title, description string, taskID uint, multiSelect, anonymous bool, closesAt *time.Time, userID uint) (*models.Vote, error) {
 	// タイトルのバリデーション
 	if strings.TrimSpace(title) == "" {
 		return nil, errors.New("タイトルは必須です")
 	}
 
+	// 締め切りのバリデーション
+	if closesAt != nil && closesAt.Before(time.Now()) {
+		return nil, errors.New("締め切りは未来の日時を指定してください")
+	}
+
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(taskID)
+	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil {
 		return nil, errors.New("タスクが見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 	if err != nil || !isMember {
 		return nil, errors.New("このタスクに投票を追加する権限がありません")
 	}
@@ -72,34 +101,36 @@ func (s *voteService) Create(title, description string, taskID uint, multiSelect
 		Description: description,
 		TaskID:      taskID,
 		MultiSelect: multiSelect,
+		Anonymous:   anonymous,
 		IsActive:    true,
 		CreatedBy:   userID,
+		ClosesAt:    closesAt,
 	}
 
 	// データベースに保存
-	if err := s.voteRepo.Create(vote); err != nil {
+	if err := s.voteRepo.Create(ctx, vote); err != nil {
 		return nil, fmt.Errorf("投票の作成に失敗しました: %v", err)
 	}
 
-	return s.GetByID(vote.ID, userID)
+	return s.GetByID(ctx, vote.ID, userID)
 }
 
 // GetByID IDで投票を取得
-func (s *voteService) GetByID(id, userID uint) (*models.Vote, error) {
+func (s *voteService) GetByID(ctx context.Context, id, userID uint) (*models.Vote, error) {
 	// 投票を取得
-	vote, err := s.voteRepo.FindByID(id)
+	vote, err := s.voteRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("投票が見つかりません")
 	}
 
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(vote.TaskID)
+	task, err := s.taskRepo.FindByID(ctx, vote.TaskID)
 	if err != nil {
 		return nil, errors.New("タスクが見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 	if err != nil || !isMember {
 		return nil, errors.New("この投票を閲覧する権限がありません")
 	}
@@ -108,9 +139,9 @@ func (s *voteService) GetByID(id, userID uint) (*models.Vote, error) {
 }
 
 // Update 投票を更新
-func (s *voteService) Update(id, userID uint, title, description string, multiSelect bool) (*models.Vote, error) {
+func (s *voteService) Update(ctx context.Context, id, userID uint, title, description string, multiSelect bool, closesAt *time.Time) (*models.Vote, error) {
 	// 投票を取得
-	vote, err := s.voteRepo.FindByID(id)
+	vote, err := s.voteRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("投票が見つかりません")
 	}
@@ -121,14 +152,14 @@ func (s *voteService) Update(id, userID uint, title, description string, multiSe
 	}
 
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(vote.TaskID)
+	task, err := s.taskRepo.FindByID(ctx, vote.TaskID)
 	if err != nil {
 		return nil, errors.New("タスクが見つかりません")
 	}
 
 	// 投票が作成者またはプロジェクトのオーナーかどうか確認
 	if vote.CreatedBy != userID {
-		isOwner, err := s.projectRepo.IsOwner(task.ProjectID, userID)
+		isOwner, err := s.projectRepo.IsOwner(ctx, task.ProjectID, userID)
 		if err != nil || !isOwner {
 			return nil, errors.New("この投票を更新する権限がありません")
 		}
@@ -137,7 +168,7 @@ func (s *voteService) Update(id, userID uint, title, description string, multiSe
 	// すでに投票が行われている場合は、マルチセレクト設定を変更できない
 	if vote.MultiSelect != multiSelect {
 		// 投票がすでに行われているか確認
-		voteOptions, err := s.voteRepo.GetOptions(id)
+		voteOptions, err := s.voteRepo.GetOptions(ctx, id)
 		if err != nil {
 			return nil, fmt.Errorf("投票オプションの取得に失敗しました: %v", err)
 		}
@@ -149,43 +180,49 @@ func (s *voteService) Update(id, userID uint, title, description string, multiSe
 		}
 	}
 
+	// 締め切りのバリデーション
+	if closesAt != nil && closesAt.Before(time.Now()) {
+		return nil, errors.New("締め切りは未来の日時を指定してください")
+	}
+
 	// フィールドを更新
 	vote.Title = title
 	vote.Description = description
 	vote.MultiSelect = multiSelect
+	vote.ClosesAt = closesAt
 
 	// データベースを更新
-	if err := s.voteRepo.Update(vote); err != nil {
+	if err := s.voteRepo.Update(ctx, vote); err != nil {
 		return nil, fmt.Errorf("投票の更新に失敗しました: %v", err)
 	}
 
-	return s.GetByID(id, userID)
+	return s.GetByID(ctx, id, userID)
 }
 
 // Delete 投票を削除
-func (s *voteService) Delete(id, userID uint) error {
+func (s *voteService) Delete(ctx context.Context, id, userID uint) error {
 	// 投票を取得
-	vote, err := s.voteRepo.FindByID(id)
+	vote, err := s.voteRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("投票が見つかりません")
 	}
 
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(vote.TaskID)
+	task, err := s.taskRepo.FindByID(ctx, vote.TaskID)
 	if err != nil {
 		return errors.New("タスクが見つかりません")
 	}
 
 	// 投票が作成者またはプロジェクトのオーナーかどうか確認
 	if vote.CreatedBy != userID {
-		isOwner, err := s.projectRepo.IsOwner(task.ProjectID, userID)
+		isOwner, err := s.projectRepo.IsOwner(ctx, task.ProjectID, userID)
 		if err != nil || !isOwner {
 			return errors.New("この投票を削除する権限がありません")
 		}
 	}
 
 	// 投票を削除
-	if err := s.voteRepo.Delete(id); err != nil {
+	if err := s.voteRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("投票の削除に失敗しました: %v", err)
 	}
 
@@ -193,39 +230,39 @@ func (s *voteService) Delete(id, userID uint) error {
 }
 
 // ListByTask タスクの投票一覧を取得
-func (s *voteService) ListByTask(taskID, userID uint) ([]models.Vote, error) {
+func (s *voteService) ListByTask(ctx context.Context, taskID, userID uint) ([]models.Vote, error) {
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(taskID)
+	task, err := s.taskRepo.FindByID(ctx, taskID)
 	if err != nil {
 		return nil, errors.New("タスクが見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 	if err != nil || !isMember {
 		return nil, errors.New("このタスクの投票一覧を閲覧する権限がありません")
 	}
 
 	// 投票一覧を取得
-	return s.voteRepo.ListByTask(taskID)
+	return s.voteRepo.ListByTask(ctx, taskID)
 }
 
 // AddOption 投票オプションを追加
-func (s *voteService) AddOption(voteID, userID uint, optionText string, workID *uint) (*models.VoteOption, error) {
+func (s *voteService) AddOption(ctx context.Context, voteID, userID uint, optionText string, workID *uint) (*models.VoteOption, error) {
 	// 投票を取得
-	vote, err := s.voteRepo.FindByID(voteID)
+	vote, err := s.voteRepo.FindByID(ctx, voteID)
 	if err != nil {
 		return nil, errors.New("投票が見つかりません")
 	}
 
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(vote.TaskID)
+	task, err := s.taskRepo.FindByID(ctx, vote.TaskID)
 	if err != nil {
 		return nil, errors.New("タスクが見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 	if err != nil || !isMember {
 		return nil, errors.New("この投票にオプションを追加する権限がありません")
 	}
@@ -237,7 +274,7 @@ func (s *voteService) AddOption(voteID, userID uint, optionText string, workID *
 
 	// 作品IDがある場合は、作品が存在するか確認
 	if workID != nil {
-		_, err := s.workRepo.FindByID(*workID)
+		_, err := s.workRepo.FindByID(ctx, *workID)
 		if err != nil {
 			return nil, errors.New("作品が見つかりません")
 		}
@@ -251,36 +288,36 @@ func (s *voteService) AddOption(voteID, userID uint, optionText string, workID *
 	}
 
 	// データベースに保存
-	if err := s.voteRepo.CreateOption(option); err != nil {
+	if err := s.voteRepo.CreateOption(ctx, option); err != nil {
 		return nil, fmt.Errorf("投票オプションの作成に失敗しました: %v", err)
 	}
 
-	return s.voteRepo.FindOptionByID(option.ID)
+	return s.voteRepo.FindOptionByID(ctx, option.ID)
 }
 
 // DeleteOption 投票オプションを削除
-func (s *voteService) DeleteOption(optionID, userID uint) error {
+func (s *voteService) DeleteOption(ctx context.Context, optionID, userID uint) error {
 	// オプションを取得
-	option, err := s.voteRepo.FindOptionByID(optionID)
+	option, err := s.voteRepo.FindOptionByID(ctx, optionID)
 	if err != nil {
 		return errors.New("投票オプションが見つかりません")
 	}
 
 	// 投票を取得
-	vote, err := s.voteRepo.FindByID(option.VoteID)
+	vote, err := s.voteRepo.FindByID(ctx, option.VoteID)
 	if err != nil {
 		return errors.New("投票が見つかりません")
 	}
 
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(vote.TaskID)
+	task, err := s.taskRepo.FindByID(ctx, vote.TaskID)
 	if err != nil {
 		return errors.New("タスクが見つかりません")
 	}
 
 	// 投票が作成者またはプロジェクトのオーナーかどうか確認
 	if vote.CreatedBy != userID {
-		isOwner, err := s.projectRepo.IsOwner(task.ProjectID, userID)
+		isOwner, err := s.projectRepo.IsOwner(ctx, task.ProjectID, userID)
 		if err != nil || !isOwner {
 			return errors.New("この投票オプションを削除する権限がありません")
 		}
@@ -292,7 +329,7 @@ func (s *voteService) DeleteOption(optionID, userID uint) error {
 	}
 
 	// オプションを削除
-	if err := s.voteRepo.DeleteOption(optionID); err != nil {
+	if err := s.voteRepo.DeleteOption(ctx, optionID); err != nil {
 		return fmt.Errorf("投票オプションの削除に失敗しました: %v", err)
 	}
 
@@ -300,9 +337,9 @@ func (s *voteService) DeleteOption(optionID, userID uint) error {
 }
 
 // Vote 投票する
-func (s *voteService) Vote(voteID, optionID, userID uint) error {
+func (s *voteService) Vote(ctx context.Context, voteID, optionID, userID uint) error {
 	// 投票を取得
-	vote, err := s.voteRepo.FindByID(voteID)
+	vote, err := s.voteRepo.FindByID(ctx, voteID)
 	if err != nil {
 		return errors.New("投票が見つかりません")
 	}
@@ -313,7 +350,7 @@ func (s *voteService) Vote(voteID, optionID, userID uint) error {
 	}
 
 	// オプションを取得
-	option, err := s.voteRepo.FindOptionByID(optionID)
+	option, err := s.voteRepo.FindOptionByID(ctx, optionID)
 	if err != nil {
 		return errors.New("投票オプションが見つかりません")
 	}
@@ -324,67 +361,65 @@ func (s *voteService) Vote(voteID, optionID, userID uint) error {
 	}
 
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(vote.TaskID)
+	task, err := s.taskRepo.FindByID(ctx, vote.TaskID)
 	if err != nil {
 		return errors.New("タスクが見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 	if err != nil || !isMember {
 		return errors.New("この投票に参加する権限がありません")
 	}
 
-	// マルチセレクトでない場合は、既存の投票を取得
-	if !vote.MultiSelect {
-		// ユーザーの投票を取得
-		responses, err := s.voteRepo.GetUserResponses(voteID, userID)
-		if err != nil {
-			return fmt.Errorf("投票情報の取得に失敗しました: %v", err)
-		}
-
-		// すでに同じオプションに投票している場合は何もしない
-		for _, response := range responses {
-			if response.OptionID == optionID {
-				return nil
-			}
-		}
-
-		// 他のオプションに投票している場合は削除
-		for _, response := range responses {
-			if err := s.voteRepo.RemoveResponse(voteID, response.OptionID, userID); err != nil {
-				return fmt.Errorf("既存の投票の削除に失敗しました: %v", err)
-			}
-		}
-	} else {
-		// マルチセレクトの場合は、すでに同じオプションに投票していないか確認
-		responses, err := s.voteRepo.GetUserResponses(voteID, userID)
-		if err != nil {
-			return fmt.Errorf("投票情報の取得に失敗しました: %v", err)
-		}
-
-		// すでに同じオプションに投票している場合は何もしない
-		for _, response := range responses {
-			if response.OptionID == optionID {
-				return nil
-			}
-		}
+	// すでに同じオプションに投票している場合は何もしない
+	if voted, err := s.hasVotedFor(ctx, voteID, optionID, userID); err != nil {
+		return err
+	} else if voted {
+		return nil
 	}
 
-	// 投票を追加
+	// 投票を追加（マルチセレクトでない場合は、他のオプションへの投票を同じトランザクションで置き換える）
 	response := &models.VoteResponse{
 		VoteID:   voteID,
 		OptionID: optionID,
 		UserID:   userID,
 	}
 
-	return s.voteRepo.AddResponse(response)
+	err = s.voteRepo.AddResponse(ctx, response)
+	if errors.Is(err, repository.ErrDuplicateResponse) {
+		// 同時に投票された場合、同じオプションであれば投票済みとして扱う
+		if voted, checkErr := s.hasVotedFor(ctx, voteID, optionID, userID); checkErr == nil && voted {
+			return nil
+		}
+		return errors.New("他の投票と競合しました。もう一度お試しください")
+	}
+	if err != nil {
+		return err
+	}
+
+	touchProjectMember(ctx, s.projectRepo, task.ProjectID, userID)
+	return nil
+}
+
+// hasVotedFor ユーザーがオプションに投票済みか確認
+func (s *voteService) hasVotedFor(ctx context.Context, voteID, optionID, userID uint) (bool, error) {
+	responses, err := s.voteRepo.GetUserResponses(ctx, voteID, userID)
+	if err != nil {
+		return false, fmt.Errorf("投票情報の取得に失敗しました: %v", err)
+	}
+	for _, response := range responses {
+		if response.OptionID == optionID {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // RemoveVote 投票を削除
-func (s *voteService) RemoveVote(voteID, optionID, userID uint) error {
+func (s *voteService) RemoveVote(ctx context.Context, voteID, optionID, userID uint) error {
 	// 投票を取得
-	vote, err := s.voteRepo.FindByID(voteID)
+	vote, err := s.voteRepo.FindByID(ctx, voteID)
 	if err != nil {
 		return errors.New("投票が見つかりません")
 	}
@@ -395,7 +430,7 @@ func (s *voteService) RemoveVote(voteID, optionID, userID uint) error {
 	}
 
 	// オプションを取得
-	option, err := s.voteRepo.FindOptionByID(optionID)
+	option, err := s.voteRepo.FindOptionByID(ctx, optionID)
 	if err != nil {
 		return errors.New("投票オプションが見つかりません")
 	}
@@ -406,49 +441,49 @@ func (s *voteService) RemoveVote(voteID, optionID, userID uint) error {
 	}
 
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(vote.TaskID)
+	task, err := s.taskRepo.FindByID(ctx, vote.TaskID)
 	if err != nil {
 		return errors.New("タスクが見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 	if err != nil || !isMember {
 		return errors.New("この投票を削除する権限がありません")
 	}
 
 	// 投票を削除
-	return s.voteRepo.RemoveResponse(voteID, optionID, userID)
+	return s.voteRepo.RemoveResponse(ctx, voteID, optionID, userID)
 }
 
 // GetUserVotes ユーザーの投票を取得
-func (s *voteService) GetUserVotes(voteID, userID uint) ([]models.VoteResponse, error) {
+func (s *voteService) GetUserVotes(ctx context.Context, voteID, userID uint) ([]models.VoteResponse, error) {
 	// 投票を取得
-	vote, err := s.voteRepo.FindByID(voteID)
+	vote, err := s.voteRepo.FindByID(ctx, voteID)
 	if err != nil {
 		return nil, errors.New("投票が見つかりません")
 	}
 
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(vote.TaskID)
+	task, err := s.taskRepo.FindByID(ctx, vote.TaskID)
 	if err != nil {
 		return nil, errors.New("タスクが見つかりません")
 	}
 
 	// ユーザーがプロジェクトのメンバーかどうか確認
-	isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+	isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 	if err != nil || !isMember {
 		return nil, errors.New("この投票を閲覧する権限がありません")
 	}
 
 	// ユーザーの投票を取得
-	return s.voteRepo.GetUserResponses(voteID, userID)
+	return s.voteRepo.GetUserResponses(ctx, voteID, userID)
 }
 
 // CloseVote 投票を終了
-func (s *voteService) CloseVote(voteID, userID uint) error {
+func (s *voteService) CloseVote(ctx context.Context, voteID, userID uint) error {
 	// 投票を取得
-	vote, err := s.voteRepo.FindByID(voteID)
+	vote, err := s.voteRepo.FindByID(ctx, voteID)
 	if err != nil {
 		return errors.New("投票が見つかりません")
 	}
@@ -459,19 +494,219 @@ func (s *voteService) CloseVote(voteID, userID uint) error {
 	}
 
 	// タスクを取得
-	task, err := s.taskRepo.FindByID(vote.TaskID)
+	task, err := s.taskRepo.FindByID(ctx, vote.TaskID)
 	if err != nil {
 		return errors.New("タスクが見つかりません")
 	}
 
 	// 投票が作成者またはプロジェクトのオーナーかどうか確認
 	if vote.CreatedBy != userID {
-		isOwner, err := s.projectRepo.IsOwner(task.ProjectID, userID)
+		isOwner, err := s.projectRepo.IsOwner(ctx, task.ProjectID, userID)
 		if err != nil || !isOwner {
 			return errors.New("この投票を終了する権限がありません")
 		}
 	}
 
-	// 投票を終了
-	return s.voteRepo.CloseVote(voteID)
+	// 投票を終了（スケジューラなどが先に終了した場合は発表しない）
+	closed, err := s.voteRepo.CloseVote(ctx, voteID)
+	if err != nil {
+		return err
+	}
+	if !closed {
+		return errors.New("この投票は既に終了しています")
+	}
+
+	// 勝者を発表
+	s.announceWinners(ctx, vote, task)
+	s.finishContest(ctx, vote, task)
+
+	return nil
+}
+
+// CloseExpiredVotes 締め切りを過ぎた投票を終了し、勝者を発表する（スケジューラから呼び出される）
+func (s *voteService) CloseExpiredVotes(ctx context.Context) (int, error) {
+	votes, err := s.voteRepo.ListExpired(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, expired := range votes {
+		// オプションと投票数を含めて再取得
+		vote, err := s.voteRepo.FindByID(ctx, expired.ID)
+		if err != nil {
+			log.Printf("投票の取得に失敗しました (ID=%d): %v", expired.ID, err)
+			continue
+		}
+
+		task, err := s.taskRepo.FindByID(ctx, vote.TaskID)
+		if err != nil {
+			log.Printf("タスクの取得に失敗しました (VoteID=%d): %v", vote.ID, err)
+			continue
+		}
+
+		// 手動での終了や他のインスタンスが先に終了した場合は発表しない
+		closedNow, err := s.voteRepo.CloseVote(ctx, vote.ID)
+		if err != nil {
+			log.Printf("投票の終了に失敗しました (ID=%d): %v", vote.ID, err)
+			continue
+		}
+		if !closedNow {
+			continue
+		}
+
+		s.announceWinners(ctx, vote, task)
+		s.finishContest(ctx, vote, task)
+		closed++
+	}
+
+	return closed, nil
+}
+
+// finishContest コンテストの審査の投票が終了した場合、コンテストを結果発表済みにする
+// 投票の終了自体は完了しているため、ここでのエラーはログ出力のみとする
+func (s *voteService) finishContest(ctx context.Context, vote *models.Vote, task *models.Task) {
+	project, err := s.projectRepo.FindByID(ctx, task.ProjectID)
+	if err != nil {
+		log.Printf("プロジェクトの取得に失敗しました (ProjectID=%d): %v", task.ProjectID, err)
+		return
+	}
+	if !project.IsContest || project.ContestVoteID == nil || *project.ContestVoteID != vote.ID {
+		return
+	}
+
+	project.ContestPhase = models.ContestPhaseFinished
+	if err := s.projectRepo.Update(ctx, project); err != nil {
+		log.Printf("コンテストの更新に失敗しました (ProjectID=%d): %v", project.ID, err)
+	}
+}
+
+// findWinners 最多得票のオプションを取得（同票の場合は複数）
+func findWinners(options []models.VoteOption) []models.VoteOption {
+	var maxCount int64
+	for _, option := range options {
+		if option.VoteCount > maxCount {
+			maxCount = option.VoteCount
+		}
+	}
+
+	// 誰も投票していない場合は勝者なし
+	if maxCount == 0 {
+		return nil
+	}
+
+	var winners []models.VoteOption
+	for _, option := range options {
+		if option.VoteCount == maxCount {
+			winners = append(winners, option)
+		}
+	}
+	return winners
+}
+
+// announceWinners 勝者のアクティビティと通知を作成し、必要に応じてバッジを付与
+// 投票の終了自体は完了しているため、ここでのエラーはログ出力のみとする
+func (s *voteService) announceWinners(ctx context.Context, vote *models.Vote, task *models.Task) {
+	winners := findWinners(vote.Options)
+	if len(winners) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(winners))
+	for _, winner := range winners {
+		names = append(names, winner.OptionText)
+	}
+	message := fmt.Sprintf("投票「%s」の結果: %s", vote.Title, strings.Join(names, "、"))
+
+	// アクティビティを記録
+	projectID := task.ProjectID
+	activity := &models.Activity{
+		ProjectID:   &projectID,
+		Type:        models.ActivityTypeVoteWinner,
+		SubjectType: "vote",
+		SubjectID:   vote.ID,
+		Message:     message,
+	}
+	if err := s.activityRepo.Create(ctx, activity); err != nil {
+		log.Printf("アクティビティの記録に失敗しました (VoteID=%d): %v", vote.ID, err)
+	}
+
+	// プロジェクトメンバー全員に通知
+	members, err := s.projectRepo.GetMembers(ctx, task.ProjectID)
+	if err != nil {
+		log.Printf("メンバーの取得に失敗しました (ProjectID=%d): %v", task.ProjectID, err)
+	} else {
+		userIDs := make([]uint, 0, len(members))
+		for _, member := range members {
+			userIDs = append(userIDs, member.UserID)
+		}
+		link := fmt.Sprintf("/votes/%d", vote.ID)
+		if err := s.notificationService.Notify(ctx, userIDs, models.NotificationTypeVoteWinner, "投票結果が発表されました", message, link); err != nil {
+			log.Printf("通知の作成に失敗しました (VoteID=%d): %v", vote.ID, err)
+		}
+	}
+
+	// 勝者の作品にバッジを付与
+	if !s.config.Vote.AwardWinnerBadge {
+		return
+	}
+	for _, winner := range winners {
+		if winner.WorkID == nil {
+			continue
+		}
+		voteID := vote.ID
+		exists, err := s.badgeRepo.Exists(ctx, *winner.WorkID, models.BadgeTypeWinner, &voteID)
+		if err != nil || exists {
+			continue
+		}
+		badge := &models.WorkBadge{
+			WorkID: *winner.WorkID,
+			Type:   models.BadgeTypeWinner,
+			VoteID: &voteID,
+		}
+		if err := s.badgeRepo.Create(ctx, badge); err != nil {
+			// 同時に付与された場合はレピュテーションを二重に加算しない
+			if !errors.Is(err, repository.ErrBadgeAlreadyAwarded) {
+				log.Printf("バッジの付与に失敗しました (WorkID=%d): %v", *winner.WorkID, err)
+			}
+			continue
+		}
+
+		// 作者のレピュテーションを加算
+		if work, err := s.workRepo.FindByID(ctx, *winner.WorkID); err == nil {
+			s.reputationService.Apply(ctx, work.UserID, ReputationContestWin)
+		}
+	}
+}
+
+// ExportResults 投票の結果をエクスポートする（投票の作成者またはプロジェクトのオーナーのみ）
+// startにオプションと投票数を読み込んだ投票を渡し、匿名でない場合は続けて回答を古い順に少しずつfnに渡す
+func (s *voteService) ExportResults(ctx context.Context, voteID, userID uint, start func(vote *models.Vote) error, fn func(responses []models.VoteResponse) error) error {
+	// 投票を取得
+	vote, err := s.voteRepo.FindByID(ctx, voteID)
+	if err != nil {
+		return errors.New("投票が見つかりません")
+	}
+
+	// タスクを取得
+	task, err := s.taskRepo.FindByID(ctx, vote.TaskID)
+	if err != nil {
+		return errors.New("タスクが見つかりません")
+	}
+
+	// 投票が作成者またはプロジェクトのオーナーかどうか確認
+	if vote.CreatedBy != userID {
+		isOwner, err := s.projectRepo.IsOwner(ctx, task.ProjectID, userID)
+		if err != nil || !isOwner {
+			return errors.New("この投票の結果をエクスポートする権限がありません")
+		}
+	}
+
+	if err := start(vote); err != nil {
+		return err
+	}
+	if vote.Anonymous {
+		return nil
+	}
+	return s.voteRepo.EachResponse(ctx, voteID, voteExportBatchSize, fn)
 }
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository/memory"
+)
+
+// countingNotifications 通知の作成回数を数えるNotificationService
+type countingNotifications struct {
+	NotificationService
+	count int
+}
+
+func (n *countingNotifications) Notify(ctx context.Context, userIDs []uint, notificationType, title, message, link string) error {
+	n.count++
+	return nil
+}
+
+// countingReputation レピュテーションの加算回数を数えるReputationService
+type countingReputation struct {
+	ReputationService
+	count int
+}
+
+func (r *countingReputation) Apply(ctx context.Context, userID uint, event ReputationEvent) {
+	r.count++
+}
+
+// staleVoteRepository 終了前に取得した投票を返し続けるVoteRepository（手動での終了とスケジューラが重なった場合）
+type staleVoteRepository struct {
+	repository.VoteRepository
+	stale *models.Vote
+}
+
+func (r *staleVoteRepository) FindByID(ctx context.Context, id uint) (*models.Vote, error) {
+	vote := *r.stale
+	return &vote, nil
+}
+
+func TestVoteServiceAnnouncesWinnersOnceWhenClosesOverlap(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	voteRepo := memory.NewVoteRepository(store)
+	taskRepo := memory.NewTaskRepository(store)
+	projectRepo := memory.NewProjectRepository(store)
+	workRepo := memory.NewWorkRepository(store)
+
+	project := &models.Project{Title: "プロジェクト", OwnerID: 1}
+	if err := projectRepo.Create(ctx, project); err != nil {
+		t.Fatalf("プロジェクトの作成に失敗しました: %v", err)
+	}
+	if err := projectRepo.AddMember(ctx, project.ID, 1, true); err != nil {
+		t.Fatalf("メンバーの追加に失敗しました: %v", err)
+	}
+	task := &models.Task{Title: "タスク", ProjectID: project.ID}
+	if err := taskRepo.Create(ctx, task); err != nil {
+		t.Fatalf("タスクの作成に失敗しました: %v", err)
+	}
+	work := &models.Work{Title: "作品", PDEContent: "void setup() {}", UserID: 1}
+	if err := workRepo.Create(ctx, work); err != nil {
+		t.Fatalf("作品の作成に失敗しました: %v", err)
+	}
+	closesAt := time.Now().Add(-time.Minute)
+	vote := &models.Vote{Title: "投票", TaskID: task.ID, IsActive: true, CreatedBy: 1, ClosesAt: &closesAt}
+	if err := voteRepo.Create(ctx, vote); err != nil {
+		t.Fatalf("投票の作成に失敗しました: %v", err)
+	}
+	option := &models.VoteOption{VoteID: vote.ID, OptionText: "作品", WorkID: &work.ID}
+	if err := voteRepo.CreateOption(ctx, option); err != nil {
+		t.Fatalf("オプションの作成に失敗しました: %v", err)
+	}
+	if err := voteRepo.AddResponse(ctx, &models.VoteResponse{VoteID: vote.ID, OptionID: option.ID, UserID: 1}); err != nil {
+		t.Fatalf("投票に失敗しました: %v", err)
+	}
+	stale, err := voteRepo.FindByID(ctx, vote.ID)
+	if err != nil {
+		t.Fatalf("投票の取得に失敗しました: %v", err)
+	}
+
+	cfg := &config.Config{}
+	cfg.Vote.AwardWinnerBadge = true
+	notifications := &countingNotifications{}
+	reputation := &countingReputation{}
+	newService := func(voteRepo repository.VoteRepository) VoteService {
+		return NewVoteService(voteRepo, taskRepo, projectRepo, workRepo, memory.NewActivityRepository(store),
+			memory.NewBadgeRepository(store), notifications, reputation, cfg)
+	}
+
+	// スケジューラが先に終了する
+	closed, err := newService(voteRepo).CloseExpiredVotes(ctx)
+	if err != nil || closed != 1 {
+		t.Fatalf("CloseExpiredVotes = %d, %v", closed, err)
+	}
+
+	// 終了前に投票を読み込んでいた手動の終了
+	if err := newService(&staleVoteRepository{VoteRepository: voteRepo, stale: stale}).CloseVote(ctx, vote.ID, 1); err == nil {
+		t.Errorf("既に終了した投票を終了できました")
+	}
+
+	if notifications.count != 1 {
+		t.Errorf("通知の作成回数 = %d, want 1", notifications.count)
+	}
+	if reputation.count != 1 {
+		t.Errorf("レピュテーションの加算回数 = %d, want 1", reputation.count)
+	}
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+)
+
+// WorkImportService URLのPDEファイルから作品を作成するサービスインターフェース
+type WorkImportService interface {
+	// ImportURL GitHub Gist・GitHubのファイル・.pdeファイルのURLからコードを取得して作品を作成（titleを省略した場合はファイル名などを使う）
+	ImportURL(ctx context.Context, rawURL, title, description string, codeShared bool, license, language string, tagNames []string, userID uint) (*models.Work, error)
+}
+
+// Gistから取り込むファイルの最大数
+const maxImportFiles = 20
+
+// GistのID
+var gistIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{5,64}$`)
+
+// workImportService WorkImportServiceの実装
+type workImportService struct {
+	workService WorkService
+	fetcher     URLFetcher
+	config      *config.Config
+}
+
+// NewWorkImportService WorkImportServiceを作成
+func NewWorkImportService(workService WorkService, fetcher URLFetcher, cfg *config.Config) WorkImportService {
+	return &workImportService{
+		workService: workService,
+		fetcher:     fetcher,
+		config:      cfg,
+	}
+}
+
+// importedFile 取り込んだPDEファイル
+type importedFile struct {
+	name    string
+	content string
+}
+
+// gistResponse GitHubのGist APIのレスポンス（必要な項目のみ）
+type gistResponse struct {
+	Description string `json:"description"`
+	Files       map[string]struct {
+		Filename  string `json:"filename"`
+		RawURL    string `json:"raw_url"`
+		Truncated bool   `json:"truncated"`
+		Content   string `json:"content"`
+	} `json:"files"`
+}
+
+// ImportURL URLのPDEファイルから作品を作成
+func (s *workImportService) ImportURL(ctx context.Context, rawURL, title, description string, codeShared bool, license, language string, tagNames []string, userID uint) (*models.Work, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, errors.New("URLが不正です")
+	}
+	if err := s.fetcher.Validate(u, nil); err != nil {
+		return nil, err
+	}
+
+	var files []importedFile
+	var defaultTitle string
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case host == "gist.github.com":
+		files, defaultTitle, err = s.fetchGist(ctx, u)
+	case host == "github.com":
+		// ファイルの表示ページのURLは生のファイルのURLに置き換える
+		files, err = s.fetchGithubFile(ctx, u)
+	default:
+		if !strings.EqualFold(path.Ext(u.Path), ".pde") {
+			return nil, errors.New("GitHub GistのURLか、.pdeファイルのURLを指定してください")
+		}
+		var file *importedFile
+		file, err = s.fetchFile(ctx, u.String())
+		if file != nil {
+			files = []importedFile{*file}
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pdeContent, mainFile := joinImportedFiles(files)
+	if strings.TrimSpace(title) == "" {
+		title = defaultTitle
+	}
+	if strings.TrimSpace(title) == "" {
+		title = strings.TrimSuffix(mainFile, path.Ext(mainFile))
+	}
+
+	return s.workService.Create(ctx, title, description, pdeContent, "", "", codeShared, license, language, tagNames, nil, userID)
+}
+
+// fetchGist GistのPDEファイルを全て取得（説明文の1行目を作品のタイトルの候補にする）
+func (s *workImportService) fetchGist(ctx context.Context, u *url.URL) ([]importedFile, string, error) {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	gistID := segments[len(segments)-1]
+	if !gistIDPattern.MatchString(gistID) {
+		return nil, "", errors.New("GistのURLが不正です")
+	}
+
+	apiURL, err := url.Parse(s.config.Auth.GithubAPIURL)
+	if err != nil {
+		return nil, "", errors.New("GitHub APIのURLが不正です")
+	}
+	result, err := s.fetcher.Fetch(ctx, strings.TrimSuffix(apiURL.String(), "/")+"/gists/"+gistID, FetchOptions{
+		AllowedHosts: []string{apiURL.Hostname()},
+		ContentTypes: []string{"application/json"},
+		Accept:       "application/vnd.github+json",
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "ステータスコード 404") {
+			return nil, "", errors.New("Gistが見つかりません")
+		}
+		return nil, "", s.fetchError(err)
+	}
+
+	var gist gistResponse
+	if err := json.Unmarshal(result.Body, &gist); err != nil {
+		return nil, "", fmt.Errorf("Gistの取得に失敗しました: %v", err)
+	}
+
+	files := []importedFile{}
+	for _, f := range gist.Files {
+		if !strings.EqualFold(path.Ext(f.Filename), ".pde") {
+			continue
+		}
+		if len(files) >= maxImportFiles {
+			return nil, "", fmt.Errorf("取り込めるPDEファイルは%d個までです", maxImportFiles)
+		}
+
+		// 大きいファイルは内容が省略されるため、生のファイルを取得する
+		content := f.Content
+		if f.Truncated {
+			file, err := s.fetchFile(ctx, f.RawURL)
+			if err != nil {
+				return nil, "", err
+			}
+			content = file.content
+		}
+		if err := validateImportedCode(content); err != nil {
+			return nil, "", err
+		}
+		files = append(files, importedFile{name: f.Filename, content: content})
+	}
+	if len(files) == 0 {
+		return nil, "", errors.New("GistにPDEファイルがありません")
+	}
+
+	title := strings.TrimSpace(strings.SplitN(gist.Description, "\n", 2)[0])
+	if runes := []rune(title); len(runes) > 100 {
+		title = string(runes[:100])
+	}
+	return files, title, nil
+}
+
+// fetchGithubFile GitHubのファイルの表示ページ（/owner/repo/blob/ref/path.pde）のURLから生のファイルを取得
+func (s *workImportService) fetchGithubFile(ctx context.Context, u *url.URL) ([]importedFile, error) {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 5 || (segments[2] != "blob" && segments[2] != "raw") || !strings.EqualFold(path.Ext(u.Path), ".pde") {
+		return nil, errors.New("GitHubの.pdeファイルのURLを指定してください")
+	}
+
+	rawURL := "https://raw.githubusercontent.com/" + strings.Join(append(segments[:2:2], segments[3:]...), "/")
+	file, err := s.fetchFile(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return []importedFile{*file}, nil
+}
+
+// fetchFile .pdeファイルを取得して検証
+func (s *workImportService) fetchFile(ctx context.Context, rawURL string) (*importedFile, error) {
+	opts := FetchOptions{Accept: "text/plain"}
+	if s.config.Limits.PDEMaxSizeKB > 0 {
+		opts.MaxBytes = int64(s.config.Limits.PDEMaxSizeKB) * 1024
+	}
+	result, err := s.fetcher.Fetch(ctx, rawURL, opts)
+	if err != nil {
+		return nil, s.fetchError(err)
+	}
+
+	// ログイン画面やエラーページを取り込まないようにする
+	if result.ContentType == "text/html" || result.ContentType == "application/xhtml+xml" {
+		return nil, errors.New("URLの内容がPDEファイルではありません（HTMLが返されました）")
+	}
+	content := string(result.Body)
+	if err := validateImportedCode(content); err != nil {
+		return nil, err
+	}
+
+	return &importedFile{name: path.Base(result.URL.Path), content: content}, nil
+}
+
+// fetchError 取得時のエラーを作品の作成時と同じ形式のエラーにする
+func (s *workImportService) fetchError(err error) error {
+	switch {
+	case errors.Is(err, ErrFetchTooLarge):
+		return fmt.Errorf("PDEコードのサイズが上限（%dKB）を超えています", s.config.Limits.PDEMaxSizeKB)
+	case errors.Is(err, ErrFetchBlocked):
+		return ErrFetchBlocked
+	default:
+		return fmt.Errorf("URLの取得に失敗しました: %v", err)
+	}
+}
+
+// validateImportedCode 取得した内容がテキストのPDEコードか確認
+func validateImportedCode(content string) error {
+	if !utf8.ValidString(content) || strings.ContainsRune(content, 0) {
+		return errors.New("URLの内容がテキストではありません")
+	}
+	if strings.TrimSpace(content) == "" {
+		return errors.New("PDEファイルが空です")
+	}
+	return nil
+}
+
+// joinImportedFiles 複数のタブのPDEファイルを1つのコードにまとめ、メインのファイル名を返す
+// Processingと同じく、setup()を含むファイルを先頭にして残りはファイル名順に並べる
+func joinImportedFiles(files []importedFile) (string, string) {
+	sort.SliceStable(files, func(i, j int) bool {
+		iMain := strings.Contains(files[i].content, "setup(")
+		jMain := strings.Contains(files[j].content, "setup(")
+		if iMain != jMain {
+			return iMain
+		}
+		return files[i].name < files[j].name
+	})
+
+	if len(files) == 1 {
+		return strings.TrimPrefix(files[0].content, "\ufeff"), files[0].name
+	}
+	parts := make([]string, len(files))
+	for i, file := range files {
+		parts[i] = "// " + file.name + "\n" + strings.TrimPrefix(file.content, "\ufeff")
+	}
+	return strings.Join(parts, "\n\n"), files[0].name
+}
@@ -1,34 +1,144 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
 	"github.com/SketchShifter/sketchshifter_backend/internal/models"
 	"github.com/SketchShifter/sketchshifter_backend/internal/repository"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
 )
 
 // WorkService 作品に関するサービスインターフェース
 type WorkService interface {
-	Create(title, description, pdeContent, thumbnailURL string, codeShared bool, tagNames []string, taskID *uint, userID uint) (*models.Work, error)
-	GetByID(id uint) (*models.Work, error)
-	Update(id, userID uint, title, description, pdeContent, thumbnailURL string, codeShared bool, tagNames []string, taskID *uint) (*models.Work, error)
-	Delete(id, userID uint) error
-	List(page, limit int, search, tag string, userID *uint, sort string) ([]models.Work, int64, int, error)
-	AddLike(userID, workID uint) (int, error)
-	RemoveLike(userID, workID uint) (int, error)
-	HasLiked(userID, workID uint) (bool, error)
-	GetUserWorks(userID uint, page, limit int) ([]models.Work, int64, int, error)
+	Create(ctx context.Context, title, description, pdeContent, thumbnailURL, thumbnailBlurhash string, codeShared bool, license, language string, tagNames []string, taskID *uint, userID uint) (*models.Work, error)
+	GetByID(ctx context.Context, id uint) (*models.Work, error)
+	Update(ctx context.Context, id, userID uint, title, description, pdeContent, thumbnailURL string, codeShared bool, license, language string, tagNames []string, taskID, version *uint) (*models.Work, error)
+	Preview(ctx context.Context, userID uint, pdeContent string) (*ConversionPreview, error)
+	Delete(ctx context.Context, id, userID uint) error
+	Fork(ctx context.Context, id, userID uint) (*models.Work, error)
+	Compare(ctx context.Context, ids []uint, viewerID *uint) ([]CompareWork, error)
+	ListTrash(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, int, error)
+	Restore(ctx context.Context, id, userID uint) (*models.Work, error)
+	PurgeTrash(ctx context.Context) (int64, error)
+	List(ctx context.Context, page, limit int, search, tag, license, language string, userID *uint, sort string) ([]models.Work, int64, int, error)
+	AddLike(ctx context.Context, userID, workID uint) (int, error)
+	RemoveLike(ctx context.Context, userID, workID uint) (int, error)
+	HasLiked(ctx context.Context, userID, workID uint) (bool, error)
+	ListLiked(ctx context.Context, userID uint, page, limit int) ([]LikedWork, int64, int, error)
+	ListLikers(ctx context.Context, workID uint, page, limit int) ([]Liker, int64, int, error)
+	GetUserWorks(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, int, error)
+	GetCollaborations(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, int, error)
+	ReconcileCounters(ctx context.Context) (int64, error)
+	Bulk(ctx context.Context, userID uint, action string, workIDs []uint, tagNames []string, codeShared *bool) ([]BulkWorkResult, error)
+	SetFeatured(ctx context.Context, id uint, from, until *time.Time) (*models.Work, error)
+	UnsetFeatured(ctx context.Context, id uint) (*models.Work, error)
+	ListFeatured(ctx context.Context, page, limit int) ([]models.Work, int64, int, error)
+	UpdateMadeAt(ctx context.Context, id, userID uint, input MadeAtInput) (*models.Work, error)
+	ListByEvent(ctx context.Context, event string, page, limit int) ([]models.Work, int64, int, error)
+	ListEvents(ctx context.Context) ([]repository.WorkEventSummary, error)
+	ListLanguages(ctx context.Context) ([]repository.WorkLanguageSummary, error)
+	Map(ctx context.Context, event string) (*WorkFeatureCollection, error)
+}
+
+// 一括操作の種類
+const (
+	BulkActionDelete        = "delete"
+	BulkActionAddTags       = "add_tags"
+	BulkActionRemoveTags    = "remove_tags"
+	BulkActionSetVisibility = "set_visibility"
+)
+
+// 一括操作で一度に指定できる作品数
+const maxBulkWorks = 100
+
+// 一括操作の作品ごとの結果
+const (
+	BulkStatusOK        = "ok"
+	BulkStatusNotFound  = "not_found"
+	BulkStatusForbidden = "forbidden"
+)
+
+// LikedWork いいねした作品といいねした日時
+type LikedWork struct {
+	models.Work
+	LikedAt time.Time `json:"liked_at"`
+}
+
+// Liker 作品にいいねしたユーザーといいねした日時（誰でも閲覧できるため、メールアドレスなどは含めない）
+type Liker struct {
+	ID       uint      `json:"id"`
+	Name     string    `json:"name"`
+	Nickname string    `json:"nickname"`
+	Handle   *string   `json:"handle,omitempty"`
+	LikedAt  time.Time `json:"liked_at"`
+}
+
+// BulkWorkResult 一括操作の作品ごとの結果
+type BulkWorkResult struct {
+	WorkID uint   `json:"work_id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// 比較できる作品数
+const (
+	minCompareWorks = 2
+	maxCompareWorks = 4
+)
+
+// ConversionPreview 保存せずに変換したPDEコードの結果
+type ConversionPreview struct {
+	JSContent        string              `json:"js_content"` // 検証で拒否された場合は空
+	ConverterVersion string              `json:"converter_version"`
+	Validation       *JSValidationResult `json:"validation"`
+}
+
+// CompareWork 作品比較用の作品情報
+type CompareWork struct {
+	ID                 uint                     `json:"id"`
+	Title              string                   `json:"title"`
+	DescriptionHTML    string                   `json:"description_html"`
+	JSContent          string                   `json:"js_content"`
+	PDEContent         string                   `json:"pde_content,omitempty"` // コードが公開されている場合のみ
+	CodeShared         bool                     `json:"code_shared"`
+	License            string                   `json:"license"`
+	ConverterVersion   string                   `json:"converter_version"`
+	JSValidationStatus string                   `json:"js_validation_status"`
+	ThumbnailURL       string                   `json:"thumbnail_url"`
+	ThumbnailBlurhash  string                   `json:"thumbnail_blurhash,omitempty"`
+	LikesCount         int64                    `json:"likes_count"`
+	CommentsCount      int64                    `json:"comments_count"`
+	UserID             uint                     `json:"user_id"`
+	Nickname           string                   `json:"nickname"`
+	Attribution        []models.WorkAttribution `json:"attribution,omitempty"`
+	CreatedAt          time.Time                `json:"created_at"`
+	UpdatedAt          time.Time                `json:"updated_at"`
 }
 
 // workService WorkServiceの実装
 type workService struct {
-	workRepo      repository.WorkRepository
-	tagRepo       repository.TagRepository
-	lambdaService LambdaService
-	taskRepo      repository.TaskRepository
-	projectRepo   repository.ProjectRepository
+	workRepo          repository.WorkRepository
+	tagRepo           repository.TagRepository
+	lambdaService     LambdaService
+	conversionQueue   ConversionQueue
+	taskRepo          repository.TaskRepository
+	projectRepo       repository.ProjectRepository
+	seriesRepo        repository.SeriesRepository
+	collaboratorRepo  repository.CollaboratorRepository
+	reputationService ReputationService
+	conversionQuota   ConversionQuotaService
+	jsValidator       JSValidationService
+	storageQuota      StorageQuotaService
+	sitemapService    SitemapService
+	conversionJobs    ConversionJobService
+	ageGate           AgeGateService
+	attributions      *attributionResolver
+	config            *config.Config
 }
 
 // NewWorkService WorkServiceを作成
@@ -36,72 +146,152 @@ func NewWorkService(
 	workRepo repository.WorkRepository,
 	tagRepo repository.TagRepository,
 	lambdaService LambdaService,
+	conversionQueue ConversionQueue,
 	taskRepo repository.TaskRepository,
-	projectRepo repository.ProjectRepository) WorkService {
+	projectRepo repository.ProjectRepository,
+	seriesRepo repository.SeriesRepository,
+	collaboratorRepo repository.CollaboratorRepository,
+	reputationService ReputationService,
+	conversionQuota ConversionQuotaService,
+	jsValidator JSValidationService,
+	storageQuota StorageQuotaService,
+	sitemapService SitemapService,
+	conversionJobs ConversionJobService,
+	ageGate AgeGateService,
+	cfg *config.Config) WorkService {
 	return &workService{
-		workRepo:      workRepo,
-		tagRepo:       tagRepo,
-		lambdaService: lambdaService,
-		taskRepo:      taskRepo,
-		projectRepo:   projectRepo,
+		workRepo:          workRepo,
+		tagRepo:           tagRepo,
+		lambdaService:     lambdaService,
+		conversionQueue:   conversionQueue,
+		taskRepo:          taskRepo,
+		projectRepo:       projectRepo,
+		seriesRepo:        seriesRepo,
+		collaboratorRepo:  collaboratorRepo,
+		reputationService: reputationService,
+		conversionQuota:   conversionQuota,
+		jsValidator:       jsValidator,
+		storageQuota:      storageQuota,
+		sitemapService:    sitemapService,
+		conversionJobs:    conversionJobs,
+		ageGate:           ageGate,
+		attributions:      newAttributionResolver(workRepo),
+		config:            cfg,
 	}
 }
 
 // GetByID IDで作品を取得
-func (s *workService) GetByID(id uint) (*models.Work, error) {
-	work, err := s.workRepo.FindByID(id)
+func (s *workService) GetByID(ctx context.Context, id uint) (*models.Work, error) {
+	work, err := s.workRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	// 閲覧数を増加
-	if err := s.workRepo.IncrementViews(id); err != nil {
+	if err := s.workRepo.IncrementViews(ctx, id); err != nil {
 		// エラーでも続行
 		fmt.Printf("閲覧数の更新に失敗しました: %v\n", err)
 	}
 
+	// フォーク元のクレジットを設定
+	work.Attribution = s.attributions.Resolve(ctx, work)
+
+	// 承認済みの共同編集者を設定
+	work.CoAuthors = coAuthorsByWork(ctx, s.collaboratorRepo, []uint{work.ID})[work.ID]
+
+	// シリーズ内の位置と前後の作品を設定
+	if work.SeriesID != nil {
+		work.Series = s.resolveSeries(ctx, work)
+	}
+
+	// 変換の来歴を設定
+	work.Pipeline = work.ConversionPipeline()
+
 	return work, nil
 }
 
+// resolveSeries 作品が属するシリーズの前後の作品を取得（取得できない場合はnil）
+func (s *workService) resolveSeries(ctx context.Context, work *models.Work) *models.SeriesNavigation {
+	series, err := s.seriesRepo.FindByID(ctx, *work.SeriesID)
+	if err != nil {
+		return nil
+	}
+	works, err := s.seriesRepo.ListWorks(ctx, series.ID)
+	if err != nil {
+		return nil
+	}
+	return seriesNavigation(series, works, work.ID)
+}
+
 // Create 新しい作品を作成
 func (s *workService) Create(
-	title, description, pdeContent, thumbnailURL string,
+	ctx context.Context,
+	title, description, pdeContent, thumbnailURL, thumbnailBlurhash string,
 	codeShared bool,
+	license, language string,
 	tagNames []string,
 	taskID *uint,
 	userID uint) (*models.Work, error) {
 
+	// 見守りが有効になっていない未成年のユーザーは作品を公開できない
+	if err := s.ageGate.CheckPosting(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	// タイトルのバリデーション
 	if strings.TrimSpace(title) == "" {
 		return nil, errors.New("タイトルは必須です")
 	}
 
+	// ライセンスのバリデーション（未指定の場合は全著作権保持）
+	if license == "" {
+		license = models.WorkLicenseAllRightsReserved
+	}
+	if !models.IsValidWorkLicense(license) {
+		return nil, fmt.Errorf("無効なライセンスです: %s", license)
+	}
+
+	// 言語のバリデーション（未指定の場合はタイトルと説明文から判定）
+	language, err := resolveWorkLanguage(language, title, description)
+	if err != nil {
+		return nil, err
+	}
+
 	// PDEコードのバリデーション
 	if strings.TrimSpace(pdeContent) == "" {
 		return nil, errors.New("PDEコードは必須です")
 	}
 
+	// サイズとストレージ容量を確認
+	if err := s.storageQuota.CheckPDESize(pdeContent); err != nil {
+		return nil, err
+	}
+	if err := s.storageQuota.CheckStorage(ctx, userID, 0, int64(len(pdeContent))); err != nil {
+		return nil, err
+	}
+
 	// タスクIDが指定されている場合のバリデーションと権限チェック
 	if taskID != nil {
 		// タスクが存在するか確認
-		task, err := s.taskRepo.FindByID(*taskID)
+		task, err := s.taskRepo.FindByID(ctx, *taskID)
 		if err != nil {
 			return nil, errors.New("指定されたタスクが見つかりません")
 		}
 
 		// ユーザーがプロジェクトのメンバーか確認
-		isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+		isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 		if err != nil || !isMember {
 			return nil, errors.New("このタスクに作品を投稿する権限がありません")
 		}
 	}
 
-	// JavaScriptへの変換（Lambda関数を使用）
-	jsContent := ""
-	jsConversionErr := error(nil)
+	// 変換回数の上限を確認
+	if _, err := s.conversionQuota.Consume(ctx, userID); err != nil {
+		return nil, err
+	}
 
 	// Lambda関数を呼び出してPDEをJSに変換
-	jsContent, jsConversionErr = s.lambdaService.ConvertPDEToJS(pdeContent)
+	output, jsConversionErr := s.conversionQueue.Convert(ctx, ConversionPriorityWork, pdeContent)
 	if jsConversionErr != nil {
 		// 変換に失敗しても続行するが、エラーをログ出力
 		fmt.Printf("PDE変換に失敗しました: %v\n", jsConversionErr)
@@ -111,25 +301,38 @@ func (s *workService) Create(
 	work := &models.Work{
 		Title:             title,
 		Description:       description,
+		DescriptionHTML:   utils.RenderMarkdown(description),
 		PDEContent:        pdeContent,
-		JSContent:         jsContent,
 		ThumbnailURL:      thumbnailURL,
+		ThumbnailBlurhash: thumbnailBlurhash,
 		ThumbnailType:     "image/png", // TODO: URLから判定する場合は別途処理
 		ThumbnailPublicID: "",          // Cloudinaryを使わない場合は不要
 		CodeShared:        codeShared,
+		License:           license,
+		Language:          language,
 		UserID:            userID,
 	}
 
+	// 変換後のJSを検証して設定
+	if jsConversionErr == nil {
+		s.jsValidator.Apply(work, output, s.lambdaService.ConverterVersion())
+	}
+
+	// 変換後のJSを含めてストレージ容量を確認
+	if err := s.storageQuota.CheckStorage(ctx, userID, 0, int64(len(work.PDEContent)+len(work.JSContent))); err != nil {
+		return nil, err
+	}
+
 	// データベースに保存
-	if err := s.workRepo.Create(work); err != nil {
+	if err := s.workRepo.Create(ctx, work); err != nil {
 		return nil, fmt.Errorf("作品の保存に失敗しました: %v", err)
 	}
 
 	// タスクに作品を関連付け
 	if taskID != nil {
-		if err := s.taskRepo.AddWork(*taskID, work.ID); err != nil {
+		if err := s.taskRepo.AddWork(ctx, *taskID, work.ID); err != nil {
 			// 作品を削除してエラーを返す
-			s.workRepo.Delete(work.ID)
+			s.workRepo.Delete(ctx, work.ID)
 			return nil, fmt.Errorf("タスクへの作品の追加に失敗しました: %v", err)
 		}
 	}
@@ -141,7 +344,7 @@ func (s *workService) Create(
 			if name = strings.TrimSpace(name); name == "" {
 				continue
 			}
-			tag, err := s.tagRepo.FindOrCreate(name)
+			tag, err := s.tagRepo.FindOrCreate(ctx, name)
 			if err != nil {
 				continue
 			}
@@ -149,79 +352,77 @@ func (s *workService) Create(
 		}
 
 		if len(tagIDs) > 0 {
-			if err := s.tagRepo.AttachTagsToWork(work.ID, tagIDs); err != nil {
+			if err := s.tagRepo.AttachTagsToWork(ctx, work.ID, tagIDs); err != nil {
 				fmt.Printf("タグの関連付けに失敗しました: %v\n", err)
 			}
 		}
 	}
 
-	// JS変換に失敗した場合、非同期で再試行
+	// JS変換に失敗した場合、バックグラウンドで再試行
 	if jsConversionErr != nil {
-		go func(workID uint, pdeCode string) {
-			// 再度変換を試みる
-			jsContent, err := s.lambdaService.ConvertPDEToJS(pdeCode)
-			if err != nil {
-				fmt.Printf("非同期PDE変換に失敗しました (ID=%d): %v\n", workID, err)
-				return
-			}
-
-			// データベースを更新
-			work, err := s.workRepo.FindByID(workID)
-			if err != nil {
-				fmt.Printf("作品の取得に失敗しました (ID=%d): %v\n", workID, err)
-				return
-			}
-
-			work.JSContent = jsContent
-			if err := s.workRepo.Update(work); err != nil {
-				fmt.Printf("JS変換結果の保存に失敗しました (ID=%d): %v\n", workID, err)
-			}
-		}(work.ID, pdeContent)
+		s.enqueueConversion(ctx, work.ID)
 	}
+	s.sitemapService.Invalidate()
 
 	// タグを含む作品を再取得
-	return s.GetByID(work.ID)
+	return s.GetByID(ctx, work.ID)
 }
 
 // Update 作品を更新
-func (s *workService) Update(id, userID uint, title, description, pdeContent, thumbnailURL string, codeShared bool, tagNames []string, taskID *uint) (*models.Work, error) {
+func (s *workService) Update(ctx context.Context, id, userID uint, title, description, pdeContent, thumbnailURL string, codeShared bool, license, language string, tagNames []string, taskID, version *uint) (*models.Work, error) {
 	// 作品を取得
-	work, err := s.workRepo.FindByID(id)
+	work, err := s.workRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, errors.New("作品が見つかりません")
 	}
 
-	// 権限チェック
-	if work.UserID != userID {
+	// 権限チェック（作成者と、編集権限のある共同編集者のみ）
+	if !hasWorkPermission(ctx, s.collaboratorRepo, work, userID, models.CollaboratorPermissionEdit) {
 		return nil, errors.New("この作品を更新する権限がありません")
 	}
+	if err := checkVersion(version, work.Version); err != nil {
+		return nil, err
+	}
+
+	// PDEコードが変更される場合はサイズ・ストレージ容量・変換回数の上限を確認
+	if strings.TrimSpace(pdeContent) != "" && pdeContent != work.PDEContent {
+		if err := s.storageQuota.CheckPDESize(pdeContent); err != nil {
+			return nil, err
+		}
+		if err := s.storageQuota.CheckStorage(ctx, work.UserID, id, int64(len(pdeContent))); err != nil {
+			return nil, err
+		}
+		if _, err := s.conversionQuota.Consume(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
 
 	// タスクIDが変更される場合の処理
 	if taskID != nil {
 		// 新しいタスクが存在するか確認
-		task, err := s.taskRepo.FindByID(*taskID)
+		task, err := s.taskRepo.FindByID(ctx, *taskID)
 		if err != nil {
 			return nil, errors.New("指定されたタスクが見つかりません")
 		}
 
 		// ユーザーがプロジェクトのメンバーか確認
-		isMember, err := s.projectRepo.IsMember(task.ProjectID, userID)
+		isMember, err := s.projectRepo.IsMember(ctx, task.ProjectID, userID)
 		if err != nil || !isMember {
 			return nil, errors.New("このタスクに作品を移動する権限がありません")
 		}
 
 		// 現在のタスクとの関連を削除（もしあれば）
 		// 現在関連付けられているタスクを取得
-		currentTasks, _, err := s.taskRepo.GetWorks(0, 1, 1) // TODO: 作品に関連するタスク一覧を取得する機能が必要
+		currentTasks, _, err := s.taskRepo.GetWorks(ctx, 0, 1, 1) // TODO: 作品に関連するタスク一覧を取得する機能が必要
 		if err == nil {
 			for _, currentTask := range currentTasks {
 				// 作品が含まれているタスクから削除
-				s.taskRepo.RemoveWork(currentTask.ID, work.ID)
+				s.taskRepo.RemoveWork(ctx, currentTask.ID, work.ID)
 			}
 		}
 
 		// 新しいタスクに作品を追加
-		if err := s.taskRepo.AddWork(*taskID, work.ID); err != nil {
+		if err := s.taskRepo.AddWork(ctx, *taskID, work.ID); err != nil {
 			return nil, fmt.Errorf("タスクへの作品の移動に失敗しました: %v", err)
 		}
 	}
@@ -231,37 +432,67 @@ func (s *workService) Update(id, userID uint, title, description, pdeContent, th
 		return nil, errors.New("タイトルは必須です")
 	}
 
+	// ライセンスを更新（未指定の場合は変更しない）
+	if license != "" {
+		if !models.IsValidWorkLicense(license) {
+			return nil, fmt.Errorf("無効なライセンスです: %s", license)
+		}
+		work.License = license
+	}
+
+	// 言語を更新（未指定の場合は変更しないが、まだ判定していない作品は新しい説明文から判定する）
+	if language != "" || work.Language == "" {
+		if work.Language, err = resolveWorkLanguage(language, title, description); err != nil {
+			return nil, err
+		}
+	}
+
 	// フィールドを更新
 	work.Title = title
 	work.Description = description
+	work.DescriptionHTML = utils.RenderMarkdown(description)
 	work.CodeShared = codeShared
 
-	// サムネイルURLを更新
-	if thumbnailURL != "" {
+	// サムネイルURLを更新（別の画像になるため、ぼかし画像は使わない）
+	if thumbnailURL != "" && thumbnailURL != work.ThumbnailURL {
 		work.ThumbnailURL = thumbnailURL
+		work.ThumbnailBlurhash = ""
 		work.ThumbnailType = "image/png" // TODO: URLから判定する場合は別途処理
 	}
 
 	// PDEコードが変更された場合
 	pdeChanged := false
+	var jsConversionErr error
 	if strings.TrimSpace(pdeContent) != "" && pdeContent != work.PDEContent {
 		work.PDEContent = pdeContent
 		pdeChanged = true
 
 		// Lambda関数を呼び出してJavaScriptへの変換
-		jsContent, err := s.lambdaService.ConvertPDEToJS(pdeContent)
-		if err != nil {
+		var output ConversionOutput
+		output, jsConversionErr = s.conversionQueue.Convert(ctx, ConversionPriorityWork, pdeContent)
+		if jsConversionErr != nil {
 			// 変換に失敗しても続行するが、エラーをログ出力
-			fmt.Printf("PDE変換に失敗しました: %v\n", err)
+			fmt.Printf("PDE変換に失敗しました: %v\n", jsConversionErr)
 		} else {
-			work.JSContent = jsContent
+			s.jsValidator.Apply(work, output, s.lambdaService.ConverterVersion())
+		}
+
+		// 変換後のJSを含めてストレージ容量を確認
+		if err := s.storageQuota.CheckStorage(ctx, work.UserID, id, int64(len(work.PDEContent)+len(work.JSContent))); err != nil {
+			return nil, err
 		}
 	}
 
 	// データベースを更新
-	if err := s.workRepo.Update(work); err != nil {
+	if err := s.workRepo.Update(ctx, work); err != nil {
+		if errors.Is(err, repository.ErrVersionConflict) {
+			if latest, findErr := s.workRepo.FindByID(ctx, id); findErr == nil {
+				return nil, &VersionConflictError{CurrentVersion: latest.Version}
+			}
+		}
 		return nil, fmt.Errorf("作品の更新に失敗しました: %v", err)
 	}
+	s.attributions.Invalidate()
 
 	// タグを処理
 	if tagNames != nil {
@@ -270,7 +501,7 @@ func (s *workService) Update(id, userID uint, title, description, pdeContent, th
 			if name = strings.TrimSpace(name); name == "" {
 				continue
 			}
-			tag, err := s.tagRepo.FindOrCreate(name)
+			tag, err := s.tagRepo.FindOrCreate(ctx, name)
 			if err != nil {
 				continue
 			}
@@ -278,63 +509,259 @@ func (s *workService) Update(id, userID uint, title, description, pdeContent, th
 		}
 
 		// タグの関連付けを更新
-		if err := s.tagRepo.AttachTagsToWork(id, tagIDs); err != nil {
+		if err := s.tagRepo.AttachTagsToWork(ctx, id, tagIDs); err != nil {
 			fmt.Printf("タグの更新に失敗しました: %v\n", err)
 		}
 	}
 
-	// PDEが変更されていて、JS変換に失敗していれば非同期で再試行
-	if pdeChanged && (work.JSContent == "" || err != nil) {
-		go func(workID uint, pdeCode string) {
-			// 再度変換を試みる
-			jsContent, err := s.lambdaService.ConvertPDEToJS(pdeCode)
-			if err != nil {
-				fmt.Printf("非同期PDE変換に失敗しました (ID=%d): %v\n", workID, err)
-				return
-			}
+	// PDEが変更されていて、JS変換に失敗していればバックグラウンドで再試行（変更前のJSが残っている場合を含む）
+	if pdeChanged && work.JSValidationStatus != JSValidationRejected && (work.JSContent == "" || jsConversionErr != nil) {
+		s.enqueueConversion(ctx, work.ID)
+	}
+	s.sitemapService.Invalidate()
 
-			// データベースを更新
-			work, err := s.workRepo.FindByID(workID)
-			if err != nil {
-				fmt.Printf("作品の取得に失敗しました (ID=%d): %v\n", workID, err)
-				return
-			}
+	// 更新された作品を取得
+	return s.GetByID(ctx, id)
+}
 
-			work.JSContent = jsContent
-			if err := s.workRepo.Update(work); err != nil {
-				fmt.Printf("JS変換結果の保存に失敗しました (ID=%d): %v\n", workID, err)
-			}
-		}(work.ID, pdeContent)
+// Preview 編集中のPDEコードを保存せずに変換（他の変換より優先して処理する）
+func (s *workService) Preview(ctx context.Context, userID uint, pdeContent string) (*ConversionPreview, error) {
+	// PDEコードのバリデーション
+	if strings.TrimSpace(pdeContent) == "" {
+		return nil, errors.New("PDEコードは必須です")
+	}
+	if err := s.storageQuota.CheckPDESize(pdeContent); err != nil {
+		return nil, err
 	}
 
-	// 更新された作品を取得
-	return s.GetByID(id)
+	// 変換回数の上限を確認
+	if _, err := s.conversionQuota.Consume(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	output, err := s.conversionQueue.Convert(ctx, ConversionPriorityPreview, pdeContent)
+	if err != nil {
+		return nil, fmt.Errorf("PDE変換に失敗しました: %w", err)
+	}
+
+	preview := &ConversionPreview{
+		JSContent:        output.JSContent,
+		ConverterVersion: s.lambdaService.ConverterVersion(),
+		Validation:       s.jsValidator.Validate(output.JSContent),
+	}
+	if preview.Validation.Status == JSValidationRejected {
+		preview.JSContent = ""
+	}
+	return preview, nil
 }
 
 // Delete 作品を削除
-func (s *workService) Delete(id, userID uint) error {
+func (s *workService) Delete(ctx context.Context, id, userID uint) error {
 	// 作品を取得
-	work, err := s.workRepo.FindByID(id)
+	work, err := s.workRepo.FindByID(ctx, id)
 	if err != nil {
 		return errors.New("作品が見つかりません")
 	}
 
-	// 権限チェック
-	if work.UserID != userID {
+	// 権限チェック（作成者と、管理権限のある共同編集者のみ）
+	if !hasWorkPermission(ctx, s.collaboratorRepo, work, userID, models.CollaboratorPermissionManage) {
 		return errors.New("この作品を削除する権限がありません")
 	}
 
 	// データベースから削除
-	return s.workRepo.Delete(id)
+	if err := s.workRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.attributions.Invalidate()
+	s.sitemapService.Invalidate()
+	return nil
+}
+
+// Fork 作品をフォークして自分の作品として作成
+func (s *workService) Fork(ctx context.Context, id, userID uint) (*models.Work, error) {
+	// フォーク元の作品を取得
+	source, err := s.workRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+
+	// 他人の作品はコードが公開されていて、ライセンスが許可している場合のみフォークできる
+	if source.UserID != userID {
+		if !source.CodeShared {
+			return nil, errors.New("コードが公開されていないため、この作品をフォークする権限がありません")
+		}
+		if !source.AllowsFork() {
+			return nil, fmt.Errorf("ライセンス（%s）によりこの作品をフォークする権限がありません", source.License)
+		}
+	}
+
+	tagNames := make([]string, 0, len(source.Tags))
+	for _, tag := range source.Tags {
+		tagNames = append(tagNames, tag.Name)
+	}
+
+	// フォーク元と同じライセンスで作品を作成
+	work, err := s.Create(
+		ctx,
+		source.Title+" (fork)",
+		source.Description,
+		source.PDEContent,
+		source.ThumbnailURL,
+		source.ThumbnailBlurhash,
+		source.CodeShared,
+		source.License,
+		source.Language,
+		tagNames,
+		nil,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// フォーク元を記録
+	work.ForkedFromID = &source.ID
+	if err := s.workRepo.Update(ctx, work); err != nil {
+		return nil, fmt.Errorf("フォーク元の記録に失敗しました: %v", err)
+	}
+	work.Attribution = s.attributions.Resolve(ctx, work)
+
+	return work, nil
+}
+
+// Compare 作品を並べて再生するために、複数の作品のJSとメタデータをまとめて取得
+// PDEコードは公開されている作品か、閲覧者自身の作品の場合のみ含める
+func (s *workService) Compare(ctx context.Context, ids []uint, viewerID *uint) ([]CompareWork, error) {
+	if len(ids) < minCompareWorks || len(ids) > maxCompareWorks {
+		return nil, fmt.Errorf("比較する作品は%d〜%d件指定してください", minCompareWorks, maxCompareWorks)
+	}
+
+	result := make([]CompareWork, 0, len(ids))
+	for _, id := range ids {
+		work, err := s.workRepo.FindByID(ctx, id)
+		if err != nil || work.HiddenAt != nil {
+			return nil, fmt.Errorf("作品が見つかりません (ID=%d)", id)
+		}
+
+		item := CompareWork{
+			ID:                 work.ID,
+			Title:              work.Title,
+			DescriptionHTML:    work.DescriptionHTML,
+			JSContent:          work.JSContent,
+			CodeShared:         work.CodeShared,
+			License:            work.License,
+			ConverterVersion:   work.ConverterVersion,
+			JSValidationStatus: work.JSValidationStatus,
+			ThumbnailURL:       work.ThumbnailURL,
+			ThumbnailBlurhash:  work.ThumbnailBlurhash,
+			LikesCount:         work.LikesCount,
+			CommentsCount:      work.CommentsCount,
+			UserID:             work.UserID,
+			Nickname:           work.User.Nickname,
+			Attribution:        s.attributions.Resolve(ctx, work),
+			CreatedAt:          work.CreatedAt,
+			UpdatedAt:          work.UpdatedAt,
+		}
+		if work.CodeShared || (viewerID != nil && *viewerID == work.UserID) {
+			item.PDEContent = work.PDEContent
+		}
+		result = append(result, item)
+	}
+
+	return result, nil
+}
+
+// 一度に完全削除する作品数
+const trashPurgeBatchSize = 100
+
+// trashCutoff 復元できる期限の基準時刻（これより前に削除された作品は復元できない）
+// 保持期間が0以下の場合は無期限
+func (s *workService) trashCutoff() time.Time {
+	if s.config.Trash.RetentionDays <= 0 {
+		return time.Time{}
+	}
+	return time.Now().AddDate(0, 0, -s.config.Trash.RetentionDays)
+}
+
+// ListTrash 保持期間内に削除した自分の作品一覧を取得
+func (s *workService) ListTrash(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, int, error) {
+	works, total, err := s.workRepo.ListDeletedByUser(ctx, userID, s.trashCutoff(), page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return works, total, pages, nil
+}
+
+// Restore 削除済みの作品を復元
+func (s *workService) Restore(ctx context.Context, id, userID uint) (*models.Work, error) {
+	// 削除済みの作品を取得
+	work, err := s.workRepo.FindDeletedByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("削除済みの作品が見つかりません")
+	}
+
+	// 権限チェック
+	if work.UserID != userID {
+		return nil, errors.New("この作品を復元する権限がありません")
+	}
+
+	// 保持期間を確認
+	if work.DeletedAt.Time.Before(s.trashCutoff()) {
+		return nil, fmt.Errorf("保持期間（%d日）を過ぎたため復元できません", s.config.Trash.RetentionDays)
+	}
+
+	// ストレージ容量を確認
+	if err := s.storageQuota.CheckStorage(ctx, userID, 0, int64(len(work.PDEContent)+len(work.JSContent))); err != nil {
+		return nil, err
+	}
+
+	if err := s.workRepo.Restore(ctx, id); err != nil {
+		return nil, fmt.Errorf("作品の復元に失敗しました: %v", err)
+	}
+	s.attributions.Invalidate()
+	s.sitemapService.Invalidate()
+
+	return s.workRepo.FindByID(ctx, id)
+}
+
+// PurgeTrash 保持期間を過ぎた削除済み作品を完全に削除
+func (s *workService) PurgeTrash(ctx context.Context) (int64, error) {
+	if s.config.Trash.RetentionDays <= 0 {
+		return 0, nil
+	}
+
+	var purged int64
+	for {
+		n, err := s.workRepo.PurgeDeletedBefore(ctx, s.trashCutoff(), trashPurgeBatchSize)
+		purged += n
+		if err != nil || n < trashPurgeBatchSize {
+			return purged, err
+		}
+	}
 }
 
 // List 作品一覧を取得
-func (s *workService) List(page, limit int, search, tag string, userID *uint, sort string) ([]models.Work, int64, int, error) {
-	works, total, err := s.workRepo.List(page, limit, search, tag, userID, sort)
+func (s *workService) List(ctx context.Context, page, limit int, search, tag, license, language string, userID *uint, sort string) ([]models.Work, int64, int, error) {
+	// 絞り込みのない新着順（トップページ）ではピックアップ中の作品を先頭に表示する（オプション）
+	if s.config.Featured.BoostHomepage && sort == "newest" && search == "" && tag == "" && license == "" && language == "" && userID == nil {
+		sort = "featured"
+	}
+
+	works, total, err := s.workRepo.List(ctx, page, limit, search, tag, license, language, userID, sort)
 	if err != nil {
 		return nil, 0, 0, err
 	}
 
+	// 承認済みの共同編集者を設定
+	attachCoAuthors(ctx, s.collaboratorRepo, works)
+
 	// 総ページ数を計算
 	pages := int(total) / limit
 	if int(total)%limit > 0 {
@@ -344,10 +771,163 @@ func (s *workService) List(page, limit int, search, tag string, userID *uint, so
 	return works, total, pages, nil
 }
 
+// SetFeatured 作品をピックアップに設定（管理者用）
+// fromを省略した場合は現在から、untilを省略した場合はfromから既定の日数だけピックアップする
+func (s *workService) SetFeatured(ctx context.Context, id uint, from, until *time.Time) (*models.Work, error) {
+	if _, err := s.workRepo.FindByID(ctx, id); err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+
+	if from == nil {
+		now := time.Now()
+		from = &now
+	}
+	if until == nil && s.config.Featured.DefaultDays > 0 {
+		end := from.AddDate(0, 0, s.config.Featured.DefaultDays)
+		until = &end
+	}
+	if until != nil && !until.After(*from) {
+		return nil, errors.New("ピックアップの終了日時は開始日時より後にしてください")
+	}
+
+	if err := s.workRepo.SetFeatured(ctx, id, from, until); err != nil {
+		return nil, fmt.Errorf("ピックアップの設定に失敗しました: %v", err)
+	}
+
+	return s.workRepo.FindByID(ctx, id)
+}
+
+// UnsetFeatured 作品のピックアップを解除（管理者用）
+func (s *workService) UnsetFeatured(ctx context.Context, id uint) (*models.Work, error) {
+	if _, err := s.workRepo.FindByID(ctx, id); err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+
+	if err := s.workRepo.SetFeatured(ctx, id, nil, nil); err != nil {
+		return nil, fmt.Errorf("ピックアップの解除に失敗しました: %v", err)
+	}
+
+	return s.workRepo.FindByID(ctx, id)
+}
+
+// ListFeatured 現在ピックアップ中の作品一覧を取得
+func (s *workService) ListFeatured(ctx context.Context, page, limit int) ([]models.Work, int64, int, error) {
+	works, total, err := s.workRepo.ListFeatured(ctx, time.Now(), page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 承認済みの共同編集者を設定
+	attachCoAuthors(ctx, s.collaboratorRepo, works)
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return works, total, pages, nil
+}
+
+// UpdateMadeAt 作品を制作したイベント・場所を設定（作成者と編集権限のある共同編集者のみ）
+func (s *workService) UpdateMadeAt(ctx context.Context, id, userID uint, input MadeAtInput) (*models.Work, error) {
+	work, err := s.workRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("作品が見つかりません")
+	}
+	if !hasWorkPermission(ctx, s.collaboratorRepo, work, userID, models.CollaboratorPermissionEdit) {
+		return nil, errors.New("この作品を更新する権限がありません")
+	}
+
+	input, err = validateMadeAt(input)
+	if err != nil {
+		return nil, err
+	}
+
+	work.Event = input.Event
+	work.LocationName = input.LocationName
+	work.Latitude = input.Latitude
+	work.Longitude = input.Longitude
+	if err := s.workRepo.UpdateMadeAt(ctx, work); err != nil {
+		return nil, fmt.Errorf("制作したイベント・場所の更新に失敗しました: %v", err)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// ListByEvent イベントで制作された作品一覧を取得
+func (s *workService) ListByEvent(ctx context.Context, event string, page, limit int) ([]models.Work, int64, int, error) {
+	event, err := normalizeEvent(event)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if event == "" {
+		return nil, 0, 0, errors.New("イベントを指定してください")
+	}
+
+	works, total, err := s.workRepo.ListByEvent(ctx, event, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 承認済みの共同編集者を設定
+	attachCoAuthors(ctx, s.collaboratorRepo, works)
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return works, total, pages, nil
+}
+
+// ListEvents 作品が登録されているイベントを作品数とともに取得（最後に投稿された順）
+func (s *workService) ListEvents(ctx context.Context) ([]repository.WorkEventSummary, error) {
+	return s.workRepo.ListEvents(ctx, maxEventSummaries)
+}
+
+// ListLanguages 作品の言語を作品数とともに取得（作品数の多い順）
+func (s *workService) ListLanguages(ctx context.Context) ([]repository.WorkLanguageSummary, error) {
+	return s.workRepo.ListLanguages(ctx)
+}
+
+// resolveWorkLanguage 指定された言語を確認し、未指定の場合はタイトルと説明文から判定
+func resolveWorkLanguage(language, title, description string) (string, error) {
+	if strings.TrimSpace(language) == "" {
+		return utils.DetectLanguage(title + "\n" + description), nil
+	}
+	code, ok := models.NormalizeWorkLanguage(language)
+	if !ok {
+		return "", fmt.Errorf("無効な言語です: %s", language)
+	}
+	return code, nil
+}
+
+// Map 制作した場所の座標がある作品をGeoJSONで取得（eventを指定した場合はそのイベントのみ）
+func (s *workService) Map(ctx context.Context, event string) (*WorkFeatureCollection, error) {
+	event, err := normalizeEvent(event)
+	if err != nil {
+		return nil, err
+	}
+
+	works, err := s.workRepo.ListWithLocation(ctx, event, maxMapWorks)
+	if err != nil {
+		return nil, err
+	}
+	return newWorkFeatureCollection(works), nil
+}
+
 // AddLike いいねを追加
-func (s *workService) AddLike(userID, workID uint) (int, error) {
+func (s *workService) AddLike(ctx context.Context, userID, workID uint) (int, error) {
+	// 作品を取得
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return 0, errors.New("作品が見つかりません")
+	}
+
 	// いいね済みかチェック
-	liked, err := s.workRepo.HasLiked(userID, workID)
+	liked, err := s.workRepo.HasLiked(ctx, userID, workID)
 	if err != nil {
 		return 0, err
 	}
@@ -357,12 +937,17 @@ func (s *workService) AddLike(userID, workID uint) (int, error) {
 	}
 
 	// いいねを追加
-	if err := s.workRepo.AddLike(userID, workID); err != nil {
+	if err := s.workRepo.AddLike(ctx, userID, workID); err != nil {
 		return 0, err
 	}
 
+	// 作者のレピュテーションを加算（自分の作品へのいいねは除く）
+	if work.UserID != userID {
+		s.reputationService.Apply(ctx, work.UserID, ReputationLikeReceived)
+	}
+
 	// いいね数を取得
-	count, err := s.workRepo.GetLikesCount(workID)
+	count, err := s.workRepo.GetLikesCount(ctx, workID)
 	if err != nil {
 		return 0, err
 	}
@@ -371,9 +956,15 @@ func (s *workService) AddLike(userID, workID uint) (int, error) {
 }
 
 // RemoveLike いいねを削除
-func (s *workService) RemoveLike(userID, workID uint) (int, error) {
+func (s *workService) RemoveLike(ctx context.Context, userID, workID uint) (int, error) {
+	// 作品を取得
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil {
+		return 0, errors.New("作品が見つかりません")
+	}
+
 	// いいね済みかチェック
-	liked, err := s.workRepo.HasLiked(userID, workID)
+	liked, err := s.workRepo.HasLiked(ctx, userID, workID)
 	if err != nil {
 		return 0, err
 	}
@@ -383,12 +974,17 @@ func (s *workService) RemoveLike(userID, workID uint) (int, error) {
 	}
 
 	// いいねを削除
-	if err := s.workRepo.RemoveLike(userID, workID); err != nil {
+	if err := s.workRepo.RemoveLike(ctx, userID, workID); err != nil {
 		return 0, err
 	}
 
+	// 作者のレピュテーションを減算
+	if work.UserID != userID {
+		s.reputationService.Apply(ctx, work.UserID, ReputationLikeRemoved)
+	}
+
 	// いいね数を取得
-	count, err := s.workRepo.GetLikesCount(workID)
+	count, err := s.workRepo.GetLikesCount(ctx, workID)
 	if err != nil {
 		return 0, err
 	}
@@ -397,17 +993,74 @@ func (s *workService) RemoveLike(userID, workID uint) (int, error) {
 }
 
 // HasLiked ユーザーがいいねしているか確認
-func (s *workService) HasLiked(userID, workID uint) (bool, error) {
-	return s.workRepo.HasLiked(userID, workID)
+func (s *workService) HasLiked(ctx context.Context, userID, workID uint) (bool, error) {
+	return s.workRepo.HasLiked(ctx, userID, workID)
+}
+
+// ListLiked ユーザーがいいねした作品一覧を取得（いいねの新しい順）
+func (s *workService) ListLiked(ctx context.Context, userID uint, page, limit int) ([]LikedWork, int64, int, error) {
+	likes, total, err := s.workRepo.ListLikedByUser(ctx, userID, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	works := make([]LikedWork, 0, len(likes))
+	for _, like := range likes {
+		works = append(works, LikedWork{Work: like.Work, LikedAt: like.CreatedAt})
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return works, total, pages, nil
+}
+
+// ListLikers 作品にいいねしたユーザー一覧を取得（いいねの新しい順）
+// 非表示の作品は一覧を公開せず、いいねを非公開にしているユーザーは含めない
+func (s *workService) ListLikers(ctx context.Context, workID uint, page, limit int) ([]Liker, int64, int, error) {
+	work, err := s.workRepo.FindByID(ctx, workID)
+	if err != nil || work.HiddenAt != nil {
+		return nil, 0, 0, errors.New("作品が見つかりません")
+	}
+
+	likes, total, err := s.workRepo.ListLikers(ctx, workID, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	users := make([]Liker, 0, len(likes))
+	for _, like := range likes {
+		users = append(users, Liker{
+			ID:       like.User.ID,
+			Name:     like.User.Name,
+			Nickname: like.User.Nickname,
+			Handle:   like.User.Handle,
+			LikedAt:  like.CreatedAt,
+		})
+	}
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return users, total, pages, nil
 }
 
 // GetUserWorks ユーザーの作品一覧を取得
-func (s *workService) GetUserWorks(userID uint, page, limit int) ([]models.Work, int64, int, error) {
-	works, total, err := s.workRepo.ListByUser(userID, page, limit)
+func (s *workService) GetUserWorks(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, int, error) {
+	works, total, err := s.workRepo.ListByUser(ctx, userID, page, limit)
 	if err != nil {
 		return nil, 0, 0, err
 	}
 
+	// 承認済みの共同編集者を設定
+	attachCoAuthors(ctx, s.collaboratorRepo, works)
+
 	// 総ページ数を計算
 	pages := int(total) / limit
 	if int(total)%limit > 0 {
@@ -416,3 +1069,140 @@ func (s *workService) GetUserWorks(userID uint, page, limit int) ([]models.Work,
 
 	return works, total, pages, nil
 }
+
+// GetCollaborations ユーザーが共同編集者として参加している作品一覧を取得
+func (s *workService) GetCollaborations(ctx context.Context, userID uint, page, limit int) ([]models.Work, int64, int, error) {
+	works, total, err := s.workRepo.ListByCollaborator(ctx, userID, page, limit)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 承認済みの共同編集者を設定
+	attachCoAuthors(ctx, s.collaboratorRepo, works)
+
+	// 総ページ数を計算
+	pages := int(total) / limit
+	if int(total)%limit > 0 {
+		pages++
+	}
+
+	return works, total, pages, nil
+}
+
+// ReconcileCounters いいね数・コメント数のずれを修正
+func (s *workService) ReconcileCounters(ctx context.Context) (int64, error) {
+	return s.workRepo.ReconcileCounters(ctx)
+}
+
+// Bulk 自分の作品に対して一括操作を行う
+// 存在しない作品や他人の作品は結果に含めてスキップし、残りをまとめて1トランザクションで処理する
+func (s *workService) Bulk(ctx context.Context, userID uint, action string, workIDs []uint, tagNames []string, codeShared *bool) ([]BulkWorkResult, error) {
+	if len(workIDs) == 0 {
+		return nil, errors.New("作品IDを指定してください")
+	}
+	if len(workIDs) > maxBulkWorks {
+		return nil, fmt.Errorf("一度に操作できる作品は%d件までです", maxBulkWorks)
+	}
+
+	// 操作内容を組み立て
+	var changes repository.BulkWorkChanges
+	switch action {
+	case BulkActionDelete:
+		changes.Delete = true
+	case BulkActionAddTags, BulkActionRemoveTags:
+		tagIDs, err := s.bulkTagIDs(ctx, action, tagNames)
+		if err != nil {
+			return nil, err
+		}
+		if action == BulkActionAddTags {
+			changes.AddTagIDs = tagIDs
+		} else {
+			changes.RemoveTagIDs = tagIDs
+		}
+	case BulkActionSetVisibility:
+		if codeShared == nil {
+			return nil, errors.New("code_sharedを指定してください")
+		}
+		changes.CodeShared = codeShared
+	default:
+		return nil, fmt.Errorf("不明な操作です: %s", action)
+	}
+
+	// 対象の作品を取得
+	works, err := s.workRepo.FindByIDs(ctx, workIDs)
+	if err != nil {
+		return nil, err
+	}
+	owners := make(map[uint]uint, len(works))
+	for _, work := range works {
+		owners[work.ID] = work.UserID
+	}
+
+	// 作品ごとに権限を確認
+	results := make([]BulkWorkResult, 0, len(workIDs))
+	seen := make(map[uint]bool, len(workIDs))
+	var targetIDs []uint
+	for _, id := range workIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		ownerID, ok := owners[id]
+		switch {
+		case !ok:
+			results = append(results, BulkWorkResult{WorkID: id, Status: BulkStatusNotFound, Error: "作品が見つかりません"})
+		case ownerID != userID:
+			results = append(results, BulkWorkResult{WorkID: id, Status: BulkStatusForbidden, Error: "この作品を操作する権限がありません"})
+		default:
+			results = append(results, BulkWorkResult{WorkID: id, Status: BulkStatusOK})
+			targetIDs = append(targetIDs, id)
+		}
+	}
+
+	// まとめて適用
+	if err := s.workRepo.ApplyBulk(ctx, targetIDs, changes); err != nil {
+		return nil, fmt.Errorf("一括操作に失敗しました: %v", err)
+	}
+	if changes.Delete {
+		s.attributions.Invalidate()
+		s.sitemapService.Invalidate()
+	}
+
+	return results, nil
+}
+
+// bulkTagIDs 一括操作の対象タグのIDを取得
+// 追加の場合は存在しないタグを作成し、削除の場合は存在しないタグを無視する
+func (s *workService) bulkTagIDs(ctx context.Context, action string, tagNames []string) ([]uint, error) {
+	var tagIDs []uint
+	for _, name := range tagNames {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+
+		var tag *models.Tag
+		var err error
+		if action == BulkActionAddTags {
+			tag, err = s.tagRepo.FindOrCreate(ctx, name)
+		} else {
+			tag, err = s.tagRepo.FindByName(ctx, name)
+		}
+		if err != nil {
+			continue
+		}
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	if len(tagIDs) == 0 && action == BulkActionAddTags {
+		return nil, errors.New("タグを指定してください")
+	}
+	return tagIDs, nil
+}
+
+// enqueueConversion 変換の再試行ジョブを登録（作品の保存は完了しているため、エラーはログ出力のみとする）
+func (s *workService) enqueueConversion(ctx context.Context, workID uint) {
+	if err := s.conversionJobs.Enqueue(ctx, workID); err != nil {
+		fmt.Printf("変換の再試行ジョブの登録に失敗しました (ID=%d): %v\n", workID, err)
+	}
+}
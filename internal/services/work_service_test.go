@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/config"
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/repository/memory"
+)
+
+// failingConversionQueue 常に変換に失敗するConversionQueue
+type failingConversionQueue struct {
+	ConversionQueue
+}
+
+func (failingConversionQueue) Convert(ctx context.Context, priority ConversionPriority, pdeContent string) (ConversionOutput, error) {
+	return ConversionOutput{}, errors.New("変換に失敗しました")
+}
+
+// recordingConversionJobs 再試行ジョブを登録した作品を記録するConversionJobService
+type recordingConversionJobs struct {
+	ConversionJobService
+	enqueued []uint
+}
+
+func (r *recordingConversionJobs) Enqueue(ctx context.Context, workID uint) error {
+	r.enqueued = append(r.enqueued, workID)
+	return nil
+}
+
+// unlimitedQuota 上限のないStorageQuotaService・ConversionQuotaService
+type unlimitedQuota struct {
+	StorageQuotaService
+	ConversionQuotaService
+}
+
+func (unlimitedQuota) CheckPDESize(pdeContent string) error { return nil }
+
+func (unlimitedQuota) CheckStorage(ctx context.Context, userID, excludeWorkID uint, additionalBytes int64) error {
+	return nil
+}
+
+func (unlimitedQuota) Consume(ctx context.Context, userID uint) (*ConversionQuota, error) {
+	return &ConversionQuota{}, nil
+}
+
+// noopSitemap 何もしないSitemapService
+type noopSitemap struct {
+	SitemapService
+}
+
+func (noopSitemap) Invalidate() {}
+
+func TestWorkServiceUpdateRetriesFailedConversionWhenJSExists(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	workRepo := memory.NewWorkRepository(store)
+
+	work := &models.Work{
+		Title:      "作品",
+		PDEContent: "void setup() {}",
+		JSContent:  "function setup() {}",
+		UserID:     1,
+	}
+	if err := workRepo.Create(ctx, work); err != nil {
+		t.Fatalf("作品の作成に失敗しました: %v", err)
+	}
+
+	jobs := &recordingConversionJobs{}
+	quota := unlimitedQuota{}
+	s := &workService{
+		workRepo:         workRepo,
+		collaboratorRepo: memory.NewCollaboratorRepository(store),
+		conversionQueue:  failingConversionQueue{},
+		conversionQuota:  quota,
+		storageQuota:     quota,
+		sitemapService:   noopSitemap{},
+		conversionJobs:   jobs,
+		attributions:     newAttributionResolver(workRepo),
+		config:           &config.Config{},
+	}
+
+	updated, err := s.Update(ctx, work.ID, work.UserID, "作品", "", "void setup() { size(200, 200); }", "", false, "", "ja", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("作品の更新に失敗しました: %v", err)
+	}
+	if updated.JSContent == "" {
+		t.Fatalf("変更前のJSが残っていることを前提としています")
+	}
+	if len(jobs.enqueued) != 1 || jobs.enqueued[0] != work.ID {
+		t.Fatalf("変換の再試行ジョブが登録されていません: %v", jobs.enqueued)
+	}
+}
@@ -0,0 +1,68 @@
+// Package testsupport リポジトリ層・サービス層の統合テスト用のヘルパー
+// 一時的なデータベースの作成と、テストデータのファクトリを提供する
+package testsupport
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+	"github.com/SketchShifter/sketchshifter_backend/internal/utils"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// テスト用のMySQLサーバーを指定する環境変数
+// 例: TEST_DB_DSN="root:test@tcp(127.0.0.1:3307)/"
+const testDBDSNEnv = "TEST_DB_DSN"
+
+// NewTestDB テストごとに一時的なデータベースを作成し、マイグレーションを実行
+// データベースはテストの終了時に削除される。TEST_DB_DSNが未設定の場合はテストをスキップする。
+func NewTestDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	serverDSN := os.Getenv(testDBDSNEnv)
+	if serverDSN == "" {
+		t.Skipf("%sが未設定のため、データベースを使用するテストをスキップします", testDBDSNEnv)
+	}
+	if !strings.HasSuffix(serverDSN, "/") {
+		serverDSN += "/"
+	}
+
+	gormConfig := &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+
+	// サーバーに接続して一時的なデータベースを作成
+	server, err := gorm.Open(mysql.Open(serverDSN), gormConfig)
+	if err != nil {
+		t.Fatalf("テスト用のデータベースサーバーに接続できません: %v", err)
+	}
+	name := "sketchshifter_test_" + utils.GenerateRandomString(12)
+	if err := server.Exec(fmt.Sprintf("CREATE DATABASE `%s` CHARACTER SET utf8mb4", name)).Error; err != nil {
+		t.Fatalf("テスト用のデータベースを作成できません: %v", err)
+	}
+
+	db, err := gorm.Open(mysql.Open(serverDSN+name+"?charset=utf8mb4&parseTime=True&loc=Local"), gormConfig)
+	if err != nil {
+		t.Fatalf("テスト用のデータベースに接続できません: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		server.Exec(fmt.Sprintf("DROP DATABASE IF EXISTS `%s`", name))
+		if sqlDB, err := server.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if err := db.AutoMigrate(models.AllModels()...); err != nil {
+		t.Fatalf("マイグレーションに失敗しました: %v", err)
+	}
+
+	return db
+}
@@ -0,0 +1,172 @@
+package testsupport
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SketchShifter/sketchshifter_backend/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// DefaultPassword ファクトリで作成したユーザーのパスワード
+const DefaultPassword = "password123"
+
+// Factory テストデータを作成するファクトリ
+// 各メソッドは必須項目に連番の値を設定して保存し、オプションで任意の項目を上書きできる
+//
+//	f := testsupport.NewFactory(t, db)
+//	owner := f.User()
+//	work := f.Work(owner, func(w *models.Work) { w.CodeShared = true })
+type Factory struct {
+	t   testing.TB
+	db  *gorm.DB
+	seq int
+}
+
+// NewFactory Factoryを作成
+func NewFactory(t testing.TB, db *gorm.DB) *Factory {
+	return &Factory{t: t, db: db}
+}
+
+// next 連番を取得
+func (f *Factory) next() int {
+	f.seq++
+	return f.seq
+}
+
+// create レコードを保存（失敗した場合はテストを失敗させる）
+func (f *Factory) create(value interface{}) {
+	f.t.Helper()
+	if err := f.db.Create(value).Error; err != nil {
+		f.t.Fatalf("テストデータの作成に失敗しました: %v", err)
+	}
+}
+
+// User ユーザーを作成（パスワードはDefaultPassword）
+func (f *Factory) User(opts ...func(*models.User)) *models.User {
+	f.t.Helper()
+
+	// テストを速くするため最小のコストでハッシュ化する
+	hashed, err := bcrypt.GenerateFromPassword([]byte(DefaultPassword), bcrypt.MinCost)
+	if err != nil {
+		f.t.Fatalf("パスワードのハッシュ化に失敗しました: %v", err)
+	}
+
+	n := f.next()
+	user := &models.User{
+		Email:    fmt.Sprintf("user%d@example.com", n),
+		Password: string(hashed),
+		Name:     fmt.Sprintf("ユーザー%d", n),
+		Nickname: fmt.Sprintf("user%d", n),
+		Role:     models.UserRoleUser,
+	}
+	for _, opt := range opts {
+		opt(user)
+	}
+	f.create(user)
+	return user
+}
+
+// Work 作品を作成
+func (f *Factory) Work(owner *models.User, opts ...func(*models.Work)) *models.Work {
+	f.t.Helper()
+
+	n := f.next()
+	work := &models.Work{
+		Title:              fmt.Sprintf("作品%d", n),
+		Description:        "テスト用の作品",
+		PDEContent:         "void setup() {\n  size(400, 400);\n}\n",
+		JSContent:          "function setup() {\n  createCanvas(400, 400);\n}\n",
+		JSValidationStatus: "passed", // services.JSValidationPassed（サービス層のテストから使えるようにimportしない）
+		License:            models.WorkLicenseAllRightsReserved,
+		UserID:             owner.ID,
+	}
+	for _, opt := range opts {
+		opt(work)
+	}
+	f.create(work)
+	return work
+}
+
+// Project プロジェクトを作成（オーナーはメンバーとしても登録する）
+func (f *Factory) Project(owner *models.User, opts ...func(*models.Project)) *models.Project {
+	f.t.Helper()
+
+	n := f.next()
+	project := &models.Project{
+		Title:          fmt.Sprintf("プロジェクト%d", n),
+		Description:    "テスト用のプロジェクト",
+		InvitationCode: fmt.Sprintf("INVITE%04d", n),
+		OwnerID:        owner.ID,
+	}
+	for _, opt := range opts {
+		opt(project)
+	}
+	f.create(project)
+	f.Member(project, owner, true)
+	return project
+}
+
+// Member プロジェクトにメンバーを追加
+func (f *Factory) Member(project *models.Project, user *models.User, isOwner bool) *models.ProjectMember {
+	f.t.Helper()
+
+	member := &models.ProjectMember{
+		ProjectID: project.ID,
+		UserID:    user.ID,
+		IsOwner:   isOwner,
+		JoinedAt:  time.Now(),
+	}
+	f.create(member)
+	return member
+}
+
+// Task タスクを作成
+func (f *Factory) Task(project *models.Project, opts ...func(*models.Task)) *models.Task {
+	f.t.Helper()
+
+	n := f.next()
+	task := &models.Task{
+		Title:       fmt.Sprintf("タスク%d", n),
+		Description: "テスト用のタスク",
+		ProjectID:   project.ID,
+		OrderIndex:  n,
+	}
+	for _, opt := range opts {
+		opt(task)
+	}
+	f.create(task)
+	return task
+}
+
+// Vote 投票を作成（worksを指定した場合は各作品の選択肢も作成する）
+func (f *Factory) Vote(task *models.Task, creator *models.User, works []*models.Work, opts ...func(*models.Vote)) *models.Vote {
+	f.t.Helper()
+
+	n := f.next()
+	vote := &models.Vote{
+		Title:     fmt.Sprintf("投票%d", n),
+		TaskID:    task.ID,
+		IsActive:  true,
+		CreatedBy: creator.ID,
+	}
+	for _, opt := range opts {
+		opt(vote)
+	}
+	f.create(vote)
+
+	for _, work := range works {
+		workID := work.ID
+		option := models.VoteOption{
+			VoteID:     vote.ID,
+			OptionText: work.Title,
+			WorkID:     &workID,
+		}
+		f.create(&option)
+		vote.Options = append(vote.Options, option)
+	}
+	return vote
+}
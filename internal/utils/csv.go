@@ -0,0 +1,23 @@
+package utils
+
+import "strings"
+
+// csvFormulaPrefixes 表計算ソフトが数式として解釈する先頭文字
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// CSVSafe セルの値が数式として解釈されないように先頭に ' を付ける（CSVインジェクション対策）
+func CSVSafe(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// CSVSafeRecord CSVの1行のすべてのセルにCSVSafeを適用
+func CSVSafeRecord(record []string) []string {
+	safe := make([]string, len(record))
+	for i, value := range record {
+		safe[i] = CSVSafe(value)
+	}
+	return safe
+}
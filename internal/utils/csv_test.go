@@ -0,0 +1,23 @@
+package utils
+
+import "testing"
+
+func TestCSVSafe(t *testing.T) {
+	cases := map[string]string{
+		"":               "",
+		"hello":          "hello",
+		"=1+1":           "'=1+1",
+		"+1":             "'+1",
+		"-1":             "'-1",
+		"@SUM(A1)":       "'@SUM(A1)",
+		"\t=1":           "'\t=1",
+		"\r=1":           "'\r=1",
+		"a=1":            "a=1",
+		"コメント=HYPERLINK": "コメント=HYPERLINK",
+	}
+	for input, want := range cases {
+		if got := CSVSafe(input); got != want {
+			t.Errorf("CSVSafe(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// EncodeCursor ページ位置を不透明なカーソル文字列に変換
+// クライアントは中身に依存せず、レスポンスのカーソルをそのまま次のリクエストに渡す
+func EncodeCursor(page, limit int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("p%d:l%d", page, limit)))
+}
+
+// DecodeCursor カーソル文字列からページ位置を取得
+func DecodeCursor(cursor string) (page, limit int, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, errors.New("無効なカーソルです")
+	}
+	if _, err := fmt.Sscanf(string(raw), "p%d:l%d", &page, &limit); err != nil || page < 1 || limit < 1 {
+		return 0, 0, errors.New("無効なカーソルです")
+	}
+	return page, limit, nil
+}
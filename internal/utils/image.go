@@ -0,0 +1,202 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"math"
+	"strings"
+)
+
+// blurhashの計算に使う画素の最大数（縦横それぞれ）
+// ぼかし画像のため、大きな画像も間引いてから計算する
+const blurhashSampleSize = 64
+
+// blurhashの文字（base83）
+const blurhashCharacters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// EncodeBlurhash 画像をblurhash（読み込み中に表示するぼかし画像を表す短い文字列）に変換
+// xComponents・yComponentsは横・縦方向の成分の数（1〜9）
+func EncodeBlurhash(img image.Image, xComponents, yComponents int) (string, error) {
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", errors.New("blurhashの成分の数は1〜9で指定してください")
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return "", errors.New("画像が空です")
+	}
+
+	// 間引いた画素を線形RGBに変換
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > blurhashSampleSize {
+		width = blurhashSampleSize
+	}
+	if height > blurhashSampleSize {
+		height = blurhashSampleSize
+	}
+	pixels := make([][3]float64, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x*bounds.Dx()/width, bounds.Min.Y+y*bounds.Dy()/height).RGBA()
+			pixels[y*width+x] = [3]float64{sRGBToLinear(r >> 8), sRGBToLinear(g >> 8), sRGBToLinear(b >> 8)}
+		}
+	}
+
+	// 成分ごとにコサイン変換
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1
+			}
+			var factor [3]float64
+			for y := 0; y < height; y++ {
+				for x := 0; x < width; x++ {
+					basis := normalisation *
+						math.Cos(math.Pi*float64(i)*float64(x)/float64(width)) *
+						math.Cos(math.Pi*float64(j)*float64(y)/float64(height))
+					pixel := pixels[y*width+x]
+					factor[0] += basis * pixel[0]
+					factor[1] += basis * pixel[1]
+					factor[2] += basis * pixel[2]
+				}
+			}
+			scale := 1 / float64(width*height)
+			factors = append(factors, [3]float64{factor[0] * scale, factor[1] * scale, factor[2] * scale})
+		}
+	}
+
+	var hash strings.Builder
+	hash.WriteString(encodeBase83((xComponents-1)+(yComponents-1)*9, 1))
+
+	dc, ac := factors[0], factors[1:]
+	maximumValue := 1.0
+	if len(ac) > 0 {
+		actualMax := 0.0
+		for _, factor := range ac {
+			for _, v := range factor {
+				actualMax = math.Max(actualMax, math.Abs(v))
+			}
+		}
+		quantisedMax := int(math.Max(0, math.Min(82, math.Floor(actualMax*166-0.5))))
+		maximumValue = float64(quantisedMax+1) / 166
+		hash.WriteString(encodeBase83(quantisedMax, 1))
+	} else {
+		hash.WriteString(encodeBase83(0, 1))
+	}
+
+	hash.WriteString(encodeBase83(linearToSRGB(dc[0])<<16+linearToSRGB(dc[1])<<8+linearToSRGB(dc[2]), 4))
+	for _, factor := range ac {
+		quantise := func(v float64) int {
+			return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maximumValue, 0.5)*9+9.5))))
+		}
+		hash.WriteString(encodeBase83(quantise(factor[0])*19*19+quantise(factor[1])*19+quantise(factor[2]), 2))
+	}
+	return hash.String(), nil
+}
+
+// sRGBToLinear sRGBの値（0〜255）を線形の値（0〜1）に変換
+func sRGBToLinear(value uint32) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB 線形の値（0〜1）をsRGBの値（0〜255）に変換
+func linearToSRGB(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+// signPow 符号を保ったまま累乗
+func signPow(value, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
+
+// encodeBase83 値をlength桁のbase83文字列に変換
+func encodeBase83(value, length int) string {
+	result := make([]byte, length)
+	for i := 1; i <= length; i++ {
+		digit := (value / int(math.Pow(83, float64(length-i)))) % 83
+		result[i-1] = blurhashCharacters[digit]
+	}
+	return string(result)
+}
+
+// pngSignature PNGファイルの先頭8バイト
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// StripImageMetadata JPEG・PNGからEXIF（撮影場所など）・XMP・IPTC・テキストのメタデータを除去
+// 画素データは再圧縮せずにそのまま残す（その他の形式と、解析できない場合は元のデータを返す）
+func StripImageMetadata(data []byte) []byte {
+	switch {
+	case len(data) > 2 && data[0] == 0xFF && data[1] == 0xD8:
+		if stripped, ok := stripJPEGMetadata(data); ok {
+			return stripped
+		}
+	case bytes.HasPrefix(data, pngSignature):
+		if stripped, ok := stripPNGMetadata(data); ok {
+			return stripped
+		}
+	}
+	return data
+}
+
+// stripJPEGMetadata JPEGのAPP1（EXIF・XMP）とAPP13（IPTC）のセグメントを除去
+func stripJPEGMetadata(data []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, false
+		}
+		marker := data[pos+1]
+		// 画像データの開始（SOS）以降はそのまま残す
+		if marker == 0xDA {
+			return append(out, data[pos:]...), true
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, false
+		}
+		if marker != 0xE1 && marker != 0xED {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+	return nil, false
+}
+
+// stripPNGMetadata PNGのeXIf・tEXt・zTXt・iTXtのチャンクを除去
+func stripPNGMetadata(data []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	pos := len(pngSignature)
+	for pos+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		chunkType := string(data[pos+4 : pos+8])
+		end := pos + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, false
+		}
+		switch chunkType {
+		case "eXIf", "tEXt", "zTXt", "iTXt":
+		default:
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+		if chunkType == "IEND" {
+			return out, true
+		}
+	}
+	return nil, false
+}
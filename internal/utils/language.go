@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 英語と判定するための頻出語（ラテン文字の文章は他の言語と区別できないため、これらを含む場合のみ英語とする）
+var englishWords = map[string]bool{
+	"the": true, "and": true, "is": true, "are": true, "of": true, "to": true,
+	"with": true, "this": true, "that": true, "for": true, "in": true, "it": true,
+}
+
+// DetectLanguage 文章の言語をISO 639-1のコードで推定（判定できない場合は空文字列）
+// 使われている文字の種類で判定するため、ラテン文字の文章は英語のみ判定する
+func DetectLanguage(text string) string {
+	counts := map[string]int{}
+	latin := 0
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			counts["ja"]++
+		case unicode.Is(unicode.Han, r):
+			counts["han"]++
+		case unicode.Is(unicode.Hangul, r):
+			counts["ko"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts["ru"]++
+		case unicode.Is(unicode.Arabic, r):
+			counts["ar"]++
+		case unicode.Is(unicode.Thai, r):
+			counts["th"]++
+		case unicode.Is(unicode.Greek, r):
+			counts["el"]++
+		case unicode.Is(unicode.Hebrew, r):
+			counts["he"]++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		}
+	}
+
+	// 漢字はかなを含む場合は日本語、含まない場合は中国語とする
+	if counts["ja"] > 0 {
+		counts["ja"] += counts["han"]
+	} else if counts["han"] > 0 {
+		counts["zh"] = counts["han"]
+	}
+	delete(counts, "han")
+
+	language, most := "", 0
+	for code, count := range counts {
+		if count > most || (count == most && code < language) {
+			language, most = code, count
+		}
+	}
+	if language != "" && most*2 >= latin {
+		return language
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		if englishWords[word] {
+			return "en"
+		}
+	}
+	return language
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 説明文で利用できるMarkdown記法
+//
+// 入力はすべてHTMLエスケープしてから変換するため、生のHTMLタグは出力されない。
+// 出力されるHTML要素は以下に限定される。
+//
+//	ブロック要素: p, h1〜h6, ul, ol, li, blockquote, pre, code, hr
+//	インライン要素: strong, em, del, code, a, br
+//
+// リンクは http / https / mailto のURLのみ許可し、
+// rel="nofollow noopener noreferrer" を付与する。
+
+var (
+	headingPattern    = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	unorderedPattern  = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+	orderedPattern    = regexp.MustCompile(`^\d+[.)]\s+(.*)$`)
+	hrPattern         = regexp.MustCompile(`^(?:-{3,}|\*{3,}|_{3,})$`)
+	inlineCodePattern = regexp.MustCompile("`([^`]+)`")
+	linkPattern       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	strongPattern     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	emPattern         = regexp.MustCompile(`\*([^*]+)\*`)
+	strikePattern     = regexp.MustCompile(`~~([^~]+)~~`)
+)
+
+// RenderMarkdown Markdownを安全なHTMLに変換
+func RenderMarkdown(source string) string {
+	if strings.TrimSpace(source) == "" {
+		return ""
+	}
+
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	var out strings.Builder
+	var paragraph []string
+	var listTag string
+	var quote []string
+	inCode := false
+
+	flushParagraph := func() {
+		if len(paragraph) == 0 {
+			return
+		}
+		parts := make([]string, len(paragraph))
+		for i, line := range paragraph {
+			parts[i] = renderInline(line)
+		}
+		out.WriteString("<p>" + strings.Join(parts, "<br>") + "</p>\n")
+		paragraph = nil
+	}
+	closeList := func() {
+		if listTag != "" {
+			out.WriteString("</" + listTag + ">\n")
+			listTag = ""
+		}
+	}
+	flushQuote := func() {
+		if len(quote) == 0 {
+			return
+		}
+		out.WriteString("<blockquote>" + RenderMarkdown(strings.Join(quote, "\n")) + "</blockquote>\n")
+		quote = nil
+	}
+	flushAll := func() {
+		flushParagraph()
+		closeList()
+		flushQuote()
+	}
+	openList := func(tag string) {
+		if listTag != tag {
+			closeList()
+			out.WriteString("<" + tag + ">\n")
+			listTag = tag
+		}
+	}
+
+	for _, raw := range lines {
+		line := strings.TrimRight(raw, " \t")
+
+		// コードブロック
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			if inCode {
+				out.WriteString("</code></pre>\n")
+				inCode = false
+			} else {
+				flushAll()
+				out.WriteString("<pre><code>")
+				inCode = true
+			}
+			continue
+		}
+		if inCode {
+			out.WriteString(html.EscapeString(raw) + "\n")
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+
+		// 引用
+		if strings.HasPrefix(trimmed, ">") {
+			flushParagraph()
+			closeList()
+			quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(trimmed, ">"), " "))
+			continue
+		}
+		flushQuote()
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+		case hrPattern.MatchString(trimmed):
+			flushAll()
+			out.WriteString("<hr>\n")
+		case headingPattern.MatchString(trimmed):
+			flushAll()
+			m := headingPattern.FindStringSubmatch(trimmed)
+			level := string(rune('0' + len(m[1])))
+			out.WriteString("<h" + level + ">" + renderInline(m[2]) + "</h" + level + ">\n")
+		case unorderedPattern.MatchString(trimmed):
+			flushParagraph()
+			openList("ul")
+			out.WriteString("<li>" + renderInline(unorderedPattern.FindStringSubmatch(trimmed)[1]) + "</li>\n")
+		case orderedPattern.MatchString(trimmed):
+			flushParagraph()
+			openList("ol")
+			out.WriteString("<li>" + renderInline(orderedPattern.FindStringSubmatch(trimmed)[1]) + "</li>\n")
+		default:
+			closeList()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+
+	// 閉じられていないコードブロックを閉じる
+	if inCode {
+		out.WriteString("</code></pre>\n")
+	}
+	flushAll()
+
+	return strings.TrimRight(out.String(), "\n")
+}
+
+// renderInline インライン要素を変換
+// 先にHTMLエスケープを行い、その後で許可した記法のみタグに置換する
+func renderInline(text string) string {
+	escaped := html.EscapeString(strings.ReplaceAll(text, "\x00", ""))
+
+	// インラインコードとリンクは他の記法の対象外にするため退避する
+	var stash []string
+	hold := func(s string) string {
+		stash = append(stash, s)
+		return "\x00" + strconv.Itoa(len(stash)-1) + "\x00"
+	}
+
+	escaped = inlineCodePattern.ReplaceAllStringFunc(escaped, func(s string) string {
+		return hold("<code>" + inlineCodePattern.FindStringSubmatch(s)[1] + "</code>")
+	})
+	escaped = linkPattern.ReplaceAllStringFunc(escaped, func(s string) string {
+		m := linkPattern.FindStringSubmatch(s)
+		href := html.UnescapeString(m[2])
+		if !isSafeURL(href) {
+			return m[1]
+		}
+		return hold(`<a href="` + html.EscapeString(href) + `" rel="nofollow noopener noreferrer">` + m[1] + "</a>")
+	})
+	escaped = strongPattern.ReplaceAllString(escaped, "<strong>$1</strong>")
+	escaped = emPattern.ReplaceAllString(escaped, "<em>$1</em>")
+	escaped = strikePattern.ReplaceAllString(escaped, "<del>$1</del>")
+
+	// リンク内のコードも復元できるよう後ろから戻す
+	for i := len(stash) - 1; i >= 0; i-- {
+		escaped = strings.Replace(escaped, "\x00"+strconv.Itoa(i)+"\x00", stash[i], 1)
+	}
+
+	return escaped
+}
+
+// isSafeURL リンク先として許可するURLか確認
+func isSafeURL(href string) bool {
+	lower := strings.ToLower(strings.TrimSpace(href))
+	return strings.HasPrefix(lower, "http://") ||
+		strings.HasPrefix(lower, "https://") ||
+		strings.HasPrefix(lower, "mailto:")
+}
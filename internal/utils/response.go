@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// レスポンス形式
+const (
+	ResponseFormatEnvelope = "envelope" // {"data": ..., "meta": ..., "error": ...}
+	ResponseFormatLegacy   = "legacy"   // 従来の形式（エンドポイントごとに異なる）
+)
+
+// APIのバージョン
+const (
+	APIVersion1 = "1"
+	APIVersion2 = "2" // エンベロープ形式・エラーコード・カーソルによるページネーション
+)
+
+// コンテキストのキー
+const (
+	responseFormatKey = "response_format"
+	apiVersionKey     = "api_version"
+)
+
+// SetAPIVersion リクエストのAPIバージョンを設定
+func SetAPIVersion(ctx *gin.Context, version string) {
+	ctx.Set(apiVersionKey, version)
+}
+
+// IsAPIv2 v2のAPIとして処理するかどうか
+func IsAPIv2(ctx *gin.Context) bool {
+	return ctx.GetString(apiVersionKey) == APIVersion2
+}
+
+// SetResponseFormat リクエストのレスポンス形式を設定
+func SetResponseFormat(ctx *gin.Context, format string) {
+	ctx.Set(responseFormatKey, format)
+}
+
+// IsLegacyResponse 従来の形式でレスポンスを返すかどうか
+func IsLegacyResponse(ctx *gin.Context) bool {
+	return ctx.GetString(responseFormatKey) == ResponseFormatLegacy
+}
+
+// ErrorBody エンベロープ形式のエラー
+type ErrorBody struct {
+	Status  int    `json:"status"`
+	Code    string `json:"code,omitempty"` // v2のみ（例: not_found, forbidden）
+	Message string `json:"message"`
+}
+
+// errorCodes HTTPステータスに対応するエラーコード（v2）
+var errorCodes = map[int]string{
+	http.StatusBadRequest:            "bad_request",
+	http.StatusUnauthorized:          "unauthorized",
+	http.StatusForbidden:             "forbidden",
+	http.StatusNotFound:              "not_found",
+	http.StatusConflict:              "conflict",
+	http.StatusGone:                  "gone",
+	http.StatusRequestEntityTooLarge: "payload_too_large",
+	http.StatusTooManyRequests:       "rate_limited",
+	http.StatusInternalServerError:   "internal_error",
+	http.StatusServiceUnavailable:    "maintenance",
+	http.StatusGatewayTimeout:        "timeout",
+}
+
+// ErrorCode HTTPステータスに対応するエラーコードを取得
+func ErrorCode(status int) string {
+	if code, ok := errorCodes[status]; ok {
+		return code
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// Envelope エンベロープ形式のレスポンス
+type Envelope struct {
+	Data  interface{} `json:"data"`
+	Meta  gin.H       `json:"meta,omitempty"`
+	Error *ErrorBody  `json:"error"`
+}
+
+// Respond 成功レスポンスを返す
+// 従来の形式では {key: data}（keyが空の場合はdataをそのまま）を返す
+func Respond(ctx *gin.Context, status int, key string, data interface{}) {
+	RespondWithMeta(ctx, status, key, data, nil)
+}
+
+// RespondWithMeta ページネーションなどの付加情報付きの成功レスポンスを返す
+// 従来の形式では付加情報をdataと同じ階層に展開する
+func RespondWithMeta(ctx *gin.Context, status int, key string, data interface{}, meta gin.H) {
+	if !IsLegacyResponse(ctx) {
+		ctx.JSON(status, Envelope{Data: data, Meta: meta})
+		return
+	}
+
+	if key == "" && len(meta) == 0 {
+		ctx.JSON(status, data)
+		return
+	}
+
+	body := gin.H{}
+	for k, v := range meta {
+		body[k] = v
+	}
+	if key != "" {
+		body[key] = data
+	}
+	ctx.JSON(status, body)
+}
+
+// RespondError エラーレスポンスを返す
+// 従来の形式では {"error": message} を返す
+func RespondError(ctx *gin.Context, status int, message string) {
+	RespondErrorWithMeta(ctx, status, message, nil)
+}
+
+// RespondErrorWithMeta 付加情報付きのエラーレスポンスを返す
+func RespondErrorWithMeta(ctx *gin.Context, status int, message string, meta gin.H) {
+	if !IsLegacyResponse(ctx) {
+		body := &ErrorBody{Status: status, Message: message}
+		if IsAPIv2(ctx) {
+			body.Code = ErrorCode(status)
+		}
+		ctx.JSON(status, Envelope{Meta: meta, Error: body})
+		return
+	}
+
+	body := gin.H{"error": message}
+	for k, v := range meta {
+		body[k] = v
+	}
+	ctx.JSON(status, body)
+}
+
+// AbortWithError エラーレスポンスを返して以降のハンドラーを中断（ミドルウェア用）
+func AbortWithError(ctx *gin.Context, status int, message string) {
+	RespondError(ctx, status, message)
+	ctx.Abort()
+}
@@ -0,0 +1,25 @@
+-- クエリパターンに合わせた複合インデックス
+-- AutoMigrateはモデルのタグで宣言されたインデックスしか作成しないため、手動で適用する
+--   mysql -u processing_user -p processing_platform < migrations/20261015_indexes.sql
+
+-- ユーザーの作品一覧（WHERE user_id = ? ORDER BY created_at DESC）
+CREATE INDEX idx_works_user_id_created_at ON works (user_id, created_at);
+
+-- タグによる作品の絞り込み（主キーは work_id, tag_id の順のため逆順を追加）
+CREATE INDEX idx_work_tags_tag_id_work_id ON work_tags (tag_id, work_id);
+
+-- 作品ごとのいいね数の集計（主キーは user_id, work_id の順）
+CREATE INDEX idx_likes_work_id ON likes (work_id);
+
+-- 作品のコメント一覧（WHERE work_id = ? ORDER BY created_at DESC）
+CREATE INDEX idx_comments_work_id_created_at ON comments (work_id, created_at);
+
+-- ユーザーが参加しているプロジェクト一覧（主キーは project_id, user_id の順）
+CREATE INDEX idx_project_members_user_id ON project_members (user_id);
+
+-- ロールバック
+-- DROP INDEX idx_works_user_id_created_at ON works;
+-- DROP INDEX idx_work_tags_tag_id_work_id ON work_tags;
+-- DROP INDEX idx_likes_work_id ON likes;
+-- DROP INDEX idx_comments_work_id_created_at ON comments;
+-- DROP INDEX idx_project_members_user_id ON project_members;
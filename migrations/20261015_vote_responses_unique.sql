@@ -0,0 +1,28 @@
+-- 投票回答の重複をデータベースの一意制約で防ぐ
+-- 既存のデータに重複があるとAutoMigrateで一意インデックスを作成できないため、`make migrate-up` の前に手動で適用する
+--   mysql -u processing_user -p processing_platform < migrations/20261015_vote_responses_unique.sql
+
+-- 同じオプションへの重複した回答を削除（最初の回答を残す）
+DELETE r1 FROM vote_responses r1
+JOIN vote_responses r2
+  ON r1.vote_id = r2.vote_id AND r1.option_id = r2.option_id AND r1.user_id = r2.user_id AND r1.id > r2.id;
+
+-- 単一選択の投票で1人が複数の回答を持っている場合は、最後の回答を残す
+DELETE r1 FROM vote_responses r1
+JOIN vote_responses r2
+  ON r1.vote_id = r2.vote_id AND r1.user_id = r2.user_id AND r1.id < r2.id
+JOIN votes ON votes.id = r1.vote_id AND votes.multi_select = FALSE;
+
+-- 単一選択の投票のみユーザーIDを設定し、1人1回答に制限する（NULLは重複とみなされない）
+ALTER TABLE vote_responses ADD COLUMN single_user_id BIGINT UNSIGNED NULL;
+UPDATE vote_responses
+JOIN votes ON votes.id = vote_responses.vote_id AND votes.multi_select = FALSE
+SET vote_responses.single_user_id = vote_responses.user_id;
+
+CREATE UNIQUE INDEX idx_vote_responses_option_user ON vote_responses (vote_id, option_id, user_id);
+CREATE UNIQUE INDEX idx_vote_responses_single_user ON vote_responses (vote_id, single_user_id);
+
+-- ロールバック
+-- DROP INDEX idx_vote_responses_single_user ON vote_responses;
+-- DROP INDEX idx_vote_responses_option_user ON vote_responses;
+-- ALTER TABLE vote_responses DROP COLUMN single_user_id;
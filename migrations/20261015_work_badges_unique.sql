@@ -0,0 +1,13 @@
+-- 同じ投票のバッジの二重付与をデータベースの一意制約で防ぐ
+-- 既存のデータに重複があるとAutoMigrateで一意インデックスを作成できないため、`make migrate-up` の前に手動で適用する
+--   mysql -u processing_user -p processing_platform < migrations/20261015_work_badges_unique.sql
+
+-- 同じ作品・種類・投票の重複したバッジを削除（最初のバッジを残す）
+DELETE b1 FROM work_badges b1
+JOIN work_badges b2
+  ON b1.work_id = b2.work_id AND b1.type = b2.type AND b1.vote_id = b2.vote_id AND b1.id > b2.id;
+
+CREATE UNIQUE INDEX idx_work_badges_work_type_vote ON work_badges (work_id, type, vote_id);
+
+-- ロールバック
+-- DROP INDEX idx_work_badges_work_type_vote ON work_badges;